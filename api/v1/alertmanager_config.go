@@ -10,10 +10,42 @@ type AlertmanagerConfigGlobal struct {
 }
 
 type AlertmanagerConfigRoute struct {
-	Receiver       string                    `json:"receiver,omitempty"`
-	RepeatInterval string                    `json:"repeat_interval,omitempty"`
-	Match          map[string]string         `json:"match,omitempty"`
-	Routes         []AlertmanagerConfigRoute `json:"routes,omitempty"`
+	Receiver            string                    `json:"receiver,omitempty"`
+	GroupBy             []string                  `json:"group_by,omitempty"`
+	GroupWait           string                    `json:"group_wait,omitempty"`
+	GroupInterval       string                    `json:"group_interval,omitempty"`
+	RepeatInterval      string                    `json:"repeat_interval,omitempty"`
+	Match               map[string]string         `json:"match,omitempty"`
+	Matchers            []string                  `json:"matchers,omitempty"`
+	Continue            bool                      `json:"continue,omitempty"`
+	MuteTimeIntervals   []string                  `json:"mute_time_intervals,omitempty"`
+	ActiveTimeIntervals []string                  `json:"active_time_intervals,omitempty"`
+	Routes              []AlertmanagerConfigRoute `json:"routes,omitempty"`
+}
+
+type AlertmanagerConfigInhibitRule struct {
+	SourceMatchers []string `json:"source_matchers,omitempty"`
+	TargetMatchers []string `json:"target_matchers,omitempty"`
+	Equal          []string `json:"equal,omitempty"`
+}
+
+type AlertmanagerConfigTimeInterval struct {
+	Name          string                         `json:"name"`
+	TimeIntervals []AlertmanagerConfigTimePeriod `json:"time_intervals"`
+}
+
+type AlertmanagerConfigTimePeriod struct {
+	Times       []AlertmanagerConfigTimeRange `json:"times,omitempty"`
+	Weekdays    []string                      `json:"weekdays,omitempty"`
+	DaysOfMonth []string                      `json:"days_of_month,omitempty"`
+	Months      []string                      `json:"months,omitempty"`
+	Years       []string                      `json:"years,omitempty"`
+	Location    string                        `json:"location,omitempty"`
+}
+
+type AlertmanagerConfigTimeRange struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
 }
 
 type EmailSubject struct {
@@ -28,7 +60,18 @@ type EmailConfig struct {
 }
 
 type PagerDutyConfig struct {
-	ServiceKey string `json:"service_key"`
+	ServiceKey string `json:"service_key,omitempty"`
+	// Integration key of the Events API v2
+	RoutingKey string `json:"routing_key,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+}
+
+type SlackConfig struct {
+	SendResolved bool   `json:"send_resolved,omitempty"`
+	ApiUrl       string `json:"api_url"`
+	Channel      string `json:"channel,omitempty"`
+	Title        string `json:"title,omitempty"`
+	Text         string `json:"text,omitempty"`
 }
 
 type WebhookConfig struct {
@@ -40,10 +83,13 @@ type AlertmanagerConfigReceiver struct {
 	PagerDutyConfigs []PagerDutyConfig `json:"pagerduty_configs,omitempty"`
 	WebhookConfigs   []WebhookConfig   `json:"webhook_configs,omitempty"`
 	EmailConfig      []EmailConfig     `json:"email_configs,omitempty"`
+	SlackConfigs     []SlackConfig     `json:"slack_configs,omitempty"`
 }
 
 type AlertmanagerConfigRoot struct {
-	Global    *AlertmanagerConfigGlobal    `json:"global,omitempty"`
-	Route     *AlertmanagerConfigRoute     `json:"route,omitempty"`
-	Receivers []AlertmanagerConfigReceiver `json:"receivers,omitempty"`
+	Global        *AlertmanagerConfigGlobal        `json:"global,omitempty"`
+	Route         *AlertmanagerConfigRoute         `json:"route,omitempty"`
+	Receivers     []AlertmanagerConfigReceiver     `json:"receivers,omitempty"`
+	InhibitRules  []AlertmanagerConfigInhibitRule  `json:"inhibit_rules,omitempty"`
+	TimeIntervals []AlertmanagerConfigTimeInterval `json:"time_intervals,omitempty"`
 }
@@ -18,6 +18,34 @@ type GrafanaIndex struct {
 	Dashboards             []string           `json:"dashboards"`
 	DashboardLabelSelector *v13.LabelSelector `json:"dashboardLabelSelector,omitempty"`
 	GrafanaVersion         string             `json:"grafanaVersion,omitempty"`
+	// Adds a datasource of the long-term storage of the Observatorium instances of the index.
+	// Defaults to true.
+	ObservatoriumDatasource *bool `json:"observatoriumDatasource,omitempty"`
+	// Folders of the dashboards, the dashboards without a folder stay in the default folder
+	Folders []GrafanaFolderIndex `json:"folders,omitempty"`
+}
+
+// Folder of dashboards of the index, a dashboard can only be in one folder
+type GrafanaFolderIndex struct {
+	// Title of the folder in Grafana
+	Name string `json:"name"`
+	// Paths of the dashboards as listed in the dashboards of the index
+	Dashboards []string `json:"dashboards"`
+	// Complete permissions of the folder, Grafana keeps its default permissions without any
+	Permissions []GrafanaFolderPermission `json:"permissions,omitempty"`
+}
+
+type GrafanaFolderPermission struct {
+	// role or team
+	Type string `json:"type"`
+	// Viewer or Editor for roles, the id of the team for teams
+	Target string `json:"target"`
+	// view, edit or admin
+	Permission string `json:"permission"`
+}
+
+func (in *GrafanaIndex) ObservatoriumDatasourceEnabled() bool {
+	return in.ObservatoriumDatasource == nil || *in.ObservatoriumDatasource
 }
 
 type DexConfig struct {
@@ -39,6 +67,9 @@ type RedhatSsoConfig struct {
 	MetricsSecret string `json:"metricsSecret"`
 	LogsClient    string `json:"logsClientId"`
 	LogsSecret    string `json:"logsSecret"`
+	// Client with read access for the queries, the metrics client is used without one
+	QueryClient string `json:"queryClientId,omitempty"`
+	QuerySecret string `json:"querySecret,omitempty"`
 }
 
 func (in *RedhatSsoConfig) HasAuthServer() bool {
@@ -53,14 +84,42 @@ func (in *RedhatSsoConfig) HasLogs() bool {
 	return in.HasAuthServer() && in.LogsClient != "" && in.LogsSecret != ""
 }
 
+func (in *RedhatSsoConfig) HasQueries() bool {
+	return in.HasAuthServer() && (in.QueryClient != "" && in.QuerySecret != "" || in.MetricsClient != "" && in.MetricsSecret != "")
+}
+
+// Signing of the remote write requests of the sigv4 auth type. Without an access key the
+// default credentials chain is used, which includes the workload identity of Prometheus.
+type Sigv4Config struct {
+	Region  string `json:"region,omitempty"`
+	RoleArn string `json:"roleArn,omitempty"`
+	Profile string `json:"profile,omitempty"`
+	// Secret in the Prometheus namespace with the `accessKey` and `secretKey` keys
+	AccessKeySecret string `json:"accessKeySecret,omitempty"`
+}
+
+// Projected service account tokens of the serviceaccount auth type. The kubelet renews the token
+// before it expires, Prometheus and Promtail read the token file for every request.
+type ServiceAccountTokenConfig struct {
+	// Audience that Observatorium accepts, defaults to the audience of the API server
+	Audience string `json:"audience,omitempty"`
+	// Lifetime of the token, at least 600 seconds. Defaults to one hour.
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
 type ObservatoriumIndex struct {
-	Id              string                `json:"id"`
-	SecretName      string                `json:"secretName,omitempty"`
-	Gateway         string                `json:"gateway"`
-	Tenant          string                `json:"tenant"`
-	AuthType        ObservabilityAuthType `json:"authType"`
-	DexConfig       *DexConfig            `json:"dexConfig,omitempty"`
-	RedhatSsoConfig *RedhatSsoConfig      `json:"redhatSsoConfig,omitempty"`
+	Id               string                `json:"id"`
+	SecretName       string                `json:"secretName,omitempty"`
+	Gateway          string                `json:"gateway"`
+	Tenant           string                `json:"tenant"`
+	AuthType         ObservabilityAuthType `json:"authType"`
+	DexConfig        *DexConfig            `json:"dexConfig,omitempty"`
+	RedhatSsoConfig  *RedhatSsoConfig      `json:"redhatSsoConfig,omitempty"`
+	SecondaryGateway string                `json:"secondaryGateway,omitempty"`
+	TokenRefresher   *TokenRefresherSpec   `json:"tokenRefresher,omitempty"`
+	Sigv4Config      *Sigv4Config          `json:"sigv4Config,omitempty"`
+	// Used by the serviceaccount auth type
+	ServiceAccountTokenConfig *ServiceAccountTokenConfig `json:"serviceAccountTokenConfig,omitempty"`
 }
 
 func (in *ObservatoriumIndex) IsValid() bool {
@@ -84,24 +143,101 @@ type AlertmanagerIndex struct {
 	SmtpToEmailAddress            []string `json:"smtpToEmailAddress"`
 	SmtpFromEmailAddress          string   `json:"smtpFromEmailAddress"`
 	OverrideAlertmanagerPvcSize   string   `json:"overrideAlertmanagerPvcSize,omitempty"`
+	// Relabeling of the alerts of the index before Prometheus sends them, one of replace, keep or drop
+	AlertRelabelConfigs []v12.RelabelConfig `json:"alertRelabelConfigs,omitempty"`
+	// Routing tree of the alerts of the index, replaces the severity routes of its receivers
+	Routes []AlertmanagerIndexRoute `json:"routes,omitempty"`
+	// Inhibitions between the alerts of the index
+	InhibitRules []AlertmanagerIndexInhibitRule `json:"inhibitRules,omitempty"`
+	// Time intervals referenced by the routes of the index
+	MuteTimeIntervals []AlertmanagerTimeInterval `json:"muteTimeIntervals,omitempty"`
+}
+
+// Route of the routing tree of an index. Receivers are the ones of the index: pagerduty, deadmanssnitch,
+// smtp or default. Matchers use the Alertmanager syntax, e.g. severity=~"critical|warning".
+type AlertmanagerIndexRoute struct {
+	// Inherited from the parent route if empty
+	Receiver            string                   `json:"receiver,omitempty"`
+	Matchers            []string                 `json:"matchers,omitempty"`
+	GroupBy             []string                 `json:"groupBy,omitempty"`
+	GroupWait           string                   `json:"groupWait,omitempty"`
+	GroupInterval       string                   `json:"groupInterval,omitempty"`
+	RepeatInterval      string                   `json:"repeatInterval,omitempty"`
+	Continue            bool                     `json:"continue,omitempty"`
+	MuteTimeIntervals   []string                 `json:"muteTimeIntervals,omitempty"`
+	ActiveTimeIntervals []string                 `json:"activeTimeIntervals,omitempty"`
+	Routes              []AlertmanagerIndexRoute `json:"routes,omitempty"`
+}
+
+type AlertmanagerIndexInhibitRule struct {
+	SourceMatchers []string `json:"sourceMatchers"`
+	TargetMatchers []string `json:"targetMatchers"`
+	Equal          []string `json:"equal,omitempty"`
+}
+
+// Named time interval, it matches if one of its intervals matches
+type AlertmanagerTimeInterval struct {
+	Name          string                   `json:"name"`
+	TimeIntervals []AlertmanagerTimePeriod `json:"timeIntervals"`
+}
+
+// Fields that are empty match any time. Weekdays, days of the month, months and years are single
+// values or inclusive ranges, e.g. monday:friday, -3:-1, january:march or 2026:2027.
+type AlertmanagerTimePeriod struct {
+	Times       []AlertmanagerTimeRange `json:"times,omitempty"`
+	Weekdays    []string                `json:"weekdays,omitempty"`
+	DaysOfMonth []string                `json:"daysOfMonth,omitempty"`
+	Months      []string                `json:"months,omitempty"`
+	Years       []string                `json:"years,omitempty"`
+	// IANA time zone, defaults to UTC
+	Location string `json:"location,omitempty"`
+}
+
+// Start inclusive, end exclusive, e.g. 09:00 to 17:00
+type AlertmanagerTimeRange struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
 }
 
 type PrometheusIndex struct {
-	Rules                           []string           `json:"rules"`
-	PodMonitors                     []string           `json:"pod_monitors"`
-	Federation                      string             `json:"federation,omitempty"`
-	Observatorium                   string             `json:"observatorium,omitempty"`
-	RemoteWrite                     string             `json:"remoteWrite,omitempty"`
-	OverridePrometheusPvcSize       string             `json:"overridePrometheusPvcSize,omitempty"`
-	Labels                          *v13.LabelSelector `json:"labels,omitempty"`
-	PodMonitorLabelSelector         *v13.LabelSelector `json:"podMonitorLabelSelector,omitempty"`
-	PodMonitorNamespaceSelector     *v13.LabelSelector `json:"podMonitorNamespaceSelector,omitempty"`
-	ServiceMonitorLabelSelector     *v13.LabelSelector `json:"serviceMonitorLabelSelector,omitempty"`
-	ServiceMonitorNamespaceSelector *v13.LabelSelector `json:"serviceMonitorNamespaceSelector,omitempty"`
-	RuleLabelSelector               *v13.LabelSelector `json:"ruleLabelSelector,omitempty"`
-	RuleNamespaceSelector           *v13.LabelSelector `json:"ruleNamespaceSelector,omitempty"`
-	ProbeLabelSelector              *v13.LabelSelector `json:"probeSelector,omitempty"`
-	ProbeNamespaceSelector          *v13.LabelSelector `json:"probeNamespaceSelector,omitempty"`
+	Rules                           []string             `json:"rules"`
+	PodMonitors                     []string             `json:"pod_monitors"`
+	Federation                      string               `json:"federation,omitempty"`
+	FederationUpstreams             []FederationUpstream `json:"federationUpstreams,omitempty"`
+	StaticTargets                   []StaticTargetGroup  `json:"staticTargets,omitempty"`
+	ScrapeInterval                  string               `json:"scrapeInterval,omitempty"`
+	EvaluationInterval              string               `json:"evaluationInterval,omitempty"`
+	SampleLimit                     uint64               `json:"sampleLimit,omitempty"`
+	LabelLimit                      uint64               `json:"labelLimit,omitempty"`
+	LabelNameLengthLimit            uint64               `json:"labelNameLengthLimit,omitempty"`
+	TargetLimit                     uint64               `json:"targetLimit,omitempty"`
+	MetricFilter                    *MetricFilter        `json:"metricFilter,omitempty"`
+	Aggregations                    []Aggregation        `json:"aggregations,omitempty"`
+	RemoteRead                      []RemoteReadTarget   `json:"remoteRead,omitempty"`
+	Observatorium                   string               `json:"observatorium,omitempty"`
+	RemoteWrite                     string               `json:"remoteWrite,omitempty"`
+	OverridePrometheusPvcSize       string               `json:"overridePrometheusPvcSize,omitempty"`
+	Labels                          *v13.LabelSelector   `json:"labels,omitempty"`
+	PodMonitorLabelSelector         *v13.LabelSelector   `json:"podMonitorLabelSelector,omitempty"`
+	PodMonitorNamespaceSelector     *v13.LabelSelector   `json:"podMonitorNamespaceSelector,omitempty"`
+	ServiceMonitorLabelSelector     *v13.LabelSelector   `json:"serviceMonitorLabelSelector,omitempty"`
+	ServiceMonitorNamespaceSelector *v13.LabelSelector   `json:"serviceMonitorNamespaceSelector,omitempty"`
+	RuleLabelSelector               *v13.LabelSelector   `json:"ruleLabelSelector,omitempty"`
+	RuleNamespaceSelector           *v13.LabelSelector   `json:"ruleNamespaceSelector,omitempty"`
+	ProbeLabelSelector              *v13.LabelSelector   `json:"probeSelector,omitempty"`
+	ProbeNamespaceSelector          *v13.LabelSelector   `json:"probeNamespaceSelector,omitempty"`
+}
+
+// Pre-aggregates a metric with a recording rule. Only the aggregate is remote written, the series
+// of the metric stay in the cluster.
+type Aggregation struct {
+	Metric string `json:"metric"`
+	// Labels kept by the aggregation, all other labels are aggregated away
+	By []string `json:"by,omitempty"`
+	// One of sum, min, max, avg or count, defaults to sum
+	Operation string `json:"operation,omitempty"`
+	// Window of the rate for counters, e.g. 5m. Without a window the metric is aggregated as is.
+	Rate string `json:"rate,omitempty"`
 }
 
 type PromtailIndex struct {
@@ -109,6 +245,8 @@ type PromtailIndex struct {
 	NamespaceLabelSelector map[string]string  `json:"namespaceLabelSelector,omitempty"`
 	Observatorium          string             `json:"observatorium,omitempty"`
 	DaemonSetLabelSelector *v13.LabelSelector `json:"daemonSetLabelSelector,omitempty"`
+	// LogQL alerting and recording rules, evaluated by the Loki ruler of the Observatorium instance
+	Rules []v12.RuleGroup `json:"rules,omitempty"`
 }
 
 type RepositoryConfig struct {
@@ -117,6 +255,8 @@ type RepositoryConfig struct {
 	Alertmanager *AlertmanagerIndex   `json:"alertmanager,omitempty"`
 	Promtail     *PromtailIndex       `json:"promtail,omitempty"`
 	Observatoria []ObservatoriumIndex `json:"observatoria,omitempty"`
+	// Digests by image reference with tag, used instead of resolving the tags when images are pinned
+	ImageDigests map[string]string `json:"imageDigests,omitempty"`
 }
 
 type RepositoryIndex struct {
@@ -126,4 +266,10 @@ type RepositoryIndex struct {
 	Source      *v1.Secret        `json:"-"`
 	Id          string            `json:"id"`
 	Config      *RepositoryConfig `json:"config"`
+	// Changes of the index are applied after a delay between zero and the window, e.g. 6h.
+	// The delay of a cluster is derived from its cluster id.
+	RolloutWindow string `json:"rolloutWindow,omitempty"`
+	// Only this percentage of the clusters, selected by cluster id, applies changes of the index.
+	// Raising it to 100 or removing it rolls the change out to all clusters.
+	CanaryPercent *int `json:"canaryPercent,omitempty"`
 }
@@ -62,6 +62,8 @@ func TestIndex_HasAuthServer(t *testing.T) {
 				tt.fields.MetricsSecret,
 				tt.fields.LogsClient,
 				tt.fields.LogsSecret,
+				"",
+				"",
 			}
 			result := config.HasAuthServer()
 			Expect(result).To(Equal(tt.want))
@@ -126,6 +128,8 @@ func TestIndex_HasMetrics(t *testing.T) {
 				tt.fields.MetricsSecret,
 				tt.fields.LogsClient,
 				tt.fields.LogsSecret,
+				"",
+				"",
 			}
 			result := config.HasMetrics()
 			Expect(result).To(Equal(tt.want))
@@ -190,6 +194,8 @@ func TestIndex_HasLogs(t *testing.T) {
 				tt.fields.MetricsSecret,
 				tt.fields.LogsClient,
 				tt.fields.LogsSecret,
+				"",
+				"",
 			}
 			result := config.HasLogs()
 			Expect(result).To(Equal(tt.want))
@@ -250,9 +256,31 @@ func TestIndex_IsValid(t *testing.T) {
 				tt.fields.AuthType,
 				tt.fields.DexConfig,
 				tt.fields.RedhatSsoConfig,
+				"",
+				nil,
+				nil,
+				nil,
 			}
 			result := obsIndex.IsValid()
 			Expect(result).To(Equal(tt.want))
 		})
 	}
 }
+
+func TestIndex_HasQueries(t *testing.T) {
+	RegisterTestingT(t)
+
+	config := &RedhatSsoConfig{Url: configUrl, Realm: configRealm}
+	Expect(config.HasQueries()).To(BeFalse())
+
+	config.MetricsClient = "metrics-client"
+	config.MetricsSecret = "metrics-secret"
+	Expect(config.HasQueries()).To(BeTrue())
+
+	readOnly := &RedhatSsoConfig{Url: configUrl, Realm: configRealm, QueryClient: "query-client", QuerySecret: "query-secret"}
+	Expect(readOnly.HasQueries()).To(BeTrue())
+	Expect(readOnly.HasMetrics()).To(BeFalse())
+
+	readOnly.Realm = ""
+	Expect(readOnly.HasQueries()).To(BeFalse())
+}
@@ -0,0 +1,4 @@
+package v1
+
+// v1 is the storage version, the other versions of the Observability resource convert to and from it
+func (*Observability) Hub() {}
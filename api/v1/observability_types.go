@@ -31,6 +31,8 @@ type ObservabilityStageStatus string
 
 type ObservabilityAuthType string
 
+type ConfigurationErrorStage string
+
 const (
 	GrafanaInstallation      ObservabilityStageName = "Grafana"
 	GrafanaConfiguration     ObservabilityStageName = "GrafanaConfiguration"
@@ -54,11 +56,160 @@ const (
 const (
 	AuthTypeDex    ObservabilityAuthType = "dex"
 	AuthTypeRedhat ObservabilityAuthType = "redhat"
+	// AWS Signature Version 4, e.g. for Amazon Managed Service for Prometheus
+	AuthTypeSigv4 ObservabilityAuthType = "sigv4"
+	// Bound token of the service account of Prometheus or Promtail, projected into the pod
+	AuthTypeServiceAccount ObservabilityAuthType = "serviceaccount"
+)
+
+const (
+	ErrorStageFetch ConfigurationErrorStage = "fetch"
+	ErrorStageParse ConfigurationErrorStage = "parse"
+	ErrorStageApply ConfigurationErrorStage = "apply"
+	// Resources of the index that were rejected before applying them
+	ErrorStageValidate ConfigurationErrorStage = "validate"
+)
+
+// Annotation of the CR that requests a sync of the indexes without waiting for the resync period,
+// any value that differs from the previous request starts one, e.g. the current time
+const ResyncAnnotation = "observability-operator/resync"
+
+// +kubebuilder:validation:Enum=cluster;observatorium;both
+type RuleDestination string
+
+const (
+	RuleDestinationCluster       RuleDestination = "cluster"
+	RuleDestinationObservatorium RuleDestination = "observatorium"
+	RuleDestinationBoth          RuleDestination = "both"
+)
+
+// +kubebuilder:validation:Enum=openshift;kubernetes
+type ClusterType string
+
+const (
+	ClusterTypeOpenShift  ClusterType = "openshift"
+	ClusterTypeKubernetes ClusterType = "kubernetes"
+)
+
+type ClusterTopology string
+
+const (
+	ClusterTopologyStandalone ClusterTopology = "standalone"
+	// Control plane hosted outside of the cluster, e.g. by HyperShift. There is no
+	// openshift-monitoring to federate from.
+	ClusterTopologyHosted ClusterTopology = "hosted"
+)
+
+// +kubebuilder:validation:Enum=kubernetes;vault;externalsecrets
+type CredentialProviderType string
+
+const (
+	CredentialProviderKubernetes      CredentialProviderType = "kubernetes"
+	CredentialProviderVault           CredentialProviderType = "vault"
+	CredentialProviderExternalSecrets CredentialProviderType = "externalsecrets"
+)
+
+// Components of which the image can be overridden
+type ImageComponent string
+
+const (
+	ImagePrometheus       ImageComponent = "prometheus"
+	ImageBlackboxExporter ImageComponent = "blackbox"
+	ImageOAuthProxy       ImageComponent = "oauth-proxy"
+	ImageTokenRefresher   ImageComponent = "token-refresher"
+	ImagePromtail         ImageComponent = "promtail"
+	ImageGrafana          ImageComponent = "grafana"
+	ImageKubeRBACProxy    ImageComponent = "kube-rbac-proxy"
+	ImagePromLabelProxy   ImageComponent = "prom-label-proxy"
+	ImageKubeStateMetrics ImageComponent = "kube-state-metrics"
+	ImageNodeExporter     ImageComponent = "node-exporter"
+	ImageEventExporter    ImageComponent = "event-exporter"
+	// Defaults to the image of the operator
+	ImageAlertForwarder ImageComponent = "alert-forwarder"
+	// Thanos sidecar and compactor
+	ImageThanos ImageComponent = "thanos"
+	// Defaults to the image of the operator
+	ImageQueryLogExporter ImageComponent = "query-log-exporter"
+)
+
+// Components behind an oauth proxy
+type OAuthProxyComponent string
+
+const (
+	OAuthProxyPrometheus   OAuthProxyComponent = "prometheus"
+	OAuthProxyAlertmanager OAuthProxyComponent = "alertmanager"
+	OAuthProxyGrafana      OAuthProxyComponent = "grafana"
+)
+
+// Subject access review of an oauth proxy, users and bearer tokens are let in if it is allowed.
+// The fields are passed to the oauth proxy as they are.
+type OAuthProxyAuthorization struct {
+	Resource string `json:"resource"`
+	// Defaults to get
+	Verb  string `json:"verb,omitempty"`
+	Group string `json:"group,omitempty"`
+	// Cluster wide if not set
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the resource, all resources if not set
+	Name string `json:"name,omitempty"`
+}
+
+// Login of Grafana users
+type GrafanaAuthenticationType string
+
+const (
+	// Anonymous access behind the oauth proxy, the default
+	GrafanaAuthenticationProxy GrafanaAuthenticationType = "proxy"
+	// Grafana logs in with the OpenShift OAuth server itself
+	GrafanaAuthenticationOpenShift GrafanaAuthenticationType = "openshift"
+	// Grafana logs in with an external OpenID Connect provider
+	GrafanaAuthenticationOIDC GrafanaAuthenticationType = "oidc"
 )
 
+// Grafana org role of the users in a group or bound to a cluster role
+type GrafanaRoleMapping struct {
+	// Users bound to the cluster role by a cluster role binding, directly or by group.
+	// Only used with OpenShift OAuth.
+	ClusterRole string `json:"clusterRole,omitempty"`
+	// Group of OpenShift, or in the groups claim of the OIDC provider
+	Group string `json:"group,omitempty"`
+	// +kubebuilder:validation:Enum=Viewer;Editor;Admin
+	Role string `json:"role"`
+}
+
+type GrafanaOIDCSpec struct {
+	AuthUrl  string `json:"authUrl"`
+	TokenUrl string `json:"tokenUrl"`
+	// Userinfo endpoint of the provider
+	ApiUrl   string `json:"apiUrl"`
+	ClientId string `json:"clientId"`
+	// Secret in the Grafana namespace with the client secret in the clientSecret key
+	ClientSecret string `json:"clientSecret"`
+	// Defaults to openid profile email
+	Scopes string `json:"scopes,omitempty"`
+	// Claim with the groups of the user, defaults to groups
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+}
+
+type GrafanaAuthenticationSpec struct {
+	// +kubebuilder:validation:Enum=proxy;openshift;oidc
+	Type GrafanaAuthenticationType `json:"type,omitempty"`
+	// Users without a mapped role are viewers, the highest mapped role applies
+	RoleMappings []GrafanaRoleMapping `json:"roleMappings,omitempty"`
+	OIDC         *GrafanaOIDCSpec     `json:"oidc,omitempty"`
+}
+
 type Storage struct {
 	PrometheusStorageSpec   *prometheusv1.StorageSpec `json:"prometheus,omitempty"`
 	AlertManagerStorageSpec *prometheusv1.StorageSpec `json:"alertmanager,omitempty"`
+	PrometheusAutosizing    *StorageAutosizing        `json:"prometheusAutosizing,omitempty"`
+}
+
+// Derive the storage size from the ingestion rate and the configured retention
+type StorageAutosizing struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Apply the recommended size to the volume claims. Volumes are only ever grown.
+	Resize *bool `json:"resize,omitempty"`
 }
 
 type SelfContained struct {
@@ -91,6 +242,361 @@ type SelfContained struct {
 	GrafanaOperatorResourceRequirement    *v1.ResourceRequirements `json:"grafanaOperatorResourceRequirement,omitempty"`
 	GrafanaVersion                        string                   `json:"grafanaVersion,omitempty"`
 	DisableLogging                        *bool                    `json:"disableLogging,omitempty"`
+	DisableWALCompression                 *bool                    `json:"disableWALCompression,omitempty"`
+	AdditionalScrapeConfigs               *AdditionalScrapeConfigs `json:"additionalScrapeConfigs,omitempty"`
+	// Scrape and rule evaluation intervals, take precedence over the intervals of the index
+	ScrapeInterval     string `json:"scrapeInterval,omitempty"`
+	EvaluationInterval string `json:"evaluationInterval,omitempty"`
+	// Sent to in addition to the Observatorium instances of the indexes
+	RemoteWrite []RemoteWriteTarget `json:"remoteWrite,omitempty"`
+	// Queried in addition to the remote read endpoints of the indexes
+	RemoteRead []RemoteReadTarget `json:"remoteRead,omitempty"`
+	// Built-in rules and dashboards for API availability, pod restarts, volume usage and remote write health
+	DefaultRules *bool `json:"defaultRules,omitempty"`
+	// Skip the federation from openshift-monitoring or kube-prometheus
+	DisableFederation *bool `json:"disableFederation,omitempty"`
+	// Scrape the kubelets and cAdvisor through the API server when cluster metrics are not federated
+	ScrapeKubelet *bool `json:"scrapeKubelet,omitempty"`
+	// Managed kube-state-metrics and node-exporter on Kubernetes, scraped instead of federating from kube-prometheus
+	InfrastructureExporters *InfrastructureExporters `json:"infrastructureExporters,omitempty"`
+	// Managed exporter of the Kubernetes events as metrics, with a dashboard
+	EventExporter *EventExporter `json:"eventExporter,omitempty"`
+	// Routes of the alerts that no index routes, to PagerDuty and Slack by their severity
+	AlertManagerDefaultRouting *AlertmanagerDefaultRouting `json:"alertManagerDefaultRouting,omitempty"`
+	// Exemplars of the scraped metrics, e.g. the trace ids of requests, sent with remote write
+	PrometheusExemplars *PrometheusExemplars `json:"prometheusExemplars,omitempty"`
+	// Scrape and store native histograms, requires Prometheus 2.40 or later
+	PrometheusNativeHistograms *bool `json:"prometheusNativeHistograms,omitempty"`
+	// Feature flags passed to the managed Prometheus, e.g. memory-snapshot-on-shutdown
+	PrometheusEnableFeatures []string `json:"prometheusEnableFeatures,omitempty"`
+	// Query log of the managed Prometheus, with a sidecar that exports the slow queries
+	PrometheusQueryLog *PrometheusQueryLog `json:"prometheusQueryLog,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=emptyDir;persistent
+type QueryLogVolume string
+
+const (
+	QueryLogVolumeEmptyDir QueryLogVolume = "emptyDir"
+	// The data volume of Prometheus, a volume claim when storage is configured
+	QueryLogVolumePersistent QueryLogVolume = "persistent"
+)
+
+// Prometheus logs every query to a file, the exporter counts the queries slower than the threshold
+type PrometheusQueryLog struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Defaults to emptyDir
+	Volume QueryLogVolume `json:"volume,omitempty"`
+	// Defaults to 10s
+	SlowQueryThreshold string `json:"slowQueryThreshold,omitempty"`
+	// Distinct slow queries exported with their query, the least recent are dropped first. Defaults to 50.
+	MaxQueries int `json:"maxQueries,omitempty"`
+}
+
+// Exemplar storage of the managed Prometheus, kept in memory next to the head block
+type PrometheusExemplars struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Exemplars kept across all series, defaults to 100000
+	MaxSize *int64 `json:"maxSize,omitempty"`
+}
+
+// Receivers and routes of a complete Alertmanager config, so that installs without indexes don't need a config
+// secret. Paged severities go to PagerDuty, notified severities to Slack, critical alerts inhibit the warnings of
+// the same alert and namespace. The dead man's switch is not sent anywhere.
+type AlertmanagerDefaultRouting struct {
+	// Secret in the namespace of the CR with the integration key of a PagerDuty Events API v2 service
+	PagerDutySecretRef *v1.SecretKeySelector `json:"pagerDutySecretRef,omitempty"`
+	// Secret in the namespace of the CR with the url of a Slack incoming webhook
+	SlackSecretRef *v1.SecretKeySelector `json:"slackSecretRef,omitempty"`
+	// Defaults to the channel of the webhook
+	SlackChannel string `json:"slackChannel,omitempty"`
+	// Label with the severity of the alerts, defaults to severity
+	SeverityLabel string `json:"severityLabel,omitempty"`
+	// Defaults to critical
+	PageSeverities []string `json:"pageSeverities,omitempty"`
+	// Defaults to critical and warning
+	NotifySeverities []string `json:"notifySeverities,omitempty"`
+	// Defaults to alertname and namespace
+	GroupBy []string `json:"groupBy,omitempty"`
+	// Defaults to 30s
+	GroupWait string `json:"groupWait,omitempty"`
+	// Defaults to 5m
+	GroupInterval string `json:"groupInterval,omitempty"`
+	// Defaults to 4h for pages and 12h for notifications
+	RepeatInterval string `json:"repeatInterval,omitempty"`
+}
+
+type InfrastructureExporters struct {
+	Enabled                 *bool  `json:"enabled,omitempty"`
+	KubeStateMetricsVersion string `json:"kubeStateMetricsVersion,omitempty"`
+	NodeExporterVersion     string `json:"nodeExporterVersion,omitempty"`
+}
+
+type EventExporter struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Remote write endpoint that is not tied to Observatorium, e.g. Grafana Cloud, Mimir, VictoriaMetrics or Cortex
+type RemoteWriteTarget struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+	// Secret in the Prometheus namespace with a bearer token in the `token` key
+	AuthSecret string `json:"authSecret,omitempty"`
+	// Secret in the Prometheus namespace with the `username` and `password` keys
+	BasicAuthSecret string `json:"basicAuthSecret,omitempty"`
+	// Sent with every request, e.g. X-Scope-OrgID for multi-tenant receivers
+	Headers             map[string]string            `json:"headers,omitempty"`
+	RemoteTimeout       string                       `json:"remoteTimeout,omitempty"`
+	WriteRelabelConfigs []prometheusv1.RelabelConfig `json:"writeRelabelConfigs,omitempty"`
+	QueueConfig         *prometheusv1.QueueConfig    `json:"queueConfig,omitempty"`
+	InsecureSkipVerify  *bool                        `json:"insecureSkipVerify,omitempty"`
+}
+
+// Long-term storage queried by Prometheus, e.g. the read endpoint of Observatorium or Thanos
+type RemoteReadTarget struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+	// Secret in the Prometheus namespace with a bearer token in the `token` key
+	AuthSecret string `json:"authSecret,omitempty"`
+	// Secret in the Prometheus namespace with the `username` and `password` keys
+	BasicAuthSecret string            `json:"basicAuthSecret,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	// Only queries with these label matchers are sent to the endpoint
+	RequiredMatchers map[string]string `json:"requiredMatchers,omitempty"`
+	// Also query the endpoint for time ranges that the local storage holds
+	ReadRecent         bool   `json:"readRecent,omitempty"`
+	RemoteTimeout      string `json:"remoteTimeout,omitempty"`
+	InsecureSkipVerify *bool  `json:"insecureSkipVerify,omitempty"`
+}
+
+// Prometheus scrape_config entries appended to the generated additional scrape config
+type AdditionalScrapeConfigs struct {
+	// YAML list of scrape_config entries
+	Raw string `json:"raw,omitempty"`
+	// Key of a secret in the namespace of the CR with a YAML list of scrape_config entries. The secret
+	// isn't watched, its changes are applied by the next resync.
+	SecretRef *v1.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// Scheduling and resources of the Promtail DaemonSets. Log volume differs by node role, so
+// these are configured independently from the global tolerations and affinity.
+type PromtailSpec struct {
+	NodeSelector map[string]string        `json:"nodeSelector,omitempty"`
+	Tolerations  []v1.Toleration          `json:"tolerations,omitempty"`
+	Resources    *v1.ResourceRequirements `json:"resources,omitempty"`
+	// Defaults to true
+	ExcludeInfraNodes *bool `json:"excludeInfraNodes,omitempty"`
+	// Defaults to false
+	ExcludeMasterNodes *bool `json:"excludeMasterNodes,omitempty"`
+}
+
+// Prometheus to federate metrics from, in addition to openshift-monitoring
+type FederationUpstream struct {
+	Name string `json:"name"`
+	// Base URL of the upstream Prometheus, the /federate path is appended
+	Url string `json:"url"`
+	// Secret in the Prometheus namespace with a bearer token in the `token` key
+	AuthSecret string   `json:"authSecret,omitempty"`
+	Match      []string `json:"match"`
+	// Defaults to true
+	HonorLabels        *bool `json:"honorLabels,omitempty"`
+	InsecureSkipVerify *bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// Replicas of an Alertmanager cluster, e.g. the central alerting tier of an organization. All URLs
+// of an Alertmanager share the scheme and path.
+type ExternalAlertmanager struct {
+	Name string `json:"name"`
+	// Base URLs of the replicas, e.g. https://alertmanager-0.example.com/prefix
+	Urls []string `json:"urls"`
+	// Secret in the Prometheus namespace with a bearer token in the `token` key
+	AuthSecret string `json:"authSecret,omitempty"`
+	// Version of the Alertmanager API, defaults to v2
+	// +kubebuilder:validation:Enum=v1;v2
+	ApiVersion string `json:"apiVersion,omitempty"`
+	// Timeout of sending alerts, defaults to 10s
+	Timeout            string `json:"timeout,omitempty"`
+	InsecureSkipVerify *bool  `json:"insecureSkipVerify,omitempty"`
+}
+
+// Prometheus CR managed by someone else. Its rule, monitor and probe selectors must select the resources
+// of the indexes.
+type ExternalPrometheus struct {
+	Name string `json:"name"`
+	// Defaults to the namespace of the managed Prometheus
+	Namespace string `json:"namespace,omitempty"`
+	// Queried by the operator and the Grafana datasource, defaults to the prometheus-operated service
+	// of the Prometheus
+	Url string `json:"url,omitempty"`
+}
+
+// Monitoring endpoints of clusters with a hosted control plane
+type HostedControlPlaneSpec struct {
+	// Prometheus to federate from instead of openshift-monitoring, e.g. the monitoring stack of the
+	// hosted control plane namespace. The /federate path is appended. Federation is skipped if not set.
+	FederationUrl string `json:"federationUrl,omitempty"`
+	// Secret in the Prometheus namespace with a bearer token in the `token` key
+	AuthSecret         string `json:"authSecret,omitempty"`
+	InsecureSkipVerify *bool  `json:"insecureSkipVerify,omitempty"`
+	// Alternative metrics endpoints of the hosted cluster, scraped like the static targets
+	Endpoints []StaticTargetGroup `json:"endpoints,omitempty"`
+}
+
+// Targets outside of the cluster, scraped by a job of the same name. The targets are
+// written to a file_sd file, so that changes don't require a Prometheus reload.
+type StaticTargetGroup struct {
+	Name string `json:"name"`
+	// List of host:port
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	// Defaults to /metrics
+	MetricsPath string `json:"metricsPath,omitempty"`
+	// Defaults to http
+	// +kubebuilder:validation:Enum=http;https
+	Scheme string `json:"scheme,omitempty"`
+	// Scraped over https with the certificates of the mesh, requires serviceMesh.mtlsScrapes
+	ServiceMeshMTLS *bool `json:"serviceMeshMTLS,omitempty"`
+}
+
+// Limits per scrape, 0 means no limit. Scrapes exceeding a limit fail.
+type ScrapeLimits struct {
+	SampleLimit          uint64 `json:"sampleLimit,omitempty"`
+	LabelLimit           uint64 `json:"labelLimit,omitempty"`
+	LabelNameLengthLimit uint64 `json:"labelNameLengthLimit,omitempty"`
+	TargetLimit          uint64 `json:"targetLimit,omitempty"`
+}
+
+// Scrape budget of the service and pod monitors of a namespace, protecting the shared Prometheus from
+// noisy tenants. Every monitor of the namespace is limited to the budget of the whole namespace,
+// the usage of the namespace is compared to the budget and violations are reported.
+type NamespaceScrapeBudget struct {
+	Namespace   string `json:"namespace"`
+	TargetLimit uint64 `json:"targetLimit,omitempty"`
+	// Enforced as sample limit of the monitors, the samples of one scrape interval
+	SamplesPerSecond uint64 `json:"samplesPerSecond,omitempty"`
+	// Metric name patterns that are dropped when scraping the monitors of the namespace
+	Drop []string `json:"drop,omitempty"`
+}
+
+// Metric name patterns applied to the remote write targets. Patterns are regular expressions
+// that have to match the whole metric name.
+type MetricFilter struct {
+	// Only metrics matching one of the patterns are sent
+	Keep []string `json:"keep,omitempty"`
+	// Metrics matching one of the patterns are not sent
+	Drop []string `json:"drop,omitempty"`
+}
+
+// Noisy high resolution metrics that are only kept locally. The series of a class are dropped from
+// remote write, the aggregates recorded from them are remote written instead.
+type MetricClass struct {
+	Name string `json:"name"`
+	// Series matching one of the selectors belong to the class
+	Selectors []MetricClassSelector `json:"selectors"`
+	// Remote write the series of the class as well
+	RemoteWrite *bool `json:"remoteWrite,omitempty"`
+	// Recorded from the series of the class, the aggregates are remote written
+	Aggregations []Aggregation `json:"aggregations,omitempty"`
+}
+
+// Relabel rule with a single source label, the regex has to match the whole label value
+type MetricClassSelector struct {
+	// Defaults to __name__
+	SourceLabel string `json:"sourceLabel,omitempty"`
+	Regex       string `json:"regex"`
+}
+
+// Thanos next to Prometheus, requires the thanos feature gate. The sidecar uploads the blocks of
+// Prometheus to object storage.
+type ThanosSpec struct {
+	// Secret in the namespace of Prometheus with the Thanos object storage config
+	ObjectStorageConfig *v1.SecretKeySelector `json:"objectStorageConfig"`
+	Compactor           *ThanosCompactor      `json:"compactor,omitempty"`
+}
+
+// Downsamples and compacts the uploaded blocks, so that a long retention doesn't need the
+// resolution and the disk of the raw samples
+type ThanosCompactor struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Only deployed while the retention of Prometheus exceeds the threshold, defaults to 30d
+	RetentionThreshold string `json:"retentionThreshold,omitempty"`
+	// Retention of the raw samples in object storage, defaults to the retention of Prometheus
+	RetentionRaw string `json:"retentionRaw,omitempty"`
+	// Retention of the 5m resolution, defaults to 180d
+	Retention5m string `json:"retention5m,omitempty"`
+	// Retention of the 1h resolution, defaults to 1y
+	Retention1h string `json:"retention1h,omitempty"`
+	// Size of the volume of the working directory, defaults to 50Gi
+	StorageSize      string  `json:"storageSize,omitempty"`
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// Periodically records the metrics with the most series per namespace in the
+// observability-cardinality ConfigMap and alerts on sudden growth of the head series
+type CardinalityAnalysis struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Time between two reports, defaults to 1h
+	Interval string `json:"interval,omitempty"`
+	// Number of metrics reported per namespace, defaults to 10
+	TopK int `json:"topK,omitempty"`
+	// Alert when the head series grow by more than this percentage within an hour, defaults to 50
+	GrowthAlertPercent int `json:"growthAlertPercent,omitempty"`
+}
+
+// Periodically reads the latest probe sample back from the Observatorium instances that the indexes
+// remote write to. The operator exports the current time as a sample that Prometheus scrapes and
+// remote writes, the probe fails when the latest sample in Observatorium is older than the SLA.
+type RemoteWriteProbe struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Time between two verifications, defaults to 5m
+	Interval string `json:"interval,omitempty"`
+	// Maximum age of the latest sample in Observatorium, defaults to 5m
+	Sla string `json:"sla,omitempty"`
+	// Adds the RemoteWriteProbeFailed alert. Defaults to false.
+	Alert *bool `json:"alert,omitempty"`
+}
+
+// Periodically summarizes the active targets of the managed Prometheus by monitor
+type ScrapeTargetHealth struct {
+	// Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+	// Time between two summaries, defaults to 5m
+	Interval string `json:"interval,omitempty"`
+}
+
+// Objective of a ratio SLI, e.g. the share of successful requests. The queries contain the
+// placeholder $window for the range of their rates and must return a single series, e.g.
+// sum(rate(http_requests_total{code=~"5.."}[$window])).
+type ServiceLevelObjective struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name       string `json:"name"`
+	ErrorQuery string `json:"errorQuery"`
+	TotalQuery string `json:"totalQuery"`
+	// Percentage of good events, e.g. 99.9
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Target string `json:"target"`
+	// Period of the error budget, defaults to 30d. Prometheus needs to retain at least this period.
+	Window string `json:"window,omitempty"`
+	// Added to the alerts, e.g. the team to route them to
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Expose the components through networking.k8s.io/v1 Ingresses instead of routes.
+// Components without an endpoint are not exposed.
+type IngressSpec struct {
+	// The default IngressClass of the cluster is used if not set
+	IngressClassName string            `json:"ingressClassName,omitempty"`
+	Annotations      map[string]string `json:"annotations,omitempty"`
+	Prometheus       *IngressEndpoint  `json:"prometheus,omitempty"`
+	Alertmanager     *IngressEndpoint  `json:"alertmanager,omitempty"`
+	Grafana          *IngressEndpoint  `json:"grafana,omitempty"`
+}
+
+type IngressEndpoint struct {
+	Host string `json:"host"`
+	// Secret with the certificate for the host, TLS is not configured if not set
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
 }
 
 // ObservabilitySpec defines the desired state of Observability
@@ -102,12 +608,386 @@ type ObservabilitySpec struct {
 	Storage                 *Storage              `json:"storage,omitempty"`
 	Tolerations             []v1.Toleration       `json:"tolerations,omitempty"`
 	Affinity                *v1.Affinity          `json:"affinity,omitempty"`
+	Promtail                *PromtailSpec         `json:"promtail,omitempty"`
 	SelfContained           *SelfContained        `json:"selfContained,omitempty"`
 	DescopedMode            *DescopedMode         `json:"descopedMode,omitempty"`
 	Retention               string                `json:"retention,omitempty"`
+	RetentionSize           string                `json:"retentionSize,omitempty"`
 	AlertManagerDefaultName string                `json:"alertManagerDefaultName,omitempty"`
 	PrometheusDefaultName   string                `json:"prometheusDefaultName,omitempty"`
 	GrafanaDefaultName      string                `json:"grafanaDefaultName,omitempty"`
+	// When enabled, the configuration stage computes pending changes to managed
+	// resources and records them in a ConfigMap instead of applying them.
+	DryRun *bool `json:"dryRun,omitempty"`
+	// Apply the resource recommendations from the status to the requests of the managed components
+	AutoResize *bool `json:"autoResize,omitempty"`
+	// Use FIPS capable images, verify all certificates and restrict TLS to FIPS approved versions and ciphers
+	FIPSMode *bool `json:"fipsMode,omitempty"`
+	// Detected from the available APIs if not set. On Kubernetes clusters the OpenShift
+	// specific resources (routes, oauth proxies, serving certificates) are not created.
+	ClusterType ClusterType  `json:"clusterType,omitempty"`
+	Ingress     *IngressSpec `json:"ingress,omitempty"`
+	// Detected from the control plane topology of the OpenShift infrastructure if not set
+	// +kubebuilder:validation:Enum=standalone;hosted
+	ClusterTopology    ClusterTopology         `json:"clusterTopology,omitempty"`
+	HostedControlPlane *HostedControlPlaneSpec `json:"hostedControlPlane,omitempty"`
+	// Federated in addition to the upstreams from the indexes
+	FederationUpstreams []FederationUpstream `json:"federationUpstreams,omitempty"`
+	// Alertmanagers outside of the cluster that Prometheus sends the alerts to, in addition to the
+	// in-cluster Alertmanager unless that is disabled in the components
+	ExternalAlertmanagers []ExternalAlertmanager `json:"externalAlertmanagers,omitempty"`
+	// Muted routes of the in-cluster Alertmanager, e.g. maintenance windows. The dead man's switch is never muted.
+	AlertmanagerMuteTimeIntervals []AlertmanagerMuteTimeInterval `json:"alertmanagerMuteTimeIntervals,omitempty"`
+	// Forwards the alerts of the in-cluster Alertmanager to an external endpoint, e.g. an event bus or ticketing system
+	AlertForwarder *AlertForwarderSpec `json:"alertForwarder,omitempty"`
+	// Existing Prometheus that evaluates the rules and scrapes the monitors of the indexes. No
+	// Prometheus is created and the Prometheus Operator is not installed.
+	ExternalPrometheus *ExternalPrometheus `json:"externalPrometheus,omitempty"`
+	// Scraped in addition to the static targets from the indexes
+	StaticTargets []StaticTargetGroup `json:"staticTargets,omitempty"`
+	// Enforced for all service and pod monitors, lower limits of a monitor take precedence
+	ScrapeLimits        *ScrapeLimits        `json:"scrapeLimits,omitempty"`
+	CardinalityAnalysis *CardinalityAnalysis `json:"cardinalityAnalysis,omitempty"`
+	// Limits of the service and pod monitors by namespace
+	NamespaceScrapeBudgets []NamespaceScrapeBudget `json:"namespaceScrapeBudgets,omitempty"`
+	// Applied to all remote write targets in addition to the filter of the index
+	MetricFilter *MetricFilter `json:"metricFilter,omitempty"`
+	// Retention classes of metrics, series that are in no class are kept for the full retention
+	MetricClasses []MetricClass `json:"metricClasses,omitempty"`
+	// Object storage of the blocks of Prometheus and their downsampling
+	Thanos *ThanosSpec `json:"thanos,omitempty"`
+	// Verifies that the samples written by Prometheus arrive in Observatorium
+	RemoteWriteProbe *RemoteWriteProbe `json:"remoteWriteProbe,omitempty"`
+	// Summary of the down targets of the managed Prometheus in the status
+	ScrapeTargetHealth *ScrapeTargetHealth `json:"scrapeTargetHealth,omitempty"`
+	// Run the token refreshers of all indexes in one deployment per auth realm instead of
+	// a deployment per Observatorium instance and signal type
+	SharedTokenRefresher *bool `json:"sharedTokenRefresher,omitempty"`
+	// Takes precedence over the token refresher settings of the indexes
+	TokenRefresher *TokenRefresherSpec `json:"tokenRefresher,omitempty"`
+	// Cloud identity of the Prometheus service account
+	WorkloadIdentity *WorkloadIdentity `json:"workloadIdentity,omitempty"`
+	// Added to the external labels of Prometheus and the labels of all alerts, e.g. environment,
+	// region or tier, so that Alertmanager routes can distinguish clusters
+	ClusterLabels map[string]string `json:"clusterLabels,omitempty"`
+	// Where the rules of the indexes are evaluated: as PrometheusRules in the cluster (default),
+	// by the Observatorium instance that the index remote writes to, or both
+	RuleDestination RuleDestination `json:"ruleDestination,omitempty"`
+	// Rules and dashboards of the indexes to apply on this cluster
+	RuleFilters      *ResourceFilter `json:"ruleFilters,omitempty"`
+	DashboardFilters *ResourceFilter `json:"dashboardFilters,omitempty"`
+	// Keep the Prometheus volume claims when the CR is deleted, a recreated CR with the same
+	// storage settings binds them again. Defaults to true, false deletes the claims.
+	RetainStorageOnDelete *bool `json:"retainStorageOnDelete,omitempty"`
+	// Take over existing Prometheus, Alertmanager and Grafana CRs with the configured names instead
+	// of replacing their spec. Fields that the operator doesn't set are kept.
+	AdoptExistingResources *bool `json:"adoptExistingResources,omitempty"`
+	// Start a new Prometheus version next to the serving one and switch the service once it is
+	// ready, instead of updating the Prometheus statefulset in place
+	BlueGreenUpgrades *bool `json:"blueGreenUpgrades,omitempty"`
+	// Apply the configuration recorded in this revision instead of fetching the indexes, to roll
+	// back to a known good configuration. The available revisions are kept as secrets.
+	PinnedConfigRevision *int `json:"pinnedConfigRevision,omitempty"`
+	// Number of applied configuration revisions to keep, defaults to 5
+	ConfigRevisionHistoryLimit *int `json:"configRevisionHistoryLimit,omitempty"`
+	// Stop reconciling the CR, the managed resources are left as they are until it is resumed.
+	// Deleting a paused CR still removes them.
+	Paused *bool `json:"paused,omitempty"`
+	// Number of dashboards, rules and pod monitors of the indexes fetched and applied at a time,
+	// defaults to 4. Dry runs are always sequential, so that the report is stable.
+	ResourceSyncWorkers *int `json:"resourceSyncWorkers,omitempty"`
+	// Delays between the reconciles of the stages, once all stages succeeded and while a stage is in
+	// progress or failed. Unlike the resync period, which only applies to fetching the indexes.
+	Requeue *RequeuePolicy `json:"requeue,omitempty"`
+	// Timeout, rate limit and circuit breaker of the requests to the repositories of the indexes
+	Fetch *FetchPolicy `json:"fetch,omitempty"`
+	// Components the operator runs, all are enabled by default. The resources of a disabled
+	// component are removed, e.g. to only run the log pipeline or only the dashboards.
+	Components *Components `json:"components,omitempty"`
+	// Discover monitors, probes and rules only in these namespaces and grant Prometheus access to
+	// them with roles instead of cluster roles. Set when the operator runs with namespace scoped
+	// permissions, the namespace of Prometheus is always included.
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// Add the namespaces annotated with observability.redhat.com/scrape=true to the namespace selectors
+	// of Prometheus and the namespaces of Promtail, so that teams opt in without changing the indexes.
+	// Ignored with target namespaces, the namespaces can't be listed then.
+	NamespaceDiscovery *bool `json:"namespaceDiscovery,omitempty"`
+	// Where the credentials referenced by secret name are looked up: observatorium config and dex
+	// credential secrets, PagerDuty, Dead Man's Snitch and SMTP secrets. Defaults to Kubernetes secrets.
+	CredentialProvider *CredentialProviderSpec `json:"credentialProvider,omitempty"`
+	// Security contexts of the pods and containers created by the operator. The defaults comply with
+	// the restricted pod security profile, except for Promtail which reads the logs of the nodes.
+	SecurityContext *SecurityContextSpec `json:"securityContext,omitempty"`
+	// Images by component: prometheus, blackbox, oauth-proxy, token-refresher, promtail and grafana.
+	// Images without a tag get the default tag or the configured version.
+	ImageOverrides map[ImageComponent]string `json:"imageOverrides,omitempty"`
+	// Registry, optionally with a path, that replaces the registry of all default images, e.g. a
+	// local mirror in disconnected clusters
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+	// Images by node architecture and component, e.g. arm64 builds of images without a multi-arch manifest
+	ArchImageOverrides map[string]map[ImageComponent]string `json:"archImageOverrides,omitempty"`
+	// Node architecture for Prometheus, Alertmanager, Grafana and the token refreshers, e.g. arm64.
+	// Without it they are scheduled on the architectures supported by their images.
+	Architecture string `json:"architecture,omitempty"`
+	// Run the managed images by digest instead of by tag, optionally only once their signature is verified
+	ImagePinning *ImagePinningSpec `json:"imagePinning,omitempty"`
+	TenancyProxy *TenancyProxySpec `json:"tenancyProxy,omitempty"`
+	// Prometheus serves HTTPS itself instead of behind the oauth proxy, for meshes or gateways that authenticate
+	PrometheusWebTLS *PrometheusWebTLS `json:"prometheusWebTLS,omitempty"`
+	// Sidecar injection, excluded ports and mesh scrapes for Istio or OpenShift Service Mesh
+	ServiceMesh *ServiceMeshSpec `json:"serviceMesh,omitempty"`
+	// Serving certificates of Prometheus, Alertmanager and Grafana issued by cert-manager instead of the service CA
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
+	// Authorization of the oauth proxies by component, defaults to users who can get namespaces
+	OAuthProxyAuthorization map[OAuthProxyComponent]OAuthProxyAuthorization `json:"oauthProxyAuthorization,omitempty"`
+	// Login of Grafana users with OpenShift OAuth or OIDC and their org roles, instead of the oauth proxy
+	GrafanaAuthentication *GrafanaAuthenticationSpec `json:"grafanaAuthentication,omitempty"`
+	// Recorded error ratios, burn rate alerts and a dashboard are generated for every objective
+	ServiceLevelObjectives []ServiceLevelObjective `json:"serviceLevelObjectives,omitempty"`
+	// LogQL alerting and recording rules, evaluated by the Loki ruler of every Observatorium instance that receives logs
+	LogRules []prometheusv1.RuleGroup `json:"logRules,omitempty"`
+}
+
+// Every delay is randomly lengthened or shortened by the jitter, so that the reconciles of the
+// clusters of a fleet spread out instead of hitting shared services at the same time
+type RequeuePolicy struct {
+	// Delay after a reconcile in which all stages succeeded, defaults to 10s
+	Interval string `json:"interval,omitempty"`
+	// Percentage of the delay by which it varies, defaults to 10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	JitterPercent *int `json:"jitterPercent,omitempty"`
+	// Delays of the stages that are in progress or failed
+	Backoff *RequeueBackoff `json:"backoff,omitempty"`
+	// Backoff of single stages, unset fields fall back to the backoff of all stages
+	StageBackoffs []StageRequeueBackoff `json:"stageBackoffs,omitempty"`
+}
+
+// A stage in progress is retried after the initial delay. A stage that failed again waits the
+// multiplier times as long as before, up to the max delay.
+type RequeueBackoff struct {
+	// Defaults to 5s
+	InitialDelay string `json:"initialDelay,omitempty"`
+	// Defaults to 5m
+	MaxDelay string `json:"maxDelay,omitempty"`
+	// Defaults to 2, 1 retries failed stages at the initial delay
+	// +kubebuilder:validation:Minimum=1
+	Multiplier *int `json:"multiplier,omitempty"`
+}
+
+type StageRequeueBackoff struct {
+	Stage          ObservabilityStageName `json:"stage"`
+	RequeueBackoff `json:",inline"`
+}
+
+type Components struct {
+	// Prometheus and its scrape configuration, applies to the Prometheus Operator together with
+	// Alertmanager
+	Prometheus   *ComponentToggle `json:"prometheus,omitempty"`
+	Alertmanager *ComponentToggle `json:"alertmanager,omitempty"`
+	// Grafana, the Grafana Operator and the dashboards
+	Grafana *ComponentToggle `json:"grafana,omitempty"`
+	// Promtail daemon sets of the indexes
+	Promtail *ComponentToggle `json:"promtail,omitempty"`
+	// Blackbox exporter of the probes, also disabled by selfContained.disableBlackboxExporter
+	Blackbox *ComponentToggle `json:"blackbox,omitempty"`
+}
+
+type ComponentToggle struct {
+	// Defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// Requests are rate limited and failures counted by host. Once a host failed too often in a row, its
+// requests fail right away for the open duration, instead of holding up the sync with timeouts.
+type FetchPolicy struct {
+	// Timeout of a request, including the wait for the rate limit, defaults to 30s
+	Timeout string `json:"timeout,omitempty"`
+	// Requests per second to a host, defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond *int `json:"requestsPerSecond,omitempty"`
+	// Requests sent at once before the rate limit applies, defaults to the requests per second
+	// +kubebuilder:validation:Minimum=1
+	Burst *int `json:"burst,omitempty"`
+	// Consecutive failed requests to a host after which the circuit opens, defaults to 5. Timeouts,
+	// server errors and rate limit responses fail a request, 0 disables the circuit breaker.
+	// +kubebuilder:validation:Minimum=0
+	FailureThreshold *int `json:"failureThreshold,omitempty"`
+	// Time the circuit stays open, defaults to 1m. A Retry-After of the host takes precedence.
+	// The next request is sent once it passed, the circuit closes again when it succeeds.
+	OpenDuration string `json:"openDuration,omitempty"`
+}
+
+// Query API of Prometheus on a separate route, restricted to the series with the namespace label
+// of a namespace in which the user can get pod metrics. Only available on OpenShift.
+type TenancyProxySpec struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// Serving certificate of the web endpoint of Prometheus. Without the oauth proxy the endpoint is not
+// authenticated, requests are expected to be authenticated by the mesh or gateway in front of it.
+type PrometheusWebTLS struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Secret with the tls.crt and tls.key of the certificate, e.g. issued by cert-manager. Defaults to the
+	// certificate issued by the service CA on OpenShift, required on Kubernetes.
+	SecretName string `json:"secretName,omitempty"`
+	// Minimum TLS version, e.g. TLS13. Defaults to TLS12.
+	MinVersion string `json:"minVersion,omitempty"`
+}
+
+// The routes of the indexes are muted during the time periods of the interval and the events of its calendar
+type AlertmanagerMuteTimeInterval struct {
+	// Unique among the intervals of the CR
+	Name          string                   `json:"name"`
+	TimeIntervals []AlertmanagerTimePeriod `json:"timeIntervals,omitempty"`
+	Calendar      *AlertmanagerCalendar    `json:"calendar,omitempty"`
+	// Ids of the indexes whose routes are muted, all indexes if empty
+	Indexes []string `json:"indexes,omitempty"`
+}
+
+// The payloads of the Alertmanager webhooks are forwarded as they are, with the cluster id, the name and labels
+// of the CR, its cluster labels and the console URL added in the cluster key
+type AlertForwarderSpec struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Endpoint that receives the payloads
+	Url string `json:"url"`
+	// Secret in the namespace of the CR with a bearer token in the token key
+	AuthSecret string `json:"authSecret,omitempty"`
+	// Only alerts that match all matchers are forwarded, e.g. severity=~"critical|warning". Defaults to all alerts.
+	Matchers []string `json:"matchers,omitempty"`
+	// Timeout of the requests to the endpoint, defaults to 10s
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// iCalendar feed, e.g. of a maintenance or on-call calendar. The events of the next 30 days are
+// synced into time periods, recurring events are expanded for daily and weekly rules.
+type AlertmanagerCalendar struct {
+	// Url of the feed, ignored if the url secret is set
+	Url string `json:"url,omitempty"`
+	// Secret with the url of the feed, for feeds with a private url
+	UrlSecretRef *v1.SecretKeySelector `json:"urlSecretRef,omitempty"`
+	// Defaults to 1h
+	SyncInterval string `json:"syncInterval,omitempty"`
+	// Only events with a matching summary are synced, e.g. (?i)maintenance. Defaults to all events.
+	SummaryRegex string `json:"summaryRegex,omitempty"`
+}
+
+// A cert-manager Certificate is created for every serving certificate. The certificates are written
+// to the secrets of the service CA, so the components mount them unchanged.
+type CertManagerSpec struct {
+	IssuerRef CertManagerIssuerRef `json:"issuerRef"`
+	// Lifetime of the certificates, defaults to the one of the issuer
+	Duration string `json:"duration,omitempty"`
+	// Renewal before the expiry, defaults to a third of the lifetime
+	RenewBefore string `json:"renewBefore,omitempty"`
+}
+
+type CertManagerIssuerRef struct {
+	Name string `json:"name"`
+	// Issuer or ClusterIssuer, or the kind of an external issuer. Defaults to Issuer.
+	Kind string `json:"kind,omitempty"`
+	// Defaults to cert-manager.io, set for external issuers
+	Group string `json:"group,omitempty"`
+}
+
+// The sidecar of the mesh is injected into Prometheus, Alertmanager, Grafana and the token refreshers.
+// Promtail runs on the nodes and is left out of the mesh.
+type ServiceMeshSpec struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Prometheus scrapes the static targets that opt in with the mesh certificates of its sidecar, so
+	// that targets with strict mTLS can be scraped. Other scrapes bypass the sidecar.
+	MTLSScrapes *bool `json:"mtlsScrapes,omitempty"`
+	// Inbound ports that bypass the sidecar, e.g. metrics ports scraped from outside the mesh
+	ExcludeInboundPorts []int32 `json:"excludeInboundPorts,omitempty"`
+	// Outbound ports that bypass the sidecar, e.g. the port of the Kubernetes API
+	ExcludeOutboundPorts []int32 `json:"excludeOutboundPorts,omitempty"`
+	// The mesh authenticates the requests, the oauth proxies of Prometheus, Alertmanager and Grafana
+	// are removed. Grafana keeps its anonymous access.
+	Authentication *bool `json:"authentication,omitempty"`
+}
+
+// A resource of the indexes is applied if it matches the include selector, or there is none,
+// and doesn't match the exclude selector
+type ResourceFilter struct {
+	Include *ResourceSelector `json:"include,omitempty"`
+	Exclude *ResourceSelector `json:"exclude,omitempty"`
+}
+
+// Matches resources by name or by the labels from the index. Dashboards in JSON or jsonnet have no labels.
+type ResourceSelector struct {
+	// Regular expressions, matched against the whole name
+	Names  []string              `json:"names,omitempty"`
+	Labels *metav1.LabelSelector `json:"labels,omitempty"`
+}
+
+// Binds the Prometheus service account to an AWS IAM role, so that the sigv4 auth type needs no
+// static credentials. Prometheus only signs remote write requests for AWS.
+type WorkloadIdentity struct {
+	// IAM role for service accounts on EKS, used by the default credentials chain of the sigv4 auth type
+	AwsRoleArn string `json:"awsRoleArn,omitempty"`
+}
+
+// Image and size of the token refresher deployments
+type TokenRefresherSpec struct {
+	Image     string                   `json:"image,omitempty"`
+	Tag       string                   `json:"tag,omitempty"`
+	Replicas  *int32                   `json:"replicas,omitempty"`
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+type CredentialProviderSpec struct {
+	Type            CredentialProviderType             `json:"type,omitempty"`
+	Vault           *VaultCredentialProvider           `json:"vault,omitempty"`
+	ExternalSecrets *ExternalSecretsCredentialProvider `json:"externalSecrets,omitempty"`
+}
+
+// Credentials of a secret are read from the KV version 2 engine at <secretsPath>/data/<pathPrefix>/<namespace>/<name>.
+// The operator logs in with the Kubernetes auth method and its service account token.
+type VaultCredentialProvider struct {
+	Address string `json:"address"`
+	Role    string `json:"role"`
+	// Mount of the Kubernetes auth method, defaults to kubernetes
+	AuthPath string `json:"authPath,omitempty"`
+	// Mount of the KV secrets engine, defaults to secret
+	SecretsPath string `json:"secretsPath,omitempty"`
+	PathPrefix  string `json:"pathPrefix,omitempty"`
+	// Secret in the namespace of the CR with the CA certificate of Vault in the ca.crt key. Vault
+	// is verified with the trusted CA bundle of the cluster as well.
+	CASecret string `json:"caSecret,omitempty"`
+}
+
+// An ExternalSecret is created for every referenced secret, the External Secrets operator syncs the
+// key <pathPrefix>/<namespace>/<name> of the store into the secret
+type ExternalSecretsCredentialProvider struct {
+	SecretStoreName string `json:"secretStoreName"`
+	// SecretStore or ClusterSecretStore, defaults to SecretStore
+	SecretStoreKind string `json:"secretStoreKind,omitempty"`
+	PathPrefix      string `json:"pathPrefix,omitempty"`
+	// Defaults to 1h
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+}
+
+// Tags are resolved to digests with anonymous registry requests, unless the digest is supplied in an index
+type ImagePinningSpec struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Tags are resolved again after this interval, defaults to 1h
+	ResolveInterval string `json:"resolveInterval,omitempty"`
+	// PEM encoded ECDSA public keys. When set, a new digest is only rolled out once its cosign
+	// signature verifies with one of the keys, until then the previous digest keeps running.
+	CosignPublicKeys []string `json:"cosignPublicKeys,omitempty"`
+	// Pull secrets in the namespace of the CR with the logins of private registries, of type
+	// kubernetes.io/dockerconfigjson
+	PullSecrets []string `json:"pullSecrets,omitempty"`
+}
+
+// Overrides replace the defaults of the operator as a whole
+type SecurityContextSpec struct {
+	// Pods of Prometheus, Alertmanager, Grafana and the token refreshers
+	Pod *v1.PodSecurityContext `json:"pod,omitempty"`
+	// Containers of these pods, including the ones created by the Prometheus and Grafana operators
+	Container *v1.SecurityContext `json:"container,omitempty"`
+	// Container of Promtail, privileged by default
+	Promtail *v1.SecurityContext `json:"promtail,omitempty"`
 }
 
 type DescopedMode struct {
@@ -115,6 +995,86 @@ type DescopedMode struct {
 	PrometheusOperatorNamespace string `json:"prometheusOperatorNamespace,omitempty"`
 }
 
+// A permission of the operator service account that a stage needs
+type Permission struct {
+	Group    string `json:"group,omitempty"`
+	Resource string `json:"resource"`
+	Verb     string `json:"verb"`
+	// Empty for cluster wide permissions
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type MissingPermission struct {
+	Stage      ObservabilityStageName `json:"stage"`
+	Permission `json:",inline"`
+}
+
+// An error that occurred while processing the configuration of a single index
+type ConfigurationError struct {
+	// Id of the index or name of the configuration secret if the index could not be read
+	Index   string                  `json:"index,omitempty"`
+	Stage   ConfigurationErrorStage `json:"stage"`
+	Message string                  `json:"message"`
+}
+
+// Resource requests derived from the observed usage of a container
+type ResourceRecommendation struct {
+	Component string          `json:"component"`
+	Container string          `json:"container"`
+	Requests  v1.ResourceList `json:"requests,omitempty"`
+}
+
+// Gateway that an Observatorium instance with a secondary gateway currently uses
+type ActiveGateway struct {
+	Observatorium string `json:"observatorium"`
+	Gateway       string `json:"gateway"`
+	// Unix timestamp of the last switch
+	Since int64 `json:"since"`
+}
+
+// Readiness of a token refresher deployment
+type TokenRefresherStatus struct {
+	Name          string `json:"name"`
+	Ready         bool   `json:"ready"`
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"readyReplicas"`
+}
+
+// Resource created by the configuration sync, deleted once it is no longer part of the indexes
+type ManagedResource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Id of the index the resource was created from, empty if it is shared by several indexes
+	Index string `json:"index,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Candidate;Switched
+type PrometheusUpgradePhase string
+
+const (
+	// The candidate with the new version is starting next to the serving Prometheus
+	PrometheusUpgradeCandidate PrometheusUpgradePhase = "Candidate"
+	// The candidate serves while the Prometheus of the CR is upgraded in place
+	PrometheusUpgradeSwitched PrometheusUpgradePhase = "Switched"
+)
+
+// Blue/green upgrade of Prometheus in progress
+type PrometheusUpgradeStatus struct {
+	FromVersion string                 `json:"fromVersion"`
+	ToVersion   string                 `json:"toVersion"`
+	Phase       PrometheusUpgradePhase `json:"phase"`
+}
+
+// Revision of an index that is applied, and of a change that is held back by its rollout settings
+type IndexRollout struct {
+	Index           string `json:"index"`
+	AppliedRevision string `json:"appliedRevision"`
+	PendingRevision string `json:"pendingRevision,omitempty"`
+	// Unix timestamp the pending revision was first seen
+	PendingSince int64 `json:"pendingSince,omitempty"`
+}
+
 // ObservabilityStatus defines the observed state of Observability
 type ObservabilityStatus struct {
 	Stage        ObservabilityStageName   `json:"stage"`
@@ -124,10 +1084,148 @@ type ObservabilityStatus struct {
 	ClusterID    string                   `json:"clusterId,omitempty"`
 	LastSynced   int64                    `json:"lastSynced,omitempty"`
 	Migrated     bool                     `json:"migrated,omitempty"`
+	// Cluster type from the spec or detected on the first reconcile
+	ClusterType ClusterType `json:"clusterType,omitempty"`
+	// Cluster topology from the spec or detected on the first reconcile
+	ClusterTopology ClusterTopology `json:"clusterTopology,omitempty"`
+	// Generation of the CR that was last reconciled through all stages
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Prometheus storage size needed for the current ingestion rate and retention
+	PrometheusStorageRecommendation string `json:"prometheusStorageRecommendation,omitempty"`
+	// CPU and memory requests derived from the observed usage of the managed components
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+	// Only set for Observatorium instances with a secondary gateway
+	ActiveGateways []ActiveGateway `json:"activeGateways,omitempty"`
+	// Token refresher deployments in the Prometheus namespace
+	TokenRefreshers []TokenRefresherStatus `json:"tokenRefreshers,omitempty"`
+	// Resources created by the last configuration sync
+	ManagedResources []ManagedResource `json:"managedResources,omitempty"`
+	// Only set while a blue/green upgrade of Prometheus is in progress
+	PrometheusUpgrade *PrometheusUpgradeStatus `json:"prometheusUpgrade,omitempty"`
+	// Rollout state of the indexes
+	IndexRollouts []IndexRollout `json:"indexRollouts,omitempty"`
+	// Configuration revision applied by the last sync
+	ConfigRevision int `json:"configRevision,omitempty"`
+	// Set while the configuration is pinned to the revision
+	ConfigRevisionPinned bool `json:"configRevisionPinned,omitempty"`
+	// Permissions that the operator lacks for the stages of the features in use. The stage
+	// that needs them is not reconciled until they are granted.
+	MissingPermissions []MissingPermission `json:"missingPermissions,omitempty"`
+	// Errors of the last configuration sync. A sync that reports errors here but
+	// finishes successfully has only partially applied the configuration.
+	ConfigurationErrors []ConfigurationError `json:"configurationErrors,omitempty"`
+	// Digests of the managed images when they are pinned
+	ResolvedImages []ResolvedImage `json:"resolvedImages,omitempty"`
+	// Managed certificates that expire within 30 days
+	ExpiringCertificates []CertificateExpiry `json:"expiringCertificates,omitempty"`
+	// ObservabilityTenants of all namespaces, aggregated before every sync
+	Tenants []TenantStatus `json:"tenants,omitempty"`
+	// Namespaces that opted in to be scraped with the discovery annotation
+	DiscoveredNamespaces []string `json:"discoveredNamespaces,omitempty"`
+	// Namespaces whose observed scrape usage exceeds their budget
+	ScrapeBudgetViolations []ScrapeBudgetViolation `json:"scrapeBudgetViolations,omitempty"`
+	// Last verification of the remote write path, by Observatorium instance
+	RemoteWriteProbes []RemoteWriteProbeStatus `json:"remoteWriteProbes,omitempty"`
+	// Last sync of the calendars of the mute time intervals
+	AlertmanagerCalendars []AlertmanagerCalendarStatus `json:"alertmanagerCalendars,omitempty"`
+	// Health of the active targets of the managed Prometheus
+	ScrapeTargets *ScrapeTargetsStatus `json:"scrapeTargets,omitempty"`
+	// Rules last pushed to the Rules API, by Observatorium instance
+	ObservatoriumRules []PushedRules `json:"observatoriumRules,omitempty"`
+	// Log rules last synced to the Loki ruler, by Observatorium instance
+	LogRules []PushedRules `json:"logRules,omitempty"`
+	// Set while the reconciliation is paused in the spec
+	Paused bool `json:"paused,omitempty"`
+	// Value of the resync annotation that the last sync was started for
+	ResyncRequest string `json:"resyncRequest,omitempty"`
+}
+
+type ScrapeTargetsStatus struct {
+	Up   int `json:"up"`
+	Down int `json:"down"`
+	// Time of the summary
+	LastChecked int64 `json:"lastChecked"`
+	// Monitors with down targets, the ones with the most down targets first
+	Unhealthy []UnhealthyScrapeTargets `json:"unhealthy,omitempty"`
+}
+
+type UnhealthyScrapeTargets struct {
+	// Kind and name of the service or pod monitor, or the job of other scrape configs
+	Monitor   string `json:"monitor"`
+	Namespace string `json:"namespace,omitempty"`
+	Down      int    `json:"down"`
+	Total     int    `json:"total"`
+	// Error of one of the down targets
+	LastError string `json:"lastError,omitempty"`
+}
+
+type PushedRules struct {
+	Observatorium string `json:"observatorium"`
+	Tenant        string `json:"tenant"`
+	// Hash of the pushed rules, the rules are only pushed again when it changes
+	Hash string `json:"hash"`
+}
+
+type AlertmanagerCalendarStatus struct {
+	// Name of the mute time interval
+	Name string `json:"name"`
+	// Time of the last successful sync
+	LastSynced int64 `json:"lastSynced,omitempty"`
+	// Synced events of the next 30 days
+	Events  int    `json:"events"`
+	Message string `json:"message,omitempty"`
+}
+
+type RemoteWriteProbeStatus struct {
+	Observatorium string `json:"observatorium"`
+	// True if the latest sample arrived within the SLA
+	Success bool `json:"success"`
+	// Time of the latest probe sample in Observatorium, 0 if none was found
+	LastSample int64 `json:"lastSample,omitempty"`
+	// Time of the verification
+	LastChecked int64  `json:"lastChecked"`
+	Message     string `json:"message,omitempty"`
+}
+
+type ScrapeBudgetViolation struct {
+	Namespace string `json:"namespace"`
+	// Either targets or samplesPerSecond
+	Limit    string `json:"limit"`
+	Budget   uint64 `json:"budget"`
+	Observed uint64 `json:"observed"`
+}
+
+// Registration of an ObservabilityTenant with its namespaces resolved
+type TenantStatus struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Namespace of the tenant, its namespaces and the namespaces matching its selector
+	Namespaces           []string `json:"namespaces,omitempty"`
+	RemoteWriteAllowlist []string `json:"remoteWriteAllowlist,omitempty"`
+	Dashboards           []string `json:"dashboards,omitempty"`
+}
+
+// Expiry of the earliest expiring certificate of a secret, route or CA bundle
+type CertificateExpiry struct {
+	// One of serving-cert, route, ingress or proxy-ca
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Expires   int64  `json:"expires"`
+}
+
+// Digest of an image reference with tag
+type ResolvedImage struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest"`
+	// Set when the cosign signature of the digest was verified
+	Verified   bool  `json:"verified,omitempty"`
+	ResolvedAt int64 `json:"resolvedAt,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 
 // Observability is the Schema for the observabilities API
 type Observability struct {
@@ -172,9 +1270,16 @@ func (in *Observability) SmtpDisabled() bool {
 }
 
 func (in *Observability) BlackboxExporterDisabled() bool {
+	if in.Spec.Components != nil && !in.Spec.Components.Blackbox.IsEnabled() {
+		return true
+	}
 	return in.Spec.SelfContained != nil && in.Spec.SelfContained.DisableBlackboxExporter != nil && *in.Spec.SelfContained.DisableBlackboxExporter
 }
 
+func (in *Observability) WALCompressionDisabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.DisableWALCompression != nil && *in.Spec.SelfContained.DisableWALCompression
+}
+
 func (in *Observability) SelfSignedCerts() bool {
 	return in.Spec.SelfContained != nil && in.Spec.SelfContained.SelfSignedCerts != nil && *in.Spec.SelfContained.SelfSignedCerts
 }
@@ -203,6 +1308,290 @@ func (in *Observability) DescopedModeEnabled() bool {
 	return false
 }
 
+func (in *ComponentToggle) IsEnabled() bool {
+	return in == nil || in.Enabled == nil || *in.Enabled
+}
+
+// An external Prometheus replaces the managed one
+func (in *Observability) PrometheusEnabled() bool {
+	if in.ExternalPrometheusEnabled() {
+		return false
+	}
+	return in.Spec.Components == nil || in.Spec.Components.Prometheus.IsEnabled()
+}
+
+func (in *Observability) ExternalPrometheusEnabled() bool {
+	return in.Spec.ExternalPrometheus != nil && in.Spec.ExternalPrometheus.Name != ""
+}
+
+func (in *Observability) AlertmanagerEnabled() bool {
+	return in.Spec.Components == nil || in.Spec.Components.Alertmanager.IsEnabled()
+}
+
+// Descoped mode runs without Grafana
+func (in *Observability) GrafanaEnabled() bool {
+	if in.DescopedModeEnabled() {
+		return false
+	}
+	return in.Spec.Components == nil || in.Spec.Components.Grafana.IsEnabled()
+}
+
+func (in *Observability) PromtailEnabled() bool {
+	return in.Spec.Components == nil || in.Spec.Components.Promtail.IsEnabled()
+}
+
+func (in *Observability) DryRunEnabled() bool {
+	return in.Spec.DryRun != nil && *in.Spec.DryRun
+}
+
+func (in *Observability) PromtailInfraNodesExcluded() bool {
+	return in.Spec.Promtail == nil || in.Spec.Promtail.ExcludeInfraNodes == nil || *in.Spec.Promtail.ExcludeInfraNodes
+}
+
+func (in *Observability) PromtailMasterNodesExcluded() bool {
+	return in.Spec.Promtail != nil && in.Spec.Promtail.ExcludeMasterNodes != nil && *in.Spec.Promtail.ExcludeMasterNodes
+}
+
+func (in *Observability) IsKubernetesCluster() bool {
+	if in.Spec.ClusterType != "" {
+		return in.Spec.ClusterType == ClusterTypeKubernetes
+	}
+	return in.Status.ClusterType == ClusterTypeKubernetes
+}
+
+func (in *Observability) IsHostedControlPlane() bool {
+	if in.Spec.ClusterTopology != "" {
+		return in.Spec.ClusterTopology == ClusterTopologyHosted
+	}
+	return in.Status.ClusterTopology == ClusterTopologyHosted
+}
+
+func (in *FederationUpstream) HonorLabelsEnabled() bool {
+	return in.HonorLabels == nil || *in.HonorLabels
+}
+
+func (in *FederationUpstream) InsecureSkipVerifyEnabled() bool {
+	return in.InsecureSkipVerify != nil && *in.InsecureSkipVerify
+}
+
+func (in *ExternalAlertmanager) InsecureSkipVerifyEnabled() bool {
+	return in.InsecureSkipVerify != nil && *in.InsecureSkipVerify
+}
+
+func (in *ThanosCompactor) IsEnabled() bool {
+	return in.Enabled != nil && *in.Enabled
+}
+
+func (in *MetricClass) RemoteWriteEnabled() bool {
+	return in.RemoteWrite != nil && *in.RemoteWrite
+}
+
+func (in *RemoteWriteTarget) InsecureSkipVerifyEnabled() bool {
+	return in.InsecureSkipVerify != nil && *in.InsecureSkipVerify
+}
+
+func (in *RemoteReadTarget) InsecureSkipVerifyEnabled() bool {
+	return in.InsecureSkipVerify != nil && *in.InsecureSkipVerify
+}
+
+func (in *Observability) IngressEnabled() bool {
+	return in.Spec.Ingress != nil
+}
+
+func (in *Observability) CardinalityAnalysisEnabled() bool {
+	return in.Spec.CardinalityAnalysis != nil && in.Spec.CardinalityAnalysis.Enabled != nil && *in.Spec.CardinalityAnalysis.Enabled
+}
+
+func (in *Observability) ExemplarsEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.PrometheusExemplars != nil &&
+		in.Spec.SelfContained.PrometheusExemplars.Enabled != nil && *in.Spec.SelfContained.PrometheusExemplars.Enabled
+}
+
+func (in *Observability) NativeHistogramsEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.PrometheusNativeHistograms != nil &&
+		*in.Spec.SelfContained.PrometheusNativeHistograms
+}
+
+func (in *Observability) QueryLogEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.PrometheusQueryLog != nil &&
+		in.Spec.SelfContained.PrometheusQueryLog.Enabled != nil && *in.Spec.SelfContained.PrometheusQueryLog.Enabled
+}
+
+func (in *Observability) RemoteWriteProbeEnabled() bool {
+	return in.Spec.RemoteWriteProbe != nil && in.Spec.RemoteWriteProbe.Enabled != nil && *in.Spec.RemoteWriteProbe.Enabled
+}
+
+func (in *Observability) RemoteWriteProbeAlertEnabled() bool {
+	return in.RemoteWriteProbeEnabled() && in.Spec.RemoteWriteProbe.Alert != nil && *in.Spec.RemoteWriteProbe.Alert
+}
+
+// Only the managed Prometheus is queried
+func (in *Observability) ScrapeTargetHealthEnabled() bool {
+	if !in.PrometheusEnabled() {
+		return false
+	}
+	return in.Spec.ScrapeTargetHealth == nil || in.Spec.ScrapeTargetHealth.Enabled == nil || *in.Spec.ScrapeTargetHealth.Enabled
+}
+
+func (in *Observability) SharedTokenRefresherEnabled() bool {
+	return in.Spec.SharedTokenRefresher != nil && *in.Spec.SharedTokenRefresher
+}
+
+// Rules are only pushed to Observatorium if it is enabled, otherwise they stay in the cluster
+func (in *Observability) ObservatoriumRulesEnabled() bool {
+	if in.ObservatoriumDisabled() {
+		return false
+	}
+	return in.Spec.RuleDestination == RuleDestinationObservatorium || in.Spec.RuleDestination == RuleDestinationBoth
+}
+
+func (in *Observability) ClusterRulesEnabled() bool {
+	return in.Spec.RuleDestination != RuleDestinationObservatorium || in.ObservatoriumDisabled()
+}
+
+func (in *Observability) RetainStorageOnDeleteEnabled() bool {
+	return in.Spec.RetainStorageOnDelete == nil || *in.Spec.RetainStorageOnDelete
+}
+
+func (in *Observability) AdoptExistingResourcesEnabled() bool {
+	return in.Spec.AdoptExistingResources != nil && *in.Spec.AdoptExistingResources
+}
+
+func (in *Observability) BlueGreenUpgradesEnabled() bool {
+	return in.Spec.BlueGreenUpgrades != nil && *in.Spec.BlueGreenUpgrades
+}
+
+func (in *Observability) ReconciliationPaused() bool {
+	return in.Spec.Paused != nil && *in.Spec.Paused
+}
+
+// A resync is requested when the annotation changes to a value the last sync wasn't started for
+func (in *Observability) ResyncRequested() bool {
+	request := in.Annotations[ResyncAnnotation]
+	return request != "" && request != in.Status.ResyncRequest
+}
+
+func (in *Observability) ConfigRevisionPinned() bool {
+	return in.Spec.PinnedConfigRevision != nil
+}
+
+func (in *Observability) GetConfigRevisionHistoryLimit() int {
+	if in.Spec.ConfigRevisionHistoryLimit != nil && *in.Spec.ConfigRevisionHistoryLimit > 0 {
+		return *in.Spec.ConfigRevisionHistoryLimit
+	}
+	return 5
+}
+
+func (in *Observability) NamespaceScoped() bool {
+	return len(in.Spec.TargetNamespaces) > 0
+}
+
+func (in *Observability) NamespaceDiscoveryEnabled() bool {
+	return in.Spec.NamespaceDiscovery != nil && *in.Spec.NamespaceDiscovery && !in.NamespaceScoped()
+}
+
+func (in *Observability) GetCredentialProviderType() CredentialProviderType {
+	if in.Spec.CredentialProvider == nil || in.Spec.CredentialProvider.Type == "" {
+		return CredentialProviderKubernetes
+	}
+	return in.Spec.CredentialProvider.Type
+}
+
+func (in *Observability) ImagePinningEnabled() bool {
+	return in.Spec.ImagePinning != nil && in.Spec.ImagePinning.Enabled != nil && *in.Spec.ImagePinning.Enabled
+}
+
+func (in *Observability) ImageVerificationEnabled() bool {
+	return in.ImagePinningEnabled() && len(in.Spec.ImagePinning.CosignPublicKeys) > 0
+}
+
+func (in *Observability) AlertForwarderEnabled() bool {
+	return in.Spec.AlertForwarder != nil && in.Spec.AlertForwarder.Enabled != nil && *in.Spec.AlertForwarder.Enabled && in.AlertmanagerEnabled()
+}
+
+func (in *Observability) TenancyProxyEnabled() bool {
+	return in.Spec.TenancyProxy != nil && in.Spec.TenancyProxy.Enabled != nil && *in.Spec.TenancyProxy.Enabled && !in.IsKubernetesCluster()
+}
+
+func (in *Observability) PrometheusWebTLSEnabled() bool {
+	return in.Spec.PrometheusWebTLS != nil && in.Spec.PrometheusWebTLS.Enabled != nil && *in.Spec.PrometheusWebTLS.Enabled
+}
+
+func (in *Observability) ServiceMeshEnabled() bool {
+	return in.Spec.ServiceMesh != nil && in.Spec.ServiceMesh.Enabled != nil && *in.Spec.ServiceMesh.Enabled
+}
+
+func (in *Observability) ServiceMeshMTLSScrapesEnabled() bool {
+	return in.ServiceMeshEnabled() && in.Spec.ServiceMesh.MTLSScrapes != nil && *in.Spec.ServiceMesh.MTLSScrapes
+}
+
+func (in *Observability) ServiceMeshAuthenticationEnabled() bool {
+	return in.ServiceMeshEnabled() && in.Spec.ServiceMesh.Authentication != nil && *in.Spec.ServiceMesh.Authentication
+}
+
+// The oauth proxies only run on OpenShift and are not needed when the mesh authenticates
+func (in *Observability) OAuthProxyEnabled() bool {
+	return !in.IsKubernetesCluster() && !in.ServiceMeshAuthenticationEnabled()
+}
+
+func (in *Observability) CertManagerEnabled() bool {
+	return in.Spec.CertManager != nil && in.Spec.CertManager.IssuerRef.Name != ""
+}
+
+func (in *Observability) DefaultRulesEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.DefaultRules != nil && *in.Spec.SelfContained.DefaultRules
+}
+
+func (in *Observability) FederationDisabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.DisableFederation != nil && *in.Spec.SelfContained.DisableFederation
+}
+
+// Only without the federation of the cluster metrics, which include those of the kubelets. Nodes are
+// cluster scoped, Prometheus can't discover them with namespaced roles.
+func (in *Observability) KubeletScrapingEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.ScrapeKubelet != nil && *in.Spec.SelfContained.ScrapeKubelet &&
+		(in.FederationDisabled() || in.InfrastructureExportersEnabled()) && !in.NamespaceScoped()
+}
+
+// Only deployed on Kubernetes, OpenShift has its own
+func (in *Observability) InfrastructureExportersEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.InfrastructureExporters != nil &&
+		in.Spec.SelfContained.InfrastructureExporters.Enabled != nil && *in.Spec.SelfContained.InfrastructureExporters.Enabled && in.IsKubernetesCluster()
+}
+
+// The exporter watches the events of all namespaces, which namespaced roles don't allow
+func (in *Observability) EventExporterEnabled() bool {
+	return in.Spec.SelfContained != nil && in.Spec.SelfContained.EventExporter != nil &&
+		in.Spec.SelfContained.EventExporter.Enabled != nil && *in.Spec.SelfContained.EventExporter.Enabled && !in.NamespaceScoped()
+}
+
+// OpenShift OAuth is only available on OpenShift, the proxy is the default
+func (in *Observability) GetGrafanaAuthenticationType() GrafanaAuthenticationType {
+	if in.Spec.GrafanaAuthentication == nil || in.Spec.GrafanaAuthentication.Type == "" {
+		return GrafanaAuthenticationProxy
+	}
+	if in.Spec.GrafanaAuthentication.Type == GrafanaAuthenticationOpenShift && in.IsKubernetesCluster() {
+		return GrafanaAuthenticationProxy
+	}
+	return in.Spec.GrafanaAuthentication.Type
+}
+
+func (in *Observability) FIPSModeEnabled() bool {
+	return in.Spec.FIPSMode != nil && *in.Spec.FIPSMode
+}
+
+func (in *Observability) AutoResizeEnabled() bool {
+	return in.Spec.AutoResize != nil && *in.Spec.AutoResize
+}
+
+func (in *Observability) PrometheusAutosizingEnabled() bool {
+	return in.Spec.Storage != nil && in.Spec.Storage.PrometheusAutosizing != nil && in.Spec.Storage.PrometheusAutosizing.Enabled != nil && *in.Spec.Storage.PrometheusAutosizing.Enabled
+}
+
+func (in *Observability) PrometheusAutoResizeEnabled() bool {
+	return in.PrometheusAutosizingEnabled() && in.Spec.Storage.PrometheusAutosizing.Resize != nil && *in.Spec.Storage.PrometheusAutosizing.Resize
+}
+
 func (in *Observability) GetPrometheusOperatorNamespace() string {
 	if in.DescopedModeEnabled() && in.Spec.DescopedMode.PrometheusOperatorNamespace != "" {
 		return in.Spec.DescopedMode.PrometheusOperatorNamespace
@@ -648,3 +648,444 @@ func TestObservabilityTypes_HasBlackboxBearerTokenSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestObservabilityTypes_WALCompressionDisabled(t *testing.T) {
+	type fields struct {
+		TypeMeta   metav1.TypeMeta
+		ObjectMeta metav1.ObjectMeta
+		Spec       ObservabilitySpec
+		Status     ObservabilityStatus
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "true if spec is self contained and WAL compression disabled",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						DisableWALCompression: &([]bool{true})[0],
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "false if spec is not self contained",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: nil,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "false if DisableWALCompression is false",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					SelfContained: &SelfContained{
+						DisableWALCompression: &([]bool{false})[0],
+					},
+				},
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &Observability{
+				tt.fields.TypeMeta,
+				tt.fields.ObjectMeta,
+				tt.fields.Spec,
+				tt.fields.Status,
+			}
+			result := obs.WALCompressionDisabled()
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestObservabilityTypes_PrometheusAutoResizeEnabled(t *testing.T) {
+	type fields struct {
+		TypeMeta   metav1.TypeMeta
+		ObjectMeta metav1.ObjectMeta
+		Spec       ObservabilitySpec
+		Status     ObservabilityStatus
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "true if autosizing and resize are enabled",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Storage: &Storage{
+						PrometheusAutosizing: &StorageAutosizing{
+							Enabled: &([]bool{true})[0],
+							Resize:  &([]bool{true})[0],
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "false if resize is enabled but autosizing is not",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Storage: &Storage{
+						PrometheusAutosizing: &StorageAutosizing{
+							Resize: &([]bool{true})[0],
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "false if storage is not set",
+			fields: fields{
+				Spec: ObservabilitySpec{},
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &Observability{
+				tt.fields.TypeMeta,
+				tt.fields.ObjectMeta,
+				tt.fields.Spec,
+				tt.fields.Status,
+			}
+			result := obs.PrometheusAutoResizeEnabled()
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestObservabilityTypes_PromtailMasterNodesExcluded(t *testing.T) {
+	type fields struct {
+		TypeMeta   metav1.TypeMeta
+		ObjectMeta metav1.ObjectMeta
+		Spec       ObservabilitySpec
+		Status     ObservabilityStatus
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "true if master nodes are excluded",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Promtail: &PromtailSpec{
+						ExcludeMasterNodes: &([]bool{true})[0],
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "false if promtail is not set",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Promtail: nil,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "false if ExcludeMasterNodes is false",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					Promtail: &PromtailSpec{
+						ExcludeMasterNodes: &([]bool{false})[0],
+					},
+				},
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &Observability{
+				tt.fields.TypeMeta,
+				tt.fields.ObjectMeta,
+				tt.fields.Spec,
+				tt.fields.Status,
+			}
+			result := obs.PromtailMasterNodesExcluded()
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestObservabilityTypes_IsKubernetesCluster(t *testing.T) {
+	type fields struct {
+		TypeMeta   metav1.TypeMeta
+		ObjectMeta metav1.ObjectMeta
+		Spec       ObservabilitySpec
+		Status     ObservabilityStatus
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "true if cluster type is kubernetes",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ClusterType: ClusterTypeKubernetes,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "true if kubernetes was detected and cluster type is not set",
+			fields: fields{
+				Status: ObservabilityStatus{
+					ClusterType: ClusterTypeKubernetes,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "false if cluster type overrides the detected type",
+			fields: fields{
+				Spec: ObservabilitySpec{
+					ClusterType: ClusterTypeOpenShift,
+				},
+				Status: ObservabilityStatus{
+					ClusterType: ClusterTypeKubernetes,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "false if cluster type is not set or detected",
+			fields: fields{
+				Spec: ObservabilitySpec{},
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &Observability{
+				tt.fields.TypeMeta,
+				tt.fields.ObjectMeta,
+				tt.fields.Spec,
+				tt.fields.Status,
+			}
+			result := obs.IsKubernetesCluster()
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestObservabilityTypes_RuleDestination(t *testing.T) {
+	tests := []struct {
+		name              string
+		spec              ObservabilitySpec
+		wantCluster       bool
+		wantObservatorium bool
+	}{
+		{
+			name:              "rules in the cluster by default",
+			spec:              ObservabilitySpec{},
+			wantCluster:       true,
+			wantObservatorium: false,
+		},
+		{
+			name: "rules only in observatorium",
+			spec: ObservabilitySpec{
+				RuleDestination: RuleDestinationObservatorium,
+			},
+			wantCluster:       false,
+			wantObservatorium: true,
+		},
+		{
+			name: "rules in both",
+			spec: ObservabilitySpec{
+				RuleDestination: RuleDestinationBoth,
+			},
+			wantCluster:       true,
+			wantObservatorium: true,
+		},
+		{
+			name: "rules stay in the cluster if observatorium is disabled",
+			spec: ObservabilitySpec{
+				RuleDestination: RuleDestinationObservatorium,
+				SelfContained: &SelfContained{
+					DisableObservatorium: &([]bool{true})[0],
+				},
+			},
+			wantCluster:       true,
+			wantObservatorium: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &Observability{Spec: tt.spec}
+			Expect(obs.ClusterRulesEnabled()).To(Equal(tt.wantCluster))
+			Expect(obs.ObservatoriumRulesEnabled()).To(Equal(tt.wantObservatorium))
+		})
+	}
+}
+
+func TestObservabilityTypes_KubeletScrapingEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ObservabilitySpec
+		want bool
+	}{
+		{
+			name: "kubelet metrics are federated by default",
+			spec: ObservabilitySpec{
+				SelfContained: &SelfContained{
+					ScrapeKubelet: &([]bool{true})[0],
+				},
+			},
+			want: false,
+		},
+		{
+			name: "scraped without federation",
+			spec: ObservabilitySpec{
+				SelfContained: &SelfContained{
+					DisableFederation: &([]bool{true})[0],
+					ScrapeKubelet:     &([]bool{true})[0],
+				},
+			},
+			want: true,
+		},
+		{
+			name: "scraped with the infrastructure exporters",
+			spec: ObservabilitySpec{
+				ClusterType: ClusterTypeKubernetes,
+				SelfContained: &SelfContained{
+					ScrapeKubelet:           &([]bool{true})[0],
+					InfrastructureExporters: &InfrastructureExporters{Enabled: &([]bool{true})[0]},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "not scraped when namespace scoped",
+			spec: ObservabilitySpec{
+				TargetNamespaces: []string{"team-a"},
+				SelfContained: &SelfContained{
+					DisableFederation: &([]bool{true})[0],
+					ScrapeKubelet:     &([]bool{true})[0],
+				},
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &Observability{Spec: tt.spec}
+			Expect(obs.KubeletScrapingEnabled()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestObservabilityTypes_Components(t *testing.T) {
+	disabled := &ComponentToggle{Enabled: &([]bool{false})[0]}
+	enabled := &ComponentToggle{Enabled: &([]bool{true})[0]}
+
+	RegisterTestingT(t)
+
+	// Everything is enabled by default
+	obs := &Observability{}
+	Expect(obs.PrometheusEnabled()).To(BeTrue())
+	Expect(obs.AlertmanagerEnabled()).To(BeTrue())
+	Expect(obs.GrafanaEnabled()).To(BeTrue())
+	Expect(obs.PromtailEnabled()).To(BeTrue())
+	Expect(obs.BlackboxExporterDisabled()).To(BeFalse())
+
+	obs.Spec.Components = &Components{
+		Prometheus:   disabled,
+		Alertmanager: enabled,
+		Grafana:      disabled,
+		Promtail:     &ComponentToggle{},
+		Blackbox:     disabled,
+	}
+	Expect(obs.PrometheusEnabled()).To(BeFalse())
+	Expect(obs.AlertmanagerEnabled()).To(BeTrue())
+	Expect(obs.GrafanaEnabled()).To(BeFalse())
+	Expect(obs.PromtailEnabled()).To(BeTrue())
+	Expect(obs.BlackboxExporterDisabled()).To(BeTrue())
+
+	// Descoped mode runs without Grafana, the self contained flag disables the blackbox exporter as well
+	obs.Spec.Components = &Components{Grafana: enabled, Blackbox: enabled}
+	obs.Spec.DescopedMode = &DescopedMode{Enabled: &([]bool{true})[0]}
+	obs.Spec.SelfContained = &SelfContained{DisableBlackboxExporter: &([]bool{true})[0]}
+	Expect(obs.GrafanaEnabled()).To(BeFalse())
+	Expect(obs.BlackboxExporterDisabled()).To(BeTrue())
+}
+
+func TestObservabilityTypes_ScrapeTargetHealthEnabled(t *testing.T) {
+	RegisterTestingT(t)
+
+	obs := &Observability{}
+	Expect(obs.ScrapeTargetHealthEnabled()).To(BeTrue())
+
+	obs.Spec.ScrapeTargetHealth = &ScrapeTargetHealth{Enabled: &([]bool{false})[0]}
+	Expect(obs.ScrapeTargetHealthEnabled()).To(BeFalse())
+
+	// Only the managed Prometheus is summarized
+	obs.Spec.ScrapeTargetHealth = nil
+	obs.Spec.ExternalPrometheus = &ExternalPrometheus{Name: "prometheus"}
+	Expect(obs.ScrapeTargetHealthEnabled()).To(BeFalse())
+}
+
+func TestObservabilityTypes_ResyncRequested(t *testing.T) {
+	RegisterTestingT(t)
+
+	obs := &Observability{}
+	Expect(obs.ResyncRequested()).To(BeFalse())
+
+	obs.Annotations = map[string]string{ResyncAnnotation: "2022-10-01T00:00:00Z"}
+	Expect(obs.ResyncRequested()).To(BeTrue())
+
+	// Only requested again once the value changes
+	obs.Status.ResyncRequest = "2022-10-01T00:00:00Z"
+	Expect(obs.ResyncRequested()).To(BeFalse())
+}
+
+func TestObservabilityTypes_RetainStorageOnDeleteEnabled(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Volume claims are kept unless their deletion is requested
+	obs := &Observability{}
+	Expect(obs.RetainStorageOnDeleteEnabled()).To(BeTrue())
+
+	obs.Spec.RetainStorageOnDelete = &([]bool{true})[0]
+	Expect(obs.RetainStorageOnDeleteEnabled()).To(BeTrue())
+
+	obs.Spec.RetainStorageOnDelete = &([]bool{false})[0]
+	Expect(obs.RetainStorageOnDeleteEnabled()).To(BeFalse())
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Registers the namespaces, metrics and dashboards of an application team with the stack
+type ObservabilityTenantSpec struct {
+	// Namespaces of the team in addition to the namespace of the tenant. They have to opt in with the
+	// observability.redhat.com/tenant label set to the namespace of the tenant.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Selects further namespaces of the team by their labels, they have to opt in like the namespaces
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Patterns of the metric names that are remote written, added to the keep list of the metric filter.
+	// Every pattern has to start with the metric prefix of the team, e.g. payments_, the prefixes of the
+	// cluster metrics such as kube_ or node_ are reserved.
+	RemoteWriteAllowlist []string `json:"remoteWriteAllowlist,omitempty"`
+	// Config maps in the namespace of the tenant, every key ending with .json is a Grafana dashboard
+	Dashboards []string `json:"dashboards,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ObservabilityTenant onboards an application team, the Observability CR aggregates all tenants
+type ObservabilityTenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ObservabilityTenantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ObservabilityTenantList contains a list of ObservabilityTenant
+type ObservabilityTenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ObservabilityTenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ObservabilityTenant{}, &ObservabilityTenantList{})
+}
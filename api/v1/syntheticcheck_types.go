@@ -0,0 +1,119 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Exactly one of the checks is set. Targets have to be public addresses, internal ones are rejected.
+type SyntheticCheckSpec struct {
+	// Time between two runs, defaults to 1m
+	Interval string `json:"interval,omitempty"`
+	// Time a run may take, including all steps of an HTTP sequence, defaults to 10s
+	Timeout     string            `json:"timeout,omitempty"`
+	HTTP        *HTTPCheck        `json:"http,omitempty"`
+	DNS         *DNSCheck         `json:"dns,omitempty"`
+	TCP         *TCPCheck         `json:"tcp,omitempty"`
+	Certificate *CertificateCheck `json:"certificate,omitempty"`
+}
+
+// Requests that run in order and share cookies, the first failed step fails the run
+type HTTPCheck struct {
+	Steps []HTTPCheckStep `json:"steps"`
+	// Skip the verification of the server certificates
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// The url, headers and body may refer to values captured by earlier steps as ${name}
+type HTTPCheckStep struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Defaults to GET
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	// Defaults to any 2xx status
+	ExpectedStatusCodes []int `json:"expectedStatusCodes,omitempty"`
+	// Regular expression that the response body has to match
+	BodyRegex string `json:"bodyRegex,omitempty"`
+	// Regular expressions by value name, the first group of the match in the response body is captured
+	Capture map[string]string `json:"capture,omitempty"`
+}
+
+type DNSCheck struct {
+	Host string `json:"host"`
+	// Address of the resolver as host:port, defaults to the resolver of the operator
+	Server string `json:"server,omitempty"`
+	// One of A, AAAA, CNAME, MX, NS or TXT, defaults to A
+	// +kubebuilder:validation:Enum=A;AAAA;CNAME;MX;NS;TXT
+	RecordType string `json:"recordType,omitempty"`
+	// At least one of the answers has to be one of these values
+	ExpectedValues []string `json:"expectedValues,omitempty"`
+}
+
+type TCPCheck struct {
+	// host:port
+	Address string `json:"address"`
+	// Complete a TLS handshake after connecting
+	TLS bool `json:"tls,omitempty"`
+}
+
+// Fails when the certificate of a TLS endpoint expires within the threshold
+type CertificateCheck struct {
+	// host:port
+	Address string `json:"address"`
+	// Defaults to the host of the address
+	ServerName string `json:"serverName,omitempty"`
+	// Defaults to 14d
+	ExpiryThreshold string `json:"expiryThreshold,omitempty"`
+}
+
+type SyntheticCheckStatus struct {
+	LastRun *metav1.Time `json:"lastRun,omitempty"`
+	Success bool         `json:"success,omitempty"`
+	// Reason of the failure of the last run
+	Message string `json:"message,omitempty"`
+	// Generation of the spec that the last run used
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Success",type=boolean,JSONPath=`.status.success`
+// +kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=`.status.lastRun`
+
+// SyntheticCheck is run periodically by the operator, its results are exported as metrics
+type SyntheticCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SyntheticCheckSpec   `json:"spec,omitempty"`
+	Status SyntheticCheckStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SyntheticCheckList contains a list of SyntheticCheck
+type SyntheticCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SyntheticCheck `json:"items"`
+}
+
+func (in *SyntheticCheck) GetType() string {
+	switch {
+	case in.Spec.HTTP != nil:
+		return "http"
+	case in.Spec.DNS != nil:
+		return "dns"
+	case in.Spec.TCP != nil:
+		return "tcp"
+	case in.Spec.Certificate != nil:
+		return "certificate"
+	default:
+		return ""
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&SyntheticCheck{}, &SyntheticCheckList{})
+}
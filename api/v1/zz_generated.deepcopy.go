@@ -28,6 +28,121 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveGateway) DeepCopyInto(out *ActiveGateway) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveGateway.
+func (in *ActiveGateway) DeepCopy() *ActiveGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalScrapeConfigs) DeepCopyInto(out *AdditionalScrapeConfigs) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalScrapeConfigs.
+func (in *AdditionalScrapeConfigs) DeepCopy() *AdditionalScrapeConfigs {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalScrapeConfigs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Aggregation) DeepCopyInto(out *Aggregation) {
+	*out = *in
+	if in.By != nil {
+		in, out := &in.By, &out.By
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Aggregation.
+func (in *Aggregation) DeepCopy() *Aggregation {
+	if in == nil {
+		return nil
+	}
+	out := new(Aggregation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertForwarderSpec) DeepCopyInto(out *AlertForwarderSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertForwarderSpec.
+func (in *AlertForwarderSpec) DeepCopy() *AlertForwarderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertForwarderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerCalendar) DeepCopyInto(out *AlertmanagerCalendar) {
+	*out = *in
+	if in.UrlSecretRef != nil {
+		in, out := &in.UrlSecretRef, &out.UrlSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerCalendar.
+func (in *AlertmanagerCalendar) DeepCopy() *AlertmanagerCalendar {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerCalendar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerCalendarStatus) DeepCopyInto(out *AlertmanagerCalendarStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerCalendarStatus.
+func (in *AlertmanagerCalendarStatus) DeepCopy() *AlertmanagerCalendarStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerCalendarStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertmanagerConfigGlobal) DeepCopyInto(out *AlertmanagerConfigGlobal) {
 	*out = *in
@@ -43,6 +158,36 @@ func (in *AlertmanagerConfigGlobal) DeepCopy() *AlertmanagerConfigGlobal {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerConfigInhibitRule) DeepCopyInto(out *AlertmanagerConfigInhibitRule) {
+	*out = *in
+	if in.SourceMatchers != nil {
+		in, out := &in.SourceMatchers, &out.SourceMatchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetMatchers != nil {
+		in, out := &in.TargetMatchers, &out.TargetMatchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigInhibitRule.
+func (in *AlertmanagerConfigInhibitRule) DeepCopy() *AlertmanagerConfigInhibitRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerConfigInhibitRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertmanagerConfigReceiver) DeepCopyInto(out *AlertmanagerConfigReceiver) {
 	*out = *in
@@ -61,6 +206,11 @@ func (in *AlertmanagerConfigReceiver) DeepCopyInto(out *AlertmanagerConfigReceiv
 		*out = make([]EmailConfig, len(*in))
 		copy(*out, *in)
 	}
+	if in.SlackConfigs != nil {
+		in, out := &in.SlackConfigs, &out.SlackConfigs
+		*out = make([]SlackConfig, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigReceiver.
@@ -93,6 +243,20 @@ func (in *AlertmanagerConfigRoot) DeepCopyInto(out *AlertmanagerConfigRoot) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InhibitRules != nil {
+		in, out := &in.InhibitRules, &out.InhibitRules
+		*out = make([]AlertmanagerConfigInhibitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TimeIntervals != nil {
+		in, out := &in.TimeIntervals, &out.TimeIntervals
+		*out = make([]AlertmanagerConfigTimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigRoot.
@@ -108,6 +272,11 @@ func (in *AlertmanagerConfigRoot) DeepCopy() *AlertmanagerConfigRoot {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertmanagerConfigRoute) DeepCopyInto(out *AlertmanagerConfigRoute) {
 	*out = *in
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Match != nil {
 		in, out := &in.Match, &out.Match
 		*out = make(map[string]string, len(*in))
@@ -115,6 +284,21 @@ func (in *AlertmanagerConfigRoute) DeepCopyInto(out *AlertmanagerConfigRoute) {
 			(*out)[key] = val
 		}
 	}
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MuteTimeIntervals != nil {
+		in, out := &in.MuteTimeIntervals, &out.MuteTimeIntervals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActiveTimeIntervals != nil {
+		in, out := &in.ActiveTimeIntervals, &out.ActiveTimeIntervals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Routes != nil {
 		in, out := &in.Routes, &out.Routes
 		*out = make([]AlertmanagerConfigRoute, len(*in))
@@ -135,659 +319,3377 @@ func (in *AlertmanagerConfigRoute) DeepCopy() *AlertmanagerConfigRoute {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertmanagerIndex) DeepCopyInto(out *AlertmanagerIndex) {
+func (in *AlertmanagerConfigTimeInterval) DeepCopyInto(out *AlertmanagerConfigTimeInterval) {
 	*out = *in
-	if in.SmtpToEmailAddress != nil {
-		in, out := &in.SmtpToEmailAddress, &out.SmtpToEmailAddress
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.TimeIntervals != nil {
+		in, out := &in.TimeIntervals, &out.TimeIntervals
+		*out = make([]AlertmanagerConfigTimePeriod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerIndex.
-func (in *AlertmanagerIndex) DeepCopy() *AlertmanagerIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigTimeInterval.
+func (in *AlertmanagerConfigTimeInterval) DeepCopy() *AlertmanagerConfigTimeInterval {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertmanagerIndex)
+	out := new(AlertmanagerConfigTimeInterval)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DescopedMode) DeepCopyInto(out *DescopedMode) {
+func (in *AlertmanagerConfigTimePeriod) DeepCopyInto(out *AlertmanagerConfigTimePeriod) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	if in.Times != nil {
+		in, out := &in.Times, &out.Times
+		*out = make([]AlertmanagerConfigTimeRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaysOfMonth != nil {
+		in, out := &in.DaysOfMonth, &out.DaysOfMonth
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Months != nil {
+		in, out := &in.Months, &out.Months
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Years != nil {
+		in, out := &in.Years, &out.Years
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DescopedMode.
-func (in *DescopedMode) DeepCopy() *DescopedMode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigTimePeriod.
+func (in *AlertmanagerConfigTimePeriod) DeepCopy() *AlertmanagerConfigTimePeriod {
 	if in == nil {
 		return nil
 	}
-	out := new(DescopedMode)
+	out := new(AlertmanagerConfigTimePeriod)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DexConfig) DeepCopyInto(out *DexConfig) {
+func (in *AlertmanagerConfigTimeRange) DeepCopyInto(out *AlertmanagerConfigTimeRange) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DexConfig.
-func (in *DexConfig) DeepCopy() *DexConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigTimeRange.
+func (in *AlertmanagerConfigTimeRange) DeepCopy() *AlertmanagerConfigTimeRange {
 	if in == nil {
 		return nil
 	}
-	out := new(DexConfig)
+	out := new(AlertmanagerConfigTimeRange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailConfig) DeepCopyInto(out *EmailConfig) {
+func (in *AlertmanagerDefaultRouting) DeepCopyInto(out *AlertmanagerDefaultRouting) {
 	*out = *in
-	out.EmailHeader = in.EmailHeader
+	if in.PagerDutySecretRef != nil {
+		in, out := &in.PagerDutySecretRef, &out.PagerDutySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SlackSecretRef != nil {
+		in, out := &in.SlackSecretRef, &out.SlackSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PageSeverities != nil {
+		in, out := &in.PageSeverities, &out.PageSeverities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotifySeverities != nil {
+		in, out := &in.NotifySeverities, &out.NotifySeverities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfig.
-func (in *EmailConfig) DeepCopy() *EmailConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerDefaultRouting.
+func (in *AlertmanagerDefaultRouting) DeepCopy() *AlertmanagerDefaultRouting {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailConfig)
+	out := new(AlertmanagerDefaultRouting)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailSubject) DeepCopyInto(out *EmailSubject) {
+func (in *AlertmanagerIndex) DeepCopyInto(out *AlertmanagerIndex) {
 	*out = *in
+	if in.SmtpToEmailAddress != nil {
+		in, out := &in.SmtpToEmailAddress, &out.SmtpToEmailAddress
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AlertRelabelConfigs != nil {
+		in, out := &in.AlertRelabelConfigs, &out.AlertRelabelConfigs
+		*out = make([]monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]AlertmanagerIndexRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InhibitRules != nil {
+		in, out := &in.InhibitRules, &out.InhibitRules
+		*out = make([]AlertmanagerIndexInhibitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MuteTimeIntervals != nil {
+		in, out := &in.MuteTimeIntervals, &out.MuteTimeIntervals
+		*out = make([]AlertmanagerTimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailSubject.
-func (in *EmailSubject) DeepCopy() *EmailSubject {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerIndex.
+func (in *AlertmanagerIndex) DeepCopy() *AlertmanagerIndex {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailSubject)
+	out := new(AlertmanagerIndex)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GrafanaIndex) DeepCopyInto(out *GrafanaIndex) {
+func (in *AlertmanagerIndexInhibitRule) DeepCopyInto(out *AlertmanagerIndexInhibitRule) {
 	*out = *in
-	if in.Dashboards != nil {
-		in, out := &in.Dashboards, &out.Dashboards
+	if in.SourceMatchers != nil {
+		in, out := &in.SourceMatchers, &out.SourceMatchers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.DashboardLabelSelector != nil {
-		in, out := &in.DashboardLabelSelector, &out.DashboardLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.TargetMatchers != nil {
+		in, out := &in.TargetMatchers, &out.TargetMatchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaIndex.
-func (in *GrafanaIndex) DeepCopy() *GrafanaIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerIndexInhibitRule.
+func (in *AlertmanagerIndexInhibitRule) DeepCopy() *AlertmanagerIndexInhibitRule {
 	if in == nil {
 		return nil
 	}
-	out := new(GrafanaIndex)
+	out := new(AlertmanagerIndexInhibitRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Observability) DeepCopyInto(out *Observability) {
+func (in *AlertmanagerIndexRoute) DeepCopyInto(out *AlertmanagerIndexRoute) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MuteTimeIntervals != nil {
+		in, out := &in.MuteTimeIntervals, &out.MuteTimeIntervals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActiveTimeIntervals != nil {
+		in, out := &in.ActiveTimeIntervals, &out.ActiveTimeIntervals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]AlertmanagerIndexRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observability.
-func (in *Observability) DeepCopy() *Observability {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerIndexRoute.
+func (in *AlertmanagerIndexRoute) DeepCopy() *AlertmanagerIndexRoute {
 	if in == nil {
 		return nil
 	}
-	out := new(Observability)
+	out := new(AlertmanagerIndexRoute)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Observability) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservabilityList) DeepCopyInto(out *ObservabilityList) {
+func (in *AlertmanagerMuteTimeInterval) DeepCopyInto(out *AlertmanagerMuteTimeInterval) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Observability, len(*in))
+	if in.TimeIntervals != nil {
+		in, out := &in.TimeIntervals, &out.TimeIntervals
+		*out = make([]AlertmanagerTimePeriod, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Calendar != nil {
+		in, out := &in.Calendar, &out.Calendar
+		*out = new(AlertmanagerCalendar)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityList.
-func (in *ObservabilityList) DeepCopy() *ObservabilityList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerMuteTimeInterval.
+func (in *AlertmanagerMuteTimeInterval) DeepCopy() *AlertmanagerMuteTimeInterval {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservabilityList)
+	out := new(AlertmanagerMuteTimeInterval)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ObservabilityList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerTimeInterval) DeepCopyInto(out *AlertmanagerTimeInterval) {
+	*out = *in
+	if in.TimeIntervals != nil {
+		in, out := &in.TimeIntervals, &out.TimeIntervals
+		*out = make([]AlertmanagerTimePeriod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerTimeInterval.
+func (in *AlertmanagerTimeInterval) DeepCopy() *AlertmanagerTimeInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerTimeInterval)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
+func (in *AlertmanagerTimePeriod) DeepCopyInto(out *AlertmanagerTimePeriod) {
 	*out = *in
-	if in.ConfigurationSelector != nil {
-		in, out := &in.ConfigurationSelector, &out.ConfigurationSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Storage != nil {
-		in, out := &in.Storage, &out.Storage
-		*out = new(Storage)
-		(*in).DeepCopyInto(*out)
+	if in.Times != nil {
+		in, out := &in.Times, &out.Times
+		*out = make([]AlertmanagerTimeRange, len(*in))
+		copy(*out, *in)
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
+	if in.DaysOfMonth != nil {
+		in, out := &in.DaysOfMonth, &out.DaysOfMonth
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.SelfContained != nil {
-		in, out := &in.SelfContained, &out.SelfContained
-		*out = new(SelfContained)
-		(*in).DeepCopyInto(*out)
+	if in.Months != nil {
+		in, out := &in.Months, &out.Months
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.DescopedMode != nil {
-		in, out := &in.DescopedMode, &out.DescopedMode
-		*out = new(DescopedMode)
-		(*in).DeepCopyInto(*out)
+	if in.Years != nil {
+		in, out := &in.Years, &out.Years
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
-func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerTimePeriod.
+func (in *AlertmanagerTimePeriod) DeepCopy() *AlertmanagerTimePeriod {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservabilitySpec)
+	out := new(AlertmanagerTimePeriod)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservabilityStatus) DeepCopyInto(out *ObservabilityStatus) {
+func (in *AlertmanagerTimeRange) DeepCopyInto(out *AlertmanagerTimeRange) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityStatus.
-func (in *ObservabilityStatus) DeepCopy() *ObservabilityStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerTimeRange.
+func (in *AlertmanagerTimeRange) DeepCopy() *AlertmanagerTimeRange {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservabilityStatus)
+	out := new(AlertmanagerTimeRange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObservatoriumIndex) DeepCopyInto(out *ObservatoriumIndex) {
+func (in *CardinalityAnalysis) DeepCopyInto(out *CardinalityAnalysis) {
 	*out = *in
-	if in.DexConfig != nil {
-		in, out := &in.DexConfig, &out.DexConfig
-		*out = new(DexConfig)
-		**out = **in
-	}
-	if in.RedhatSsoConfig != nil {
-		in, out := &in.RedhatSsoConfig, &out.RedhatSsoConfig
-		*out = new(RedhatSsoConfig)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservatoriumIndex.
-func (in *ObservatoriumIndex) DeepCopy() *ObservatoriumIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CardinalityAnalysis.
+func (in *CardinalityAnalysis) DeepCopy() *CardinalityAnalysis {
 	if in == nil {
 		return nil
 	}
-	out := new(ObservatoriumIndex)
+	out := new(CardinalityAnalysis)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PagerDutyConfig) DeepCopyInto(out *PagerDutyConfig) {
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyConfig.
-func (in *PagerDutyConfig) DeepCopy() *PagerDutyConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
 	if in == nil {
 		return nil
 	}
-	out := new(PagerDutyConfig)
+	out := new(CertManagerIssuerRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PrometheusIndex) DeepCopyInto(out *PrometheusIndex) {
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
 	*out = *in
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.PodMonitors != nil {
-		in, out := &in.PodMonitors, &out.PodMonitors
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PodMonitorLabelSelector != nil {
-		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PodMonitorNamespaceSelector != nil {
-		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceMonitorLabelSelector != nil {
-		in, out := &in.ServiceMonitorLabelSelector, &out.ServiceMonitorLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceMonitorNamespaceSelector != nil {
-		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RuleLabelSelector != nil {
-		in, out := &in.RuleLabelSelector, &out.RuleLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.RuleNamespaceSelector != nil {
-		in, out := &in.RuleNamespaceSelector, &out.RuleNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ProbeLabelSelector != nil {
-		in, out := &in.ProbeLabelSelector, &out.ProbeLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ProbeNamespaceSelector != nil {
-		in, out := &in.ProbeNamespaceSelector, &out.ProbeNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
+	out.IssuerRef = in.IssuerRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusIndex.
-func (in *PrometheusIndex) DeepCopy() *PrometheusIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PrometheusIndex)
+	out := new(CertManagerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PromtailIndex) DeepCopyInto(out *PromtailIndex) {
+func (in *CertificateCheck) DeepCopyInto(out *CertificateCheck) {
 	*out = *in
-	if in.NamespaceLabelSelector != nil {
-		in, out := &in.NamespaceLabelSelector, &out.NamespaceLabelSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.DaemonSetLabelSelector != nil {
-		in, out := &in.DaemonSetLabelSelector, &out.DaemonSetLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromtailIndex.
-func (in *PromtailIndex) DeepCopy() *PromtailIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateCheck.
+func (in *CertificateCheck) DeepCopy() *CertificateCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(PromtailIndex)
+	out := new(CertificateCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedhatSsoConfig) DeepCopyInto(out *RedhatSsoConfig) {
+func (in *CertificateExpiry) DeepCopyInto(out *CertificateExpiry) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedhatSsoConfig.
-func (in *RedhatSsoConfig) DeepCopy() *RedhatSsoConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateExpiry.
+func (in *CertificateExpiry) DeepCopy() *CertificateExpiry {
 	if in == nil {
 		return nil
 	}
-	out := new(RedhatSsoConfig)
+	out := new(CertificateExpiry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteWriteIndex) DeepCopyInto(out *RemoteWriteIndex) {
+func (in *ComponentToggle) DeepCopyInto(out *ComponentToggle) {
 	*out = *in
-	if in.QueueConfig != nil {
-		in, out := &in.QueueConfig, &out.QueueConfig
-		*out = new(monitoringv1.QueueConfig)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
 		**out = **in
 	}
-	if in.WriteRelabelConfigs != nil {
-		in, out := &in.WriteRelabelConfigs, &out.WriteRelabelConfigs
-		*out = make([]monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteIndex.
-func (in *RemoteWriteIndex) DeepCopy() *RemoteWriteIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentToggle.
+func (in *ComponentToggle) DeepCopy() *ComponentToggle {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteWriteIndex)
+	out := new(ComponentToggle)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryConfig) DeepCopyInto(out *RepositoryConfig) {
+func (in *Components) DeepCopyInto(out *Components) {
 	*out = *in
-	if in.Grafana != nil {
-		in, out := &in.Grafana, &out.Grafana
-		*out = new(GrafanaIndex)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.Prometheus != nil {
 		in, out := &in.Prometheus, &out.Prometheus
-		*out = new(PrometheusIndex)
+		*out = new(ComponentToggle)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Alertmanager != nil {
 		in, out := &in.Alertmanager, &out.Alertmanager
-		*out = new(AlertmanagerIndex)
+		*out = new(ComponentToggle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(ComponentToggle)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Promtail != nil {
 		in, out := &in.Promtail, &out.Promtail
-		*out = new(PromtailIndex)
+		*out = new(ComponentToggle)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Observatoria != nil {
-		in, out := &in.Observatoria, &out.Observatoria
-		*out = make([]ObservatoriumIndex, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Blackbox != nil {
+		in, out := &in.Blackbox, &out.Blackbox
+		*out = new(ComponentToggle)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfig.
-func (in *RepositoryConfig) DeepCopy() *RepositoryConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Components.
+func (in *Components) DeepCopy() *Components {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryConfig)
+	out := new(Components)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryIndex) DeepCopyInto(out *RepositoryIndex) {
+func (in *ConfigurationError) DeepCopyInto(out *ConfigurationError) {
 	*out = *in
-	if in.Source != nil {
-		in, out := &in.Source, &out.Source
-		*out = new(corev1.Secret)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationError.
+func (in *ConfigurationError) DeepCopy() *ConfigurationError {
+	if in == nil {
+		return nil
 	}
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = new(RepositoryConfig)
-		(*in).DeepCopyInto(*out)
+	out := new(ConfigurationError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialProviderSpec) DeepCopyInto(out *CredentialProviderSpec) {
+	*out = *in
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultCredentialProvider)
+		**out = **in
+	}
+	if in.ExternalSecrets != nil {
+		in, out := &in.ExternalSecrets, &out.ExternalSecrets
+		*out = new(ExternalSecretsCredentialProvider)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryIndex.
-func (in *RepositoryIndex) DeepCopy() *RepositoryIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialProviderSpec.
+func (in *CredentialProviderSpec) DeepCopy() *CredentialProviderSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryIndex)
+	out := new(CredentialProviderSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryInfo) DeepCopyInto(out *RepositoryInfo) {
+func (in *DNSCheck) DeepCopyInto(out *DNSCheck) {
 	*out = *in
-	if in.Source != nil {
-		in, out := &in.Source, &out.Source
-		*out = new(corev1.Secret)
-		(*in).DeepCopyInto(*out)
+	if in.ExpectedValues != nil {
+		in, out := &in.ExpectedValues, &out.ExpectedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryInfo.
-func (in *RepositoryInfo) DeepCopy() *RepositoryInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSCheck.
+func (in *DNSCheck) DeepCopy() *DNSCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryInfo)
+	out := new(DNSCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SelfContained) DeepCopyInto(out *SelfContained) {
+func (in *DescopedMode) DeepCopyInto(out *DescopedMode) {
 	*out = *in
-	if in.DisableRepoSync != nil {
-		in, out := &in.DisableRepoSync, &out.DisableRepoSync
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DisableObservatorium != nil {
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DescopedMode.
+func (in *DescopedMode) DeepCopy() *DescopedMode {
+	if in == nil {
+		return nil
+	}
+	out := new(DescopedMode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DexConfig) DeepCopyInto(out *DexConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DexConfig.
+func (in *DexConfig) DeepCopy() *DexConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DexConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailConfig) DeepCopyInto(out *EmailConfig) {
+	*out = *in
+	out.EmailHeader = in.EmailHeader
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfig.
+func (in *EmailConfig) DeepCopy() *EmailConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailSubject) DeepCopyInto(out *EmailSubject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailSubject.
+func (in *EmailSubject) DeepCopy() *EmailSubject {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailSubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventExporter) DeepCopyInto(out *EventExporter) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventExporter.
+func (in *EventExporter) DeepCopy() *EventExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(EventExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAlertmanager) DeepCopyInto(out *ExternalAlertmanager) {
+	*out = *in
+	if in.Urls != nil {
+		in, out := &in.Urls, &out.Urls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InsecureSkipVerify != nil {
+		in, out := &in.InsecureSkipVerify, &out.InsecureSkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAlertmanager.
+func (in *ExternalAlertmanager) DeepCopy() *ExternalAlertmanager {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAlertmanager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalPrometheus) DeepCopyInto(out *ExternalPrometheus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalPrometheus.
+func (in *ExternalPrometheus) DeepCopy() *ExternalPrometheus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalPrometheus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretsCredentialProvider) DeepCopyInto(out *ExternalSecretsCredentialProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretsCredentialProvider.
+func (in *ExternalSecretsCredentialProvider) DeepCopy() *ExternalSecretsCredentialProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretsCredentialProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationUpstream) DeepCopyInto(out *FederationUpstream) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HonorLabels != nil {
+		in, out := &in.HonorLabels, &out.HonorLabels
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InsecureSkipVerify != nil {
+		in, out := &in.InsecureSkipVerify, &out.InsecureSkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationUpstream.
+func (in *FederationUpstream) DeepCopy() *FederationUpstream {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationUpstream)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FetchPolicy) DeepCopyInto(out *FetchPolicy) {
+	*out = *in
+	if in.RequestsPerSecond != nil {
+		in, out := &in.RequestsPerSecond, &out.RequestsPerSecond
+		*out = new(int)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FetchPolicy.
+func (in *FetchPolicy) DeepCopy() *FetchPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FetchPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAuthenticationSpec) DeepCopyInto(out *GrafanaAuthenticationSpec) {
+	*out = *in
+	if in.RoleMappings != nil {
+		in, out := &in.RoleMappings, &out.RoleMappings
+		*out = make([]GrafanaRoleMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(GrafanaOIDCSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAuthenticationSpec.
+func (in *GrafanaAuthenticationSpec) DeepCopy() *GrafanaAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderIndex) DeepCopyInto(out *GrafanaFolderIndex) {
+	*out = *in
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]GrafanaFolderPermission, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderIndex.
+func (in *GrafanaFolderIndex) DeepCopy() *GrafanaFolderIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaFolderPermission) DeepCopyInto(out *GrafanaFolderPermission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaFolderPermission.
+func (in *GrafanaFolderPermission) DeepCopy() *GrafanaFolderPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaFolderPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaIndex) DeepCopyInto(out *GrafanaIndex) {
+	*out = *in
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DashboardLabelSelector != nil {
+		in, out := &in.DashboardLabelSelector, &out.DashboardLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObservatoriumDatasource != nil {
+		in, out := &in.ObservatoriumDatasource, &out.ObservatoriumDatasource
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Folders != nil {
+		in, out := &in.Folders, &out.Folders
+		*out = make([]GrafanaFolderIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaIndex.
+func (in *GrafanaIndex) DeepCopy() *GrafanaIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOIDCSpec) DeepCopyInto(out *GrafanaOIDCSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOIDCSpec.
+func (in *GrafanaOIDCSpec) DeepCopy() *GrafanaOIDCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOIDCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaRoleMapping) DeepCopyInto(out *GrafanaRoleMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleMapping.
+func (in *GrafanaRoleMapping) DeepCopy() *GrafanaRoleMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaRoleMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPCheck) DeepCopyInto(out *HTTPCheck) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]HTTPCheckStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheck.
+func (in *HTTPCheck) DeepCopy() *HTTPCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPCheckStep) DeepCopyInto(out *HTTPCheckStep) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpectedStatusCodes != nil {
+		in, out := &in.ExpectedStatusCodes, &out.ExpectedStatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Capture != nil {
+		in, out := &in.Capture, &out.Capture
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheckStep.
+func (in *HTTPCheckStep) DeepCopy() *HTTPCheckStep {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPCheckStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostedControlPlaneSpec) DeepCopyInto(out *HostedControlPlaneSpec) {
+	*out = *in
+	if in.InsecureSkipVerify != nil {
+		in, out := &in.InsecureSkipVerify, &out.InsecureSkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]StaticTargetGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostedControlPlaneSpec.
+func (in *HostedControlPlaneSpec) DeepCopy() *HostedControlPlaneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostedControlPlaneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePinningSpec) DeepCopyInto(out *ImagePinningSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CosignPublicKeys != nil {
+		in, out := &in.CosignPublicKeys, &out.CosignPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PullSecrets != nil {
+		in, out := &in.PullSecrets, &out.PullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePinningSpec.
+func (in *ImagePinningSpec) DeepCopy() *ImagePinningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePinningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexRollout) DeepCopyInto(out *IndexRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexRollout.
+func (in *IndexRollout) DeepCopy() *IndexRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfrastructureExporters) DeepCopyInto(out *InfrastructureExporters) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfrastructureExporters.
+func (in *InfrastructureExporters) DeepCopy() *InfrastructureExporters {
+	if in == nil {
+		return nil
+	}
+	out := new(InfrastructureExporters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressEndpoint) DeepCopyInto(out *IngressEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressEndpoint.
+func (in *IngressEndpoint) DeepCopy() *IngressEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(IngressEndpoint)
+		**out = **in
+	}
+	if in.Alertmanager != nil {
+		in, out := &in.Alertmanager, &out.Alertmanager
+		*out = new(IngressEndpoint)
+		**out = **in
+	}
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(IngressEndpoint)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedResource) DeepCopyInto(out *ManagedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedResource.
+func (in *ManagedResource) DeepCopy() *ManagedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricClass) DeepCopyInto(out *MetricClass) {
+	*out = *in
+	if in.Selectors != nil {
+		in, out := &in.Selectors, &out.Selectors
+		*out = make([]MetricClassSelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemoteWrite != nil {
+		in, out := &in.RemoteWrite, &out.RemoteWrite
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Aggregations != nil {
+		in, out := &in.Aggregations, &out.Aggregations
+		*out = make([]Aggregation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricClass.
+func (in *MetricClass) DeepCopy() *MetricClass {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricClassSelector) DeepCopyInto(out *MetricClassSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricClassSelector.
+func (in *MetricClassSelector) DeepCopy() *MetricClassSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricClassSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricFilter) DeepCopyInto(out *MetricFilter) {
+	*out = *in
+	if in.Keep != nil {
+		in, out := &in.Keep, &out.Keep
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drop != nil {
+		in, out := &in.Drop, &out.Drop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricFilter.
+func (in *MetricFilter) DeepCopy() *MetricFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MissingPermission) DeepCopyInto(out *MissingPermission) {
+	*out = *in
+	out.Permission = in.Permission
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MissingPermission.
+func (in *MissingPermission) DeepCopy() *MissingPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(MissingPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceScrapeBudget) DeepCopyInto(out *NamespaceScrapeBudget) {
+	*out = *in
+	if in.Drop != nil {
+		in, out := &in.Drop, &out.Drop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceScrapeBudget.
+func (in *NamespaceScrapeBudget) DeepCopy() *NamespaceScrapeBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceScrapeBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuthProxyAuthorization) DeepCopyInto(out *OAuthProxyAuthorization) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthProxyAuthorization.
+func (in *OAuthProxyAuthorization) DeepCopy() *OAuthProxyAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuthProxyAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Observability) DeepCopyInto(out *Observability) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observability.
+func (in *Observability) DeepCopy() *Observability {
+	if in == nil {
+		return nil
+	}
+	out := new(Observability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Observability) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityList) DeepCopyInto(out *ObservabilityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Observability, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityList.
+func (in *ObservabilityList) DeepCopy() *ObservabilityList {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ObservabilityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
+	*out = *in
+	if in.ConfigurationSelector != nil {
+		in, out := &in.ConfigurationSelector, &out.ConfigurationSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(Storage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Promtail != nil {
+		in, out := &in.Promtail, &out.Promtail
+		*out = new(PromtailSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelfContained != nil {
+		in, out := &in.SelfContained, &out.SelfContained
+		*out = new(SelfContained)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DescopedMode != nil {
+		in, out := &in.DescopedMode, &out.DescopedMode
+		*out = new(DescopedMode)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoResize != nil {
+		in, out := &in.AutoResize, &out.AutoResize
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FIPSMode != nil {
+		in, out := &in.FIPSMode, &out.FIPSMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostedControlPlane != nil {
+		in, out := &in.HostedControlPlane, &out.HostedControlPlane
+		*out = new(HostedControlPlaneSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FederationUpstreams != nil {
+		in, out := &in.FederationUpstreams, &out.FederationUpstreams
+		*out = make([]FederationUpstream, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalAlertmanagers != nil {
+		in, out := &in.ExternalAlertmanagers, &out.ExternalAlertmanagers
+		*out = make([]ExternalAlertmanager, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AlertmanagerMuteTimeIntervals != nil {
+		in, out := &in.AlertmanagerMuteTimeIntervals, &out.AlertmanagerMuteTimeIntervals
+		*out = make([]AlertmanagerMuteTimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AlertForwarder != nil {
+		in, out := &in.AlertForwarder, &out.AlertForwarder
+		*out = new(AlertForwarderSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalPrometheus != nil {
+		in, out := &in.ExternalPrometheus, &out.ExternalPrometheus
+		*out = new(ExternalPrometheus)
+		**out = **in
+	}
+	if in.StaticTargets != nil {
+		in, out := &in.StaticTargets, &out.StaticTargets
+		*out = make([]StaticTargetGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScrapeLimits != nil {
+		in, out := &in.ScrapeLimits, &out.ScrapeLimits
+		*out = new(ScrapeLimits)
+		**out = **in
+	}
+	if in.CardinalityAnalysis != nil {
+		in, out := &in.CardinalityAnalysis, &out.CardinalityAnalysis
+		*out = new(CardinalityAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceScrapeBudgets != nil {
+		in, out := &in.NamespaceScrapeBudgets, &out.NamespaceScrapeBudgets
+		*out = make([]NamespaceScrapeBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricFilter != nil {
+		in, out := &in.MetricFilter, &out.MetricFilter
+		*out = new(MetricFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricClasses != nil {
+		in, out := &in.MetricClasses, &out.MetricClasses
+		*out = make([]MetricClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Thanos != nil {
+		in, out := &in.Thanos, &out.Thanos
+		*out = new(ThanosSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWriteProbe != nil {
+		in, out := &in.RemoteWriteProbe, &out.RemoteWriteProbe
+		*out = new(RemoteWriteProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScrapeTargetHealth != nil {
+		in, out := &in.ScrapeTargetHealth, &out.ScrapeTargetHealth
+		*out = new(ScrapeTargetHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SharedTokenRefresher != nil {
+		in, out := &in.SharedTokenRefresher, &out.SharedTokenRefresher
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TokenRefresher != nil {
+		in, out := &in.TokenRefresher, &out.TokenRefresher
+		*out = new(TokenRefresherSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentity)
+		**out = **in
+	}
+	if in.ClusterLabels != nil {
+		in, out := &in.ClusterLabels, &out.ClusterLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RuleFilters != nil {
+		in, out := &in.RuleFilters, &out.RuleFilters
+		*out = new(ResourceFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DashboardFilters != nil {
+		in, out := &in.DashboardFilters, &out.DashboardFilters
+		*out = new(ResourceFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetainStorageOnDelete != nil {
+		in, out := &in.RetainStorageOnDelete, &out.RetainStorageOnDelete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdoptExistingResources != nil {
+		in, out := &in.AdoptExistingResources, &out.AdoptExistingResources
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BlueGreenUpgrades != nil {
+		in, out := &in.BlueGreenUpgrades, &out.BlueGreenUpgrades
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PinnedConfigRevision != nil {
+		in, out := &in.PinnedConfigRevision, &out.PinnedConfigRevision
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConfigRevisionHistoryLimit != nil {
+		in, out := &in.ConfigRevisionHistoryLimit, &out.ConfigRevisionHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResourceSyncWorkers != nil {
+		in, out := &in.ResourceSyncWorkers, &out.ResourceSyncWorkers
+		*out = new(int)
+		**out = **in
+	}
+	if in.Requeue != nil {
+		in, out := &in.Requeue, &out.Requeue
+		*out = new(RequeuePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fetch != nil {
+		in, out := &in.Fetch, &out.Fetch
+		*out = new(FetchPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = new(Components)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceDiscovery != nil {
+		in, out := &in.NamespaceDiscovery, &out.NamespaceDiscovery
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CredentialProvider != nil {
+		in, out := &in.CredentialProvider, &out.CredentialProvider
+		*out = new(CredentialProviderSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(SecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make(map[ImageComponent]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ArchImageOverrides != nil {
+		in, out := &in.ArchImageOverrides, &out.ArchImageOverrides
+		*out = make(map[string]map[ImageComponent]string, len(*in))
+		for key, val := range *in {
+			var outVal map[ImageComponent]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[ImageComponent]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ImagePinning != nil {
+		in, out := &in.ImagePinning, &out.ImagePinning
+		*out = new(ImagePinningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenancyProxy != nil {
+		in, out := &in.TenancyProxy, &out.TenancyProxy
+		*out = new(TenancyProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusWebTLS != nil {
+		in, out := &in.PrometheusWebTLS, &out.PrometheusWebTLS
+		*out = new(PrometheusWebTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMesh != nil {
+		in, out := &in.ServiceMesh, &out.ServiceMesh
+		*out = new(ServiceMeshSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		**out = **in
+	}
+	if in.OAuthProxyAuthorization != nil {
+		in, out := &in.OAuthProxyAuthorization, &out.OAuthProxyAuthorization
+		*out = make(map[OAuthProxyComponent]OAuthProxyAuthorization, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GrafanaAuthentication != nil {
+		in, out := &in.GrafanaAuthentication, &out.GrafanaAuthentication
+		*out = new(GrafanaAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceLevelObjectives != nil {
+		in, out := &in.ServiceLevelObjectives, &out.ServiceLevelObjectives
+		*out = make([]ServiceLevelObjective, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LogRules != nil {
+		in, out := &in.LogRules, &out.LogRules
+		*out = make([]monitoringv1.RuleGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
+func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityStatus) DeepCopyInto(out *ObservabilityStatus) {
+	*out = *in
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveGateways != nil {
+		in, out := &in.ActiveGateways, &out.ActiveGateways
+		*out = make([]ActiveGateway, len(*in))
+		copy(*out, *in)
+	}
+	if in.TokenRefreshers != nil {
+		in, out := &in.TokenRefreshers, &out.TokenRefreshers
+		*out = make([]TokenRefresherStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = make([]ManagedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrometheusUpgrade != nil {
+		in, out := &in.PrometheusUpgrade, &out.PrometheusUpgrade
+		*out = new(PrometheusUpgradeStatus)
+		**out = **in
+	}
+	if in.IndexRollouts != nil {
+		in, out := &in.IndexRollouts, &out.IndexRollouts
+		*out = make([]IndexRollout, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingPermissions != nil {
+		in, out := &in.MissingPermissions, &out.MissingPermissions
+		*out = make([]MissingPermission, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigurationErrors != nil {
+		in, out := &in.ConfigurationErrors, &out.ConfigurationErrors
+		*out = make([]ConfigurationError, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedImages != nil {
+		in, out := &in.ResolvedImages, &out.ResolvedImages
+		*out = make([]ResolvedImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiringCertificates != nil {
+		in, out := &in.ExpiringCertificates, &out.ExpiringCertificates
+		*out = make([]CertificateExpiry, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]TenantStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DiscoveredNamespaces != nil {
+		in, out := &in.DiscoveredNamespaces, &out.DiscoveredNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScrapeBudgetViolations != nil {
+		in, out := &in.ScrapeBudgetViolations, &out.ScrapeBudgetViolations
+		*out = make([]ScrapeBudgetViolation, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemoteWriteProbes != nil {
+		in, out := &in.RemoteWriteProbes, &out.RemoteWriteProbes
+		*out = make([]RemoteWriteProbeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AlertmanagerCalendars != nil {
+		in, out := &in.AlertmanagerCalendars, &out.AlertmanagerCalendars
+		*out = make([]AlertmanagerCalendarStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScrapeTargets != nil {
+		in, out := &in.ScrapeTargets, &out.ScrapeTargets
+		*out = new(ScrapeTargetsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObservatoriumRules != nil {
+		in, out := &in.ObservatoriumRules, &out.ObservatoriumRules
+		*out = make([]PushedRules, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogRules != nil {
+		in, out := &in.LogRules, &out.LogRules
+		*out = make([]PushedRules, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityStatus.
+func (in *ObservabilityStatus) DeepCopy() *ObservabilityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityTenant) DeepCopyInto(out *ObservabilityTenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityTenant.
+func (in *ObservabilityTenant) DeepCopy() *ObservabilityTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ObservabilityTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityTenantList) DeepCopyInto(out *ObservabilityTenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ObservabilityTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityTenantList.
+func (in *ObservabilityTenantList) DeepCopy() *ObservabilityTenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityTenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ObservabilityTenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityTenantSpec) DeepCopyInto(out *ObservabilityTenantSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWriteAllowlist != nil {
+		in, out := &in.RemoteWriteAllowlist, &out.RemoteWriteAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityTenantSpec.
+func (in *ObservabilityTenantSpec) DeepCopy() *ObservabilityTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservatoriumIndex) DeepCopyInto(out *ObservatoriumIndex) {
+	*out = *in
+	if in.DexConfig != nil {
+		in, out := &in.DexConfig, &out.DexConfig
+		*out = new(DexConfig)
+		**out = **in
+	}
+	if in.RedhatSsoConfig != nil {
+		in, out := &in.RedhatSsoConfig, &out.RedhatSsoConfig
+		*out = new(RedhatSsoConfig)
+		**out = **in
+	}
+	if in.TokenRefresher != nil {
+		in, out := &in.TokenRefresher, &out.TokenRefresher
+		*out = new(TokenRefresherSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sigv4Config != nil {
+		in, out := &in.Sigv4Config, &out.Sigv4Config
+		*out = new(Sigv4Config)
+		**out = **in
+	}
+	if in.ServiceAccountTokenConfig != nil {
+		in, out := &in.ServiceAccountTokenConfig, &out.ServiceAccountTokenConfig
+		*out = new(ServiceAccountTokenConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservatoriumIndex.
+func (in *ObservatoriumIndex) DeepCopy() *ObservatoriumIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservatoriumIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyConfig) DeepCopyInto(out *PagerDutyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyConfig.
+func (in *PagerDutyConfig) DeepCopy() *PagerDutyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Permission) DeepCopyInto(out *Permission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Permission.
+func (in *Permission) DeepCopy() *Permission {
+	if in == nil {
+		return nil
+	}
+	out := new(Permission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusExemplars) DeepCopyInto(out *PrometheusExemplars) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusExemplars.
+func (in *PrometheusExemplars) DeepCopy() *PrometheusExemplars {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusExemplars)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusIndex) DeepCopyInto(out *PrometheusIndex) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodMonitors != nil {
+		in, out := &in.PodMonitors, &out.PodMonitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FederationUpstreams != nil {
+		in, out := &in.FederationUpstreams, &out.FederationUpstreams
+		*out = make([]FederationUpstream, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaticTargets != nil {
+		in, out := &in.StaticTargets, &out.StaticTargets
+		*out = make([]StaticTargetGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricFilter != nil {
+		in, out := &in.MetricFilter, &out.MetricFilter
+		*out = new(MetricFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Aggregations != nil {
+		in, out := &in.Aggregations, &out.Aggregations
+		*out = make([]Aggregation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemoteRead != nil {
+		in, out := &in.RemoteRead, &out.RemoteRead
+		*out = make([]RemoteReadTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitorLabelSelector != nil {
+		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitorNamespaceSelector != nil {
+		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorLabelSelector != nil {
+		in, out := &in.ServiceMonitorLabelSelector, &out.ServiceMonitorLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorNamespaceSelector != nil {
+		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleLabelSelector != nil {
+		in, out := &in.RuleLabelSelector, &out.RuleLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleNamespaceSelector != nil {
+		in, out := &in.RuleNamespaceSelector, &out.RuleNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeLabelSelector != nil {
+		in, out := &in.ProbeLabelSelector, &out.ProbeLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeNamespaceSelector != nil {
+		in, out := &in.ProbeNamespaceSelector, &out.ProbeNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusIndex.
+func (in *PrometheusIndex) DeepCopy() *PrometheusIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusQueryLog) DeepCopyInto(out *PrometheusQueryLog) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusQueryLog.
+func (in *PrometheusQueryLog) DeepCopy() *PrometheusQueryLog {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusQueryLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusUpgradeStatus) DeepCopyInto(out *PrometheusUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusUpgradeStatus.
+func (in *PrometheusUpgradeStatus) DeepCopy() *PrometheusUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusWebTLS) DeepCopyInto(out *PrometheusWebTLS) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusWebTLS.
+func (in *PrometheusWebTLS) DeepCopy() *PrometheusWebTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusWebTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromtailIndex) DeepCopyInto(out *PromtailIndex) {
+	*out = *in
+	if in.NamespaceLabelSelector != nil {
+		in, out := &in.NamespaceLabelSelector, &out.NamespaceLabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DaemonSetLabelSelector != nil {
+		in, out := &in.DaemonSetLabelSelector, &out.DaemonSetLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]monitoringv1.RuleGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromtailIndex.
+func (in *PromtailIndex) DeepCopy() *PromtailIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(PromtailIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromtailSpec) DeepCopyInto(out *PromtailSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludeInfraNodes != nil {
+		in, out := &in.ExcludeInfraNodes, &out.ExcludeInfraNodes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludeMasterNodes != nil {
+		in, out := &in.ExcludeMasterNodes, &out.ExcludeMasterNodes
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromtailSpec.
+func (in *PromtailSpec) DeepCopy() *PromtailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PromtailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushedRules) DeepCopyInto(out *PushedRules) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushedRules.
+func (in *PushedRules) DeepCopy() *PushedRules {
+	if in == nil {
+		return nil
+	}
+	out := new(PushedRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedhatSsoConfig) DeepCopyInto(out *RedhatSsoConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedhatSsoConfig.
+func (in *RedhatSsoConfig) DeepCopy() *RedhatSsoConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RedhatSsoConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteReadTarget) DeepCopyInto(out *RemoteReadTarget) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RequiredMatchers != nil {
+		in, out := &in.RequiredMatchers, &out.RequiredMatchers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InsecureSkipVerify != nil {
+		in, out := &in.InsecureSkipVerify, &out.InsecureSkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteReadTarget.
+func (in *RemoteReadTarget) DeepCopy() *RemoteReadTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteReadTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteWriteIndex) DeepCopyInto(out *RemoteWriteIndex) {
+	*out = *in
+	if in.QueueConfig != nil {
+		in, out := &in.QueueConfig, &out.QueueConfig
+		*out = new(monitoringv1.QueueConfig)
+		**out = **in
+	}
+	if in.WriteRelabelConfigs != nil {
+		in, out := &in.WriteRelabelConfigs, &out.WriteRelabelConfigs
+		*out = make([]monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteIndex.
+func (in *RemoteWriteIndex) DeepCopy() *RemoteWriteIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteWriteProbe) DeepCopyInto(out *RemoteWriteProbe) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Alert != nil {
+		in, out := &in.Alert, &out.Alert
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteProbe.
+func (in *RemoteWriteProbe) DeepCopy() *RemoteWriteProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteWriteProbeStatus) DeepCopyInto(out *RemoteWriteProbeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteProbeStatus.
+func (in *RemoteWriteProbeStatus) DeepCopy() *RemoteWriteProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteWriteTarget) DeepCopyInto(out *RemoteWriteTarget) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.WriteRelabelConfigs != nil {
+		in, out := &in.WriteRelabelConfigs, &out.WriteRelabelConfigs
+		*out = make([]monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueueConfig != nil {
+		in, out := &in.QueueConfig, &out.QueueConfig
+		*out = new(monitoringv1.QueueConfig)
+		**out = **in
+	}
+	if in.InsecureSkipVerify != nil {
+		in, out := &in.InsecureSkipVerify, &out.InsecureSkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteWriteTarget.
+func (in *RemoteWriteTarget) DeepCopy() *RemoteWriteTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteWriteTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfig) DeepCopyInto(out *RepositoryConfig) {
+	*out = *in
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(GrafanaIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alertmanager != nil {
+		in, out := &in.Alertmanager, &out.Alertmanager
+		*out = new(AlertmanagerIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Promtail != nil {
+		in, out := &in.Promtail, &out.Promtail
+		*out = new(PromtailIndex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Observatoria != nil {
+		in, out := &in.Observatoria, &out.Observatoria
+		*out = make([]ObservatoriumIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImageDigests != nil {
+		in, out := &in.ImageDigests, &out.ImageDigests
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfig.
+func (in *RepositoryConfig) DeepCopy() *RepositoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryIndex) DeepCopyInto(out *RepositoryIndex) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(corev1.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(RepositoryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CanaryPercent != nil {
+		in, out := &in.CanaryPercent, &out.CanaryPercent
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryIndex.
+func (in *RepositoryIndex) DeepCopy() *RepositoryIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryInfo) DeepCopyInto(out *RepositoryInfo) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(corev1.Secret)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryInfo.
+func (in *RepositoryInfo) DeepCopy() *RepositoryInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeueBackoff) DeepCopyInto(out *RequeueBackoff) {
+	*out = *in
+	if in.Multiplier != nil {
+		in, out := &in.Multiplier, &out.Multiplier
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeueBackoff.
+func (in *RequeueBackoff) DeepCopy() *RequeueBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeueBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeuePolicy) DeepCopyInto(out *RequeuePolicy) {
+	*out = *in
+	if in.JitterPercent != nil {
+		in, out := &in.JitterPercent, &out.JitterPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(RequeueBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StageBackoffs != nil {
+		in, out := &in.StageBackoffs, &out.StageBackoffs
+		*out = make([]StageRequeueBackoff, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeuePolicy.
+func (in *RequeuePolicy) DeepCopy() *RequeuePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeuePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedImage) DeepCopyInto(out *ResolvedImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedImage.
+func (in *ResolvedImage) DeepCopy() *ResolvedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFilter) DeepCopyInto(out *ResourceFilter) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = new(ResourceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = new(ResourceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFilter.
+func (in *ResourceFilter) DeepCopy() *ResourceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
+func (in *ResourceSelector) DeepCopy() *ResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrapeBudgetViolation) DeepCopyInto(out *ScrapeBudgetViolation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeBudgetViolation.
+func (in *ScrapeBudgetViolation) DeepCopy() *ScrapeBudgetViolation {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrapeBudgetViolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrapeLimits) DeepCopyInto(out *ScrapeLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeLimits.
+func (in *ScrapeLimits) DeepCopy() *ScrapeLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrapeLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrapeTargetHealth) DeepCopyInto(out *ScrapeTargetHealth) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeTargetHealth.
+func (in *ScrapeTargetHealth) DeepCopy() *ScrapeTargetHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrapeTargetHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrapeTargetsStatus) DeepCopyInto(out *ScrapeTargetsStatus) {
+	*out = *in
+	if in.Unhealthy != nil {
+		in, out := &in.Unhealthy, &out.Unhealthy
+		*out = make([]UnhealthyScrapeTargets, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeTargetsStatus.
+func (in *ScrapeTargetsStatus) DeepCopy() *ScrapeTargetsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrapeTargetsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityContextSpec) DeepCopyInto(out *SecurityContextSpec) {
+	*out = *in
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Container != nil {
+		in, out := &in.Container, &out.Container
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Promtail != nil {
+		in, out := &in.Promtail, &out.Promtail
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityContextSpec.
+func (in *SecurityContextSpec) DeepCopy() *SecurityContextSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityContextSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfContained) DeepCopyInto(out *SelfContained) {
+	*out = *in
+	if in.DisableRepoSync != nil {
+		in, out := &in.DisableRepoSync, &out.DisableRepoSync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableObservatorium != nil {
 		in, out := &in.DisableObservatorium, &out.DisableObservatorium
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DisablePagerDuty != nil {
-		in, out := &in.DisablePagerDuty, &out.DisablePagerDuty
-		*out = new(bool)
-		**out = **in
+	if in.DisablePagerDuty != nil {
+		in, out := &in.DisablePagerDuty, &out.DisablePagerDuty
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableDeadmansSnitch != nil {
+		in, out := &in.DisableDeadmansSnitch, &out.DisableDeadmansSnitch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableSmtp != nil {
+		in, out := &in.DisableSmtp, &out.DisableSmtp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableBlackboxExporter != nil {
+		in, out := &in.DisableBlackboxExporter, &out.DisableBlackboxExporter
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SelfSignedCerts != nil {
+		in, out := &in.SelfSignedCerts, &out.SelfSignedCerts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OverrideSelectors != nil {
+		in, out := &in.OverrideSelectors, &out.OverrideSelectors
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FederatedMetrics != nil {
+		in, out := &in.FederatedMetrics, &out.FederatedMetrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodMonitorLabelSelector != nil {
+		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitorNamespaceSelector != nil {
+		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorLabelSelector != nil {
+		in, out := &in.ServiceMonitorLabelSelector, &out.ServiceMonitorLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorNamespaceSelector != nil {
+		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleLabelSelector != nil {
+		in, out := &in.RuleLabelSelector, &out.RuleLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleNamespaceSelector != nil {
+		in, out := &in.RuleNamespaceSelector, &out.RuleNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeLabelSelector != nil {
+		in, out := &in.ProbeLabelSelector, &out.ProbeLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeNamespaceSelector != nil {
+		in, out := &in.ProbeNamespaceSelector, &out.ProbeNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaDashboardLabelSelector != nil {
+		in, out := &in.GrafanaDashboardLabelSelector, &out.GrafanaDashboardLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertManagerResourceRequirement != nil {
+		in, out := &in.AlertManagerResourceRequirement, &out.AlertManagerResourceRequirement
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusResourceRequirement != nil {
+		in, out := &in.PrometheusResourceRequirement, &out.PrometheusResourceRequirement
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusOperatorResourceRequirement != nil {
+		in, out := &in.PrometheusOperatorResourceRequirement, &out.PrometheusOperatorResourceRequirement
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaResourceRequirement != nil {
+		in, out := &in.GrafanaResourceRequirement, &out.GrafanaResourceRequirement
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaOperatorResourceRequirement != nil {
+		in, out := &in.GrafanaOperatorResourceRequirement, &out.GrafanaOperatorResourceRequirement
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisableLogging != nil {
+		in, out := &in.DisableLogging, &out.DisableLogging
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableWALCompression != nil {
+		in, out := &in.DisableWALCompression, &out.DisableWALCompression
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdditionalScrapeConfigs != nil {
+		in, out := &in.AdditionalScrapeConfigs, &out.AdditionalScrapeConfigs
+		*out = new(AdditionalScrapeConfigs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWrite != nil {
+		in, out := &in.RemoteWrite, &out.RemoteWrite
+		*out = make([]RemoteWriteTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemoteRead != nil {
+		in, out := &in.RemoteRead, &out.RemoteRead
+		*out = make([]RemoteReadTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultRules != nil {
+		in, out := &in.DefaultRules, &out.DefaultRules
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableFederation != nil {
+		in, out := &in.DisableFederation, &out.DisableFederation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScrapeKubelet != nil {
+		in, out := &in.ScrapeKubelet, &out.ScrapeKubelet
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InfrastructureExporters != nil {
+		in, out := &in.InfrastructureExporters, &out.InfrastructureExporters
+		*out = new(InfrastructureExporters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventExporter != nil {
+		in, out := &in.EventExporter, &out.EventExporter
+		*out = new(EventExporter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertManagerDefaultRouting != nil {
+		in, out := &in.AlertManagerDefaultRouting, &out.AlertManagerDefaultRouting
+		*out = new(AlertmanagerDefaultRouting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusExemplars != nil {
+		in, out := &in.PrometheusExemplars, &out.PrometheusExemplars
+		*out = new(PrometheusExemplars)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusNativeHistograms != nil {
+		in, out := &in.PrometheusNativeHistograms, &out.PrometheusNativeHistograms
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrometheusEnableFeatures != nil {
+		in, out := &in.PrometheusEnableFeatures, &out.PrometheusEnableFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrometheusQueryLog != nil {
+		in, out := &in.PrometheusQueryLog, &out.PrometheusQueryLog
+		*out = new(PrometheusQueryLog)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfContained.
+func (in *SelfContained) DeepCopy() *SelfContained {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfContained)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountTokenConfig) DeepCopyInto(out *ServiceAccountTokenConfig) {
+	*out = *in
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTokenConfig.
+func (in *ServiceAccountTokenConfig) DeepCopy() *ServiceAccountTokenConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountTokenConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceLevelObjective) DeepCopyInto(out *ServiceLevelObjective) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceLevelObjective.
+func (in *ServiceLevelObjective) DeepCopy() *ServiceLevelObjective {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceLevelObjective)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshSpec) DeepCopyInto(out *ServiceMeshSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MTLSScrapes != nil {
+		in, out := &in.MTLSScrapes, &out.MTLSScrapes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludeInboundPorts != nil {
+		in, out := &in.ExcludeInboundPorts, &out.ExcludeInboundPorts
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeOutboundPorts != nil {
+		in, out := &in.ExcludeOutboundPorts, &out.ExcludeOutboundPorts
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshSpec.
+func (in *ServiceMeshSpec) DeepCopy() *ServiceMeshSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sigv4Config) DeepCopyInto(out *Sigv4Config) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sigv4Config.
+func (in *Sigv4Config) DeepCopy() *Sigv4Config {
+	if in == nil {
+		return nil
+	}
+	out := new(Sigv4Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackConfig) DeepCopyInto(out *SlackConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfig.
+func (in *SlackConfig) DeepCopy() *SlackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageRequeueBackoff) DeepCopyInto(out *StageRequeueBackoff) {
+	*out = *in
+	in.RequeueBackoff.DeepCopyInto(&out.RequeueBackoff)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageRequeueBackoff.
+func (in *StageRequeueBackoff) DeepCopy() *StageRequeueBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(StageRequeueBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticTargetGroup) DeepCopyInto(out *StaticTargetGroup) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServiceMeshMTLS != nil {
+		in, out := &in.ServiceMeshMTLS, &out.ServiceMeshMTLS
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticTargetGroup.
+func (in *StaticTargetGroup) DeepCopy() *StaticTargetGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticTargetGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Storage) DeepCopyInto(out *Storage) {
+	*out = *in
+	if in.PrometheusStorageSpec != nil {
+		in, out := &in.PrometheusStorageSpec, &out.PrometheusStorageSpec
+		*out = new(monitoringv1.StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertManagerStorageSpec != nil {
+		in, out := &in.AlertManagerStorageSpec, &out.AlertManagerStorageSpec
+		*out = new(monitoringv1.StorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusAutosizing != nil {
+		in, out := &in.PrometheusAutosizing, &out.PrometheusAutosizing
+		*out = new(StorageAutosizing)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
+func (in *Storage) DeepCopy() *Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageAutosizing) DeepCopyInto(out *StorageAutosizing) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Resize != nil {
+		in, out := &in.Resize, &out.Resize
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageAutosizing.
+func (in *StorageAutosizing) DeepCopy() *StorageAutosizing {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageAutosizing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyntheticCheck) DeepCopyInto(out *SyntheticCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyntheticCheck.
+func (in *SyntheticCheck) DeepCopy() *SyntheticCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SyntheticCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SyntheticCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyntheticCheckList) DeepCopyInto(out *SyntheticCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SyntheticCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.DisableDeadmansSnitch != nil {
-		in, out := &in.DisableDeadmansSnitch, &out.DisableDeadmansSnitch
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyntheticCheckList.
+func (in *SyntheticCheckList) DeepCopy() *SyntheticCheckList {
+	if in == nil {
+		return nil
 	}
-	if in.DisableSmtp != nil {
-		in, out := &in.DisableSmtp, &out.DisableSmtp
-		*out = new(bool)
-		**out = **in
+	out := new(SyntheticCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SyntheticCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.DisableBlackboxExporter != nil {
-		in, out := &in.DisableBlackboxExporter, &out.DisableBlackboxExporter
-		*out = new(bool)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyntheticCheckSpec) DeepCopyInto(out *SyntheticCheckSpec) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(DNSCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCP != nil {
+		in, out := &in.TCP, &out.TCP
+		*out = new(TCPCheck)
 		**out = **in
 	}
-	if in.SelfSignedCerts != nil {
-		in, out := &in.SelfSignedCerts, &out.SelfSignedCerts
-		*out = new(bool)
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(CertificateCheck)
 		**out = **in
 	}
-	if in.OverrideSelectors != nil {
-		in, out := &in.OverrideSelectors, &out.OverrideSelectors
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyntheticCheckSpec.
+func (in *SyntheticCheckSpec) DeepCopy() *SyntheticCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SyntheticCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyntheticCheckStatus) DeepCopyInto(out *SyntheticCheckStatus) {
+	*out = *in
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyntheticCheckStatus.
+func (in *SyntheticCheckStatus) DeepCopy() *SyntheticCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SyntheticCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPCheck) DeepCopyInto(out *TCPCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPCheck.
+func (in *TCPCheck) DeepCopy() *TCPCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenancyProxySpec) DeepCopyInto(out *TenancyProxySpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
-	if in.FederatedMetrics != nil {
-		in, out := &in.FederatedMetrics, &out.FederatedMetrics
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenancyProxySpec.
+func (in *TenancyProxySpec) DeepCopy() *TenancyProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenancyProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.PodMonitorLabelSelector != nil {
-		in, out := &in.PodMonitorLabelSelector, &out.PodMonitorLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PodMonitorNamespaceSelector != nil {
-		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceMonitorLabelSelector != nil {
-		in, out := &in.ServiceMonitorLabelSelector, &out.ServiceMonitorLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceMonitorNamespaceSelector != nil {
-		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.RemoteWriteAllowlist != nil {
+		in, out := &in.RemoteWriteAllowlist, &out.RemoteWriteAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.RuleLabelSelector != nil {
-		in, out := &in.RuleLabelSelector, &out.RuleLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.RuleNamespaceSelector != nil {
-		in, out := &in.RuleNamespaceSelector, &out.RuleNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
+func (in *TenantStatus) DeepCopy() *TenantStatus {
+	if in == nil {
+		return nil
 	}
-	if in.ProbeLabelSelector != nil {
-		in, out := &in.ProbeLabelSelector, &out.ProbeLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	out := new(TenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosCompactor) DeepCopyInto(out *ThanosCompactor) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
 	}
-	if in.ProbeNamespaceSelector != nil {
-		in, out := &in.ProbeNamespaceSelector, &out.ProbeNamespaceSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
 	}
-	if in.GrafanaDashboardLabelSelector != nil {
-		in, out := &in.GrafanaDashboardLabelSelector, &out.GrafanaDashboardLabelSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosCompactor.
+func (in *ThanosCompactor) DeepCopy() *ThanosCompactor {
+	if in == nil {
+		return nil
 	}
-	if in.AlertManagerResourceRequirement != nil {
-		in, out := &in.AlertManagerResourceRequirement, &out.AlertManagerResourceRequirement
-		*out = new(corev1.ResourceRequirements)
+	out := new(ThanosCompactor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThanosSpec) DeepCopyInto(out *ThanosSpec) {
+	*out = *in
+	if in.ObjectStorageConfig != nil {
+		in, out := &in.ObjectStorageConfig, &out.ObjectStorageConfig
+		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.PrometheusResourceRequirement != nil {
-		in, out := &in.PrometheusResourceRequirement, &out.PrometheusResourceRequirement
-		*out = new(corev1.ResourceRequirements)
+	if in.Compactor != nil {
+		in, out := &in.Compactor, &out.Compactor
+		*out = new(ThanosCompactor)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.PrometheusOperatorResourceRequirement != nil {
-		in, out := &in.PrometheusOperatorResourceRequirement, &out.PrometheusOperatorResourceRequirement
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosSpec.
+func (in *ThanosSpec) DeepCopy() *ThanosSpec {
+	if in == nil {
+		return nil
 	}
-	if in.GrafanaResourceRequirement != nil {
-		in, out := &in.GrafanaResourceRequirement, &out.GrafanaResourceRequirement
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	out := new(ThanosSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenRefresherSpec) DeepCopyInto(out *TokenRefresherSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
 	}
-	if in.GrafanaOperatorResourceRequirement != nil {
-		in, out := &in.GrafanaOperatorResourceRequirement, &out.GrafanaOperatorResourceRequirement
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
 		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.DisableLogging != nil {
-		in, out := &in.DisableLogging, &out.DisableLogging
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfContained.
-func (in *SelfContained) DeepCopy() *SelfContained {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRefresherSpec.
+func (in *TokenRefresherSpec) DeepCopy() *TokenRefresherSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SelfContained)
+	out := new(TokenRefresherSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Storage) DeepCopyInto(out *Storage) {
+func (in *TokenRefresherStatus) DeepCopyInto(out *TokenRefresherStatus) {
 	*out = *in
-	if in.PrometheusStorageSpec != nil {
-		in, out := &in.PrometheusStorageSpec, &out.PrometheusStorageSpec
-		*out = new(monitoringv1.StorageSpec)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRefresherStatus.
+func (in *TokenRefresherStatus) DeepCopy() *TokenRefresherStatus {
+	if in == nil {
+		return nil
 	}
-	if in.AlertManagerStorageSpec != nil {
-		in, out := &in.AlertManagerStorageSpec, &out.AlertManagerStorageSpec
-		*out = new(monitoringv1.StorageSpec)
-		(*in).DeepCopyInto(*out)
+	out := new(TokenRefresherStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnhealthyScrapeTargets) DeepCopyInto(out *UnhealthyScrapeTargets) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnhealthyScrapeTargets.
+func (in *UnhealthyScrapeTargets) DeepCopy() *UnhealthyScrapeTargets {
+	if in == nil {
+		return nil
 	}
+	out := new(UnhealthyScrapeTargets)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
-func (in *Storage) DeepCopy() *Storage {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultCredentialProvider) DeepCopyInto(out *VaultCredentialProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultCredentialProvider.
+func (in *VaultCredentialProvider) DeepCopy() *VaultCredentialProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(Storage)
+	out := new(VaultCredentialProvider)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -806,3 +3708,18 @@ func (in *WebhookConfig) DeepCopy() *WebhookConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentity) DeepCopyInto(out *WorkloadIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentity.
+func (in *WorkloadIdentity) DeepCopy() *WorkloadIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
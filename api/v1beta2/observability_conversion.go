@@ -0,0 +1,200 @@
+package v1beta2
+
+import (
+	"encoding/json"
+	"reflect"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// Convert to the storage version, the sections become the self contained settings again
+func (src *Observability) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1.Observability)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Status.DeepCopyInto(&dst.Status)
+
+	err := convertSharedFields(&src.Spec, &dst.Spec)
+	if err != nil {
+		return err
+	}
+	dst.Spec.SelfContained = getSelfContained(&src.Spec)
+	return nil
+}
+
+// Convert from the storage version, the self contained settings are split into the sections
+func (dst *Observability) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1.Observability)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	src.Status.DeepCopyInto(&dst.Status)
+
+	err := convertSharedFields(&src.Spec, &dst.Spec)
+	if err != nil {
+		return err
+	}
+	if src.Spec.SelfContained != nil {
+		setSections(src.Spec.SelfContained.DeepCopy(), &dst.Spec)
+	}
+	return nil
+}
+
+// The fields that both versions share have the same json names, the self contained settings and
+// the sections are skipped as unknown fields of the other version
+func convertSharedFields(src interface{}, dst interface{}) error {
+	content, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, dst)
+}
+
+func getSelfContained(spec *ObservabilitySpec) *v1.SelfContained {
+	result := &v1.SelfContained{}
+	if sync := spec.Sync; sync != nil {
+		result.DisableRepoSync = sync.DisableRepoSync
+		result.DisableObservatorium = sync.DisableObservatorium
+		result.SelfSignedCerts = sync.SelfSignedCerts
+	}
+	if metrics := spec.Metrics; metrics != nil {
+		result.PrometheusVersion = metrics.Version
+		result.PrometheusResourceRequirement = metrics.Resources
+		result.PrometheusOperatorResourceRequirement = metrics.OperatorResources
+		result.DisableWALCompression = metrics.DisableWALCompression
+		result.ScrapeInterval = metrics.ScrapeInterval
+		result.EvaluationInterval = metrics.EvaluationInterval
+		result.DefaultRules = metrics.DefaultRules
+		result.OverrideSelectors = metrics.OverrideSelectors
+		result.PodMonitorLabelSelector = metrics.PodMonitorSelector
+		result.PodMonitorNamespaceSelector = metrics.PodMonitorNamespaceSelector
+		result.ServiceMonitorLabelSelector = metrics.ServiceMonitorSelector
+		result.ServiceMonitorNamespaceSelector = metrics.ServiceMonitorNamespaceSelector
+		result.RuleLabelSelector = metrics.RuleSelector
+		result.RuleNamespaceSelector = metrics.RuleNamespaceSelector
+		result.ProbeLabelSelector = metrics.ProbeSelector
+		result.ProbeNamespaceSelector = metrics.ProbeNamespaceSelector
+		result.AdditionalScrapeConfigs = metrics.AdditionalScrapeConfigs
+		result.RemoteWrite = metrics.RemoteWrite
+		result.RemoteRead = metrics.RemoteRead
+		result.ScrapeKubelet = metrics.ScrapeKubelet
+		result.InfrastructureExporters = metrics.InfrastructureExporters
+		result.EventExporter = metrics.EventExporter
+		result.PrometheusExemplars = metrics.Exemplars
+		result.PrometheusNativeHistograms = metrics.NativeHistograms
+		result.PrometheusEnableFeatures = metrics.EnableFeatures
+		result.PrometheusQueryLog = metrics.QueryLog
+		if federation := metrics.Federation; federation != nil {
+			result.DisableFederation = federation.Disabled
+			result.FederatedMetrics = federation.Metrics
+		}
+		if blackbox := metrics.Blackbox; blackbox != nil {
+			result.DisableBlackboxExporter = blackbox.Disabled
+			result.BlackboxBearerTokenSecret = blackbox.BearerTokenSecret
+		}
+	}
+	if logs := spec.Logs; logs != nil {
+		result.DisableLogging = logs.Disabled
+	}
+	if alerts := spec.Alerts; alerts != nil {
+		result.AlertManagerVersion = alerts.Version
+		result.AlertManagerResourceRequirement = alerts.Resources
+		result.AlertManagerConfigSecret = alerts.ConfigSecret
+		result.DisablePagerDuty = alerts.DisablePagerDuty
+		result.DisableDeadmansSnitch = alerts.DisableDeadmansSnitch
+		result.DisableSmtp = alerts.DisableSmtp
+		result.AlertManagerDefaultRouting = alerts.DefaultRouting
+	}
+	if dashboards := spec.Dashboards; dashboards != nil {
+		result.GrafanaVersion = dashboards.Version
+		result.GrafanaResourceRequirement = dashboards.Resources
+		result.GrafanaOperatorResourceRequirement = dashboards.OperatorResources
+		result.GrafanaDashboardLabelSelector = dashboards.DashboardSelector
+	}
+
+	if reflect.DeepEqual(result, &v1.SelfContained{}) {
+		return nil
+	}
+	return result.DeepCopy()
+}
+
+// Sections without settings are left out
+func setSections(selfContained *v1.SelfContained, spec *ObservabilitySpec) {
+	sync := &SyncSpec{
+		DisableRepoSync:      selfContained.DisableRepoSync,
+		DisableObservatorium: selfContained.DisableObservatorium,
+		SelfSignedCerts:      selfContained.SelfSignedCerts,
+	}
+	if !reflect.DeepEqual(sync, &SyncSpec{}) {
+		spec.Sync = sync
+	}
+
+	metrics := &MetricsSpec{
+		Version:                         selfContained.PrometheusVersion,
+		Resources:                       selfContained.PrometheusResourceRequirement,
+		OperatorResources:               selfContained.PrometheusOperatorResourceRequirement,
+		DisableWALCompression:           selfContained.DisableWALCompression,
+		ScrapeInterval:                  selfContained.ScrapeInterval,
+		EvaluationInterval:              selfContained.EvaluationInterval,
+		DefaultRules:                    selfContained.DefaultRules,
+		OverrideSelectors:               selfContained.OverrideSelectors,
+		PodMonitorSelector:              selfContained.PodMonitorLabelSelector,
+		PodMonitorNamespaceSelector:     selfContained.PodMonitorNamespaceSelector,
+		ServiceMonitorSelector:          selfContained.ServiceMonitorLabelSelector,
+		ServiceMonitorNamespaceSelector: selfContained.ServiceMonitorNamespaceSelector,
+		RuleSelector:                    selfContained.RuleLabelSelector,
+		RuleNamespaceSelector:           selfContained.RuleNamespaceSelector,
+		ProbeSelector:                   selfContained.ProbeLabelSelector,
+		ProbeNamespaceSelector:          selfContained.ProbeNamespaceSelector,
+		AdditionalScrapeConfigs:         selfContained.AdditionalScrapeConfigs,
+		RemoteWrite:                     selfContained.RemoteWrite,
+		RemoteRead:                      selfContained.RemoteRead,
+		ScrapeKubelet:                   selfContained.ScrapeKubelet,
+		InfrastructureExporters:         selfContained.InfrastructureExporters,
+		EventExporter:                   selfContained.EventExporter,
+		Exemplars:                       selfContained.PrometheusExemplars,
+		NativeHistograms:                selfContained.PrometheusNativeHistograms,
+		EnableFeatures:                  selfContained.PrometheusEnableFeatures,
+		QueryLog:                        selfContained.PrometheusQueryLog,
+	}
+	if selfContained.DisableFederation != nil || len(selfContained.FederatedMetrics) > 0 {
+		metrics.Federation = &FederationSpec{
+			Disabled: selfContained.DisableFederation,
+			Metrics:  selfContained.FederatedMetrics,
+		}
+	}
+	if selfContained.DisableBlackboxExporter != nil || selfContained.BlackboxBearerTokenSecret != "" {
+		metrics.Blackbox = &BlackboxSpec{
+			Disabled:          selfContained.DisableBlackboxExporter,
+			BearerTokenSecret: selfContained.BlackboxBearerTokenSecret,
+		}
+	}
+	if !reflect.DeepEqual(metrics, &MetricsSpec{}) {
+		spec.Metrics = metrics
+	}
+
+	if selfContained.DisableLogging != nil {
+		spec.Logs = &LogsSpec{Disabled: selfContained.DisableLogging}
+	}
+
+	alerts := &AlertsSpec{
+		Version:               selfContained.AlertManagerVersion,
+		Resources:             selfContained.AlertManagerResourceRequirement,
+		ConfigSecret:          selfContained.AlertManagerConfigSecret,
+		DisablePagerDuty:      selfContained.DisablePagerDuty,
+		DisableDeadmansSnitch: selfContained.DisableDeadmansSnitch,
+		DisableSmtp:           selfContained.DisableSmtp,
+		DefaultRouting:        selfContained.AlertManagerDefaultRouting,
+	}
+	if !reflect.DeepEqual(alerts, &AlertsSpec{}) {
+		spec.Alerts = alerts
+	}
+
+	dashboards := &DashboardsSpec{
+		Version:           selfContained.GrafanaVersion,
+		Resources:         selfContained.GrafanaResourceRequirement,
+		OperatorResources: selfContained.GrafanaOperatorResourceRequirement,
+		DashboardSelector: selfContained.GrafanaDashboardLabelSelector,
+	}
+	if !reflect.DeepEqual(dashboards, &DashboardsSpec{}) {
+		spec.Dashboards = dashboards
+	}
+}
@@ -0,0 +1,121 @@
+package v1beta2
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	result := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		result[name] = t.Field(i).Type
+	}
+	return result
+}
+
+func TestObservabilityConversion_SharedFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Fields added to v1 must be added to v1beta2 as well, or they are lost in the conversion
+	v1Fields := jsonFields(reflect.TypeOf(v1.ObservabilitySpec{}))
+	v1beta2Fields := jsonFields(reflect.TypeOf(ObservabilitySpec{}))
+	for name, fieldType := range v1Fields {
+		if name == "selfContained" {
+			continue
+		}
+		Expect(v1beta2Fields).To(HaveKeyWithValue(name, fieldType), name)
+	}
+	for _, name := range []string{"sync", "metrics", "logs", "alerts", "dashboards"} {
+		Expect(v1Fields).ToNot(HaveKey(name))
+	}
+}
+
+func TestObservabilityConversion_RoundTrip(t *testing.T) {
+	RegisterTestingT(t)
+
+	enabled := true
+	resources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	hub := &v1.Observability{
+		ObjectMeta: metav1.ObjectMeta{Name: "observability", Namespace: "test", ResourceVersion: "1"},
+		Spec: v1.ObservabilitySpec{
+			ClusterID:    "cluster",
+			ResyncPeriod: "1m",
+			SelfContained: &v1.SelfContained{
+				DisableRepoSync:                 &enabled,
+				PrometheusVersion:               "v2.38.0",
+				PrometheusResourceRequirement:   resources,
+				ScrapeInterval:                  "15s",
+				PrometheusExemplars:             &v1.PrometheusExemplars{Enabled: &enabled},
+				PrometheusNativeHistograms:      &enabled,
+				PrometheusEnableFeatures:        []string{"memory-snapshot-on-shutdown"},
+				PrometheusQueryLog:              &v1.PrometheusQueryLog{Enabled: &enabled, SlowQueryThreshold: "5s"},
+				ServiceMonitorLabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				DisableFederation:               &enabled,
+				FederatedMetrics:                []string{"up"},
+				BlackboxBearerTokenSecret:       "token",
+				DisableLogging:                  &enabled,
+				AlertManagerConfigSecret:        "alertmanager",
+				DisablePagerDuty:                &enabled,
+				GrafanaVersion:                  "9.1.0",
+				GrafanaDashboardLabelSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "dashboards"}},
+				AlertManagerResourceRequirement: resources,
+				AlertManagerDefaultRouting:      &v1.AlertmanagerDefaultRouting{SlackChannel: "#alerts"},
+			},
+		},
+		Status: v1.ObservabilityStatus{Stage: v1.PrometheusInstallation},
+	}
+
+	spoke := &Observability{}
+	Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	Expect(spoke.Name).To(Equal("observability"))
+	Expect(spoke.Spec.ClusterID).To(Equal("cluster"))
+	Expect(spoke.Spec.ResyncPeriod).To(Equal("1m"))
+	Expect(spoke.Spec.Sync.DisableRepoSync).To(Equal(&enabled))
+	Expect(spoke.Spec.Metrics.Version).To(Equal("v2.38.0"))
+	Expect(spoke.Spec.Metrics.Resources).To(Equal(resources))
+	Expect(spoke.Spec.Metrics.Exemplars.Enabled).To(Equal(&enabled))
+	Expect(spoke.Spec.Metrics.NativeHistograms).To(Equal(&enabled))
+	Expect(spoke.Spec.Metrics.EnableFeatures).To(Equal([]string{"memory-snapshot-on-shutdown"}))
+	Expect(spoke.Spec.Metrics.QueryLog.SlowQueryThreshold).To(Equal("5s"))
+	Expect(spoke.Spec.Metrics.ServiceMonitorSelector.MatchLabels).To(HaveKeyWithValue("app", "test"))
+	Expect(spoke.Spec.Metrics.Federation.Metrics).To(Equal([]string{"up"}))
+	Expect(spoke.Spec.Metrics.Blackbox.BearerTokenSecret).To(Equal("token"))
+	Expect(spoke.Spec.Logs.Disabled).To(Equal(&enabled))
+	Expect(spoke.Spec.Alerts.ConfigSecret).To(Equal("alertmanager"))
+	Expect(spoke.Spec.Alerts.DefaultRouting.SlackChannel).To(Equal("#alerts"))
+	Expect(spoke.Spec.Dashboards.Version).To(Equal("9.1.0"))
+	Expect(spoke.Status.Stage).To(Equal(v1.PrometheusInstallation))
+
+	converted := &v1.Observability{}
+	Expect(spoke.ConvertTo(converted)).To(Succeed())
+	Expect(converted).To(Equal(hub))
+}
+
+func TestObservabilityConversion_WithoutSelfContained(t *testing.T) {
+	RegisterTestingT(t)
+
+	hub := &v1.Observability{Spec: v1.ObservabilitySpec{ClusterID: "cluster"}}
+
+	spoke := &Observability{}
+	Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	Expect(spoke.Spec.Sync).To(BeNil())
+	Expect(spoke.Spec.Metrics).To(BeNil())
+	Expect(spoke.Spec.Logs).To(BeNil())
+	Expect(spoke.Spec.Alerts).To(BeNil())
+	Expect(spoke.Spec.Dashboards).To(BeNil())
+
+	converted := &v1.Observability{}
+	Expect(spoke.ConvertTo(converted)).To(Succeed())
+	Expect(converted.Spec.SelfContained).To(BeNil())
+	Expect(converted).To(Equal(hub))
+}
@@ -0,0 +1,299 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Same fields as the v1 spec, only the self contained settings moved to the component sections.
+// New fields of v1 are added here as well, the conversion test fails for fields that are missing.
+
+// ObservabilitySpec defines the desired state of Observability
+type ObservabilitySpec struct {
+	// Cluster ID. If not provided, the operator tries to obtain it.
+	ClusterID               string                `json:"clusterId,omitempty"`
+	ConfigurationSelector   *metav1.LabelSelector `json:"configurationSelector,omitempty"`
+	ResyncPeriod            string                `json:"resyncPeriod,omitempty"`
+	Storage                 *v1.Storage           `json:"storage,omitempty"`
+	Tolerations             []corev1.Toleration   `json:"tolerations,omitempty"`
+	Affinity                *corev1.Affinity      `json:"affinity,omitempty"`
+	Promtail                *v1.PromtailSpec      `json:"promtail,omitempty"`
+	DescopedMode            *v1.DescopedMode      `json:"descopedMode,omitempty"`
+	Retention               string                `json:"retention,omitempty"`
+	RetentionSize           string                `json:"retentionSize,omitempty"`
+	AlertManagerDefaultName string                `json:"alertManagerDefaultName,omitempty"`
+	PrometheusDefaultName   string                `json:"prometheusDefaultName,omitempty"`
+	GrafanaDefaultName      string                `json:"grafanaDefaultName,omitempty"`
+	// When enabled, the configuration stage computes pending changes to managed
+	// resources and records them in a ConfigMap instead of applying them.
+	DryRun *bool `json:"dryRun,omitempty"`
+	// Apply the resource recommendations from the status to the requests of the managed components
+	AutoResize *bool `json:"autoResize,omitempty"`
+	// Use FIPS capable images, verify all certificates and restrict TLS to FIPS approved versions and ciphers
+	FIPSMode *bool `json:"fipsMode,omitempty"`
+	// Detected from the available APIs if not set. On Kubernetes clusters the OpenShift
+	// specific resources (routes, oauth proxies, serving certificates) are not created.
+	ClusterType v1.ClusterType  `json:"clusterType,omitempty"`
+	Ingress     *v1.IngressSpec `json:"ingress,omitempty"`
+	// Detected from the control plane topology of the OpenShift infrastructure if not set
+	// +kubebuilder:validation:Enum=standalone;hosted
+	ClusterTopology    v1.ClusterTopology         `json:"clusterTopology,omitempty"`
+	HostedControlPlane *v1.HostedControlPlaneSpec `json:"hostedControlPlane,omitempty"`
+	// Federated in addition to the upstreams from the indexes
+	FederationUpstreams []v1.FederationUpstream `json:"federationUpstreams,omitempty"`
+	// Alertmanagers outside of the cluster that Prometheus sends the alerts to, in addition to the
+	// in-cluster Alertmanager unless that is disabled in the components
+	ExternalAlertmanagers []v1.ExternalAlertmanager `json:"externalAlertmanagers,omitempty"`
+	// Muted routes of the in-cluster Alertmanager, e.g. maintenance windows. The dead man's switch is never muted.
+	AlertmanagerMuteTimeIntervals []v1.AlertmanagerMuteTimeInterval `json:"alertmanagerMuteTimeIntervals,omitempty"`
+	// Forwards the alerts of the in-cluster Alertmanager to an external endpoint, e.g. an event bus or ticketing system
+	AlertForwarder *v1.AlertForwarderSpec `json:"alertForwarder,omitempty"`
+	// Existing Prometheus that evaluates the rules and scrapes the monitors of the indexes. No
+	// Prometheus is created and the Prometheus Operator is not installed.
+	ExternalPrometheus *v1.ExternalPrometheus `json:"externalPrometheus,omitempty"`
+	// Scraped in addition to the static targets from the indexes
+	StaticTargets []v1.StaticTargetGroup `json:"staticTargets,omitempty"`
+	// Enforced for all service and pod monitors, lower limits of a monitor take precedence
+	ScrapeLimits        *v1.ScrapeLimits        `json:"scrapeLimits,omitempty"`
+	CardinalityAnalysis *v1.CardinalityAnalysis `json:"cardinalityAnalysis,omitempty"`
+	// Limits of the service and pod monitors by namespace
+	NamespaceScrapeBudgets []v1.NamespaceScrapeBudget `json:"namespaceScrapeBudgets,omitempty"`
+	// Applied to all remote write targets in addition to the filter of the index
+	MetricFilter *v1.MetricFilter `json:"metricFilter,omitempty"`
+	// Retention classes of metrics, series that are in no class are kept for the full retention
+	MetricClasses []v1.MetricClass `json:"metricClasses,omitempty"`
+	// Object storage of the blocks of Prometheus and their downsampling
+	Thanos *v1.ThanosSpec `json:"thanos,omitempty"`
+	// Verifies that the samples written by Prometheus arrive in Observatorium
+	RemoteWriteProbe *v1.RemoteWriteProbe `json:"remoteWriteProbe,omitempty"`
+	// Summary of the down targets of the managed Prometheus in the status
+	ScrapeTargetHealth *v1.ScrapeTargetHealth `json:"scrapeTargetHealth,omitempty"`
+	// Run the token refreshers of all indexes in one deployment per auth realm instead of
+	// a deployment per Observatorium instance and signal type
+	SharedTokenRefresher *bool `json:"sharedTokenRefresher,omitempty"`
+	// Takes precedence over the token refresher settings of the indexes
+	TokenRefresher *v1.TokenRefresherSpec `json:"tokenRefresher,omitempty"`
+	// Cloud identity of the Prometheus service account
+	WorkloadIdentity *v1.WorkloadIdentity `json:"workloadIdentity,omitempty"`
+	// Added to the external labels of Prometheus and the labels of all alerts, e.g. environment,
+	// region or tier, so that Alertmanager routes can distinguish clusters
+	ClusterLabels map[string]string `json:"clusterLabels,omitempty"`
+	// Where the rules of the indexes are evaluated: as PrometheusRules in the cluster (default),
+	// by the Observatorium instance that the index remote writes to, or both
+	RuleDestination v1.RuleDestination `json:"ruleDestination,omitempty"`
+	// Rules and dashboards of the indexes to apply on this cluster
+	RuleFilters      *v1.ResourceFilter `json:"ruleFilters,omitempty"`
+	DashboardFilters *v1.ResourceFilter `json:"dashboardFilters,omitempty"`
+	// Keep the Prometheus volume claims when the CR is deleted, a recreated CR with the same
+	// storage settings binds them again. Defaults to true, false deletes the claims.
+	RetainStorageOnDelete *bool `json:"retainStorageOnDelete,omitempty"`
+	// Take over existing Prometheus, Alertmanager and Grafana CRs with the configured names instead
+	// of replacing their spec. Fields that the operator doesn't set are kept.
+	AdoptExistingResources *bool `json:"adoptExistingResources,omitempty"`
+	// Start a new Prometheus version next to the serving one and switch the service once it is
+	// ready, instead of updating the Prometheus statefulset in place
+	BlueGreenUpgrades *bool `json:"blueGreenUpgrades,omitempty"`
+	// Apply the configuration recorded in this revision instead of fetching the indexes, to roll
+	// back to a known good configuration. The available revisions are kept as secrets.
+	PinnedConfigRevision *int `json:"pinnedConfigRevision,omitempty"`
+	// Number of applied configuration revisions to keep, defaults to 5
+	ConfigRevisionHistoryLimit *int `json:"configRevisionHistoryLimit,omitempty"`
+	// Stop reconciling the CR, the managed resources are left as they are until it is resumed.
+	// Deleting a paused CR still removes them.
+	Paused *bool `json:"paused,omitempty"`
+	// Number of dashboards, rules and pod monitors of the indexes fetched and applied at a time,
+	// defaults to 4. Dry runs are always sequential, so that the report is stable.
+	ResourceSyncWorkers *int `json:"resourceSyncWorkers,omitempty"`
+	// Delays between the reconciles of the stages, once all stages succeeded and while a stage is in
+	// progress or failed. Unlike the resync period, which only applies to fetching the indexes.
+	Requeue *v1.RequeuePolicy `json:"requeue,omitempty"`
+	// Timeout, rate limit and circuit breaker of the requests to the repositories of the indexes
+	Fetch *v1.FetchPolicy `json:"fetch,omitempty"`
+	// Components the operator runs, all are enabled by default. The resources of a disabled
+	// component are removed, e.g. to only run the log pipeline or only the dashboards.
+	Components *v1.Components `json:"components,omitempty"`
+	// Discover monitors, probes and rules only in these namespaces and grant Prometheus access to
+	// them with roles instead of cluster roles. Set when the operator runs with namespace scoped
+	// permissions, the namespace of Prometheus is always included.
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// Add the namespaces annotated with observability.redhat.com/scrape=true to the namespace selectors
+	// of Prometheus and the namespaces of Promtail, so that teams opt in without changing the indexes.
+	// Ignored with target namespaces, the namespaces can't be listed then.
+	NamespaceDiscovery *bool `json:"namespaceDiscovery,omitempty"`
+	// Where the credentials referenced by secret name are looked up: observatorium config and dex
+	// credential secrets, PagerDuty, Dead Man's Snitch and SMTP secrets. Defaults to Kubernetes secrets.
+	CredentialProvider *v1.CredentialProviderSpec `json:"credentialProvider,omitempty"`
+	// Security contexts of the pods and containers created by the operator. The defaults comply with
+	// the restricted pod security profile, except for Promtail which reads the logs of the nodes.
+	SecurityContext *v1.SecurityContextSpec `json:"securityContext,omitempty"`
+	// Images by component: prometheus, blackbox, oauth-proxy, token-refresher, promtail and grafana.
+	// Images without a tag get the default tag or the configured version.
+	ImageOverrides map[v1.ImageComponent]string `json:"imageOverrides,omitempty"`
+	// Registry, optionally with a path, that replaces the registry of all default images, e.g. a
+	// local mirror in disconnected clusters
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+	// Images by node architecture and component, e.g. arm64 builds of images without a multi-arch manifest
+	ArchImageOverrides map[string]map[v1.ImageComponent]string `json:"archImageOverrides,omitempty"`
+	// Node architecture for Prometheus, Alertmanager, Grafana and the token refreshers, e.g. arm64.
+	// Without it they are scheduled on the architectures supported by their images.
+	Architecture string `json:"architecture,omitempty"`
+	// Run the managed images by digest instead of by tag, optionally only once their signature is verified
+	ImagePinning *v1.ImagePinningSpec `json:"imagePinning,omitempty"`
+	TenancyProxy *v1.TenancyProxySpec `json:"tenancyProxy,omitempty"`
+	// Prometheus serves HTTPS itself instead of behind the oauth proxy, for meshes or gateways that authenticate
+	PrometheusWebTLS *v1.PrometheusWebTLS `json:"prometheusWebTLS,omitempty"`
+	// Sidecar injection, excluded ports and mesh scrapes for Istio or OpenShift Service Mesh
+	ServiceMesh *v1.ServiceMeshSpec `json:"serviceMesh,omitempty"`
+	// Serving certificates of Prometheus, Alertmanager and Grafana issued by cert-manager instead of the service CA
+	CertManager *v1.CertManagerSpec `json:"certManager,omitempty"`
+	// Authorization of the oauth proxies by component, defaults to users who can get namespaces
+	OAuthProxyAuthorization map[v1.OAuthProxyComponent]v1.OAuthProxyAuthorization `json:"oauthProxyAuthorization,omitempty"`
+	// Login of Grafana users with OpenShift OAuth or OIDC and their org roles, instead of the oauth proxy
+	GrafanaAuthentication *v1.GrafanaAuthenticationSpec `json:"grafanaAuthentication,omitempty"`
+	// Recorded error ratios, burn rate alerts and a dashboard are generated for every objective
+	ServiceLevelObjectives []v1.ServiceLevelObjective `json:"serviceLevelObjectives,omitempty"`
+	// LogQL alerting and recording rules, evaluated by the Loki ruler of every Observatorium instance that receives logs
+	LogRules []prometheusv1.RuleGroup `json:"logRules,omitempty"`
+	// Fetching the indexes and shipping to Observatorium
+	Sync *SyncSpec `json:"sync,omitempty"`
+	// Prometheus, its selectors, scrape and remote write settings and the exporters
+	Metrics *MetricsSpec `json:"metrics,omitempty"`
+	// Promtail and the forwarding to the Observatorium instances of the indexes
+	Logs *LogsSpec `json:"logs,omitempty"`
+	// Alertmanager and its receivers
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
+	// Grafana and the dashboards it picks up
+	Dashboards *DashboardsSpec `json:"dashboards,omitempty"`
+}
+
+type SyncSpec struct {
+	// Skip fetching the indexes, only the settings of the CR are applied
+	DisableRepoSync *bool `json:"disableRepoSync,omitempty"`
+	// Don't ship metrics and logs to the Observatorium instances of the indexes
+	DisableObservatorium *bool `json:"disableObservatorium,omitempty"`
+	// Trust self-signed certificates of the Observatorium instances
+	SelfSignedCerts *bool `json:"selfSignedCerts,omitempty"`
+}
+
+type MetricsSpec struct {
+	Version               string                       `json:"version,omitempty"`
+	Resources             *corev1.ResourceRequirements `json:"resources,omitempty"`
+	OperatorResources     *corev1.ResourceRequirements `json:"operatorResources,omitempty"`
+	DisableWALCompression *bool                        `json:"disableWALCompression,omitempty"`
+	// Scrape and rule evaluation intervals, take precedence over the intervals of the index
+	ScrapeInterval     string `json:"scrapeInterval,omitempty"`
+	EvaluationInterval string `json:"evaluationInterval,omitempty"`
+	// Built-in rules and dashboards for API availability, pod restarts, volume usage and remote write health
+	DefaultRules *bool `json:"defaultRules,omitempty"`
+	// Use the selectors below instead of the ones of the indexes
+	OverrideSelectors               *bool                       `json:"overrideSelectors,omitempty"`
+	PodMonitorSelector              *metav1.LabelSelector       `json:"podMonitorSelector,omitempty"`
+	PodMonitorNamespaceSelector     *metav1.LabelSelector       `json:"podMonitorNamespaceSelector,omitempty"`
+	ServiceMonitorSelector          *metav1.LabelSelector       `json:"serviceMonitorSelector,omitempty"`
+	ServiceMonitorNamespaceSelector *metav1.LabelSelector       `json:"serviceMonitorNamespaceSelector,omitempty"`
+	RuleSelector                    *metav1.LabelSelector       `json:"ruleSelector,omitempty"`
+	RuleNamespaceSelector           *metav1.LabelSelector       `json:"ruleNamespaceSelector,omitempty"`
+	ProbeSelector                   *metav1.LabelSelector       `json:"probeSelector,omitempty"`
+	ProbeNamespaceSelector          *metav1.LabelSelector       `json:"probeNamespaceSelector,omitempty"`
+	AdditionalScrapeConfigs         *v1.AdditionalScrapeConfigs `json:"additionalScrapeConfigs,omitempty"`
+	Federation                      *FederationSpec             `json:"federation,omitempty"`
+	// Sent to in addition to the Observatorium instances of the indexes
+	RemoteWrite []v1.RemoteWriteTarget `json:"remoteWrite,omitempty"`
+	// Queried in addition to the remote read endpoints of the indexes
+	RemoteRead []v1.RemoteReadTarget `json:"remoteRead,omitempty"`
+	// Scrape the kubelets and cAdvisor through the API server when cluster metrics are not federated
+	ScrapeKubelet *bool `json:"scrapeKubelet,omitempty"`
+	// Managed kube-state-metrics and node-exporter on Kubernetes, scraped instead of federating from kube-prometheus
+	InfrastructureExporters *v1.InfrastructureExporters `json:"infrastructureExporters,omitempty"`
+	// Managed exporter of the Kubernetes events as metrics, with a dashboard
+	EventExporter *v1.EventExporter `json:"eventExporter,omitempty"`
+	Blackbox      *BlackboxSpec     `json:"blackbox,omitempty"`
+	// Exemplars of the scraped metrics, e.g. the trace ids of requests, sent with remote write
+	Exemplars *v1.PrometheusExemplars `json:"exemplars,omitempty"`
+	// Scrape and store native histograms, requires Prometheus 2.40 or later
+	NativeHistograms *bool `json:"nativeHistograms,omitempty"`
+	// Feature flags passed to Prometheus, e.g. memory-snapshot-on-shutdown
+	EnableFeatures []string `json:"enableFeatures,omitempty"`
+	// Query log of Prometheus, with a sidecar that exports the slow queries
+	QueryLog *v1.PrometheusQueryLog `json:"queryLog,omitempty"`
+}
+
+// Federation from openshift-monitoring or kube-prometheus
+type FederationSpec struct {
+	Disabled *bool `json:"disabled,omitempty"`
+	// Federated in addition to the metrics of the indexes
+	Metrics []string `json:"metrics,omitempty"`
+}
+
+type BlackboxSpec struct {
+	Disabled *bool `json:"disabled,omitempty"`
+	// Secret with the bearer token sent with the probes
+	BearerTokenSecret string `json:"bearerTokenSecret,omitempty"`
+}
+
+type LogsSpec struct {
+	Disabled *bool `json:"disabled,omitempty"`
+}
+
+type AlertsSpec struct {
+	Version   string                       `json:"version,omitempty"`
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Secret with an Alertmanager config that replaces the generated one
+	ConfigSecret          string `json:"configSecret,omitempty"`
+	DisablePagerDuty      *bool  `json:"disablePagerDuty,omitempty"`
+	DisableDeadmansSnitch *bool  `json:"disableDeadmansSnitch,omitempty"`
+	DisableSmtp           *bool  `json:"disableSmtp,omitempty"`
+	// Routes of the alerts that no index routes, to PagerDuty and Slack by their severity
+	DefaultRouting *v1.AlertmanagerDefaultRouting `json:"defaultRouting,omitempty"`
+}
+
+type DashboardsSpec struct {
+	Version           string                       `json:"version,omitempty"`
+	Resources         *corev1.ResourceRequirements `json:"resources,omitempty"`
+	OperatorResources *corev1.ResourceRequirements `json:"operatorResources,omitempty"`
+	// Dashboards picked up by Grafana in addition to the ones of the indexes
+	DashboardSelector *metav1.LabelSelector `json:"dashboardSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Observability is the Schema for the observabilities API
+type Observability struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ObservabilitySpec      `json:"spec,omitempty"`
+	Status v1.ObservabilityStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ObservabilityList contains a list of Observability
+type ObservabilityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Observability `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Observability{}, &ObservabilityList{})
+}
@@ -0,0 +1,754 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsSpec) DeepCopyInto(out *AlertsSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisablePagerDuty != nil {
+		in, out := &in.DisablePagerDuty, &out.DisablePagerDuty
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableDeadmansSnitch != nil {
+		in, out := &in.DisableDeadmansSnitch, &out.DisableDeadmansSnitch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableSmtp != nil {
+		in, out := &in.DisableSmtp, &out.DisableSmtp
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultRouting != nil {
+		in, out := &in.DefaultRouting, &out.DefaultRouting
+		*out = new(apiv1.AlertmanagerDefaultRouting)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsSpec.
+func (in *AlertsSpec) DeepCopy() *AlertsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackboxSpec) DeepCopyInto(out *BlackboxSpec) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackboxSpec.
+func (in *BlackboxSpec) DeepCopy() *BlackboxSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackboxSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardsSpec) DeepCopyInto(out *DashboardsSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperatorResources != nil {
+		in, out := &in.OperatorResources, &out.OperatorResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DashboardSelector != nil {
+		in, out := &in.DashboardSelector, &out.DashboardSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardsSpec.
+func (in *DashboardsSpec) DeepCopy() *DashboardsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationSpec) DeepCopyInto(out *FederationSpec) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationSpec.
+func (in *FederationSpec) DeepCopy() *FederationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogsSpec) DeepCopyInto(out *LogsSpec) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogsSpec.
+func (in *LogsSpec) DeepCopy() *LogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsSpec) DeepCopyInto(out *MetricsSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperatorResources != nil {
+		in, out := &in.OperatorResources, &out.OperatorResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisableWALCompression != nil {
+		in, out := &in.DisableWALCompression, &out.DisableWALCompression
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultRules != nil {
+		in, out := &in.DefaultRules, &out.DefaultRules
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OverrideSelectors != nil {
+		in, out := &in.OverrideSelectors, &out.OverrideSelectors
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PodMonitorSelector != nil {
+		in, out := &in.PodMonitorSelector, &out.PodMonitorSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMonitorNamespaceSelector != nil {
+		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorSelector != nil {
+		in, out := &in.ServiceMonitorSelector, &out.ServiceMonitorSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorNamespaceSelector != nil {
+		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleSelector != nil {
+		in, out := &in.RuleSelector, &out.RuleSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleNamespaceSelector != nil {
+		in, out := &in.RuleNamespaceSelector, &out.RuleNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeSelector != nil {
+		in, out := &in.ProbeSelector, &out.ProbeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProbeNamespaceSelector != nil {
+		in, out := &in.ProbeNamespaceSelector, &out.ProbeNamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalScrapeConfigs != nil {
+		in, out := &in.AdditionalScrapeConfigs, &out.AdditionalScrapeConfigs
+		*out = new(apiv1.AdditionalScrapeConfigs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(FederationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWrite != nil {
+		in, out := &in.RemoteWrite, &out.RemoteWrite
+		*out = make([]apiv1.RemoteWriteTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemoteRead != nil {
+		in, out := &in.RemoteRead, &out.RemoteRead
+		*out = make([]apiv1.RemoteReadTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScrapeKubelet != nil {
+		in, out := &in.ScrapeKubelet, &out.ScrapeKubelet
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InfrastructureExporters != nil {
+		in, out := &in.InfrastructureExporters, &out.InfrastructureExporters
+		*out = new(apiv1.InfrastructureExporters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventExporter != nil {
+		in, out := &in.EventExporter, &out.EventExporter
+		*out = new(apiv1.EventExporter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Blackbox != nil {
+		in, out := &in.Blackbox, &out.Blackbox
+		*out = new(BlackboxSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exemplars != nil {
+		in, out := &in.Exemplars, &out.Exemplars
+		*out = new(apiv1.PrometheusExemplars)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NativeHistograms != nil {
+		in, out := &in.NativeHistograms, &out.NativeHistograms
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableFeatures != nil {
+		in, out := &in.EnableFeatures, &out.EnableFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.QueryLog != nil {
+		in, out := &in.QueryLog, &out.QueryLog
+		*out = new(apiv1.PrometheusQueryLog)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsSpec.
+func (in *MetricsSpec) DeepCopy() *MetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Observability) DeepCopyInto(out *Observability) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observability.
+func (in *Observability) DeepCopy() *Observability {
+	if in == nil {
+		return nil
+	}
+	out := new(Observability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Observability) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilityList) DeepCopyInto(out *ObservabilityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Observability, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityList.
+func (in *ObservabilityList) DeepCopy() *ObservabilityList {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ObservabilityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
+	*out = *in
+	if in.ConfigurationSelector != nil {
+		in, out := &in.ConfigurationSelector, &out.ConfigurationSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(apiv1.Storage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Promtail != nil {
+		in, out := &in.Promtail, &out.Promtail
+		*out = new(apiv1.PromtailSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DescopedMode != nil {
+		in, out := &in.DescopedMode, &out.DescopedMode
+		*out = new(apiv1.DescopedMode)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoResize != nil {
+		in, out := &in.AutoResize, &out.AutoResize
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FIPSMode != nil {
+		in, out := &in.FIPSMode, &out.FIPSMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(apiv1.IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostedControlPlane != nil {
+		in, out := &in.HostedControlPlane, &out.HostedControlPlane
+		*out = new(apiv1.HostedControlPlaneSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FederationUpstreams != nil {
+		in, out := &in.FederationUpstreams, &out.FederationUpstreams
+		*out = make([]apiv1.FederationUpstream, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalAlertmanagers != nil {
+		in, out := &in.ExternalAlertmanagers, &out.ExternalAlertmanagers
+		*out = make([]apiv1.ExternalAlertmanager, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AlertmanagerMuteTimeIntervals != nil {
+		in, out := &in.AlertmanagerMuteTimeIntervals, &out.AlertmanagerMuteTimeIntervals
+		*out = make([]apiv1.AlertmanagerMuteTimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AlertForwarder != nil {
+		in, out := &in.AlertForwarder, &out.AlertForwarder
+		*out = new(apiv1.AlertForwarderSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalPrometheus != nil {
+		in, out := &in.ExternalPrometheus, &out.ExternalPrometheus
+		*out = new(apiv1.ExternalPrometheus)
+		**out = **in
+	}
+	if in.StaticTargets != nil {
+		in, out := &in.StaticTargets, &out.StaticTargets
+		*out = make([]apiv1.StaticTargetGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScrapeLimits != nil {
+		in, out := &in.ScrapeLimits, &out.ScrapeLimits
+		*out = new(apiv1.ScrapeLimits)
+		**out = **in
+	}
+	if in.CardinalityAnalysis != nil {
+		in, out := &in.CardinalityAnalysis, &out.CardinalityAnalysis
+		*out = new(apiv1.CardinalityAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceScrapeBudgets != nil {
+		in, out := &in.NamespaceScrapeBudgets, &out.NamespaceScrapeBudgets
+		*out = make([]apiv1.NamespaceScrapeBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MetricFilter != nil {
+		in, out := &in.MetricFilter, &out.MetricFilter
+		*out = new(apiv1.MetricFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricClasses != nil {
+		in, out := &in.MetricClasses, &out.MetricClasses
+		*out = make([]apiv1.MetricClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Thanos != nil {
+		in, out := &in.Thanos, &out.Thanos
+		*out = new(apiv1.ThanosSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteWriteProbe != nil {
+		in, out := &in.RemoteWriteProbe, &out.RemoteWriteProbe
+		*out = new(apiv1.RemoteWriteProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScrapeTargetHealth != nil {
+		in, out := &in.ScrapeTargetHealth, &out.ScrapeTargetHealth
+		*out = new(apiv1.ScrapeTargetHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SharedTokenRefresher != nil {
+		in, out := &in.SharedTokenRefresher, &out.SharedTokenRefresher
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TokenRefresher != nil {
+		in, out := &in.TokenRefresher, &out.TokenRefresher
+		*out = new(apiv1.TokenRefresherSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(apiv1.WorkloadIdentity)
+		**out = **in
+	}
+	if in.ClusterLabels != nil {
+		in, out := &in.ClusterLabels, &out.ClusterLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RuleFilters != nil {
+		in, out := &in.RuleFilters, &out.RuleFilters
+		*out = new(apiv1.ResourceFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DashboardFilters != nil {
+		in, out := &in.DashboardFilters, &out.DashboardFilters
+		*out = new(apiv1.ResourceFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetainStorageOnDelete != nil {
+		in, out := &in.RetainStorageOnDelete, &out.RetainStorageOnDelete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdoptExistingResources != nil {
+		in, out := &in.AdoptExistingResources, &out.AdoptExistingResources
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BlueGreenUpgrades != nil {
+		in, out := &in.BlueGreenUpgrades, &out.BlueGreenUpgrades
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PinnedConfigRevision != nil {
+		in, out := &in.PinnedConfigRevision, &out.PinnedConfigRevision
+		*out = new(int)
+		**out = **in
+	}
+	if in.ConfigRevisionHistoryLimit != nil {
+		in, out := &in.ConfigRevisionHistoryLimit, &out.ConfigRevisionHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ResourceSyncWorkers != nil {
+		in, out := &in.ResourceSyncWorkers, &out.ResourceSyncWorkers
+		*out = new(int)
+		**out = **in
+	}
+	if in.Requeue != nil {
+		in, out := &in.Requeue, &out.Requeue
+		*out = new(apiv1.RequeuePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fetch != nil {
+		in, out := &in.Fetch, &out.Fetch
+		*out = new(apiv1.FetchPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = new(apiv1.Components)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceDiscovery != nil {
+		in, out := &in.NamespaceDiscovery, &out.NamespaceDiscovery
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CredentialProvider != nil {
+		in, out := &in.CredentialProvider, &out.CredentialProvider
+		*out = new(apiv1.CredentialProviderSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(apiv1.SecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make(map[apiv1.ImageComponent]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ArchImageOverrides != nil {
+		in, out := &in.ArchImageOverrides, &out.ArchImageOverrides
+		*out = make(map[string]map[apiv1.ImageComponent]string, len(*in))
+		for key, val := range *in {
+			var outVal map[apiv1.ImageComponent]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[apiv1.ImageComponent]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ImagePinning != nil {
+		in, out := &in.ImagePinning, &out.ImagePinning
+		*out = new(apiv1.ImagePinningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenancyProxy != nil {
+		in, out := &in.TenancyProxy, &out.TenancyProxy
+		*out = new(apiv1.TenancyProxySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusWebTLS != nil {
+		in, out := &in.PrometheusWebTLS, &out.PrometheusWebTLS
+		*out = new(apiv1.PrometheusWebTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMesh != nil {
+		in, out := &in.ServiceMesh, &out.ServiceMesh
+		*out = new(apiv1.ServiceMeshSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(apiv1.CertManagerSpec)
+		**out = **in
+	}
+	if in.OAuthProxyAuthorization != nil {
+		in, out := &in.OAuthProxyAuthorization, &out.OAuthProxyAuthorization
+		*out = make(map[apiv1.OAuthProxyComponent]apiv1.OAuthProxyAuthorization, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GrafanaAuthentication != nil {
+		in, out := &in.GrafanaAuthentication, &out.GrafanaAuthentication
+		*out = new(apiv1.GrafanaAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceLevelObjectives != nil {
+		in, out := &in.ServiceLevelObjectives, &out.ServiceLevelObjectives
+		*out = make([]apiv1.ServiceLevelObjective, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LogRules != nil {
+		in, out := &in.LogRules, &out.LogRules
+		*out = make([]monitoringv1.RuleGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(SyncSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(LogsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AlertsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = new(DashboardsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilitySpec.
+func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservabilitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncSpec) DeepCopyInto(out *SyncSpec) {
+	*out = *in
+	if in.DisableRepoSync != nil {
+		in, out := &in.DisableRepoSync, &out.DisableRepoSync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableObservatorium != nil {
+		in, out := &in.DisableObservatorium, &out.DisableObservatorium
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SelfSignedCerts != nil {
+		in, out := &in.SelfSignedCerts, &out.SelfSignedCerts
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncSpec.
+func (in *SyncSpec) DeepCopy() *SyncSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ConfigCommand = "config"
+	ResyncCommand = "resync"
+	DiffCommand   = "diff"
+	PauseCommand  = "pause"
+	ResumeCommand = "resume"
+	HealthCommand = "health"
+
+	// Namespace of the operator deployed from config/default
+	DefaultOperatorNamespace = "observability-operator-system"
+)
+
+const usage = `Usage: kubectl observability <command> [flags] [name]
+
+Commands:
+  config   Show the spec of the CR with the defaults of the operator config
+  resync   Sync the indexes without waiting for the resync period
+  diff     Show the changes of the indexes that are pending in dry run mode
+  pause    Stop reconciling the CR
+  resume   Continue reconciling the CR
+  health   Show the health of the components, -watch keeps printing changes
+
+The name of the CR can be omitted if there is only one in the namespace.
+`
+
+// The kubectl observability plugin, a wrapper of the spec and status fields of the CR
+type CLI struct {
+	client    client.Client
+	out       io.Writer
+	namespace string
+}
+
+// Namespace of the CRs if not set with -n, usually the one of the current kubeconfig context
+func New(client client.Client, out io.Writer, namespace string) *CLI {
+	return &CLI{
+		client:    client,
+		out:       out,
+		namespace: namespace,
+	}
+}
+
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(c.out, usage)
+		return nil
+	}
+
+	command := args[0]
+	switch command {
+	case ConfigCommand, ResyncCommand, DiffCommand, PauseCommand, ResumeCommand, HealthCommand:
+	default:
+		fmt.Fprint(c.out, usage)
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	flags.StringVar(&c.namespace, "n", c.namespace, "Namespace of the Observability CR.")
+	operatorNamespace := flags.String("operator-namespace", DefaultOperatorNamespace, "Namespace of the operator config.")
+	operatorConfig := flags.String("operator-config", controllers.DefaultOperatorConfigMapName, "Name of the operator config map.")
+	start := flags.Bool("start", false, "Enable dry run mode and compute the pending changes.")
+	apply := flags.Bool("apply", false, "Disable dry run mode, the next sync applies the pending changes.")
+	watch := flags.Bool("watch", false, "Keep printing the health whenever it changes.")
+	interval := flags.Duration("interval", 10*time.Second, "Time between the checks of the health with -watch.")
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	cr, err := c.getObservability(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case ConfigCommand:
+		return c.config(ctx, cr, client.ObjectKey{Namespace: *operatorNamespace, Name: *operatorConfig})
+	case ResyncCommand:
+		return c.resync(ctx, cr)
+	case DiffCommand:
+		return c.diff(ctx, cr, *start, *apply)
+	case PauseCommand:
+		return c.setPaused(ctx, cr, true)
+	case ResumeCommand:
+		return c.setPaused(ctx, cr, false)
+	default:
+		return c.health(ctx, cr, *watch, *interval)
+	}
+}
+
+// The named CR, or the only one in the namespace
+func (c *CLI) getObservability(ctx context.Context, name string) (*v1.Observability, error) {
+	if name != "" {
+		cr := &v1.Observability{}
+		err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: name}, cr)
+		return cr, err
+	}
+
+	list := &v1.ObservabilityList{}
+	err := c.client.List(ctx, list, client.InNamespace(c.namespace))
+	if err != nil {
+		return nil, err
+	}
+	switch len(list.Items) {
+	case 0:
+		return nil, fmt.Errorf("no observability CR found in namespace %q", c.namespace)
+	case 1:
+		return &list.Items[0], nil
+	default:
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		return nil, fmt.Errorf("multiple observability CRs found in namespace %q, select one of %v", c.namespace, strings.Join(names, ", "))
+	}
+}
+
+func (c *CLI) config(ctx context.Context, cr *v1.Observability, operatorConfig client.ObjectKey) error {
+	spec, err := controllers.GetEffectiveSpec(ctx, c.client, cr, operatorConfig)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return err
+	}
+	_, err = c.out.Write(content)
+	return err
+}
+
+func (c *CLI) resync(ctx context.Context, cr *v1.Observability) error {
+	request := time.Now().UTC().Format(time.RFC3339Nano)
+	err := c.patch(ctx, cr, fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, v1.ResyncAnnotation, request))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "resync of %v/%v requested\n", cr.Namespace, cr.Name)
+	return nil
+}
+
+// The changes are the dry run report of the last sync. Starting a dry run also requests a resync,
+// so that the report is computed right away.
+func (c *CLI) diff(ctx context.Context, cr *v1.Observability, start bool, apply bool) error {
+	switch {
+	case start && apply:
+		return errors.New("-start and -apply are mutually exclusive")
+	case start:
+		request := time.Now().UTC().Format(time.RFC3339Nano)
+		err := c.patch(ctx, cr, fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}},"spec":{"dryRun":true}}`, v1.ResyncAnnotation, request))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "dry run of %v/%v started, the changes are shown once the sync finished\n", cr.Namespace, cr.Name)
+		return nil
+	case apply:
+		err := c.patch(ctx, cr, `{"spec":{"dryRun":null}}`)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "dry run of %v/%v stopped, the pending changes are applied by the next sync\n", cr.Namespace, cr.Name)
+		return nil
+	}
+
+	configMap := model.GetDryRunReportConfigMap(cr)
+	err := c.client.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+	if apierrors.IsNotFound(err) {
+		if !cr.DryRunEnabled() {
+			return errors.New("dry run mode is not enabled, start it with -start")
+		}
+		return errors.New("no dry run report yet, the sync is still in progress")
+	}
+	if err != nil {
+		return err
+	}
+
+	var changes []utils.DryRunChange
+	err = yaml.Unmarshal([]byte(configMap.Data[model.DryRunReportKey]), &changes)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(c.out, formatChanges(changes))
+	return nil
+}
+
+func (c *CLI) setPaused(ctx context.Context, cr *v1.Observability, paused bool) error {
+	patch := `{"spec":{"paused":null}}`
+	action := "resumed"
+	if paused {
+		patch = `{"spec":{"paused":true}}`
+		action = "paused"
+	}
+	err := c.patch(ctx, cr, patch)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "reconciliation of %v/%v %v\n", cr.Namespace, cr.Name, action)
+	return nil
+}
+
+// Print the health, with -watch again whenever it changes until interrupted
+func (c *CLI) health(ctx context.Context, cr *v1.Observability, watch bool, interval time.Duration) error {
+	previous := ""
+	for {
+		pods := &corev1.PodList{}
+		err := c.client.List(ctx, pods, client.InNamespace(cr.GetPrometheusOperatorNamespace()))
+		if err != nil {
+			return err
+		}
+
+		current := formatHealth(cr, pods.Items)
+		if !watch {
+			fmt.Fprint(c.out, current)
+			return nil
+		}
+		if current != previous {
+			fmt.Fprintf(c.out, "--- %v\n%v", time.Now().UTC().Format(time.RFC3339), current)
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+
+		err = c.client.Get(ctx, client.ObjectKeyFromObject(cr), cr)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *CLI) patch(ctx context.Context, cr *v1.Observability, patch string) error {
+	return c.client.Patch(ctx, cr, client.RawPatch(types.MergePatchType, []byte(patch)))
+}
+
+func formatChanges(changes []utils.DryRunChange) string {
+	if len(changes) == 0 {
+		return "no pending changes\n"
+	}
+
+	var result strings.Builder
+	for _, change := range changes {
+		name := change.Name
+		if change.Namespace != "" {
+			name = change.Namespace + "/" + change.Name
+		}
+		fmt.Fprintf(&result, "%v %v %v\n", change.Operation, change.Kind, name)
+		if change.Diff != "" {
+			fmt.Fprintf(&result, "  %v\n", change.Diff)
+		}
+	}
+	return result.String()
+}
+
+// Summary of the status of the CR and the pods of the managed components
+func formatHealth(cr *v1.Observability, pods []corev1.Pod) string {
+	var result strings.Builder
+	s := cr.Status
+
+	fmt.Fprintf(&result, "observability %v/%v: stage %v %v\n", cr.Namespace, cr.Name, s.Stage, s.StageStatus)
+	if s.Paused {
+		fmt.Fprintf(&result, "  reconciliation paused\n")
+	}
+	if s.LastMessage != "" {
+		fmt.Fprintf(&result, "  last message: %v\n", s.LastMessage)
+	}
+	if s.LastSynced != 0 {
+		fmt.Fprintf(&result, "  last synced: %v\n", time.Unix(s.LastSynced, 0).UTC().Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(&result, "  last synced: never\n")
+	}
+	for _, permission := range s.MissingPermissions {
+		fmt.Fprintf(&result, "  missing permission in stage %v: %v %v.%v\n", permission.Stage, permission.Verb, permission.Resource, permission.Group)
+	}
+	for _, configurationError := range s.ConfigurationErrors {
+		fmt.Fprintf(&result, "  index %v failed to %v: %v\n", configurationError.Index, configurationError.Stage, configurationError.Message)
+	}
+
+	if len(pods) > 0 {
+		fmt.Fprintf(&result, "pods:\n")
+		for _, pod := range pods {
+			ready := 0
+			restarts := int32(0)
+			for _, container := range pod.Status.ContainerStatuses {
+				if container.Ready {
+					ready++
+				}
+				restarts += container.RestartCount
+			}
+			fmt.Fprintf(&result, "  %v %v %v/%v ready, %v restarts\n", pod.Name, pod.Status.Phase, ready, len(pod.Spec.Containers), restarts)
+		}
+	}
+
+	if len(s.TokenRefreshers) > 0 {
+		fmt.Fprintf(&result, "token refreshers:\n")
+		for _, refresher := range s.TokenRefreshers {
+			fmt.Fprintf(&result, "  %v %v/%v ready\n", refresher.Name, refresher.ReadyReplicas, refresher.Replicas)
+		}
+	}
+
+	if len(s.RemoteWriteProbes) > 0 {
+		fmt.Fprintf(&result, "remote write:\n")
+		for _, probe := range s.RemoteWriteProbes {
+			if probe.Success {
+				fmt.Fprintf(&result, "  %v ok, latest sample at %v\n", probe.Observatorium, time.Unix(probe.LastSample, 0).UTC().Format(time.RFC3339))
+			} else {
+				fmt.Fprintf(&result, "  %v failing: %v\n", probe.Observatorium, probe.Message)
+			}
+		}
+	}
+
+	if s.ScrapeTargets != nil {
+		fmt.Fprintf(&result, "scrape targets: %v up, %v down\n", s.ScrapeTargets.Up, s.ScrapeTargets.Down)
+		for _, unhealthy := range s.ScrapeTargets.Unhealthy {
+			monitor := unhealthy.Monitor
+			if unhealthy.Namespace != "" {
+				monitor = unhealthy.Namespace + "/" + unhealthy.Monitor
+			}
+			fmt.Fprintf(&result, "  %v %v/%v down: %v\n", monitor, unhealthy.Down, unhealthy.Total, unhealthy.LastError)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestCLI(objs ...client.Object) (*CLI, client.Client, *bytes.Buffer) {
+	scheme := runtime.NewScheme()
+	Expect(v1.AddToScheme(scheme)).To(Succeed())
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	out := &bytes.Buffer{}
+	return New(c, out, "test-namespace"), c, out
+}
+
+func newTestObservability(name string) *v1.Observability {
+	return &v1.Observability{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"},
+	}
+}
+
+func getTestObservability(c client.Client, name string) *v1.Observability {
+	cr := &v1.Observability{}
+	Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: name}, cr)).To(Succeed())
+	return cr
+}
+
+func TestCLI_GetObservability(t *testing.T) {
+	RegisterTestingT(t)
+
+	cli, _, _ := newTestCLI()
+	Expect(cli.Run(context.Background(), []string{ResyncCommand})).To(MatchError(ContainSubstring("no observability CR found")))
+
+	cli, _, _ = newTestCLI(newTestObservability("first"), newTestObservability("second"))
+	Expect(cli.Run(context.Background(), []string{ResyncCommand})).To(MatchError(ContainSubstring("select one of first, second")))
+	Expect(cli.Run(context.Background(), []string{ResyncCommand, "second"})).To(Succeed())
+
+	Expect(cli.Run(context.Background(), []string{"unknown"})).To(MatchError(ContainSubstring("unknown command")))
+}
+
+func TestCLI_Resync(t *testing.T) {
+	RegisterTestingT(t)
+
+	cli, c, out := newTestCLI(newTestObservability("observability"))
+	Expect(cli.Run(context.Background(), []string{ResyncCommand})).To(Succeed())
+	Expect(out.String()).To(Equal("resync of test-namespace/observability requested\n"))
+
+	cr := getTestObservability(c, "observability")
+	Expect(cr.Annotations).To(HaveKey(v1.ResyncAnnotation))
+	Expect(cr.ResyncRequested()).To(BeTrue())
+}
+
+func TestCLI_PauseResume(t *testing.T) {
+	RegisterTestingT(t)
+
+	cli, c, _ := newTestCLI(newTestObservability("observability"))
+	Expect(cli.Run(context.Background(), []string{PauseCommand})).To(Succeed())
+	Expect(getTestObservability(c, "observability").ReconciliationPaused()).To(BeTrue())
+
+	Expect(cli.Run(context.Background(), []string{ResumeCommand, "-n", "test-namespace", "observability"})).To(Succeed())
+	Expect(getTestObservability(c, "observability").Spec.Paused).To(BeNil())
+}
+
+func TestCLI_Diff(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := newTestObservability("observability")
+	cli, c, out := newTestCLI(cr)
+	Expect(cli.Run(context.Background(), []string{DiffCommand})).To(MatchError(ContainSubstring("dry run mode is not enabled")))
+
+	// Starting a dry run also requests a sync
+	Expect(cli.Run(context.Background(), []string{DiffCommand, "-start"})).To(Succeed())
+	cr = getTestObservability(c, "observability")
+	Expect(cr.DryRunEnabled()).To(BeTrue())
+	Expect(cr.ResyncRequested()).To(BeTrue())
+	Expect(cli.Run(context.Background(), []string{DiffCommand})).To(MatchError(ContainSubstring("no dry run report yet")))
+
+	report := model.GetDryRunReportConfigMap(cr)
+	report.Data = map[string]string{
+		model.DryRunReportKey: "- operation: update\n  kind: PrometheusRule\n  namespace: test-namespace\n  name: rules\n  diff: '{\"spec\":{}}'\n",
+	}
+	Expect(c.Create(context.Background(), report)).To(Succeed())
+	out.Reset()
+	Expect(cli.Run(context.Background(), []string{DiffCommand})).To(Succeed())
+	Expect(out.String()).To(Equal("update PrometheusRule test-namespace/rules\n  {\"spec\":{}}\n"))
+
+	Expect(cli.Run(context.Background(), []string{DiffCommand, "-apply"})).To(Succeed())
+	Expect(getTestObservability(c, "observability").DryRunEnabled()).To(BeFalse())
+}
+
+func TestCLI_Config(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := newTestObservability("observability")
+	cr.Spec.Retention = "45d"
+	operatorConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: controllers.DefaultOperatorConfigMapName, Namespace: DefaultOperatorNamespace},
+		Data:       map[string]string{controllers.OperatorConfigKey: "defaults:\n  resyncPeriod: 1h\n  retention: 30d\n"},
+	}
+	cli, _, out := newTestCLI(cr, operatorConfig)
+	Expect(cli.Run(context.Background(), []string{ConfigCommand})).To(Succeed())
+	Expect(out.String()).To(ContainSubstring("resyncPeriod: 1h"))
+	Expect(out.String()).To(ContainSubstring("retention: 45d"))
+}
+
+func TestCLI_FormatHealth(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := newTestObservability("observability")
+	cr.Status = v1.ObservabilityStatus{
+		Stage:       v1.Configuration,
+		StageStatus: v1.ResultSuccess,
+		Paused:      true,
+		LastSynced:  1664582400,
+		TokenRefreshers: []v1.TokenRefresherStatus{
+			{Name: "token-refresher-test", Replicas: 1, ReadyReplicas: 1},
+		},
+		RemoteWriteProbes: []v1.RemoteWriteProbeStatus{
+			{Observatorium: "test", Message: "no probe sample found"},
+		},
+		ScrapeTargets: &v1.ScrapeTargetsStatus{Up: 3, Down: 1, Unhealthy: []v1.UnhealthyScrapeTargets{
+			{Monitor: "serviceMonitor/app", Namespace: "app", Down: 1, Total: 2, LastError: "connection refused"},
+		}},
+	}
+	pods := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus-0"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "prometheus"}, {Name: "config-reloader"}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "prometheus", Ready: true, RestartCount: 2},
+			{Name: "config-reloader"},
+		}},
+	}}
+
+	Expect(formatHealth(cr, pods)).To(Equal(`observability test-namespace/observability: stage configuration success
+  reconciliation paused
+  last synced: 2022-10-01T00:00:00Z
+pods:
+  prometheus-0 Running 1/2 ready, 2 restarts
+token refreshers:
+  token-refresher-test 1/1 ready
+remote write:
+  test failing: no probe sample found
+scrape targets: 3 up, 1 down
+  app/serviceMonitor/app 1/2 down: connection refused
+`))
+}
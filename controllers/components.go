@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The stages of a disabled component remove its resources instead of reconciling them. The
+// Prometheus Operator is installed as long as Prometheus or Alertmanager are enabled, an external
+// Prometheus comes with its own operator.
+func isStageEnabled(cr *apiv1.Observability, stage apiv1.ObservabilityStageName) bool {
+	switch stage {
+	case apiv1.PrometheusInstallation:
+		return (cr.PrometheusEnabled() || cr.AlertmanagerEnabled()) && !cr.ExternalPrometheusEnabled()
+	case apiv1.PrometheusConfiguration:
+		return cr.PrometheusEnabled()
+	case apiv1.AlertmanagerInstallation:
+		return cr.AlertmanagerEnabled()
+	case apiv1.GrafanaInstallation, apiv1.GrafanaConfiguration:
+		return cr.GrafanaEnabled()
+	case apiv1.PromtailInstallation:
+		return cr.PromtailEnabled()
+	default:
+		return true
+	}
+}
+
+// Cleanup stages of the disabled components, in the order of the cleanup of the CR so that the
+// operators are still running while their resources are removed
+func (r *ObservabilityReconciler) getDisabledStages(cr *apiv1.Observability) []apiv1.ObservabilityStageName {
+	var result []apiv1.ObservabilityStageName
+	for _, stage := range r.getCleanupStages() {
+		if !isStageEnabled(cr, stage) {
+			result = append(result, stage)
+		}
+	}
+	return result
+}
+
+func getClusterId(ctx context.Context, c client.Client, cr *apiv1.Observability) (string, error) {
+	if cr.Spec.ClusterID != "" {
+		return cr.Spec.ClusterID, nil
+	}
+	if cr.IsKubernetesCluster() {
+		return utils.GetKubernetesClusterId(ctx, c)
+	}
+	return utils.GetClusterId(ctx, c)
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestComponents_GetDisabledStages(t *testing.T) {
+	RegisterTestingT(t)
+
+	disabled := &apiv1.ComponentToggle{Enabled: &([]bool{false})[0]}
+	r := &ObservabilityReconciler{}
+
+	cr := &apiv1.Observability{}
+	Expect(r.getDisabledStages(cr)).To(BeEmpty())
+
+	// The Prometheus Operator keeps running for Alertmanager
+	cr.Spec.Components = &apiv1.Components{Prometheus: disabled, Promtail: disabled}
+	Expect(r.getDisabledStages(cr)).To(Equal([]apiv1.ObservabilityStageName{
+		apiv1.PrometheusConfiguration,
+		apiv1.PromtailInstallation,
+	}))
+	Expect(isStageEnabled(cr, apiv1.PrometheusInstallation)).To(BeTrue())
+
+	// Only the log pipeline, the components are removed while their operators are still running
+	cr.Spec.Components = &apiv1.Components{Prometheus: disabled, Alertmanager: disabled, Grafana: disabled}
+	Expect(r.getDisabledStages(cr)).To(Equal([]apiv1.ObservabilityStageName{
+		apiv1.PrometheusConfiguration,
+		apiv1.AlertmanagerInstallation,
+		apiv1.GrafanaConfiguration,
+		apiv1.PrometheusInstallation,
+		apiv1.GrafanaInstallation,
+	}))
+	Expect(isStageEnabled(cr, apiv1.PromtailInstallation)).To(BeTrue())
+	Expect(isStageEnabled(cr, apiv1.Configuration)).To(BeTrue())
+
+	// An external Prometheus comes with its own operator
+	cr.Spec.Components = nil
+	cr.Spec.ExternalPrometheus = &apiv1.ExternalPrometheus{Name: "k8s"}
+	Expect(r.getDisabledStages(cr)).To(Equal([]apiv1.ObservabilityStageName{
+		apiv1.PrometheusConfiguration,
+		apiv1.PrometheusInstallation,
+	}))
+	Expect(isStageEnabled(cr, apiv1.AlertmanagerInstallation)).To(BeTrue())
+}
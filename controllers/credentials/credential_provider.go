@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Types implementing CredentialProvider look up the data of a secret referenced by name in the
+// CR or an index. The data has the same keys as the Kubernetes secret it replaces.
+type CredentialProvider interface {
+	GetCredentials(ctx context.Context, namespace string, name string) (map[string][]byte, error)
+}
+
+// Reads Kubernetes secrets
+type KubernetesCredentialProvider struct {
+	Client client.Client
+}
+
+// Returns the credential provider configured in the CR
+func GetCredentialProvider(cr *v1.Observability, c client.Client) CredentialProvider {
+	switch cr.GetCredentialProviderType() {
+	case v1.CredentialProviderVault:
+		return NewVaultCredentialProvider(cr, c)
+	case v1.CredentialProviderExternalSecrets:
+		return NewExternalSecretsCredentialProvider(cr, c)
+	default:
+		return NewKubernetesCredentialProvider(c)
+	}
+}
+
+func NewKubernetesCredentialProvider(c client.Client) CredentialProvider {
+	return &KubernetesCredentialProvider{Client: c}
+}
+
+func (p *KubernetesCredentialProvider) GetCredentials(ctx context.Context, namespace string, name string) (map[string][]byte, error) {
+	secret := &v12.Secret{}
+	selector := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+
+	err := p.Client.Get(ctx, selector, secret)
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+// Path of a secret in external stores
+func getCredentialsPath(prefix string, namespace string, name string) string {
+	if prefix == "" {
+		return fmt.Sprintf("%v/%v", namespace, name)
+	}
+	return fmt.Sprintf("%v/%v/%v", prefix, namespace, name)
+}
@@ -0,0 +1,263 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCredentialProvider_GetCredentialProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *v1.CredentialProviderSpec
+		expected interface{}
+	}{
+		{name: "defaults to kubernetes", spec: nil, expected: &KubernetesCredentialProvider{}},
+		{name: "vault", spec: &v1.CredentialProviderSpec{Type: v1.CredentialProviderVault}, expected: &VaultCredentialProvider{}},
+		{name: "external secrets", spec: &v1.CredentialProviderSpec{Type: v1.CredentialProviderExternalSecrets}, expected: &ExternalSecretsCredentialProvider{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cr := &v1.Observability{Spec: v1.ObservabilitySpec{CredentialProvider: tt.spec}}
+			g.Expect(GetCredentialProvider(cr, nil)).To(BeAssignableToTypeOf(tt.expected))
+		})
+	}
+}
+
+func TestCredentialProvider_Vault(t *testing.T) {
+	g := NewWithT(t)
+
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/auth/kubernetes/login":
+			logins++
+			body := map[string]string{}
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			if body["jwt"] != "sa-token" || body["role"] != "operator" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"vault-token","lease_duration":3600}}`))
+		case "/v1/secret/data/observability/test/pagerduty":
+			if req.Header.Get("X-Vault-Token") != "vault-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"data":{"PAGERDUTY_KEY":"key"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := &VaultCredentialProvider{
+		Config: &v1.VaultCredentialProvider{
+			Address:    server.URL,
+			Role:       "operator",
+			PathPrefix: "observability",
+		},
+		HttpClient: server.Client(),
+		GetServiceAccountToken: func() ([]byte, error) {
+			return []byte("sa-token"), nil
+		},
+	}
+
+	data, err := provider.GetCredentials(context.TODO(), "test", "pagerduty")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(map[string][]byte{"PAGERDUTY_KEY": []byte("key")}))
+
+	// Cached credentials don't need another login
+	_, err = provider.GetCredentials(context.TODO(), "test", "pagerduty")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(logins).To(Equal(1))
+
+	_, err = provider.GetCredentials(context.TODO(), "test", "missing")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestCredentialProvider_ExternalSecrets(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = v12.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(ExternalSecretGroupVersionKind, &unstructured.Unstructured{})
+
+	cr := &v1.Observability{
+		Spec: v1.ObservabilitySpec{
+			CredentialProvider: &v1.CredentialProviderSpec{
+				Type: v1.CredentialProviderExternalSecrets,
+				ExternalSecrets: &v1.ExternalSecretsCredentialProvider{
+					SecretStoreName: "vault-backend",
+					PathPrefix:      "observability",
+				},
+			},
+		},
+	}
+	c := testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())
+	provider := GetCredentialProvider(cr, c)
+
+	// The secret is not synced yet
+	_, err := provider.GetCredentials(context.TODO(), "test", "smtp")
+	g.Expect(err).To(HaveOccurred())
+
+	externalSecret := &unstructured.Unstructured{}
+	externalSecret.SetGroupVersionKind(ExternalSecretGroupVersionKind)
+	err = c.Get(context.TODO(), client.ObjectKey{Namespace: "test", Name: "smtp"}, externalSecret)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	storeKind, _, _ := unstructured.NestedString(externalSecret.Object, "spec", "secretStoreRef", "kind")
+	g.Expect(storeKind).To(Equal("SecretStore"))
+	dataFrom, _, _ := unstructured.NestedSlice(externalSecret.Object, "spec", "dataFrom")
+	g.Expect(dataFrom).To(HaveLen(1))
+	g.Expect(dataFrom[0]).To(HaveKeyWithValue("extract", HaveKeyWithValue("key", "observability/test/smtp")))
+
+	err = c.Create(context.TODO(), &v12.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "smtp"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	data, err := provider.GetCredentials(context.TODO(), "test", "smtp")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(HaveKeyWithValue("password", []byte("secret")))
+}
+
+func TestCredentialProvider_VaultTLS(t *testing.T) {
+	g := NewWithT(t)
+
+	reads := map[string]int{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/auth/kubernetes/login":
+			body := map[string]string{}
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"auth":{"client_token":"%v","lease_duration":3600}}`, body["role"])))
+		case "/v1/secret/data/test/smtp":
+			reads[req.Header.Get("X-Vault-Token")]++
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"secret"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = v12.AddToScheme(scheme)
+	_ = configv1.AddToScheme(scheme)
+	secret := &v12.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault-ca", Namespace: "observability"},
+		Data:       map[string][]byte{"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})},
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	getProvider := func(role string, caSecret string) CredentialProvider {
+		cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability"}}
+		cr.Spec.CredentialProvider = &v1.CredentialProviderSpec{
+			Type:  v1.CredentialProviderVault,
+			Vault: &v1.VaultCredentialProvider{Address: server.URL, Role: role, CASecret: caSecret},
+		}
+		provider := GetCredentialProvider(cr, c).(*VaultCredentialProvider)
+		provider.GetServiceAccountToken = func() ([]byte, error) {
+			return []byte("sa-token"), nil
+		}
+		return provider
+	}
+
+	// The certificate of Vault is verified
+	_, err := getProvider("operator", "").GetCredentials(context.TODO(), "test", "smtp")
+	g.Expect(err).To(MatchError(ContainSubstring("certificate")))
+
+	data, err := getProvider("operator", "vault-ca").GetCredentials(context.TODO(), "test", "smtp")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(map[string][]byte{"password": []byte("secret")}))
+
+	// Credentials are cached per role
+	_, err = getProvider("operator", "vault-ca").GetCredentials(context.TODO(), "test", "smtp")
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = getProvider("reader", "vault-ca").GetCredentials(context.TODO(), "test", "smtp")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reads).To(Equal(map[string]int{"operator": 1, "reader": 1}))
+}
+
+func TestCredentialProvider_VaultConcurrentReads(t *testing.T) {
+	g := NewWithT(t)
+
+	var mu sync.Mutex
+	logins := 0
+	reads := map[string]int{}
+	slow := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/auth/concurrent/login":
+			mu.Lock()
+			logins++
+			mu.Unlock()
+			// Tokens without a lease are reused
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"vault-token","lease_duration":0}}`))
+		case "/v1/secret/data/concurrent/slow", "/v1/secret/data/concurrent/fast":
+			mu.Lock()
+			reads[req.URL.Path]++
+			mu.Unlock()
+			if req.URL.Path == "/v1/secret/data/concurrent/slow" {
+				<-slow
+			}
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"secret"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := func() *VaultCredentialProvider {
+		return &VaultCredentialProvider{
+			Config:     &v1.VaultCredentialProvider{Address: server.URL, Role: "operator", AuthPath: "concurrent"},
+			HttpClient: server.Client(),
+			GetServiceAccountToken: func() ([]byte, error) {
+				return []byte("sa-token"), nil
+			},
+		}
+	}
+
+	// Concurrent reads of a secret wait for a single request
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := provider().GetCredentials(context.TODO(), "concurrent", "slow")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(data).To(Equal(map[string][]byte{"password": []byte("secret")}))
+		}()
+	}
+
+	// Other secrets are read while a request is pending
+	g.Eventually(func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return reads["/v1/secret/data/concurrent/slow"]
+	}).Should(Equal(1))
+	_, err := provider().GetCredentials(context.TODO(), "concurrent", "fast")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	close(slow)
+	wg.Wait()
+	g.Expect(reads).To(Equal(map[string]int{"/v1/secret/data/concurrent/slow": 1, "/v1/secret/data/concurrent/fast": 1}))
+	g.Expect(logins).To(Equal(1))
+}
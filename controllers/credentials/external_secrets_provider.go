@@ -0,0 +1,102 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	externalSecretsDefaultStoreKind       = "SecretStore"
+	externalSecretsDefaultRefreshInterval = "1h"
+)
+
+var ExternalSecretGroupVersionKind = schema.GroupVersionKind{
+	Group:   "external-secrets.io",
+	Version: "v1beta1",
+	Kind:    "ExternalSecret",
+}
+
+// Creates an ExternalSecret for every requested secret and reads the secret synced by the
+// External Secrets operator
+type ExternalSecretsCredentialProvider struct {
+	Config *v1.ExternalSecretsCredentialProvider
+	Client client.Client
+}
+
+func NewExternalSecretsCredentialProvider(cr *v1.Observability, c client.Client) CredentialProvider {
+	return &ExternalSecretsCredentialProvider{
+		Config: cr.Spec.CredentialProvider.ExternalSecrets,
+		Client: c,
+	}
+}
+
+func (p *ExternalSecretsCredentialProvider) GetCredentials(ctx context.Context, namespace string, name string) (map[string][]byte, error) {
+	if p.Config == nil || p.Config.SecretStoreName == "" {
+		return nil, fmt.Errorf("no secret store configured")
+	}
+
+	err := p.reconcileExternalSecret(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &v12.Secret{}
+	err = p.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret)
+	if errors.IsNotFound(err) {
+		return nil, fmt.Errorf("waiting for external secret %v/%v to be synced", namespace, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+func (p *ExternalSecretsCredentialProvider) reconcileExternalSecret(ctx context.Context, namespace string, name string) error {
+	storeKind := p.Config.SecretStoreKind
+	if storeKind == "" {
+		storeKind = externalSecretsDefaultStoreKind
+	}
+	refreshInterval := p.Config.RefreshInterval
+	if refreshInterval == "" {
+		refreshInterval = externalSecretsDefaultRefreshInterval
+	}
+
+	externalSecret := &unstructured.Unstructured{}
+	externalSecret.SetGroupVersionKind(ExternalSecretGroupVersionKind)
+	externalSecret.SetNamespace(namespace)
+	externalSecret.SetName(name)
+
+	_, err := utils.Apply(ctx, p.Client, externalSecret, func() error {
+		externalSecret.SetLabels(map[string]string{
+			"managed-by": "observability-operator",
+		})
+		externalSecret.Object["spec"] = map[string]interface{}{
+			"refreshInterval": refreshInterval,
+			"secretStoreRef": map[string]interface{}{
+				"name": p.Config.SecretStoreName,
+				"kind": storeKind,
+			},
+			"target": map[string]interface{}{
+				"name":           name,
+				"creationPolicy": "Owner",
+			},
+			"dataFrom": []interface{}{
+				map[string]interface{}{
+					"extract": map[string]interface{}{
+						"key": getCredentialsPath(p.Config.PathPrefix, namespace, name),
+					},
+				},
+			},
+		}
+		return nil
+	})
+	return err
+}
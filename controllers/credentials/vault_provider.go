@@ -0,0 +1,282 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"golang.org/x/sync/singleflight"
+	v12 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Token of the operator service account, used for the Kubernetes auth method
+	ServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// Secrets without a lease are read again after this time, so that rotated credentials are picked up.
+	// Tokens without a lease don't expire and are reused for as long.
+	VaultDefaultRefreshInterval = 5 * time.Minute
+	// Secrets with a shorter lease are still cached for this time, so that Vault isn't read for every use
+	VaultMinimumRefreshInterval = 10 * time.Second
+
+	vaultDefaultAuthPath    = "kubernetes"
+	vaultDefaultSecretsPath = "secret"
+)
+
+type vaultCacheEntry struct {
+	data    map[string][]byte
+	expires time.Time
+}
+
+// Reconcilers are created for every reconcile, the login and the credentials are shared between them.
+// The lock only guards the maps, concurrent requests for the same key wait for a single request to Vault.
+var vaultCache = struct {
+	sync.Mutex
+	requests singleflight.Group
+	tokens   map[string]vaultCacheEntry
+	secrets  map[string]vaultCacheEntry
+}{
+	tokens:  map[string]vaultCacheEntry{},
+	secrets: map[string]vaultCacheEntry{},
+}
+
+// Reads credentials from the KV version 2 secrets engine of Vault
+type VaultCredentialProvider struct {
+	Config *v1.VaultCredentialProvider
+	// Built from the trusted CA bundle, the CA secret and the proxy of the cluster if nil
+	HttpClient *http.Client
+	// Returns the JWT for the Kubernetes auth method
+	GetServiceAccountToken func() ([]byte, error)
+
+	cr     *v1.Observability
+	client client.Client
+}
+
+func NewVaultCredentialProvider(cr *v1.Observability, c client.Client) CredentialProvider {
+	return &VaultCredentialProvider{
+		Config: cr.Spec.CredentialProvider.Vault,
+		GetServiceAccountToken: func() ([]byte, error) {
+			return ioutil.ReadFile(ServiceAccountTokenFile)
+		},
+		cr:     cr,
+		client: c,
+	}
+}
+
+func (p *VaultCredentialProvider) GetCredentials(ctx context.Context, namespace string, name string) (map[string][]byte, error) {
+	if p.Config == nil || p.Config.Address == "" {
+		return nil, errors.New("no vault address configured")
+	}
+
+	secretsPath := p.Config.SecretsPath
+	if secretsPath == "" {
+		secretsPath = vaultDefaultSecretsPath
+	}
+	url := fmt.Sprintf("%v/v1/%v/data/%v", strings.TrimSuffix(p.Config.Address, "/"), secretsPath, getCredentialsPath(p.Config.PathPrefix, namespace, name))
+	// Roles may have access to different secrets
+	key := fmt.Sprintf("%v@%v", p.Config.Role, url)
+
+	if data, ok := getVaultCacheEntry(vaultCache.secrets, key); ok {
+		return data, nil
+	}
+
+	if p.HttpClient == nil {
+		httpClient, err := p.getHttpClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.HttpClient = httpClient
+	}
+
+	data, err, _ := vaultCache.requests.Do("secret:"+key, func() (interface{}, error) {
+		return p.readSecret(ctx, url, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.(map[string][]byte), nil
+}
+
+func (p *VaultCredentialProvider) readSecret(ctx context.Context, url string, key string) (map[string][]byte, error) {
+	token, err := p.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := struct {
+		LeaseDuration int64 `json:"lease_duration"`
+		Data          struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}{}
+	err = p.request(ctx, http.MethodGet, url, token, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{}
+	for key, value := range response.Data.Data {
+		if s, ok := value.(string); ok {
+			data[key] = []byte(s)
+		} else {
+			data[key] = []byte(fmt.Sprintf("%v", value))
+		}
+	}
+
+	refresh := VaultDefaultRefreshInterval
+	if lease := time.Duration(response.LeaseDuration) * time.Second; lease > 0 && lease < refresh {
+		refresh = lease
+	}
+	if refresh < VaultMinimumRefreshInterval {
+		refresh = VaultMinimumRefreshInterval
+	}
+	setVaultCacheEntry(vaultCache.secrets, key, data, refresh)
+	return data, nil
+}
+
+func getVaultCacheEntry(entries map[string]vaultCacheEntry, key string) (map[string][]byte, bool) {
+	vaultCache.Lock()
+	defer vaultCache.Unlock()
+	entry, ok := entries[key]
+	if !ok || !time.Now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+func setVaultCacheEntry(entries map[string]vaultCacheEntry, key string, data map[string][]byte, lifetime time.Duration) {
+	vaultCache.Lock()
+	defer vaultCache.Unlock()
+	entries[key] = vaultCacheEntry{data: data, expires: time.Now().Add(lifetime)}
+}
+
+// Vault is always verified, with the system roots, the trusted CA bundle of the cluster and the CA
+// secret. The requests go through the cluster-wide proxy.
+func (p *VaultCredentialProvider) getHttpClient(ctx context.Context) (*http.Client, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	bundle := model.GetTrustedCABundleConfigMap(p.cr)
+	err = p.client.Get(ctx, client.ObjectKeyFromObject(bundle), bundle)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	pool.AppendCertsFromPEM([]byte(bundle.Data[model.TrustedCABundleKey]))
+
+	if p.Config.CASecret != "" {
+		secret := &v12.Secret{}
+		err = p.client.Get(ctx, client.ObjectKey{Namespace: p.cr.Namespace, Name: p.Config.CASecret}, secret)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching vault ca secret: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(secret.Data["ca.crt"]) {
+			return nil, fmt.Errorf("no certificates found in vault ca secret %v", p.Config.CASecret)
+		}
+	}
+
+	proxy, err := utils.GetClusterProxy(ctx, p.client)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: model.GetTLSConfig(p.cr, pool),
+			Proxy:           model.GetProxyFunc(proxy),
+		},
+	}, nil
+}
+
+// Log in with the Kubernetes auth method, the client token is reused until shortly before it expires
+func (p *VaultCredentialProvider) login(ctx context.Context) (string, error) {
+	authPath := p.Config.AuthPath
+	if authPath == "" {
+		authPath = vaultDefaultAuthPath
+	}
+	url := fmt.Sprintf("%v/v1/auth/%v/login", strings.TrimSuffix(p.Config.Address, "/"), authPath)
+	key := fmt.Sprintf("%v@%v", p.Config.Role, url)
+
+	if entry, ok := getVaultCacheEntry(vaultCache.tokens, key); ok {
+		return string(entry["token"]), nil
+	}
+
+	token, err, _ := vaultCache.requests.Do("token:"+key, func() (interface{}, error) {
+		return p.requestLogin(ctx, url, key)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.(string), nil
+}
+
+func (p *VaultCredentialProvider) requestLogin(ctx context.Context, url string, key string) (string, error) {
+	jwt, err := p.GetServiceAccountToken()
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"role": p.Config.Role,
+		"jwt":  string(jwt),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	response := struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}{}
+	err = p.request(ctx, http.MethodPost, url, "", body, &response)
+	if err != nil {
+		return "", err
+	}
+	if response.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault login with role %v returned no token", p.Config.Role)
+	}
+
+	// Log in again once 80% of the lifetime of the token have passed
+	lifetime := time.Duration(response.Auth.LeaseDuration) * time.Second * 8 / 10
+	if response.Auth.LeaseDuration == 0 {
+		lifetime = VaultDefaultRefreshInterval
+	}
+	setVaultCacheEntry(vaultCache.tokens, key, map[string][]byte{"token": []byte(response.Auth.ClientToken)}, lifetime)
+	return response.Auth.ClientToken, nil
+}
+
+func (p *VaultCredentialProvider) request(ctx context.Context, method string, url string, token string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := p.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code from vault for %v: %v", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
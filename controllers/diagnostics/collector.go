@@ -0,0 +1,292 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	grafanav1alpha1 "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+const (
+	// Lines of the logs of every operator container
+	DefaultLogLines = 1000
+	// Errors of the parts that couldn't be collected
+	errorsFile = "errors.txt"
+)
+
+type Options struct {
+	// Namespace of the Observability CRs, all namespaces if empty
+	Namespace string
+	// Namespace of the operator pods whose logs are collected, no logs if empty
+	OperatorNamespace string
+	LogLines          int64
+}
+
+// Collects the state that support cases need into one archive. Secrets are never collected.
+type Collector struct {
+	client    client.Client
+	clientset kubernetes.Interface
+	files     map[string][]byte
+	errors    []string
+}
+
+func NewCollector(client client.Client, clientset kubernetes.Interface) *Collector {
+	return &Collector{
+		client:    client,
+		clientset: clientset,
+	}
+}
+
+// Write a gzipped tar archive of the CRs with their status, the generated Prometheus, Alertmanager
+// and Grafana specs, the managed config maps, the pod statuses and events of the namespaces and
+// the recent operator logs. Parts that can't be collected are listed in errors.txt.
+func (c *Collector) Collect(ctx context.Context, opts Options, w io.Writer) error {
+	c.files = map[string][]byte{}
+	c.errors = nil
+
+	list := &v1.ObservabilityList{}
+	err := c.client.List(ctx, list, client.InNamespace(opts.Namespace))
+	if err != nil {
+		return err
+	}
+
+	namespaces := map[string]bool{}
+	for i := range list.Items {
+		cr := &list.Items[i]
+		namespaces[cr.Namespace] = true
+		namespaces[cr.GetPrometheusOperatorNamespace()] = true
+		c.collectObservability(cr)
+	}
+	if opts.Namespace != "" {
+		namespaces[opts.Namespace] = true
+	}
+
+	for _, namespace := range sortedNamespaces(namespaces) {
+		c.collectNamespace(ctx, namespace)
+	}
+
+	if opts.OperatorNamespace != "" {
+		c.collectOperatorLogs(ctx, opts.OperatorNamespace, opts.LogLines)
+	}
+
+	if len(c.errors) > 0 {
+		c.files[errorsFile] = []byte(strings.Join(c.errors, "\n") + "\n")
+	}
+	return c.write(w)
+}
+
+// The CR and the parts of its status that describe the index syncs and the remote write health
+func (c *Collector) collectObservability(cr *v1.Observability) {
+	dir := path.Join("observabilities", cr.Namespace, cr.Name)
+	object := cr.DeepCopy()
+	object.ManagedFields = nil
+	c.addYaml(path.Join(dir, "observability.yaml"), object)
+
+	c.addYaml(path.Join(dir, "indexes.yaml"), map[string]interface{}{
+		"lastSynced":           cr.Status.LastSynced,
+		"configRevision":       cr.Status.ConfigRevision,
+		"configRevisionPinned": cr.Status.ConfigRevisionPinned,
+		"indexRollouts":        cr.Status.IndexRollouts,
+		"configurationErrors":  cr.Status.ConfigurationErrors,
+	})
+	c.addYaml(path.Join(dir, "remote-write.yaml"), map[string]interface{}{
+		"remoteWriteProbes": cr.Status.RemoteWriteProbes,
+		"activeGateways":    cr.Status.ActiveGateways,
+		"tokenRefreshers":   cr.Status.TokenRefreshers,
+		"scrapeTargets":     cr.Status.ScrapeTargets,
+	})
+}
+
+func (c *Collector) collectNamespace(ctx context.Context, namespace string) {
+	dir := path.Join("namespaces", namespace)
+	opts := client.InNamespace(namespace)
+
+	prometheuses := &prometheusv1.PrometheusList{}
+	if c.list(ctx, prometheuses, opts) {
+		for _, item := range prometheuses.Items {
+			item.ManagedFields = nil
+			c.addYaml(path.Join(dir, "prometheuses", item.Name+".yaml"), item)
+		}
+	}
+
+	alertmanagers := &prometheusv1.AlertmanagerList{}
+	if c.list(ctx, alertmanagers, opts) {
+		for _, item := range alertmanagers.Items {
+			item.ManagedFields = nil
+			c.addYaml(path.Join(dir, "alertmanagers", item.Name+".yaml"), item)
+		}
+	}
+
+	grafanas := &grafanav1alpha1.GrafanaList{}
+	if c.list(ctx, grafanas, opts) {
+		for _, item := range grafanas.Items {
+			item.ManagedFields = nil
+			c.addYaml(path.Join(dir, "grafanas", item.Name+".yaml"), item)
+		}
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if c.list(ctx, configMaps, opts, client.MatchingLabels{"managed-by": "observability-operator"}) {
+		for _, item := range configMaps.Items {
+			item.ManagedFields = nil
+			c.addYaml(path.Join(dir, "configmaps", item.Name+".yaml"), item)
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if c.list(ctx, pods, opts) {
+		c.addYaml(path.Join(dir, "pods.yaml"), getPodStatuses(pods.Items))
+	}
+
+	events := &corev1.EventList{}
+	if c.list(ctx, events, opts) {
+		sort.Slice(events.Items, func(i, j int) bool {
+			return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+		})
+		var result []string
+		for _, event := range events.Items {
+			result = append(result, fmt.Sprintf("%v %v %v/%v %v: %v", event.LastTimestamp.UTC().Format(time.RFC3339), event.Type,
+				event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Reason, event.Message))
+		}
+		c.files[path.Join(dir, "events.txt")] = []byte(strings.Join(result, "\n") + "\n")
+	}
+}
+
+func (c *Collector) collectOperatorLogs(ctx context.Context, namespace string, lines int64) {
+	if lines <= 0 {
+		lines = DefaultLogLines
+	}
+
+	pods := &corev1.PodList{}
+	if !c.list(ctx, pods, client.InNamespace(namespace), client.MatchingLabelsSelector{
+		Selector: labels.SelectorFromSet(map[string]string{"control-plane": "controller-manager"}),
+	}) {
+		return
+	}
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			name := path.Join("logs", namespace, pod.Name, container.Name+".log")
+			content, err := c.clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+				Container: container.Name,
+				TailLines: &lines,
+			}).DoRaw(ctx)
+			if err != nil {
+				c.addError(name, err)
+				continue
+			}
+			c.files[name] = content
+		}
+	}
+}
+
+// Kinds of operators that are not installed are skipped
+func (c *Collector) list(ctx context.Context, list client.ObjectList, opts ...client.ListOption) bool {
+	err := c.client.List(ctx, list, opts...)
+	if meta.IsNoMatchError(err) {
+		return false
+	}
+	if err != nil {
+		c.addError(fmt.Sprintf("%T", list), err)
+		return false
+	}
+	return true
+}
+
+func (c *Collector) addYaml(name string, object interface{}) {
+	content, err := yaml.Marshal(object)
+	if err != nil {
+		c.addError(name, err)
+		return
+	}
+	c.files[name] = content
+}
+
+func (c *Collector) addError(part string, err error) {
+	c.errors = append(c.errors, fmt.Sprintf("%v: %v", part, err))
+}
+
+func (c *Collector) write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	var names []string
+	for name := range c.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for _, name := range names {
+		content := c.files[name]
+		err := archive.WriteHeader(&tar.Header{
+			Name:    path.Join("must-gather", name),
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: now,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = archive.Write(content)
+		if err != nil {
+			return err
+		}
+	}
+	err := archive.Close()
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+type podStatus struct {
+	Name       string                   `json:"name"`
+	Phase      corev1.PodPhase          `json:"phase"`
+	Node       string                   `json:"node,omitempty"`
+	Conditions []corev1.PodCondition    `json:"conditions,omitempty"`
+	Containers []corev1.ContainerStatus `json:"containers,omitempty"`
+}
+
+// Only the status of the pods, their specs are generated from the collected CRs
+func getPodStatuses(pods []corev1.Pod) []podStatus {
+	var result []podStatus
+	for _, pod := range pods {
+		result = append(result, podStatus{
+			Name:       pod.Name,
+			Phase:      pod.Status.Phase,
+			Node:       pod.Spec.NodeName,
+			Conditions: pod.Status.Conditions,
+			Containers: pod.Status.ContainerStatuses,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func sortedNamespaces(namespaces map[string]bool) []string {
+	var result []string
+	for namespace := range namespaces {
+		result = append(result, namespace)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,78 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func readArchive(content []byte) map[string]string {
+	result := map[string]string{}
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	Expect(err).To(BeNil())
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return result
+		}
+		Expect(err).To(BeNil())
+		data, err := io.ReadAll(archive)
+		Expect(err).To(BeNil())
+		result[header.Name] = string(data)
+	}
+}
+
+func TestCollector_Collect(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	Expect(v1.AddToScheme(scheme)).To(Succeed())
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(prometheusv1.AddToScheme(scheme)).To(Succeed())
+
+	operatorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: "operator", Labels: map[string]string{"control-plane": "controller-manager"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "manager"}}},
+	}
+	client := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1.Observability{
+			ObjectMeta: metav1.ObjectMeta{Name: "observability", Namespace: "obs"},
+			Status:     v1.ObservabilityStatus{RemoteWriteProbes: []v1.RemoteWriteProbeStatus{{Observatorium: "obs", Success: true}}},
+		},
+		&prometheusv1.Prometheus{ObjectMeta: metav1.ObjectMeta{Name: "obs-prometheus", Namespace: "obs"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "prometheus-0", Namespace: "obs"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "obs", Labels: map[string]string{"managed-by": "observability-operator"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "obs"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "obs"}},
+		operatorPod,
+	).Build()
+
+	var out bytes.Buffer
+	err := NewCollector(client, fakeclientset.NewSimpleClientset(operatorPod)).Collect(context.Background(), Options{OperatorNamespace: "operator"}, &out)
+	Expect(err).To(BeNil())
+
+	files := readArchive(out.Bytes())
+	Expect(files).To(HaveKey("must-gather/observabilities/obs/observability/observability.yaml"))
+	Expect(files["must-gather/observabilities/obs/observability/remote-write.yaml"]).To(ContainSubstring("observatorium: obs"))
+	Expect(files).To(HaveKey("must-gather/namespaces/obs/prometheuses/obs-prometheus.yaml"))
+	Expect(files).To(HaveKey("must-gather/namespaces/obs/configmaps/managed.yaml"))
+	Expect(files).NotTo(HaveKey("must-gather/namespaces/obs/configmaps/other.yaml"))
+	Expect(files["must-gather/namespaces/obs/pods.yaml"]).To(ContainSubstring("phase: Running"))
+	Expect(files).To(HaveKey("must-gather/logs/operator/operator/manager.log"))
+	for name := range files {
+		Expect(name).NotTo(ContainSubstring("token"))
+	}
+}
@@ -0,0 +1,66 @@
+package diagnostics
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// First argument of the operator binary that runs the collection instead of the operator
+const MustGatherCommand = "must-gather"
+
+// Collect the diagnostics with the kubeconfig or the service account of the pod, e.g. with
+// oc exec deploy/observability-operator-controller-manager -- /manager must-gather -output - > must-gather.tar.gz
+func RunMustGather(scheme *runtime.Scheme, args []string) error {
+	operatorNamespace, _ := utils.GetOperatorNamespace()
+
+	flags := flag.NewFlagSet(MustGatherCommand, flag.ContinueOnError)
+	namespace := flags.String("namespace", "", "Namespace of the Observability CRs, all namespaces if empty.")
+	flags.StringVar(&operatorNamespace, "operator-namespace", operatorNamespace, "Namespace of the operator pods whose logs are collected.")
+	output := flags.String("output", "must-gather.tar.gz", "Path of the archive, - writes it to stdout.")
+	logLines := flags.Int64("log-lines", DefaultLogLines, "Lines of the logs of every operator container.")
+	timeout := flags.Duration("timeout", 2*time.Minute, "Time the collection may take.")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	return NewCollector(c, clientset).Collect(ctx, Options{
+		Namespace:         *namespace,
+		OperatorNamespace: operatorNamespace,
+		LogLines:          *logLines,
+	}, w)
+}
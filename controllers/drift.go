@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Prometheus and Alertmanager are only watched once the Prometheus installation stage installed
+// their CRDs, a watch for a missing kind would stop the controller
+func (r *ObservabilityReconciler) watchOperandDrift() error {
+	if r.watchingOperands || r.controller == nil {
+		return nil
+	}
+
+	for _, obj := range []client.Object{&prometheusv1.Prometheus{}, &prometheusv1.Alertmanager{}} {
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return err
+		}
+		_, err = r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, obj := range []client.Object{&prometheusv1.Prometheus{}, &prometheusv1.Alertmanager{}} {
+		err := r.controller.Watch(&source.Kind{Type: obj}, handler.EnqueueRequestsFromMapFunc(r.getDriftRequests), getDriftPredicate())
+		if err != nil {
+			return err
+		}
+	}
+	r.watchingOperands = true
+	return nil
+}
+
+// Managed resources that are deleted or changed by someone else are reconciled right away, instead of
+// being repaired by the next resync. Creates are ignored, the cache reports all existing resources
+// as created on startup.
+func getDriftPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isManagedResource(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isManagedResource(e.ObjectOld) && isExternalChange(e.ObjectOld, e.ObjectNew)
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+func isManagedResource(obj client.Object) bool {
+	return obj.GetLabels()["managed-by"] == "observability-operator"
+}
+
+// Changes of the status or metadata only don't change the generation of resources that have one. The
+// last change is the one of the newest managed fields, changes of a status subresource aside.
+func isExternalChange(old client.Object, new client.Object) bool {
+	if new.GetGeneration() != 0 && new.GetGeneration() == old.GetGeneration() {
+		return false
+	}
+
+	var latest *metav1.ManagedFieldsEntry
+	fields := new.GetManagedFields()
+	for i := range fields {
+		if fields[i].Subresource != "" || fields[i].Time == nil {
+			continue
+		}
+		if latest == nil || latest.Time.Before(fields[i].Time) {
+			latest = &fields[i]
+		}
+	}
+	// An entry of the operator with the same time as another one is taken as the last change
+	for i := range fields {
+		if latest != nil && fields[i].Subresource == "" && fields[i].Time.Equal(latest.Time) && utils.IsOperatorFieldManager(fields[i].Manager) {
+			return false
+		}
+	}
+	return latest == nil || !utils.IsOperatorFieldManager(latest.Manager)
+}
+
+// The CR that owns an adopted resource, all CRs for the others, the operator doesn't record which CR
+// created them. The CRs are synced regardless of their resync period, so that the resource is repaired.
+func (r *ObservabilityReconciler) getDriftRequests(obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Kind == "Observability" && owner.APIVersion == apiv1.GroupVersion.String() {
+			requests = []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: obj.GetNamespace(), Name: owner.Name}}}
+			break
+		}
+	}
+	if requests == nil {
+		requests = r.getObservabilityRequests("managed resource change")
+	}
+
+	if r.syncState != nil {
+		for _, request := range requests {
+			r.syncState.RequestSync(request.Namespace, request.Name)
+		}
+	}
+	return requests
+}
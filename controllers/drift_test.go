@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/configuration"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDrift_DriftPredicate(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Now()
+	entry := func(manager string, offset time.Duration, subresource string) metav1.ManagedFieldsEntry {
+		at := metav1.NewTime(now.Add(offset))
+		return metav1.ManagedFieldsEntry{Manager: manager, Time: &at, Subresource: subresource}
+	}
+	secret := func(fields ...metav1.ManagedFieldsEntry) *v1.Secret {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:          "grafana-session",
+			Labels:        map[string]string{"managed-by": "observability-operator"},
+			ManagedFields: fields,
+		}}
+	}
+	updated := func(new *v1.Secret) bool {
+		return getDriftPredicate().Update(event.UpdateEvent{ObjectOld: secret(), ObjectNew: new})
+	}
+
+	// Changes of the operator itself, also with the updates of earlier versions
+	Expect(updated(secret(entry("kubectl", -time.Minute, ""), entry(utils.FieldManager, 0, "")))).To(BeFalse())
+	Expect(updated(secret(entry("manager", 0, "")))).To(BeFalse())
+	Expect(updated(secret(entry("kubectl", 0, ""), entry(utils.FieldManager, 0, "")))).To(BeFalse())
+
+	// Changes of others, status updates aside
+	Expect(updated(secret(entry(utils.FieldManager, -time.Minute, ""), entry("kubectl", 0, "")))).To(BeTrue())
+	Expect(updated(secret(entry("kubectl", -time.Minute, ""), entry(utils.FieldManager, 0, "status")))).To(BeTrue())
+	Expect(updated(secret())).To(BeTrue())
+
+	// Resources that aren't managed and creates
+	unmanaged := secret(entry("kubectl", 0, ""))
+	unmanaged.Labels = nil
+	Expect(getDriftPredicate().Update(event.UpdateEvent{ObjectOld: unmanaged, ObjectNew: unmanaged})).To(BeFalse())
+	Expect(getDriftPredicate().Create(event.CreateEvent{Object: secret()})).To(BeFalse())
+
+	// Deletes of managed resources
+	Expect(getDriftPredicate().Delete(event.DeleteEvent{Object: secret()})).To(BeTrue())
+	Expect(getDriftPredicate().Delete(event.DeleteEvent{Object: unmanaged})).To(BeFalse())
+}
+
+func TestDrift_IsExternalChange(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Resources with a generation only change when their spec changes
+	old := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	new := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	Expect(isExternalChange(old, new)).To(BeFalse())
+	new.Generation = 3
+	Expect(isExternalChange(old, new)).To(BeTrue())
+}
+
+func TestDrift_GetDriftRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	// The owner of an adopted resource is synced regardless of its resync period
+	r := &ObservabilityReconciler{syncState: configuration.NewSyncState()}
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "observability",
+		Name:      "grafana-session",
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: apiv1.GroupVersion.String(), Kind: "Observability", Name: "observability-stack"},
+		},
+	}}
+	Expect(r.getDriftRequests(secret)).To(Equal([]reconcile.Request{
+		{NamespacedName: client.ObjectKey{Namespace: "observability", Name: "observability-stack"}},
+	}))
+}
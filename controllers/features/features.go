@@ -0,0 +1,114 @@
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Feature string
+
+// Subsystems in development ship behind a gate, disabled by default until they are stable
+const (
+	// Thanos sidecar, compactor and querier next to Prometheus
+	Thanos Feature = "thanos"
+	// Prometheus in agent mode, only forwarding with remote write
+	AgentMode Feature = "agentMode"
+	// Traces of the reconciles
+	Tracing Feature = "tracing"
+)
+
+// Default of every feature
+var knownFeatures = map[Feature]bool{
+	Thanos:    false,
+	AgentMode: false,
+	Tracing:   false,
+}
+
+// Features enabled for the operator, set once from the --feature-gates flag on startup
+type Gates struct {
+	mu      sync.RWMutex
+	enabled map[Feature]bool
+}
+
+var defaultGates = &Gates{}
+
+// Parse a comma separated list of feature=bool pairs, e.g. thanos=true,agentMode=false. Unknown
+// features fail, so that typos don't go unnoticed.
+func (g *Gates) Set(value string) error {
+	enabled := map[Feature]bool{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("missing value of feature gate %v, expected %v=true or %v=false", pair, pair, pair)
+		}
+		feature := Feature(strings.TrimSpace(parts[0]))
+		if _, ok := knownFeatures[feature]; !ok {
+			return fmt.Errorf("unknown feature gate %v, known are %v", feature, strings.Join(getKnownFeatures(), ", "))
+		}
+		value, err := strconv.ParseBool(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature gate %v: %w", feature, err)
+		}
+		enabled[feature] = value
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.enabled = enabled
+	return nil
+}
+
+func (g *Gates) Enabled(feature Feature) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if value, ok := g.enabled[feature]; ok {
+		return value
+	}
+	return knownFeatures[feature]
+}
+
+// All known features and whether they are enabled
+func (g *Gates) All() map[Feature]bool {
+	result := map[Feature]bool{}
+	for feature := range knownFeatures {
+		result[feature] = g.Enabled(feature)
+	}
+	return result
+}
+
+// Current value of the flag, the features that were set
+func (g *Gates) String() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var result []string
+	for feature, value := range g.enabled {
+		result = append(result, fmt.Sprintf("%v=%v", feature, value))
+	}
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
+
+// The gates of the operator, also implements flag.Value for --feature-gates
+func DefaultGates() *Gates {
+	return defaultGates
+}
+
+func Enabled(feature Feature) bool {
+	return defaultGates.Enabled(feature)
+}
+
+func getKnownFeatures() []string {
+	var result []string
+	for feature := range knownFeatures {
+		result = append(result, string(feature))
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,41 @@
+package features
+
+import (
+	"flag"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFeatures_Set(t *testing.T) {
+	RegisterTestingT(t)
+
+	gates := &Gates{}
+	Expect(gates.Enabled(Thanos)).To(BeFalse())
+
+	Expect(gates.Set("thanos=true, agentMode=false")).To(Succeed())
+	Expect(gates.Enabled(Thanos)).To(BeTrue())
+	Expect(gates.Enabled(AgentMode)).To(BeFalse())
+	Expect(gates.Enabled(Tracing)).To(BeFalse())
+	Expect(gates.String()).To(Equal("agentMode=false,thanos=true"))
+	Expect(gates.All()).To(Equal(map[Feature]bool{Thanos: true, AgentMode: false, Tracing: false}))
+
+	// Invalid gates keep the previous ones
+	Expect(gates.Set("thanos")).To(MatchError(ContainSubstring("missing value")))
+	Expect(gates.Set("thanos=maybe")).To(MatchError(ContainSubstring("invalid value")))
+	Expect(gates.Set("thanoss=true")).To(MatchError("unknown feature gate thanoss, known are agentMode, thanos, tracing"))
+	Expect(gates.Enabled(Thanos)).To(BeTrue())
+
+	Expect(gates.Set("")).To(Succeed())
+	Expect(gates.Enabled(Thanos)).To(BeFalse())
+}
+
+func TestFeatures_Flag(t *testing.T) {
+	RegisterTestingT(t)
+
+	gates := &Gates{}
+	flags := flag.NewFlagSet("operator", flag.ContinueOnError)
+	flags.Var(gates, "feature-gates", "")
+	Expect(flags.Parse([]string{"--feature-gates=tracing=true"})).To(Succeed())
+	Expect(gates.Enabled(Tracing)).To(BeTrue())
+}
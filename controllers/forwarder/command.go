@@ -0,0 +1,64 @@
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-logr/logr"
+)
+
+const DefaultListenAddress = ":9096"
+
+// Run the forwarder with the config file mounted from the secret of the operator, e.g.
+// /manager alert-forwarder -config-file /etc/alert-forwarder/config.yaml
+func Run(logger logr.Logger, args []string) error {
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	listenAddress := flags.String("listen-address", DefaultListenAddress, "The address the webhook receiver binds to.")
+	configFile := flags.String("config-file", "/etc/alert-forwarder/config.yaml", "Path of the forwarder config.")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(*configFile)
+	if err != nil {
+		return err
+	}
+	config := Config{}
+	err = yaml.Unmarshal(content, &config)
+	if err != nil {
+		return err
+	}
+	forwarder, err := NewForwarder(config, logger)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              *listenAddress,
+		Handler:           forwarder.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdown)
+	}()
+
+	logger.Info("forwarding alerts", "address", *listenAddress, "url", config.Url)
+	err = server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
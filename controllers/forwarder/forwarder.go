@@ -0,0 +1,145 @@
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// First argument of the operator binary that runs the forwarder instead of the operator
+	Command = "alert-forwarder"
+	// Path that the webhook receiver of Alertmanager posts to
+	WebhookPath    = "/webhook"
+	DefaultTimeout = 10 * time.Second
+	// Larger payloads are rejected, Alertmanager batches alerts by group
+	maxPayloadSize = 5 * 1024 * 1024
+)
+
+// Written to a secret by the operator, the token must not end up in the deployment
+type Config struct {
+	// Endpoint that receives the enriched payloads
+	Url string `json:"url"`
+	// Bearer token of the endpoint
+	Token   string          `json:"token,omitempty"`
+	Timeout string          `json:"timeout,omitempty"`
+	Cluster ClusterMetadata `json:"cluster"`
+}
+
+// Added to every payload in the cluster key
+type ClusterMetadata struct {
+	Id         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	ConsoleUrl string `json:"consoleUrl,omitempty"`
+	// Labels of the Observability CR and the cluster labels of its spec
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Receives the webhooks of Alertmanager and forwards them with the cluster metadata
+type Forwarder struct {
+	config Config
+	client *http.Client
+	logger logr.Logger
+}
+
+func NewForwarder(config Config, logger logr.Logger) (*Forwarder, error) {
+	if config.Url == "" {
+		return nil, fmt.Errorf("no url to forward to")
+	}
+	timeout := DefaultTimeout
+	if config.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %v", config.Timeout)
+		}
+	}
+	return &Forwarder{
+		config: config,
+		client: &http.Client{Timeout: timeout},
+		logger: logger,
+	}, nil
+}
+
+func (f *Forwarder) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(WebhookPath, f.handleWebhook)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// Failed forwards return an error status, so that Alertmanager retries the notification
+func (f *Forwarder) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := f.enrich(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		f.logger.Error(err, "invalid webhook payload")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = f.forward(r.Context(), payload)
+	if err != nil {
+		f.logger.Error(err, "error forwarding alerts")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// The payload of Alertmanager is kept as it is, fields of newer versions are passed on
+func (f *Forwarder) enrich(body io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxPayloadSize {
+		return nil, fmt.Errorf("payload exceeds %v bytes", maxPayloadSize)
+	}
+
+	payload := map[string]interface{}{}
+	err = json.Unmarshal(content, &payload)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding payload: %v", err)
+	}
+	if _, ok := payload["alerts"]; !ok {
+		return nil, fmt.Errorf("payload without alerts")
+	}
+	payload["cluster"] = f.config.Cluster
+	return json.Marshal(payload)
+}
+
+func (f *Forwarder) forward(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.config.Url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.config.Token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", f.config.Token))
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %v from %v", resp.StatusCode, f.config.Url)
+	}
+	return nil
+}
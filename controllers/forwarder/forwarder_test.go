@@ -0,0 +1,85 @@
+package forwarder
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+)
+
+const testPayload = `{"version":"4","status":"firing","receiver":"alert-forwarder","alerts":[{"status":"firing","labels":{"alertname":"KubePodCrashLooping"}}]}`
+
+func TestForwarder_Forward(t *testing.T) {
+	RegisterTestingT(t)
+
+	var received map[string]interface{}
+	var authorization string
+	status := http.StatusOK
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		content, _ := io.ReadAll(r.Body)
+		received = nil
+		_ = json.Unmarshal(content, &received)
+		w.WriteHeader(status)
+	}))
+	defer upstream.Close()
+
+	forwarder, err := NewForwarder(Config{
+		Url:   upstream.URL,
+		Token: "secret-token",
+		Cluster: ClusterMetadata{
+			Id:         "cluster-1",
+			ConsoleUrl: "https://console.example.com",
+			Labels:     map[string]string{"environment": "production"},
+		},
+	}, logr.Discard())
+	Expect(err).ToNot(HaveOccurred())
+	server := httptest.NewServer(forwarder.Handler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+WebhookPath, "application/json", strings.NewReader(testPayload))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	Expect(authorization).To(Equal("Bearer secret-token"))
+
+	// The payload is kept and the cluster metadata added
+	Expect(received["version"]).To(Equal("4"))
+	Expect(received["alerts"]).To(HaveLen(1))
+	Expect(received["cluster"]).To(Equal(map[string]interface{}{
+		"id":         "cluster-1",
+		"consoleUrl": "https://console.example.com",
+		"labels":     map[string]interface{}{"environment": "production"},
+	}))
+
+	// Alertmanager retries on errors of the endpoint
+	status = http.StatusServiceUnavailable
+	resp, err = http.Post(server.URL+WebhookPath, "application/json", strings.NewReader(testPayload))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+
+	resp, err = http.Post(server.URL+WebhookPath, "application/json", strings.NewReader(`{"status":"firing"}`))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+	resp, err = http.Get(server.URL + WebhookPath)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+}
+
+func TestForwarder_NewForwarder(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := NewForwarder(Config{}, logr.Discard())
+	Expect(err).To(HaveOccurred())
+	_, err = NewForwarder(Config{Url: "https://events.example.com", Timeout: "ten seconds"}, logr.Discard())
+	Expect(err).To(HaveOccurred())
+
+	forwarder, err := NewForwarder(Config{Url: "https://events.example.com", Timeout: "30s"}, logr.Discard())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(forwarder.client.Timeout.Seconds()).To(Equal(30.0))
+}
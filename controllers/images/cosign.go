@@ -0,0 +1,103 @@
+package images
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Annotation of the signature layers that holds the signature of the payload
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+type signatureManifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// Simple signing payload, the signed claim about the digest
+type signaturePayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// Parses PEM encoded ECDSA public keys, the key type cosign generates
+func ParsePublicKeys(keys []string) ([]*ecdsa.PublicKey, error) {
+	var result []*ecdsa.PublicKey
+	for _, key := range keys {
+		block, _ := pem.Decode([]byte(key))
+		if block == nil {
+			return nil, errors.New("invalid PEM encoded public key")
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, ok := parsed.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errors.New("only ECDSA public keys are supported")
+		}
+		result = append(result, publicKey)
+	}
+	return result, nil
+}
+
+// Verifies that a cosign signature of the digest, stored next to the image with the tag
+// sha256-<digest>.sig, was created by one of the keys
+func (c *RegistryClient) VerifySignature(ctx context.Context, image string, digest string, keys []*ecdsa.PublicKey) error {
+	ref := ParseReference(image)
+	signatureTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+
+	manifest := &signatureManifest{}
+	err := c.GetManifest(ctx, ref, signatureTag, manifest)
+	if err != nil {
+		return fmt.Errorf("no signature found for %v: %v", image, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(signature) == 0 {
+			continue
+		}
+		payload, err := c.GetBlob(ctx, ref, layer.Digest)
+		if err != nil {
+			return err
+		}
+		if verifyPayload(payload, signature, digest, keys) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no valid signature found for %v@%v", image, digest)
+}
+
+// The payload must be signed by one of the keys and name the digest
+func verifyPayload(payload []byte, signature []byte, digest string, keys []*ecdsa.PublicKey) bool {
+	hash := sha256.Sum256(payload)
+	signed := false
+	for _, key := range keys {
+		if ecdsa.VerifyASN1(key, hash[:], signature) {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return false
+	}
+
+	claim := &signaturePayload{}
+	if err := json.Unmarshal(payload, claim); err != nil {
+		return false
+	}
+	return claim.Critical.Image.DockerManifestDigest == digest
+}
@@ -0,0 +1,268 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	dockerHubHost     = "docker.io"
+)
+
+// Manifest types accepted when resolving a tag, indexes are preferred so that the digest
+// is the same on all architectures
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference of an image in a registry
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// Parses an image reference. Images without a registry host are Docker Hub images.
+func ParseReference(image string) Reference {
+	ref := Reference{Registry: dockerHubRegistry, Tag: "latest"}
+
+	if separator := strings.Index(image, "@"); separator >= 0 {
+		ref.Digest = image[separator+1:]
+		image = image[:separator]
+		ref.Tag = ""
+	}
+	if separator := strings.LastIndex(image, ":"); separator > strings.LastIndex(image, "/") {
+		ref.Tag = image[separator+1:]
+		image = image[:separator]
+	}
+
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		image = parts[1]
+	}
+	if ref.Registry == dockerHubHost {
+		ref.Registry = dockerHubRegistry
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(image, "/") {
+		image = "library/" + image
+	}
+	ref.Repository = image
+	return ref
+}
+
+// Login to a registry from a pull secret
+type RegistryCredential struct {
+	Username string
+	Password string
+}
+
+// Reads manifests and blobs from OCI registries, with the credentials of the registry if there are
+// any and anonymous access otherwise
+type RegistryClient struct {
+	HttpClient *http.Client
+	// Credentials by registry host
+	Credentials map[string]RegistryCredential
+	// Defaults to https, tests use plain http
+	Scheme string
+}
+
+// The transport of the client verifies the registries and goes through the proxy
+func NewRegistryClient(transport http.RoundTripper, credentials map[string]RegistryCredential) *RegistryClient {
+	return &RegistryClient{
+		HttpClient:  &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		Credentials: credentials,
+		Scheme:      "https",
+	}
+}
+
+// Adds the registry logins of a .dockerconfigjson pull secret. Docker Hub logins are keyed by the
+// index url, they apply to the Docker Hub registry.
+func ParseDockerConfig(content []byte, credentials map[string]RegistryCredential) error {
+	config := struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}{}
+	err := json.Unmarshal(content, &config)
+	if err != nil {
+		return err
+	}
+
+	for server, auth := range config.Auths {
+		credential := RegistryCredential{Username: auth.Username, Password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return fmt.Errorf("invalid auth of %v", server)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("invalid auth of %v", server)
+			}
+			credential = RegistryCredential{Username: parts[0], Password: parts[1]}
+		}
+
+		host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+		host = strings.SplitN(host, "/", 2)[0]
+		if host == dockerHubHost || host == "index.docker.io" {
+			host = dockerHubRegistry
+		}
+		credentials[host] = credential
+	}
+	return nil
+}
+
+// Returns the digest of the manifest the tag points to
+func (c *RegistryClient) ResolveDigest(ctx context.Context, image string) (string, error) {
+	ref := ParseReference(image)
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	resp, err := c.get(ctx, http.MethodHead, ref, fmt.Sprintf("manifests/%v", ref.Tag), manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("registry returned no digest for %v", image)
+	}
+	return digest, nil
+}
+
+// Returns the manifest with the tag or digest
+func (c *RegistryClient) GetManifest(ctx context.Context, ref Reference, reference string, result interface{}) error {
+	resp, err := c.get(ctx, http.MethodGet, ref, fmt.Sprintf("manifests/%v", reference), manifestMediaTypes)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (c *RegistryClient) GetBlob(ctx context.Context, ref Reference, digest string) ([]byte, error) {
+	resp, err := c.get(ctx, http.MethodGet, ref, fmt.Sprintf("blobs/%v", digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+}
+
+// Registries without anonymous access answer with a challenge, the token for it is requested
+// once and the request repeated. Basic challenges are answered with the credentials of the registry.
+func (c *RegistryClient) get(ctx context.Context, method string, ref Reference, path string, accept []string) (*http.Response, error) {
+	target := fmt.Sprintf("%v://%v/v2/%v/%v", c.Scheme, ref.Registry, ref.Repository, path)
+	credential, hasCredential := c.Credentials[ref.Registry]
+
+	authorization := ""
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		resp, err := c.HttpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized || authorization != "" {
+			return nil, fmt.Errorf("unexpected status code from %v: %v", target, resp.StatusCode)
+		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if strings.HasPrefix(challenge, "Basic ") && hasCredential {
+			req.SetBasicAuth(credential.Username, credential.Password)
+			authorization = req.Header.Get("Authorization")
+			continue
+		}
+		token, err := c.getToken(ctx, challenge, credential, hasCredential)
+		if err != nil {
+			return nil, err
+		}
+		authorization = "Bearer " + token
+	}
+	return nil, fmt.Errorf("unauthorized request to %v", target)
+}
+
+// Requests a token for a bearer challenge, anonymous without credentials, e.g.
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"
+func (c *RegistryClient) getToken(ctx context.Context, challenge string, credential RegistryCredential, hasCredential bool) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication: %v", challenge)
+	}
+
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		parts := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(parts) == 2 {
+			params[parts[0]] = strings.Trim(parts[1], "\"")
+		}
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry challenge without realm: %v", challenge)
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCredential {
+		req.SetBasicAuth(credential.Username, credential.Password)
+	}
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code from %v: %v", params["realm"], resp.StatusCode)
+	}
+
+	response := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return "", err
+	}
+	if response.Token != "" {
+		return response.Token, nil
+	}
+	return response.AccessToken, nil
+}
@@ -0,0 +1,212 @@
+package images
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const testDigest = "sha256:0123456789abcdef"
+
+func TestRegistry_ParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{
+			image: "quay.io/prometheus/prometheus:v2.35.0",
+			want:  Reference{Registry: "quay.io", Repository: "prometheus/prometheus", Tag: "v2.35.0"},
+		},
+		{
+			image: "grafana/grafana",
+			want:  Reference{Registry: "registry-1.docker.io", Repository: "grafana/grafana", Tag: "latest"},
+		},
+		{
+			image: "docker.io/nginx:1.21",
+			want:  Reference{Registry: "registry-1.docker.io", Repository: "library/nginx", Tag: "1.21"},
+		},
+		{
+			image: "localhost:5000/promtail@sha256:abc",
+			want:  Reference{Registry: "localhost:5000", Repository: "promtail", Digest: "sha256:abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ParseReference(tt.image)).To(Equal(tt.want))
+		})
+	}
+}
+
+func getTestRegistry(t *testing.T, key *ecdsa.PrivateKey, signedDigest string) (*RegistryClient, string) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"image":{"docker-manifest-digest":"%v"},"type":"cosign container image signature"}}`, signedDigest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%v/token",service="test",scope="repository:observability/prometheus:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch req.URL.Path {
+		case "/v2/observability/prometheus/manifests/v2.35.0":
+			w.Header().Set("Docker-Content-Digest", testDigest)
+		case "/v2/observability/prometheus/manifests/sha256-0123456789abcdef.sig":
+			manifest := map[string]interface{}{
+				"layers": []map[string]interface{}{
+					{
+						"digest": "sha256:payload",
+						"annotations": map[string]string{
+							cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+						},
+					},
+				},
+			}
+			_ = json.NewEncoder(w).Encode(manifest)
+		case "/v2/observability/prometheus/blobs/sha256:payload":
+			_, _ = w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &RegistryClient{HttpClient: server.Client(), Scheme: "http"}, strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestRegistry_ResolveDigest(t *testing.T) {
+	g := NewWithT(t)
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c, host := getTestRegistry(t, key, testDigest)
+
+	digest, err := c.ResolveDigest(context.TODO(), host+"/observability/prometheus:v2.35.0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(digest).To(Equal(testDigest))
+
+	_, err = c.ResolveDigest(context.TODO(), host+"/observability/prometheus:missing")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRegistry_ParseDockerConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+	content := fmt.Sprintf(`{"auths":{"quay.io":{"auth":"%v"},"https://index.docker.io/v1/":{"username":"user","password":"pass"}}}`, auth)
+	credentials := map[string]RegistryCredential{}
+	g.Expect(ParseDockerConfig([]byte(content), credentials)).To(Succeed())
+	g.Expect(credentials).To(Equal(map[string]RegistryCredential{
+		"quay.io":         {Username: "robot", Password: "secret"},
+		dockerHubRegistry: {Username: "user", Password: "pass"},
+	}))
+
+	g.Expect(ParseDockerConfig([]byte(`{"auths":{"quay.io":{"auth":"invalid"}}}`), credentials)).ToNot(Succeed())
+}
+
+// Private registries issue tokens for the login of the pull secret, or take the login directly
+func TestRegistry_ResolveDigestWithCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	var bearer *httptest.Server
+	bearer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			if username, password, ok := req.BasicAuth(); !ok || username != "robot" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"robot-token"}`))
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer robot-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%v/token",service="test"`, bearer.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", testDigest)
+	}))
+	defer bearer.Close()
+
+	basic := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if username, password, ok := req.BasicAuth(); !ok || username != "robot" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", testDigest)
+	}))
+	defer basic.Close()
+
+	for _, server := range []*httptest.Server{bearer, basic} {
+		host := strings.TrimPrefix(server.URL, "http://")
+		c := NewRegistryClient(server.Client().Transport, map[string]RegistryCredential{host: {Username: "robot", Password: "secret"}})
+		c.Scheme = "http"
+		digest, err := c.ResolveDigest(context.TODO(), host+"/observability/prometheus:v2.35.0")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(digest).To(Equal(testDigest))
+
+		// Without the login the registry can't be read
+		c.Credentials = nil
+		_, err = c.ResolveDigest(context.TODO(), host+"/observability/prometheus:v2.35.0")
+		g.Expect(err).To(HaveOccurred())
+	}
+}
+
+func TestCosign_VerifySignature(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	encode := func(key *ecdsa.PrivateKey) string {
+		der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+
+	tests := []struct {
+		name         string
+		keys         []string
+		signedDigest string
+		valid        bool
+	}{
+		{name: "signed with one of the keys", keys: []string{encode(otherKey), encode(key)}, signedDigest: testDigest, valid: true},
+		{name: "signed with an unknown key", keys: []string{encode(otherKey)}, signedDigest: testDigest, valid: false},
+		{name: "signature of another digest", keys: []string{encode(key)}, signedDigest: "sha256:other", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c, host := getTestRegistry(t, key, tt.signedDigest)
+			keys, err := ParsePublicKeys(tt.keys)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = c.VerifySignature(context.TODO(), host+"/observability/prometheus:v2.35.0", testDigest, keys)
+			if tt.valid {
+				g.Expect(err).ToNot(HaveOccurred())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+			}
+		})
+	}
+}
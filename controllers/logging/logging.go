@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Subsystem of the levels that don't set their own
+const DefaultSubsystem = "default"
+
+// Highest verbosity of the subsystems, the base logger must be created with at least this level
+const MaxLevel = 5
+
+// Verbosity of the subsystems. The levels of the --log-levels flag are overridden by the ones of the
+// operator config, so that they can be raised at runtime without restarting the operator.
+type Levels struct {
+	mu     sync.RWMutex
+	flag   map[string]int
+	config map[string]int
+}
+
+var defaultLevels = &Levels{}
+
+// Parse a comma separated list of subsystem=level pairs, e.g. default=0,configuration=2
+func (l *Levels) Set(value string) error {
+	levels, err := parseLevels(value)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flag = levels
+	return nil
+}
+
+// Levels of the operator config, nil restores the ones of the flag
+func (l *Levels) SetOverrides(levels map[string]int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = levels
+}
+
+// Level of the subsystem, the default level if it has none
+func (l *Levels) Get(subsystem string) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, key := range []string{subsystem, DefaultSubsystem} {
+		if level, ok := l.config[key]; ok {
+			return level
+		}
+		if level, ok := l.flag[key]; ok {
+			return level
+		}
+	}
+	return 0
+}
+
+// Current value of the flag
+func (l *Levels) String() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var result []string
+	for subsystem, level := range l.flag {
+		result = append(result, fmt.Sprintf("%v=%v", subsystem, level))
+	}
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
+
+// The levels of the operator, also implements flag.Value for --log-levels
+func DefaultLevels() *Levels {
+	return defaultLevels
+}
+
+// Logger of a subsystem, named after it. Info messages above the level of the subsystem are dropped,
+// errors are always logged.
+func ForSubsystem(logger logr.Logger, subsystem string) logr.Logger {
+	return WithLevels(logger.WithName(subsystem), subsystem)
+}
+
+// Filter the messages of the logger by the level of the subsystem, without naming it. Loggers of other
+// subsystems derived from the result replace the level, they are not filtered twice.
+func WithLevels(logger logr.Logger, subsystem string) logr.Logger {
+	sink := logger.GetSink()
+	if sink == nil {
+		return logger
+	}
+	if filtered, ok := sink.(*levelSink); ok {
+		return logr.New(&levelSink{sink: filtered.sink, subsystem: subsystem, levels: filtered.levels})
+	}
+
+	// The sink is called through the level sink, one frame deeper
+	if withCallDepth, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = withCallDepth.WithCallDepth(1)
+	}
+	return logr.New(&levelSink{
+		sink:      sink,
+		subsystem: subsystem,
+		levels:    defaultLevels,
+	})
+}
+
+func parseLevels(value string) (map[string]int, error) {
+	levels := map[string]int{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("missing level of subsystem %v, expected %v=<level>", pair, pair)
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid level of subsystem %v, expected 0 to %v", parts[0], MaxLevel)
+		}
+		levels[strings.TrimSpace(parts[0])] = level
+	}
+	return levels, ValidateLevels(levels)
+}
+
+// Levels of the operator config are checked with it before they are set
+func ValidateLevels(levels map[string]int) error {
+	for subsystem, level := range levels {
+		if level < 0 || level > MaxLevel {
+			return fmt.Errorf("invalid level of subsystem %v, expected 0 to %v", subsystem, MaxLevel)
+		}
+	}
+	return nil
+}
+
+// Filters the info messages by the current level of the subsystem
+type levelSink struct {
+	sink      logr.LogSink
+	subsystem string
+	levels    *Levels
+}
+
+// The wrapped sink was initialized by its own logger
+func (s *levelSink) Init(info logr.RuntimeInfo) {
+}
+
+func (s *levelSink) Enabled(level int) bool {
+	return level <= s.levels.Get(s.subsystem) && s.sink.Enabled(level)
+}
+
+func (s *levelSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.sink.Info(level, msg, keysAndValues...)
+}
+
+func (s *levelSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+func (s *levelSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &levelSink{sink: s.sink.WithValues(keysAndValues...), subsystem: s.subsystem, levels: s.levels}
+}
+
+func (s *levelSink) WithName(name string) logr.LogSink {
+	return &levelSink{sink: s.sink.WithName(name), subsystem: s.subsystem, levels: s.levels}
+}
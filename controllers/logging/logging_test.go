@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+)
+
+func newTestLogger(lines *[]string) logr.Logger {
+	return funcr.New(func(prefix, args string) {
+		*lines = append(*lines, prefix+" "+args)
+	}, funcr.Options{Verbosity: MaxLevel})
+}
+
+func TestLevels_Set(t *testing.T) {
+	RegisterTestingT(t)
+
+	levels := &Levels{}
+	Expect(levels.Set("default=1, configuration=3")).To(Succeed())
+	Expect(levels.Get("configuration")).To(Equal(3))
+	Expect(levels.Get("grafana")).To(Equal(1))
+	Expect(levels.String()).To(Equal("configuration=3,default=1"))
+
+	Expect(levels.Set("configuration")).To(MatchError(ContainSubstring("missing level")))
+	Expect(levels.Set("configuration=high")).To(MatchError(ContainSubstring("invalid level")))
+	Expect(levels.Set("configuration=6")).To(MatchError(ContainSubstring("invalid level")))
+	Expect(levels.Get("configuration")).To(Equal(3))
+
+	Expect(levels.Set("")).To(Succeed())
+	Expect(levels.Get("configuration")).To(Equal(0))
+}
+
+func TestLevels_Overrides(t *testing.T) {
+	RegisterTestingT(t)
+
+	levels := &Levels{}
+	Expect(levels.Set("default=1,configuration=2")).To(Succeed())
+
+	levels.SetOverrides(map[string]int{"configuration": 4})
+	Expect(levels.Get("configuration")).To(Equal(4))
+	Expect(levels.Get("grafana")).To(Equal(1))
+
+	levels.SetOverrides(map[string]int{"default": 3})
+	Expect(levels.Get("configuration")).To(Equal(2))
+	Expect(levels.Get("grafana")).To(Equal(3))
+
+	levels.SetOverrides(nil)
+	Expect(levels.Get("configuration")).To(Equal(2))
+	Expect(levels.Get("grafana")).To(Equal(1))
+}
+
+func TestLogging_ForSubsystem(t *testing.T) {
+	RegisterTestingT(t)
+	defer DefaultLevels().SetOverrides(nil)
+
+	var lines []string
+	logger := ForSubsystem(newTestLogger(&lines), "configuration").WithValues("observability", "test")
+
+	DefaultLevels().SetOverrides(map[string]int{"configuration": 1})
+	logger.Info("synced")
+	logger.V(1).Info("applied")
+	logger.V(2).Info("unchanged")
+	logger.V(2).Error(errors.New("failed"), "fetch error")
+
+	Expect(lines).To(HaveLen(3))
+	Expect(lines[0]).To(ContainSubstring(`configuration`))
+	Expect(lines[0]).To(ContainSubstring(`"observability"="test"`))
+	Expect(lines[1]).To(ContainSubstring(`"msg"="applied"`))
+	Expect(lines[2]).To(ContainSubstring(`"msg"="fetch error"`))
+
+	// Raised at runtime without recreating the logger
+	DefaultLevels().SetOverrides(map[string]int{"configuration": 2})
+	logger.V(2).Info("unchanged")
+	Expect(lines).To(HaveLen(4))
+}
+
+func TestLogging_WithLevelsReplacesSubsystem(t *testing.T) {
+	RegisterTestingT(t)
+	defer DefaultLevels().SetOverrides(nil)
+
+	var lines []string
+	logger := WithLevels(newTestLogger(&lines), "observability")
+	configuration := ForSubsystem(logger, "configuration")
+
+	DefaultLevels().SetOverrides(map[string]int{"configuration": 2})
+	logger.V(1).Info("dropped")
+	configuration.V(2).Info("logged")
+
+	Expect(lines).To(HaveLen(1))
+	Expect(lines[0]).To(ContainSubstring(`"msg"="logged"`))
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	OperationCreated = "created"
+	OperationUpdated = "updated"
+	OperationDeleted = "deleted"
+)
+
+// InstrumentedClient counts successful writes of managed resources
+type InstrumentedClient struct {
+	client.Client
+}
+
+func NewInstrumentedClient(c client.Client) client.Client {
+	return &InstrumentedClient{
+		Client: c,
+	}
+}
+
+func (c *InstrumentedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	if err == nil {
+		c.count(OperationCreated, obj)
+	}
+	return err
+}
+
+func (c *InstrumentedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	if err == nil {
+		c.count(OperationUpdated, obj)
+	}
+	return err
+}
+
+func (c *InstrumentedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	if err == nil {
+		c.count(OperationUpdated, obj)
+	}
+	return err
+}
+
+func (c *InstrumentedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	if err == nil {
+		c.count(OperationDeleted, obj)
+	}
+	return err
+}
+
+func (c *InstrumentedClient) count(operation string, obj client.Object) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	IncreaseResourceOperationsMetric(operation, kind)
+}
@@ -1,14 +1,37 @@
 package metrics
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/features"
+	"github.com/redhat-developer/observability-operator/v4/controllers/synthetic"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 const (
 	LabelStage             = "stage"
 	LabelConfigurationSync = "configuration_sync"
+	LabelFetchType         = "type"
+	LabelOperation         = "operation"
+	LabelKind              = "kind"
+	LabelName              = "name"
+	LabelNamespace         = "namespace"
+	LabelType              = "type"
+	LabelStep              = "step"
+	LabelLimit             = "limit"
+	LabelHost              = "host"
+	LabelFeature           = "feature"
+	LabelObservatorium     = "observatorium"
+	LabelMonitor           = "monitor"
+	LabelHealth            = "health"
+)
+
+const (
+	FetchTypeIndex    = "index"
+	FetchTypeResource = "resource"
 )
 
 var reconciliationsLabels = []string{
@@ -49,6 +72,114 @@ var failedConfigurationSyncsMetric = prometheus.NewCounter(
 	},
 )
 
+var reconciliationDurationMetric = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:      "reconciler_duration_seconds",
+		Subsystem: "observability_operator",
+		Help:      "Duration of a single reconciliation per stage",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	},
+	reconciliationsLabels,
+)
+
+var fetchDurationMetric = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:      "fetch_duration_seconds",
+		Subsystem: "observability_operator",
+		Help:      "Duration of fetching repository indexes and resources",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	},
+	[]string{LabelFetchType},
+)
+
+var failedFetchesMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:      "fetch_failure_count",
+		Subsystem: "observability_operator",
+		Help:      "Number of failed repository index and resource fetches",
+	},
+	[]string{LabelFetchType},
+)
+
+var notModifiedFetchesMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:      "fetch_not_modified_count",
+		Subsystem: "observability_operator",
+		Help:      "Number of repository index and resource fetches answered with Not Modified",
+	},
+	[]string{LabelFetchType},
+)
+
+var rejectedFetchesMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:      "fetch_rejected_count",
+		Subsystem: "observability_operator",
+		Help:      "Number of fetches that failed right away because the circuit of the host was open",
+	},
+	[]string{LabelHost},
+)
+
+var fetchCircuitOpenMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "fetch_circuit_open",
+		Subsystem: "observability_operator",
+		Help:      "1 once the requests to a host failed too often in a row, until a request succeeds again",
+	},
+	[]string{LabelHost},
+)
+
+var featureEnabledMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "feature_enabled",
+		Subsystem: "observability_operator",
+		Help:      "1 if the feature gate is enabled",
+	},
+	[]string{LabelFeature},
+)
+
+var resourceOperationsMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name:      "resource_operations_total_count",
+		Subsystem: "observability_operator",
+		Help:      "Number of managed resources created, updated or deleted",
+	},
+	[]string{LabelOperation, LabelKind},
+)
+
+var remoteWriteTargetsMetric = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name:      "remote_write_targets",
+		Subsystem: "observability_operator",
+		Help:      "Number of remote write targets configured in Prometheus",
+	},
+)
+
+var generationMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "cr_generation",
+		Subsystem: "observability_operator",
+		Help:      "Generation of the Observability CR",
+	},
+	[]string{LabelNamespace, LabelName},
+)
+
+var observedGenerationMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "cr_observed_generation",
+		Subsystem: "observability_operator",
+		Help:      "Generation of the Observability CR that was last fully reconciled",
+	},
+	[]string{LabelNamespace, LabelName},
+)
+
+var leaderMetric = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name:      "leader",
+		Subsystem: "observability_operator",
+		Help:      "1 if this replica holds the leader lease and reconciles, 0 if it is on standby",
+	},
+)
+
 func IncreaseTotalReconciliationsMetric(stage apiv1.ObservabilityStageName) {
 	labels := prometheus.Labels{
 		LabelStage: string(stage),
@@ -71,9 +202,386 @@ func IncreaseFailedConfigurationSyncsMetric() {
 	failedConfigurationSyncsMetric.Inc()
 }
 
+func ObserveReconciliationDurationMetric(stage apiv1.ObservabilityStageName, duration time.Duration) {
+	labels := prometheus.Labels{
+		LabelStage: string(stage),
+	}
+	reconciliationDurationMetric.With(labels).Observe(duration.Seconds())
+}
+
+func ObserveFetchDurationMetric(fetchType string, duration time.Duration) {
+	labels := prometheus.Labels{
+		LabelFetchType: fetchType,
+	}
+	fetchDurationMetric.With(labels).Observe(duration.Seconds())
+}
+
+func IncreaseFailedFetchesMetric(fetchType string) {
+	labels := prometheus.Labels{
+		LabelFetchType: fetchType,
+	}
+	failedFetchesMetric.With(labels).Inc()
+}
+
+func IncreaseNotModifiedFetchesMetric(fetchType string) {
+	labels := prometheus.Labels{
+		LabelFetchType: fetchType,
+	}
+	notModifiedFetchesMetric.With(labels).Inc()
+}
+
+func IncreaseRejectedFetchesMetric(host string) {
+	labels := prometheus.Labels{
+		LabelHost: host,
+	}
+	rejectedFetchesMetric.With(labels).Inc()
+}
+
+func SetFetchCircuitOpenMetric(host string, open bool) {
+	labels := prometheus.Labels{
+		LabelHost: host,
+	}
+	if open {
+		fetchCircuitOpenMetric.With(labels).Set(1)
+	} else {
+		fetchCircuitOpenMetric.With(labels).Set(0)
+	}
+}
+
+func IncreaseResourceOperationsMetric(operation string, kind string) {
+	labels := prometheus.Labels{
+		LabelOperation: operation,
+		LabelKind:      kind,
+	}
+	resourceOperationsMetric.With(labels).Inc()
+}
+
+func SetRemoteWriteTargetsMetric(count int) {
+	remoteWriteTargetsMetric.Set(float64(count))
+}
+
+func SetGenerationMetrics(cr *apiv1.Observability, status *apiv1.ObservabilityStatus) {
+	labels := prometheus.Labels{
+		LabelNamespace: cr.Namespace,
+		LabelName:      cr.Name,
+	}
+	generationMetric.With(labels).Set(float64(cr.Generation))
+	observedGenerationMetric.With(labels).Set(float64(status.ObservedGeneration))
+}
+
+func SetFeatureMetrics(gates map[features.Feature]bool) {
+	for feature, enabled := range gates {
+		labels := prometheus.Labels{
+			LabelFeature: string(feature),
+		}
+		if enabled {
+			featureEnabledMetric.With(labels).Set(1)
+		} else {
+			featureEnabledMetric.With(labels).Set(0)
+		}
+	}
+}
+
+func SetLeaderMetric(leader bool) {
+	if leader {
+		leaderMetric.Set(1)
+	} else {
+		leaderMetric.Set(0)
+	}
+}
+
+var syntheticCheckLabels = []string{
+	LabelNamespace,
+	LabelName,
+	LabelType,
+}
+
+var syntheticCheckStepLabels = []string{
+	LabelNamespace,
+	LabelName,
+	LabelStep,
+}
+
+var syntheticCheckSuccessMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "success",
+		Subsystem: "synthetic_check",
+		Help:      "1 if the last run of the synthetic check succeeded",
+	},
+	syntheticCheckLabels,
+)
+
+var syntheticCheckDurationMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "duration_seconds",
+		Subsystem: "synthetic_check",
+		Help:      "Duration of the last run of the synthetic check",
+	},
+	syntheticCheckLabels,
+)
+
+var syntheticCheckStepSuccessMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "step_success",
+		Subsystem: "synthetic_check",
+		Help:      "1 if the step of the HTTP check succeeded in the last run",
+	},
+	syntheticCheckStepLabels,
+)
+
+var syntheticCheckStepDurationMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "step_duration_seconds",
+		Subsystem: "synthetic_check",
+		Help:      "Duration of the step of the HTTP check in the last run",
+	},
+	syntheticCheckStepLabels,
+)
+
+var syntheticCheckCertificateExpiryMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "certificate_expiry_timestamp_seconds",
+		Subsystem: "synthetic_check",
+		Help:      "Expiry of the certificate checked by the synthetic check",
+	},
+	syntheticCheckLabels,
+)
+
+var certificateExpiryMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "observability_cert_expiry_timestamp_seconds",
+		Help: "Expiry of the earliest expiring certificate of a managed secret, route or CA bundle",
+	},
+	[]string{
+		LabelKind,
+		LabelNamespace,
+		LabelName,
+	},
+)
+
+var scrapeBudgetUsageMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "scrape_budget_usage_ratio",
+		Subsystem: "observability_operator",
+		Help:      "Observed scrape usage of a namespace relative to its budget, above 1 the budget is exceeded",
+	},
+	[]string{
+		LabelNamespace,
+		LabelLimit,
+	},
+)
+
+// Exports the current time while the remote write probe is enabled, every scrape writes a new sample
+type remoteWriteProbeCollector struct {
+	enabled atomic.Bool
+	desc    *prometheus.Desc
+}
+
+func (c *remoteWriteProbeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *remoteWriteProbeCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.enabled.Load() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(time.Now().Unix()))
+	}
+}
+
+var remoteWriteProbeSampleMetric = &remoteWriteProbeCollector{
+	desc: prometheus.NewDesc(
+		"observability_operator_remote_write_probe_timestamp_seconds",
+		"Time of the scrape, read back from Observatorium to verify the remote write path",
+		nil,
+		nil,
+	),
+}
+
+var remoteWriteProbeSuccessMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "remote_write_probe_success",
+		Subsystem: "observability_operator",
+		Help:      "1 if the latest probe sample arrived in the Observatorium instance within the SLA",
+	},
+	[]string{
+		LabelObservatorium,
+	},
+)
+
+var remoteWriteProbeLagMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "remote_write_probe_lag_seconds",
+		Subsystem: "observability_operator",
+		Help:      "Age of the latest probe sample in the Observatorium instance when it was last verified",
+	},
+	[]string{
+		LabelObservatorium,
+	},
+)
+
+var scrapeTargetsMetric = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name:      "scrape_targets",
+		Subsystem: "observability_operator",
+		Help:      "Active targets of the managed Prometheus by monitor and health",
+	},
+	[]string{
+		LabelMonitor,
+		LabelNamespace,
+		LabelHealth,
+	},
+)
+
 func init() {
 	metrics.Registry.MustRegister(totalReconciliationsMetric)
 	metrics.Registry.MustRegister(failedReconciliationsMetric)
 	metrics.Registry.MustRegister(successfulConfigurationSyncsMetric)
 	metrics.Registry.MustRegister(failedConfigurationSyncsMetric)
+	metrics.Registry.MustRegister(reconciliationDurationMetric)
+	metrics.Registry.MustRegister(fetchDurationMetric)
+	metrics.Registry.MustRegister(failedFetchesMetric)
+	metrics.Registry.MustRegister(notModifiedFetchesMetric)
+	metrics.Registry.MustRegister(rejectedFetchesMetric)
+	metrics.Registry.MustRegister(fetchCircuitOpenMetric)
+	metrics.Registry.MustRegister(featureEnabledMetric)
+	metrics.Registry.MustRegister(resourceOperationsMetric)
+	metrics.Registry.MustRegister(remoteWriteTargetsMetric)
+	metrics.Registry.MustRegister(generationMetric)
+	metrics.Registry.MustRegister(observedGenerationMetric)
+	metrics.Registry.MustRegister(leaderMetric)
+	metrics.Registry.MustRegister(syntheticCheckSuccessMetric)
+	metrics.Registry.MustRegister(syntheticCheckDurationMetric)
+	metrics.Registry.MustRegister(syntheticCheckStepSuccessMetric)
+	metrics.Registry.MustRegister(syntheticCheckStepDurationMetric)
+	metrics.Registry.MustRegister(syntheticCheckCertificateExpiryMetric)
+	metrics.Registry.MustRegister(certificateExpiryMetric)
+	metrics.Registry.MustRegister(scrapeBudgetUsageMetric)
+	metrics.Registry.MustRegister(remoteWriteProbeSampleMetric)
+	metrics.Registry.MustRegister(remoteWriteProbeSuccessMetric)
+	metrics.Registry.MustRegister(remoteWriteProbeLagMetric)
+	metrics.Registry.MustRegister(scrapeTargetsMetric)
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Replaces the series of the previous run, steps of a changed sequence don't linger
+func SetSyntheticCheckMetrics(check *apiv1.SyntheticCheck, result synthetic.Result) {
+	DeleteSyntheticCheckMetrics(check.Namespace, check.Name)
+
+	labels := prometheus.Labels{
+		LabelNamespace: check.Namespace,
+		LabelName:      check.Name,
+		LabelType:      check.GetType(),
+	}
+	syntheticCheckSuccessMetric.With(labels).Set(boolToFloat(result.Success))
+	syntheticCheckDurationMetric.With(labels).Set(result.Duration.Seconds())
+	if !result.CertificateExpiry.IsZero() {
+		syntheticCheckCertificateExpiryMetric.With(labels).Set(float64(result.CertificateExpiry.Unix()))
+	}
+
+	for _, step := range result.Steps {
+		stepLabels := prometheus.Labels{
+			LabelNamespace: check.Namespace,
+			LabelName:      check.Name,
+			LabelStep:      step.Name,
+		}
+		syntheticCheckStepSuccessMetric.With(stepLabels).Set(boolToFloat(step.Success))
+		syntheticCheckStepDurationMetric.With(stepLabels).Set(step.Duration.Seconds())
+	}
+}
+
+func DeleteSyntheticCheckMetrics(namespace string, name string) {
+	labels := prometheus.Labels{
+		LabelNamespace: namespace,
+		LabelName:      name,
+	}
+	for _, metric := range []*prometheus.GaugeVec{
+		syntheticCheckSuccessMetric,
+		syntheticCheckDurationMetric,
+		syntheticCheckStepSuccessMetric,
+		syntheticCheckStepDurationMetric,
+		syntheticCheckCertificateExpiryMetric,
+	} {
+		metric.DeletePartialMatch(labels)
+	}
+}
+
+// Replaces all series, certificates that are no longer in use don't linger
+func SetCertificateExpiryMetrics(expiries []apiv1.CertificateExpiry) {
+	certificateExpiryMetric.Reset()
+	for _, expiry := range expiries {
+		labels := prometheus.Labels{
+			LabelKind:      expiry.Kind,
+			LabelNamespace: expiry.Namespace,
+			LabelName:      expiry.Name,
+		}
+		certificateExpiryMetric.With(labels).Set(float64(expiry.Expires))
+	}
+}
+
+// Observed usage of a budgeted namespace
+type ScrapeBudgetUsage struct {
+	Namespace string
+	Limit     string
+	Budget    uint64
+	Observed  float64
+}
+
+// Replaces all series, budgets that were removed don't linger
+func SetScrapeBudgetMetrics(usage []ScrapeBudgetUsage) {
+	scrapeBudgetUsageMetric.Reset()
+	for _, u := range usage {
+		labels := prometheus.Labels{
+			LabelNamespace: u.Namespace,
+			LabelLimit:     u.Limit,
+		}
+		scrapeBudgetUsageMetric.With(labels).Set(u.Observed / float64(u.Budget))
+	}
+}
+
+func SetRemoteWriteProbeEnabled(enabled bool) {
+	remoteWriteProbeSampleMetric.enabled.Store(enabled)
+}
+
+// Replaces all series, instances that are no longer probed don't linger. Instances without a
+// sample have no lag.
+func SetRemoteWriteProbeMetrics(probes []apiv1.RemoteWriteProbeStatus) {
+	remoteWriteProbeSuccessMetric.Reset()
+	remoteWriteProbeLagMetric.Reset()
+	for _, probe := range probes {
+		labels := prometheus.Labels{
+			LabelObservatorium: probe.Observatorium,
+		}
+		remoteWriteProbeSuccessMetric.With(labels).Set(boolToFloat(probe.Success))
+		if probe.LastSample > 0 {
+			remoteWriteProbeLagMetric.With(labels).Set(float64(probe.LastChecked - probe.LastSample))
+		}
+	}
+}
+
+// Active targets of a service or pod monitor or of a job
+type ScrapeTargetCount struct {
+	Monitor   string
+	Namespace string
+	Up        int
+	Down      int
+}
+
+// Replaces all series, monitors that were removed don't linger
+func SetScrapeTargetMetrics(counts []ScrapeTargetCount) {
+	scrapeTargetsMetric.Reset()
+	for _, count := range counts {
+		for health, value := range map[string]int{"up": count.Up, "down": count.Down} {
+			labels := prometheus.Labels{
+				LabelMonitor:   count.Monitor,
+				LabelNamespace: count.Namespace,
+				LabelHealth:    health,
+			}
+			scrapeTargetsMetric.With(labels).Set(float64(value))
+		}
+	}
 }
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const defaultAggregationOperation = "sum"
+
+var aggregationOperations = []string{"sum", "min", "max", "avg", "count"}
+
+// Recording rules of the aggregations of all indexes
+func GetAggregationRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-aggregations",
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+func getAggregationOperation(aggregation *v1.Aggregation) string {
+	if aggregation.Operation == "" {
+		return defaultAggregationOperation
+	}
+	return aggregation.Operation
+}
+
+func ValidateAggregation(aggregation *v1.Aggregation) error {
+	if !commonmodel.IsValidMetricName(commonmodel.LabelValue(aggregation.Metric)) {
+		return fmt.Errorf("invalid metric name %v", aggregation.Metric)
+	}
+	for _, label := range aggregation.By {
+		if !commonmodel.LabelName(label).IsValid() {
+			return fmt.Errorf("invalid label name %v in aggregation of %v", label, aggregation.Metric)
+		}
+	}
+
+	operation := getAggregationOperation(aggregation)
+	valid := false
+	for _, existing := range aggregationOperations {
+		if operation == existing {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid operation %v in aggregation of %v", operation, aggregation.Metric)
+	}
+
+	if aggregation.Rate != "" {
+		if _, err := commonmodel.ParseDuration(aggregation.Rate); err != nil {
+			return fmt.Errorf("invalid rate window %v in aggregation of %v", aggregation.Rate, aggregation.Metric)
+		}
+	}
+	return nil
+}
+
+// Follows the level:metric:operations convention of recording rules, e.g. namespace:http_requests_total:sum_rate5m
+func GetAggregationRecordName(aggregation *v1.Aggregation) string {
+	level := "cluster"
+	if len(aggregation.By) > 0 {
+		level = strings.Join(aggregation.By, "_")
+	}
+
+	operations := getAggregationOperation(aggregation)
+	if aggregation.Rate != "" {
+		operations = fmt.Sprintf("%v_rate%v", operations, aggregation.Rate)
+	}
+	return fmt.Sprintf("%v:%v:%v", level, aggregation.Metric, operations)
+}
+
+func GetAggregationExpr(aggregation *v1.Aggregation) string {
+	series := aggregation.Metric
+	if aggregation.Rate != "" {
+		series = fmt.Sprintf("rate(%v[%v])", aggregation.Metric, aggregation.Rate)
+	}
+
+	by := ""
+	if len(aggregation.By) > 0 {
+		by = fmt.Sprintf(" by (%v)", strings.Join(aggregation.By, ", "))
+	}
+	return fmt.Sprintf("%v%v (%v)", getAggregationOperation(aggregation), by, series)
+}
+
+// One group per index, only valid aggregations are expected
+func GetAggregationRuleGroup(indexId string, aggregations []v1.Aggregation) prometheusv1.RuleGroup {
+	group := prometheusv1.RuleGroup{
+		Name: fmt.Sprintf("%v-aggregations", indexId),
+	}
+	for i := range aggregations {
+		group.Rules = append(group.Rules, prometheusv1.Rule{
+			Record: GetAggregationRecordName(&aggregations[i]),
+			Expr:   intstr.FromString(GetAggregationExpr(&aggregations[i])),
+		})
+	}
+	return group
+}
+
+// Drops the series of the aggregated metrics from remote write, the aggregates have different names
+func GetAggregationRelabelConfigs(aggregations []v1.Aggregation) []prometheusv1.RelabelConfig {
+	var metrics []string
+	seen := map[string]bool{}
+	for _, aggregation := range aggregations {
+		if !seen[aggregation.Metric] {
+			metrics = append(metrics, regexp.QuoteMeta(aggregation.Metric))
+			seen[aggregation.Metric] = true
+		}
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	sort.Strings(metrics)
+	return []prometheusv1.RelabelConfig{
+		{
+			SourceLabels: []prometheusv1.LabelName{"__name__"},
+			Regex:        strings.Join(metrics, "|"),
+			Action:       "drop",
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestAggregationResources_GetAggregationRuleGroup(t *testing.T) {
+	type args struct {
+		indexId      string
+		aggregations []v1.Aggregation
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want monitoringv1.RuleGroup
+	}{
+		{
+			name: "rate of a counter by labels and default operation",
+			args: args{
+				indexId: "kafka",
+				aggregations: []v1.Aggregation{
+					{
+						Metric: "http_requests_total",
+						By:     []string{"namespace", "code"},
+						Rate:   "5m",
+					},
+					{
+						Metric:    "kafka_log_size",
+						Operation: "max",
+					},
+				},
+			},
+			want: monitoringv1.RuleGroup{
+				Name: "kafka-aggregations",
+				Rules: []monitoringv1.Rule{
+					{
+						Record: "namespace_code:http_requests_total:sum_rate5m",
+						Expr:   intstr.FromString("sum by (namespace, code) (rate(http_requests_total[5m]))"),
+					},
+					{
+						Record: "cluster:kafka_log_size:max",
+						Expr:   intstr.FromString("max (kafka_log_size)"),
+					},
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetAggregationRuleGroup(tt.args.indexId, tt.args.aggregations)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestAggregationResources_ValidateAggregation(t *testing.T) {
+	tests := []struct {
+		name        string
+		aggregation v1.Aggregation
+		wantErr     bool
+	}{
+		{
+			name:        "valid aggregation",
+			aggregation: v1.Aggregation{Metric: "up", By: []string{"job"}, Operation: "count", Rate: "5m"},
+			wantErr:     false,
+		},
+		{
+			name:        "invalid metric name",
+			aggregation: v1.Aggregation{Metric: "http-requests"},
+			wantErr:     true,
+		},
+		{
+			name:        "unknown operation",
+			aggregation: v1.Aggregation{Metric: "up", Operation: "topk"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid rate window",
+			aggregation: v1.Aggregation{Metric: "up", Rate: "five minutes"},
+			wantErr:     true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAggregation(&tt.aggregation)
+			Expect(err != nil).To(Equal(tt.wantErr))
+		})
+	}
+}
+
+func TestAggregationResources_GetAggregationRelabelConfigs(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetAggregationRelabelConfigs(nil)).To(BeNil())
+	Expect(GetAggregationRelabelConfigs([]v1.Aggregation{
+		{Metric: "http_requests_total", By: []string{"namespace"}},
+		{Metric: "http_requests_total", By: []string{"code"}},
+		{Metric: "kafka_log_size"},
+	})).To(Equal([]monitoringv1.RelabelConfig{
+		{
+			SourceLabels: []monitoringv1.LabelName{"__name__"},
+			Regex:        "http_requests_total|kafka_log_size",
+			Action:       "drop",
+		},
+	}))
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/forwarder"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v15 "k8s.io/api/networking/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	AlertForwarderName      = "alert-forwarder"
+	AlertForwarderPort      = 9096
+	AlertForwarderConfigKey = "config.yaml"
+	alertForwarderConfigDir = "/etc/alert-forwarder"
+)
+
+func getAlertForwarderObjectMeta(cr *v1.Observability) v12.ObjectMeta {
+	return v12.ObjectMeta{
+		Name:      AlertForwarderName,
+		Namespace: cr.GetPrometheusOperatorNamespace(),
+		Labels: map[string]string{
+			"app.kubernetes.io/component": "alert-forwarder",
+			"app.kubernetes.io/name":      AlertForwarderName,
+			"managed-by":                  "observability-operator",
+		},
+	}
+}
+
+// The forwarder is a command of the operator binary, it runs the image of the operator unless overridden
+func GetAlertForwarderImage(cr *v1.Observability, operatorImage string) string {
+	repository, tag := SplitImageTag(operatorImage)
+	return GetImage(cr, v1.ImageAlertForwarder, repository, tag)
+}
+
+func GetAlertForwarderSecret(cr *v1.Observability) *v14.Secret {
+	return &v14.Secret{
+		ObjectMeta: getAlertForwarderObjectMeta(cr),
+	}
+}
+
+func GetAlertForwarderDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: getAlertForwarderObjectMeta(cr),
+	}
+}
+
+func GetAlertForwarderService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: getAlertForwarderObjectMeta(cr),
+	}
+}
+
+func GetAlertForwarderNetworkPolicy(cr *v1.Observability) *v15.NetworkPolicy {
+	return &v15.NetworkPolicy{
+		ObjectMeta: getAlertForwarderObjectMeta(cr),
+	}
+}
+
+// Url of the webhook receiver in the Alertmanager config
+func GetAlertForwarderWebhookUrl(cr *v1.Observability) string {
+	return fmt.Sprintf("http://%v.%v.svc:%v%v", AlertForwarderName, cr.GetPrometheusOperatorNamespace(), AlertForwarderPort, forwarder.WebhookPath)
+}
+
+// Labels of the CR, overridden by its cluster labels
+func GetAlertForwarderConfig(cr *v1.Observability, token string, consoleUrl string) forwarder.Config {
+	labels := map[string]string{}
+	for key, value := range cr.Labels {
+		labels[key] = value
+	}
+	for key, value := range cr.Spec.ClusterLabels {
+		labels[key] = value
+	}
+	if len(labels) == 0 {
+		labels = nil
+	}
+
+	return forwarder.Config{
+		Url:     cr.Spec.AlertForwarder.Url,
+		Token:   token,
+		Timeout: cr.Spec.AlertForwarder.Timeout,
+		Cluster: forwarder.ClusterMetadata{
+			Id:         cr.Status.ClusterID,
+			Name:       cr.Name,
+			Namespace:  cr.Namespace,
+			ConsoleUrl: consoleUrl,
+			Labels:     labels,
+		},
+	}
+}
+
+func GetAlertForwarderContainer(cr *v1.Observability, operatorImage string, proxyEnv []v14.EnvVar) v14.Container {
+	return v14.Container{
+		Name:    AlertForwarderName,
+		Image:   GetAlertForwarderImage(cr, operatorImage),
+		Command: []string{"/manager"},
+		Args: []string{
+			forwarder.Command,
+			fmt.Sprintf("-listen-address=:%v", AlertForwarderPort),
+			fmt.Sprintf("-config-file=%v/%v", alertForwarderConfigDir, AlertForwarderConfigKey),
+		},
+		Env: proxyEnv,
+		Ports: []v14.ContainerPort{
+			{
+				Name:          "http",
+				ContainerPort: AlertForwarderPort,
+			},
+		},
+		ReadinessProbe: &v14.Probe{
+			ProbeHandler: v14.ProbeHandler{
+				HTTPGet: &v14.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromInt(AlertForwarderPort),
+				},
+			},
+		},
+		VolumeMounts: []v14.VolumeMount{
+			{
+				Name:      AlertForwarderName,
+				MountPath: alertForwarderConfigDir,
+				ReadOnly:  true,
+			},
+		},
+		SecurityContext: GetContainerSecurityContext(cr),
+	}
+}
+
+func GetAlertForwarderVolumes(cr *v1.Observability) []v14.Volume {
+	return []v14.Volume{
+		{
+			Name: AlertForwarderName,
+			VolumeSource: v14.VolumeSource{
+				Secret: &v14.SecretVolumeSource{
+					SecretName: GetAlertForwarderSecret(cr).Name,
+				},
+			},
+		},
+	}
+}
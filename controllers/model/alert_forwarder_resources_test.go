@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestAlertForwarderResources_GetAlertForwarderConfig(t *testing.T) {
+	RegisterTestingT(t)
+	tr := true
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Name = "observability-stack"
+		obsCR.Labels = map[string]string{"environment": "dev", "team": "platform"}
+		obsCR.Spec.ClusterLabels = map[string]string{"environment": "production"}
+		obsCR.Spec.AlertForwarder = &v1.AlertForwarderSpec{
+			Enabled: &tr,
+			Url:     "https://events.example.com/alerts",
+			Timeout: "5s",
+		}
+		obsCR.Status.ClusterID = "cluster-1"
+	})
+	Expect(cr.AlertForwarderEnabled()).To(BeTrue())
+
+	config := GetAlertForwarderConfig(cr, "secret-token", "https://console.example.com")
+	Expect(config.Url).To(Equal("https://events.example.com/alerts"))
+	Expect(config.Token).To(Equal("secret-token"))
+	Expect(config.Timeout).To(Equal("5s"))
+	Expect(config.Cluster.Id).To(Equal("cluster-1"))
+	Expect(config.Cluster.Name).To(Equal("observability-stack"))
+	Expect(config.Cluster.ConsoleUrl).To(Equal("https://console.example.com"))
+	// Cluster labels take precedence over the labels of the CR
+	Expect(config.Cluster.Labels).To(Equal(map[string]string{"environment": "production", "team": "platform"}))
+
+	Expect(GetAlertForwarderWebhookUrl(cr)).To(Equal("http://alert-forwarder." + testNamespace + ".svc:9096/webhook"))
+
+	// The token is only mounted from the secret
+	container := GetAlertForwarderContainer(cr, "quay.io/rhoas/observability-operator:v4.2.0", nil)
+	Expect(container.Image).To(Equal("quay.io/rhoas/observability-operator:v4.2.0"))
+	Expect(container.Args).To(ContainElement("alert-forwarder"))
+	Expect(container.Env).To(BeEmpty())
+
+	cr.Spec.ImageOverrides = map[v1.ImageComponent]string{v1.ImageAlertForwarder: "registry.example.com/forwarder:v1"}
+	Expect(GetAlertForwarderImage(cr, "")).To(Equal("registry.example.com/forwarder:v1"))
+
+	cr.Spec.Components = &v1.Components{Alertmanager: &v1.ComponentToggle{Enabled: new(bool)}}
+	Expect(cr.AlertForwarderEnabled()).To(BeFalse())
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"sort"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	kv1 "k8s.io/api/core/v1"
+)
+
+const NodeArchitectureLabel = "kubernetes.io/arch"
+
+var allArchitectures = []string{"amd64", "arm64", "ppc64le", "s390x"}
+
+// Architectures of the default images, by the constants in use. Images without a multi-arch manifest only run on amd64,
+// arm64 clusters need arch image overrides for them.
+var defaultImageArchitectures = map[string][]string{
+	PrometheusBaseImage:        allArchitectures,
+	BlackboxExporterImage:      allArchitectures,
+	GrafanaBaseImage:           allArchitectures,
+	OAuthProxyImage:            {"amd64"},
+	OAuthProxyFIPSImage:        allArchitectures,
+	PromtailImage:              {"amd64"},
+	DefaultTokenRefresherImage: {"amd64"},
+	KubeRBACProxyImage:         allArchitectures,
+	PromLabelProxyImage:        allArchitectures,
+	KubeStateMetricsImage:      allArchitectures,
+	NodeExporterImage:          allArchitectures,
+	EventExporterImage:         {"amd64"},
+	ThanosImage:                {"amd64", "arm64"},
+}
+
+// Architectures the default image of the component runs on, nil if unknown. Overrides are
+// expected to match the nodes.
+func GetImageArchitectures(cr *v1.Observability, component v1.ImageComponent, repository string) []string {
+	if getImageOverride(cr, component, cr.Spec.Architecture) != "" {
+		return nil
+	}
+	return defaultImageArchitectures[repository]
+}
+
+// Architectures with an override for the component
+func GetOverriddenArchitectures(cr *v1.Observability, component v1.ImageComponent) []string {
+	var result []string
+	for architecture, overrides := range cr.Spec.ArchImageOverrides {
+		if overrides[component] != "" {
+			result = append(result, architecture)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Restricts the pods to the architecture of the CR, or else to the architectures supported by
+// all of their images. The affinity of the CR is kept.
+func GetArchitectureAffinity(cr *v1.Observability, affinity *kv1.Affinity, architectures ...[]string) *kv1.Affinity {
+	supported := []string{cr.Spec.Architecture}
+	if cr.Spec.Architecture == "" {
+		supported = intersectArchitectures(architectures)
+	}
+	if len(supported) == 0 {
+		return affinity
+	}
+	return AddNodeSelectorRequirement(affinity, kv1.NodeSelectorRequirement{
+		Key:      NodeArchitectureLabel,
+		Operator: kv1.NodeSelectorOpIn,
+		Values:   supported,
+	})
+}
+
+// Adds the requirement to all node selector terms, the terms are ORed
+func AddNodeSelectorRequirement(affinity *kv1.Affinity, requirement kv1.NodeSelectorRequirement) *kv1.Affinity {
+	if affinity == nil {
+		affinity = &kv1.Affinity{}
+	} else {
+		affinity = affinity.DeepCopy()
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &kv1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &kv1.NodeSelector{}
+	}
+
+	selector := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = []kv1.NodeSelectorTerm{{}}
+	}
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+	return affinity
+}
+
+// Unknown architectures don't restrict, nil if nothing is restricted or nothing is left
+func intersectArchitectures(architectures [][]string) []string {
+	var result []string
+	restricted := false
+	for _, set := range architectures {
+		if set == nil {
+			continue
+		}
+		if !restricted {
+			result = append([]string{}, set...)
+			restricted = true
+			continue
+		}
+		var next []string
+		for _, architecture := range result {
+			for _, candidate := range set {
+				if architecture == candidate {
+					next = append(next, architecture)
+				}
+			}
+		}
+		result = next
+	}
+	sort.Strings(result)
+	return result
+}
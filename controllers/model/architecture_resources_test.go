@@ -0,0 +1,95 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestArchitectureResources_GetArchitectureAffinity(t *testing.T) {
+	buildAffinity := func(architectures ...string) *corev1.Affinity {
+		return &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{
+									Key:      NodeArchitectureLabel,
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   architectures,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		cr            *v1.Observability
+		architectures [][]string
+		want          *corev1.Affinity
+	}{
+		{
+			name:          "restricted to the architectures of all images",
+			cr:            buildObservabilityCR(nil),
+			architectures: [][]string{allArchitectures, {"amd64"}},
+			want:          buildAffinity("amd64"),
+		},
+		{
+			name:          "unknown architectures don't restrict",
+			cr:            buildObservabilityCR(nil),
+			architectures: [][]string{nil, allArchitectures},
+			want:          buildAffinity(allArchitectures...),
+		},
+		{
+			name:          "no affinity without known architectures",
+			cr:            buildObservabilityCR(nil),
+			architectures: [][]string{nil},
+			want:          nil,
+		},
+		{
+			name: "architecture of the CR",
+			cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+				obsCR.Spec.Architecture = "arm64"
+			}),
+			architectures: [][]string{allArchitectures},
+			want:          buildAffinity("arm64"),
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Expect(GetArchitectureAffinity(tt.cr, nil, tt.architectures...)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestArchitectureResources_GetImageForArchitecture(t *testing.T) {
+	RegisterTestingT(t)
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.ImageOverrides = map[v1.ImageComponent]string{v1.ImagePromtail: "mirror.local/promtail:v1"}
+		obsCR.Spec.ArchImageOverrides = map[string]map[v1.ImageComponent]string{
+			"arm64": {v1.ImagePromtail: "mirror.local/promtail-arm64:v1"},
+		}
+	})
+
+	Expect(GetImageForArchitecture(cr, v1.ImagePromtail, "", PromtailImage, PromtailImageTag)).To(Equal("mirror.local/promtail:v1"))
+	Expect(GetImageForArchitecture(cr, v1.ImagePromtail, "arm64", PromtailImage, PromtailImageTag)).To(Equal("mirror.local/promtail-arm64:v1"))
+	Expect(GetOverriddenArchitectures(cr, v1.ImagePromtail)).To(Equal([]string{"arm64"}))
+	Expect(GetImageArchitectures(cr, v1.ImagePromtail, PromtailImage)).To(BeNil())
+
+	// The default daemonset skips the nodes of architectures with their own daemonset
+	affinity := GetPromtailArchitectureAffinity(cr, "")
+	Expect(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions).To(ContainElement(corev1.NodeSelectorRequirement{
+		Key:      NodeArchitectureLabel,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   []string{"arm64"},
+	}))
+}
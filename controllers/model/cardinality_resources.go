@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	CardinalityReportKey = "report.yaml"
+	// Scrapes the head series of the managed Prometheus for the growth alert
+	CardinalityScrapeJobName = "cardinality-prometheus"
+
+	defaultCardinalityInterval      = time.Hour
+	defaultCardinalityTopK          = 10
+	defaultCardinalityGrowthPercent = 50
+)
+
+// Holds the metrics with the most series per namespace
+func GetCardinalityReportConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-cardinality",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+func GetCardinalityGrowthRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-cardinality-growth",
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// Invalid intervals fall back to the default
+func GetCardinalityInterval(cr *v1.Observability) time.Duration {
+	if cr.Spec.CardinalityAnalysis != nil && cr.Spec.CardinalityAnalysis.Interval != "" {
+		interval, err := commonmodel.ParseDuration(cr.Spec.CardinalityAnalysis.Interval)
+		if err == nil && interval > 0 {
+			return time.Duration(interval)
+		}
+	}
+	return defaultCardinalityInterval
+}
+
+func GetCardinalityTopK(cr *v1.Observability) int {
+	if cr.Spec.CardinalityAnalysis != nil && cr.Spec.CardinalityAnalysis.TopK > 0 {
+		return cr.Spec.CardinalityAnalysis.TopK
+	}
+	return defaultCardinalityTopK
+}
+
+func GetCardinalityGrowthAlertPercent(cr *v1.Observability) int {
+	if cr.Spec.CardinalityAnalysis != nil && cr.Spec.CardinalityAnalysis.GrowthAlertPercent > 0 {
+		return cr.Spec.CardinalityAnalysis.GrowthAlertPercent
+	}
+	return defaultCardinalityGrowthPercent
+}
+
+// Prometheus scrapes its own head series, all other metrics of the server are dropped. The certificate
+// of the web TLS endpoint is not issued for localhost, so it isn't verified.
+func GetCardinalityScrapeConfig(cr *v1.Observability) []byte {
+	tlsConfig := ""
+	if cr.PrometheusWebTLSEnabled() {
+		tlsConfig = `
+  tls_config:
+    insecure_skip_verify: true`
+	}
+	return []byte(fmt.Sprintf(`
+- job_name: %v
+  scheme: %v%v
+  static_configs:
+    - targets: [ 'localhost:9090' ]
+  metric_relabel_configs:
+    - source_labels: [ __name__ ]
+      regex: prometheus_tsdb_head_series
+      action: keep
+`, CardinalityScrapeJobName, GetPrometheusScheme(cr), tlsConfig))
+}
+
+// Fires when the head series of the managed Prometheus grew by more than the configured percentage within an hour
+func GetCardinalityGrowthRuleGroup(cr *v1.Observability) prometheusv1.RuleGroup {
+	series := fmt.Sprintf("prometheus_tsdb_head_series{job=\"%v\"}", CardinalityScrapeJobName)
+	report := GetCardinalityReportConfigMap(cr)
+	return prometheusv1.RuleGroup{
+		Name: "cardinality",
+		Rules: []prometheusv1.Rule{
+			{
+				Alert: "PrometheusCardinalityGrowth",
+				Expr:  intstr.FromString(fmt.Sprintf("((%[1]v - %[1]v offset 1h) / %[1]v offset 1h) * 100 > %[2]v", series, GetCardinalityGrowthAlertPercent(cr))),
+				For:   "15m",
+				Labels: map[string]string{
+					"severity": "warning",
+				},
+				Annotations: map[string]string{
+					"summary":     "Number of series in Prometheus grows rapidly",
+					"description": fmt.Sprintf("The head series grew by {{ $value | humanize }}%% within an hour. The metrics with the most series per namespace are reported in the %v/%v ConfigMap.", report.Namespace, report.Name),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,331 @@
+package model
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	kv1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	CertificateKindServingCert = "serving-cert"
+	CertificateKindRoute       = "route"
+	CertificateKindIngress     = "ingress"
+	CertificateKindProxyCA     = "proxy-ca"
+
+	// Reported in the status when it expires within this period
+	CertificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+	proxyCANamespace = "openshift-config"
+	proxyCAKey       = "ca-bundle.crt"
+
+	// Annotation of the services whose serving certificate is issued by the service CA
+	ServingCertSecretAnnotation = "service.alpha.openshift.io/serving-cert-secret-name"
+
+	// Key of the CA in the secrets of cert-manager
+	ServingCertificateCAKey = "ca.crt"
+
+	certManagerDefaultIssuerKind  = "Issuer"
+	certManagerDefaultIssuerGroup = "cert-manager.io"
+)
+
+var CertManagerCertificateGroupVersionKind = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// Serving certificate of the service of a component, written to the secret
+type ServingCertificate struct {
+	Namespace  string
+	Service    string
+	SecretName string
+}
+
+// A secret or config map with PEM encoded certificates under the key
+type CertificateSource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Key       string
+}
+
+// Serving certificates of the oauth proxies on OpenShift or of cert-manager, the web TLS certificate of
+// Prometheus and the certificates of the ingresses
+func GetCertificateSecrets(cr *v1.Observability) []CertificateSource {
+	var result []CertificateSource
+	if !cr.IsKubernetesCluster() || cr.CertManagerEnabled() {
+		for _, secret := range []CertificateSource{
+			{Namespace: cr.GetPrometheusOperatorNamespace(), Name: "prometheus-k8s-tls"},
+			{Namespace: cr.GetPrometheusOperatorNamespace(), Name: "alertmanager-k8s-tls"},
+			{Namespace: cr.Namespace, Name: GrafanaTLSSecretName},
+		} {
+			secret.Kind = CertificateKindServingCert
+			secret.Key = "tls.crt"
+			result = append(result, secret)
+		}
+	}
+
+	if cr.PrometheusWebTLSEnabled() && cr.Spec.PrometheusWebTLS.SecretName != "" {
+		result = append(result, CertificateSource{
+			Kind:      CertificateKindServingCert,
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+			Name:      cr.Spec.PrometheusWebTLS.SecretName,
+			Key:       "tls.crt",
+		})
+	}
+
+	if cr.IngressEnabled() {
+		endpoints := []struct {
+			endpoint  *v1.IngressEndpoint
+			namespace string
+		}{
+			{cr.Spec.Ingress.Prometheus, cr.GetPrometheusOperatorNamespace()},
+			{cr.Spec.Ingress.Alertmanager, cr.GetPrometheusOperatorNamespace()},
+			{cr.Spec.Ingress.Grafana, cr.Namespace},
+		}
+		for _, endpoint := range endpoints {
+			if endpoint.endpoint != nil && endpoint.endpoint.TLSSecretName != "" {
+				result = append(result, CertificateSource{
+					Kind:      CertificateKindIngress,
+					Namespace: endpoint.namespace,
+					Name:      endpoint.endpoint.TLSSecretName,
+					Key:       "tls.crt",
+				})
+			}
+		}
+	}
+	return result
+}
+
+// Additional CA bundle of the cluster proxy, the system roots of the injected bundle are not tracked
+func GetProxyCABundle(proxy *configv1.Proxy) *CertificateSource {
+	if proxy == nil || proxy.Spec.TrustedCA.Name == "" {
+		return nil
+	}
+	return &CertificateSource{
+		Kind:      CertificateKindProxyCA,
+		Namespace: proxyCANamespace,
+		Name:      proxy.Spec.TrustedCA.Name,
+		Key:       proxyCAKey,
+	}
+}
+
+// Routes of the managed components, their certificates are only set when they don't use the
+// default certificate of the router
+func GetCertificateRoutes(cr *v1.Observability) []*routev1.Route {
+	if cr.IsKubernetesCluster() {
+		return nil
+	}
+	return []*routev1.Route{
+		GetPrometheusRoute(cr),
+		GetAlertmanagerRoute(cr),
+		GetPrometheusTenancyRoute(cr),
+		{
+			ObjectMeta: v12.ObjectMeta{
+				Name:      GrafanaRouteName,
+				Namespace: cr.Namespace,
+			},
+		},
+	}
+}
+
+// Earliest expiry of the PEM encoded certificates
+func GetCertificateExpiry(data []byte) (time.Time, error) {
+	var expiry time.Time
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if expiry.IsZero() || certificate.NotAfter.Before(expiry) {
+			expiry = certificate.NotAfter
+		}
+	}
+	if expiry.IsZero() {
+		return time.Time{}, errors.New("no certificates found")
+	}
+	return expiry, nil
+}
+
+func GetCertificateExpiryRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-certificate-expiry",
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// The expiries are exported by the operator and scraped with the operator job
+func GetCertificateExpiryRuleGroup() prometheusv1.RuleGroup {
+	series := fmt.Sprintf(`observability_cert_expiry_timestamp_seconds{job="%v"}`, OperatorScrapeJobName)
+	alert := func(name string, days int, severity string) prometheusv1.Rule {
+		return prometheusv1.Rule{
+			Alert: name,
+			Expr:  intstr.FromString(fmt.Sprintf("%v - time() < %v * 86400", series, days)),
+			For:   "1h",
+			Labels: map[string]string{
+				"severity": severity,
+			},
+			Annotations: map[string]string{
+				"summary":     "Managed certificate expires soon",
+				"description": fmt.Sprintf("The {{ $labels.kind }} certificate {{ $labels.namespace }}/{{ $labels.name }} expires within %v days.", days),
+			},
+		}
+	}
+	return prometheusv1.RuleGroup{
+		Name: "certificate-expiry",
+		Rules: []prometheusv1.Rule{
+			alert("ManagedCertificateExpiringSoon", 14, "warning"),
+			alert("ManagedCertificateExpiring", 3, "critical"),
+		},
+	}
+}
+
+// Serving certificates issued by cert-manager. The route of Grafana is created by the Grafana operator
+// and can't trust another CA than the service CA, so Grafana keeps its certificate on OpenShift.
+func GetServingCertificates(cr *v1.Observability) []ServingCertificate {
+	var result []ServingCertificate
+	if cr.PrometheusEnabled() {
+		result = append(result, ServingCertificate{
+			Namespace:  cr.GetPrometheusOperatorNamespace(),
+			Service:    GetPrometheusService(cr).Name,
+			SecretName: GetPrometheusTLSSecret(cr).Name,
+		})
+	}
+	if cr.AlertmanagerEnabled() {
+		result = append(result, ServingCertificate{
+			Namespace:  cr.GetPrometheusOperatorNamespace(),
+			Service:    GetAlertmanagerService(cr).Name,
+			SecretName: GetAlertmanagerTLSSecret(cr).Name,
+		})
+	}
+	if cr.GrafanaEnabled() && cr.IsKubernetesCluster() {
+		result = append(result, ServingCertificate{
+			Namespace:  cr.Namespace,
+			Service:    GrafanaServiceName,
+			SecretName: GrafanaTLSSecretName,
+		})
+	}
+	return result
+}
+
+// Certificates are named after their secret
+func GetCertManagerCertificate(certificate ServingCertificate) *unstructured.Unstructured {
+	result := &unstructured.Unstructured{}
+	result.SetGroupVersionKind(CertManagerCertificateGroupVersionKind)
+	result.SetNamespace(certificate.Namespace)
+	result.SetName(certificate.SecretName)
+	return result
+}
+
+// Issued for the names of the service within the cluster
+func GetCertManagerCertificateSpec(cr *v1.Observability, certificate ServingCertificate) map[string]interface{} {
+	config := cr.Spec.CertManager
+	kind := config.IssuerRef.Kind
+	if kind == "" {
+		kind = certManagerDefaultIssuerKind
+	}
+	group := config.IssuerRef.Group
+	if group == "" {
+		group = certManagerDefaultIssuerGroup
+	}
+
+	host := fmt.Sprintf("%v.%v.svc", certificate.Service, certificate.Namespace)
+	spec := map[string]interface{}{
+		"secretName": certificate.SecretName,
+		"commonName": host,
+		"dnsNames": []interface{}{
+			host,
+			fmt.Sprintf("%v.cluster.local", host),
+		},
+		"usages": []interface{}{"server auth", "digital signature", "key encipherment"},
+		"issuerRef": map[string]interface{}{
+			"name":  config.IssuerRef.Name,
+			"kind":  kind,
+			"group": group,
+		},
+	}
+	if config.Duration != "" {
+		spec["duration"] = config.Duration
+	}
+	if config.RenewBefore != "" {
+		spec["renewBefore"] = config.RenewBefore
+	}
+	return spec
+}
+
+// Without the oauth proxies on Kubernetes Alertmanager and Grafana serve the certificates of cert-manager
+// themselves, unless the mesh secures their traffic
+func IsCertManagerServedDirectly(cr *v1.Observability) bool {
+	return cr.IsKubernetesCluster() && cr.CertManagerEnabled() && !cr.ServiceMeshAuthenticationEnabled()
+}
+
+// Path of a key of the serving certificate secret, mounted by the Grafana operator
+func GetGrafanaTLSFile(key string) string {
+	return fmt.Sprintf("%v/%v/%v", grafanaSecretsPath, GrafanaTLSSecretName, key)
+}
+
+// Web TLS of Alertmanager, nil unless it serves the certificate of cert-manager itself
+func GetAlertmanagerWebSpec(cr *v1.Observability) *prometheusv1.AlertmanagerWebSpec {
+	if !IsCertManagerServedDirectly(cr) {
+		return nil
+	}
+	secretName := GetAlertmanagerTLSSecret(cr).Name
+	return &prometheusv1.AlertmanagerWebSpec{
+		TLSConfig: &prometheusv1.WebTLSConfig{
+			KeySecret: kv1.SecretKeySelector{
+				LocalObjectReference: kv1.LocalObjectReference{Name: secretName},
+				Key:                  "tls.key",
+			},
+			Cert: prometheusv1.SecretOrConfigMap{
+				Secret: &kv1.SecretKeySelector{
+					LocalObjectReference: kv1.LocalObjectReference{Name: secretName},
+					Key:                  "tls.crt",
+				},
+			},
+		},
+	}
+}
+
+// cert-manager writes the CA of the issuer next to the certificate
+func GetServingCertificateCA(secretName string) prometheusv1.SecretOrConfigMap {
+	return prometheusv1.SecretOrConfigMap{
+		Secret: &kv1.SecretKeySelector{
+			LocalObjectReference: kv1.LocalObjectReference{Name: secretName},
+			Key:                  ServingCertificateCAKey,
+		},
+	}
+}
+
+// The service CA issues the certificate of the service unless cert-manager does
+func GetServingCertAnnotations(cr *v1.Observability, secretName string) map[string]string {
+	for _, certificate := range GetServingCertificates(cr) {
+		if cr.CertManagerEnabled() && certificate.SecretName == secretName {
+			return nil
+		}
+	}
+	return map[string]string{
+		ServingCertSecretAnnotation: secretName,
+	}
+}
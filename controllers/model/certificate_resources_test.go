@@ -0,0 +1,159 @@
+package model
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func buildCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCertificateResources_GetCertificateExpiry(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Now().Truncate(time.Second).UTC()
+	first := now.Add(24 * time.Hour)
+	second := now.Add(48 * time.Hour)
+
+	// The earliest certificate of a chain expires it
+	expiry, err := GetCertificateExpiry(append(buildCertificate(t, second), buildCertificate(t, first)...))
+	Expect(err).To(BeNil())
+	Expect(expiry).To(Equal(first))
+
+	_, err = GetCertificateExpiry([]byte("not a certificate"))
+	Expect(err).NotTo(BeNil())
+}
+
+func TestCertificateResources_GetCertificateSecrets(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	Expect(GetCertificateSecrets(cr)).To(HaveLen(3))
+	Expect(GetCertificateRoutes(cr)).To(HaveLen(4))
+
+	cr = buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.ClusterType = v1.ClusterTypeKubernetes
+		obsCR.Spec.Ingress = &v1.IngressSpec{
+			Prometheus: &v1.IngressEndpoint{Host: "prometheus.example.com", TLSSecretName: "prometheus-tls"},
+			Grafana:    &v1.IngressEndpoint{Host: "grafana.example.com"},
+		}
+	})
+	Expect(GetCertificateSecrets(cr)).To(Equal([]CertificateSource{{
+		Kind:      CertificateKindIngress,
+		Namespace: cr.GetPrometheusOperatorNamespace(),
+		Name:      "prometheus-tls",
+		Key:       "tls.crt",
+	}}))
+	Expect(GetCertificateRoutes(cr)).To(BeEmpty())
+
+	// The web TLS certificate of Prometheus, unless it is the one of the service CA
+	enabled := true
+	cr.Spec.Ingress = nil
+	cr.Spec.PrometheusWebTLS = &v1.PrometheusWebTLS{Enabled: &enabled, SecretName: "prometheus-serving-cert"}
+	Expect(GetCertificateSecrets(cr)).To(Equal([]CertificateSource{{
+		Kind:      CertificateKindServingCert,
+		Namespace: cr.GetPrometheusOperatorNamespace(),
+		Name:      "prometheus-serving-cert",
+		Key:       "tls.crt",
+	}}))
+}
+
+func TestCertificateResources_GetProxyCABundle(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetProxyCABundle(nil)).To(BeNil())
+	Expect(GetProxyCABundle(&configv1.Proxy{})).To(BeNil())
+
+	proxy := &configv1.Proxy{}
+	proxy.Spec.TrustedCA.Name = "user-ca-bundle"
+	Expect(GetProxyCABundle(proxy)).To(Equal(&CertificateSource{
+		Kind:      CertificateKindProxyCA,
+		Namespace: "openshift-config",
+		Name:      "user-ca-bundle",
+		Key:       "ca-bundle.crt",
+	}))
+}
+
+func TestCertificateResources_GetServingCertificates(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Grafana keeps the certificate of the service CA on OpenShift
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.CertManager = &v1.CertManagerSpec{
+			IssuerRef: v1.CertManagerIssuerRef{Name: "ca-issuer"},
+		}
+	})
+	certificates := GetServingCertificates(cr)
+	Expect(certificates).To(Equal([]ServingCertificate{
+		{Namespace: cr.GetPrometheusOperatorNamespace(), Service: "obs-prometheus", SecretName: "prometheus-k8s-tls"},
+		{Namespace: cr.GetPrometheusOperatorNamespace(), Service: "obs-alertmanager", SecretName: "alertmanager-k8s-tls"},
+	}))
+	Expect(GetServingCertAnnotations(cr, "prometheus-k8s-tls")).To(BeNil())
+	Expect(GetServingCertAnnotations(cr, GrafanaTLSSecretName)).To(Equal(map[string]string{
+		ServingCertSecretAnnotation: GrafanaTLSSecretName,
+	}))
+	Expect(IsCertManagerServedDirectly(cr)).To(BeFalse())
+	Expect(GetAlertmanagerWebSpec(cr)).To(BeNil())
+
+	host := fmt.Sprintf("obs-prometheus.%v.svc", cr.GetPrometheusOperatorNamespace())
+	Expect(GetCertManagerCertificateSpec(cr, certificates[0])).To(Equal(map[string]interface{}{
+		"secretName": "prometheus-k8s-tls",
+		"commonName": host,
+		"dnsNames":   []interface{}{host, host + ".cluster.local"},
+		"usages":     []interface{}{"server auth", "digital signature", "key encipherment"},
+		"issuerRef": map[string]interface{}{
+			"name":  "ca-issuer",
+			"kind":  "Issuer",
+			"group": "cert-manager.io",
+		},
+	}))
+	certificate := GetCertManagerCertificate(certificates[0])
+	Expect(certificate.GetName()).To(Equal("prometheus-k8s-tls"))
+	Expect(certificate.GroupVersionKind()).To(Equal(CertManagerCertificateGroupVersionKind))
+
+	cr.Spec.CertManager.IssuerRef.Kind = "ClusterIssuer"
+	cr.Spec.CertManager.Duration = "2160h"
+	spec := GetCertManagerCertificateSpec(cr, certificates[0])
+	Expect(spec["issuerRef"]).To(HaveKeyWithValue("kind", "ClusterIssuer"))
+	Expect(spec).To(HaveKeyWithValue("duration", "2160h"))
+	Expect(spec).ToNot(HaveKey("renewBefore"))
+
+	// On Kubernetes Alertmanager and Grafana serve the certificates themselves
+	cr.Spec.ClusterType = v1.ClusterTypeKubernetes
+	Expect(GetServingCertificates(cr)).To(HaveLen(3))
+	Expect(GetServingCertificates(cr)[2]).To(Equal(ServingCertificate{Namespace: cr.Namespace, Service: "grafana-service", SecretName: GrafanaTLSSecretName}))
+	Expect(IsCertManagerServedDirectly(cr)).To(BeTrue())
+	Expect(GetAlertmanagerWebSpec(cr).TLSConfig.Cert.Secret.Name).To(Equal("alertmanager-k8s-tls"))
+	Expect(GetCertificateSecrets(cr)).To(HaveLen(3))
+
+	// Without an issuer the service CA issues all of them
+	cr.Spec.CertManager = nil
+	Expect(GetServingCertAnnotations(cr, "prometheus-k8s-tls")).To(HaveKeyWithValue(ServingCertSecretAnnotation, "prometheus-k8s-tls"))
+}
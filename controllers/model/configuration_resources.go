@@ -0,0 +1,181 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DryRunReportKey    = "changes.yaml"
+	TrustedCABundleKey = "ca-bundle.crt"
+	// One of the default certificate directories of Go binaries. Certificates found here are
+	// trusted in addition to the certificate bundle of the image.
+	TrustedCABundleMountPath = "/etc/pki/tls/certs"
+	// Set on the secrets of the configuration revisions
+	ConfigRevisionLabel          = "observability-operator/config-revision"
+	ConfigRevisionHashAnnotation = "observability-operator/config-hash"
+	ConfigRevisionKey            = "bundle.json.gz"
+	RequiredPermissionsKey       = "role.yaml"
+)
+
+const defaultResourceSyncWorkers = 4
+
+// Components for which resource recommendations are calculated
+const (
+	ComponentPrometheus = "prometheus"
+	ComponentGrafana    = "grafana"
+	ComponentPromtail   = "promtail"
+)
+
+// Holds the changes computed during a dry run of the configuration stage
+func GetDryRunReportConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-dry-run",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// Last applied revision of the indexes with a staged rollout, one key per index id. A secret, the
+// indexes hold the credentials of the Observatorium instances.
+func GetIndexRolloutSecret(cr *v1.Observability) *v13.Secret {
+	return &v13.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-index-rollout",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// Config map of the applied revisions of earlier operator versions, replaced by the secret
+func GetIndexRolloutConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-index-rollout",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// Documents fetched by the sync that created the revision
+func GetConfigRevisionSecret(cr *v1.Observability, revision int) *v13.Secret {
+	return &v13.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("observability-config-revision-%d", revision),
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by":        "observability-operator",
+				ConfigRevisionLabel: strconv.Itoa(revision),
+			},
+		},
+	}
+}
+
+// Roles with the permissions required by the stages for the features enabled in the CR
+func GetRequiredPermissionsConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-required-permissions",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// The cluster network operator injects the trusted CA bundle of the cluster into this config map
+func GetTrustedCABundleConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observability-trusted-ca-bundle",
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+				"config.openshift.io/inject-trusted-cabundle": "true",
+			},
+		},
+	}
+}
+
+// The volume is optional, pods don't wait for the bundle to be injected
+func GetTrustedCABundleVolume(cr *v1.Observability) v13.Volume {
+	configMap := GetTrustedCABundleConfigMap(cr)
+	return v13.Volume{
+		Name: "trusted-ca-bundle",
+		VolumeSource: v13.VolumeSource{
+			ConfigMap: &v13.ConfigMapVolumeSource{
+				LocalObjectReference: v13.LocalObjectReference{
+					Name: configMap.Name,
+				},
+				Items: []v13.KeyToPath{
+					{
+						Key:  TrustedCABundleKey,
+						Path: TrustedCABundleKey,
+					},
+				},
+				Optional: &([]bool{true})[0],
+			},
+		},
+	}
+}
+
+func GetTrustedCABundleVolumeMount() v13.VolumeMount {
+	return v13.VolumeMount{
+		Name:      "trusted-ca-bundle",
+		MountPath: TrustedCABundleMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// Returns the resource requirements with the recommended requests of the container applied,
+// if auto resize is enabled. Requests are capped at the configured limits.
+func GetRecommendedResourceRequirement(cr *v1.Observability, component string, container string, requirement v13.ResourceRequirements) v13.ResourceRequirements {
+	if !cr.AutoResizeEnabled() {
+		return requirement
+	}
+
+	for _, recommendation := range cr.Status.ResourceRecommendations {
+		if recommendation.Component != component || recommendation.Container != container {
+			continue
+		}
+
+		result := *requirement.DeepCopy()
+		if result.Requests == nil {
+			result.Requests = v13.ResourceList{}
+		}
+		for name, quantity := range recommendation.Requests {
+			if limit, ok := result.Limits[name]; ok && limit.Cmp(quantity) < 0 {
+				quantity = limit
+			}
+			result.Requests[name] = quantity
+		}
+		return result
+	}
+
+	return requirement
+}
+
+func GetResourceSyncWorkers(cr *v1.Observability) int {
+	if cr.DryRunEnabled() {
+		return 1
+	}
+	if cr.Spec.ResourceSyncWorkers != nil && *cr.Spec.ResourceSyncWorkers > 0 {
+		return *cr.Spec.ResourceSyncWorkers
+	}
+	return defaultResourceSyncWorkers
+}
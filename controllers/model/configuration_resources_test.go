@@ -0,0 +1,159 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigurationResources_GetDryRunReportConfigMap(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+	tests := []struct {
+		name string
+		args args
+		want *corev1.ConfigMap
+	}{
+		{
+			name: "return managed dry run report config map in cr namespace",
+			args: args{
+				cr: buildObservabilityCR(nil),
+			},
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "observability-dry-run",
+					Namespace: testNamespace,
+					Labels: map[string]string{
+						"managed-by": "observability-operator",
+					},
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetDryRunReportConfigMap(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestConfigurationResources_GetRecommendedResourceRequirement(t *testing.T) {
+	withRecommendation := func(autoResize bool) *v1.Observability {
+		return buildObservabilityCR(func(obsCR *v1.Observability) {
+			obsCR.Spec.AutoResize = &autoResize
+			obsCR.Status.ResourceRecommendations = []v1.ResourceRecommendation{
+				{
+					Component: ComponentPrometheus,
+					Container: "prometheus",
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+			}
+		})
+	}
+
+	type args struct {
+		cr          *v1.Observability
+		container   string
+		requirement corev1.ResourceRequirements
+	}
+	tests := []struct {
+		name string
+		args args
+		want corev1.ResourceRequirements
+	}{
+		{
+			name: "unchanged requirement if auto resize is disabled",
+			args: args{
+				cr:          withRecommendation(false),
+				container:   "prometheus",
+				requirement: corev1.ResourceRequirements{},
+			},
+			want: corev1.ResourceRequirements{},
+		},
+		{
+			name: "unchanged requirement if there is no recommendation for the container",
+			args: args{
+				cr:          withRecommendation(true),
+				container:   "oauth-proxy",
+				requirement: corev1.ResourceRequirements{},
+			},
+			want: corev1.ResourceRequirements{},
+		},
+		{
+			name: "recommended requests capped at limits",
+			args: args{
+				cr:        withRecommendation(true),
+				container: "prometheus",
+				requirement: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+			want: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetRecommendedResourceRequirement(tt.args.cr, ComponentPrometheus, tt.args.container, tt.args.requirement)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestConfigurationResources_GetTrustedCABundleConfigMap(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+	tests := []struct {
+		name string
+		args args
+		want *corev1.ConfigMap
+	}{
+		{
+			name: "return managed config map labelled for trusted ca bundle injection",
+			args: args{
+				cr: buildObservabilityCR(nil),
+			},
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "observability-trusted-ca-bundle",
+					Namespace: testNamespace,
+					Labels: map[string]string{
+						"managed-by": "observability-operator",
+						"config.openshift.io/inject-trusted-cabundle": "true",
+					},
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetTrustedCABundleConfigMap(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v15 "k8s.io/api/rbac/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	EventExporterImage    = "caicloud/event-exporter"
+	EventExporterImageTag = "v1.0.0"
+
+	EventExporterName = "event-exporter"
+	EventExporterPort = 9102
+)
+
+//go:embed event_exporter_dashboard.json
+var eventExporterDashboard string
+
+func GetEventExporterImage(cr *v1.Observability) string {
+	tag := EventExporterImageTag
+	if exporter := cr.Spec.SelfContained.EventExporter; exporter.Version != "" {
+		tag = exporter.Version
+	}
+	return GetImage(cr, v1.ImageEventExporter, EventExporterImage, tag)
+}
+
+func GetEventExporterServiceAccount(cr *v1.Observability) *v14.ServiceAccount {
+	return &v14.ServiceAccount{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, EventExporterName),
+	}
+}
+
+// Cluster scoped, the namespace keeps the names of multiple instances apart
+func GetEventExporterClusterRole(cr *v1.Observability) *v15.ClusterRole {
+	return &v15.ClusterRole{
+		ObjectMeta: v12.ObjectMeta{
+			Name:   fmt.Sprintf("%v-%v", cr.GetPrometheusOperatorNamespace(), EventExporterName),
+			Labels: getInfrastructureExporterLabels(EventExporterName),
+		},
+	}
+}
+
+func GetEventExporterClusterRoleBinding(cr *v1.Observability) *v15.ClusterRoleBinding {
+	return &v15.ClusterRoleBinding{
+		ObjectMeta: v12.ObjectMeta{
+			Name:   fmt.Sprintf("%v-%v", cr.GetPrometheusOperatorNamespace(), EventExporterName),
+			Labels: getInfrastructureExporterLabels(EventExporterName),
+		},
+	}
+}
+
+func GetEventExporterClusterRoleRules() []v15.PolicyRule {
+	return []v15.PolicyRule{
+		{
+			Verbs:     []string{"get", "list", "watch"},
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+		},
+	}
+}
+
+func GetEventExporterDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, EventExporterName),
+	}
+}
+
+func GetEventExporterService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, EventExporterName),
+	}
+}
+
+func GetEventExporterContainer(cr *v1.Observability) v14.Container {
+	return v14.Container{
+		Name:  EventExporterName,
+		Image: GetEventExporterImage(cr),
+		Ports: []v14.ContainerPort{
+			{
+				Name:          "http-metrics",
+				ContainerPort: EventExporterPort,
+			},
+		},
+		SecurityContext: GetContainerSecurityContext(cr),
+	}
+}
+
+// The exporter counts the events by the involved object, reason and type in kube_event_count.
+// The series of the involved objects are kept, honor_labels keeps the namespace of the objects
+// apart from the one of the exporter.
+func GetEventExporterScrapeConfig(cr *v1.Observability) []byte {
+	const config = `
+- job_name: event-exporter
+  honor_labels: true
+  kubernetes_sd_configs:
+    - role: endpoints
+      namespaces:
+        names:
+          - %[1]v
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_service_name', '__meta_kubernetes_endpoint_port_name' ]
+      regex: %[2]v;http-metrics
+`
+	return []byte(fmt.Sprintf(config, cr.GetPrometheusOperatorNamespace(), EventExporterName))
+}
+
+// Events by reason and namespace, OOM kills and failed scheduling
+func GetEventExporterDashboard(cr *v1.Observability) *v1alpha1.GrafanaDashboard {
+	return &v1alpha1.GrafanaDashboard{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-events",
+			Namespace: cr.Namespace,
+		},
+		Spec: v1alpha1.GrafanaDashboardSpec{
+			Json: eventExporterDashboard,
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestEventExporterResources_GetEventExporterScrapeConfig(t *testing.T) {
+	RegisterTestingT(t)
+	tr := true
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.SelfContained = &v1.SelfContained{
+			EventExporter: &v1.EventExporter{
+				Enabled: &tr,
+				Version: "v1.1.0",
+			},
+		}
+	})
+	Expect(cr.EventExporterEnabled()).To(BeTrue())
+
+	var scrapeConfigs []map[string]interface{}
+	Expect(yaml.Unmarshal(GetEventExporterScrapeConfig(cr), &scrapeConfigs)).To(Succeed())
+	Expect(scrapeConfigs).To(HaveLen(1))
+	Expect(scrapeConfigs[0]["job_name"]).To(Equal(EventExporterName))
+
+	Expect(GetEventExporterImage(cr)).To(Equal(EventExporterImage + ":v1.1.0"))
+
+	var dashboard map[string]interface{}
+	Expect(json.Unmarshal([]byte(GetEventExporterDashboard(cr).Spec.Json), &dashboard)).To(Succeed())
+	Expect(dashboard["uid"]).To(Equal("generated-events"))
+
+	// Namespaced roles can't watch the events of all namespaces
+	cr.Spec.TargetNamespaces = []string{"observability"}
+	Expect(cr.EventExporterEnabled()).To(BeFalse())
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	defaultFetchTimeout           = 30 * time.Second
+	defaultFetchRequestsPerSecond = 10
+	defaultFetchFailureThreshold  = 5
+	defaultFetchOpenDuration      = time.Minute
+)
+
+// Fetch policy of the CR with the defaults applied
+type FetchLimits struct {
+	Timeout           time.Duration
+	RequestsPerSecond int
+	Burst             int
+	// 0 if the circuit breaker is disabled
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+func GetFetchLimits(cr *v1.Observability) FetchLimits {
+	limits := FetchLimits{
+		Timeout:           defaultFetchTimeout,
+		RequestsPerSecond: defaultFetchRequestsPerSecond,
+		FailureThreshold:  defaultFetchFailureThreshold,
+		OpenDuration:      defaultFetchOpenDuration,
+	}
+
+	policy := cr.Spec.Fetch
+	if policy != nil {
+		limits.Timeout = parsePositiveDuration(policy.Timeout, limits.Timeout)
+		limits.OpenDuration = parsePositiveDuration(policy.OpenDuration, limits.OpenDuration)
+		if policy.RequestsPerSecond != nil && *policy.RequestsPerSecond >= 1 {
+			limits.RequestsPerSecond = *policy.RequestsPerSecond
+		}
+		if policy.Burst != nil && *policy.Burst >= 1 {
+			limits.Burst = *policy.Burst
+		}
+		if policy.FailureThreshold != nil && *policy.FailureThreshold >= 0 {
+			limits.FailureThreshold = *policy.FailureThreshold
+		}
+	}
+
+	if limits.Burst == 0 {
+		limits.Burst = limits.RequestsPerSecond
+	}
+	return limits
+}
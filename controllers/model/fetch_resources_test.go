@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestFetchResources_GetFetchLimits(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetFetchLimits(buildObservabilityCR(nil))).To(Equal(FetchLimits{
+		Timeout:           30 * time.Second,
+		RequestsPerSecond: 10,
+		Burst:             10,
+		FailureThreshold:  5,
+		OpenDuration:      time.Minute,
+	}))
+
+	requestsPerSecond := 2
+	failureThreshold := 0
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.Fetch = &v1.FetchPolicy{
+			Timeout:           "5s",
+			RequestsPerSecond: &requestsPerSecond,
+			FailureThreshold:  &failureThreshold,
+			OpenDuration:      "never",
+		}
+	})
+	Expect(GetFetchLimits(cr)).To(Equal(FetchLimits{
+		Timeout:           5 * time.Second,
+		RequestsPerSecond: 2,
+		Burst:             2,
+		FailureThreshold:  0,
+		OpenDuration:      time.Minute,
+	}))
+}
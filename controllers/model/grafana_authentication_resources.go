@@ -0,0 +1,221 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	GrafanaServiceAccountName = "grafana-serviceaccount"
+	GrafanaRouteName          = "grafana-route"
+	// Created by the Grafana operator
+	GrafanaServiceName   = "grafana-service"
+	GrafanaTLSSecretName = "grafana-k8s-tls"
+	// Key of the client secret in the OIDC client secret
+	GrafanaOIDCClientSecretKey = "clientSecret"
+	grafanaSecretsPath         = "/etc/grafana-secrets"
+	grafanaOAuthClientName     = "grafana-oauth-client"
+	grafanaDefaultOIDCScopes   = "openid profile email"
+	grafanaDefaultGroupsClaim  = "groups"
+	// Roles in the order they're matched, users without a match are viewers
+	grafanaAdminRole  = "Admin"
+	grafanaEditorRole = "Editor"
+	grafanaViewerRole = "Viewer"
+
+	// Token of the Grafana service account, the secret of its OAuth client
+	GrafanaOAuthTokenVolume = "grafana-oauth-token"
+	// Grafana only reads the client secret on start, its pods are rolled before the token expires
+	GrafanaOAuthTokenExpirationSeconds  int64 = 86400
+	GrafanaOAuthTokenRotationAnnotation       = "observability-operator/oauth-token-rotation"
+)
+
+// Users and groups bound to a cluster role
+type GrafanaRoleSubjects struct {
+	Users  []string
+	Groups []string
+}
+
+// Long-lived token secret of the Grafana service account created by earlier versions, replaced
+// by a projected token
+func GetGrafanaOAuthClientSecret(cr *v1.Observability) *v13.Secret {
+	return &v13.Secret{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      grafanaOAuthClientName,
+			Namespace: cr.Namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// The OAuth server of OpenShift runs on a route of the default ingress domain
+func GetOpenShiftOAuthHost(domain string) string {
+	return fmt.Sprintf("oauth-openshift.%v", domain)
+}
+
+// External URL of Grafana, the redirects of the login must match it. The route created by the
+// Grafana operator gets the default host of OpenShift.
+func GetGrafanaRootUrl(cr *v1.Observability, domain string) string {
+	if cr.IngressEnabled() && cr.Spec.Ingress.Grafana != nil {
+		return fmt.Sprintf("https://%v", GetIngressHost(cr.Spec.Ingress.Grafana))
+	}
+	if domain == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%v-%v.%v", GrafanaRouteName, cr.Namespace, domain)
+}
+
+// Generic OAuth configuration of the OpenShift OAuth server or the OIDC provider. The Grafana
+// service account is the OAuth client on OpenShift and the API returns the user.
+func GetGrafanaAuthGenericOauth(cr *v1.Observability, oauthHost string, roleAttributePath string) *v1alpha1.GrafanaConfigAuthGenericOauth {
+	t := true
+	config := &v1alpha1.GrafanaConfigAuthGenericOauth{
+		Enabled:           &t,
+		AllowSignUp:       &t,
+		RoleAttributePath: roleAttributePath,
+	}
+
+	switch cr.GetGrafanaAuthenticationType() {
+	case v1.GrafanaAuthenticationOpenShift:
+		config.ClientId = fmt.Sprintf("system:serviceaccount:%v:%v", cr.Namespace, GrafanaServiceAccountName)
+		config.ClientSecret = fmt.Sprintf("$__file{%v}", GetServiceAccountTokenFile(GrafanaOAuthTokenVolume))
+		config.Scopes = "user:info"
+		config.AuthUrl = fmt.Sprintf("https://%v/oauth/authorize", oauthHost)
+		config.TokenUrl = fmt.Sprintf("https://%v/oauth/token", oauthHost)
+		config.ApiUrl = "https://kubernetes.default.svc/apis/user.openshift.io/v1/users/~"
+		config.EmailAttributePath = "metadata.name"
+	case v1.GrafanaAuthenticationOIDC:
+		oidc := cr.Spec.GrafanaAuthentication.OIDC
+		if oidc == nil {
+			return nil
+		}
+		config.ClientId = oidc.ClientId
+		config.ClientSecret = fmt.Sprintf("$__file{%v/%v/%v}", grafanaSecretsPath, oidc.ClientSecret, GrafanaOIDCClientSecretKey)
+		config.Scopes = oidc.Scopes
+		if config.Scopes == "" {
+			config.Scopes = grafanaDefaultOIDCScopes
+		}
+		config.AuthUrl = oidc.AuthUrl
+		config.TokenUrl = oidc.TokenUrl
+		config.ApiUrl = oidc.ApiUrl
+	default:
+		return nil
+	}
+	return config
+}
+
+// Secrets mounted by the Grafana operator for the login
+func GetGrafanaAuthenticationSecrets(cr *v1.Observability) []string {
+	if cr.GetGrafanaAuthenticationType() == v1.GrafanaAuthenticationOIDC && cr.Spec.GrafanaAuthentication.OIDC != nil {
+		return []string{cr.Spec.GrafanaAuthentication.OIDC.ClientSecret}
+	}
+	return nil
+}
+
+// Projected token of the Grafana service account on OpenShift
+func GetGrafanaAuthenticationVolumes(cr *v1.Observability) []v13.Volume {
+	if cr.GetGrafanaAuthenticationType() != v1.GrafanaAuthenticationOpenShift {
+		return nil
+	}
+	return []v13.Volume{GetServiceAccountTokenVolume(GrafanaOAuthTokenVolume, "", GrafanaOAuthTokenExpirationSeconds)}
+}
+
+func GetGrafanaAuthenticationVolumeMounts(cr *v1.Observability) []v13.VolumeMount {
+	if cr.GetGrafanaAuthenticationType() != v1.GrafanaAuthenticationOpenShift {
+		return nil
+	}
+	return []v13.VolumeMount{GetServiceAccountTokenVolumeMount(GrafanaOAuthTokenVolume)}
+}
+
+// Changes every three quarters of the token lifetime, so that the pods are rolled and read a new
+// token before the one they started with expires. Empty without the OpenShift login.
+func GetGrafanaOAuthTokenRotation(cr *v1.Observability, now time.Time) string {
+	if cr.GetGrafanaAuthenticationType() != v1.GrafanaAuthenticationOpenShift {
+		return ""
+	}
+	return strconv.FormatInt(now.Unix()/(GrafanaOAuthTokenExpirationSeconds*3/4), 10)
+}
+
+// OpenShift users have no login or name claims, their name is the login. The service account
+// CA bundle includes the CA of the ingress and of the API server.
+func GetGrafanaAuthenticationEnv(cr *v1.Observability) []v13.EnvVar {
+	if cr.GetGrafanaAuthenticationType() != v1.GrafanaAuthenticationOpenShift {
+		return nil
+	}
+	return []v13.EnvVar{
+		{
+			Name:  "GF_AUTH_GENERIC_OAUTH_LOGIN_ATTRIBUTE_PATH",
+			Value: "metadata.name",
+		},
+		{
+			Name:  "GF_AUTH_GENERIC_OAUTH_NAME_ATTRIBUTE_PATH",
+			Value: "metadata.name",
+		},
+		{
+			Name:  "SSL_CERT_DIR",
+			Value: "/etc/ssl/certs:/var/run/secrets/kubernetes.io/serviceaccount",
+		},
+	}
+}
+
+// JMESPath expression of the org role of a user. Group mappings match the groups of the user,
+// cluster role mappings the subjects of their bindings. Empty without any mapping.
+func GetGrafanaRoleAttributePath(cr *v1.Observability, bindings map[string]GrafanaRoleSubjects) string {
+	if cr.Spec.GrafanaAuthentication == nil {
+		return ""
+	}
+
+	authType := cr.GetGrafanaAuthenticationType()
+	groupsClaim := grafanaDefaultGroupsClaim
+	if oidc := cr.Spec.GrafanaAuthentication.OIDC; authType == v1.GrafanaAuthenticationOIDC && oidc != nil && oidc.GroupsClaim != "" {
+		groupsClaim = oidc.GroupsClaim
+	}
+	groups := fmt.Sprintf("%v || `[]`", groupsClaim)
+
+	var cases []string
+	for _, role := range []string{grafanaAdminRole, grafanaEditorRole} {
+		var conditions []string
+		for _, mapping := range cr.Spec.GrafanaAuthentication.RoleMappings {
+			if mapping.Role != role {
+				continue
+			}
+			if mapping.Group != "" {
+				conditions = append(conditions, fmt.Sprintf("contains(%v, %v)", groups, quoteJMESPath(mapping.Group)))
+			}
+			if mapping.ClusterRole == "" || authType != v1.GrafanaAuthenticationOpenShift {
+				continue
+			}
+			subjects := bindings[mapping.ClusterRole]
+			if len(subjects.Users) > 0 {
+				var users []string
+				for _, user := range subjects.Users {
+					users = append(users, quoteJMESPath(user))
+				}
+				conditions = append(conditions, fmt.Sprintf("contains([%v], metadata.name)", strings.Join(users, ", ")))
+			}
+			for _, group := range subjects.Groups {
+				conditions = append(conditions, fmt.Sprintf("contains(%v, %v)", groups, quoteJMESPath(group)))
+			}
+		}
+		if len(conditions) > 0 {
+			cases = append(cases, fmt.Sprintf("(%v) && '%v'", strings.Join(conditions, " || "), role))
+		}
+	}
+	if len(cases) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v || '%v'", strings.Join(cases, " || "), grafanaViewerRole)
+}
+
+func quoteJMESPath(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return fmt.Sprintf("'%v'", strings.ReplaceAll(value, `'`, `\'`))
+}
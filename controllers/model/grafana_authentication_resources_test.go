@@ -0,0 +1,117 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestGrafanaAuthenticationResources_GetGrafanaRoleAttributePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		cr       *v1.Observability
+		bindings map[string]GrafanaRoleSubjects
+		want     string
+	}{
+		{
+			name: "no mappings",
+			cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+				obsCR.Spec.GrafanaAuthentication = &v1.GrafanaAuthenticationSpec{Type: v1.GrafanaAuthenticationOpenShift}
+			}),
+			want: "",
+		},
+		{
+			name: "cluster roles and groups on OpenShift",
+			cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+				obsCR.Spec.GrafanaAuthentication = &v1.GrafanaAuthenticationSpec{
+					Type: v1.GrafanaAuthenticationOpenShift,
+					RoleMappings: []v1.GrafanaRoleMapping{
+						{Group: "sre", Role: "Editor"},
+						{ClusterRole: "cluster-admin", Role: "Admin"},
+					},
+				}
+			}),
+			bindings: map[string]GrafanaRoleSubjects{
+				"cluster-admin": {Users: []string{"alice", "o'brien"}, Groups: []string{"admins"}},
+			},
+			want: "(contains(['alice', 'o\\'brien'], metadata.name) || contains(groups || `[]`, 'admins')) && 'Admin' || " +
+				"(contains(groups || `[]`, 'sre')) && 'Editor' || 'Viewer'",
+		},
+		{
+			name: "cluster roles are ignored with OIDC",
+			cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+				obsCR.Spec.GrafanaAuthentication = &v1.GrafanaAuthenticationSpec{
+					Type: v1.GrafanaAuthenticationOIDC,
+					RoleMappings: []v1.GrafanaRoleMapping{
+						{ClusterRole: "cluster-admin", Role: "Admin"},
+						{Group: "grafana-admins", Role: "Admin"},
+					},
+					OIDC: &v1.GrafanaOIDCSpec{GroupsClaim: "roles"},
+				}
+			}),
+			bindings: map[string]GrafanaRoleSubjects{
+				"cluster-admin": {Users: []string{"alice"}},
+			},
+			want: "(contains(roles || `[]`, 'grafana-admins')) && 'Admin' || 'Viewer'",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Expect(GetGrafanaRoleAttributePath(tt.cr, tt.bindings)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGrafanaAuthenticationResources_GetGrafanaAuthGenericOauth(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.GrafanaAuthentication = &v1.GrafanaAuthenticationSpec{Type: v1.GrafanaAuthenticationOpenShift}
+	})
+	config := GetGrafanaAuthGenericOauth(cr, GetOpenShiftOAuthHost("apps.example.com"), "")
+	Expect(config.ClientId).To(Equal("system:serviceaccount:" + cr.Namespace + ":grafana-serviceaccount"))
+	Expect(config.AuthUrl).To(Equal("https://oauth-openshift.apps.example.com/oauth/authorize"))
+	Expect(config.ClientSecret).To(Equal("$__file{/var/run/secrets/tokens/grafana-oauth-token/token}"))
+	Expect(GetGrafanaAuthenticationSecrets(cr)).To(BeEmpty())
+	Expect(GetGrafanaAuthenticationVolumes(cr)).To(HaveLen(1))
+	Expect(*GetGrafanaAuthenticationVolumes(cr)[0].Projected.Sources[0].ServiceAccountToken.ExpirationSeconds).To(Equal(GrafanaOAuthTokenExpirationSeconds))
+	Expect(GetGrafanaAuthenticationVolumeMounts(cr)[0].MountPath).To(Equal("/var/run/secrets/tokens/grafana-oauth-token"))
+	Expect(GetGrafanaRootUrl(cr, "apps.example.com")).To(Equal("https://grafana-route-" + cr.Namespace + ".apps.example.com"))
+
+	cr.Spec.GrafanaAuthentication = &v1.GrafanaAuthenticationSpec{
+		Type: v1.GrafanaAuthenticationOIDC,
+		OIDC: &v1.GrafanaOIDCSpec{ClientId: "grafana", ClientSecret: "grafana-oidc"},
+	}
+	config = GetGrafanaAuthGenericOauth(cr, "", "")
+	Expect(config.ClientSecret).To(Equal("$__file{/etc/grafana-secrets/grafana-oidc/clientSecret}"))
+	Expect(config.Scopes).To(Equal("openid profile email"))
+	Expect(GetGrafanaAuthenticationEnv(cr)).To(BeNil())
+	Expect(GetGrafanaAuthenticationVolumes(cr)).To(BeNil())
+
+	// The proxy authentication has no login of Grafana
+	cr.Spec.GrafanaAuthentication = nil
+	Expect(GetGrafanaAuthGenericOauth(cr, "", "")).To(BeNil())
+}
+
+func TestGrafanaAuthenticationResources_GetGrafanaOAuthTokenRotation(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.GrafanaAuthentication = &v1.GrafanaAuthenticationSpec{Type: v1.GrafanaAuthenticationOpenShift}
+	})
+	start := time.Unix(0, 0)
+	rotation := GetGrafanaOAuthTokenRotation(cr, start)
+	Expect(rotation).ToNot(BeEmpty())
+
+	// The pods are rolled while the token they started with is still valid
+	period := time.Duration(GrafanaOAuthTokenExpirationSeconds) * time.Second * 3 / 4
+	Expect(GetGrafanaOAuthTokenRotation(cr, start.Add(period-time.Second))).To(Equal(rotation))
+	Expect(GetGrafanaOAuthTokenRotation(cr, start.Add(period))).ToNot(Equal(rotation))
+
+	cr.Spec.GrafanaAuthentication.Type = v1.GrafanaAuthenticationOIDC
+	Expect(GetGrafanaOAuthTokenRotation(cr, start)).To(BeEmpty())
+}
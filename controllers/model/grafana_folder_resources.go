@@ -0,0 +1,106 @@
+package model
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	GrafanaFolderPermissionRole = "role"
+	GrafanaFolderPermissionTeam = "team"
+	// Folder of the dashboards without a custom folder
+	grafanaDefaultFolder   = "general"
+	maxGrafanaFolderPrefix = 40
+)
+
+// Levels of the folder permissions of the Grafana API
+var grafanaFolderPermissionLevels = map[string]int{
+	"view":  1,
+	"edit":  2,
+	"admin": 4,
+}
+
+// Keys of the folder permissions of the Grafana API
+var grafanaFolderPermissionTargetTypes = map[string]string{
+	GrafanaFolderPermissionRole: "role",
+	GrafanaFolderPermissionTeam: "teamId",
+}
+
+var grafanaFolderNameInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Titles can have any characters, the hash keeps the names of similar titles apart
+func GetGrafanaFolder(cr *v1.Observability, title string) *v1alpha1.GrafanaFolder {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(title))
+	prefix := strings.Trim(grafanaFolderNameInvalidChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(prefix) > maxGrafanaFolderPrefix {
+		prefix = strings.TrimRight(prefix[:maxGrafanaFolderPrefix], "-")
+	}
+	name := fmt.Sprintf("dashboard-folder-%08x", hash.Sum32())
+	if prefix != "" {
+		name = fmt.Sprintf("dashboard-folder-%v-%08x", prefix, hash.Sum32())
+	}
+	return &v1alpha1.GrafanaFolder{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+// Dashboards of a folder have to be listed in the dashboards of its index
+func ValidateGrafanaFolder(folder *v1.GrafanaFolderIndex, dashboards []string) error {
+	if strings.TrimSpace(folder.Name) == "" || strings.EqualFold(folder.Name, grafanaDefaultFolder) {
+		return fmt.Errorf("invalid grafana folder name %v", folder.Name)
+	}
+	for _, dashboard := range folder.Dashboards {
+		found := false
+		for _, existing := range dashboards {
+			if existing == dashboard {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("dashboard %v of grafana folder %v is not a dashboard of the index", dashboard, folder.Name)
+		}
+	}
+	for _, permission := range folder.Permissions {
+		if _, ok := grafanaFolderPermissionLevels[permission.Permission]; !ok {
+			return fmt.Errorf("invalid permission %v in grafana folder %v", permission.Permission, folder.Name)
+		}
+		switch permission.Type {
+		case GrafanaFolderPermissionRole:
+			if permission.Target != "Viewer" && permission.Target != "Editor" {
+				return fmt.Errorf("invalid role %v in grafana folder %v", permission.Target, folder.Name)
+			}
+		case GrafanaFolderPermissionTeam:
+			if _, err := strconv.ParseUint(permission.Target, 10, 64); err != nil {
+				return fmt.Errorf("invalid team id %v in grafana folder %v", permission.Target, folder.Name)
+			}
+		default:
+			return fmt.Errorf("invalid permission type %v in grafana folder %v", permission.Type, folder.Name)
+		}
+	}
+	return nil
+}
+
+// Only valid folders are expected
+func GetGrafanaFolderPermissions(folder *v1.GrafanaFolderIndex) []v1alpha1.GrafanaPermissionItem {
+	var result []v1alpha1.GrafanaPermissionItem
+	for _, permission := range folder.Permissions {
+		result = append(result, v1alpha1.GrafanaPermissionItem{
+			PermissionTargetType: grafanaFolderPermissionTargetTypes[permission.Type],
+			PermissionTarget:     permission.Target,
+			PermissionLevel:      grafanaFolderPermissionLevels[permission.Permission],
+		})
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestGrafanaFolderResources_GetGrafanaFolder(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	folder := GetGrafanaFolder(cr, "Kafka / Brokers")
+	Expect(folder.Name).To(MatchRegexp(`^dashboard-folder-kafka-brokers-[0-9a-f]{8}$`))
+	Expect(folder.Namespace).To(Equal(testNamespace))
+	Expect(GetGrafanaFolder(cr, "Kafka Brokers").Name).ToNot(Equal(folder.Name))
+	Expect(GetGrafanaFolder(cr, "Überwachung").Name).To(MatchRegexp(`^dashboard-folder-berwachung-[0-9a-f]{8}$`))
+	Expect(GetGrafanaFolder(cr, "???").Name).To(MatchRegexp(`^dashboard-folder-[0-9a-f]{8}$`))
+}
+
+func TestGrafanaFolderResources_ValidateGrafanaFolder(t *testing.T) {
+	RegisterTestingT(t)
+
+	dashboards := []string{"dashboards/kafka.json"}
+	valid := v1.GrafanaFolderIndex{
+		Name:       "Kafka",
+		Dashboards: dashboards,
+		Permissions: []v1.GrafanaFolderPermission{
+			{Type: GrafanaFolderPermissionRole, Target: "Editor", Permission: "admin"},
+		},
+	}
+	Expect(ValidateGrafanaFolder(&valid, dashboards)).To(Succeed())
+	Expect(GetGrafanaFolderPermissions(&valid)[0].PermissionLevel).To(Equal(4))
+
+	invalid := []v1.GrafanaFolderIndex{
+		{Name: ""},
+		{Name: "General"},
+		{Name: "Kafka", Dashboards: []string{"dashboards/other.json"}},
+		{Name: "Kafka", Permissions: []v1.GrafanaFolderPermission{{Type: "role", Target: "Admin", Permission: "view"}}},
+		{Name: "Kafka", Permissions: []v1.GrafanaFolderPermission{{Type: "team", Target: "sre", Permission: "view"}}},
+		{Name: "Kafka", Permissions: []v1.GrafanaFolderPermission{{Type: "user", Target: "1", Permission: "view"}}},
+		{Name: "Kafka", Permissions: []v1.GrafanaFolderPermission{{Type: "role", Target: "Viewer", Permission: "write"}}},
+	}
+	for i := range invalid {
+		Expect(ValidateGrafanaFolder(&invalid[i], dashboards)).ToNot(Succeed(), invalid[i].Name)
+	}
+}
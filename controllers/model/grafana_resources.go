@@ -91,6 +91,16 @@ func GetGrafanaDatasource(cr *v1.Observability) *v1alpha12.GrafanaDataSource {
 	}
 }
 
+// Datasources of the long-term storage of the Observatorium instances of the indexes
+func GetGrafanaQueryDatasource(cr *v1.Observability) *v1alpha12.GrafanaDataSource {
+	return &v1alpha12.GrafanaDataSource{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "observatorium-query",
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
 func GetGrafanaDashboardLabelSelectors(cr *v1.Observability, indexes []v1.RepositoryIndex) *v12.LabelSelector {
 	// if selfcontained is set override default
 	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.GrafanaDashboardLabelSelector != nil {
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+// Splits an image reference into the repository and the tag. Digests stay part of the repository.
+func SplitImageTag(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	separator := strings.LastIndex(image, ":")
+	if separator <= strings.LastIndex(image, "/") {
+		return image, ""
+	}
+	return image[:separator], image[separator+1:]
+}
+
+// Replaces the registry of the image with the image registry of the CR. Images without a
+// registry host are Docker Hub images.
+func GetMirroredImage(cr *v1.Observability, image string) string {
+	registry := strings.TrimSuffix(cr.Spec.ImageRegistry, "/")
+	if registry == "" {
+		return image
+	}
+
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return registry + "/" + parts[1]
+	}
+	return registry + "/" + image
+}
+
+// Overrides for the architecture take precedence
+func getImageOverride(cr *v1.Observability, component v1.ImageComponent, architecture string) string {
+	if override := cr.Spec.ArchImageOverrides[architecture][component]; architecture != "" && override != "" {
+		return override
+	}
+	return cr.Spec.ImageOverrides[component]
+}
+
+// Repository of the component and the tag of the override, if any. Overrides are used as
+// they are, the default repository is mirrored.
+func GetImageRepository(cr *v1.Observability, component v1.ImageComponent, repository string) (string, string) {
+	return GetImageRepositoryForArchitecture(cr, component, cr.Spec.Architecture, repository)
+}
+
+func GetImageRepositoryForArchitecture(cr *v1.Observability, component v1.ImageComponent, architecture string, repository string) (string, string) {
+	if override := getImageOverride(cr, component, architecture); override != "" {
+		return SplitImageTag(override)
+	}
+	return GetMirroredImage(cr, repository), ""
+}
+
+const defaultImageResolveInterval = time.Hour
+
+// Default images by repository, the tags are versioned separately
+const (
+	PrometheusBaseImage      = "quay.io/prometheus/prometheus"
+	GrafanaBaseImage         = "docker.io/grafana/grafana"
+	BlackboxExporterImage    = "quay.io/prometheus/blackbox-exporter"
+	BlackboxExporterImageTag = "v0.19.0"
+	PromtailImage            = "quay.io/integreatly/promtail"
+	PromtailImageTag         = "latest"
+)
+
+// Image of the component, the tag is used unless the override has its own tag or a digest
+func GetImage(cr *v1.Observability, component v1.ImageComponent, repository string, tag string) string {
+	return GetImageForArchitecture(cr, component, cr.Spec.Architecture, repository, tag)
+}
+
+func GetImageForArchitecture(cr *v1.Observability, component v1.ImageComponent, architecture string, repository string, tag string) string {
+	image, overrideTag := GetImageRepositoryForArchitecture(cr, component, architecture, repository)
+	if overrideTag != "" {
+		image = image + ":" + overrideTag
+	} else if !strings.Contains(image, "@") && tag != "" {
+		image = image + ":" + tag
+	}
+	return GetPinnedImage(cr, image)
+}
+
+// Replaces the tag with the resolved digest when images are pinned. With signature verification
+// an image without a verified digest runs the last verified digest of the same repository.
+func GetPinnedImage(cr *v1.Observability, image string) string {
+	if !cr.ImagePinningEnabled() || strings.Contains(image, "@") {
+		return image
+	}
+
+	repository, _ := SplitImageTag(image)
+	held := ""
+	for _, resolved := range cr.Status.ResolvedImages {
+		if resolved.Digest == "" || (cr.ImageVerificationEnabled() && !resolved.Verified) {
+			continue
+		}
+		if resolved.Image == image {
+			return repository + "@" + resolved.Digest
+		}
+		if previous, _ := SplitImageTag(resolved.Image); previous == repository && cr.ImageVerificationEnabled() {
+			held = previous + "@" + resolved.Digest
+		}
+	}
+	if held != "" {
+		return held
+	}
+	return image
+}
+
+// Invalid intervals fall back to the default
+func GetImageResolveInterval(cr *v1.Observability) time.Duration {
+	if cr.Spec.ImagePinning != nil && cr.Spec.ImagePinning.ResolveInterval != "" {
+		interval, err := commonmodel.ParseDuration(cr.Spec.ImagePinning.ResolveInterval)
+		if err == nil && interval > 0 {
+			return time.Duration(interval)
+		}
+	}
+	return defaultImageResolveInterval
+}
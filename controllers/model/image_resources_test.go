@@ -0,0 +1,98 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestImageResources_GetImage(t *testing.T) {
+	type args struct {
+		registry   string
+		overrides  map[v1.ImageComponent]string
+		repository string
+		tag        string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "default image",
+			args: args{repository: "quay.io/prometheus/prometheus", tag: "v2.35.0"},
+			want: "quay.io/prometheus/prometheus:v2.35.0",
+		},
+		{
+			name: "registry replaces the registry host",
+			args: args{registry: "mirror.example.com:5000/observability/", repository: "quay.io/prometheus/prometheus", tag: "v2.35.0"},
+			want: "mirror.example.com:5000/observability/prometheus/prometheus:v2.35.0",
+		},
+		{
+			name: "registry prefixes docker hub images",
+			args: args{registry: "mirror.example.com", repository: "grafana/grafana", tag: "8.5.0"},
+			want: "mirror.example.com/grafana/grafana:8.5.0",
+		},
+		{
+			name: "override without tag gets the version",
+			args: args{
+				registry:   "mirror.example.com",
+				overrides:  map[v1.ImageComponent]string{v1.ImagePrometheus: "registry.local/prometheus"},
+				repository: "quay.io/prometheus/prometheus",
+				tag:        "v2.35.0",
+			},
+			want: "registry.local/prometheus:v2.35.0",
+		},
+		{
+			name: "override with tag",
+			args: args{
+				overrides:  map[v1.ImageComponent]string{v1.ImagePrometheus: "registry.local:5000/prometheus:custom"},
+				repository: "quay.io/prometheus/prometheus",
+				tag:        "v2.35.0",
+			},
+			want: "registry.local:5000/prometheus:custom",
+		},
+		{
+			name: "override with digest",
+			args: args{
+				overrides:  map[v1.ImageComponent]string{v1.ImagePrometheus: "registry.local/prometheus@sha256:abc"},
+				repository: "quay.io/prometheus/prometheus",
+				tag:        "v2.35.0",
+			},
+			want: "registry.local/prometheus@sha256:abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cr := &v1.Observability{
+				Spec: v1.ObservabilitySpec{
+					ImageRegistry:  tt.args.registry,
+					ImageOverrides: tt.args.overrides,
+				},
+			}
+			g.Expect(GetImage(cr, v1.ImagePrometheus, tt.args.repository, tt.args.tag)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestImageResources_GetTokenRefresherImage(t *testing.T) {
+	g := NewWithT(t)
+	cr := &v1.Observability{
+		Spec: v1.ObservabilitySpec{
+			ImageOverrides: map[v1.ImageComponent]string{v1.ImageTokenRefresher: "registry.local/token-refresher:v1"},
+		},
+	}
+
+	image, tag := GetTokenRefresherImage(cr, nil)
+	g.Expect(image).To(Equal("registry.local/token-refresher"))
+	g.Expect(tag).To(Equal("v1"))
+
+	// The token refresher settings take precedence
+	cr.Spec.TokenRefresher = &v1.TokenRefresherSpec{Tag: "v2"}
+	_, tag = GetTokenRefresherImage(cr, nil)
+	g.Expect(tag).To(Equal("v2"))
+}
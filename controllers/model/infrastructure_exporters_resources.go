@@ -0,0 +1,271 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	v15 "k8s.io/api/rbac/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	KubeStateMetricsImage    = "registry.k8s.io/kube-state-metrics/kube-state-metrics"
+	KubeStateMetricsImageTag = "v2.6.0"
+	NodeExporterImage        = "quay.io/prometheus/node-exporter"
+	NodeExporterImageTag     = "v1.4.0"
+
+	KubeStateMetricsName = "kube-state-metrics"
+	KubeStateMetricsPort = 8080
+	NodeExporterName     = "node-exporter"
+	NodeExporterPort     = 9100
+	// nobody, node-exporter only reads the host filesystems
+	nodeExporterRunAsUser = 65534
+)
+
+// Resources exposed by kube-state-metrics, it needs to list and watch all of them
+var kubeStateMetricsResources = map[string][]string{
+	"":            {"namespaces", "nodes", "persistentvolumeclaims", "persistentvolumes", "pods", "services"},
+	"apps":        {"daemonsets", "deployments", "replicasets", "statefulsets"},
+	"batch":       {"cronjobs", "jobs"},
+	"autoscaling": {"horizontalpodautoscalers"},
+}
+
+func getInfrastructureExporterLabels(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/component": "exporter",
+		"app.kubernetes.io/name":      name,
+		"managed-by":                  "observability-operator",
+	}
+}
+
+func getInfrastructureExporterObjectMeta(cr *v1.Observability, name string) v12.ObjectMeta {
+	return v12.ObjectMeta{
+		Name:      name,
+		Namespace: cr.GetPrometheusOperatorNamespace(),
+		Labels:    getInfrastructureExporterLabels(name),
+	}
+}
+
+func GetKubeStateMetricsImage(cr *v1.Observability) string {
+	tag := KubeStateMetricsImageTag
+	if exporters := cr.Spec.SelfContained.InfrastructureExporters; exporters.KubeStateMetricsVersion != "" {
+		tag = exporters.KubeStateMetricsVersion
+	}
+	return GetImage(cr, v1.ImageKubeStateMetrics, KubeStateMetricsImage, tag)
+}
+
+func GetNodeExporterImage(cr *v1.Observability) string {
+	tag := NodeExporterImageTag
+	if exporters := cr.Spec.SelfContained.InfrastructureExporters; exporters.NodeExporterVersion != "" {
+		tag = exporters.NodeExporterVersion
+	}
+	return GetImage(cr, v1.ImageNodeExporter, NodeExporterImage, tag)
+}
+
+func GetKubeStateMetricsServiceAccount(cr *v1.Observability) *v14.ServiceAccount {
+	return &v14.ServiceAccount{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, KubeStateMetricsName),
+	}
+}
+
+// Cluster scoped, the namespace keeps the names of multiple instances apart
+func GetKubeStateMetricsClusterRole(cr *v1.Observability) *v15.ClusterRole {
+	return &v15.ClusterRole{
+		ObjectMeta: v12.ObjectMeta{
+			Name:   fmt.Sprintf("%v-%v", cr.GetPrometheusOperatorNamespace(), KubeStateMetricsName),
+			Labels: getInfrastructureExporterLabels(KubeStateMetricsName),
+		},
+	}
+}
+
+func GetKubeStateMetricsClusterRoleBinding(cr *v1.Observability) *v15.ClusterRoleBinding {
+	return &v15.ClusterRoleBinding{
+		ObjectMeta: v12.ObjectMeta{
+			Name:   fmt.Sprintf("%v-%v", cr.GetPrometheusOperatorNamespace(), KubeStateMetricsName),
+			Labels: getInfrastructureExporterLabels(KubeStateMetricsName),
+		},
+	}
+}
+
+func GetKubeStateMetricsClusterRoleRules() []v15.PolicyRule {
+	var rules []v15.PolicyRule
+	for _, group := range []string{"", "apps", "batch", "autoscaling"} {
+		rules = append(rules, v15.PolicyRule{
+			Verbs:     []string{"list", "watch"},
+			APIGroups: []string{group},
+			Resources: kubeStateMetricsResources[group],
+		})
+	}
+	return rules
+}
+
+func GetKubeStateMetricsDeployment(cr *v1.Observability) *v13.Deployment {
+	return &v13.Deployment{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, KubeStateMetricsName),
+	}
+}
+
+func GetKubeStateMetricsService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, KubeStateMetricsName),
+	}
+}
+
+// Only the resources it is allowed to watch are exposed
+func GetKubeStateMetricsContainer(cr *v1.Observability) v14.Container {
+	var resources []string
+	for _, groupResources := range kubeStateMetricsResources {
+		resources = append(resources, groupResources...)
+	}
+	sort.Strings(resources)
+
+	return v14.Container{
+		Name:  KubeStateMetricsName,
+		Image: GetKubeStateMetricsImage(cr),
+		Args: []string{
+			fmt.Sprintf("--port=%v", KubeStateMetricsPort),
+			"--telemetry-port=8081",
+			fmt.Sprintf("--resources=%v", strings.Join(resources, ",")),
+		},
+		Ports: []v14.ContainerPort{
+			{
+				Name:          "http-metrics",
+				ContainerPort: KubeStateMetricsPort,
+			},
+		},
+		SecurityContext: GetContainerSecurityContext(cr),
+	}
+}
+
+func GetNodeExporterServiceAccount(cr *v1.Observability) *v14.ServiceAccount {
+	return &v14.ServiceAccount{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, NodeExporterName),
+	}
+}
+
+func GetNodeExporterDaemonSet(cr *v1.Observability) *v13.DaemonSet {
+	return &v13.DaemonSet{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, NodeExporterName),
+	}
+}
+
+func GetNodeExporterService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: getInfrastructureExporterObjectMeta(cr, NodeExporterName),
+	}
+}
+
+// node-exporter runs in the network and pid namespace of the node and reads its filesystems
+// read only, which requires a privileged namespace like Promtail
+func GetNodeExporterPodSpec(cr *v1.Observability) v14.PodSpec {
+	hostPath := func(name string, path string) v14.Volume {
+		return v14.Volume{
+			Name: name,
+			VolumeSource: v14.VolumeSource{
+				HostPath: &v14.HostPathVolumeSource{
+					Path: path,
+				},
+			},
+		}
+	}
+	propagation := v14.MountPropagationHostToContainer
+
+	return v14.PodSpec{
+		ServiceAccountName: GetNodeExporterServiceAccount(cr).Name,
+		PriorityClassName:  ObservabilityPriorityClassName,
+		HostNetwork:        true,
+		HostPID:            true,
+		Tolerations: []v14.Toleration{
+			{
+				Operator: v14.TolerationOpExists,
+			},
+		},
+		Affinity: GetArchitectureAffinity(cr, nil, GetImageArchitectures(cr, v1.ImageNodeExporter, NodeExporterImage)),
+		Volumes: []v14.Volume{
+			hostPath("proc", "/proc"),
+			hostPath("sys", "/sys"),
+			hostPath("root", "/"),
+		},
+		Containers: []v14.Container{
+			{
+				Name:  NodeExporterName,
+				Image: GetNodeExporterImage(cr),
+				Args: []string{
+					fmt.Sprintf("--web.listen-address=:%v", NodeExporterPort),
+					"--path.procfs=/host/proc",
+					"--path.sysfs=/host/sys",
+					"--path.rootfs=/host/root",
+					"--collector.filesystem.mount-points-exclude=^/(dev|proc|sys|run/k3s/containerd/.+|var/lib/docker/.+|var/lib/kubelet/pods/.+)($|/)",
+				},
+				Ports: []v14.ContainerPort{
+					{
+						Name:          "http-metrics",
+						ContainerPort: NodeExporterPort,
+					},
+				},
+				SecurityContext: &v14.SecurityContext{
+					RunAsNonRoot:             &([]bool{true})[0],
+					RunAsUser:                &([]int64{nodeExporterRunAsUser})[0],
+					ReadOnlyRootFilesystem:   &([]bool{true})[0],
+					AllowPrivilegeEscalation: &([]bool{false})[0],
+					Capabilities: &v14.Capabilities{
+						Drop: []v14.Capability{"ALL"},
+					},
+				},
+				VolumeMounts: []v14.VolumeMount{
+					{
+						Name:      "proc",
+						MountPath: "/host/proc",
+						ReadOnly:  true,
+					},
+					{
+						Name:      "sys",
+						MountPath: "/host/sys",
+						ReadOnly:  true,
+					},
+					{
+						Name:             "root",
+						MountPath:        "/host/root",
+						ReadOnly:         true,
+						MountPropagation: &propagation,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Scrape configs of the exporters by their service endpoints. kube-state-metrics keeps the
+// labels of the objects, node-exporter series get the node name as instance.
+func GetInfrastructureExportersScrapeConfig(cr *v1.Observability) []byte {
+	const config = `
+- job_name: kube-state-metrics
+  honor_labels: true
+  kubernetes_sd_configs:
+    - role: endpoints
+      namespaces:
+        names:
+          - %[1]v
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_service_name', '__meta_kubernetes_endpoint_port_name' ]
+      regex: %[2]v;http-metrics
+- job_name: node-exporter
+  kubernetes_sd_configs:
+    - role: endpoints
+      namespaces:
+        names:
+          - %[1]v
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_service_name', '__meta_kubernetes_endpoint_port_name' ]
+      regex: %[3]v;http-metrics
+    - source_labels: [ '__meta_kubernetes_pod_node_name' ]
+      target_label: instance
+`
+	return []byte(fmt.Sprintf(config, cr.GetPrometheusOperatorNamespace(), KubeStateMetricsName, NodeExporterName))
+}
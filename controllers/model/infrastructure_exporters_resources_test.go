@@ -0,0 +1,46 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestInfrastructureExportersResources_GetInfrastructureExportersScrapeConfig(t *testing.T) {
+	RegisterTestingT(t)
+	tr := true
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.ClusterType = v1.ClusterTypeKubernetes
+		obsCR.Spec.SelfContained = &v1.SelfContained{
+			InfrastructureExporters: &v1.InfrastructureExporters{
+				Enabled:             &tr,
+				NodeExporterVersion: "v1.5.0",
+			},
+		}
+	})
+	Expect(cr.InfrastructureExportersEnabled()).To(BeTrue())
+
+	var scrapeConfigs []map[string]interface{}
+	Expect(yaml.Unmarshal(GetInfrastructureExportersScrapeConfig(cr), &scrapeConfigs)).To(Succeed())
+	Expect(scrapeConfigs).To(HaveLen(2))
+	Expect(scrapeConfigs[0]["job_name"]).To(Equal(KubeStateMetricsName))
+	Expect(scrapeConfigs[1]["job_name"]).To(Equal(NodeExporterName))
+
+	Expect(GetKubeStateMetricsImage(cr)).To(Equal(KubeStateMetricsImage + ":" + KubeStateMetricsImageTag))
+	Expect(GetNodeExporterImage(cr)).To(Equal(NodeExporterImage + ":v1.5.0"))
+
+	// kube-state-metrics only exposes the resources of its cluster role
+	var resources []string
+	for _, rule := range GetKubeStateMetricsClusterRoleRules() {
+		resources = append(resources, rule.Resources...)
+	}
+	args := GetKubeStateMetricsContainer(cr).Args
+	Expect(strings.Split(strings.TrimPrefix(args[len(args)-1], "--resources="), ",")).To(ConsistOf(resources))
+
+	// OpenShift has its own exporters
+	cr.Spec.ClusterType = v1.ClusterTypeOpenShift
+	Expect(cr.InfrastructureExportersEnabled()).To(BeFalse())
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func GetPrometheusIngress(cr *v1.Observability) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetDefaultNamePrometheus(cr),
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+func GetAlertmanagerIngress(cr *v1.Observability) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetDefaultNameAlertmanager(cr),
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// Host of the endpoint, empty if the component is not exposed
+func GetIngressHost(endpoint *v1.IngressEndpoint) string {
+	if endpoint == nil {
+		return ""
+	}
+	return endpoint.Host
+}
+
+// Routes all requests for the endpoint host to the named port of the service
+func GetIngressSpec(cr *v1.Observability, endpoint *v1.IngressEndpoint, serviceName string, servicePort string) networkingv1.IngressSpec {
+	pathType := networkingv1.PathTypePrefix
+	spec := networkingv1.IngressSpec{
+		Rules: []networkingv1.IngressRule{
+			{
+				Host: endpoint.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     "/",
+								PathType: &pathType,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: serviceName,
+										Port: networkingv1.ServiceBackendPort{
+											Name: servicePort,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if cr.Spec.Ingress.IngressClassName != "" {
+		spec.IngressClassName = &cr.Spec.Ingress.IngressClassName
+	}
+
+	if endpoint.TLSSecretName != "" {
+		spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{endpoint.Host},
+				SecretName: endpoint.TLSSecretName,
+			},
+		}
+	}
+
+	return spec
+}
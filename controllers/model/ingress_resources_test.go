@@ -0,0 +1,137 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestIngressResources_GetIngressSpec(t *testing.T) {
+	pathType := networkingv1.PathTypePrefix
+	ingressClassName := "nginx"
+	buildSpec := func(modifyFn func(spec *networkingv1.IngressSpec)) networkingv1.IngressSpec {
+		spec := networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "prometheus.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "prometheus",
+											Port: networkingv1.ServiceBackendPort{
+												Name: "web",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		if modifyFn != nil {
+			modifyFn(&spec)
+		}
+		return spec
+	}
+
+	type args struct {
+		cr       *v1.Observability
+		endpoint *v1.IngressEndpoint
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want networkingv1.IngressSpec
+	}{
+		{
+			name: "routes the host to the service",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.Ingress = &v1.IngressSpec{}
+				}),
+				endpoint: &v1.IngressEndpoint{
+					Host: "prometheus.example.com",
+				},
+			},
+			want: buildSpec(nil),
+		},
+		{
+			name: "sets ingress class and tls if configured",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.Ingress = &v1.IngressSpec{
+						IngressClassName: ingressClassName,
+					}
+				}),
+				endpoint: &v1.IngressEndpoint{
+					Host:          "prometheus.example.com",
+					TLSSecretName: "prometheus-tls",
+				},
+			},
+			want: buildSpec(func(spec *networkingv1.IngressSpec) {
+				spec.IngressClassName = &ingressClassName
+				spec.TLS = []networkingv1.IngressTLS{
+					{
+						Hosts:      []string{"prometheus.example.com"},
+						SecretName: "prometheus-tls",
+					},
+				}
+			}),
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetIngressSpec(tt.args.cr, tt.args.endpoint, "prometheus", "web")
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIngressResources_GetIngressHost(t *testing.T) {
+	type args struct {
+		endpoint *v1.IngressEndpoint
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "host of the endpoint",
+			args: args{
+				endpoint: &v1.IngressEndpoint{
+					Host: "grafana.example.com",
+				},
+			},
+			want: "grafana.example.com",
+		},
+		{
+			name: "empty if the component is not exposed",
+			args: args{
+				endpoint: nil,
+			},
+			want: "",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetIngressHost(tt.args.endpoint)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"embed"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Rules and dashboards shipped with the operator, bumped with every change of the library
+const LibraryVersion = "1.0.0"
+
+const (
+	LibraryVersionLabel = "observability.redhat.com/library-version"
+	libraryPrefix       = "library-"
+)
+
+//go:embed library
+var library embed.FS
+
+// Applied resources of the library share the prefix
+func IsLibraryResource(name string) bool {
+	return strings.HasPrefix(name, libraryPrefix)
+}
+
+func getLibraryName(file string) string {
+	return libraryPrefix + strings.TrimSuffix(file, path.Ext(file))
+}
+
+func GetLibraryRules(cr *v1.Observability) ([]*prometheusv1.PrometheusRule, error) {
+	files, err := library.ReadDir("library/rules")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*prometheusv1.PrometheusRule
+	for _, file := range files {
+		source, err := library.ReadFile(path.Join("library/rules", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		rule := &prometheusv1.PrometheusRule{}
+		err = yaml.Unmarshal(source, rule)
+		if err != nil {
+			return nil, err
+		}
+		rule.ObjectMeta = v12.ObjectMeta{
+			Name:      getLibraryName(file.Name()),
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		}
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+func GetLibraryDashboards(cr *v1.Observability) ([]*v1alpha1.GrafanaDashboard, error) {
+	files, err := library.ReadDir("library/dashboards")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*v1alpha1.GrafanaDashboard
+	for _, file := range files {
+		source, err := library.ReadFile(path.Join("library/dashboards", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &v1alpha1.GrafanaDashboard{
+			ObjectMeta: v12.ObjectMeta{
+				Name:      getLibraryName(file.Name()),
+				Namespace: cr.Namespace,
+			},
+			Spec: v1alpha1.GrafanaDashboardSpec{
+				Json: string(source),
+			},
+		})
+	}
+	return result, nil
+}
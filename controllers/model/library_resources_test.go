@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLibraryResources_GetLibraryResources(t *testing.T) {
+	RegisterTestingT(t)
+	cr := buildObservabilityCR(nil)
+
+	rules, err := GetLibraryRules(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(rules).To(HaveLen(4))
+	for _, rule := range rules {
+		Expect(IsLibraryResource(rule.Name)).To(BeTrue())
+		Expect(rule.Namespace).To(Equal(cr.GetPrometheusOperatorNamespace()))
+		Expect(rule.Spec.Groups).ToNot(BeEmpty())
+	}
+
+	dashboards, err := GetLibraryDashboards(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(dashboards).To(HaveLen(2))
+	for _, dashboard := range dashboards {
+		Expect(IsLibraryResource(dashboard.Name)).To(BeTrue())
+		Expect(json.Valid([]byte(dashboard.Spec.Json))).To(BeTrue())
+	}
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// Set on the aggregates of a class, the raw series of a class don't have the label
+	MetricClassLabel              = "metric_class"
+	defaultMetricClassSourceLabel = "__name__"
+	metricClassRelabelSeparator   = ";"
+)
+
+// Recording rules of the aggregations of all metric classes
+func GetMetricClassRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-metric-classes",
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+func getMetricClassSourceLabel(selector *v1.MetricClassSelector) string {
+	if selector.SourceLabel == "" {
+		return defaultMetricClassSourceLabel
+	}
+	return selector.SourceLabel
+}
+
+func ValidateMetricClass(class *v1.MetricClass) error {
+	if len(validation.IsDNS1123Label(class.Name)) > 0 {
+		return fmt.Errorf("invalid metric class name %v", class.Name)
+	}
+	if len(class.Selectors) == 0 {
+		return fmt.Errorf("metric class %v has no selectors", class.Name)
+	}
+	for _, selector := range class.Selectors {
+		label := getMetricClassSourceLabel(&selector)
+		if !commonmodel.LabelName(label).IsValid() || label == MetricClassLabel {
+			return fmt.Errorf("invalid source label %v in metric class %v", label, class.Name)
+		}
+		// Prometheus anchors relabel regexes
+		expr, err := regexp.Compile(fmt.Sprintf("^(?:%v)$", selector.Regex))
+		if err != nil {
+			return fmt.Errorf("invalid regex %v in metric class %v: %v", selector.Regex, class.Name, err)
+		}
+		// The relabel configs would drop all series without the label otherwise
+		if expr.MatchString("") {
+			return fmt.Errorf("regex %v in metric class %v matches the empty label value", selector.Regex, class.Name)
+		}
+	}
+
+	for i := range class.Aggregations {
+		if err := ValidateAggregation(&class.Aggregations[i]); err != nil {
+			return fmt.Errorf("metric class %v: %v", class.Name, err)
+		}
+	}
+	return nil
+}
+
+// One group per class, only valid classes are expected
+func GetMetricClassRuleGroup(class *v1.MetricClass) prometheusv1.RuleGroup {
+	group := GetAggregationRuleGroup(fmt.Sprintf("metric-class-%v", class.Name), class.Aggregations)
+	for i := range group.Rules {
+		group.Rules[i].Labels = map[string]string{
+			MetricClassLabel: class.Name,
+		}
+	}
+	return group
+}
+
+// Drops the series of the classes that are not remote written, the aggregates have the class label
+func GetMetricClassRelabelConfigs(classes []v1.MetricClass) []prometheusv1.RelabelConfig {
+	var result []prometheusv1.RelabelConfig
+	for _, class := range classes {
+		if class.RemoteWriteEnabled() {
+			continue
+		}
+		for _, selector := range class.Selectors {
+			result = append(result, prometheusv1.RelabelConfig{
+				SourceLabels: []prometheusv1.LabelName{MetricClassLabel, prometheusv1.LabelName(getMetricClassSourceLabel(&selector))},
+				Separator:    metricClassRelabelSeparator,
+				Regex:        fmt.Sprintf("%v(?:%v)", metricClassRelabelSeparator, selector.Regex),
+				Action:       "drop",
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func buildMetricClass() v1.MetricClass {
+	return v1.MetricClass{
+		Name: "histograms",
+		Selectors: []v1.MetricClassSelector{
+			{Regex: ".+_bucket"},
+			{SourceLabel: "job", Regex: "kube-state-metrics"},
+		},
+		Aggregations: []v1.Aggregation{
+			{Metric: "http_request_duration_seconds_bucket", By: []string{"namespace", "le"}, Rate: "5m"},
+		},
+	}
+}
+
+func TestMetricClassResources_ValidateMetricClass(t *testing.T) {
+	RegisterTestingT(t)
+
+	class := buildMetricClass()
+	Expect(ValidateMetricClass(&class)).To(Succeed())
+
+	for _, modify := range []func(class *v1.MetricClass){
+		func(class *v1.MetricClass) { class.Name = "Histograms" },
+		func(class *v1.MetricClass) { class.Selectors = nil },
+		func(class *v1.MetricClass) { class.Selectors[0].SourceLabel = "job-name" },
+		func(class *v1.MetricClass) { class.Selectors[0].SourceLabel = MetricClassLabel },
+		func(class *v1.MetricClass) { class.Selectors[0].Regex = "(unbalanced" },
+		func(class *v1.MetricClass) { class.Selectors[0].Regex = ".*" },
+		func(class *v1.MetricClass) { class.Aggregations[0].Operation = "median" },
+	} {
+		class := buildMetricClass()
+		modify(&class)
+		Expect(ValidateMetricClass(&class)).ToNot(Succeed(), "%+v", class)
+	}
+}
+
+func TestMetricClassResources_GetMetricClassResources(t *testing.T) {
+	RegisterTestingT(t)
+
+	class := buildMetricClass()
+	group := GetMetricClassRuleGroup(&class)
+	Expect(group.Name).To(Equal("metric-class-histograms-aggregations"))
+	Expect(group.Rules).To(HaveLen(1))
+	Expect(group.Rules[0].Record).To(Equal("namespace_le:http_request_duration_seconds_bucket:sum_rate5m"))
+	Expect(group.Rules[0].Labels).To(Equal(map[string]string{MetricClassLabel: "histograms"}))
+
+	// The aggregates match the selectors too, only series without the class label are dropped
+	Expect(GetMetricClassRelabelConfigs([]v1.MetricClass{class})).To(Equal([]monitoringv1.RelabelConfig{
+		{SourceLabels: []monitoringv1.LabelName{MetricClassLabel, "__name__"}, Separator: ";", Regex: ";(?:.+_bucket)", Action: "drop"},
+		{SourceLabels: []monitoringv1.LabelName{MetricClassLabel, "job"}, Separator: ";", Regex: ";(?:kube-state-metrics)", Action: "drop"},
+	}))
+
+	tr := true
+	class.RemoteWrite = &tr
+	Expect(GetMetricClassRelabelConfigs([]v1.MetricClass{class})).To(BeEmpty())
+}
@@ -0,0 +1,31 @@
+package model
+
+import "fmt"
+
+const (
+	OperatorScrapeJobName = "observability-operator"
+	// Named port of the operator metrics endpoint, see config/manager/manager.yaml
+	operatorMetricsPortName = "metrics"
+)
+
+// The operator reports the results of the synthetic checks and the expiry of the managed
+// certificates on its metrics endpoint, only the leader runs them. Its other metrics are not kept.
+func GetOperatorScrapeConfig(operatorNamespace string) []byte {
+	return []byte(fmt.Sprintf(`
+- job_name: %[1]v
+  honor_labels: true
+  kubernetes_sd_configs:
+    - role: pod
+      namespaces:
+        names:
+          - %[2]v
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_pod_label_control_plane', '__meta_kubernetes_pod_container_port_name' ]
+      regex: controller-manager;%[3]v
+  metric_relabel_configs:
+    - source_labels: [ __name__ ]
+      regex: synthetic_check_.+|observability_cert_expiry_timestamp_seconds
+      action: keep
+`, OperatorScrapeJobName, operatorNamespace, operatorMetricsPortName))
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/gomega"
+)
+
+func TestOperatorResources_GetOperatorScrapeConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	var scrapeConfigs []map[string]interface{}
+	Expect(yaml.Unmarshal(GetOperatorScrapeConfig("observability-operator"), &scrapeConfigs)).To(Succeed())
+	Expect(scrapeConfigs).To(HaveLen(1))
+	Expect(scrapeConfigs[0]["job_name"]).To(Equal(OperatorScrapeJobName))
+	Expect(scrapeConfigs[0]["kubernetes_sd_configs"]).To(ContainElement(HaveKeyWithValue("namespaces", map[string]interface{}{
+		"names": []interface{}{"observability-operator"},
+	})))
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/blang/semver"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	PrometheusFeatureExemplarStorage  = "exemplar-storage"
+	PrometheusFeatureNativeHistograms = "native-histograms"
+	// Switches Prometheus to agent mode, which the operator doesn't manage
+	prometheusFeatureAgent = "agent"
+)
+
+// First Prometheus version of the known feature flags, unknown flags are passed through
+var prometheusFeatureVersions = map[string]string{
+	PrometheusFeatureExemplarStorage:  "2.26.0",
+	PrometheusFeatureNativeHistograms: "2.40.0",
+	"memory-snapshot-on-shutdown":     "2.30.0",
+	"new-service-discovery-manager":   "2.37.0",
+	"promql-at-modifier":              "2.25.0",
+	"promql-negative-offset":          "2.26.0",
+}
+
+var prometheusFeatureName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Versions that can't be parsed, e.g. of image overrides, are expected to support the feature
+func isPrometheusFeatureSupported(feature string, version string) bool {
+	minVersion, ok := prometheusFeatureVersions[feature]
+	if !ok {
+		return true
+	}
+	current, err := semver.ParseTolerant(version)
+	if err != nil {
+		return true
+	}
+	return current.GTE(semver.MustParse(minVersion))
+}
+
+func validatePrometheusFeature(feature string, version string) error {
+	if !prometheusFeatureName.MatchString(feature) {
+		return fmt.Errorf("invalid prometheus feature %v", feature)
+	}
+	if feature == prometheusFeatureAgent {
+		return fmt.Errorf("prometheus feature %v is not supported", feature)
+	}
+	if !isPrometheusFeatureSupported(feature, version) {
+		return fmt.Errorf("prometheus %v doesn't support %v, it requires %v or later", version, feature, prometheusFeatureVersions[feature])
+	}
+	return nil
+}
+
+// Feature flags of the managed Prometheus, along with the errors of the features that are invalid or
+// that the version doesn't support
+func GetPrometheusEnableFeatures(cr *v1.Observability, version string) ([]string, []error) {
+	var requested []string
+	if cr.ExemplarsEnabled() {
+		requested = append(requested, PrometheusFeatureExemplarStorage)
+	}
+	if cr.NativeHistogramsEnabled() {
+		requested = append(requested, PrometheusFeatureNativeHistograms)
+	}
+	if cr.Spec.SelfContained != nil {
+		requested = append(requested, cr.Spec.SelfContained.PrometheusEnableFeatures...)
+	}
+
+	var result []string
+	var errs []error
+	seen := map[string]bool{}
+	for _, feature := range requested {
+		if seen[feature] {
+			continue
+		}
+		seen[feature] = true
+		if err := validatePrometheusFeature(feature, version); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result = append(result, feature)
+	}
+	return result, errs
+}
+
+// Nil keeps the default size of Prometheus
+func GetPrometheusExemplars(cr *v1.Observability) *prometheusv1.Exemplars {
+	if !cr.ExemplarsEnabled() || cr.Spec.SelfContained.PrometheusExemplars.MaxSize == nil {
+		return nil
+	}
+	return &prometheusv1.Exemplars{
+		MaxSize: cr.Spec.SelfContained.PrometheusExemplars.MaxSize,
+	}
+}
+
+// Exemplars are sent to all remote write targets once they are stored
+func ApplyRemoteWriteExemplars(features []string, remoteWrites []prometheusv1.RemoteWriteSpec) {
+	for _, feature := range features {
+		if feature != PrometheusFeatureExemplarStorage {
+			continue
+		}
+		send := true
+		for i := range remoteWrites {
+			remoteWrites[i].SendExemplars = &send
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func buildPrometheusFeaturesCR(maxSize *int64) *v1.Observability {
+	tr := true
+	return buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.SelfContained = &v1.SelfContained{
+			PrometheusExemplars: &v1.PrometheusExemplars{
+				Enabled: &tr,
+				MaxSize: maxSize,
+			},
+			PrometheusNativeHistograms: &tr,
+		}
+	})
+}
+
+func TestPrometheusFeaturesResources_GetPrometheusEnableFeatures(t *testing.T) {
+	RegisterTestingT(t)
+
+	features, errs := GetPrometheusEnableFeatures(buildObservabilityCR(nil), PrometheusVersion)
+	Expect(features).To(BeEmpty())
+	Expect(errs).To(BeEmpty())
+
+	features, errs = GetPrometheusEnableFeatures(buildPrometheusFeaturesCR(nil), "v2.41.0")
+	Expect(features).To(Equal([]string{PrometheusFeatureExemplarStorage, PrometheusFeatureNativeHistograms}))
+	Expect(errs).To(BeEmpty())
+
+	features, errs = GetPrometheusEnableFeatures(buildPrometheusFeaturesCR(nil), "v2.36.2")
+	Expect(features).To(Equal([]string{PrometheusFeatureExemplarStorage}))
+	Expect(errs).To(HaveLen(1))
+	Expect(errs[0].Error()).To(ContainSubstring("native-histograms"))
+
+	features, errs = GetPrometheusEnableFeatures(buildPrometheusFeaturesCR(nil), "latest")
+	Expect(features).To(HaveLen(2))
+	Expect(errs).To(BeEmpty())
+}
+
+func TestPrometheusFeaturesResources_GetPrometheusEnableFeaturesPassthrough(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildPrometheusFeaturesCR(nil)
+	cr.Spec.SelfContained.PrometheusEnableFeatures = []string{
+		"memory-snapshot-on-shutdown",
+		"exemplar-storage",
+		"some-future-feature",
+		"new-service-discovery-manager",
+		"agent",
+		"a,b",
+	}
+
+	features, errs := GetPrometheusEnableFeatures(cr, "v2.36.2")
+	Expect(features).To(Equal([]string{PrometheusFeatureExemplarStorage, "memory-snapshot-on-shutdown", "some-future-feature"}))
+	Expect(errs).To(HaveLen(4))
+	Expect(errs[1].Error()).To(ContainSubstring("new-service-discovery-manager"))
+	Expect(errs[2].Error()).To(ContainSubstring("agent"))
+	Expect(errs[3].Error()).To(ContainSubstring("a,b"))
+}
+
+func TestPrometheusFeaturesResources_GetPrometheusExemplars(t *testing.T) {
+	RegisterTestingT(t)
+
+	var maxSize int64 = 200000
+	Expect(GetPrometheusExemplars(buildObservabilityCR(nil))).To(BeNil())
+	Expect(GetPrometheusExemplars(buildPrometheusFeaturesCR(nil))).To(BeNil())
+	Expect(*GetPrometheusExemplars(buildPrometheusFeaturesCR(&maxSize)).MaxSize).To(Equal(maxSize))
+}
+
+func TestPrometheusFeaturesResources_ApplyRemoteWriteExemplars(t *testing.T) {
+	RegisterTestingT(t)
+
+	remoteWrites := []prometheusv1.RemoteWriteSpec{{URL: "https://a"}, {URL: "https://b"}}
+	ApplyRemoteWriteExemplars([]string{PrometheusFeatureNativeHistograms}, remoteWrites)
+	Expect(remoteWrites[0].SendExemplars).To(BeNil())
+
+	ApplyRemoteWriteExemplars([]string{PrometheusFeatureExemplarStorage}, remoteWrites)
+	Expect(*remoteWrites[0].SendExemplars).To(BeTrue())
+	Expect(*remoteWrites[1].SendExemplars).To(BeTrue())
+}
@@ -4,9 +4,15 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 	t "text/template"
+	"time"
 
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -16,7 +22,9 @@ import (
 	coreosv1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	v13 "k8s.io/api/core/v1"
 	v14 "k8s.io/api/rbac/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,6 +36,9 @@ const (
 	PrometheusVersion        = "v2.36.2"
 	PrometheusDefaultStorage = "250Gi"
 	PrometheusOldDefaultName = "kafka-prometheus"
+
+	// Namespaces with this annotation set to true are scraped with namespace discovery
+	NamespaceDiscoveryAnnotation = "observability.redhat.com/scrape"
 )
 
 func GetPrometheusNamespace(cr *v1.Observability) *v13.Namespace {
@@ -123,6 +134,87 @@ func GetPrometheusService(cr *v1.Observability) *v13.Service {
 	}
 }
 
+// Endpoint of the Prometheus server, bypassing the oauth proxy
+func GetPrometheusUpstreamUrl(cr *v1.Observability) string {
+	if cr.ExternalPrometheusEnabled() {
+		external := cr.Spec.ExternalPrometheus
+		if external.Url != "" {
+			return strings.TrimSuffix(external.Url, "/")
+		}
+		return fmt.Sprintf("http://prometheus-operated.%v.svc:9090", GetExternalPrometheus(cr).Namespace)
+	}
+	service := GetPrometheusService(cr)
+	return fmt.Sprintf("%v://%v.%v.svc:9090", GetPrometheusScheme(cr), service.Name, service.Namespace)
+}
+
+// Prometheus serves HTTPS on its web port when it serves TLS itself
+func GetPrometheusScheme(cr *v1.Observability) string {
+	if cr.PrometheusWebTLSEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// Secret of the web TLS certificate, the one of the service CA unless another is set
+func GetPrometheusWebTLSSecretName(cr *v1.Observability) string {
+	if cr.Spec.PrometheusWebTLS != nil && cr.Spec.PrometheusWebTLS.SecretName != "" {
+		return cr.Spec.PrometheusWebTLS.SecretName
+	}
+	return GetPrometheusTLSSecret(cr).Name
+}
+
+// Serving certificate of the web endpoint, nil unless Prometheus serves TLS itself. In FIPS mode only
+// the FIPS approved cipher suites are served.
+func GetPrometheusWebSpec(cr *v1.Observability) (*prometheusv1.PrometheusWebSpec, error) {
+	if !cr.PrometheusWebTLSEnabled() {
+		return nil, nil
+	}
+	if cr.IsKubernetesCluster() && !cr.CertManagerEnabled() && cr.Spec.PrometheusWebTLS.SecretName == "" {
+		return nil, errors.New("the secret of the prometheus web tls certificate is required on kubernetes without cert-manager")
+	}
+
+	secretName := GetPrometheusWebTLSSecretName(cr)
+	config := &prometheusv1.WebTLSConfig{
+		KeySecret: v13.SecretKeySelector{
+			LocalObjectReference: v13.LocalObjectReference{Name: secretName},
+			Key:                  "tls.key",
+		},
+		Cert: prometheusv1.SecretOrConfigMap{
+			Secret: &v13.SecretKeySelector{
+				LocalObjectReference: v13.LocalObjectReference{Name: secretName},
+				Key:                  "tls.crt",
+			},
+		},
+		MinVersion: cr.Spec.PrometheusWebTLS.MinVersion,
+	}
+	if cr.FIPSModeEnabled() {
+		if config.MinVersion == "" {
+			config.MinVersion = "TLS12"
+		}
+		for _, suite := range FIPSCipherSuites {
+			config.CipherSuites = append(config.CipherSuites, tls.CipherSuiteName(suite))
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &prometheusv1.PrometheusWebSpec{TLSConfig: config}, nil
+}
+
+// Reference to the external Prometheus, only read by the operator
+func GetExternalPrometheus(cr *v1.Observability) *prometheusv1.Prometheus {
+	namespace := cr.Spec.ExternalPrometheus.Namespace
+	if namespace == "" {
+		namespace = cr.GetPrometheusOperatorNamespace()
+	}
+	return &prometheusv1.Prometheus{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      cr.Spec.ExternalPrometheus.Name,
+			Namespace: namespace,
+		},
+	}
+}
+
 func GetPrometheusClusterRole(cr *v1.Observability) *v14.ClusterRole {
 	return &v14.ClusterRole{
 		ObjectMeta: v12.ObjectMeta{
@@ -139,6 +231,73 @@ func GetPrometheusClusterRoleBinding(cr *v1.Observability) *v14.ClusterRoleBindi
 	}
 }
 
+// Used instead of the cluster role when running namespace scoped, one per target namespace
+func GetPrometheusRole(cr *v1.Observability, namespace string) *v14.Role {
+	return &v14.Role{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      GetDefaultNamePrometheus(cr),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+func GetPrometheusRoleBinding(cr *v1.Observability, namespace string) *v14.RoleBinding {
+	return &v14.RoleBinding{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      GetDefaultNamePrometheus(cr),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// Namespaces watched by Prometheus when running namespace scoped, starting with its own namespace
+func GetTargetNamespaces(cr *v1.Observability) []string {
+	result := []string{cr.GetPrometheusOperatorNamespace()}
+	seen := map[string]bool{result[0]: true}
+	namespaces := append([]string{}, cr.Spec.TargetNamespaces...)
+	// Tenants extend the target namespaces, the whole cluster is selected already otherwise
+	if cr.NamespaceScoped() {
+		for _, tenant := range cr.Status.Tenants {
+			namespaces = append(namespaces, tenant.Namespaces...)
+		}
+	}
+	for _, namespace := range namespaces {
+		if !seen[namespace] {
+			seen[namespace] = true
+			result = append(result, namespace)
+		}
+	}
+	return result
+}
+
+// Selects the target namespaces by the name label that Kubernetes sets on every namespace
+func GetTargetNamespaceSelector(cr *v1.Observability) *v12.LabelSelector {
+	return GetNamespaceNameSelector(GetTargetNamespaces(cr))
+}
+
+func GetNamespaceNameSelector(namespaces []string) *v12.LabelSelector {
+	return &v12.LabelSelector{
+		MatchExpressions: []v12.LabelSelectorRequirement{
+			{
+				Key:      "kubernetes.io/metadata.name",
+				Operator: v12.LabelSelectorOpIn,
+				Values:   namespaces,
+			},
+		},
+	}
+}
+
+// Whether the namespace opted in to namespace discovery
+func IsDiscoveredNamespace(namespace *v13.Namespace) bool {
+	return namespace.Annotations[NamespaceDiscoveryAnnotation] == "true"
+}
+
 func GetPrometheusRoute(cr *v1.Observability) *routev1.Route {
 	return &routev1.Route{
 		ObjectMeta: v12.ObjectMeta{
@@ -148,6 +307,8 @@ func GetPrometheusRoute(cr *v1.Observability) *routev1.Route {
 	}
 }
 
+// Federate from openshift-monitoring with the token of the Prometheus service account. The certificate
+// of the federate endpoint is issued by the service CA, which OpenShift mounts next to the token.
 func GetFederationConfigBearerToken(patterns []string) ([]byte, error) {
 	const config = `
 - job_name: openshift-monitoring-federation
@@ -170,17 +331,380 @@ func GetFederationConfigBearerToken(patterns []string) ([]byte, error) {
   params:
     match[]: [{{ .Patterns }}]
   scheme: https
-  bearer_token_file: "/var/run/secrets/kubernetes.io/serviceaccount/token"
+  bearer_token_file: "{{ .TokenFile }}"
+  tls_config:
+    ca_file: "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+`
+
+	return executeFederationTemplate(config, patterns)
+}
+
+// Federate from the Prometheus of a kube-prometheus stack, which serves plain http in the monitoring namespace
+func GetFederationConfigKubePrometheus(patterns []string) ([]byte, error) {
+	const config = `
+- job_name: kube-prometheus-federation
+  honor_labels: true
+  kubernetes_sd_configs:
+    - role: service
+      namespaces:
+        names:
+          - monitoring
+  scrape_interval: 120s
+  scrape_timeout: 60s
+  metrics_path: /federate
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_service_name' ]
+      regex: prometheus-k8s
+    - action: keep
+      source_labels: [ '__meta_kubernetes_service_port_name' ]
+      regex: web
+  params:
+    match[]: [{{ .Patterns }}]
+  scheme: http
+`
+
+	return executeFederationTemplate(config, patterns)
+}
+
+// Scrape the kubelets and their cAdvisor through the node proxy of the API server, which is trusted by
+// the service account CA. Unused high cardinality cAdvisor series are dropped.
+func GetKubeletScrapeConfig() []byte {
+	const config = `
+- job_name: kubelet
+  honor_labels: true
+  scheme: https
+  tls_config:
+    ca_file: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+  bearer_token_file: /var/run/secrets/kubernetes.io/serviceaccount/token
+  kubernetes_sd_configs:
+    - role: node
+  relabel_configs:
+    - target_label: __address__
+      replacement: kubernetes.default.svc:443
+    - source_labels: [ '__meta_kubernetes_node_name' ]
+      regex: (.+)
+      target_label: __metrics_path__
+      replacement: /api/v1/nodes/${1}/proxy/metrics
+    - source_labels: [ '__meta_kubernetes_node_name' ]
+      target_label: node
+- job_name: kubelet-cadvisor
+  honor_labels: true
+  honor_timestamps: false
+  scheme: https
+  tls_config:
+    ca_file: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+  bearer_token_file: /var/run/secrets/kubernetes.io/serviceaccount/token
+  kubernetes_sd_configs:
+    - role: node
+  relabel_configs:
+    - target_label: __address__
+      replacement: kubernetes.default.svc:443
+    - source_labels: [ '__meta_kubernetes_node_name' ]
+      regex: (.+)
+      target_label: __metrics_path__
+      replacement: /api/v1/nodes/${1}/proxy/metrics/cadvisor
+    - source_labels: [ '__meta_kubernetes_node_name' ]
+      target_label: node
+  metric_relabel_configs:
+    - action: drop
+      source_labels: [ '__name__' ]
+      regex: container_(network_tcp_usage_total|network_udp_usage_total|tasks_state|cpu_load_average_10s|memory_failures_total)
+`
+	return []byte(config)
+}
+
+// Replaces the openshift-monitoring federation on clusters with a hosted control plane, empty
+// if federation is skipped
+func GetHostedControlPlaneFederationUpstreams(cr *v1.Observability, patterns []string) []v1.FederationUpstream {
+	if !cr.IsHostedControlPlane() || cr.Spec.HostedControlPlane == nil || cr.Spec.HostedControlPlane.FederationUrl == "" {
+		return nil
+	}
+
+	// The patterns of the indexes are quoted already
+	var match []string
+	for _, pattern := range patterns {
+		match = append(match, strings.Trim(pattern, "'"))
+	}
+
+	return []v1.FederationUpstream{
+		{
+			Name:               "hosted-control-plane",
+			Url:                cr.Spec.HostedControlPlane.FederationUrl,
+			AuthSecret:         cr.Spec.HostedControlPlane.AuthSecret,
+			Match:              match,
+			InsecureSkipVerify: cr.Spec.HostedControlPlane.InsecureSkipVerify,
+		},
+	}
+}
+
+// Federate from each upstream Prometheus with its own scrape job
+func GetFederationConfigUpstreams(upstreams []v1.FederationUpstream) ([]byte, error) {
+	const config = `{{- range . }}
+- job_name: federation-{{ .Name }}
+  honor_labels: {{ .HonorLabels }}
+  static_configs:
+    - targets: [ '{{ .Host }}' ]
+  scrape_interval: 120s
+  scrape_timeout: 60s
+  metrics_path: {{ .Path }}
+  params:
+    match[]: [{{ .Patterns }}]
+  scheme: {{ .Scheme }}
+{{- if .TokenFile }}
+  bearer_token_file: "{{ .TokenFile }}"
+{{- end }}
   tls_config:
-    insecure_skip_verify: true
+    insecure_skip_verify: {{ .InsecureSkipVerify }}
+{{- end }}
 `
 
+	type job struct {
+		Name               string
+		HonorLabels        bool
+		Host               string
+		Path               string
+		Patterns           string
+		Scheme             string
+		TokenFile          string
+		InsecureSkipVerify bool
+	}
+
+	var jobs []job
+	for i := range upstreams {
+		upstream := &upstreams[i]
+		upstreamUrl, err := url.Parse(upstream.Url)
+		if err != nil {
+			return nil, err
+		}
+		if upstreamUrl.Host == "" {
+			return nil, fmt.Errorf("federation upstream %v has no host in url %v", upstream.Name, upstream.Url)
+		}
+
+		var patterns []string
+		for _, pattern := range upstream.Match {
+			patterns = append(patterns, fmt.Sprintf("'%s'", pattern))
+		}
+
+		tokenFile := ""
+		if upstream.AuthSecret != "" {
+			tokenFile = fmt.Sprintf("/etc/prometheus/secrets/%s/token", upstream.AuthSecret)
+		}
+
+		jobs = append(jobs, job{
+			Name:               upstream.Name,
+			HonorLabels:        upstream.HonorLabelsEnabled(),
+			Host:               upstreamUrl.Host,
+			Path:               strings.TrimSuffix(upstreamUrl.Path, "/") + "/federate",
+			Patterns:           strings.Join(patterns, ","),
+			Scheme:             upstreamUrl.Scheme,
+			TokenFile:          tokenFile,
+			InsecureSkipVerify: upstream.InsecureSkipVerifyEnabled(),
+		})
+	}
+
+	template := t.Must(t.New("template").Parse(config))
+	var buffer bytes.Buffer
+	err := template.Execute(&buffer, jobs)
+	return buffer.Bytes(), err
+}
+
+// Key of the alertmanager_config entries of the external Alertmanagers in the additional scrape config secret
+const AdditionalAlertmanagerConfigKey = "additional-alertmanager-config.yaml"
+
+// Key of the alert relabel configs of the indexes in the additional scrape config secret
+const AdditionalAlertRelabelConfigKey = "additional-alert-relabel-config.yaml"
+
+// Prometheus alertmanager_config entries of the external Alertmanagers
+func GetExternalAlertmanagersConfig(alertmanagers []v1.ExternalAlertmanager) ([]byte, error) {
+	const config = `{{- range . }}
+- scheme: {{ .Scheme }}
+  path_prefix: {{ .PathPrefix }}
+  api_version: {{ .ApiVersion }}
+  timeout: {{ .Timeout }}
+  static_configs:
+    - targets: [ {{ .Targets }} ]
+{{- if .TokenFile }}
+  bearer_token_file: "{{ .TokenFile }}"
+{{- end }}
+  tls_config:
+    insecure_skip_verify: {{ .InsecureSkipVerify }}
+{{- end }}
+`
+
+	type alertmanagerConfig struct {
+		Scheme             string
+		PathPrefix         string
+		ApiVersion         string
+		Timeout            string
+		Targets            string
+		TokenFile          string
+		InsecureSkipVerify bool
+	}
+
+	var configs []alertmanagerConfig
+	for i := range alertmanagers {
+		alertmanager := &alertmanagers[i]
+		if len(alertmanager.Urls) == 0 {
+			return nil, fmt.Errorf("external alertmanager %v has no urls", alertmanager.Name)
+		}
+
+		var scheme, pathPrefix string
+		var targets []string
+		for j, alertmanagerUrl := range alertmanager.Urls {
+			parsed, err := url.Parse(alertmanagerUrl)
+			if err != nil {
+				return nil, err
+			}
+			if parsed.Host == "" {
+				return nil, fmt.Errorf("external alertmanager %v has no host in url %v", alertmanager.Name, alertmanagerUrl)
+			}
+			path := strings.TrimSuffix(parsed.Path, "/")
+			if j > 0 && (parsed.Scheme != scheme || path != pathPrefix) {
+				return nil, fmt.Errorf("urls of external alertmanager %v differ in scheme or path", alertmanager.Name)
+			}
+			scheme = parsed.Scheme
+			pathPrefix = path
+			targets = append(targets, fmt.Sprintf("'%s'", parsed.Host))
+		}
+		if pathPrefix == "" {
+			pathPrefix = "/"
+		}
+
+		apiVersion := alertmanager.ApiVersion
+		if apiVersion == "" {
+			apiVersion = "v2"
+		}
+		timeout := alertmanager.Timeout
+		if parsed, err := time.ParseDuration(timeout); err != nil || parsed <= 0 {
+			timeout = "10s"
+		}
+
+		tokenFile := ""
+		if alertmanager.AuthSecret != "" {
+			tokenFile = fmt.Sprintf("/etc/prometheus/secrets/%s/token", alertmanager.AuthSecret)
+		}
+
+		configs = append(configs, alertmanagerConfig{
+			Scheme:             scheme,
+			PathPrefix:         pathPrefix,
+			ApiVersion:         apiVersion,
+			Timeout:            timeout,
+			Targets:            strings.Join(targets, ", "),
+			TokenFile:          tokenFile,
+			InsecureSkipVerify: alertmanager.InsecureSkipVerifyEnabled(),
+		})
+	}
+
+	template := t.Must(t.New("template").Parse(config))
+	var buffer bytes.Buffer
+	err := template.Execute(&buffer, configs)
+	return buffer.Bytes(), err
+}
+
+func GetPrometheusStaticTargetsConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "prometheus-static-targets",
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+func getStaticTargetsFileName(group *v1.StaticTargetGroup) string {
+	return fmt.Sprintf("%s.json", group.Name)
+}
+
+// file_sd file contents per target group, keyed by file name
+func GetStaticTargetsFileSD(groups []v1.StaticTargetGroup) (map[string]string, error) {
+	type fileSDGroup struct {
+		Targets []string          `json:"targets"`
+		Labels  map[string]string `json:"labels,omitempty"`
+	}
+
+	result := map[string]string{}
+	for i := range groups {
+		group := &groups[i]
+		content, err := json.Marshal([]fileSDGroup{
+			{
+				Targets: group.Targets,
+				Labels:  group.Labels,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		result[getStaticTargetsFileName(group)] = string(content)
+	}
+	return result, nil
+}
+
+// A scrape job per target group, reading the targets from the static targets ConfigMap
+// that the Prometheus operator mounts to /etc/prometheus/configmaps
+func GetStaticTargetsScrapeConfig(cr *v1.Observability, groups []v1.StaticTargetGroup) ([]byte, error) {
+	const config = `{{- range . }}
+- job_name: static-{{ .Name }}
+  metrics_path: {{ .MetricsPath }}
+  scheme: {{ .Scheme }}
+{{- .TLSConfig }}
+  file_sd_configs:
+    - files: [ '{{ .File }}' ]
+{{- end }}
+`
+
+	type job struct {
+		Name        string
+		MetricsPath string
+		Scheme      string
+		TLSConfig   string
+		File        string
+	}
+
+	configMap := GetPrometheusStaticTargetsConfigMap(cr)
+	var jobs []job
+	for i := range groups {
+		group := &groups[i]
+		metricsPath := group.MetricsPath
+		if metricsPath == "" {
+			metricsPath = "/metrics"
+		}
+		scheme := group.Scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		tlsConfig := ""
+		if IsServiceMeshScrape(cr, group) {
+			scheme = "https"
+			tlsConfig = GetServiceMeshScrapeTLSConfig()
+		}
+
+		jobs = append(jobs, job{
+			Name:        group.Name,
+			MetricsPath: metricsPath,
+			Scheme:      scheme,
+			TLSConfig:   tlsConfig,
+			File:        fmt.Sprintf("/etc/prometheus/configmaps/%s/%s", configMap.Name, getStaticTargetsFileName(group)),
+		})
+	}
+
+	template := t.Must(t.New("template").Parse(config))
+	var buffer bytes.Buffer
+	err := template.Execute(&buffer, jobs)
+	return buffer.Bytes(), err
+}
+
+func executeFederationTemplate(config string, patterns []string) ([]byte, error) {
 	template := t.Must(t.New("template").Parse(config))
 	var buffer bytes.Buffer
 	err := template.Execute(&buffer, struct {
-		Patterns string
+		Patterns  string
+		TokenFile string
 	}{
-		Patterns: strings.Join(patterns, ","),
+		Patterns:  strings.Join(patterns, ","),
+		TokenFile: GetServiceAccountTokenFile(PrometheusTokenVolume),
 	})
 
 	return buffer.Bytes(), err
@@ -290,6 +814,41 @@ func GetPrometheus(cr *v1.Observability) *prometheusv1.Prometheus {
 	}
 }
 
+// Runs the new version during a blue/green upgrade
+func GetPrometheusCandidate(cr *v1.Observability) *prometheusv1.Prometheus {
+	return &prometheusv1.Prometheus{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("%v-next", GetDefaultNamePrometheus(cr)),
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// Created by the Prometheus operator for the Prometheus with the given name
+func GetPrometheusStatefulSet(cr *v1.Observability, name string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("prometheus-%v", name),
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// Prometheus that the service points at, the candidate while the Prometheus of the CR is upgraded in place
+func GetPrometheusServingName(cr *v1.Observability, upgrade *v1.PrometheusUpgradeStatus) string {
+	if upgrade != nil && upgrade.Phase == v1.PrometheusUpgradeSwitched {
+		return GetPrometheusCandidate(cr).Name
+	}
+	return GetDefaultNamePrometheus(cr)
+}
+
+// Claims of the Prometheus statefulset are named <template>-prometheus-<name>-<ordinal>. The template
+// name depends on the storage settings, so only the name of Prometheus and the ordinal are matched.
+func IsPrometheusVolumeClaimName(cr *v1.Observability, name string) bool {
+	pattern := fmt.Sprintf("^.+-prometheus-%v-[0-9]+$", regexp.QuoteMeta(GetDefaultNamePrometheus(cr)))
+	return regexp.MustCompile(pattern).MatchString(name)
+}
+
 func GetDeadmansSwitch(cr *v1.Observability) *prometheusv1.PrometheusRule {
 	return &prometheusv1.PrometheusRule{
 		ObjectMeta: v12.ObjectMeta{
@@ -301,6 +860,62 @@ func GetDeadmansSwitch(cr *v1.Observability) *prometheusv1.PrometheusRule {
 
 // Label Selectors
 
+// Labels that satisfy the selector, for the resources generated by the operator. In expressions take
+// their first value and Exists an empty one, NotIn and DoesNotExist are met by leaving the label out.
+func GetSelectorLabels(selector *v12.LabelSelector) map[string]string {
+	if selector == nil {
+		return nil
+	}
+
+	labels := make(map[string]string)
+	for k, v := range selector.MatchLabels {
+		labels[k] = v
+	}
+	for _, expression := range selector.MatchExpressions {
+		if _, ok := labels[expression.Key]; ok {
+			continue
+		}
+		switch expression.Operator {
+		case v12.LabelSelectorOpIn:
+			if len(expression.Values) > 0 {
+				labels[expression.Key] = expression.Values[0]
+			}
+		case v12.LabelSelectorOpExists:
+			labels[expression.Key] = ""
+		}
+	}
+	return labels
+}
+
+// Invalid selectors of the index would be rejected by the Prometheus Operator
+func ValidateIndexSelectors(index *v1.RepositoryIndex) error {
+	if index.Config == nil || index.Config.Prometheus == nil {
+		return nil
+	}
+
+	config := index.Config.Prometheus
+	selectors := []struct {
+		name     string
+		selector *v12.LabelSelector
+	}{
+		{"podMonitorLabelSelector", config.PodMonitorLabelSelector},
+		{"podMonitorNamespaceSelector", config.PodMonitorNamespaceSelector},
+		{"serviceMonitorLabelSelector", config.ServiceMonitorLabelSelector},
+		{"serviceMonitorNamespaceSelector", config.ServiceMonitorNamespaceSelector},
+		{"ruleLabelSelector", config.RuleLabelSelector},
+		{"ruleNamespaceSelector", config.RuleNamespaceSelector},
+		{"probeSelector", config.ProbeLabelSelector},
+		{"probeNamespaceSelector", config.ProbeNamespaceSelector},
+	}
+	for _, s := range selectors {
+		_, err := v12.LabelSelectorAsSelector(s.selector)
+		if err != nil {
+			return fmt.Errorf("invalid %v: %v", s.name, err)
+		}
+	}
+	return nil
+}
+
 func GetPrometheusPodMonitorLabelSelectors(cr *v1.Observability, indexes []v1.RepositoryIndex) *v12.LabelSelector {
 	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.PodMonitorLabelSelector != nil {
 		return cr.Spec.SelfContained.PodMonitorLabelSelector
@@ -384,6 +999,10 @@ func GetPrometheusPodMonitorNamespaceSelectors(cr *v1.Observability, indexes []v
 		return cr.Spec.SelfContained.PodMonitorNamespaceSelector
 	}
 
+	if cr.NamespaceScoped() {
+		return GetTargetNamespaceSelector(cr)
+	}
+
 	if cr.OverrideSelectors() && cr.Spec.SelfContained.PodMonitorNamespaceSelector == nil {
 		return &v12.LabelSelector{}
 	}
@@ -400,6 +1019,10 @@ func GetPrometheusServiceMonitorNamespaceSelectors(cr *v1.Observability, indexes
 		return cr.Spec.SelfContained.ServiceMonitorNamespaceSelector
 	}
 
+	if cr.NamespaceScoped() {
+		return GetTargetNamespaceSelector(cr)
+	}
+
 	if cr.OverrideSelectors() && cr.Spec.SelfContained.ServiceMonitorNamespaceSelector == nil {
 		return &v12.LabelSelector{}
 	}
@@ -416,6 +1039,10 @@ func GetPrometheusRuleNamespaceSelectors(cr *v1.Observability, indexes []v1.Repo
 		return cr.Spec.SelfContained.RuleNamespaceSelector
 	}
 
+	if cr.NamespaceScoped() {
+		return GetTargetNamespaceSelector(cr)
+	}
+
 	if cr.OverrideSelectors() && cr.Spec.SelfContained.RuleNamespaceSelector == nil {
 		return &v12.LabelSelector{}
 	}
@@ -432,6 +1059,10 @@ func GetProbeNamespaceSelectors(cr *v1.Observability, indexes []v1.RepositoryInd
 		return cr.Spec.SelfContained.ProbeNamespaceSelector
 	}
 
+	if cr.NamespaceScoped() {
+		return GetTargetNamespaceSelector(cr)
+	}
+
 	if cr.OverrideSelectors() && cr.Spec.SelfContained.ProbeNamespaceSelector == nil {
 		return &v12.LabelSelector{}
 	}
@@ -458,6 +1089,225 @@ func getPrometheusRepositoryIndexConfig(indexes []v1.RepositoryIndex) *v1.Promet
 	return &v1.PrometheusIndex{}
 }
 
+// The interval from the CR takes precedence over the index, invalid durations are ignored
+func GetPrometheusScrapeInterval(cr *v1.Observability, indexes []v1.RepositoryIndex) prometheusv1.Duration {
+	if cr.Spec.SelfContained != nil && isValidDuration(cr.Spec.SelfContained.ScrapeInterval) {
+		return prometheusv1.Duration(cr.Spec.SelfContained.ScrapeInterval)
+	}
+
+	prometheusConfig := getPrometheusRepositoryIndexConfig(indexes)
+	if prometheusConfig != nil && isValidDuration(prometheusConfig.ScrapeInterval) {
+		return prometheusv1.Duration(prometheusConfig.ScrapeInterval)
+	}
+
+	return ""
+}
+
+// The interval from the CR takes precedence over the index, invalid durations are ignored
+func GetPrometheusEvaluationInterval(cr *v1.Observability, indexes []v1.RepositoryIndex) prometheusv1.Duration {
+	if cr.Spec.SelfContained != nil && isValidDuration(cr.Spec.SelfContained.EvaluationInterval) {
+		return prometheusv1.Duration(cr.Spec.SelfContained.EvaluationInterval)
+	}
+
+	prometheusConfig := getPrometheusRepositoryIndexConfig(indexes)
+	if prometheusConfig != nil && isValidDuration(prometheusConfig.EvaluationInterval) {
+		return prometheusv1.Duration(prometheusConfig.EvaluationInterval)
+	}
+
+	return ""
+}
+
+// Remote write spec of a self-contained target. Bearer tokens are read from the secret mounted to
+// /etc/prometheus/secrets, basic auth credentials are read by the Prometheus operator.
+func GetRemoteWriteTargetSpec(cr *v1.Observability, target *v1.RemoteWriteTarget, proxyUrl string) (*prometheusv1.RemoteWriteSpec, error) {
+	targetUrl, err := url.Parse(target.Url)
+	if err != nil {
+		return nil, err
+	}
+	if targetUrl.Host == "" {
+		return nil, fmt.Errorf("url of remote write target %v has no host", target.Name)
+	}
+	if target.AuthSecret != "" && target.BasicAuthSecret != "" {
+		return nil, fmt.Errorf("remote write target %v can only use one of bearer token or basic auth", target.Name)
+	}
+
+	spec := &prometheusv1.RemoteWriteSpec{
+		URL:                 target.Url,
+		Name:                target.Name,
+		Headers:             target.Headers,
+		RemoteTimeout:       prometheusv1.Duration(target.RemoteTimeout),
+		WriteRelabelConfigs: target.WriteRelabelConfigs,
+		TLSConfig: &prometheusv1.TLSConfig{
+			SafeTLSConfig: prometheusv1.SafeTLSConfig{
+				InsecureSkipVerify: target.InsecureSkipVerifyEnabled() && !cr.FIPSModeEnabled(),
+			},
+		},
+		ProxyURL:    proxyUrl,
+		QueueConfig: target.QueueConfig,
+	}
+
+	if target.AuthSecret != "" {
+		spec.BearerTokenFile = fmt.Sprintf("/etc/prometheus/secrets/%s/token", target.AuthSecret)
+	}
+
+	if target.BasicAuthSecret != "" {
+		spec.BasicAuth = getBasicAuth(target.BasicAuthSecret)
+	}
+
+	return spec, nil
+}
+
+// Remote read spec of a target from the CR or an index, the credentials are handled as for remote write targets
+func GetRemoteReadSpec(cr *v1.Observability, target *v1.RemoteReadTarget, proxyUrl string) (*prometheusv1.RemoteReadSpec, error) {
+	targetUrl, err := url.Parse(target.Url)
+	if err != nil {
+		return nil, err
+	}
+	if targetUrl.Host == "" {
+		return nil, fmt.Errorf("url of remote read target %v has no host", target.Name)
+	}
+	if target.AuthSecret != "" && target.BasicAuthSecret != "" {
+		return nil, fmt.Errorf("remote read target %v can only use one of bearer token or basic auth", target.Name)
+	}
+
+	spec := &prometheusv1.RemoteReadSpec{
+		URL:              target.Url,
+		Name:             target.Name,
+		Headers:          target.Headers,
+		RequiredMatchers: target.RequiredMatchers,
+		ReadRecent:       target.ReadRecent,
+		RemoteTimeout:    prometheusv1.Duration(target.RemoteTimeout),
+		TLSConfig: &prometheusv1.TLSConfig{
+			SafeTLSConfig: prometheusv1.SafeTLSConfig{
+				InsecureSkipVerify: target.InsecureSkipVerifyEnabled() && !cr.FIPSModeEnabled(),
+			},
+		},
+		ProxyURL: proxyUrl,
+	}
+
+	if target.AuthSecret != "" {
+		spec.BearerTokenFile = fmt.Sprintf("/etc/prometheus/secrets/%s/token", target.AuthSecret)
+	}
+
+	if target.BasicAuthSecret != "" {
+		spec.BasicAuth = getBasicAuth(target.BasicAuthSecret)
+	}
+
+	return spec, nil
+}
+
+func getBasicAuth(secretName string) *prometheusv1.BasicAuth {
+	return &prometheusv1.BasicAuth{
+		Username: v13.SecretKeySelector{
+			LocalObjectReference: v13.LocalObjectReference{Name: secretName},
+			Key:                  "username",
+		},
+		Password: v13.SecretKeySelector{
+			LocalObjectReference: v13.LocalObjectReference{Name: secretName},
+			Key:                  "password",
+		},
+	}
+}
+
+// Access keys are optional, Prometheus falls back to the default AWS credentials chain
+func GetSigv4(config *v1.Sigv4Config) *prometheusv1.Sigv4 {
+	sigv4 := &prometheusv1.Sigv4{
+		Region:  config.Region,
+		RoleArn: config.RoleArn,
+		Profile: config.Profile,
+	}
+	if config.AccessKeySecret != "" {
+		sigv4.AccessKey = &v13.SecretKeySelector{
+			LocalObjectReference: v13.LocalObjectReference{Name: config.AccessKeySecret},
+			Key:                  "accessKey",
+		}
+		sigv4.SecretKey = &v13.SecretKeySelector{
+			LocalObjectReference: v13.LocalObjectReference{Name: config.AccessKeySecret},
+			Key:                  "secretKey",
+		}
+	}
+	return sigv4
+}
+
+const awsRoleArnAnnotation = "eks.amazonaws.com/role-arn"
+
+// Service account annotation read by the identity webhook of EKS
+func GetWorkloadIdentityAnnotations(cr *v1.Observability) map[string]string {
+	result := map[string]string{}
+	identity := cr.Spec.WorkloadIdentity
+	if identity == nil {
+		return result
+	}
+	if identity.AwsRoleArn != "" {
+		result[awsRoleArnAnnotation] = identity.AwsRoleArn
+	}
+	return result
+}
+
+// Write relabel configs keeping or dropping series by metric name. Every filter results in its own
+// keep and drop step, so the keep lists of multiple filters all have to match.
+func GetMetricFilterRelabelConfigs(filters ...*v1.MetricFilter) ([]prometheusv1.RelabelConfig, error) {
+	var result []prometheusv1.RelabelConfig
+	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
+
+		for _, step := range []struct {
+			action   string
+			patterns []string
+		}{
+			{action: "keep", patterns: filter.Keep},
+			{action: "drop", patterns: filter.Drop},
+		} {
+			if len(step.patterns) == 0 {
+				continue
+			}
+
+			for _, pattern := range step.patterns {
+				// Prometheus anchors relabel regexes
+				_, err := regexp.Compile(fmt.Sprintf("^(?:%v)$", pattern))
+				if err != nil {
+					return nil, fmt.Errorf("invalid metric filter pattern %v: %v", pattern, err)
+				}
+			}
+
+			result = append(result, prometheusv1.RelabelConfig{
+				SourceLabels: []prometheusv1.LabelName{"__name__"},
+				Regex:        strings.Join(step.patterns, "|"),
+				Action:       step.action,
+			})
+		}
+	}
+	return result, nil
+}
+
+func isValidDuration(duration string) bool {
+	if duration == "" {
+		return false
+	}
+	_, err := commonmodel.ParseDuration(duration)
+	return err == nil
+}
+
+// Label names that are not valid in Prometheus are ignored
+func GetClusterLabels(cr *v1.Observability) map[string]string {
+	result := map[string]string{}
+	for name, value := range cr.Spec.ClusterLabels {
+		if commonmodel.LabelName(name).IsValid() {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// The cluster id can't be overridden by the cluster labels
+func GetPrometheusExternalLabels(cr *v1.Observability) map[string]string {
+	result := GetClusterLabels(cr)
+	result["cluster_id"] = cr.Status.ClusterID
+	return result
+}
+
 func GetPrometheusVersion(cr *v1.Observability) string {
 	if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.PrometheusVersion != "" {
 		return cr.Spec.SelfContained.PrometheusVersion
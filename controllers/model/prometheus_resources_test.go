@@ -3,6 +3,7 @@ package model
 import (
 	"testing"
 
+	"github.com/ghodss/yaml"
 	. "github.com/onsi/gomega"
 	routev1 "github.com/openshift/api/route/v1"
 	coreosv1 "github.com/operator-framework/api/pkg/operators/v1"
@@ -12,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v14 "k8s.io/api/rbac/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 )
 
 var (
@@ -41,12 +43,34 @@ var (
   params:
     match[]: [test1,test2]
   scheme: https
-  bearer_token_file: "/var/run/secrets/kubernetes.io/serviceaccount/token"
+  bearer_token_file: "/var/run/secrets/tokens/prometheus-token/token"
   tls_config:
-    insecure_skip_verify: true
+    ca_file: "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+`
+	configAsByteArrayBearerToken       = []byte(testFederationConfigBearerToken)
+	testFederationConfigKubePrometheus = `
+- job_name: kube-prometheus-federation
+  honor_labels: true
+  kubernetes_sd_configs:
+    - role: service
+      namespaces:
+        names:
+          - monitoring
+  scrape_interval: 120s
+  scrape_timeout: 60s
+  metrics_path: /federate
+  relabel_configs:
+    - action: keep
+      source_labels: [ '__meta_kubernetes_service_name' ]
+      regex: prometheus-k8s
+    - action: keep
+      source_labels: [ '__meta_kubernetes_service_port_name' ]
+      regex: web
+  params:
+    match[]: [test1,test2]
+  scheme: http
 `
-	configAsByteArrayBearerToken = []byte(testFederationConfigBearerToken)
-	testRepoIndexes              = []v1.RepositoryIndex{
+	testRepoIndexes = []v1.RepositoryIndex{
 		{
 			Config: &v1.RepositoryConfig{
 				Grafana: &v1.GrafanaIndex{
@@ -445,6 +469,35 @@ func TestPrometheusResources_GetPrometheusClusterRoleBinding(t *testing.T) {
 	}
 }
 
+func TestPrometheusResources_GetTargetNamespaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []string
+		want    []string
+	}{
+		{
+			name:    "the namespace of prometheus comes first",
+			targets: []string{"app-1", "app-2"},
+			want:    []string{testNamespace, "app-1", "app-2"},
+		},
+		{
+			name:    "namespaces are listed once",
+			targets: []string{"app-1", testNamespace, "app-1"},
+			want:    []string{testNamespace, "app-1"},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+				obsCR.Spec.TargetNamespaces = tt.targets
+			})
+			Expect(GetTargetNamespaces(cr)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestPrometheusResources_GetPrometheusRoute(t *testing.T) {
 	type args struct {
 		cr *v1.Observability
@@ -511,6 +564,280 @@ func TestPrometheusResources_GetFederationConfigBearerToken(t *testing.T) {
 	}
 }
 
+func TestPrometheusResources_GetFederationConfigKubePrometheus(t *testing.T) {
+	type args struct {
+		patterns []string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		want    []byte
+	}{
+		{
+			name: "returns kube-prometheus federation config with no error",
+			args: args{
+				patterns: testPattern,
+			},
+			wantErr: false,
+			want:    []byte(testFederationConfigKubePrometheus),
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetFederationConfigKubePrometheus(tt.args.patterns)
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetFederationConfigUpstreams(t *testing.T) {
+	type args struct {
+		upstreams []v1.FederationUpstream
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		want    string
+	}{
+		{
+			name: "returns a job per upstream",
+			args: args{
+				upstreams: []v1.FederationUpstream{
+					{
+						Name:       "central",
+						Url:        "https://prometheus.example.com/prefix/",
+						AuthSecret: "central-token",
+						Match:      testPattern,
+					},
+					{
+						Name:        "local",
+						Url:         "http://prometheus.monitoring.svc:9090",
+						Match:       []string{"up"},
+						HonorLabels: &([]bool{false})[0],
+					},
+				},
+			},
+			wantErr: false,
+			want: `
+- job_name: federation-central
+  honor_labels: true
+  static_configs:
+    - targets: [ 'prometheus.example.com' ]
+  scrape_interval: 120s
+  scrape_timeout: 60s
+  metrics_path: /prefix/federate
+  params:
+    match[]: ['test1','test2']
+  scheme: https
+  bearer_token_file: "/etc/prometheus/secrets/central-token/token"
+  tls_config:
+    insecure_skip_verify: false
+- job_name: federation-local
+  honor_labels: false
+  static_configs:
+    - targets: [ 'prometheus.monitoring.svc:9090' ]
+  scrape_interval: 120s
+  scrape_timeout: 60s
+  metrics_path: /federate
+  params:
+    match[]: ['up']
+  scheme: http
+  tls_config:
+    insecure_skip_verify: false
+`,
+		},
+		{
+			name: "returns error if the url has no host",
+			args: args{
+				upstreams: []v1.FederationUpstream{
+					{
+						Name:  "invalid",
+						Url:   "prometheus:9090",
+						Match: testPattern,
+					},
+				},
+			},
+			wantErr: true,
+			want:    "",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetFederationConfigUpstreams(tt.args.upstreams)
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(string(result)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetHostedControlPlaneFederationUpstreams(t *testing.T) {
+	hosted := func(obsCR *v1.Observability) {
+		obsCR.Spec.ClusterTopology = v1.ClusterTopologyHosted
+		obsCR.Spec.HostedControlPlane = &v1.HostedControlPlaneSpec{
+			FederationUrl: "https://prometheus.hcp-cluster.svc:9091",
+			AuthSecret:    "hcp-token",
+		}
+	}
+
+	tests := []struct {
+		name string
+		cr   *v1.Observability
+		want []v1.FederationUpstream
+	}{
+		{
+			name: "federates the patterns from the hosted control plane",
+			cr:   buildObservabilityCR(hosted),
+			want: []v1.FederationUpstream{
+				{
+					Name:       "hosted-control-plane",
+					Url:        "https://prometheus.hcp-cluster.svc:9091",
+					AuthSecret: "hcp-token",
+					Match:      []string{"kubelet_volume_stats_used_bytes"},
+				},
+			},
+		},
+		{
+			name: "federation is skipped without url",
+			cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+				obsCR.Spec.ClusterTopology = v1.ClusterTopologyHosted
+			}),
+			want: nil,
+		},
+		{
+			name: "standalone clusters federate from openshift-monitoring",
+			cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+				hosted(obsCR)
+				obsCR.Spec.ClusterTopology = v1.ClusterTopologyStandalone
+			}),
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetHostedControlPlaneFederationUpstreams(tt.cr, []string{"'kubelet_volume_stats_used_bytes'"})
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetStaticTargetsFileSD(t *testing.T) {
+	type args struct {
+		groups []v1.StaticTargetGroup
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		want    map[string]string
+	}{
+		{
+			name: "returns a file per target group",
+			args: args{
+				groups: []v1.StaticTargetGroup{
+					{
+						Name:    "appliances",
+						Targets: []string{"10.0.0.1:9100", "10.0.0.2:9100"},
+						Labels: map[string]string{
+							"site": "dc1",
+						},
+					},
+					{
+						Name:    "vms",
+						Targets: []string{"vm.example.com:9100"},
+					},
+				},
+			},
+			wantErr: false,
+			want: map[string]string{
+				"appliances.json": `[{"targets":["10.0.0.1:9100","10.0.0.2:9100"],"labels":{"site":"dc1"}}]`,
+				"vms.json":        `[{"targets":["vm.example.com:9100"]}]`,
+			},
+		},
+		{
+			name: "returns no files without target groups",
+			args: args{
+				groups: nil,
+			},
+			wantErr: false,
+			want:    map[string]string{},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetStaticTargetsFileSD(tt.args.groups)
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetStaticTargetsScrapeConfig(t *testing.T) {
+	type args struct {
+		groups []v1.StaticTargetGroup
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		want    string
+	}{
+		{
+			name: "returns a job per target group with defaults",
+			args: args{
+				groups: []v1.StaticTargetGroup{
+					{
+						Name:    "appliances",
+						Targets: []string{"10.0.0.1:9100"},
+					},
+					{
+						Name:        "vms",
+						Targets:     []string{"vm.example.com:9443"},
+						MetricsPath: "/custom/metrics",
+						Scheme:      "https",
+					},
+				},
+			},
+			wantErr: false,
+			want: `
+- job_name: static-appliances
+  metrics_path: /metrics
+  scheme: http
+  file_sd_configs:
+    - files: [ '/etc/prometheus/configmaps/prometheus-static-targets/appliances.json' ]
+- job_name: static-vms
+  metrics_path: /custom/metrics
+  scheme: https
+  file_sd_configs:
+    - files: [ '/etc/prometheus/configmaps/prometheus-static-targets/vms.json' ]
+`,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetStaticTargetsScrapeConfig(buildObservabilityCR(nil), tt.args.groups)
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(string(result)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestPrometheusResources_GetPrometheusAdditionalScrapeConfig(t *testing.T) {
 	type args struct {
 		cr *v1.Observability
@@ -954,6 +1281,24 @@ func TestPrometheusResources_GetPrometheusPodMonitorNamespaceSelectors(t *testin
 			},
 			want: &v12.LabelSelector{},
 		},
+		{
+			name: "returns the target namespaces when namespace scoped",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.TargetNamespaces = []string{"app-1", "app-2"}
+				}),
+				indexes: testRepoIndexes,
+			},
+			want: &v12.LabelSelector{
+				MatchExpressions: []v12.LabelSelectorRequirement{
+					{
+						Key:      "kubernetes.io/metadata.name",
+						Operator: v12.LabelSelectorOpIn,
+						Values:   []string{testNamespace, "app-1", "app-2"},
+					},
+				},
+			},
+		},
 		{
 			name: "returns pod monitor NamespaceSelector from repo Prometheus Index",
 			args: args{
@@ -1213,93 +1558,205 @@ func TestPrometheusResources_GetPrometheusVersion(t *testing.T) {
 	}
 }
 
-func TestPrometheusResources_GetPrometheusResourceRequirement(t *testing.T) {
+func TestPrometheusResources_GetPrometheusScrapeInterval(t *testing.T) {
+	buildIndexes := func(interval string) []v1.RepositoryIndex {
+		return []v1.RepositoryIndex{
+			{
+				Config: &v1.RepositoryConfig{
+					Prometheus: &v1.PrometheusIndex{
+						ScrapeInterval: interval,
+					},
+				},
+			},
+		}
+	}
+
 	type args struct {
-		cr *v1.Observability
+		cr      *v1.Observability
+		indexes []v1.RepositoryIndex
 	}
 
 	tests := []struct {
 		name string
 		args args
-		want *corev1.ResourceRequirements
+		want monitoringv1.Duration
 	}{
 		{
-			name: "returns CR PrometheusResourceRequirement when self contained",
+			name: "returns CR interval over index interval",
 			args: args{
 				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
 					obsCR.Spec.SelfContained = &v1.SelfContained{
-						PrometheusResourceRequirement: &corev1.ResourceRequirements{
-							Limits: testResourceList,
-						},
+						ScrapeInterval: "1m",
 					}
 				}),
+				indexes: buildIndexes("2m"),
 			},
-			want: &corev1.ResourceRequirements{
-				Limits: testResourceList,
+			want: "1m",
+		},
+		{
+			name: "returns index interval if CR interval is invalid",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.SelfContained = &v1.SelfContained{
+						ScrapeInterval: "one minute",
+					}
+				}),
+				indexes: buildIndexes("1m30s"),
 			},
+			want: "1m30s",
 		},
 		{
-			name: "returns blank ResourceRequirements when NOT self contained",
+			name: "returns empty interval if not set",
 			args: args{
-				cr: buildObservabilityCR(nil),
+				cr:      buildObservabilityCR(nil),
+				indexes: buildIndexes(""),
 			},
-			want: &corev1.ResourceRequirements{},
+			want: "",
 		},
 	}
 
 	RegisterTestingT(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := GetPrometheusResourceRequirement(tt.args.cr)
+			result := GetPrometheusScrapeInterval(tt.args.cr, tt.args.indexes)
 			Expect(result).To(Equal(tt.want))
 		})
 	}
 }
 
-func TestPrometheusResources_GetPrometheusOperatorResourceRequirement(t *testing.T) {
+func TestPrometheusResources_GetPrometheusEvaluationInterval(t *testing.T) {
 	type args struct {
-		cr *v1.Observability
+		cr      *v1.Observability
+		indexes []v1.RepositoryIndex
 	}
 
 	tests := []struct {
 		name string
 		args args
-		want *corev1.ResourceRequirements
+		want monitoringv1.Duration
 	}{
 		{
-			name: "returns CR PrometheusOperatorResourceRequirement when self contained",
+			name: "returns index interval if not set in CR",
 			args: args{
-				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
-					obsCR.Spec.SelfContained = &v1.SelfContained{
-						PrometheusOperatorResourceRequirement: &corev1.ResourceRequirements{
-							Limits: testResourceList,
+				cr: buildObservabilityCR(nil),
+				indexes: []v1.RepositoryIndex{
+					{
+						Config: &v1.RepositoryConfig{
+							Prometheus: &v1.PrometheusIndex{
+								EvaluationInterval: "1m",
+							},
 						},
-					}
-				}),
-			},
-			want: &corev1.ResourceRequirements{
-				Limits: testResourceList,
+					},
+				},
 			},
+			want: "1m",
 		},
 		{
-			name: "returns blank ResourceRequirements when NOT self contained",
+			name: "returns empty interval without indexes",
 			args: args{
-				cr: buildObservabilityCR(nil),
+				cr:      buildObservabilityCR(nil),
+				indexes: []v1.RepositoryIndex{},
 			},
-			want: &corev1.ResourceRequirements{},
+			want: "",
 		},
 	}
 
 	RegisterTestingT(t)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := GetPrometheusOperatorResourceRequirement(tt.args.cr)
+			result := GetPrometheusEvaluationInterval(tt.args.cr, tt.args.indexes)
 			Expect(result).To(Equal(tt.want))
 		})
 	}
 }
 
-func TestPrometheusResources_GetPrometheusStorageSize(t *testing.T) {
+func TestPrometheusResources_GetPrometheusResourceRequirement(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *corev1.ResourceRequirements
+	}{
+		{
+			name: "returns CR PrometheusResourceRequirement when self contained",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.SelfContained = &v1.SelfContained{
+						PrometheusResourceRequirement: &corev1.ResourceRequirements{
+							Limits: testResourceList,
+						},
+					}
+				}),
+			},
+			want: &corev1.ResourceRequirements{
+				Limits: testResourceList,
+			},
+		},
+		{
+			name: "returns blank ResourceRequirements when NOT self contained",
+			args: args{
+				cr: buildObservabilityCR(nil),
+			},
+			want: &corev1.ResourceRequirements{},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetPrometheusResourceRequirement(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetPrometheusOperatorResourceRequirement(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *corev1.ResourceRequirements
+	}{
+		{
+			name: "returns CR PrometheusOperatorResourceRequirement when self contained",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.SelfContained = &v1.SelfContained{
+						PrometheusOperatorResourceRequirement: &corev1.ResourceRequirements{
+							Limits: testResourceList,
+						},
+					}
+				}),
+			},
+			want: &corev1.ResourceRequirements{
+				Limits: testResourceList,
+			},
+		},
+		{
+			name: "returns blank ResourceRequirements when NOT self contained",
+			args: args{
+				cr: buildObservabilityCR(nil),
+			},
+			want: &corev1.ResourceRequirements{},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetPrometheusOperatorResourceRequirement(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetPrometheusStorageSize(t *testing.T) {
 	type args struct {
 		cr      *v1.Observability
 		indexes []v1.RepositoryIndex
@@ -1391,3 +1848,707 @@ func TestPrometheusResources_GetPrometheusStorageSize(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusResources_GetMetricFilterRelabelConfigs(t *testing.T) {
+	type args struct {
+		filters []*v1.MetricFilter
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []monitoringv1.RelabelConfig
+		wantErr bool
+	}{
+		{
+			name: "no relabel configs without filters",
+			args: args{
+				filters: []*v1.MetricFilter{nil, {}},
+			},
+			want: nil,
+		},
+		{
+			name: "keep and drop steps per filter",
+			args: args{
+				filters: []*v1.MetricFilter{
+					{
+						Keep: []string{"kafka_.*", "up"},
+					},
+					{
+						Keep: []string{"kafka_.*"},
+						Drop: []string{"kafka_.*_bucket"},
+					},
+				},
+			},
+			want: []monitoringv1.RelabelConfig{
+				{
+					SourceLabels: []monitoringv1.LabelName{"__name__"},
+					Regex:        "kafka_.*|up",
+					Action:       "keep",
+				},
+				{
+					SourceLabels: []monitoringv1.LabelName{"__name__"},
+					Regex:        "kafka_.*",
+					Action:       "keep",
+				},
+				{
+					SourceLabels: []monitoringv1.LabelName{"__name__"},
+					Regex:        "kafka_.*_bucket",
+					Action:       "drop",
+				},
+			},
+		},
+		{
+			name: "error if a pattern does not compile",
+			args: args{
+				filters: []*v1.MetricFilter{
+					{
+						Drop: []string{"kafka_(.*"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetMetricFilterRelabelConfigs(tt.args.filters...)
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetRemoteWriteTargetSpec(t *testing.T) {
+	type args struct {
+		cr     *v1.Observability
+		target *v1.RemoteWriteTarget
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    *monitoringv1.RemoteWriteSpec
+		wantErr bool
+	}{
+		{
+			name: "bearer token from the mounted secret",
+			args: args{
+				cr: buildObservabilityCR(nil),
+				target: &v1.RemoteWriteTarget{
+					Name:       "mimir",
+					Url:        "https://mimir.example.com/api/v1/push",
+					AuthSecret: "mimir-token",
+					Headers: map[string]string{
+						"X-Scope-OrgID": "tenant",
+					},
+				},
+			},
+			want: &monitoringv1.RemoteWriteSpec{
+				URL:  "https://mimir.example.com/api/v1/push",
+				Name: "mimir",
+				Headers: map[string]string{
+					"X-Scope-OrgID": "tenant",
+				},
+				BearerTokenFile: "/etc/prometheus/secrets/mimir-token/token",
+				TLSConfig:       &monitoringv1.TLSConfig{},
+			},
+		},
+		{
+			name: "basic auth from the secret",
+			args: args{
+				cr: buildObservabilityCR(nil),
+				target: &v1.RemoteWriteTarget{
+					Name:               "grafana-cloud",
+					Url:                "https://prometheus.grafana.net/api/prom/push",
+					BasicAuthSecret:    "grafana-cloud",
+					InsecureSkipVerify: &([]bool{true})[0],
+				},
+			},
+			want: &monitoringv1.RemoteWriteSpec{
+				URL:  "https://prometheus.grafana.net/api/prom/push",
+				Name: "grafana-cloud",
+				BasicAuth: &monitoringv1.BasicAuth{
+					Username: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "grafana-cloud"},
+						Key:                  "username",
+					},
+					Password: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "grafana-cloud"},
+						Key:                  "password",
+					},
+				},
+				TLSConfig: &monitoringv1.TLSConfig{
+					SafeTLSConfig: monitoringv1.SafeTLSConfig{
+						InsecureSkipVerify: true,
+					},
+				},
+			},
+		},
+		{
+			name: "error if the url has no host",
+			args: args{
+				cr: buildObservabilityCR(nil),
+				target: &v1.RemoteWriteTarget{
+					Name: "invalid",
+					Url:  "/api/v1/push",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error if both auth methods are set",
+			args: args{
+				cr: buildObservabilityCR(nil),
+				target: &v1.RemoteWriteTarget{
+					Name:            "mimir",
+					Url:             "https://mimir.example.com/api/v1/push",
+					AuthSecret:      "mimir-token",
+					BasicAuthSecret: "mimir-basic-auth",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetRemoteWriteTargetSpec(tt.args.cr, tt.args.target, "")
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetRemoteReadSpec(t *testing.T) {
+	type args struct {
+		cr     *v1.Observability
+		target *v1.RemoteReadTarget
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    *monitoringv1.RemoteReadSpec
+		wantErr bool
+	}{
+		{
+			name: "bearer token from the mounted secret",
+			args: args{
+				cr: buildObservabilityCR(nil),
+				target: &v1.RemoteReadTarget{
+					Name:       "observatorium",
+					Url:        "https://observatorium.example.com/api/metrics/v1/test/api/v1/read",
+					AuthSecret: "observatorium-token",
+					RequiredMatchers: map[string]string{
+						"cluster_id": "test",
+					},
+				},
+			},
+			want: &monitoringv1.RemoteReadSpec{
+				URL:  "https://observatorium.example.com/api/metrics/v1/test/api/v1/read",
+				Name: "observatorium",
+				RequiredMatchers: map[string]string{
+					"cluster_id": "test",
+				},
+				BearerTokenFile: "/etc/prometheus/secrets/observatorium-token/token",
+				TLSConfig:       &monitoringv1.TLSConfig{},
+			},
+		},
+		{
+			name: "tls is verified in fips mode",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.FIPSMode = &([]bool{true})[0]
+				}),
+				target: &v1.RemoteReadTarget{
+					Name:               "thanos",
+					Url:                "https://thanos.example.com/api/v1/read",
+					BasicAuthSecret:    "thanos",
+					ReadRecent:         true,
+					InsecureSkipVerify: &([]bool{true})[0],
+				},
+			},
+			want: &monitoringv1.RemoteReadSpec{
+				URL:        "https://thanos.example.com/api/v1/read",
+				Name:       "thanos",
+				ReadRecent: true,
+				BasicAuth: &monitoringv1.BasicAuth{
+					Username: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "thanos"},
+						Key:                  "username",
+					},
+					Password: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "thanos"},
+						Key:                  "password",
+					},
+				},
+				TLSConfig: &monitoringv1.TLSConfig{},
+			},
+		},
+		{
+			name: "error if the url has no host",
+			args: args{
+				cr: buildObservabilityCR(nil),
+				target: &v1.RemoteReadTarget{
+					Name: "invalid",
+					Url:  "api/v1/read",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetRemoteReadSpec(tt.args.cr, tt.args.target, "")
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetSigv4(t *testing.T) {
+	type args struct {
+		config *v1.Sigv4Config
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *monitoringv1.Sigv4
+	}{
+		{
+			name: "default credentials chain without access key",
+			args: args{
+				config: &v1.Sigv4Config{
+					Region:  "us-east-1",
+					RoleArn: "arn:aws:iam::123456789012:role/prometheus",
+				},
+			},
+			want: &monitoringv1.Sigv4{
+				Region:  "us-east-1",
+				RoleArn: "arn:aws:iam::123456789012:role/prometheus",
+			},
+		},
+		{
+			name: "access key from secret",
+			args: args{
+				config: &v1.Sigv4Config{
+					Region:          "us-east-1",
+					AccessKeySecret: "aws-credentials",
+				},
+			},
+			want: &monitoringv1.Sigv4{
+				Region: "us-east-1",
+				AccessKey: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "aws-credentials"},
+					Key:                  "accessKey",
+				},
+				SecretKey: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "aws-credentials"},
+					Key:                  "secretKey",
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetSigv4(tt.args.config)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetWorkloadIdentityAnnotations(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want map[string]string
+	}{
+		{
+			name: "no annotations without workload identity",
+			args: args{
+				cr: &v1.Observability{},
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "role annotation of eks",
+			args: args{
+				cr: &v1.Observability{
+					Spec: v1.ObservabilitySpec{
+						WorkloadIdentity: &v1.WorkloadIdentity{
+							AwsRoleArn: "arn:aws:iam::123456789012:role/prometheus",
+						},
+					},
+				},
+			},
+			want: map[string]string{
+				"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/prometheus",
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Expect(GetWorkloadIdentityAnnotations(tt.args.cr)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetPrometheusExternalLabels(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want map[string]string
+	}{
+		{
+			name: "only the cluster id without cluster labels",
+			args: args{
+				cr: &v1.Observability{
+					Status: v1.ObservabilityStatus{ClusterID: "test-cluster"},
+				},
+			},
+			want: map[string]string{"cluster_id": "test-cluster"},
+		},
+		{
+			name: "cluster labels don't override the cluster id, invalid names are ignored",
+			args: args{
+				cr: &v1.Observability{
+					Spec: v1.ObservabilitySpec{
+						ClusterLabels: map[string]string{
+							"cluster_id":   "other",
+							"region":       "eu-west-1",
+							"cluster-tier": "gold",
+						},
+					},
+					Status: v1.ObservabilityStatus{ClusterID: "test-cluster"},
+				},
+			},
+			want: map[string]string{"cluster_id": "test-cluster", "region": "eu-west-1"},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetPrometheusExternalLabels(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_IsPrometheusVolumeClaimName(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim string
+		want  bool
+	}{
+		{
+			name:  "claim of the managed services template",
+			claim: "managed-services-prometheus-obs-prometheus-0",
+			want:  true,
+		},
+		{
+			name:  "claim of the default template",
+			claim: "prometheus-obs-prometheus-db-prometheus-obs-prometheus-1",
+			want:  true,
+		},
+		{
+			name:  "claim of another prometheus",
+			claim: "managed-services-prometheus-kafka-prometheus-0",
+			want:  false,
+		},
+		{
+			name:  "claim without ordinal",
+			claim: "managed-services-prometheus-obs-prometheus",
+			want:  false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsPrometheusVolumeClaimName(&v1.Observability{}, tt.claim)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetPrometheusServingName(t *testing.T) {
+	tests := []struct {
+		name    string
+		upgrade *v1.PrometheusUpgradeStatus
+		want    string
+	}{
+		{
+			name:    "no upgrade in progress",
+			upgrade: nil,
+			want:    "obs-prometheus",
+		},
+		{
+			name:    "candidate is starting",
+			upgrade: &v1.PrometheusUpgradeStatus{Phase: v1.PrometheusUpgradeCandidate},
+			want:    "obs-prometheus",
+		},
+		{
+			name:    "candidate serves",
+			upgrade: &v1.PrometheusUpgradeStatus{Phase: v1.PrometheusUpgradeSwitched},
+			want:    "obs-prometheus-next",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetPrometheusServingName(&v1.Observability{}, tt.upgrade)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetKubeletScrapeConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	var scrapeConfigs []map[string]interface{}
+	Expect(yaml.Unmarshal(GetKubeletScrapeConfig(), &scrapeConfigs)).To(Succeed())
+	Expect(scrapeConfigs).To(HaveLen(2))
+	Expect(scrapeConfigs[1]["job_name"]).To(Equal("kubelet-cadvisor"))
+	Expect(scrapeConfigs[1]["scheme"]).To(Equal("https"))
+}
+
+func TestPrometheusResources_GetSelectorLabels(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetSelectorLabels(nil)).To(BeNil())
+
+	selector := &v12.LabelSelector{
+		MatchLabels: map[string]string{
+			"app": "observability",
+		},
+		MatchExpressions: []v12.LabelSelectorRequirement{
+			{Key: "team", Operator: v12.LabelSelectorOpIn, Values: []string{"sre", "platform"}},
+			{Key: "monitored", Operator: v12.LabelSelectorOpExists},
+			{Key: "tier", Operator: v12.LabelSelectorOpNotIn, Values: []string{"test"}},
+			{Key: "legacy", Operator: v12.LabelSelectorOpDoesNotExist},
+		},
+	}
+	labels := GetSelectorLabels(selector)
+	Expect(labels).To(Equal(map[string]string{
+		"app":       "observability",
+		"team":      "sre",
+		"monitored": "",
+	}))
+
+	// The labels satisfy the selector and are a copy of the match labels
+	parsed, err := v12.LabelSelectorAsSelector(selector)
+	Expect(err).To(BeNil())
+	Expect(parsed.Matches(k8slabels.Set(labels))).To(BeTrue())
+
+	labels["app"] = "changed"
+	Expect(selector.MatchLabels["app"]).To(Equal("observability"))
+}
+
+func TestPrometheusResources_ValidateIndexSelectors(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(ValidateIndexSelectors(&v1.RepositoryIndex{})).To(BeNil())
+
+	index := &v1.RepositoryIndex{
+		Config: &v1.RepositoryConfig{
+			Prometheus: &v1.PrometheusIndex{
+				PodMonitorLabelSelector: &v12.LabelSelector{
+					MatchExpressions: []v12.LabelSelectorRequirement{
+						{Key: "team", Operator: v12.LabelSelectorOpNotIn, Values: []string{"test"}},
+					},
+				},
+			},
+		},
+	}
+	Expect(ValidateIndexSelectors(index)).To(BeNil())
+
+	index.Config.Prometheus.ProbeLabelSelector = &v12.LabelSelector{
+		MatchExpressions: []v12.LabelSelectorRequirement{
+			{Key: "team", Operator: v12.LabelSelectorOpIn},
+		},
+	}
+	err := ValidateIndexSelectors(index)
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("probeSelector"))
+}
+
+func TestPrometheusResources_IsDiscoveredNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	namespace := &corev1.Namespace{}
+	Expect(IsDiscoveredNamespace(namespace)).To(BeFalse())
+
+	namespace.Annotations = map[string]string{NamespaceDiscoveryAnnotation: "false"}
+	Expect(IsDiscoveredNamespace(namespace)).To(BeFalse())
+
+	namespace.Annotations[NamespaceDiscoveryAnnotation] = "true"
+	Expect(IsDiscoveredNamespace(namespace)).To(BeTrue())
+}
+
+func TestPrometheusResources_GetExternalAlertmanagersConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		alertmanagers []v1.ExternalAlertmanager
+		wantErr       bool
+		want          string
+	}{
+		{
+			name: "returns an entry per alertmanager",
+			alertmanagers: []v1.ExternalAlertmanager{
+				{
+					Name:       "central",
+					Urls:       []string{"https://alertmanager-0.example.com/prefix/", "https://alertmanager-1.example.com/prefix"},
+					AuthSecret: "central-token",
+					Timeout:    "30s",
+				},
+				{
+					Name:               "local",
+					Urls:               []string{"http://alertmanager.monitoring.svc:9093"},
+					ApiVersion:         "v1",
+					Timeout:            "invalid",
+					InsecureSkipVerify: &([]bool{true})[0],
+				},
+			},
+			want: `
+- scheme: https
+  path_prefix: /prefix
+  api_version: v2
+  timeout: 30s
+  static_configs:
+    - targets: [ 'alertmanager-0.example.com', 'alertmanager-1.example.com' ]
+  bearer_token_file: "/etc/prometheus/secrets/central-token/token"
+  tls_config:
+    insecure_skip_verify: false
+- scheme: http
+  path_prefix: /
+  api_version: v1
+  timeout: 10s
+  static_configs:
+    - targets: [ 'alertmanager.monitoring.svc:9093' ]
+  tls_config:
+    insecure_skip_verify: true
+`,
+		},
+		{
+			name: "returns error if the urls differ in their path",
+			alertmanagers: []v1.ExternalAlertmanager{
+				{
+					Name: "invalid",
+					Urls: []string{"https://alertmanager-0.example.com/a", "https://alertmanager-1.example.com/b"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns error if the url has no host",
+			alertmanagers: []v1.ExternalAlertmanager{
+				{
+					Name: "invalid",
+					Urls: []string{"alertmanager:9093"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns error without urls",
+			alertmanagers: []v1.ExternalAlertmanager{
+				{
+					Name: "invalid",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GetExternalAlertmanagersConfig(tt.alertmanagers)
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(string(result)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusResources_GetPrometheusUpstreamUrl(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	Expect(GetPrometheusUpstreamUrl(cr)).To(Equal("http://obs-prometheus.testNamespace.svc:9090"))
+
+	// The prometheus-operated service of the external Prometheus, unless the url is set
+	cr.Spec.ExternalPrometheus = &v1.ExternalPrometheus{Name: "k8s", Namespace: "monitoring"}
+	Expect(GetPrometheusUpstreamUrl(cr)).To(Equal("http://prometheus-operated.monitoring.svc:9090"))
+	Expect(GetExternalPrometheus(cr).Name).To(Equal("k8s"))
+
+	cr.Spec.ExternalPrometheus.Url = "https://prometheus.example.com/"
+	Expect(GetPrometheusUpstreamUrl(cr)).To(Equal("https://prometheus.example.com"))
+}
+
+func TestPrometheusResources_GetPrometheusWebSpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	spec, err := GetPrometheusWebSpec(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(spec).To(BeNil())
+
+	// The service CA certificate on OpenShift
+	enabled := true
+	cr.Spec.PrometheusWebTLS = &v1.PrometheusWebTLS{Enabled: &enabled}
+	spec, err = GetPrometheusWebSpec(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(spec.TLSConfig.KeySecret.Name).To(Equal("prometheus-k8s-tls"))
+	Expect(spec.TLSConfig.KeySecret.Key).To(Equal("tls.key"))
+	Expect(spec.TLSConfig.Cert.Secret.Name).To(Equal("prometheus-k8s-tls"))
+	Expect(spec.TLSConfig.Cert.Secret.Key).To(Equal("tls.crt"))
+	Expect(spec.TLSConfig.CipherSuites).To(BeEmpty())
+	Expect(GetPrometheusUpstreamUrl(cr)).To(Equal("https://obs-prometheus.testNamespace.svc:9090"))
+	Expect(string(GetCardinalityScrapeConfig(cr))).To(ContainSubstring("scheme: https\n  tls_config:\n    insecure_skip_verify: true"))
+
+	// A certificate of its own is required on Kubernetes
+	cr.Spec.ClusterType = v1.ClusterTypeKubernetes
+	_, err = GetPrometheusWebSpec(cr)
+	Expect(err).To(MatchError(ContainSubstring("required on kubernetes")))
+
+	// Or the one of cert-manager
+	cr.Spec.CertManager = &v1.CertManagerSpec{IssuerRef: v1.CertManagerIssuerRef{Name: "ca-issuer"}}
+	spec, err = GetPrometheusWebSpec(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(spec.TLSConfig.Cert.Secret.Name).To(Equal("prometheus-k8s-tls"))
+	cr.Spec.CertManager = nil
+
+	cr.Spec.PrometheusWebTLS.SecretName = "prometheus-serving-cert"
+	cr.Spec.FIPSMode = &enabled
+	spec, err = GetPrometheusWebSpec(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(spec.TLSConfig.Cert.Secret.Name).To(Equal("prometheus-serving-cert"))
+	Expect(spec.TLSConfig.MinVersion).To(Equal("TLS12"))
+	Expect(spec.TLSConfig.CipherSuites).To(HaveLen(len(FIPSCipherSuites)))
+
+	cr.Spec.PrometheusWebTLS.MinVersion = "TLS13"
+	spec, err = GetPrometheusWebSpec(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(spec.TLSConfig.MinVersion).To(Equal("TLS13"))
+}
@@ -495,3 +495,75 @@ func TestPromtailResources_GetPromtailDaemonSetLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestPromtailResources_GetPromtailAffinity(t *testing.T) {
+	infraRequirement := corev1.NodeSelectorRequirement{
+		Key:      "node-role.kubernetes.io/infra",
+		Operator: corev1.NodeSelectorOpDoesNotExist,
+	}
+	masterRequirement := corev1.NodeSelectorRequirement{
+		Key:      "node-role.kubernetes.io/master",
+		Operator: corev1.NodeSelectorOpDoesNotExist,
+	}
+	buildAffinity := func(requirements ...corev1.NodeSelectorRequirement) *corev1.Affinity {
+		return &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: requirements,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		cr *v1.Observability
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *corev1.Affinity
+	}{
+		{
+			name: "excludes infra nodes by default",
+			args: args{
+				cr: buildObservabilityCR(nil),
+			},
+			want: buildAffinity(infraRequirement),
+		},
+		{
+			name: "excludes infra and master nodes",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.Promtail = &v1.PromtailSpec{
+						ExcludeMasterNodes: &([]bool{true})[0],
+					}
+				}),
+			},
+			want: buildAffinity(infraRequirement, masterRequirement),
+		},
+		{
+			name: "no affinity if no node role is excluded",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.Promtail = &v1.PromtailSpec{
+						ExcludeInfraNodes: &([]bool{false})[0],
+					}
+				}),
+			},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetPromtailAffinity(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
@@ -139,6 +139,11 @@ scrape_configs:
 			}
 			tokenRefresherName := GetTokenRefresherName(c.Id, LogsTokenRefresher)
 			url = fmt.Sprintf("http://%v.%v.svc.cluster.local", tokenRefresherName, cr.Namespace)
+		case v1.AuthTypeServiceAccount:
+			url = fmt.Sprintf("%s/api/logs/v1/%s/loki/api/v1/push", c.Gateway, c.Tenant)
+			requireToken = true
+		case v1.AuthTypeSigv4:
+			return "", errors2.New(fmt.Sprintf("logs are not supported for auth type %v of %v", c.AuthType, c.Id))
 		}
 	}
 
@@ -172,3 +177,94 @@ func GetPromtailDaemonSetLabels(index *v1.RepositoryIndex) *metav1.LabelSelector
 		},
 	}
 }
+
+// Keeps the Promtail pods off the excluded node roles, nil if no role is excluded
+func GetPromtailAffinity(cr *v1.Observability) *v12.Affinity {
+	var requirements []v12.NodeSelectorRequirement
+	if cr.PromtailInfraNodesExcluded() {
+		requirements = append(requirements, v12.NodeSelectorRequirement{
+			Key:      "node-role.kubernetes.io/infra",
+			Operator: v12.NodeSelectorOpDoesNotExist,
+		})
+	}
+	if cr.PromtailMasterNodesExcluded() {
+		requirements = append(requirements, v12.NodeSelectorRequirement{
+			Key:      "node-role.kubernetes.io/master",
+			Operator: v12.NodeSelectorOpDoesNotExist,
+		})
+	}
+
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	return &v12.Affinity{
+		NodeAffinity: &v12.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v12.NodeSelector{
+				NodeSelectorTerms: []v12.NodeSelectorTerm{
+					{
+						MatchExpressions: requirements,
+					},
+				},
+			},
+		},
+	}
+}
+
+// Daemonset of the architecture, or of the default image if empty
+func GetPromtailDaemonSetForArchitecture(cr *v1.Observability, name string, architecture string) *v13.DaemonSet {
+	if architecture != "" {
+		name = fmt.Sprintf("%v-%v", name, architecture)
+	}
+	return GetPromtailDaemonSet(cr, name)
+}
+
+// Architectures with an override run their own daemonset, the default image runs on the
+// remaining nodes it supports
+func GetPromtailArchitectureAffinity(cr *v1.Observability, architecture string) *v12.Affinity {
+	affinity := GetPromtailAffinity(cr)
+	if architecture != "" {
+		return AddNodeSelectorRequirement(affinity, v12.NodeSelectorRequirement{
+			Key:      NodeArchitectureLabel,
+			Operator: v12.NodeSelectorOpIn,
+			Values:   []string{architecture},
+		})
+	}
+
+	if supported := defaultImageArchitectures[PromtailImage]; getImageOverride(cr, v1.ImagePromtail, "") == "" {
+		affinity = AddNodeSelectorRequirement(affinity, v12.NodeSelectorRequirement{
+			Key:      NodeArchitectureLabel,
+			Operator: v12.NodeSelectorOpIn,
+			Values:   supported,
+		})
+	}
+	if overridden := GetOverriddenArchitectures(cr, v1.ImagePromtail); len(overridden) > 0 {
+		affinity = AddNodeSelectorRequirement(affinity, v12.NodeSelectorRequirement{
+			Key:      NodeArchitectureLabel,
+			Operator: v12.NodeSelectorOpNotIn,
+			Values:   overridden,
+		})
+	}
+	return affinity
+}
+
+func GetPromtailNodeSelector(cr *v1.Observability) map[string]string {
+	if cr.Spec.Promtail != nil {
+		return cr.Spec.Promtail.NodeSelector
+	}
+	return nil
+}
+
+func GetPromtailTolerations(cr *v1.Observability) []v12.Toleration {
+	if cr.Spec.Promtail != nil {
+		return cr.Spec.Promtail.Tolerations
+	}
+	return nil
+}
+
+func GetPromtailResourceRequirement(cr *v1.Observability) *v12.ResourceRequirements {
+	if cr.Spec.Promtail != nil && cr.Spec.Promtail.Resources != nil {
+		return cr.Spec.Promtail.Resources
+	}
+	return &v12.ResourceRequirements{}
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"net/http"
+	"net/url"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"golang.org/x/net/http/httpproxy"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Proxy environment of containers making outbound connections. The variables are always
+// present, so that removing the cluster-wide proxy also clears them.
+func GetProxyEnvVars(proxy *configv1.Proxy) []v1.EnvVar {
+	var httpProxy, httpsProxy, noProxy string
+	if proxy != nil {
+		httpProxy = proxy.Status.HTTPProxy
+		httpsProxy = proxy.Status.HTTPSProxy
+		noProxy = proxy.Status.NoProxy
+	}
+
+	return []v1.EnvVar{
+		{
+			Name:  "HTTP_PROXY",
+			Value: httpProxy,
+		},
+		{
+			Name:  "HTTPS_PROXY",
+			Value: httpsProxy,
+		},
+		{
+			Name:  "NO_PROXY",
+			Value: noProxy,
+		},
+	}
+}
+
+// Returns the proxy for requests to the target url, empty if there is no cluster-wide proxy
+// or the target is excluded by NO_PROXY
+func GetProxyUrlFor(proxy *configv1.Proxy, target string) string {
+	targetUrl, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	proxyUrl, err := GetProxyFunc(proxy)(&http.Request{URL: targetUrl})
+	if err != nil || proxyUrl == nil {
+		return ""
+	}
+	return proxyUrl.String()
+}
+
+// Proxy of the HTTP requests of the operator itself, direct without a cluster-wide proxy
+func GetProxyFunc(proxy *configv1.Proxy) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, nil
+		}
+	}
+	config := httpproxy.Config{
+		HTTPProxy:  proxy.Status.HTTPProxy,
+		HTTPSProxy: proxy.Status.HTTPSProxy,
+		NoProxy:    proxy.Status.NoProxy,
+	}
+	proxyFunc := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var testClusterProxy = &configv1.Proxy{
+	Status: configv1.ProxyStatus{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://secure-proxy.example.com:3129",
+		NoProxy:    ".cluster.local,.svc,internal.example.com",
+	},
+}
+
+func TestProxyResources_GetProxyEnvVars(t *testing.T) {
+	type args struct {
+		proxy *configv1.Proxy
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []corev1.EnvVar
+	}{
+		{
+			name: "empty variables without cluster proxy",
+			args: args{
+				proxy: nil,
+			},
+			want: []corev1.EnvVar{
+				{
+					Name: "HTTP_PROXY",
+				},
+				{
+					Name: "HTTPS_PROXY",
+				},
+				{
+					Name: "NO_PROXY",
+				},
+			},
+		},
+		{
+			name: "variables from cluster proxy status",
+			args: args{
+				proxy: testClusterProxy,
+			},
+			want: []corev1.EnvVar{
+				{
+					Name:  "HTTP_PROXY",
+					Value: "http://proxy.example.com:3128",
+				},
+				{
+					Name:  "HTTPS_PROXY",
+					Value: "http://secure-proxy.example.com:3129",
+				},
+				{
+					Name:  "NO_PROXY",
+					Value: ".cluster.local,.svc,internal.example.com",
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetProxyEnvVars(tt.args.proxy)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestProxyResources_GetProxyUrlFor(t *testing.T) {
+	type args struct {
+		proxy  *configv1.Proxy
+		target string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "no proxy without cluster proxy",
+			args: args{
+				proxy:  nil,
+				target: "https://observatorium.example.com/api/metrics/v1/test/api/v1/receive",
+			},
+			want: "",
+		},
+		{
+			name: "https proxy for https targets",
+			args: args{
+				proxy:  testClusterProxy,
+				target: "https://observatorium.example.com/api/metrics/v1/test/api/v1/receive",
+			},
+			want: "http://secure-proxy.example.com:3129",
+		},
+		{
+			name: "http proxy for http targets",
+			args: args{
+				proxy:  testClusterProxy,
+				target: "http://observatorium.example.com/api/metrics/v1/test/api/v1/receive",
+			},
+			want: "http://proxy.example.com:3128",
+		},
+		{
+			name: "no proxy for excluded targets",
+			args: args{
+				proxy:  testClusterProxy,
+				target: "https://internal.example.com/api/metrics/v1/test/api/v1/receive",
+			},
+			want: "",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetProxyUrlFor(tt.args.proxy, tt.args.target)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
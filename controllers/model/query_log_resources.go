@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/querylog"
+	v14 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	QueryLogExporterName = "query-log-exporter"
+	QueryLogExporterPort = 9097
+	queryLogFileName     = "query.log"
+	// Mounted by the Prometheus operator for query log files without a directory
+	queryLogEmptyDirVolume = "query-log-file"
+	queryLogEmptyDirPath   = "/var/log/prometheus"
+	prometheusDataPath     = "/prometheus"
+	prometheusDataSubPath  = "prometheus-db"
+)
+
+func GetQueryLogExporterImage(cr *v1.Observability, operatorImage string) string {
+	repository, tag := SplitImageTag(operatorImage)
+	return GetImage(cr, v1.ImageQueryLogExporter, repository, tag)
+}
+
+func getQueryLogVolume(cr *v1.Observability) v1.QueryLogVolume {
+	if cr.Spec.SelfContained.PrometheusQueryLog.Volume == "" {
+		return v1.QueryLogVolumeEmptyDir
+	}
+	return cr.Spec.SelfContained.PrometheusQueryLog.Volume
+}
+
+// Empty without the query log. A file name without a directory gets an emptyDir from the Prometheus operator.
+func GetPrometheusQueryLogFile(cr *v1.Observability) string {
+	if !cr.QueryLogEnabled() {
+		return ""
+	}
+	if getQueryLogVolume(cr) == v1.QueryLogVolumePersistent {
+		return fmt.Sprintf("%v/%v", prometheusDataPath, queryLogFileName)
+	}
+	return queryLogFileName
+}
+
+func GetQueryLogSlowQueryThreshold(cr *v1.Observability) (time.Duration, error) {
+	threshold := cr.Spec.SelfContained.PrometheusQueryLog.SlowQueryThreshold
+	if threshold == "" {
+		return querylog.DefaultThreshold, nil
+	}
+	duration, err := time.ParseDuration(threshold)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid slow query threshold %v", threshold)
+	}
+	return duration, nil
+}
+
+// The volume mount is set by ApplyQueryLogExporterVolumeMount, it depends on the storage of Prometheus
+func GetQueryLogExporterContainer(cr *v1.Observability, operatorImage string, threshold time.Duration) v14.Container {
+	path := fmt.Sprintf("%v/%v", queryLogEmptyDirPath, queryLogFileName)
+	if getQueryLogVolume(cr) == v1.QueryLogVolumePersistent {
+		path = fmt.Sprintf("%v/%v", prometheusDataPath, queryLogFileName)
+	}
+	args := []string{
+		querylog.Command,
+		fmt.Sprintf("-listen-address=:%v", QueryLogExporterPort),
+		fmt.Sprintf("-query-log-file=%v", path),
+		fmt.Sprintf("-slow-query-threshold=%v", threshold),
+	}
+	if cr.Spec.SelfContained.PrometheusQueryLog.MaxQueries > 0 {
+		args = append(args, fmt.Sprintf("-max-queries=%v", cr.Spec.SelfContained.PrometheusQueryLog.MaxQueries))
+	}
+	return v14.Container{
+		Name:    QueryLogExporterName,
+		Image:   GetQueryLogExporterImage(cr, operatorImage),
+		Command: []string{"/manager"},
+		Args:    args,
+		Ports: []v14.ContainerPort{
+			{
+				Name:          "query-log",
+				ContainerPort: QueryLogExporterPort,
+			},
+		},
+		ReadinessProbe: &v14.Probe{
+			ProbeHandler: v14.ProbeHandler{
+				HTTPGet: &v14.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromInt(QueryLogExporterPort),
+				},
+			},
+		},
+		SecurityContext: GetContainerSecurityContext(cr),
+	}
+}
+
+// Same volume and sub path as the data directory of Prometheus, as mounted by the Prometheus operator
+func getPrometheusDataVolumeMount(prometheus *prometheusv1.Prometheus) v14.VolumeMount {
+	mount := v14.VolumeMount{
+		Name:      fmt.Sprintf("prometheus-%v-db", prometheus.Name),
+		MountPath: prometheusDataPath,
+	}
+	storage := prometheus.Spec.Storage
+	if storage != nil {
+		if storage.VolumeClaimTemplate.Name != "" {
+			mount.Name = storage.VolumeClaimTemplate.Name
+		}
+		// Deprecated, but still honoured by the Prometheus operator
+		if !storage.DisableMountSubPath {
+			mount.SubPath = prometheusDataSubPath
+		}
+	}
+	return mount
+}
+
+// Mount the volume of the query log into the exporter, also after the storage of the Prometheus changed,
+// e.g. for the candidate of an upgrade
+func ApplyQueryLogExporterVolumeMount(cr *v1.Observability, prometheus *prometheusv1.Prometheus) {
+	mount := v14.VolumeMount{
+		Name:      queryLogEmptyDirVolume,
+		MountPath: queryLogEmptyDirPath,
+	}
+	if cr.QueryLogEnabled() && getQueryLogVolume(cr) == v1.QueryLogVolumePersistent {
+		mount = getPrometheusDataVolumeMount(prometheus)
+	}
+	for i := range prometheus.Spec.Containers {
+		container := &prometheus.Spec.Containers[i]
+		if container.Name != QueryLogExporterName {
+			continue
+		}
+		container.VolumeMounts = []v14.VolumeMount{mount}
+	}
+}
+
+// The exporter runs in the pod of Prometheus
+func GetQueryLogExporterScrapeConfig() []byte {
+	return []byte(fmt.Sprintf(`
+- job_name: %v
+  static_configs:
+    - targets: [ 'localhost:%v' ]
+`, QueryLogExporterName, QueryLogExporterPort))
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v14 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildQueryLogCR(queryLog *v1.PrometheusQueryLog) *v1.Observability {
+	tr := true
+	queryLog.Enabled = &tr
+	return buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.SelfContained = &v1.SelfContained{PrometheusQueryLog: queryLog}
+	})
+}
+
+func TestQueryLogResources_GetPrometheusQueryLogFile(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetPrometheusQueryLogFile(buildObservabilityCR(nil))).To(BeEmpty())
+	Expect(GetPrometheusQueryLogFile(buildQueryLogCR(&v1.PrometheusQueryLog{}))).To(Equal("query.log"))
+	Expect(GetPrometheusQueryLogFile(buildQueryLogCR(&v1.PrometheusQueryLog{Volume: v1.QueryLogVolumePersistent}))).To(Equal("/prometheus/query.log"))
+}
+
+func TestQueryLogResources_GetQueryLogSlowQueryThreshold(t *testing.T) {
+	RegisterTestingT(t)
+
+	threshold, err := GetQueryLogSlowQueryThreshold(buildQueryLogCR(&v1.PrometheusQueryLog{}))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(threshold).To(Equal(10 * time.Second))
+
+	threshold, err = GetQueryLogSlowQueryThreshold(buildQueryLogCR(&v1.PrometheusQueryLog{SlowQueryThreshold: "2s"}))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(threshold).To(Equal(2 * time.Second))
+
+	_, err = GetQueryLogSlowQueryThreshold(buildQueryLogCR(&v1.PrometheusQueryLog{SlowQueryThreshold: "-1s"}))
+	Expect(err).To(HaveOccurred())
+}
+
+func TestQueryLogResources_GetQueryLogExporterContainer(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildQueryLogCR(&v1.PrometheusQueryLog{MaxQueries: 20})
+	container := GetQueryLogExporterContainer(cr, "quay.io/rhoas/observability-operator:v4.2.0", 5*time.Second)
+	Expect(container.Image).To(Equal("quay.io/rhoas/observability-operator:v4.2.0"))
+	Expect(container.Args).To(Equal([]string{
+		"query-log-exporter",
+		"-listen-address=:9097",
+		"-query-log-file=/var/log/prometheus/query.log",
+		"-slow-query-threshold=5s",
+		"-max-queries=20",
+	}))
+
+	cr.Spec.ImageOverrides = map[v1.ImageComponent]string{v1.ImageQueryLogExporter: "registry.example.com/exporter:v1"}
+	Expect(GetQueryLogExporterContainer(cr, "", time.Second).Image).To(Equal("registry.example.com/exporter:v1"))
+}
+
+func TestQueryLogResources_ApplyQueryLogExporterVolumeMount(t *testing.T) {
+	RegisterTestingT(t)
+
+	prometheus := &prometheusv1.Prometheus{
+		ObjectMeta: v12.ObjectMeta{Name: "kafka-prometheus"},
+	}
+	prometheus.Spec.Containers = []v14.Container{{Name: "oauth-proxy"}, {Name: QueryLogExporterName}}
+
+	ApplyQueryLogExporterVolumeMount(buildQueryLogCR(&v1.PrometheusQueryLog{}), prometheus)
+	Expect(prometheus.Spec.Containers[0].VolumeMounts).To(BeEmpty())
+	Expect(prometheus.Spec.Containers[1].VolumeMounts).To(Equal([]v14.VolumeMount{{Name: "query-log-file", MountPath: "/var/log/prometheus"}}))
+
+	cr := buildQueryLogCR(&v1.PrometheusQueryLog{Volume: v1.QueryLogVolumePersistent})
+	ApplyQueryLogExporterVolumeMount(cr, prometheus)
+	Expect(prometheus.Spec.Containers[1].VolumeMounts).To(Equal([]v14.VolumeMount{{Name: "prometheus-kafka-prometheus-db", MountPath: "/prometheus"}}))
+
+	prometheus.Spec.Storage = &prometheusv1.StorageSpec{
+		VolumeClaimTemplate: prometheusv1.EmbeddedPersistentVolumeClaim{
+			EmbeddedObjectMetadata: prometheusv1.EmbeddedObjectMetadata{Name: "managed-services"},
+		},
+	}
+	ApplyQueryLogExporterVolumeMount(cr, prometheus)
+	Expect(prometheus.Spec.Containers[1].VolumeMounts).To(Equal([]v14.VolumeMount{{Name: "managed-services", MountPath: "/prometheus", SubPath: "prometheus-db"}}))
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// Exported by the operator with the current time as value, scraped and remote written by Prometheus
+	RemoteWriteProbeMetric = "observability_operator_remote_write_probe_timestamp_seconds"
+	// Result of the last verification, exported by the operator
+	RemoteWriteProbeSuccessMetric = "observability_operator_remote_write_probe_success"
+
+	defaultRemoteWriteProbeInterval = 5 * time.Minute
+	defaultRemoteWriteProbeSla      = 5 * time.Minute
+	// Samples older than this are reported as missing
+	remoteWriteProbeLookback = "1h"
+)
+
+func GetRemoteWriteProbeRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-remote-write-probe",
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// Durations in the Prometheus format, invalid and non-positive durations fall back to the default
+func parsePrometheusDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	duration, err := commonmodel.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return time.Duration(duration)
+}
+
+func GetRemoteWriteProbeInterval(cr *v1.Observability) time.Duration {
+	if cr.Spec.RemoteWriteProbe == nil {
+		return defaultRemoteWriteProbeInterval
+	}
+	return parsePrometheusDuration(cr.Spec.RemoteWriteProbe.Interval, defaultRemoteWriteProbeInterval)
+}
+
+func GetRemoteWriteProbeSla(cr *v1.Observability) time.Duration {
+	if cr.Spec.RemoteWriteProbe == nil {
+		return defaultRemoteWriteProbeSla
+	}
+	return parsePrometheusDuration(cr.Spec.RemoteWriteProbe.Sla, defaultRemoteWriteProbeSla)
+}
+
+// Latest probe sample of the cluster, the value is the time it was scraped
+func GetRemoteWriteProbeQuery(cr *v1.Observability) string {
+	return fmt.Sprintf(`max(max_over_time(%v{cluster_id="%v"}[%v]))`, RemoteWriteProbeMetric, cr.Status.ClusterID, remoteWriteProbeLookback)
+}
+
+// Fires when the samples of the cluster don't arrive in an Observatorium instance within the SLA
+func GetRemoteWriteProbeRuleGroup() prometheusv1.RuleGroup {
+	return prometheusv1.RuleGroup{
+		Name: "remote-write-probe",
+		Rules: []prometheusv1.Rule{
+			{
+				Alert: "RemoteWriteProbeFailed",
+				Expr:  intstr.FromString(fmt.Sprintf("%v == 0", RemoteWriteProbeSuccessMetric)),
+				For:   "15m",
+				Labels: map[string]string{
+					"severity": "warning",
+				},
+				Annotations: map[string]string{
+					"summary":     "Remote written samples don't arrive in Observatorium",
+					"description": "The latest probe sample in Observatorium instance {{ $labels.observatorium }} is older than the SLA. Check status.remoteWriteProbes of the Observability CR.",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestRemoteWriteProbeResources_Settings(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Status.ClusterID = "cluster"
+	})
+	Expect(GetRemoteWriteProbeInterval(cr)).To(Equal(5 * time.Minute))
+	Expect(GetRemoteWriteProbeSla(cr)).To(Equal(5 * time.Minute))
+	Expect(GetRemoteWriteProbeQuery(cr)).To(Equal(`max(max_over_time(observability_operator_remote_write_probe_timestamp_seconds{cluster_id="cluster"}[1h]))`))
+
+	cr.Spec.RemoteWriteProbe = &v1.RemoteWriteProbe{Interval: "10m", Sla: "invalid"}
+	Expect(GetRemoteWriteProbeInterval(cr)).To(Equal(10 * time.Minute))
+	Expect(GetRemoteWriteProbeSla(cr)).To(Equal(5 * time.Minute))
+}
+
+func TestRemoteWriteProbeResources_GetMetricFilter(t *testing.T) {
+	RegisterTestingT(t)
+
+	enabled := true
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.MetricFilter = &v1.MetricFilter{Keep: []string{"kafka_.*"}}
+		obsCR.Spec.RemoteWriteProbe = &v1.RemoteWriteProbe{Enabled: &enabled}
+	})
+	Expect(GetMetricFilter(cr).Keep).To(Equal([]string{"kafka_.*", RemoteWriteProbeMetric}))
+	Expect(cr.Spec.MetricFilter.Keep).To(Equal([]string{"kafka_.*"}))
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	defaultRequeueInterval      = 10 * time.Second
+	defaultRequeueJitterPercent = 10
+	defaultBackoffInitialDelay  = 5 * time.Second
+	defaultBackoffMaxDelay      = 5 * time.Minute
+	defaultBackoffMultiplier    = 2
+)
+
+// Delay after a reconcile in which all stages succeeded
+func GetRequeueInterval(cr *v1.Observability) time.Duration {
+	if cr.Spec.Requeue == nil {
+		return defaultRequeueInterval
+	}
+	return parsePositiveDuration(cr.Spec.Requeue.Interval, defaultRequeueInterval)
+}
+
+// Delay after a reconcile in which the stage didn't succeed. Failures is the number of consecutive
+// reconciles in which the stage failed, 0 while it is in progress.
+func GetStageRequeueDelay(cr *v1.Observability, stage v1.ObservabilityStageName, failures int) time.Duration {
+	initialDelay, maxDelay, multiplier := getRequeueBackoff(cr, stage)
+
+	delay := initialDelay
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= time.Duration(multiplier)
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// Lengthen or shorten the delay by up to the jitter, random is in [0, 1)
+func GetJitteredRequeueDelay(cr *v1.Observability, delay time.Duration, random float64) time.Duration {
+	percent := defaultRequeueJitterPercent
+	if cr.Spec.Requeue != nil && cr.Spec.Requeue.JitterPercent != nil {
+		percent = *cr.Spec.Requeue.JitterPercent
+	}
+	if percent <= 0 {
+		return delay
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	jitter := float64(delay) * float64(percent) / 100 * (2*random - 1)
+	return delay + time.Duration(jitter)
+}
+
+// The backoff of the stage takes precedence over the backoff of all stages
+func getRequeueBackoff(cr *v1.Observability, stage v1.ObservabilityStageName) (time.Duration, time.Duration, int) {
+	initialDelay := defaultBackoffInitialDelay
+	maxDelay := defaultBackoffMaxDelay
+	multiplier := defaultBackoffMultiplier
+	if cr.Spec.Requeue == nil {
+		return initialDelay, maxDelay, multiplier
+	}
+
+	apply := func(backoff *v1.RequeueBackoff) {
+		initialDelay = parsePositiveDuration(backoff.InitialDelay, initialDelay)
+		maxDelay = parsePositiveDuration(backoff.MaxDelay, maxDelay)
+		if backoff.Multiplier != nil && *backoff.Multiplier >= 1 {
+			multiplier = *backoff.Multiplier
+		}
+	}
+	if cr.Spec.Requeue.Backoff != nil {
+		apply(cr.Spec.Requeue.Backoff)
+	}
+	for i := range cr.Spec.Requeue.StageBackoffs {
+		if cr.Spec.Requeue.StageBackoffs[i].Stage == stage {
+			apply(&cr.Spec.Requeue.StageBackoffs[i].RequeueBackoff)
+		}
+	}
+
+	if maxDelay < initialDelay {
+		maxDelay = initialDelay
+	}
+	return initialDelay, maxDelay, multiplier
+}
+
+// Invalid and non-positive durations fall back to the default
+func parsePositiveDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestRequeueResources_GetRequeueInterval(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetRequeueInterval(buildObservabilityCR(nil))).To(Equal(10 * time.Second))
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.Requeue = &v1.RequeuePolicy{Interval: "2m"}
+	})
+	Expect(GetRequeueInterval(cr)).To(Equal(2 * time.Minute))
+
+	// Invalid intervals fall back to the default
+	cr.Spec.Requeue.Interval = "soon"
+	Expect(GetRequeueInterval(cr)).To(Equal(10 * time.Second))
+}
+
+func TestRequeueResources_GetStageRequeueDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	Expect(GetStageRequeueDelay(cr, v1.PrometheusInstallation, 0)).To(Equal(5 * time.Second))
+	Expect(GetStageRequeueDelay(cr, v1.PrometheusInstallation, 1)).To(Equal(5 * time.Second))
+	Expect(GetStageRequeueDelay(cr, v1.PrometheusInstallation, 3)).To(Equal(20 * time.Second))
+	Expect(GetStageRequeueDelay(cr, v1.PrometheusInstallation, 100)).To(Equal(5 * time.Minute))
+
+	multiplier := 3
+	cr.Spec.Requeue = &v1.RequeuePolicy{
+		Backoff: &v1.RequeueBackoff{InitialDelay: "1s", MaxDelay: "1m"},
+		StageBackoffs: []v1.StageRequeueBackoff{{
+			Stage:          v1.Configuration,
+			RequeueBackoff: v1.RequeueBackoff{MaxDelay: "30m", Multiplier: &multiplier},
+		}},
+	}
+	Expect(GetStageRequeueDelay(cr, v1.PrometheusInstallation, 3)).To(Equal(4 * time.Second))
+	Expect(GetStageRequeueDelay(cr, v1.PrometheusInstallation, 100)).To(Equal(time.Minute))
+
+	// Unset fields of the stage fall back to the backoff of all stages
+	Expect(GetStageRequeueDelay(cr, v1.Configuration, 3)).To(Equal(9 * time.Second))
+	Expect(GetStageRequeueDelay(cr, v1.Configuration, 100)).To(Equal(30 * time.Minute))
+}
+
+func TestRequeueResources_GetJitteredRequeueDelay(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	Expect(GetJitteredRequeueDelay(cr, 10*time.Second, 0)).To(Equal(9 * time.Second))
+	Expect(GetJitteredRequeueDelay(cr, 10*time.Second, 0.5)).To(Equal(10 * time.Second))
+	Expect(GetJitteredRequeueDelay(cr, 10*time.Second, 0.75)).To(Equal(10500 * time.Millisecond))
+
+	percent := 0
+	cr.Spec.Requeue = &v1.RequeuePolicy{JitterPercent: &percent}
+	Expect(GetJitteredRequeueDelay(cr, 10*time.Second, 0)).To(Equal(10 * time.Second))
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	// Limits and drop relabeling that the operator applied to a monitor, so that they are reverted
+	// when the budget changes or is removed
+	ScrapeBudgetAnnotation = "observability.redhat.com/scrape-budget"
+
+	ScrapeBudgetLimitTargets          = "targets"
+	ScrapeBudgetLimitSamplesPerSecond = "samplesPerSecond"
+
+	// Targets by namespace
+	ScrapeBudgetTargetsQuery = `count by (namespace) (up{namespace!=""})`
+	// Samples per second by namespace, the samples of a scrape times the scrapes of the last five minutes
+	ScrapeBudgetSamplesQuery = `sum by (namespace) (avg_over_time(scrape_samples_post_metric_relabeling{namespace!=""}[5m]) * count_over_time(up{namespace!=""}[5m])) / 300`
+
+	// Scrape interval of Prometheus if neither the monitor nor the CR or index set one
+	defaultScrapeInterval = 30 * time.Second
+)
+
+// Recorded in the scrape budget annotation of a monitor
+type AppliedScrapeBudget struct {
+	TargetLimit uint64 `json:"targetLimit,omitempty"`
+	SampleLimit uint64 `json:"sampleLimit,omitempty"`
+	// Limits of the monitor before the budget was applied
+	OriginalTargetLimit uint64 `json:"originalTargetLimit,omitempty"`
+	OriginalSampleLimit uint64 `json:"originalSampleLimit,omitempty"`
+	// Regex of the drop relabeling added to every endpoint
+	Drop string `json:"drop,omitempty"`
+}
+
+func GetScrapeBudget(cr *v1.Observability, namespace string) *v1.NamespaceScrapeBudget {
+	for i := range cr.Spec.NamespaceScrapeBudgets {
+		if cr.Spec.NamespaceScrapeBudgets[i].Namespace == namespace {
+			return &cr.Spec.NamespaceScrapeBudgets[i]
+		}
+	}
+	return nil
+}
+
+// Samples of one scrape at the shortest interval of the endpoints, the monitor then can't exceed the
+// samples per second of the whole namespace
+func GetScrapeBudgetSampleLimit(budget *v1.NamespaceScrapeBudget, intervals []prometheusv1.Duration, defaultInterval prometheusv1.Duration) uint64 {
+	if budget.SamplesPerSecond == 0 {
+		return 0
+	}
+
+	fallback := parseScrapeInterval(defaultInterval, defaultScrapeInterval)
+	shortest := fallback
+	for i, interval := range intervals {
+		parsed := parseScrapeInterval(interval, fallback)
+		if i == 0 || parsed < shortest {
+			shortest = parsed
+		}
+	}
+
+	limit := uint64(float64(budget.SamplesPerSecond) * shortest.Seconds())
+	if limit == 0 {
+		return 1
+	}
+	return limit
+}
+
+func parseScrapeInterval(interval prometheusv1.Duration, fallback time.Duration) time.Duration {
+	if interval == "" {
+		return fallback
+	}
+	parsed, err := commonmodel.ParseDuration(string(interval))
+	if err != nil || parsed <= 0 {
+		return fallback
+	}
+	return time.Duration(parsed)
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestScrapeBudgetResources_GetScrapeBudgetSampleLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	budget := &v1.NamespaceScrapeBudget{SamplesPerSecond: 100}
+
+	// The shortest interval of the endpoints, endpoints without one use the default
+	Expect(GetScrapeBudgetSampleLimit(budget, []prometheusv1.Duration{"1m", "15s"}, "")).To(Equal(uint64(1500)))
+	Expect(GetScrapeBudgetSampleLimit(budget, []prometheusv1.Duration{"1m", ""}, "10s")).To(Equal(uint64(1000)))
+	Expect(GetScrapeBudgetSampleLimit(budget, nil, "")).To(Equal(uint64(3000)))
+	Expect(GetScrapeBudgetSampleLimit(&v1.NamespaceScrapeBudget{}, nil, "")).To(BeZero())
+}
+
+func TestScrapeBudgetResources_GetScrapeBudget(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.NamespaceScrapeBudgets = []v1.NamespaceScrapeBudget{{Namespace: "payments", TargetLimit: 10}}
+	})
+	Expect(GetScrapeBudget(cr, "payments").TargetLimit).To(Equal(uint64(10)))
+	Expect(GetScrapeBudget(cr, "search")).To(BeNil())
+}
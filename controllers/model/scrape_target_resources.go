@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	defaultScrapeTargetHealthInterval = 5 * time.Minute
+	// Monitors with down targets reported in the status, the status of large clusters stays small
+	MaxUnhealthyScrapeTargets = 20
+)
+
+func GetScrapeTargetHealthInterval(cr *v1.Observability) time.Duration {
+	if cr.Spec.ScrapeTargetHealth == nil {
+		return defaultScrapeTargetHealthInterval
+	}
+	return parsePrometheusDuration(cr.Spec.ScrapeTargetHealth.Interval, defaultScrapeTargetHealthInterval)
+}
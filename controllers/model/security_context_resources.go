@@ -0,0 +1,80 @@
+package model
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	kv1 "k8s.io/api/core/v1"
+)
+
+// User and groups on Kubernetes, the same as the defaults of the Prometheus operator. OpenShift
+// assigns them from the range of the namespace.
+const (
+	SecurityContextRunAsUser  = int64(1000)
+	SecurityContextRunAsGroup = int64(2000)
+	SecurityContextFSGroup    = int64(2000)
+)
+
+// Pod security context that complies with the restricted pod security profile
+func GetPodSecurityContext(cr *v1.Observability) *kv1.PodSecurityContext {
+	if cr.Spec.SecurityContext != nil && cr.Spec.SecurityContext.Pod != nil {
+		return cr.Spec.SecurityContext.Pod.DeepCopy()
+	}
+
+	context := &kv1.PodSecurityContext{
+		RunAsNonRoot: &([]bool{true})[0],
+		SeccompProfile: &kv1.SeccompProfile{
+			Type: kv1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+	if cr.IsKubernetesCluster() {
+		context.RunAsUser = &([]int64{SecurityContextRunAsUser})[0]
+		context.RunAsGroup = &([]int64{SecurityContextRunAsGroup})[0]
+		context.FSGroup = &([]int64{SecurityContextFSGroup})[0]
+	}
+	return context
+}
+
+// Container security context that complies with the restricted pod security profile
+func GetContainerSecurityContext(cr *v1.Observability) *kv1.SecurityContext {
+	if cr.Spec.SecurityContext != nil && cr.Spec.SecurityContext.Container != nil {
+		return cr.Spec.SecurityContext.Container.DeepCopy()
+	}
+
+	return &kv1.SecurityContext{
+		RunAsNonRoot:             &([]bool{true})[0],
+		AllowPrivilegeEscalation: &([]bool{false})[0],
+		Capabilities: &kv1.Capabilities{
+			Drop: []kv1.Capability{"ALL"},
+		},
+		SeccompProfile: &kv1.SeccompProfile{
+			Type: kv1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// Promtail reads the pod logs from the host path of the nodes, which requires a privileged
+// namespace
+func GetPromtailSecurityContext(cr *v1.Observability) *kv1.SecurityContext {
+	if cr.Spec.SecurityContext != nil && cr.Spec.SecurityContext.Promtail != nil {
+		return cr.Spec.SecurityContext.Promtail.DeepCopy()
+	}
+
+	return &kv1.SecurityContext{
+		Privileged: &([]bool{true})[0],
+		SeccompProfile: &kv1.SeccompProfile{
+			Type: kv1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// Containers of Prometheus and Alertmanager are created by the Prometheus operator. Containers
+// with the same name are merged with them, so these only set the security context.
+func GetSecurityContextContainerPatches(cr *v1.Observability, names ...string) []kv1.Container {
+	var containers []kv1.Container
+	for _, name := range names {
+		containers = append(containers, kv1.Container{
+			Name:            name,
+			SecurityContext: GetContainerSecurityContext(cr),
+		})
+	}
+	return containers
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSecurityContextResources_GetPodSecurityContext(t *testing.T) {
+	override := &corev1.PodSecurityContext{
+		RunAsUser: &([]int64{4000})[0],
+	}
+
+	tests := []struct {
+		name      string
+		cr        *v1.Observability
+		runAsUser *int64
+		seccomp   bool
+	}{
+		{
+			name:      "user assigned by openshift",
+			cr:        &v1.Observability{},
+			runAsUser: nil,
+			seccomp:   true,
+		},
+		{
+			name: "fixed user on kubernetes",
+			cr: &v1.Observability{
+				Spec: v1.ObservabilitySpec{
+					ClusterType: v1.ClusterTypeKubernetes,
+				},
+			},
+			runAsUser: &([]int64{SecurityContextRunAsUser})[0],
+			seccomp:   true,
+		},
+		{
+			name: "override from the cr",
+			cr: &v1.Observability{
+				Spec: v1.ObservabilitySpec{
+					SecurityContext: &v1.SecurityContextSpec{
+						Pod: override,
+					},
+				},
+			},
+			runAsUser: override.RunAsUser,
+			seccomp:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			result := GetPodSecurityContext(tt.cr)
+			g.Expect(result.RunAsUser).To(Equal(tt.runAsUser))
+			g.Expect(result.SeccompProfile != nil).To(Equal(tt.seccomp))
+		})
+	}
+}
+
+func TestSecurityContextResources_GetContainerSecurityContext(t *testing.T) {
+	g := NewWithT(t)
+
+	result := GetContainerSecurityContext(&v1.Observability{})
+	g.Expect(*result.RunAsNonRoot).To(BeTrue())
+	g.Expect(*result.AllowPrivilegeEscalation).To(BeFalse())
+	g.Expect(result.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+	g.Expect(result.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+
+	patches := GetSecurityContextContainerPatches(&v1.Observability{}, "prometheus", "config-reloader")
+	g.Expect(patches).To(HaveLen(2))
+	g.Expect(patches[1].Name).To(Equal("config-reloader"))
+	g.Expect(patches[1].Image).To(BeEmpty())
+	g.Expect(patches[1].SecurityContext).To(Equal(result))
+}
+
+func TestSecurityContextResources_GetPromtailSecurityContext(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(*GetPromtailSecurityContext(&v1.Observability{}).Privileged).To(BeTrue())
+
+	cr := &v1.Observability{
+		Spec: v1.ObservabilitySpec{
+			SecurityContext: &v1.SecurityContextSpec{
+				Promtail: &corev1.SecurityContext{
+					RunAsUser: &([]int64{0})[0],
+				},
+			},
+		},
+	}
+	g.Expect(GetPromtailSecurityContext(cr).Privileged).To(BeNil())
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	kv1 "k8s.io/api/core/v1"
+)
+
+const (
+	ServiceMeshCertsVolume = "istio-certs"
+	// Written by the sidecar of Prometheus, read by Prometheus
+	ServiceMeshCertsOutputPath = "/etc/istio-output-certs"
+	ServiceMeshCertsPath       = "/etc/istio-certs"
+)
+
+// Pod annotations of the managed components in the mesh, nil without a mesh
+func GetServiceMeshPodAnnotations(cr *v1.Observability) map[string]string {
+	if !cr.ServiceMeshEnabled() {
+		return nil
+	}
+
+	annotations := map[string]string{
+		"sidecar.istio.io/inject": "true",
+	}
+	if ports := joinPorts(cr.Spec.ServiceMesh.ExcludeInboundPorts); ports != "" {
+		annotations["traffic.sidecar.istio.io/excludeInboundPorts"] = ports
+	}
+	if ports := joinPorts(cr.Spec.ServiceMesh.ExcludeOutboundPorts); ports != "" {
+		annotations["traffic.sidecar.istio.io/excludeOutboundPorts"] = ports
+	}
+	return annotations
+}
+
+// Scrapes of Prometheus bypass its sidecar, the mesh can't route requests to pod IPs. For mTLS scrapes
+// the sidecar writes its certificates to a volume shared with Prometheus.
+func GetPrometheusServiceMeshPodAnnotations(cr *v1.Observability) map[string]string {
+	annotations := GetServiceMeshPodAnnotations(cr)
+	if annotations == nil {
+		return nil
+	}
+
+	annotations["traffic.sidecar.istio.io/includeOutboundIPRanges"] = ""
+	if cr.ServiceMeshMTLSScrapesEnabled() {
+		annotations["proxy.istio.io/config"] = fmt.Sprintf("proxyMetadata:\n  OUTPUT_CERTS: %v\n", ServiceMeshCertsOutputPath)
+		annotations["sidecar.istio.io/userVolumeMount"] = fmt.Sprintf(`[{"name": "%v", "mountPath": "%v"}]`, ServiceMeshCertsVolume, ServiceMeshCertsOutputPath)
+	}
+	return annotations
+}
+
+func GetServiceMeshCertsVolume() kv1.Volume {
+	return kv1.Volume{
+		Name: ServiceMeshCertsVolume,
+		VolumeSource: kv1.VolumeSource{
+			EmptyDir: &kv1.EmptyDirVolumeSource{
+				Medium: kv1.StorageMediumMemory,
+			},
+		},
+	}
+}
+
+func GetServiceMeshCertsVolumeMount() kv1.VolumeMount {
+	return kv1.VolumeMount{
+		Name:      ServiceMeshCertsVolume,
+		MountPath: ServiceMeshCertsPath,
+		ReadOnly:  true,
+	}
+}
+
+// Scrape config of targets with strict mTLS. The certificates are issued for the identity of the
+// workload, not for its address, so only the chain is verified.
+func GetServiceMeshScrapeTLSConfig() string {
+	return fmt.Sprintf(`
+  tls_config:
+    ca_file: %[1]v/root-cert.pem
+    cert_file: %[1]v/cert-chain.pem
+    key_file: %[1]v/key.pem
+    insecure_skip_verify: true`, ServiceMeshCertsPath)
+}
+
+// Mesh scrapes of the static target group, the group is scraped as it is without mTLS scrapes
+func IsServiceMeshScrape(cr *v1.Observability, group *v1.StaticTargetGroup) bool {
+	return cr.ServiceMeshMTLSScrapesEnabled() && group.ServiceMeshMTLS != nil && *group.ServiceMeshMTLS
+}
+
+func joinPorts(ports []int32) string {
+	var result []string
+	for _, port := range ports {
+		result = append(result, fmt.Sprint(port))
+	}
+	return strings.Join(result, ",")
+}
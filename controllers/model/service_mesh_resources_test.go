@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestServiceMeshResources_GetServiceMeshPodAnnotations(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	Expect(GetServiceMeshPodAnnotations(cr)).To(BeNil())
+	Expect(GetPrometheusServiceMeshPodAnnotations(cr)).To(BeNil())
+	Expect(cr.OAuthProxyEnabled()).To(BeTrue())
+
+	enabled := true
+	cr.Spec.ServiceMesh = &v1.ServiceMeshSpec{
+		Enabled:              &enabled,
+		ExcludeInboundPorts:  []int32{9090, 9115},
+		ExcludeOutboundPorts: []int32{6443},
+	}
+	Expect(GetServiceMeshPodAnnotations(cr)).To(Equal(map[string]string{
+		"sidecar.istio.io/inject":                       "true",
+		"traffic.sidecar.istio.io/excludeInboundPorts":  "9090,9115",
+		"traffic.sidecar.istio.io/excludeOutboundPorts": "6443",
+	}))
+
+	// Scrapes bypass the sidecar of Prometheus, the certificates are only written for mTLS scrapes
+	annotations := GetPrometheusServiceMeshPodAnnotations(cr)
+	Expect(annotations).To(HaveKeyWithValue("traffic.sidecar.istio.io/includeOutboundIPRanges", ""))
+	Expect(annotations).ToNot(HaveKey("proxy.istio.io/config"))
+
+	cr.Spec.ServiceMesh.MTLSScrapes = &enabled
+	annotations = GetPrometheusServiceMeshPodAnnotations(cr)
+	Expect(annotations).To(HaveKeyWithValue("proxy.istio.io/config", "proxyMetadata:\n  OUTPUT_CERTS: /etc/istio-output-certs\n"))
+	Expect(annotations).To(HaveKeyWithValue("sidecar.istio.io/userVolumeMount", `[{"name": "istio-certs", "mountPath": "/etc/istio-output-certs"}]`))
+
+	cr.Spec.ServiceMesh.Authentication = &enabled
+	Expect(cr.OAuthProxyEnabled()).To(BeFalse())
+}
+
+func TestServiceMeshResources_GetStaticTargetsScrapeConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	enabled := true
+	groups := []v1.StaticTargetGroup{
+		{Name: "mesh", Targets: []string{"app.team-a.svc:8080"}, ServiceMeshMTLS: &enabled},
+	}
+
+	// Scraped as they are until the mTLS scrapes are enabled
+	cr := buildObservabilityCR(nil)
+	config, err := GetStaticTargetsScrapeConfig(cr, groups)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(string(config)).To(ContainSubstring("scheme: http\n"))
+	Expect(string(config)).ToNot(ContainSubstring("tls_config"))
+
+	cr.Spec.ServiceMesh = &v1.ServiceMeshSpec{Enabled: &enabled, MTLSScrapes: &enabled}
+	config, err = GetStaticTargetsScrapeConfig(cr, groups)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(string(config)).To(ContainSubstring(`
+  scheme: https
+  tls_config:
+    ca_file: /etc/istio-certs/root-cert.pem
+    cert_file: /etc/istio-certs/cert-chain.pem
+    key_file: /etc/istio-certs/key.pem
+    insecure_skip_verify: true
+  file_sd_configs:`))
+}
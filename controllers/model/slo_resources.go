@@ -0,0 +1,177 @@
+package model
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	defaultSLOWindow  = "30d"
+	sloWindowVariable = "$window"
+)
+
+// Multi-window, multi-burn-rate alerts: the long window detects the burn, the short window
+// resolves the alert soon after the errors stop
+type burnRateAlert struct {
+	longWindow  string
+	shortWindow string
+	// Share of the error budget consumed within the long window
+	budgetPercent int
+	severity      string
+}
+
+var burnRateAlerts = []burnRateAlert{
+	{longWindow: "1h", shortWindow: "5m", budgetPercent: 2, severity: "critical"},
+	{longWindow: "6h", shortWindow: "30m", budgetPercent: 5, severity: "critical"},
+	{longWindow: "1d", shortWindow: "2h", budgetPercent: 10, severity: "warning"},
+	{longWindow: "3d", shortWindow: "6h", budgetPercent: 10, severity: "warning"},
+}
+
+//go:embed slo_dashboard.json
+var sloDashboard string
+
+// Recording rules and alerts of all objectives
+func GetServiceLevelObjectiveRule(cr *v1.Observability) *prometheusv1.PrometheusRule {
+	return &prometheusv1.PrometheusRule{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-slos",
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// Shows one objective at a time, selected by the slo label of the recorded series
+func GetServiceLevelObjectiveDashboard(cr *v1.Observability) *v1alpha1.GrafanaDashboard {
+	return &v1alpha1.GrafanaDashboard{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      "generated-slos",
+			Namespace: cr.Namespace,
+		},
+		Spec: v1alpha1.GrafanaDashboardSpec{
+			Json: sloDashboard,
+		},
+	}
+}
+
+func getSLOWindow(slo *v1.ServiceLevelObjective) string {
+	if slo.Window == "" {
+		return defaultSLOWindow
+	}
+	return slo.Window
+}
+
+func ValidateServiceLevelObjective(slo *v1.ServiceLevelObjective) error {
+	target, err := strconv.ParseFloat(slo.Target, 64)
+	if err != nil || target <= 0 || target >= 100 {
+		return fmt.Errorf("invalid target %v of slo %v", slo.Target, slo.Name)
+	}
+	if _, err := commonmodel.ParseDuration(getSLOWindow(slo)); err != nil {
+		return fmt.Errorf("invalid window %v of slo %v", slo.Window, slo.Name)
+	}
+	for _, query := range []string{slo.ErrorQuery, slo.TotalQuery} {
+		if !strings.Contains(query, sloWindowVariable) {
+			return fmt.Errorf("query %v of slo %v has no %v placeholder", query, slo.Name, sloWindowVariable)
+		}
+	}
+	return nil
+}
+
+func getSLOErrorRatioRecordName(window string) string {
+	return fmt.Sprintf("slo:sli_error:ratio_rate%v", window)
+}
+
+func parseSLODuration(window string) time.Duration {
+	duration, _ := commonmodel.ParseDuration(window)
+	return time.Duration(duration)
+}
+
+// Burn rate at which the budget share of the alert is consumed within its long window
+func getBurnRateFactor(alert burnRateAlert, window time.Duration) string {
+	factor := float64(int64(alert.budgetPercent)*int64(window)) / float64(100*int64(parseSLODuration(alert.longWindow)))
+	return strconv.FormatFloat(factor, 'f', -1, 64)
+}
+
+// One group per objective. Windows longer than the error budget period are not recorded
+// and have no alert. Only valid objectives are expected.
+func GetServiceLevelObjectiveRuleGroup(slo *v1.ServiceLevelObjective) prometheusv1.RuleGroup {
+	window := getSLOWindow(slo)
+	period := parseSLODuration(window)
+	selector := fmt.Sprintf(`{slo="%v"}`, slo.Name)
+	errorBudget := fmt.Sprintf("(1 - %v / 100)", slo.Target)
+
+	group := prometheusv1.RuleGroup{
+		Name: fmt.Sprintf("slo-%v", slo.Name),
+	}
+	record := func(name string, expr string) {
+		group.Rules = append(group.Rules, prometheusv1.Rule{
+			Record: name,
+			Expr:   intstr.FromString(expr),
+			Labels: map[string]string{
+				"slo": slo.Name,
+			},
+		})
+	}
+
+	var windows []string
+	seen := map[string]bool{}
+	for _, alert := range burnRateAlerts {
+		for _, w := range []string{alert.shortWindow, alert.longWindow} {
+			if !seen[w] && parseSLODuration(w) <= period {
+				windows = append(windows, w)
+				seen[w] = true
+			}
+		}
+	}
+	if !seen[window] {
+		windows = append(windows, window)
+	}
+	for _, w := range windows {
+		errorQuery := strings.ReplaceAll(slo.ErrorQuery, sloWindowVariable, w)
+		totalQuery := strings.ReplaceAll(slo.TotalQuery, sloWindowVariable, w)
+		record(getSLOErrorRatioRecordName(w), fmt.Sprintf("(%v) / (%v)", errorQuery, totalQuery))
+	}
+
+	errorRatio := getSLOErrorRatioRecordName(window) + selector
+	record("slo:objective:ratio", fmt.Sprintf("vector(%v / 100)", slo.Target))
+	record("slo:sli:ratio", fmt.Sprintf("1 - %v", errorRatio))
+	record("slo:error_budget:remaining", fmt.Sprintf("1 - %v / %v", errorRatio, errorBudget))
+
+	for _, alert := range burnRateAlerts {
+		if parseSLODuration(alert.longWindow) > period {
+			continue
+		}
+
+		threshold := fmt.Sprintf("(%v * %v)", getBurnRateFactor(alert, period), errorBudget)
+		alertLabels := map[string]string{}
+		for name, value := range slo.Labels {
+			alertLabels[name] = value
+		}
+		alertLabels["slo"] = slo.Name
+		alertLabels["severity"] = alert.severity
+		alertLabels["long_window"] = alert.longWindow
+
+		group.Rules = append(group.Rules, prometheusv1.Rule{
+			Alert: "ErrorBudgetBurn",
+			Expr: intstr.FromString(fmt.Sprintf("%v%v > %v and %v%v > %v",
+				getSLOErrorRatioRecordName(alert.longWindow), selector, threshold,
+				getSLOErrorRatioRecordName(alert.shortWindow), selector, threshold)),
+			Labels: alertLabels,
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("Error budget of the SLO %v is burning too fast", slo.Name),
+				"description": fmt.Sprintf("At the error rate of the last %v, %v%% of the %v error budget of %v is consumed within %v.",
+					alert.longWindow, alert.budgetPercent, window, slo.Name, alert.longWindow),
+			},
+		})
+	}
+	return group
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestSLOResources_GetServiceLevelObjectiveRuleGroup(t *testing.T) {
+	RegisterTestingT(t)
+	slo := &v1.ServiceLevelObjective{
+		Name:       "api-availability",
+		ErrorQuery: `sum(rate(http_requests_total{code=~"5.."}[$window]))`,
+		TotalQuery: `sum(rate(http_requests_total[$window]))`,
+		Target:     "99.9",
+		Labels: map[string]string{
+			"team":     "api",
+			"severity": "info",
+		},
+	}
+	Expect(ValidateServiceLevelObjective(slo)).To(Succeed())
+
+	group := GetServiceLevelObjectiveRuleGroup(slo)
+	Expect(group.Name).To(Equal("slo-api-availability"))
+
+	records := map[string]string{}
+	var alerts []string
+	for _, rule := range group.Rules {
+		if rule.Record != "" {
+			records[rule.Record] = rule.Expr.String()
+			Expect(rule.Labels).To(Equal(map[string]string{"slo": "api-availability"}))
+			continue
+		}
+		alerts = append(alerts, rule.Expr.String())
+		Expect(rule.Labels).To(HaveKeyWithValue("team", "api"))
+		Expect(rule.Labels["severity"]).To(BeElementOf("critical", "warning"))
+	}
+	Expect(records).To(HaveLen(11))
+	Expect(records).To(HaveKeyWithValue("slo:sli_error:ratio_rate5m", `(sum(rate(http_requests_total{code=~"5.."}[5m]))) / (sum(rate(http_requests_total[5m])))`))
+	Expect(records).To(HaveKey("slo:sli_error:ratio_rate30d"))
+	Expect(records).To(HaveKeyWithValue("slo:error_budget:remaining", `1 - slo:sli_error:ratio_rate30d{slo="api-availability"} / (1 - 99.9 / 100)`))
+
+	// The burn rates of the default period consume 2%, 5%, 10% and 10% of the budget
+	Expect(alerts).To(Equal([]string{
+		`slo:sli_error:ratio_rate1h{slo="api-availability"} > (14.4 * (1 - 99.9 / 100)) and slo:sli_error:ratio_rate5m{slo="api-availability"} > (14.4 * (1 - 99.9 / 100))`,
+		`slo:sli_error:ratio_rate6h{slo="api-availability"} > (6 * (1 - 99.9 / 100)) and slo:sli_error:ratio_rate30m{slo="api-availability"} > (6 * (1 - 99.9 / 100))`,
+		`slo:sli_error:ratio_rate1d{slo="api-availability"} > (3 * (1 - 99.9 / 100)) and slo:sli_error:ratio_rate2h{slo="api-availability"} > (3 * (1 - 99.9 / 100))`,
+		`slo:sli_error:ratio_rate3d{slo="api-availability"} > (1 * (1 - 99.9 / 100)) and slo:sli_error:ratio_rate6h{slo="api-availability"} > (1 * (1 - 99.9 / 100))`,
+	}))
+
+	// Windows beyond a shorter period are not recorded and have no alert
+	slo.Window = "1d"
+	group = GetServiceLevelObjectiveRuleGroup(slo)
+	alerts = nil
+	for _, rule := range group.Rules {
+		Expect(rule.Record).ToNot(Equal("slo:sli_error:ratio_rate3d"))
+		if rule.Alert != "" {
+			alerts = append(alerts, rule.Labels["long_window"])
+		}
+	}
+	Expect(alerts).To(Equal([]string{"1h", "6h", "1d"}))
+
+	slo.Target = "100"
+	Expect(ValidateServiceLevelObjective(slo)).ToNot(Succeed())
+	slo.Target = "99"
+	slo.TotalQuery = "sum(rate(http_requests_total[5m]))"
+	Expect(ValidateServiceLevelObjective(slo)).ToNot(Succeed())
+
+	Expect(json.Valid([]byte(GetServiceLevelObjectiveDashboard(buildObservabilityCR(nil)).Spec.Json))).To(BeTrue())
+}
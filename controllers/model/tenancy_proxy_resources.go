@@ -0,0 +1,108 @@
+package model
+
+import (
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	KubeRBACProxyImage     = "quay.io/brancz/kube-rbac-proxy"
+	KubeRBACProxyImageTag  = "v0.13.1"
+	PromLabelProxyImage    = "quay.io/prometheuscommunity/prom-label-proxy"
+	PromLabelProxyImageTag = "v0.5.0"
+
+	TenancyProxyPort         = 9092
+	TenancyProxyConfigKey    = "config.yaml"
+	PromLabelProxyListenPort = 9095
+	// The label of the series and the query parameter of the requests
+	tenancyProxyLabel         = "namespace"
+	tenancyProxyAllowedPaths  = "/api/v1/query,/api/v1/query_range,/api/v1/series,/api/v1/labels,/api/v1/label/*/values"
+	tenancyProxyConfigMapName = "prometheus-tenancy-proxy"
+)
+
+func GetTenancyProxyConfigMap(cr *v1.Observability) *v13.ConfigMap {
+	return &v13.ConfigMap{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      tenancyProxyConfigMapName,
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+			Labels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+	}
+}
+
+// Requests are authorized by a subject access review for the pod metrics of the namespace in the
+// query parameter, the same check as the tenancy port of the OpenShift Thanos querier
+func GetTenancyProxyConfig() string {
+	return fmt.Sprintf(`authorization:
+  rewrites:
+    byQueryParameter:
+      name: %v
+  resourceAttributes:
+    apiVersion: metrics.k8s.io/v1beta1
+    resource: pods
+    namespace: "{{ .Value }}"
+`, tenancyProxyLabel)
+}
+
+func GetPrometheusTenancyRoute(cr *v1.Observability) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      fmt.Sprintf("%v-tenancy", GetDefaultNamePrometheus(cr)),
+			Namespace: cr.GetPrometheusOperatorNamespace(),
+		},
+	}
+}
+
+// kube-rbac-proxy authorizes the namespace, prom-label-proxy enforces it as label matcher on all
+// queries before they reach Prometheus. Only the read APIs are passed.
+func GetTenancyProxyContainers(cr *v1.Observability) []v13.Container {
+	configMap := GetTenancyProxyConfigMap(cr)
+	return []v13.Container{
+		{
+			Name:  "kube-rbac-proxy-tenancy",
+			Image: GetImage(cr, v1.ImageKubeRBACProxy, KubeRBACProxyImage, KubeRBACProxyImageTag),
+			Args: append([]string{
+				fmt.Sprintf("--secure-listen-address=0.0.0.0:%v", TenancyProxyPort),
+				fmt.Sprintf("--upstream=http://127.0.0.1:%v", PromLabelProxyListenPort),
+				fmt.Sprintf("--config-file=/etc/tenancy-proxy/%v", TenancyProxyConfigKey),
+				fmt.Sprintf("--allow-paths=%v", tenancyProxyAllowedPaths),
+				"--tls-cert-file=/etc/tls/private/tls.crt",
+				"--tls-private-key-file=/etc/tls/private/tls.key",
+				"--logtostderr=true",
+			}, GetKubeRBACProxyTLSArgs(cr)...),
+			SecurityContext: GetContainerSecurityContext(cr),
+			Ports: []v13.ContainerPort{
+				{
+					Name:          "tenancy",
+					ContainerPort: TenancyProxyPort,
+				},
+			},
+			VolumeMounts: []v13.VolumeMount{
+				{
+					Name:      "secret-prometheus-k8s-tls",
+					MountPath: "/etc/tls/private",
+				},
+				{
+					Name:      fmt.Sprintf("configmap-%v", configMap.Name),
+					MountPath: "/etc/tenancy-proxy",
+				},
+			},
+		},
+		{
+			Name:  "prom-label-proxy",
+			Image: GetImage(cr, v1.ImagePromLabelProxy, PromLabelProxyImage, PromLabelProxyImageTag),
+			Args: []string{
+				fmt.Sprintf("--insecure-listen-address=127.0.0.1:%v", PromLabelProxyListenPort),
+				"--upstream=http://127.0.0.1:9090",
+				fmt.Sprintf("--label=%v", tenancyProxyLabel),
+			},
+			SecurityContext: GetContainerSecurityContext(cr),
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestTenancyProxyResources_GetTenancyProxyConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	var config map[string]interface{}
+	Expect(yaml.Unmarshal([]byte(GetTenancyProxyConfig()), &config)).To(Succeed())
+	Expect(config).To(HaveKey("authorization"))
+}
+
+func TestTenancyProxyResources_GetTenancyProxyContainers(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.FIPSMode = &([]bool{true})[0]
+	})
+	containers := GetTenancyProxyContainers(cr)
+	Expect(containers).To(HaveLen(2))
+	Expect(containers[0].Args).To(ContainElement("--tls-min-version=VersionTLS12"))
+	Expect(containers[0].Ports[0].ContainerPort).To(Equal(int32(TenancyProxyPort)))
+	// The label proxy is only reachable through kube-rbac-proxy
+	Expect(containers[1].Args).To(ContainElement("--insecure-listen-address=127.0.0.1:9095"))
+	Expect(containers[1].Args).To(ContainElement("--label=namespace"))
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// Namespace of the tenant that a dashboard was copied from. Namespaces opt in to the tenants of a
+	// namespace with the same label.
+	TenantLabel = "observability.redhat.com/tenant"
+
+	tenantDashboardPrefix = "tenant-"
+)
+
+// Metric prefix of a team, e.g. payments_
+var tenantAllowlistPrefix = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9]*)_`)
+
+// Prefixes of the metrics of the cluster and its components, no tenant can claim them
+var reservedAllowlistPrefixes = map[string]bool{
+	"kube": true, "kubelet": true, "kubernetes": true, "node": true, "container": true, "machine": true,
+	"apiserver": true, "etcd": true, "scheduler": true, "workqueue": true, "rest": true, "storage": true,
+	"cluster": true, "namespace": true, "pod": true, "coredns": true, "openshift": true, "process": true,
+	"go": true, "prometheus": true, "alertmanager": true, "grafana": true, "scrape": true, "observability": true,
+}
+
+// Patterns of a tenant have to start with the literal metric prefix of the team, so that a tenant
+// can't remote write all metrics or the metrics of the cluster
+func ValidateTenantAllowlistPattern(pattern string) error {
+	compiled, err := regexp.Compile(fmt.Sprintf("^(?:%v)$", pattern))
+	if err != nil {
+		return fmt.Errorf("invalid remote write allowlist pattern %v: %v", pattern, err)
+	}
+	prefix, _ := compiled.LiteralPrefix()
+	match := tenantAllowlistPrefix.FindStringSubmatch(prefix)
+	if match == nil {
+		return fmt.Errorf("remote write allowlist pattern %v doesn't start with a metric prefix such as team_", pattern)
+	}
+	if reservedAllowlistPrefixes[strings.ToLower(match[1])] {
+		return fmt.Errorf("remote write allowlist pattern %v starts with the reserved metric prefix %v", pattern, match[0])
+	}
+	return nil
+}
+
+// Metric filter of the CR with the allowlists of the tenants and the remote write probe added to its
+// keep list. Without a keep list all metrics are sent, the allowlists don't change that. Invalid patterns
+// of the tenants are skipped.
+func GetMetricFilter(cr *v1.Observability) *v1.MetricFilter {
+	filter := cr.Spec.MetricFilter
+	if filter == nil || len(filter.Keep) == 0 || len(cr.Status.Tenants) == 0 && !cr.RemoteWriteProbeEnabled() {
+		return filter
+	}
+	result := filter.DeepCopy()
+	for _, tenant := range cr.Status.Tenants {
+		for _, pattern := range tenant.RemoteWriteAllowlist {
+			if ValidateTenantAllowlistPattern(pattern) == nil {
+				result.Keep = append(result.Keep, pattern)
+			}
+		}
+	}
+	if cr.RemoteWriteProbeEnabled() {
+		result.Keep = append(result.Keep, RemoteWriteProbeMetric)
+	}
+	return result
+}
+
+// Copy of a dashboard of a tenant in the namespace of Grafana, named after the tenant, the config
+// map and the key. Names that are too long are truncated.
+func GetTenantDashboard(cr *v1.Observability, tenant *v1.TenantStatus, configMap string, key string) *v1alpha1.GrafanaDashboard {
+	name := strings.ToLower(fmt.Sprintf("%v%v-%v-%v", tenantDashboardPrefix, tenant.Namespace, configMap, strings.TrimSuffix(key, ".json")))
+	name = strings.NewReplacer("_", "-", ".", "-").Replace(name)
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength], "-")
+	}
+	return &v1alpha1.GrafanaDashboard{
+		ObjectMeta: v12.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+		},
+	}
+}
+
+func IsTenantDashboard(name string) bool {
+	return strings.HasPrefix(name, tenantDashboardPrefix)
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestTenantResources_GetMetricFilter(t *testing.T) {
+	RegisterTestingT(t)
+
+	tenants := []v1.TenantStatus{{Name: "payments", Namespace: "payments", RemoteWriteAllowlist: []string{"payments_.*"}}}
+
+	// Without a keep list everything is sent already
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.MetricFilter = &v1.MetricFilter{Drop: []string{".*_bucket"}}
+		obsCR.Status.Tenants = tenants
+	})
+	Expect(GetMetricFilter(cr)).To(Equal(cr.Spec.MetricFilter))
+
+	cr.Spec.MetricFilter.Keep = []string{"kafka_.*"}
+	Expect(GetMetricFilter(cr).Keep).To(Equal([]string{"kafka_.*", "payments_.*"}))
+	Expect(cr.Spec.MetricFilter.Keep).To(Equal([]string{"kafka_.*"}))
+
+	// Patterns that would keep all metrics are skipped
+	cr.Status.Tenants[0].RemoteWriteAllowlist = []string{"payments_.*", ".*", "payments_.*|.*"}
+	Expect(GetMetricFilter(cr).Keep).To(Equal([]string{"kafka_.*", "payments_.*"}))
+}
+
+func TestTenantResources_ValidateTenantAllowlistPattern(t *testing.T) {
+	RegisterTestingT(t)
+
+	for pattern, valid := range map[string]bool{
+		"payments_.*":                      true,
+		"payments_(requests|errors)_total": true,
+		"nodejs_payments_.*":               true,
+		".*":                               false,
+		"p.*":                              false,
+		"payments.*":                       false,
+		"payments_.*|.*":                   false,
+		"payments_(":                       false,
+		"kube_.*":                          false,
+		"kube_pod_(info|labels)":           false,
+		"node_.*":                          false,
+		"container_.*":                     false,
+		"apiserver_.*":                     false,
+		"Kube_.*":                          false,
+		"observability_operator_.*":        false,
+		"prometheus_remote_storage_.*":     false,
+	} {
+		if valid {
+			Expect(ValidateTenantAllowlistPattern(pattern)).To(Succeed(), pattern)
+		} else {
+			Expect(ValidateTenantAllowlistPattern(pattern)).ToNot(Succeed(), pattern)
+		}
+	}
+}
+
+func TestTenantResources_GetTargetNamespaces(t *testing.T) {
+	RegisterTestingT(t)
+
+	tenants := []v1.TenantStatus{{Name: "payments", Namespace: "payments", Namespaces: []string{"payments", "payments-staging"}}}
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Status.Tenants = tenants
+	})
+	Expect(GetTargetNamespaces(cr)).To(Equal([]string{cr.GetPrometheusOperatorNamespace()}))
+
+	cr.Spec.TargetNamespaces = []string{"kafka"}
+	Expect(GetTargetNamespaces(cr)).To(Equal([]string{cr.GetPrometheusOperatorNamespace(), "kafka", "payments", "payments-staging"}))
+	Expect(cr.Spec.TargetNamespaces).To(Equal([]string{"kafka"}))
+}
+
+func TestTenantResources_GetTenantDashboard(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	tenant := &v1.TenantStatus{Name: "payments", Namespace: "payments"}
+	dashboard := GetTenantDashboard(cr, tenant, "payments-dashboards", "checkout_latency.json")
+	Expect(dashboard.Name).To(Equal("tenant-payments-payments-dashboards-checkout-latency"))
+	Expect(dashboard.Namespace).To(Equal(cr.Namespace))
+	Expect(IsTenantDashboard(dashboard.Name)).To(BeTrue())
+}
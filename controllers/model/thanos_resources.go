@@ -0,0 +1,200 @@
+package model
+
+import (
+	"fmt"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	ThanosImage    = "quay.io/thanos/thanos"
+	ThanosImageTag = "v0.28.0"
+
+	ThanosCompactorName = "thanos-compactor"
+	ThanosCompactorPort = 10902
+
+	defaultThanosRetentionThreshold = "30d"
+	defaultThanosRetention5m        = "180d"
+	defaultThanosRetention1h        = "1y"
+	defaultThanosStorageSize        = "50Gi"
+	thanosObjectStorageDir          = "/etc/thanos"
+	thanosDataDir                   = "/var/thanos/compact"
+	thanosDataVolume                = "data"
+	thanosObjectStorageVolume       = "objstore"
+)
+
+func GetThanosImage(cr *v1.Observability) string {
+	return GetImage(cr, v1.ImageThanos, ThanosImage, ThanosImageTag)
+}
+
+// Sidecar of Prometheus that uploads the blocks, nil without an object storage config
+func GetPrometheusThanosSpec(cr *v1.Observability) *prometheusv1.ThanosSpec {
+	if cr.Spec.Thanos == nil || cr.Spec.Thanos.ObjectStorageConfig == nil {
+		return nil
+	}
+	image := GetThanosImage(cr)
+	return &prometheusv1.ThanosSpec{
+		Image:               &image,
+		ObjectStorageConfig: cr.Spec.Thanos.ObjectStorageConfig,
+	}
+}
+
+func getThanosCompactorObjectMeta(cr *v1.Observability) v12.ObjectMeta {
+	return v12.ObjectMeta{
+		Name:      ThanosCompactorName,
+		Namespace: cr.GetPrometheusOperatorNamespace(),
+		Labels: map[string]string{
+			"app.kubernetes.io/component": "thanos",
+			"app.kubernetes.io/name":      ThanosCompactorName,
+			"managed-by":                  "observability-operator",
+		},
+	}
+}
+
+func GetThanosCompactorStatefulSet(cr *v1.Observability) *v13.StatefulSet {
+	return &v13.StatefulSet{
+		ObjectMeta: getThanosCompactorObjectMeta(cr),
+	}
+}
+
+func GetThanosCompactorService(cr *v1.Observability) *v14.Service {
+	return &v14.Service{
+		ObjectMeta: getThanosCompactorObjectMeta(cr),
+	}
+}
+
+func getThanosSetting(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// Retention of the raw samples, the 5m and the 1h resolution in object storage
+func GetThanosCompactorRetentions(cr *v1.Observability, retention string) (string, string, string) {
+	compactor := cr.Spec.Thanos.Compactor
+	return getThanosSetting(compactor.RetentionRaw, retention),
+		getThanosSetting(compactor.Retention5m, defaultThanosRetention5m),
+		getThanosSetting(compactor.Retention1h, defaultThanosRetention1h)
+}
+
+// The compactor is only worth its disk for a long retention
+func IsThanosCompactorRequired(cr *v1.Observability, retention string) (bool, error) {
+	if cr.Spec.Thanos == nil || cr.Spec.Thanos.ObjectStorageConfig == nil || cr.Spec.Thanos.Compactor == nil ||
+		!cr.Spec.Thanos.Compactor.IsEnabled() {
+		return false, nil
+	}
+	compactor := cr.Spec.Thanos.Compactor
+
+	threshold, err := commonmodel.ParseDuration(getThanosSetting(compactor.RetentionThreshold, defaultThanosRetentionThreshold))
+	if err != nil {
+		return false, fmt.Errorf("invalid thanos compactor retention threshold %v", compactor.RetentionThreshold)
+	}
+	current, err := commonmodel.ParseDuration(retention)
+	if err != nil {
+		return false, err
+	}
+
+	raw, downsampled5m, downsampled1h := GetThanosCompactorRetentions(cr, retention)
+	for _, value := range []string{raw, downsampled5m, downsampled1h} {
+		if _, err := commonmodel.ParseDuration(value); err != nil {
+			return false, fmt.Errorf("invalid thanos compactor retention %v", value)
+		}
+	}
+	if _, err := resource.ParseQuantity(getThanosSetting(compactor.StorageSize, defaultThanosStorageSize)); err != nil {
+		return false, fmt.Errorf("invalid thanos compactor storage size %v", compactor.StorageSize)
+	}
+	return current > threshold, nil
+}
+
+func GetThanosCompactorContainer(cr *v1.Observability, retention string, proxyEnv []v14.EnvVar) v14.Container {
+	raw, downsampled5m, downsampled1h := GetThanosCompactorRetentions(cr, retention)
+	return v14.Container{
+		Name:  ThanosCompactorName,
+		Image: GetThanosImage(cr),
+		Args: []string{
+			"compact",
+			"--wait",
+			fmt.Sprintf("--data-dir=%v", thanosDataDir),
+			fmt.Sprintf("--objstore.config-file=%v/%v", thanosObjectStorageDir, cr.Spec.Thanos.ObjectStorageConfig.Key),
+			fmt.Sprintf("--http-address=0.0.0.0:%v", ThanosCompactorPort),
+			fmt.Sprintf("--retention.resolution-raw=%v", raw),
+			fmt.Sprintf("--retention.resolution-5m=%v", downsampled5m),
+			fmt.Sprintf("--retention.resolution-1h=%v", downsampled1h),
+		},
+		Env: proxyEnv,
+		Ports: []v14.ContainerPort{
+			{
+				Name:          "http",
+				ContainerPort: ThanosCompactorPort,
+			},
+		},
+		LivenessProbe: &v14.Probe{
+			ProbeHandler: v14.ProbeHandler{
+				HTTPGet: &v14.HTTPGetAction{
+					Path: "/-/healthy",
+					Port: intstr.FromInt(ThanosCompactorPort),
+				},
+			},
+		},
+		ReadinessProbe: &v14.Probe{
+			ProbeHandler: v14.ProbeHandler{
+				HTTPGet: &v14.HTTPGetAction{
+					Path: "/-/ready",
+					Port: intstr.FromInt(ThanosCompactorPort),
+				},
+			},
+		},
+		VolumeMounts: []v14.VolumeMount{
+			{
+				Name:      thanosDataVolume,
+				MountPath: thanosDataDir,
+			},
+			{
+				Name:      thanosObjectStorageVolume,
+				MountPath: thanosObjectStorageDir,
+				ReadOnly:  true,
+			},
+		},
+		SecurityContext: GetContainerSecurityContext(cr),
+	}
+}
+
+func GetThanosCompactorVolumes(cr *v1.Observability) []v14.Volume {
+	return []v14.Volume{
+		{
+			Name: thanosObjectStorageVolume,
+			VolumeSource: v14.VolumeSource{
+				Secret: &v14.SecretVolumeSource{
+					SecretName: cr.Spec.Thanos.ObjectStorageConfig.Name,
+				},
+			},
+		},
+	}
+}
+
+// The working directory holds the blocks being compacted, it is only a cache of object storage
+func GetThanosCompactorVolumeClaimTemplate(cr *v1.Observability) v14.PersistentVolumeClaim {
+	compactor := cr.Spec.Thanos.Compactor
+	return v14.PersistentVolumeClaim{
+		ObjectMeta: v12.ObjectMeta{
+			Name: thanosDataVolume,
+		},
+		Spec: v14.PersistentVolumeClaimSpec{
+			AccessModes:      []v14.PersistentVolumeAccessMode{v14.ReadWriteOnce},
+			StorageClassName: compactor.StorageClassName,
+			Resources: v14.ResourceRequirements{
+				Requests: v14.ResourceList{
+					v14.ResourceStorage: resource.MustParse(getThanosSetting(compactor.StorageSize, defaultThanosStorageSize)),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v14 "k8s.io/api/core/v1"
+)
+
+func buildThanosCR(compactor *v1.ThanosCompactor) *v1.Observability {
+	return buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.Thanos = &v1.ThanosSpec{
+			ObjectStorageConfig: &v14.SecretKeySelector{
+				LocalObjectReference: v14.LocalObjectReference{Name: "thanos-objstore"},
+				Key:                  "objstore.yml",
+			},
+			Compactor: compactor,
+		}
+	})
+}
+
+func TestThanosResources_GetPrometheusThanosSpec(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetPrometheusThanosSpec(buildObservabilityCR(nil))).To(BeNil())
+
+	spec := GetPrometheusThanosSpec(buildThanosCR(nil))
+	Expect(*spec.Image).To(Equal("quay.io/thanos/thanos:v0.28.0"))
+	Expect(spec.ObjectStorageConfig.Name).To(Equal("thanos-objstore"))
+}
+
+func TestThanosResources_IsThanosCompactorRequired(t *testing.T) {
+	RegisterTestingT(t)
+
+	tr := true
+	required, err := IsThanosCompactorRequired(buildThanosCR(nil), "1y")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(required).To(BeFalse())
+
+	// Only for a retention above the threshold
+	required, err = IsThanosCompactorRequired(buildThanosCR(&v1.ThanosCompactor{Enabled: &tr}), "1y")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(required).To(BeTrue())
+	required, err = IsThanosCompactorRequired(buildThanosCR(&v1.ThanosCompactor{Enabled: &tr}), "15d")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(required).To(BeFalse())
+	required, err = IsThanosCompactorRequired(buildThanosCR(&v1.ThanosCompactor{Enabled: &tr, RetentionThreshold: "7d"}), "15d")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(required).To(BeTrue())
+
+	for _, compactor := range []v1.ThanosCompactor{
+		{Enabled: &tr, RetentionThreshold: "a month"},
+		{Enabled: &tr, Retention5m: "6 months"},
+		{Enabled: &tr, StorageSize: "50 gigabytes"},
+	} {
+		compactor := compactor
+		_, err = IsThanosCompactorRequired(buildThanosCR(&compactor), "1y")
+		Expect(err).To(HaveOccurred(), "%+v", compactor)
+	}
+}
+
+func TestThanosResources_GetThanosCompactorContainer(t *testing.T) {
+	RegisterTestingT(t)
+
+	tr := true
+	cr := buildThanosCR(&v1.ThanosCompactor{Enabled: &tr, Retention1h: "2y", StorageSize: "100Gi"})
+	container := GetThanosCompactorContainer(cr, "1y", nil)
+	Expect(container.Args).To(ContainElements(
+		"--objstore.config-file=/etc/thanos/objstore.yml",
+		"--retention.resolution-raw=1y",
+		"--retention.resolution-5m=180d",
+		"--retention.resolution-1h=2y",
+	))
+	Expect(GetThanosCompactorVolumes(cr)[0].Secret.SecretName).To(Equal("thanos-objstore"))
+
+	claim := GetThanosCompactorVolumeClaimTemplate(cr)
+	Expect(claim.Spec.Resources.Requests.Storage().String()).To(Equal("100Gi"))
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	OAuthProxyImage = "quay.io/openshift/origin-oauth-proxy:4.8"
+	// Red Hat build of the oauth proxy, uses the FIPS validated crypto of the host in FIPS mode
+	OAuthProxyFIPSImage = "registry.redhat.io/openshift4/ose-oauth-proxy:v4.10"
+)
+
+// FIPS approved cipher suites for TLS 1.2. TLS 1.3 suites are not configurable.
+var FIPSCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+func GetOAuthProxyImage(cr *v1.Observability) string {
+	image := OAuthProxyImage
+	if cr.FIPSModeEnabled() {
+		image = OAuthProxyFIPSImage
+	}
+	repository, tag := SplitImageTag(image)
+	return GetImage(cr, v1.ImageOAuthProxy, repository, tag)
+}
+
+// Same restrictions for kube-rbac-proxy, which takes the Go names of the cipher suites
+func GetKubeRBACProxyTLSArgs(cr *v1.Observability) []string {
+	if !cr.FIPSModeEnabled() {
+		return nil
+	}
+
+	var names []string
+	for _, suite := range FIPSCipherSuites {
+		names = append(names, tls.CipherSuiteName(suite))
+	}
+
+	return []string{
+		"--tls-min-version=VersionTLS12",
+		fmt.Sprintf("--tls-cipher-suites=%v", strings.Join(names, ",")),
+	}
+}
+
+func GetOAuthProxyImageArchitectures(cr *v1.Observability) []string {
+	if cr.FIPSModeEnabled() {
+		return GetImageArchitectures(cr, v1.ImageOAuthProxy, OAuthProxyFIPSImage)
+	}
+	return GetImageArchitectures(cr, v1.ImageOAuthProxy, OAuthProxyImage)
+}
+
+// Subject access review of the oauth proxy of the component as json
+func getOAuthProxySAR(cr *v1.Observability, component v1.OAuthProxyComponent) string {
+	authorization, ok := cr.Spec.OAuthProxyAuthorization[component]
+	if !ok {
+		authorization = v1.OAuthProxyAuthorization{Resource: "namespaces"}
+	}
+	if authorization.Verb == "" {
+		authorization.Verb = "get"
+	}
+	sar, _ := json.Marshal(authorization)
+	return string(sar)
+}
+
+// Logins and bearer tokens are authorized by the same review
+func GetOAuthProxyAuthorizationArgs(cr *v1.Observability, component v1.OAuthProxyComponent) []string {
+	sar := getOAuthProxySAR(cr, component)
+	return []string{
+		fmt.Sprintf("-openshift-sar=%v", sar),
+		fmt.Sprintf("-openshift-delegate-urls={\"/\": %v}", sar),
+	}
+}
+
+// Restricts the TLS versions and ciphers served by the oauth proxies in FIPS mode
+func GetOAuthProxyTLSArgs(cr *v1.Observability) []string {
+	if !cr.FIPSModeEnabled() {
+		return nil
+	}
+
+	var names []string
+	for _, suite := range FIPSCipherSuites {
+		names = append(names, tls.CipherSuiteName(suite))
+	}
+
+	return []string{
+		"-tls-min-version=VersionTLS12",
+		fmt.Sprintf("-tls-cipher-suite=%v", strings.Join(names, ",")),
+	}
+}
+
+// TLS configuration of the outbound requests of the operator. Certificates are verified if
+// trusted root CAs are given or FIPS mode is enabled.
+func GetTLSConfig(cr *v1.Observability, rootCAs *x509.CertPool) *tls.Config {
+	config := &tls.Config{
+		RootCAs:            rootCAs,
+		InsecureSkipVerify: rootCAs == nil,
+	}
+
+	if cr.FIPSModeEnabled() {
+		config.InsecureSkipVerify = false
+		config.MinVersion = tls.VersionTLS12
+		config.CipherSuites = FIPSCipherSuites
+	}
+
+	return config
+}
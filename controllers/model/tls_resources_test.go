@@ -0,0 +1,145 @@
+package model
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestTLSResources_GetOAuthProxyTLSArgs(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "no arguments if fips mode is disabled",
+			args: args{
+				cr: buildObservabilityCR(nil),
+			},
+			want: nil,
+		},
+		{
+			name: "restricted versions and ciphers if fips mode is enabled",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.FIPSMode = &([]bool{true})[0]
+				}),
+			},
+			want: []string{
+				"-tls-min-version=VersionTLS12",
+				"-tls-cipher-suite=TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetOAuthProxyTLSArgs(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestTLSResources_GetOAuthProxyAuthorizationArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		component v1.OAuthProxyComponent
+		want      []string
+	}{
+		{
+			name:      "defaults to users who can get namespaces",
+			component: v1.OAuthProxyPrometheus,
+			want: []string{
+				`-openshift-sar={"resource":"namespaces","verb":"get"}`,
+				`-openshift-delegate-urls={"/": {"resource":"namespaces","verb":"get"}}`,
+			},
+		},
+		{
+			name:      "review of the component",
+			component: v1.OAuthProxyGrafana,
+			want: []string{
+				`-openshift-sar={"resource":"services","verb":"get","namespace":"observability","name":"grafana-service"}`,
+				`-openshift-delegate-urls={"/": {"resource":"services","verb":"get","namespace":"observability","name":"grafana-service"}}`,
+			},
+		},
+	}
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.OAuthProxyAuthorization = map[v1.OAuthProxyComponent]v1.OAuthProxyAuthorization{
+			v1.OAuthProxyGrafana: {Resource: "services", Namespace: "observability", Name: "grafana-service"},
+		}
+	})
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Expect(GetOAuthProxyAuthorizationArgs(cr, tt.component)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestTLSResources_GetTLSConfig(t *testing.T) {
+	pool := x509.NewCertPool()
+
+	type args struct {
+		cr      *v1.Observability
+		rootCAs *x509.CertPool
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *tls.Config
+	}{
+		{
+			name: "skip verification without root cas",
+			args: args{
+				cr:      buildObservabilityCR(nil),
+				rootCAs: nil,
+			},
+			want: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+		{
+			name: "verify with root cas",
+			args: args{
+				cr:      buildObservabilityCR(nil),
+				rootCAs: pool,
+			},
+			want: &tls.Config{
+				RootCAs: pool,
+			},
+		},
+		{
+			name: "verify and restrict versions and ciphers in fips mode",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.FIPSMode = &([]bool{true})[0]
+				}),
+				rootCAs: nil,
+			},
+			want: &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				CipherSuites: FIPSCipherSuites,
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetTLSConfig(tt.args.cr, tt.args.rootCAs)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
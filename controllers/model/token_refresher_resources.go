@@ -2,6 +2,9 @@ package model
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
@@ -14,6 +17,13 @@ type TokenRefresherType string
 const (
 	MetricsTokenRefresher TokenRefresherType = "metrics"
 	LogsTokenRefresher    TokenRefresherType = "logs"
+	// Proxies the queries of Grafana to the query API of Observatorium
+	QueryTokenRefresher TokenRefresherType = "query"
+)
+
+const (
+	DefaultTokenRefresherImage    = "quay.io/rhoas/mk-token-refresher"
+	DefaultTokenRefresherImageTag = "0b54d2e"
 )
 
 type TokenRefresherConfigSet struct {
@@ -25,12 +35,85 @@ type TokenRefresherConfigSet struct {
 	Tenant           string
 	Secret           string
 	Type             TokenRefresherType
+	Image            string
+	Tag              string
+	Replicas         int32
+	Resources        v12.ResourceRequirements
 }
 
 func GetTokenRefresherName(id string, t TokenRefresherType) string {
 	return fmt.Sprintf("token-refresher-%v-%v", t, id)
 }
 
+// Url of the service of a token refresher, services are in the namespace of the token refreshers
+func GetTokenRefresherUrl(cr *v1.Observability, id string, t TokenRefresherType) string {
+	return fmt.Sprintf("http://%v.%v.svc.cluster.local", GetTokenRefresherName(id, t), cr.GetPrometheusOperatorNamespace())
+}
+
+var invalidNameCharacters = regexp.MustCompile("[^a-z0-9-]+")
+
+// Realms are not necessarily valid resource names
+func GetSharedTokenRefresherName(realm string) string {
+	name := invalidNameCharacters.ReplaceAllString(strings.ToLower(realm), "-")
+	return fmt.Sprintf("token-refresher-realm-%v", strings.Trim(name, "-"))
+}
+
+// Settings of the CR take precedence over the settings of the index
+func getTokenRefresherSpecs(cr *v1.Observability, observatorium *v1.ObservatoriumIndex) []*v1.TokenRefresherSpec {
+	var result []*v1.TokenRefresherSpec
+	if cr.Spec.TokenRefresher != nil {
+		result = append(result, cr.Spec.TokenRefresher)
+	}
+	if observatorium != nil && observatorium.TokenRefresher != nil {
+		result = append(result, observatorium.TokenRefresher)
+	}
+	return result
+}
+
+// Image without the tag and the tag, overridden independently
+func GetTokenRefresherImage(cr *v1.Observability, observatorium *v1.ObservatoriumIndex) (string, string) {
+	image, tag := GetImageRepository(cr, v1.ImageTokenRefresher, DefaultTokenRefresherImage)
+	if tag == "" {
+		tag = DefaultTokenRefresherImageTag
+	}
+	specs := getTokenRefresherSpecs(cr, observatorium)
+	for i := len(specs) - 1; i >= 0; i-- {
+		if specs[i].Image != "" {
+			image = specs[i].Image
+		}
+		if specs[i].Tag != "" {
+			tag = specs[i].Tag
+		}
+	}
+	return image, tag
+}
+
+// Architectures of the default image, nil for images set by the token refresher specs
+func GetTokenRefresherImageArchitectures(cr *v1.Observability, image string) []string {
+	if repository, _ := SplitImageTag(image); repository != GetMirroredImage(cr, DefaultTokenRefresherImage) {
+		return nil
+	}
+	return GetImageArchitectures(cr, v1.ImageTokenRefresher, DefaultTokenRefresherImage)
+}
+
+func GetTokenRefresherReplicas(cr *v1.Observability, observatorium *v1.ObservatoriumIndex) int32 {
+	for _, spec := range getTokenRefresherSpecs(cr, observatorium) {
+		if spec.Replicas != nil && *spec.Replicas > 0 {
+			return *spec.Replicas
+		}
+	}
+	return 1
+}
+
+func GetTokenRefresherResources(cr *v1.Observability, observatorium *v1.ObservatoriumIndex) v12.ResourceRequirements {
+	for _, spec := range getTokenRefresherSpecs(cr, observatorium) {
+		if spec.Resources != nil {
+			return *spec.Resources
+		}
+	}
+	return v12.ResourceRequirements{}
+}
+
 func GetTokenRefresherService(cr *v1.Observability, name string) *v12.Service {
 	return &v12.Service{
 		ObjectMeta: metav1.ObjectMeta{
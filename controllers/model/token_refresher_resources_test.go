@@ -40,6 +40,153 @@ func TestTokenRefresherResources_GetTokenRefresherName(t *testing.T) {
 	}
 }
 
+func TestTokenRefresherResources_GetSharedTokenRefresherName(t *testing.T) {
+	type args struct {
+		realm string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "realm is part of the name",
+			args: args{
+				realm: "redhat-external",
+			},
+			want: "token-refresher-realm-redhat-external",
+		},
+		{
+			name: "invalid characters of the realm are replaced",
+			args: args{
+				realm: "Red Hat_External.",
+			},
+			want: "token-refresher-realm-red-hat-external",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetSharedTokenRefresherName(tt.args.realm)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestTokenRefresherResources_GetTokenRefresherImage(t *testing.T) {
+	type args struct {
+		cr            *v1.Observability
+		observatorium *v1.ObservatoriumIndex
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantImage string
+		wantTag   string
+	}{
+		{
+			name: "default image without overrides",
+			args: args{
+				cr:            &v1.Observability{},
+				observatorium: &v1.ObservatoriumIndex{},
+			},
+			wantImage: DefaultTokenRefresherImage,
+			wantTag:   DefaultTokenRefresherImageTag,
+		},
+		{
+			name: "image of the index",
+			args: args{
+				cr: &v1.Observability{},
+				observatorium: &v1.ObservatoriumIndex{
+					TokenRefresher: &v1.TokenRefresherSpec{
+						Image: "quay.io/index/token-refresher",
+						Tag:   "index",
+					},
+				},
+			},
+			wantImage: "quay.io/index/token-refresher",
+			wantTag:   "index",
+		},
+		{
+			name: "tag of the cr takes precedence over the index",
+			args: args{
+				cr: &v1.Observability{
+					Spec: v1.ObservabilitySpec{
+						TokenRefresher: &v1.TokenRefresherSpec{
+							Tag: "cr",
+						},
+					},
+				},
+				observatorium: &v1.ObservatoriumIndex{
+					TokenRefresher: &v1.TokenRefresherSpec{
+						Image: "quay.io/index/token-refresher",
+						Tag:   "index",
+					},
+				},
+			},
+			wantImage: "quay.io/index/token-refresher",
+			wantTag:   "cr",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, tag := GetTokenRefresherImage(tt.args.cr, tt.args.observatorium)
+			Expect(image).To(Equal(tt.wantImage))
+			Expect(tag).To(Equal(tt.wantTag))
+		})
+	}
+}
+
+func TestTokenRefresherResources_GetTokenRefresherReplicas(t *testing.T) {
+	type args struct {
+		cr            *v1.Observability
+		observatorium *v1.ObservatoriumIndex
+	}
+	tests := []struct {
+		name string
+		args args
+		want int32
+	}{
+		{
+			name: "one replica without overrides",
+			args: args{
+				cr:            &v1.Observability{},
+				observatorium: &v1.ObservatoriumIndex{},
+			},
+			want: 1,
+		},
+		{
+			name: "replicas of the cr take precedence over the index",
+			args: args{
+				cr: &v1.Observability{
+					Spec: v1.ObservabilitySpec{
+						TokenRefresher: &v1.TokenRefresherSpec{
+							Replicas: &([]int32{3})[0],
+						},
+					},
+				},
+				observatorium: &v1.ObservatoriumIndex{
+					TokenRefresher: &v1.TokenRefresherSpec{
+						Replicas: &([]int32{2})[0],
+					},
+				},
+			},
+			want: 3,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetTokenRefresherReplicas(tt.args.cr, tt.args.observatorium)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestTokenRefresherResources_GetTokenRefresherService(t *testing.T) {
 	type args struct {
 		cr   *v1.Observability
@@ -1,11 +1,83 @@
 package model
 
 import (
+	"fmt"
+	"strings"
+
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	v12 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// Projected service account tokens are mounted below this directory, one directory per token
+	ServiceAccountTokenMountPath = "/var/run/secrets/tokens"
+	// The kubelet renews a projected token once 80% of its lifetime have passed
+	ServiceAccountTokenExpirationSeconds int64 = 3600
+	// Minimum lifetime of a projected token accepted by the API server
+	ServiceAccountTokenMinExpirationSeconds int64 = 600
+	// Token of the oauth proxies, used as the client secret of the service account oauth client
+	OAuthProxyTokenVolume = "oauth-proxy-token"
+	// Token of Prometheus for Alertmanager and the federation from the cluster monitoring stack
+	PrometheusTokenVolume = "prometheus-token"
+	// Namespace of the Loki ruler with the log rules of the operator
+	LogRulesNamespace = "observability-operator"
+)
+
+// Short-lived token bound to the pod instead of the long-lived token secret of the service account.
+// Without an audience the token is valid for the API server, e.g. for token reviews.
+func GetServiceAccountTokenVolume(name string, audience string, expirationSeconds int64) v12.Volume {
+	return v12.Volume{
+		Name: name,
+		VolumeSource: v12.VolumeSource{
+			Projected: &v12.ProjectedVolumeSource{
+				Sources: []v12.VolumeProjection{
+					{
+						ServiceAccountToken: &v12.ServiceAccountTokenProjection{
+							Audience:          audience,
+							ExpirationSeconds: &expirationSeconds,
+							Path:              "token",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func GetServiceAccountTokenVolumeMount(name string) v12.VolumeMount {
+	return v12.VolumeMount{
+		Name:      name,
+		MountPath: fmt.Sprintf("%s/%s", ServiceAccountTokenMountPath, name),
+		ReadOnly:  true,
+	}
+}
+
+func GetServiceAccountTokenFile(name string) string {
+	return fmt.Sprintf("%s/%s/token", ServiceAccountTokenMountPath, name)
+}
+
+// Token for an Observatorium instance with the serviceaccount auth type
+func GetObservatoriumServiceAccountTokenVolume(observatorium *v1.ObservatoriumIndex) v12.Volume {
+	audience := ""
+	expirationSeconds := ServiceAccountTokenExpirationSeconds
+	if config := observatorium.ServiceAccountTokenConfig; config != nil {
+		audience = config.Audience
+		if config.ExpirationSeconds != nil {
+			expirationSeconds = *config.ExpirationSeconds
+		}
+	}
+	if expirationSeconds < ServiceAccountTokenMinExpirationSeconds {
+		expirationSeconds = ServiceAccountTokenMinExpirationSeconds
+	}
+	return GetServiceAccountTokenVolume(GetObservatoriumServiceAccountTokenVolumeName(observatorium), audience, expirationSeconds)
+}
+
+// Volume names are DNS labels
+func GetObservatoriumServiceAccountTokenVolumeName(observatorium *v1.ObservatoriumIndex) string {
+	return fmt.Sprintf("observatorium-token-%s", strings.ToLower(strings.ReplaceAll(observatorium.Id, "_", "-")))
+}
+
 func GetTokenSecret(cr *v1.Observability, name string) *v12.Secret {
 	return &v12.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -18,3 +90,24 @@ func GetTokenSecret(cr *v1.Observability, name string) *v12.Secret {
 		},
 	}
 }
+
+// Gateway that the Observatorium instance currently uses. Unknown gateways in the status,
+// e.g. after the gateways have been changed in the index, fall back to the primary gateway.
+func GetObservatoriumGateway(active []v1.ActiveGateway, observatorium *v1.ObservatoriumIndex) string {
+	for _, gateway := range active {
+		if gateway.Observatorium == observatorium.Id && observatorium.SecondaryGateway != "" && gateway.Gateway == observatorium.SecondaryGateway {
+			return observatorium.SecondaryGateway
+		}
+	}
+	return observatorium.Gateway
+}
+
+// Loki ruler API of the tenant, the rule groups of the operator are kept in their own namespace
+func GetObservatoriumLogRulesUrl(gateway string, tenant string) string {
+	return fmt.Sprintf("%s/api/logs/v1/%s/loki/api/v1/rules/%s", gateway, tenant, LogRulesNamespace)
+}
+
+// Rules API of the tenant, replaces all rules of the tenant on every PUT
+func GetObservatoriumRulesUrl(gateway string, tenant string) string {
+	return fmt.Sprintf("%s/api/metrics/v1/%s/api/v1/rules/raw", gateway, tenant)
+}
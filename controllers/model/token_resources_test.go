@@ -46,3 +46,114 @@ func TestTokenResources_GetTokenSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenResources_GetObservatoriumGateway(t *testing.T) {
+	observatorium := &v1.ObservatoriumIndex{
+		Id:               "test",
+		Gateway:          "https://primary.example.com",
+		SecondaryGateway: "https://secondary.example.com",
+	}
+
+	type args struct {
+		active []v1.ActiveGateway
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "primary gateway without a failover",
+			args: args{
+				active: nil,
+			},
+			want: "https://primary.example.com",
+		},
+		{
+			name: "secondary gateway after a failover",
+			args: args{
+				active: []v1.ActiveGateway{
+					{
+						Observatorium: "test",
+						Gateway:       "https://secondary.example.com",
+					},
+				},
+			},
+			want: "https://secondary.example.com",
+		},
+		{
+			name: "primary gateway if the active gateway is no longer configured",
+			args: args{
+				active: []v1.ActiveGateway{
+					{
+						Observatorium: "test",
+						Gateway:       "https://old.example.com",
+					},
+				},
+			},
+			want: "https://primary.example.com",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetObservatoriumGateway(tt.args.active, observatorium)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestTokenResources_GetObservatoriumServiceAccountTokenVolume(t *testing.T) {
+	tests := []struct {
+		name           string
+		observatorium  *v1.ObservatoriumIndex
+		wantName       string
+		wantAudience   string
+		wantExpiration int64
+	}{
+		{
+			name:           "default audience and expiration",
+			observatorium:  &v1.ObservatoriumIndex{Id: "default"},
+			wantName:       "observatorium-token-default",
+			wantExpiration: ServiceAccountTokenExpirationSeconds,
+		},
+		{
+			name: "audience and expiration of the config",
+			observatorium: &v1.ObservatoriumIndex{
+				Id: "Team_A",
+				ServiceAccountTokenConfig: &v1.ServiceAccountTokenConfig{
+					Audience:          "observatorium",
+					ExpirationSeconds: &([]int64{1800})[0],
+				},
+			},
+			wantName:       "observatorium-token-team-a",
+			wantAudience:   "observatorium",
+			wantExpiration: 1800,
+		},
+		{
+			name: "expiration below the minimum of the API server",
+			observatorium: &v1.ObservatoriumIndex{
+				Id: "default",
+				ServiceAccountTokenConfig: &v1.ServiceAccountTokenConfig{
+					ExpirationSeconds: &([]int64{60})[0],
+				},
+			},
+			wantName:       "observatorium-token-default",
+			wantExpiration: ServiceAccountTokenMinExpirationSeconds,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetObservatoriumServiceAccountTokenVolume(tt.observatorium)
+			Expect(result.Name).To(Equal(tt.wantName))
+			projection := result.Projected.Sources[0].ServiceAccountToken
+			Expect(projection.Audience).To(Equal(tt.wantAudience))
+			Expect(*projection.ExpirationSeconds).To(Equal(tt.wantExpiration))
+			Expect(projection.Path).To(Equal("token"))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Namespaces with the discovery annotation, sorted by name. Namespaces are not watched, as the
+// operator can run without the permission to list them, opted in namespaces are picked up with
+// the next resync.
+func (r *ObservabilityReconciler) getDiscoveredNamespaces(ctx context.Context) ([]string, error) {
+	list := &v1.NamespaceList{}
+	err := r.List(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for i := range list.Items {
+		if model.IsDiscoveredNamespace(&list.Items[i]) {
+			result = append(result, list.Items[i].Name)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
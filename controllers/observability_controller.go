@@ -2,10 +2,8 @@ package controllers
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"io/ioutil"
-	"os"
+	"math/rand"
 	"reflect"
 	"strings"
 	"time"
@@ -18,6 +16,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus-operator/prometheus-operator/pkg/k8sutil"
+	"github.com/redhat-developer/observability-operator/v4/controllers/logging"
 	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
@@ -31,18 +30,29 @@ import (
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/prometheus_installation"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/promtail_installation"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v4/controllers/tracing"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 )
 
 const (
-	RequeueDelaySuccess    = 10 * time.Second
+	// Delay after a failed status update, the other delays come from the requeue policy of the CR
 	RequeueDelayError      = 5 * time.Second
 	ObservabilityFinalizer = "observability-cleanup"
 	NoInitConfigMapName    = "observability-operator-no-init"
@@ -51,33 +61,43 @@ const (
 // ObservabilityReconciler reconciles a Observability object
 type ObservabilityReconciler struct {
 	client.Client
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	installComplete bool
+	Log               logr.Logger
+	Scheme            *runtime.Scheme
+	Recorder          record.EventRecorder
+	ConfigMapName     string
+	installComplete   bool
+	permissionChecker *utils.PermissionChecker
+	stageFailures     map[types.NamespacedName]stageFailures
+	random            *rand.Rand
+	controller        controller.Controller
+	watchingOperands  bool
+	// Caches of the configuration syncs, kept across reconciles
+	syncState *configuration.SyncState
 }
 
+// The permissions of the stages are declared next to their reconcilers
 // +kubebuilder:rbac:groups=observability.redhat.com,resources=observabilities,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=observability.redhat.com,resources=observabilities/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=corev1,resources=configmaps,verbs=get;list;create;update;patch;delete
-// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors;alertmanagers;prometheuses;prometheuses/finalizers;alertmanagers/finalizers;servicemonitors;prometheusrules;thanosrulers;thanosrulers/finalizers,verbs=get;list;create;update;patch;delete;watch
-// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;infrastructures,verbs=get;list;watch
-// +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=privileged,verbs=use
-// +kubebuilder:rbac:groups=integreatly.org,resources=grafanas;grafanadashboards;grafanadatasources,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;create;update;delete;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;infrastructures;proxies,verbs=get;list;watch
 // +kubebuilder:rbac:urls=/metrics,verbs=get
-// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
-// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings;persistentvolumes;persistentvolumeclaims,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;statefulsets,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=operators.coreos.com,resources=catalogsources;subscriptions;operatorgroups;clusterserviceversions,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups="",resources=namespaces;pods;nodes;nodes/proxy,verbs=get;list;watch;delete;create
-// +kubebuilder:rbac:groups="",resources=secrets;serviceaccounts;configmaps;endpoints;services;nodes/proxy;persistentvolumes;persistentvolumeclaims,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=logging.openshift.io,resources=clusterloggings;clusterlogforwarders,verbs=get;list;create;update;delete;watch
-// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
+// One trace per reconcile, exported with the tracing feature
 func (r *ObservabilityReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.Log.WithValues("observability", req.NamespacedName)
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String("observability.namespace", req.Namespace),
+		attribute.String("observability.name", req.Name),
+	))
+	result, err := r.reconcile(ctx, req)
+	span.SetAttributes(attribute.Int64("requeue_after_ms", result.RequeueAfter.Milliseconds()))
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *ObservabilityReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logging.WithLevels(r.Log, "observability").WithValues("observability", req.NamespacedName)
 
 	// fetch Observability instance
 	obs := &apiv1.Observability{}
@@ -93,41 +113,147 @@ func (r *ObservabilityReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	// Add a cleanup finalizer if not already present
-	if obs.DeletionTimestamp == nil && len(obs.Finalizers) == 0 {
-		obs.Finalizers = append(obs.Finalizers, ObservabilityFinalizer)
+	// Add a cleanup finalizer if not already present, finalizers of others are kept
+	if obs.DeletionTimestamp == nil && !controllerutil.ContainsFinalizer(obs, ObservabilityFinalizer) {
+		controllerutil.AddFinalizer(obs, ObservabilityFinalizer)
 		err = r.Update(ctx, obs)
 		return ctrl.Result{}, err
 	}
 
+	// Fleet-wide defaults, only applied in memory. The CR is left as is, until the operator config
+	// is fixed, so that a broken config doesn't roll out the spec without the defaults.
+	config, err := r.getOperatorConfig(ctx)
+	if err == nil {
+		logging.DefaultLevels().SetOverrides(config.LogLevels)
+		err = applyOperatorDefaults(obs, config)
+	}
+	if err != nil {
+		log.Error(err, "error applying the operator config")
+		return ctrl.Result{}, err
+	}
+
+	// Nothing is reconciled while paused, the managed resources are left as they are
+	if obs.DeletionTimestamp == nil && obs.ReconciliationPaused() {
+		log.Info("reconciliation paused")
+		nextStatus := obs.Status.DeepCopy()
+		nextStatus.Paused = true
+		return r.updateStatus(obs, nextStatus, model.GetRequeueInterval(obs))
+	}
+
 	var finished = true
 
+	// The stages of disabled components are cleaned up first, then the others are installed
 	var stages []apiv1.ObservabilityStageName
 	if obs.DeletionTimestamp == nil {
-		stages = r.getInstallationStages()
+		stages = r.getDisabledStages(obs)
+		for _, stage := range r.getInstallationStages() {
+			if isStageEnabled(obs, stage) {
+				stages = append(stages, stage)
+			}
+		}
 	} else {
 		stages = r.getCleanupStages()
 	}
 
 	nextStatus := obs.Status.DeepCopy()
+	nextStatus.Paused = false
+
+	// Detect the cluster type once, assign it to the current CR so that all stages have access to it
+	if obs.Status.ClusterType == "" {
+		clusterType, err := utils.GetClusterType(r.Client)
+		if err != nil {
+			log.Error(err, "error detecting cluster type")
+			return ctrl.Result{}, err
+		}
+		nextStatus.ClusterType = clusterType
+		obs.Status.ClusterType = clusterType
+	}
+
+	if obs.Status.ClusterTopology == "" {
+		clusterTopology, err := utils.GetClusterTopology(ctx, r.Client)
+		if err != nil {
+			log.Error(err, "error detecting cluster topology")
+			return ctrl.Result{}, err
+		}
+		nextStatus.ClusterTopology = clusterTopology
+		obs.Status.ClusterTopology = clusterTopology
+	}
+
+	// The cluster id is otherwise read with the configuration of the managed Prometheus
+	if obs.Status.ClusterID == "" && !isStageEnabled(obs, apiv1.PrometheusConfiguration) && obs.DeletionTimestamp == nil {
+		clusterId, err := getClusterId(ctx, r.Client, obs)
+		if err != nil {
+			log.Error(err, "error reading cluster id")
+			return ctrl.Result{}, err
+		}
+		nextStatus.ClusterID = clusterId
+		obs.Status.ClusterID = clusterId
+	}
+
+	// Aggregate the tenants before the stages, so that all stages see the same tenants
+	if obs.DeletionTimestamp == nil {
+		tenants, err := r.getTenants(ctx)
+		if err != nil {
+			log.Error(err, "error listing tenants")
+			return ctrl.Result{}, err
+		}
+		nextStatus.Tenants = tenants
+		obs.Status.Tenants = tenants
+	}
+
+	if obs.DeletionTimestamp == nil {
+		var namespaces []string
+		if obs.NamespaceDiscoveryEnabled() {
+			namespaces, err = r.getDiscoveredNamespaces(ctx)
+			if err != nil {
+				log.Error(err, "error discovering namespaces")
+				return ctrl.Result{}, err
+			}
+		}
+		nextStatus.DiscoveredNamespaces = namespaces
+		obs.Status.DiscoveredNamespaces = namespaces
+	}
+
+	if obs.DeletionTimestamp == nil {
+		nextStatus.MissingPermissions = nil
+		err = r.reconcileRequiredPermissionsReport(ctx, obs, stages)
+		if err != nil {
+			log.Error(err, "error writing required permissions report")
+		}
+	}
 
 	for _, stage := range stages {
 		nextStatus.Stage = stage
+		cleanup := obs.DeletionTimestamp != nil || !isStageEnabled(obs, stage)
+
+		reconciler := r.getReconcilerForStage(stage, log)
+		if reconciler != nil && !cleanup && !r.hasRequiredPermissions(ctx, obs, stage, reconciler, nextStatus) {
+			nextStatus.StageStatus = apiv1.ResultFailed
+			finished = false
+			break
+		}
 
-		reconciler := r.getReconcilerForStage(stage)
 		if reconciler != nil {
 			var status apiv1.ObservabilityStageStatus
 			var err error
 
 			metrics.IncreaseTotalReconciliationsMetric(stage)
-			if obs.DeletionTimestamp == nil {
-				status, err = reconciler.Reconcile(ctx, obs, nextStatus)
+			start := time.Now()
+			stageCtx, span := tracing.Tracer().Start(ctx, string(stage), trace.WithAttributes(
+				attribute.String("stage", string(stage)),
+				attribute.Bool("cleanup", cleanup),
+			))
+			if cleanup {
+				status, err = reconciler.Cleanup(stageCtx, obs)
 			} else {
-				status, err = reconciler.Cleanup(ctx, obs)
+				status, err = reconciler.Reconcile(stageCtx, obs, nextStatus)
 			}
+			span.SetAttributes(attribute.String("stage.status", string(status)))
+			tracing.End(span, err)
+			metrics.ObserveReconciliationDurationMetric(stage, time.Since(start))
 
 			if err != nil {
-				log.Error(err, fmt.Sprintf("reconciler error in stage %v", stage))
+				log.Error(err, "reconciler error", "stage", stage)
 				nextStatus.LastMessage = err.Error()
 				metrics.IncreaseFailedReconciliationsMetric(stage)
 			} else {
@@ -139,7 +265,7 @@ func (r *ObservabilityReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 			// If a stage is not complete, do not continue with the next
 			if status != apiv1.ResultSuccess {
-				if obs.DeletionTimestamp == nil {
+				if !cleanup {
 					log.Info("stack install in progress", "working stage", stage)
 				} else {
 					log.Info("stack cleanup in progress", "working stage", stage)
@@ -150,28 +276,51 @@ func (r *ObservabilityReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
-	if obs.DeletionTimestamp == nil && finished && !r.installComplete {
-		r.installComplete = true
-		log.Info("stack installation complete")
+	if obs.DeletionTimestamp == nil {
+		err = r.watchOperandDrift()
+		if err != nil {
+			log.Error(err, "error watching Prometheus and Alertmanager")
+		}
+	}
+
+	if obs.DeletionTimestamp == nil && finished {
+		nextStatus.ObservedGeneration = obs.Generation
+		if !r.installComplete {
+			r.installComplete = true
+			log.Info("stack installation complete")
+		}
 	}
+	metrics.SetGenerationMetrics(obs, nextStatus)
 
 	// Ready for deletion?
 	// Only remove the finalizer when all stages were successful
 	if obs.DeletionTimestamp != nil && finished {
 		log.Info("cleanup stages complete, removing finalizer")
-		obs.Finalizers = []string{}
-		err = r.Update(ctx, obs)
+		// Patched, the spec has the defaults of the operator config
+		patch := client.MergeFrom(obs.DeepCopy())
+		controllerutil.RemoveFinalizer(obs, ObservabilityFinalizer)
+		err = r.Patch(ctx, obs, patch)
 		r.installComplete = false
 		return ctrl.Result{}, err
 	}
 
-	return r.updateStatus(obs, nextStatus)
+	return r.updateStatus(obs, nextStatus, r.getRequeueDelay(obs, finished, nextStatus))
 }
 
 func (r *ObservabilityReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	r.syncState = configuration.NewSyncState()
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Observability{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &apiv1.ObservabilityTenant{}}, handler.EnqueueRequestsFromMapFunc(r.getTenantRequests)).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.getDriftRequests), builder.WithPredicates(getDriftPredicate())).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.getDriftRequests), builder.WithPredicates(getDriftPredicate())).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.getOperatorConfigRequests)).
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.controller = c
+	return nil
 }
 
 func (r *ObservabilityReconciler) UpdateOperand(from *apiv1.Observability, to *apiv1.Observability) error {
@@ -188,21 +337,10 @@ func (r *ObservabilityReconciler) UpdateOperand(from *apiv1.Observability, to *a
 }
 
 func (r *ObservabilityReconciler) InitializeOperand(mgr ctrl.Manager) error {
-	// Try to retrieve the namespace from the pod filesystem first
 	r.Log.Info("determining if operand instantiation required")
-	var namespace string
-	namespacePath := "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
-	ns, err := ioutil.ReadFile(namespacePath)
+	namespace, err := utils.GetOperatorNamespace()
 	if err != nil {
-		// If that does not work (running locally?) try the env vars
-		namespace = os.Getenv("WATCH_NAMESPACE")
-	} else {
-		namespace = string(ns)
-	}
-
-	if namespace == "" {
-		err := errors.New("unable to create operand: cannot detect operator namespace")
-		return err
+		return fmt.Errorf("unable to create operand: %w", err)
 	}
 
 	// controller/cache will not be ready during operator 'setup', use manager client & API Reader instead
@@ -299,22 +437,86 @@ func (r *ObservabilityReconciler) getInstallationStages() []apiv1.ObservabilityS
 	}
 }
 
+// Components that ship data are stopped first, then the synced configuration is removed while the
+// Prometheus and Grafana operators are still running to process the deletions. The operators, the
+// tokens and the CSV go last.
 func (r *ObservabilityReconciler) getCleanupStages() []apiv1.ObservabilityStageName {
 	return []apiv1.ObservabilityStageName{
 		apiv1.PrometheusConfiguration,
-		apiv1.GrafanaConfiguration,
-		apiv1.PrometheusInstallation,
-		apiv1.GrafanaInstallation,
 		apiv1.AlertmanagerInstallation,
 		apiv1.PromtailInstallation,
 		apiv1.LoggingInstallation,
 		apiv1.Configuration,
+		apiv1.GrafanaConfiguration,
+		apiv1.PrometheusInstallation,
+		apiv1.GrafanaInstallation,
 		apiv1.TokenRequest,
 		apiv1.Csv,
 	}
 }
 
-func (r *ObservabilityReconciler) updateStatus(cr *apiv1.Observability, nextStatus *apiv1.ObservabilityStatus) (ctrl.Result, error) {
+// Stages are not reconciled without their permissions, the missing ones are reported in the status
+// instead of failing somewhere in the stage
+func (r *ObservabilityReconciler) hasRequiredPermissions(ctx context.Context, cr *apiv1.Observability, stage apiv1.ObservabilityStageName, reconciler reconcilers.ObservabilityReconciler, nextStatus *apiv1.ObservabilityStatus) bool {
+	provider, ok := reconciler.(reconcilers.PermissionsProvider)
+	if !ok {
+		return true
+	}
+
+	if r.permissionChecker == nil {
+		r.permissionChecker = utils.NewPermissionChecker(r.Client)
+	}
+
+	// The stage reports its own errors if the permissions can't be checked
+	missing, err := r.permissionChecker.GetMissingPermissions(ctx, provider.GetRequiredPermissions(cr))
+	if err != nil {
+		r.Log.Error(err, "error checking permissions", "stage", stage)
+		return true
+	}
+	if len(missing) == 0 {
+		return true
+	}
+
+	for _, permission := range missing {
+		nextStatus.MissingPermissions = append(nextStatus.MissingPermissions, apiv1.MissingPermission{
+			Stage:      stage,
+			Permission: permission,
+		})
+	}
+	nextStatus.LastMessage = fmt.Sprintf("stage %v is missing %v permissions, see the status and the %v config map", stage, len(missing), model.GetRequiredPermissionsConfigMap(cr).Name)
+	metrics.IncreaseFailedReconciliationsMetric(stage)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cr, v1.EventTypeWarning, "MissingPermissions", "Stage %v is missing %v permissions", stage, len(missing))
+	}
+	r.Log.Info("missing permissions", "stage", stage, "count", len(missing))
+	return false
+}
+
+// Writes the roles required by all stages for the current spec
+func (r *ObservabilityReconciler) reconcileRequiredPermissionsReport(ctx context.Context, cr *apiv1.Observability, stages []apiv1.ObservabilityStageName) error {
+	var permissions []apiv1.Permission
+	for _, stage := range stages {
+		if provider, ok := r.getReconcilerForStage(stage, r.Log).(reconcilers.PermissionsProvider); ok {
+			permissions = append(permissions, provider.GetRequiredPermissions(cr)...)
+		}
+	}
+
+	report, err := utils.GetRoleReport("observability-operator", permissions)
+	if err != nil {
+		return err
+	}
+
+	configMap := model.GetRequiredPermissionsConfigMap(cr)
+	_, err = utils.Apply(ctx, r.Client, configMap, func() error {
+		configMap.Data = map[string]string{
+			model.RequiredPermissionsKey: report,
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *ObservabilityReconciler) updateStatus(cr *apiv1.Observability, nextStatus *apiv1.ObservabilityStatus, requeueDelay time.Duration) (ctrl.Result, error) {
 	if !reflect.DeepEqual(&cr.Status, nextStatus) {
 		nextStatus.DeepCopyInto(&cr.Status)
 		err := r.Client.Status().Update(context.Background(), cr)
@@ -328,7 +530,7 @@ func (r *ObservabilityReconciler) updateStatus(cr *apiv1.Observability, nextStat
 
 	return ctrl.Result{
 		Requeue:      true,
-		RequeueAfter: RequeueDelaySuccess,
+		RequeueAfter: requeueDelay,
 	}, nil
 }
 
@@ -393,40 +595,60 @@ func observabilityInstanceWithoutStorage(namespace string) apiv1.Observability {
 	}
 }
 
-func (r *ObservabilityReconciler) getReconcilerForStage(stage apiv1.ObservabilityStageName) reconcilers.ObservabilityReconciler {
+// Subsystems of the log levels, by stage
+var stageSubsystems = map[apiv1.ObservabilityStageName]string{
+	apiv1.PrometheusInstallation:   "prometheus",
+	apiv1.PrometheusConfiguration:  "prometheus-configuration",
+	apiv1.GrafanaInstallation:      "grafana",
+	apiv1.GrafanaConfiguration:     "grafana-configuration",
+	apiv1.Csv:                      "csv",
+	apiv1.TokenRequest:             "token",
+	apiv1.PromtailInstallation:     "promtail",
+	apiv1.AlertmanagerInstallation: "alertmanager",
+	apiv1.Configuration:            "configuration",
+	apiv1.LoggingInstallation:      "logging",
+	apiv1.Migration:                "migration",
+}
+
+// The logger of the stage is named after its subsystem and has the values of log, e.g. the CR
+func (r *ObservabilityReconciler) getReconcilerForStage(stage apiv1.ObservabilityStageName, log logr.Logger) reconcilers.ObservabilityReconciler {
+	// Count all writes to managed resources
+	c := metrics.NewInstrumentedClient(r.Client)
+	logger := logging.ForSubsystem(log, stageSubsystems[stage])
+
 	switch stage {
 	case apiv1.PrometheusInstallation:
-		return prometheus_installation.NewReconciler(r.Client, r.Log, r.Scheme)
+		return prometheus_installation.NewReconciler(c, logger, r.Scheme)
 
 	case apiv1.PrometheusConfiguration:
-		return prometheus_configuration.NewReconciler(r.Client, r.Log)
+		return prometheus_configuration.NewReconciler(c, logger)
 
 	case apiv1.GrafanaInstallation:
-		return grafana_installation.NewReconciler(r.Client, r.Log)
+		return grafana_installation.NewReconciler(c, logger)
 
 	case apiv1.GrafanaConfiguration:
-		return grafana_configuration.NewReconciler(r.Client, r.Log)
+		return grafana_configuration.NewReconciler(c, logger)
 
 	case apiv1.Csv:
-		return csv.NewReconciler(r.Client, r.Log)
+		return csv.NewReconciler(c, logger)
 
 	case apiv1.TokenRequest:
-		return token.NewReconciler(r.Client, r.Log)
+		return token.NewReconciler(c, logger)
 
 	case apiv1.PromtailInstallation:
-		return promtail_installation.NewReconciler(r.Client, r.Log)
+		return promtail_installation.NewReconciler(c, logger)
 
 	case apiv1.AlertmanagerInstallation:
-		return alertmanager_installation.NewReconciler(r.Client, r.Log)
+		return alertmanager_installation.NewReconciler(c, logger)
 
 	case apiv1.Configuration:
-		return configuration.NewReconciler(r.Client, r.Log)
+		return configuration.NewReconciler(c, logger, r.Recorder, r.syncState)
 
 	case apiv1.LoggingInstallation:
-		return logging_installation.NewReconciler(r.Client, r.Log)
+		return logging_installation.NewReconciler(c, logger)
 
 	case apiv1.Migration:
-		return migration.NewReconciler(r.Client, r.Log)
+		return migration.NewReconciler(c, logger)
 
 	default:
 		return nil
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/logging"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	DefaultOperatorConfigMapName = "observability-operator-config"
+	OperatorConfigKey            = "config.yaml"
+)
+
+// Configuration of the operator in its namespace, the fleet policy shared by all CRs of the cluster
+type OperatorConfig struct {
+	// Spec fields for CRs that don't set them, e.g. the image overrides, resync period, retention or
+	// the features that are enabled by default
+	Defaults *apiv1.ObservabilitySpec `json:"defaults,omitempty"`
+	// Verbosity by subsystem, overrides the --log-levels of the operator while set
+	LogLevels map[string]int `json:"logLevels,omitempty"`
+}
+
+// Name of the operator config in the operator namespace
+func (r *ObservabilityReconciler) getOperatorConfigMapName() string {
+	if r.ConfigMapName != "" {
+		return r.ConfigMapName
+	}
+	return DefaultOperatorConfigMapName
+}
+
+// Empty if the operator namespace is unknown or the config map doesn't exist
+func (r *ObservabilityReconciler) getOperatorConfig(ctx context.Context) (*OperatorConfig, error) {
+	namespace, err := utils.GetOperatorNamespace()
+	if err != nil {
+		return &OperatorConfig{}, nil
+	}
+	return readOperatorConfig(ctx, r.Client, client.ObjectKey{Namespace: strings.TrimSpace(namespace), Name: r.getOperatorConfigMapName()})
+}
+
+// The spec of the CR with the defaults of the operator config in the config map, as the stages see it
+func GetEffectiveSpec(ctx context.Context, c client.Reader, cr *apiv1.Observability, configMap client.ObjectKey) (*apiv1.ObservabilitySpec, error) {
+	config, err := readOperatorConfig(ctx, c, configMap)
+	if err != nil {
+		return nil, err
+	}
+	effective := cr.DeepCopy()
+	err = applyOperatorDefaults(effective, config)
+	if err != nil {
+		return nil, err
+	}
+	return &effective.Spec, nil
+}
+
+func readOperatorConfig(ctx context.Context, c client.Reader, key client.ObjectKey) (*OperatorConfig, error) {
+	config := &OperatorConfig{}
+	configMap := &v1.ConfigMap{}
+	err := c.Get(ctx, key, configMap)
+	if apierrors.IsNotFound(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal([]byte(configMap.Data[OperatorConfigKey]), config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operator config %v/%v: %w", configMap.Namespace, configMap.Name, err)
+	}
+	err = logging.ValidateLevels(config.LogLevels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operator config %v/%v: %w", configMap.Namespace, configMap.Name, err)
+	}
+	return config, nil
+}
+
+// Merge the defaults into the spec of the CR, the fields the CR sets take precedence. Lists are
+// taken as a whole, objects are merged field by field. The CR itself is never updated with them.
+func applyOperatorDefaults(cr *apiv1.Observability, config *OperatorConfig) error {
+	if config.Defaults == nil {
+		return nil
+	}
+
+	defaults, err := json.Marshal(config.Defaults)
+	if err != nil {
+		return err
+	}
+	spec, err := json.Marshal(cr.Spec)
+	if err != nil {
+		return err
+	}
+	merged, err := jsonpatch.MergePatch(defaults, spec)
+	if err != nil {
+		return err
+	}
+
+	result := apiv1.ObservabilitySpec{}
+	err = json.Unmarshal(merged, &result)
+	if err != nil {
+		return err
+	}
+	cr.Spec = result
+	return nil
+}
+
+// Changes of the operator config apply to all CRs
+func (r *ObservabilityReconciler) getOperatorConfigRequests(obj client.Object) []reconcile.Request {
+	if obj.GetName() != r.getOperatorConfigMapName() {
+		return nil
+	}
+	namespace, err := utils.GetOperatorNamespace()
+	if err != nil || obj.GetNamespace() != strings.TrimSpace(namespace) {
+		return nil
+	}
+	return r.getObservabilityRequests("operator config change")
+}
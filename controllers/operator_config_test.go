@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOperatorConfig_ApplyOperatorDefaults(t *testing.T) {
+	RegisterTestingT(t)
+
+	enabled := true
+	disabled := false
+	config := &OperatorConfig{Defaults: &apiv1.ObservabilitySpec{
+		ResyncPeriod:      "1h",
+		Retention:         "30d",
+		BlueGreenUpgrades: &enabled,
+		ImageOverrides:    map[apiv1.ImageComponent]string{"prometheus": "mirror.example.com/prometheus", "grafana": "mirror.example.com/grafana"},
+		Tolerations:       []v1.Toleration{{Key: "infra"}},
+	}}
+
+	// Fields of the CR take precedence, maps are merged by key and lists replaced
+	cr := &apiv1.Observability{Spec: apiv1.ObservabilitySpec{
+		Retention:         "45d",
+		BlueGreenUpgrades: &disabled,
+		ImageOverrides:    map[apiv1.ImageComponent]string{"grafana": "docker.io/grafana/grafana"},
+		Tolerations:       []v1.Toleration{{Key: "observability"}},
+	}}
+	Expect(applyOperatorDefaults(cr, config)).To(Succeed())
+	Expect(cr.Spec.ResyncPeriod).To(Equal("1h"))
+	Expect(cr.Spec.Retention).To(Equal("45d"))
+	Expect(*cr.Spec.BlueGreenUpgrades).To(BeFalse())
+	Expect(cr.Spec.ImageOverrides).To(Equal(map[apiv1.ImageComponent]string{"prometheus": "mirror.example.com/prometheus", "grafana": "docker.io/grafana/grafana"}))
+	Expect(cr.Spec.Tolerations).To(Equal([]v1.Toleration{{Key: "observability"}}))
+
+	// The defaults are not changed by the CRs
+	Expect(config.Defaults.ImageOverrides).To(HaveLen(2))
+	Expect(applyOperatorDefaults(cr, &OperatorConfig{})).To(Succeed())
+	Expect(cr.Spec.ResyncPeriod).To(Equal("1h"))
+}
+
+func TestOperatorConfig_GetOperatorConfig(t *testing.T) {
+	RegisterTestingT(t)
+	t.Setenv("WATCH_NAMESPACE", "observability-operator")
+
+	scheme := runtime.NewScheme()
+	Expect(v1.AddToScheme(scheme)).To(Succeed())
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "observability-operator", Name: "fleet-config"},
+		Data:       map[string]string{OperatorConfigKey: "defaults:\n  retention: 30d\n"},
+	}
+	r := &ObservabilityReconciler{
+		Client:        fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		ConfigMapName: "fleet-config",
+	}
+
+	config, err := r.getOperatorConfig(context.TODO())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(config.Defaults.Retention).To(Equal("30d"))
+
+	// A missing config has no defaults, an invalid one fails
+	r.ConfigMapName = "missing"
+	config, err = r.getOperatorConfig(context.TODO())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(config.Defaults).To(BeNil())
+
+	configMap.Data[OperatorConfigKey] = "defaults: [retention]"
+	Expect(r.Update(context.TODO(), configMap)).To(Succeed())
+	r.ConfigMapName = "fleet-config"
+	_, err = r.getOperatorConfig(context.TODO())
+	Expect(err).To(MatchError(ContainSubstring("invalid operator config")))
+
+	configMap.Data[OperatorConfigKey] = "logLevels:\n  configuration: 2\n"
+	Expect(r.Update(context.TODO(), configMap)).To(Succeed())
+	config, err = r.getOperatorConfig(context.TODO())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(config.LogLevels).To(Equal(map[string]int{"configuration": 2}))
+
+	configMap.Data[OperatorConfigKey] = "logLevels:\n  configuration: 9\n"
+	Expect(r.Update(context.TODO(), configMap)).To(Succeed())
+	_, err = r.getOperatorConfig(context.TODO())
+	Expect(err).To(MatchError(ContainSubstring("invalid level of subsystem configuration")))
+}
@@ -0,0 +1,73 @@
+package querylog
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const DefaultListenAddress = ":9097"
+
+// Run the exporter next to Prometheus with the query log on a shared volume, e.g.
+// /manager query-log-exporter -query-log-file /var/log/prometheus/query.log
+func Run(logger logr.Logger, args []string) error {
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	listenAddress := flags.String("listen-address", DefaultListenAddress, "The address the metrics endpoint binds to.")
+	file := flags.String("query-log-file", "/var/log/prometheus/query.log", "Path of the query log of Prometheus.")
+	threshold := flags.Duration("slow-query-threshold", DefaultThreshold, "Queries slower than the threshold are exported.")
+	maxQueries := flags.Int("max-queries", DefaultMaxQueries, "Distinct slow queries exported with their query.")
+	maxSize := flags.Int64("max-size", DefaultMaxSize, "Size in bytes after which the query log is truncated.")
+	interval := flags.Duration("interval", 10*time.Second, "Interval of the reads of the query log.")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := NewExporter(Config{
+		File:       *file,
+		Threshold:  *threshold,
+		MaxQueries: *maxQueries,
+		MaxSize:    *maxSize,
+	}, logger)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              *listenAddress,
+		Handler:           exporter.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				_ = server.Shutdown(shutdown)
+				return
+			case <-ticker.C:
+				if err := exporter.Read(); err != nil {
+					logger.Error(err, "error reading the query log", "file", *file)
+				}
+			}
+		}
+	}()
+
+	logger.Info("exporting slow queries", "address", *listenAddress, "file", *file, "threshold", *threshold)
+	err = server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
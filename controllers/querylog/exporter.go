@@ -0,0 +1,245 @@
+package querylog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// First argument of the operator binary that runs the exporter instead of the operator
+	Command           = "query-log-exporter"
+	DefaultThreshold  = 10 * time.Second
+	DefaultMaxQueries = 50
+	// The log is truncated once it is read past the size, Prometheus appends to it
+	DefaultMaxSize  = 100 * 1024 * 1024
+	SourceAPI       = "api"
+	SourceRule      = "rule"
+	maxQueryLength  = 500
+	metricSubsystem = "observability_query_log"
+)
+
+// Line of the query log of Prometheus, only the fields that are exported
+type entry struct {
+	Params struct {
+		Query string `json:"query"`
+	} `json:"params"`
+	Stats struct {
+		Timings struct {
+			ExecTotalTime float64 `json:"execTotalTime"`
+		} `json:"timings"`
+	} `json:"stats"`
+	RuleGroup *struct {
+		Name string `json:"name"`
+	} `json:"ruleGroup,omitempty"`
+}
+
+type Config struct {
+	File       string
+	Threshold  time.Duration
+	MaxQueries int
+	MaxSize    int64
+}
+
+type queryKey struct {
+	query  string
+	source string
+}
+
+// Follows the query log of Prometheus and exports the slow queries
+type Exporter struct {
+	config   Config
+	logger   logr.Logger
+	registry *prometheus.Registry
+
+	mutex    sync.Mutex
+	offset   int64
+	partial  []byte
+	sequence uint64
+	lastSeen map[queryKey]uint64
+
+	duration         *prometheus.HistogramVec
+	slowQueries      *prometheus.CounterVec
+	slowQueryCount   *prometheus.CounterVec
+	slowQuerySeconds *prometheus.CounterVec
+}
+
+func NewExporter(config Config, logger logr.Logger) (*Exporter, error) {
+	if config.File == "" {
+		return nil, fmt.Errorf("no query log file")
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	if config.MaxQueries <= 0 {
+		config.MaxQueries = DefaultMaxQueries
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultMaxSize
+	}
+
+	e := &Exporter{
+		config:   config,
+		logger:   logger,
+		registry: prometheus.NewRegistry(),
+		lastSeen: map[queryKey]uint64{},
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:      "query_duration_seconds",
+			Subsystem: metricSubsystem,
+			Help:      "Duration of the logged queries",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120},
+		}, []string{"source"}),
+		slowQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:      "slow_queries_total",
+			Subsystem: metricSubsystem,
+			Help:      "Number of queries slower than the threshold",
+		}, []string{"source"}),
+		slowQueryCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:      "slow_query_count",
+			Subsystem: metricSubsystem,
+			Help:      "Number of runs of the most recent slow queries",
+		}, []string{"query", "source"}),
+		slowQuerySeconds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:      "slow_query_seconds_total",
+			Subsystem: metricSubsystem,
+			Help:      "Total duration of the runs of the most recent slow queries",
+		}, []string{"query", "source"}),
+	}
+	e.registry.MustRegister(e.duration, e.slowQueries, e.slowQueryCount, e.slowQuerySeconds)
+	return e, nil
+}
+
+func (e *Exporter) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// Read the lines that were appended since the last read. The log doesn't exist until Prometheus
+// runs its first query.
+func (e *Exporter) Read() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	file, err := os.Open(e.config.File)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	// Truncated by someone else
+	if info.Size() < e.offset {
+		e.offset = 0
+		e.partial = nil
+	}
+
+	_, err = file.Seek(e.offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	content, err := io.ReadAll(io.LimitReader(file, info.Size()-e.offset))
+	if err != nil {
+		return err
+	}
+	e.offset += int64(len(content))
+
+	lines := bytes.Split(append(e.partial, content...), []byte("\n"))
+	// The last line is incomplete until it ends with a newline
+	e.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		e.observe(line)
+	}
+
+	// The lines that Prometheus appends between the read and the truncation are lost
+	if e.offset >= e.config.MaxSize {
+		err = os.Truncate(e.config.File, 0)
+		if err != nil {
+			return err
+		}
+		e.offset = 0
+		e.partial = nil
+	}
+	return nil
+}
+
+func (e *Exporter) observe(line []byte) {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	var value entry
+	err := json.Unmarshal(line, &value)
+	if err != nil {
+		e.logger.Error(err, "skipped query log line")
+		return
+	}
+
+	source := SourceAPI
+	if value.RuleGroup != nil {
+		source = SourceRule
+	}
+	seconds := value.Stats.Timings.ExecTotalTime
+	e.duration.WithLabelValues(source).Observe(seconds)
+	if time.Duration(seconds*float64(time.Second)) < e.config.Threshold {
+		return
+	}
+
+	query := normalizeQuery(value.Params.Query)
+	e.slowQueries.WithLabelValues(source).Inc()
+	e.track(queryKey{query: query, source: source})
+	e.slowQueryCount.WithLabelValues(query, source).Inc()
+	e.slowQuerySeconds.WithLabelValues(query, source).Add(seconds)
+
+	values := []interface{}{"query", query, "source", source, "seconds", seconds}
+	if value.RuleGroup != nil {
+		values = append(values, "ruleGroup", value.RuleGroup.Name)
+	}
+	e.logger.Info("slow query", values...)
+}
+
+// Only the most recent slow queries keep their series, so that the query label is bounded
+func (e *Exporter) track(key queryKey) {
+	if _, ok := e.lastSeen[key]; !ok && len(e.lastSeen) >= e.config.MaxQueries {
+		var oldest queryKey
+		oldestSequence := e.sequence
+		for existing, sequence := range e.lastSeen {
+			if sequence <= oldestSequence {
+				oldest = existing
+				oldestSequence = sequence
+			}
+		}
+		delete(e.lastSeen, oldest)
+		e.slowQueryCount.DeleteLabelValues(oldest.query, oldest.source)
+		e.slowQuerySeconds.DeleteLabelValues(oldest.query, oldest.source)
+	}
+	e.sequence++
+	e.lastSeen[key] = e.sequence
+}
+
+// Whitespace of multi-line queries from dashboards is collapsed
+func normalizeQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxQueryLength {
+		return query[:maxQueryLength]
+	}
+	return query
+}
@@ -0,0 +1,84 @@
+package querylog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const (
+	testFastQuery = `{"params":{"query":"up"},"stats":{"timings":{"execTotalTime":0.01}}}`
+	testSlowQuery = `{"params":{"query":"sum by (namespace) (\n  rate(container_cpu_usage_seconds_total[5m])\n)"},"stats":{"timings":{"execTotalTime":12.5}}}`
+	testSlowRule  = `{"params":{"query":"count(kube_pod_info)"},"stats":{"timings":{"execTotalTime":20}},"ruleGroup":{"file":"rules.yaml","name":"pods"}}`
+)
+
+func newTestExporter(t *testing.T, maxQueries int) (*Exporter, string) {
+	file := filepath.Join(t.TempDir(), "query.log")
+	exporter, err := NewExporter(Config{File: file, Threshold: 10 * time.Second, MaxQueries: maxQueries}, logr.Discard())
+	Expect(err).ToNot(HaveOccurred())
+	return exporter, file
+}
+
+func appendLog(file string, content string) {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+	_, err = f.WriteString(content)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func TestExporter_Read(t *testing.T) {
+	RegisterTestingT(t)
+
+	exporter, file := newTestExporter(t, 0)
+	// Prometheus didn't run a query yet
+	Expect(exporter.Read()).To(Succeed())
+
+	appendLog(file, testFastQuery+"\n"+testSlowQuery+"\n"+testSlowRule[:20])
+	Expect(exporter.Read()).To(Succeed())
+	Expect(testutil.ToFloat64(exporter.slowQueries.WithLabelValues(SourceAPI))).To(Equal(1.0))
+	Expect(testutil.ToFloat64(exporter.slowQuerySeconds.WithLabelValues("sum by (namespace) ( rate(container_cpu_usage_seconds_total[5m]) )", SourceAPI))).To(Equal(12.5))
+	Expect(testutil.CollectAndCount(exporter.duration)).To(Equal(1))
+
+	// The partial line is completed by the next write
+	appendLog(file, testSlowRule[20:]+"\n")
+	Expect(exporter.Read()).To(Succeed())
+	Expect(testutil.ToFloat64(exporter.slowQueries.WithLabelValues(SourceRule))).To(Equal(1.0))
+	Expect(testutil.ToFloat64(exporter.slowQueryCount.WithLabelValues("count(kube_pod_info)", SourceRule))).To(Equal(1.0))
+}
+
+func TestExporter_ReadTruncated(t *testing.T) {
+	RegisterTestingT(t)
+
+	exporter, file := newTestExporter(t, 0)
+	exporter.config.MaxSize = int64(len(testSlowQuery))
+
+	appendLog(file, testSlowQuery+"\n")
+	Expect(exporter.Read()).To(Succeed())
+	info, err := os.Stat(file)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(info.Size()).To(BeZero())
+
+	appendLog(file, testSlowQuery+"\n")
+	Expect(exporter.Read()).To(Succeed())
+	Expect(testutil.ToFloat64(exporter.slowQueries.WithLabelValues(SourceAPI))).To(Equal(2.0))
+}
+
+func TestExporter_MaxQueries(t *testing.T) {
+	RegisterTestingT(t)
+
+	exporter, file := newTestExporter(t, 2)
+	appendLog(file, testSlowQuery+"\n"+testSlowRule+"\n"+testSlowQuery+"\n"+
+		`{"params":{"query":"count(up)"},"stats":{"timings":{"execTotalTime":11}}}`+"\n")
+	Expect(exporter.Read()).To(Succeed())
+
+	// The rule is the least recent slow query
+	Expect(testutil.CollectAndCount(exporter.slowQueryCount)).To(Equal(2))
+	Expect(exporter.lastSeen).ToNot(HaveKey(queryKey{query: "count(kube_pod_info)", source: SourceRule}))
+	Expect(testutil.ToFloat64(exporter.slowQueries.WithLabelValues(SourceAPI))).To(Equal(3.0))
+}
@@ -12,9 +12,9 @@ import (
 	v12 "k8s.io/api/core/v1"
 	v15 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
@@ -40,14 +40,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
-	status, err = r.reconcileAlertmanagerClusterRole(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
-	}
+	// The auth delegation of the proxy is granted by a cluster admin when running namespace scoped
+	if !cr.NamespaceScoped() {
+		status, err = r.reconcileAlertmanagerClusterRole(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 
-	status, err = r.reconcileAlertmanagerClusterRoleBinding(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+		status, err = r.reconcileAlertmanagerClusterRoleBinding(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 	}
 
 	status, err = r.reconcileAlertmanagerService(ctx, cr)
@@ -55,14 +58,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
-	status, err = r.reconcileAlertmanagerRoute(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
-	}
+	if cr.IngressEnabled() {
+		status, err = r.reconcileIngress(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+	} else if !cr.IsKubernetesCluster() {
+		status, err = r.reconcileAlertmanagerRoute(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 
-	status, err = r.waitForRoute(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+		status, err = r.waitForRoute(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 	}
 
 	return v1.ResultSuccess, nil
@@ -100,6 +110,12 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 
 	route := model.GetAlertmanagerRoute(cr)
 	err = r.client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	ingress := model.GetAlertmanagerIngress(cr)
+	err = r.client.Delete(ctx, ingress)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
@@ -116,16 +132,18 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		return v1.ResultFailed, err
 	}
 
-	role := model.GetAlertmanagerClusterRole(cr)
-	err = r.client.Delete(ctx, role)
-	if err != nil && !errors.IsNotFound(err) {
-		return v1.ResultFailed, err
-	}
+	if !cr.NamespaceScoped() {
+		role := model.GetAlertmanagerClusterRole(cr)
+		err = r.client.Delete(ctx, role)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
 
-	binding := model.GetAlertmanagerClusterRoleBinding(cr)
-	err = r.client.Delete(ctx, binding)
-	if err != nil && !errors.IsNotFound(err) {
-		return v1.ResultFailed, err
+		binding := model.GetAlertmanagerClusterRoleBinding(cr)
+		err = r.client.Delete(ctx, binding)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
 	}
 
 	return v1.ResultSuccess, nil
@@ -156,7 +174,7 @@ func (r *Reconciler) waitForRoute(ctx context.Context, cr *v1.Observability) (v1
 func (r *Reconciler) reconcileAlertmanagerServiceAccount(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	sa := model.GetAlertmanagerServiceAccount(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, sa, func() error {
+	_, err := utils.Apply(ctx, r.client, sa, func() error {
 		return nil
 	})
 	if err != nil {
@@ -169,7 +187,7 @@ func (r *Reconciler) reconcileAlertmanagerServiceAccount(ctx context.Context, cr
 func (r *Reconciler) reconcileAlertmanagerClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	role := model.GetAlertmanagerClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, role, func() error {
+	_, err := utils.Apply(ctx, r.client, role, func() error {
 		role.Rules = []v15.PolicyRule{
 			{
 				Verbs:     []string{"create"},
@@ -195,7 +213,7 @@ func (r *Reconciler) reconcileAlertmanagerClusterRoleBinding(ctx context.Context
 	binding := model.GetAlertmanagerClusterRoleBinding(cr)
 	role := model.GetAlertmanagerClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, binding, func() error {
+	_, err := utils.Apply(ctx, r.client, binding, func() error {
 		binding.Subjects = []v15.Subject{
 			{
 				Kind:      v15.ServiceAccountKind,
@@ -221,16 +239,19 @@ func (r *Reconciler) reconcileAlertmanagerService(ctx context.Context, cr *v1.Ob
 	service := model.GetAlertmanagerService(cr)
 	alertmanager := model.GetAlertmanagerCr(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
-		service.Annotations = map[string]string{
-			"service.alpha.openshift.io/serving-cert-secret-name": "alertmanager-k8s-tls",
+	_, err := utils.Apply(ctx, r.client, service, func() error {
+		service.Annotations = model.GetServingCertAnnotations(cr, model.GetAlertmanagerTLSSecret(cr).Name)
+		// Without the oauth proxy the web port is served by alertmanager directly
+		webTargetPort := intstr.FromString("proxy")
+		if !cr.OAuthProxyEnabled() {
+			webTargetPort = intstr.FromString("web")
 		}
 		service.Spec.Ports = []v12.ServicePort{
 			{
 				Name:       "web",
 				Protocol:   "TCP",
 				Port:       9091,
-				TargetPort: intstr.FromString("proxy"),
+				TargetPort: webTargetPort,
 			},
 		}
 		service.Spec.Selector = map[string]string{
@@ -250,12 +271,27 @@ func (r *Reconciler) reconcileAlertmanagerRoute(ctx context.Context, cr *v1.Obse
 	route := model.GetAlertmanagerRoute(cr)
 	service := model.GetAlertmanagerService(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, route, func() error {
+	// The router trusts the service CA, certificates of cert-manager are verified with its CA
+	destinationCA := ""
+	if cr.CertManagerEnabled() && cr.OAuthProxyEnabled() {
+		var err error
+		destinationCA, err = utils.GetServingCertificateCA(ctx, r.client, route.Namespace, model.GetAlertmanagerTLSSecret(cr).Name)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+	}
+
+	_, err := utils.Apply(ctx, r.client, route, func() error {
 		route.Spec.Port = &v13.RoutePort{
 			TargetPort: intstr.FromString("web"),
 		}
+		// Without the oauth proxy alertmanager serves plain HTTP
 		route.Spec.TLS = &v13.TLSConfig{
-			Termination: "reencrypt",
+			Termination:              v13.TLSTerminationReencrypt,
+			DestinationCACertificate: destinationCA,
+		}
+		if !cr.OAuthProxyEnabled() {
+			route.Spec.TLS.Termination = v13.TLSTerminationEdge
 		}
 		route.Spec.To = v13.RouteTargetReference{
 			Kind: "Service",
@@ -274,12 +310,10 @@ func (r *Reconciler) reconcileAlertmanagerRoute(ctx context.Context, cr *v1.Obse
 func (r *Reconciler) reconcileAlertmanagerProxySecret(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	secret := model.GetAlertmanagerProxySecret(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
-		if secret.Data == nil {
-			secret.Type = v12.SecretTypeOpaque
-			secret.StringData = map[string]string{
-				"session_secret": utils.GenerateRandomString(64),
-			}
+	_, err := utils.ApplyIfMissing(ctx, r.client, secret, func() error {
+		secret.Type = v12.SecretTypeOpaque
+		secret.StringData = map[string]string{
+			"session_secret": utils.GenerateRandomString(64),
 		}
 		return nil
 	})
@@ -289,3 +323,30 @@ func (r *Reconciler) reconcileAlertmanagerProxySecret(ctx context.Context, cr *v
 
 	return v1.ResultSuccess, err
 }
+
+func (r *Reconciler) reconcileIngress(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	ingress := model.GetAlertmanagerIngress(cr)
+	endpoint := cr.Spec.Ingress.Alertmanager
+
+	// Not exposed, remove the ingress in case the endpoint was removed from the spec
+	if endpoint == nil {
+		err := r.client.Delete(ctx, ingress)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+		return v1.ResultSuccess, nil
+	}
+
+	service := model.GetAlertmanagerService(cr)
+
+	_, err := utils.Apply(ctx, r.client, ingress, func() error {
+		ingress.Annotations = cr.Spec.Ingress.Annotations
+		ingress.Spec = model.GetIngressSpec(cr, endpoint, service.Name, "web")
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
@@ -0,0 +1,30 @@
+package alertmanager_installation
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets;serviceaccounts;services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	namespace := cr.GetPrometheusOperatorNamespace()
+	result := reconcilers.NewPermissions("", []string{"secrets", "serviceaccounts", "services"}, reconcilers.ManageVerbs, namespace)
+	if cr.IngressEnabled() {
+		result = append(result, reconcilers.NewPermissions("networking.k8s.io", []string{"ingresses"}, reconcilers.ManageVerbs, namespace)...)
+	} else if !cr.IsKubernetesCluster() {
+		result = append(result, reconcilers.NewPermissions("route.openshift.io", []string{"routes"}, reconcilers.ManageVerbs, namespace)...)
+	}
+	if !cr.NamespaceScoped() {
+		result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"clusterroles", "clusterrolebindings"}, reconcilers.ManageVerbs, "")...)
+		// Granted to the proxy of Alertmanager by its cluster role
+		result = append(result, reconcilers.NewPermissions("authorization.k8s.io", []string{"subjectaccessreviews"}, []string{"create"}, "")...)
+		result = append(result, reconcilers.NewPermissions("authentication.k8s.io", []string{"tokenreviews"}, []string{"create"}, "")...)
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package configuration
+
+import (
+	"context"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Set on Prometheus, Alertmanager and Grafana CRs that existed before the operator managed them
+const AdoptedLabel = "observability-operator/adopted"
+
+// Read the resource as it is in the cluster, without a resource version if it doesn't exist yet
+func (r *Reconciler) getExistingResource(ctx context.Context, obj client.Object) (client.Object, error) {
+	existing := obj.DeepCopyObject().(client.Object)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// Called before the resource is applied. Resources created by the operator are labeled as managed,
+// existing resources without the label are taken over if adoption is enabled. Returns true if the
+// resource is adopted, the fields the operator sets are then taken over from their previous owner.
+func (r *Reconciler) adoptResource(cr *v1.Observability, existing client.Object, obj client.Object, kind string) bool {
+	labels := existing.GetLabels()
+	if labels[AdoptedLabel] == "true" {
+		obj.SetLabels(MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+			AdoptedLabel: "true",
+		}, obj.GetLabels()))
+		r.setAdoptedOwner(cr, obj, kind)
+		return true
+	}
+
+	// Not created yet or created by this operator
+	if existing.GetResourceVersion() == "" || labels["managed-by"] == "observability-operator" || !cr.AdoptExistingResourcesEnabled() {
+		obj.SetLabels(MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, obj.GetLabels()))
+		return false
+	}
+
+	obj.SetLabels(MergeLabels(map[string]string{
+		"managed-by": "observability-operator",
+		AdoptedLabel: "true",
+	}, obj.GetLabels()))
+	r.setAdoptedOwner(cr, obj, kind)
+
+	r.recordEvent(cr, kv1.EventTypeNormal, EventReasonResourceAdopted,
+		"Adopted existing %v %v/%v", kind, obj.GetNamespace(), obj.GetName())
+	return true
+}
+
+// Owner references can't point to another namespace
+func (r *Reconciler) setAdoptedOwner(cr *v1.Observability, obj client.Object, kind string) {
+	if obj.GetNamespace() != cr.Namespace {
+		return
+	}
+	err := controllerutil.SetOwnerReference(cr, obj, r.client.Scheme())
+	if err != nil {
+		r.logger.Error(err, "error setting owner of adopted resource", "kind", kind, "name", obj.GetName())
+	}
+}
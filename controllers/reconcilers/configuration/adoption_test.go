@@ -0,0 +1,81 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdoption_AdoptResource(t *testing.T) {
+	adoptionEnabled := &v1.Observability{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "observability"},
+		Spec: v1.ObservabilitySpec{
+			AdoptExistingResources: &([]bool{true})[0],
+		},
+	}
+
+	tests := []struct {
+		name       string
+		cr         *v1.Observability
+		meta       metav1.ObjectMeta
+		want       bool
+		wantLabels map[string]string
+	}{
+		{
+			name: "new resources are managed, not adopted",
+			cr:   adoptionEnabled,
+			meta: metav1.ObjectMeta{Namespace: "prometheus"},
+			want: false,
+			wantLabels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+		{
+			name: "existing resources are not adopted without adoption",
+			cr:   &v1.Observability{},
+			meta: metav1.ObjectMeta{Namespace: "prometheus", ResourceVersion: "1", Labels: map[string]string{"team": "a"}},
+			want: false,
+			wantLabels: map[string]string{
+				"managed-by": "observability-operator",
+			},
+		},
+		{
+			name: "existing resources of others are adopted",
+			cr:   adoptionEnabled,
+			meta: metav1.ObjectMeta{Namespace: "prometheus", ResourceVersion: "1", Labels: map[string]string{"team": "a"}},
+			want: true,
+			wantLabels: map[string]string{
+				"managed-by": "observability-operator",
+				AdoptedLabel: "true",
+			},
+		},
+		{
+			name: "adopted resources stay adopted",
+			cr:   &v1.Observability{},
+			meta: metav1.ObjectMeta{Namespace: "prometheus", ResourceVersion: "1", Labels: map[string]string{
+				"managed-by": "observability-operator",
+				AdoptedLabel: "true",
+			}},
+			want: true,
+			wantLabels: map[string]string{
+				"managed-by": "observability-operator",
+				AdoptedLabel: "true",
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reconciler{}
+			existing := &prometheusv1.Prometheus{ObjectMeta: tt.meta}
+			prometheus := &prometheusv1.Prometheus{ObjectMeta: metav1.ObjectMeta{Namespace: tt.meta.Namespace}}
+			result := r.adoptResource(tt.cr, existing, prometheus, "Prometheus")
+			Expect(result).To(Equal(tt.want))
+			Expect(prometheus.Labels).To(Equal(tt.wantLabels))
+		})
+	}
+}
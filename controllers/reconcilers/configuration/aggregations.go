@@ -0,0 +1,69 @@
+package configuration
+
+import (
+	"context"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Aggregations of the index that can be recorded, along with the errors of the invalid ones
+func getValidAggregations(index v1.RepositoryIndex) ([]v1.Aggregation, []error) {
+	if index.Config == nil || index.Config.Prometheus == nil {
+		return nil, nil
+	}
+
+	var result []v1.Aggregation
+	var errs []error
+	for _, aggregation := range index.Config.Prometheus.Aggregations {
+		err := model.ValidateAggregation(&aggregation)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result = append(result, aggregation)
+	}
+	return result, errs
+}
+
+// Record the aggregations of all indexes in one rule, removed when no index declares aggregations
+func (r *Reconciler) reconcileAggregationRules(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	var groups []prometheusv1.RuleGroup
+	for _, index := range indexes {
+		aggregations, errs := getValidAggregations(index)
+		for _, err := range errs {
+			r.logger.Error(err, "skipped aggregation")
+			r.addConfigurationError(index.Id, v1.ErrorStageValidate, err)
+		}
+		if len(aggregations) > 0 {
+			groups = append(groups, model.GetAggregationRuleGroup(index.Id, aggregations))
+		}
+	}
+
+	rule := model.GetAggregationRule(cr)
+	if len(groups) == 0 {
+		err := r.client.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, getRuleSelectorLabels(cr))
+		rule.Spec.Groups = groups
+		return nil
+	})
+	return err
+}
+
+// The raw series of aggregated metrics are not remote written
+func applyAggregations(index v1.RepositoryIndex, remoteWrite *prometheusv1.RemoteWriteSpec) {
+	aggregations, _ := getValidAggregations(index)
+	remoteWrite.WriteRelabelConfigs = append(remoteWrite.WriteRelabelConfigs, model.GetAggregationRelabelConfigs(aggregations)...)
+}
@@ -0,0 +1,224 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	configv1 "github.com/openshift/api/config/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=config.openshift.io,resources=consoles,verbs=get
+
+const alertForwarderReceiver = "alert-forwarder"
+
+// Receiver and route of the forwarder, the route comes first and continues, so the alerts are routed as before
+func getAlertForwarderRouting(cr *v1.Observability) (*v1.AlertmanagerConfigReceiver, *v1.AlertmanagerConfigRoute, error) {
+	if !cr.AlertForwarderEnabled() {
+		return nil, nil, nil
+	}
+	for _, matcher := range cr.Spec.AlertForwarder.Matchers {
+		if err := validateAlertmanagerMatcher(matcher); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	receiver := &v1.AlertmanagerConfigReceiver{
+		Name: alertForwarderReceiver,
+		WebhookConfigs: []v1.WebhookConfig{
+			{
+				Url: model.GetAlertForwarderWebhookUrl(cr),
+			},
+		},
+	}
+	route := &v1.AlertmanagerConfigRoute{
+		Receiver: alertForwarderReceiver,
+		Matchers: cr.Spec.AlertForwarder.Matchers,
+		Continue: true,
+	}
+	return receiver, route, nil
+}
+
+// Deployment of the forwarder with its config in a secret, removed when the forwarder is disabled
+func (r *Reconciler) reconcileAlertForwarder(ctx context.Context, cr *v1.Observability) error {
+	if !cr.AlertForwarderEnabled() {
+		return r.deleteAlertForwarder(ctx, cr)
+	}
+	if cr.Spec.AlertForwarder.Url == "" {
+		return fmt.Errorf("alert forwarder without url")
+	}
+
+	err := r.ensureOperatorImage(ctx, cr, v1.ImageAlertForwarder)
+	if err != nil {
+		return err
+	}
+
+	token, err := r.getAlertForwarderToken(ctx, cr)
+	if err != nil {
+		return err
+	}
+	config, err := yaml.Marshal(model.GetAlertForwarderConfig(cr, token, r.getConsoleUrl(ctx, cr)))
+	if err != nil {
+		return err
+	}
+
+	secret := model.GetAlertForwarderSecret(cr)
+	_, err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Type = core.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			model.AlertForwarderConfigKey: config,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// A changed config rolls the pods, the forwarder reads it on start
+	contentHash, err := r.getMountedContentHash(ctx, secret.Namespace, []string{secret.Name}, nil)
+	if err != nil {
+		return err
+	}
+
+	var replicas int32 = 1
+	automountToken := false
+	deployment := model.GetAlertForwarderDeployment(cr)
+	labels := deployment.Labels
+	_, err = utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Labels = labels
+		deployment.Spec = appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						ContentHashAnnotation: contentHash,
+					},
+				},
+				Spec: core.PodSpec{
+					PriorityClassName:            model.ObservabilityPriorityClassName,
+					SecurityContext:              model.GetPodSecurityContext(cr),
+					Affinity:                     model.GetArchitectureAffinity(cr, nil),
+					Tolerations:                  cr.Spec.Tolerations,
+					AutomountServiceAccountToken: &automountToken,
+					Containers:                   []core.Container{model.GetAlertForwarderContainer(cr, r.operatorImage, model.GetProxyEnvVars(r.clusterProxy))},
+					Volumes:                      model.GetAlertForwarderVolumes(cr),
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	service := model.GetAlertForwarderService(cr)
+	selector := service.Labels
+	_, err = utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = selector
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "http",
+				Protocol:   core.ProtocolTCP,
+				Port:       model.AlertForwarderPort,
+				TargetPort: intstr.FromString("http"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.reconcileAlertForwarderNetworkPolicy(ctx, cr, selector)
+}
+
+// The webhook isn't authenticated, only the Alertmanager pods of the CR may post to it
+func (r *Reconciler) reconcileAlertForwarderNetworkPolicy(ctx context.Context, cr *v1.Observability, selector map[string]string) error {
+	policy := model.GetAlertForwarderNetworkPolicy(cr)
+	labels := policy.Labels
+	_, err := utils.Apply(ctx, r.client, policy, func() error {
+		policy.Labels = labels
+		policy.Spec = networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"alertmanager": model.GetDefaultNameAlertmanager(cr),
+								},
+							},
+						},
+					},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) getAlertForwarderToken(ctx context.Context, cr *v1.Observability) (string, error) {
+	if cr.Spec.AlertForwarder.AuthSecret == "" {
+		return "", nil
+	}
+	secret := &core.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: cr.Spec.AlertForwarder.AuthSecret}, secret)
+	if err != nil {
+		return "", fmt.Errorf("error fetching alert forwarder auth secret: %v", err)
+	}
+	token := secret.Data["token"]
+	if len(token) == 0 {
+		return "", fmt.Errorf("alert forwarder auth secret %v has no token", cr.Spec.AlertForwarder.AuthSecret)
+	}
+	return string(token), nil
+}
+
+// Url of the OpenShift console, empty on Kubernetes or if the console is not installed
+func (r *Reconciler) getConsoleUrl(ctx context.Context, cr *v1.Observability) string {
+	if cr.IsKubernetesCluster() {
+		return ""
+	}
+	console := &configv1.Console{}
+	err := r.client.Get(ctx, client.ObjectKey{Name: "cluster"}, console)
+	if err != nil {
+		r.logger.V(1).Info("console url not found", "error", err.Error())
+		return ""
+	}
+	return console.Status.ConsoleURL
+}
+
+func (r *Reconciler) deleteAlertForwarder(ctx context.Context, cr *v1.Observability) error {
+	objects := []client.Object{
+		model.GetAlertForwarderDeployment(cr),
+		model.GetAlertForwarderService(cr),
+		model.GetAlertForwarderSecret(cr),
+		model.GetAlertForwarderNetworkPolicy(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAlertForwarder_ReconcileAlertForwarderNetworkPolicy(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = networkingv1.AddToScheme(scheme)
+	r := &Reconciler{client: testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())}
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Name: "observability-stack", Namespace: "observability"}}
+
+	selector := model.GetAlertForwarderService(cr).Labels
+	Expect(r.reconcileAlertForwarderNetworkPolicy(context.TODO(), cr, selector)).To(Succeed())
+
+	// Only the Alertmanager pods may post to the forwarder
+	policy := &networkingv1.NetworkPolicy{}
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(model.GetAlertForwarderNetworkPolicy(cr)), policy)).To(Succeed())
+	Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(selector))
+	Expect(policy.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}))
+	Expect(policy.Spec.Ingress).To(HaveLen(1))
+	Expect(policy.Spec.Ingress[0].From).To(HaveLen(1))
+	Expect(policy.Spec.Ingress[0].From[0].PodSelector.MatchLabels).To(Equal(map[string]string{"alertmanager": model.GetDefaultNameAlertmanager(cr)}))
+}
@@ -8,13 +8,14 @@ import (
 	goyaml "github.com/goccy/go-yaml"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/credentials"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
@@ -29,84 +30,105 @@ func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observabi
 		Name:      route.Name,
 	}
 
+	// There are no routes on Kubernetes clusters or if ingresses are used
 	err := r.client.Get(ctx, selector, route)
-	if err != nil && !errors.IsNotFound(err) {
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 		return err
 	}
 
 	host := ""
-	if utils.IsRouteReady(route) {
+	if cr.IngressEnabled() {
+		host = model.GetIngressHost(cr.Spec.Ingress.Alertmanager)
+	} else if err == nil && utils.IsRouteReady(route) {
 		host = route.Spec.Host
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, alertmanager, func() error {
+	var secrets []string
+	var containers []v12.Container
+	var volumes []v12.Volume
+	var architectures [][]string
+
+	// The oauth proxy authenticates against the OpenShift oauth server
+	if cr.OAuthProxyEnabled() {
+		secrets = append(secrets, proxySecret.Name, "alertmanager-k8s-tls")
+		containers = append(containers, v12.Container{
+			Name:  "oauth-proxy",
+			Image: model.GetOAuthProxyImage(cr),
+			Args: append([]string{
+				"-provider=openshift",
+				"-https-address=:9091",
+				"-http-address=",
+				"-email-domain=*",
+				"-upstream=http://localhost:9093",
+				"-tls-cert=/etc/tls/private/tls.crt",
+				"-tls-key=/etc/tls/private/tls.key",
+				fmt.Sprintf("-client-secret-file=%v", model.GetServiceAccountTokenFile(model.OAuthProxyTokenVolume)),
+				"-cookie-secret-file=/etc/proxy/secrets/session_secret",
+				fmt.Sprintf("-openshift-service-account=%v", sa.Name),
+				"-openshift-ca=/etc/pki/tls/cert.pem",
+				"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+				"-skip-auth-regex=^/metrics",
+			}, append(model.GetOAuthProxyAuthorizationArgs(cr, v1.OAuthProxyAlertmanager), model.GetOAuthProxyTLSArgs(cr)...)...),
+			Ports: []v12.ContainerPort{
+				{
+					Name:          "proxy",
+					ContainerPort: 9091,
+				},
+			},
+			Env:             model.GetProxyEnvVars(r.clusterProxy),
+			SecurityContext: model.GetContainerSecurityContext(cr),
+			VolumeMounts: []v12.VolumeMount{
+				{
+					Name:      "secret-alertmanager-k8s-tls",
+					MountPath: "/etc/tls/private",
+				},
+				{
+					Name:      fmt.Sprintf("secret-%v", proxySecret.Name),
+					MountPath: "/etc/proxy/secrets",
+				},
+				model.GetServiceAccountTokenVolumeMount(model.OAuthProxyTokenVolume),
+			},
+		})
+		volumes = append(volumes, model.GetServiceAccountTokenVolume(model.OAuthProxyTokenVolume, "", model.ServiceAccountTokenExpirationSeconds))
+		architectures = append(architectures, model.GetOAuthProxyImageArchitectures(cr))
+	}
+
+	contentHash, err := r.getMountedContentHash(ctx, cr.GetPrometheusOperatorNamespace(), secrets, nil)
+	if err != nil {
+		return err
+	}
+
+	existing, err := r.getExistingResource(ctx, alertmanager)
+	if err != nil {
+		return err
+	}
+	var opts []client.PatchOption
+	if r.adoptResource(cr, existing, alertmanager, "Alertmanager") {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	_, err = utils.Apply(ctx, r.client, alertmanager, func() error {
 		alertmanager.Spec = prometheusv1.AlertmanagerSpec{
 			PodMetadata: &prometheusv1.EmbeddedObjectMetadata{
-				Annotations: map[string]string{
+				Annotations: MergeLabels(map[string]string{
 					"cluster-autoscaler.kubernetes.io/safe-to-evict": "true",
-				},
+					ContentHashAnnotation:                            contentHash,
+				}, model.GetServiceMeshPodAnnotations(cr)),
 			},
 			ConfigSecret:       configSecretName,
-			ListenLocal:        true,
+			ListenLocal:        cr.OAuthProxyEnabled(),
+			Web:                model.GetAlertmanagerWebSpec(cr),
 			ExternalURL:        fmt.Sprintf("https://%v", host),
 			ServiceAccountName: sa.Name,
-			Secrets: []string{
-				proxySecret.Name,
-				"alertmanager-k8s-tls",
-			},
-			PriorityClassName: model.ObservabilityPriorityClassName,
-			Containers: []v12.Container{
-				{
-					Name:  "oauth-proxy",
-					Image: "quay.io/openshift/origin-oauth-proxy:4.8",
-					Args: []string{
-						"-provider=openshift",
-						"-https-address=:9091",
-						"-http-address=",
-						"-email-domain=*",
-						"-upstream=http://localhost:9093",
-						"-openshift-sar={\"resource\": \"namespaces\", \"verb\": \"get\"}",
-						"-openshift-delegate-urls={\"/\": {\"resource\": \"namespaces\", \"verb\": \"get\"}}",
-						"-tls-cert=/etc/tls/private/tls.crt",
-						"-tls-key=/etc/tls/private/tls.key",
-						"-client-secret-file=/var/run/secrets/kubernetes.io/serviceaccount/token",
-						"-cookie-secret-file=/etc/proxy/secrets/session_secret",
-						fmt.Sprintf("-openshift-service-account=%v", sa.Name),
-						"-openshift-ca=/etc/pki/tls/cert.pem",
-						"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
-						"-skip-auth-regex=^/metrics",
-					},
-					Ports: []v12.ContainerPort{
-						{
-							Name:          "proxy",
-							ContainerPort: 9091,
-						},
-					},
-					Env: []v12.EnvVar{
-						{
-							Name: "HTTP_PROXY",
-						},
-						{
-							Name: "HTTPS_PROXY",
-						},
-						{
-							Name: "NO_PROXY",
-						},
-					},
-					VolumeMounts: []v12.VolumeMount{
-						{
-							Name:      "secret-alertmanager-k8s-tls",
-							MountPath: "/etc/tls/private",
-						},
-						{
-							Name:      fmt.Sprintf("secret-%v", proxySecret.Name),
-							MountPath: "/etc/proxy/secrets",
-						},
-					},
-				},
-			},
-			Version:   model.GetAlertmanagerVersion(cr),
-			Resources: *model.GetAlertmanagerResourceRequirement(cr),
+			Secrets:            secrets,
+			PriorityClassName:  model.ObservabilityPriorityClassName,
+			Containers:         append(containers, model.GetSecurityContextContainerPatches(cr, "alertmanager", "config-reloader")...),
+			InitContainers:     model.GetSecurityContextContainerPatches(cr, "init-config-reloader"),
+			SecurityContext:    model.GetPodSecurityContext(cr),
+			Volumes:            volumes,
+			Version:            model.GetAlertmanagerVersion(cr),
+			Resources:          *model.GetAlertmanagerResourceRequirement(cr),
+			Affinity:           model.GetArchitectureAffinity(cr, nil, architectures...),
 		}
 		alertmanager.Spec.Version = model.GetAlertmanagerVersion(cr)
 		alertmanager.Spec.Resources = *model.GetAlertmanagerResourceRequirement(cr)
@@ -118,7 +140,7 @@ func (r *Reconciler) reconcileAlertmanager(ctx context.Context, cr *v1.Observabi
 			alertmanager.Spec.Storage = alertManagerStorageSpec
 		}
 		return nil
-	})
+	}, opts...)
 	if err != nil {
 		return err
 	}
@@ -140,7 +162,7 @@ func getAlertManagerStorageSpecHelper(cr *v1.Observability, indexes []v1.Reposit
 
 func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
 	root := &v1.AlertmanagerConfigRoute{
-		Receiver:       "default-receiver",
+		Receiver:       defaultReceiver,
 		RepeatInterval: "12h",
 		Routes:         []v1.AlertmanagerConfigRoute{},
 	}
@@ -156,24 +178,45 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 		Route:  root,
 		Receivers: []v1.AlertmanagerConfigReceiver{
 			{
-				Name: "default-receiver",
+				Name: defaultReceiver,
 			},
 		},
 	}
 
+	// Alerts reach the forwarder before the routes of the indexes
+	forwarderReceiver, forwarderRoute, err := getAlertForwarderRouting(cr)
+	if err != nil {
+		r.logger.Error(err, "invalid alert forwarder matchers")
+		r.recordEvent(cr, v12.EventTypeWarning, "InvalidAlertForwarder", err.Error())
+	} else if forwarderRoute != nil {
+		config.Receivers = append(config.Receivers, *forwarderReceiver)
+		root.Routes = append(root.Routes, *forwarderRoute)
+	}
+
+	// Time intervals of the CR mute the routes of the indexes they apply to
+	muteTimeIntervals := r.getMuteTimeIntervals(cr)
+	config.TimeIntervals = append(config.TimeIntervals, muteTimeIntervals...)
+
 	for _, index := range indexes {
 		if index.Config == nil || index.Config.Alertmanager == nil {
 			continue
 		}
 
+		// Receivers of the index by the names its routes reference them with
+		receivers := map[string]string{
+			indexDefaultReceiver: defaultReceiver,
+		}
+		var indexRoutes, deadMansSnitchRoutes []v1.AlertmanagerConfigRoute
+
 		if !cr.PagerDutyDisabled() {
 			pagerDutySecret, err := r.getPagerDutySecret(ctx, cr, index.Config.Alertmanager)
 			if err != nil {
-				r.logger.Error(err, fmt.Sprintf("pagerduty secret %v not found", index.Config.Alertmanager.PagerDutySecretName))
+				r.logger.Error(err, "pagerduty secret not found", "index", index.Id, "secret", index.Config.Alertmanager.PagerDutySecretName)
 				continue
 			}
 
 			pagerDutyReceiver := fmt.Sprintf("%s-%s", index.Id, "pagerduty")
+			receivers["pagerduty"] = pagerDutyReceiver
 
 			config.Receivers = append(config.Receivers, v1.AlertmanagerConfigReceiver{
 				Name: pagerDutyReceiver,
@@ -184,7 +227,7 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 				},
 			})
 
-			root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+			indexRoutes = append(indexRoutes, v1.AlertmanagerConfigRoute{
 				Receiver: pagerDutyReceiver,
 				Match: map[string]string{
 					"severity":                  "critical",
@@ -196,11 +239,12 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 		if !cr.DeadMansSnitchDisabled() {
 			deadmansSnitchUrl, err := r.getDeadMansSnitchUrl(ctx, cr, index.Config.Alertmanager)
 			if err != nil {
-				r.logger.Error(err, fmt.Sprintf("deadmanssnitch secret %v not found", index.Config.Alertmanager.DeadmansSnitchSecretName))
+				r.logger.Error(err, "deadmanssnitch secret not found", "index", index.Id, "secret", index.Config.Alertmanager.DeadmansSnitchSecretName)
 				continue
 			}
 
 			deadMansSnitchReceiver := fmt.Sprintf("%s-%s", index.Id, "deadmanssnitch")
+			receivers["deadmanssnitch"] = deadMansSnitchReceiver
 
 			config.Receivers = append(config.Receivers, v1.AlertmanagerConfigReceiver{
 				Name: deadMansSnitchReceiver,
@@ -211,19 +255,22 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 				},
 			})
 
-			root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+			deadMansSnitchRoute := v1.AlertmanagerConfigRoute{
 				Receiver:       deadMansSnitchReceiver,
 				RepeatInterval: "5m",
 				Match: map[string]string{
 					"alertname":                 "DeadMansSwitch",
 					PrometheusRuleIdentifierKey: index.Id,
 				},
-			})
+			}
+			indexRoutes = append(indexRoutes, deadMansSnitchRoute)
+			deadMansSnitchRoutes = append(deadMansSnitchRoutes, deadMansSnitchRoute)
 		}
 
 		if !cr.SmtpDisabled() && len(index.Config.Alertmanager.SmtpToEmailAddress) > 0 && index.Config.Alertmanager.SmtpFromEmailAddress != "" {
 
 			smtpReceiver := fmt.Sprintf("%s-%s", index.Id, "smtp")
+			receivers["smtp"] = smtpReceiver
 
 			toEmailAddress := ""
 			if len(index.Config.Alertmanager.SmtpToEmailAddress) > 1 {
@@ -242,7 +289,7 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 				},
 			})
 
-			root.Routes = append(root.Routes, v1.AlertmanagerConfigRoute{
+			indexRoutes = append(indexRoutes, v1.AlertmanagerConfigRoute{
 				Receiver: smtpReceiver,
 				Match: map[string]string{
 					"severity":                  "warning",
@@ -250,6 +297,42 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 				},
 			})
 		}
+
+		// The dead man's switch keeps its route, a routing tree of the index replaces the severity routes.
+		// Invalid routing is not applied at all.
+		mutes := getIndexMuteTimeIntervals(cr, muteTimeIntervals, index.Id)
+		muteRoutes(indexRoutes, mutes, receivers["deadmanssnitch"])
+		routing, err := getIndexRouting(&index, receivers)
+		if err != nil {
+			r.logger.Error(err, "invalid alertmanager routing", "index", index.Id)
+			r.addConfigurationError(index.Id, v1.ErrorStageValidate, err)
+			root.Routes = append(root.Routes, indexRoutes...)
+			continue
+		}
+		if routing == nil || routing.route == nil {
+			root.Routes = append(root.Routes, indexRoutes...)
+		} else {
+			routes := []v1.AlertmanagerConfigRoute{*routing.route}
+			muteRoutes(routes, mutes, "")
+			root.Routes = append(root.Routes, deadMansSnitchRoutes...)
+			root.Routes = append(root.Routes, routes...)
+		}
+		if routing != nil {
+			config.InhibitRules = append(config.InhibitRules, routing.inhibitRules...)
+			config.TimeIntervals = append(config.TimeIntervals, routing.timeIntervals...)
+		}
+	}
+
+	// Alerts that no index routes, muted by the time intervals that apply to all indexes
+	routing, err := r.getDefaultRouting(ctx, cr)
+	if err != nil {
+		r.logger.Error(err, "invalid default routing")
+		r.recordEvent(cr, v12.EventTypeWarning, "InvalidDefaultRouting", err.Error())
+	} else if routing != nil {
+		muteRoutes(routing.routes, getIndexMuteTimeIntervals(cr, muteTimeIntervals, ""), defaultReceiver)
+		config.Receivers = append(config.Receivers, routing.receivers...)
+		config.InhibitRules = append(config.InhibitRules, routing.inhibitRules...)
+		root.Routes = append(root.Routes, routing.routes...)
 	}
 
 	configBytes, err := goyaml.Marshal(&config)
@@ -259,7 +342,7 @@ func (r *Reconciler) reconcileAlertmanagerSecret(ctx context.Context, cr *v1.Obs
 
 	secret := model.GetAlertmanagerSecret(cr)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+	_, err = utils.Apply(ctx, r.client, secret, func() error {
 		secret.Type = v12.SecretTypeOpaque
 		secret.StringData = map[string]string{
 			"alertmanager.yaml": string(configBytes),
@@ -287,21 +370,16 @@ func (r *Reconciler) getPagerDutySecret(ctx context.Context, cr *v1.Observabilit
 		ns = config.PagerDutySecretNamespace
 	}
 
-	pagerdutySecret := &v12.Secret{}
-	selector := client.ObjectKey{
-		Namespace: ns,
-		Name:      config.PagerDutySecretName,
-	}
-	err := r.client.Get(ctx, selector, pagerdutySecret)
+	pagerdutySecret, err := credentials.GetCredentialProvider(cr, r.client).GetCredentials(ctx, ns, config.PagerDutySecretName)
 	if err != nil {
 		return nil, err
 	}
 
 	var secret []byte
-	if len(pagerdutySecret.Data["PAGERDUTY_KEY"]) != 0 {
-		secret = pagerdutySecret.Data["PAGERDUTY_KEY"]
-	} else if len(pagerdutySecret.Data["serviceKey"]) != 0 {
-		secret = pagerdutySecret.Data["serviceKey"]
+	if len(pagerdutySecret["PAGERDUTY_KEY"]) != 0 {
+		secret = pagerdutySecret["PAGERDUTY_KEY"]
+	} else if len(pagerdutySecret["serviceKey"]) != 0 {
+		secret = pagerdutySecret["serviceKey"]
 	}
 
 	return secret, nil
@@ -321,21 +399,16 @@ func (r *Reconciler) getDeadMansSnitchUrl(ctx context.Context, cr *v1.Observabil
 		ns = config.DeadmansSnitchSecretNamespace
 	}
 
-	dmsSecret := &v12.Secret{}
-	selector := client.ObjectKey{
-		Namespace: ns,
-		Name:      config.DeadmansSnitchSecretName,
-	}
-	err := r.client.Get(ctx, selector, dmsSecret)
+	dmsSecret, err := credentials.GetCredentialProvider(cr, r.client).GetCredentials(ctx, ns, config.DeadmansSnitchSecretName)
 	if err != nil {
 		return nil, err
 	}
 
 	var url []byte
-	if len(dmsSecret.Data["SNITCH_URL"]) != 0 {
-		url = dmsSecret.Data["SNITCH_URL"]
-	} else if len(dmsSecret.Data["url"]) != 0 {
-		url = dmsSecret.Data["url"]
+	if len(dmsSecret["SNITCH_URL"]) != 0 {
+		url = dmsSecret["SNITCH_URL"]
+	} else if len(dmsSecret["url"]) != 0 {
+		url = dmsSecret["url"]
 	}
 
 	return url, nil
@@ -358,30 +431,25 @@ func (r *Reconciler) getSmtpSecret(ctx context.Context, cr *v1.Observability, co
 		ns = config.SmtpSecretNamespace
 	}
 
-	SmtpSecret := &v12.Secret{}
-	selector := client.ObjectKey{
-		Namespace: ns,
-		Name:      config.SmtpSecretName,
-	}
-	err := r.client.Get(ctx, selector, SmtpSecret)
+	SmtpSecret, err := credentials.GetCredentialProvider(cr, r.client).GetCredentials(ctx, ns, config.SmtpSecretName)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(SmtpSecret.Data["password"]) != 0 {
-		secrets["password"] = SmtpSecret.Data["password"]
+	if len(SmtpSecret["password"]) != 0 {
+		secrets["password"] = SmtpSecret["password"]
 	}
 
-	if len(SmtpSecret.Data["username"]) != 0 {
-		secrets["username"] = SmtpSecret.Data["username"]
+	if len(SmtpSecret["username"]) != 0 {
+		secrets["username"] = SmtpSecret["username"]
 	}
 
-	if len(SmtpSecret.Data["host"]) != 0 {
-		secrets["host"] = SmtpSecret.Data["host"]
+	if len(SmtpSecret["host"]) != 0 {
+		secrets["host"] = SmtpSecret["host"]
 	}
 
-	if len(SmtpSecret.Data["port"]) != 0 {
-		secrets["port"] = SmtpSecret.Data["port"]
+	if len(SmtpSecret["port"]) != 0 {
+		secrets["port"] = SmtpSecret["port"]
 	}
 
 	return secrets, nil
@@ -400,7 +468,7 @@ func (r *Reconciler) createGlobalConfig(ctx context.Context, cr *v1.Observabilit
 		smtpSecret, err := r.getSmtpSecret(ctx, cr, indexes[0].Config.Alertmanager)
 
 		if err != nil {
-			r.logger.Error(err, fmt.Sprintf("smtp secret %v not found", indexes[0].Config.Alertmanager.SmtpSecretName))
+			r.logger.Error(err, "smtp secret not found", "index", indexes[0].Id, "secret", indexes[0].Config.Alertmanager.SmtpSecretName)
 			return nil, err
 		}
 
@@ -0,0 +1,706 @@
+package configuration
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	core "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultCalendarSyncInterval = time.Hour
+	// Events that end later are synced with the next syncs
+	calendarHorizon = 30 * 24 * time.Hour
+	// Larger feeds are rejected instead of read into memory
+	maxCalendarSize = 10 * 1024 * 1024
+	// Time periods of a calendar, the remaining events are dropped
+	maxCalendarPeriods = 500
+	// Occurrences of a recurring event that are expanded before it is dropped
+	maxCalendarOccurrences = 100000
+	// Prefix of the time intervals of the CR, indexes prefix theirs with their id
+	muteTimeIntervalPrefix = "observability"
+)
+
+var durationRegex = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+var calendarWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Periods of the last successful sync of a calendar, kept while the feed can't be fetched.
+// Kept in the sync state, so that they outlive the reconciler.
+type calendarSync struct {
+	url        string
+	lastSync   time.Time
+	lastSynced time.Time
+	periods    []v1.AlertmanagerTimePeriod
+	events     int
+	message    string
+}
+
+type calendarEvent struct {
+	start time.Time
+	end   time.Time
+}
+
+// Fetch the calendars of the mute time intervals that are due and record their sync in the status.
+// Calendars of removed intervals are forgotten.
+func (r *Reconciler) reconcileAlertmanagerCalendars(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) {
+	previous := r.getCalendarSyncs(cr)
+	syncs := map[string]*calendarSync{}
+	var statuses []v1.AlertmanagerCalendarStatus
+	for _, interval := range cr.Spec.AlertmanagerMuteTimeIntervals {
+		if interval.Calendar == nil {
+			continue
+		}
+		sync := previous[interval.Name]
+		if sync == nil {
+			sync = &calendarSync{}
+		}
+		syncs[interval.Name] = sync
+
+		url, err := r.getCalendarUrl(ctx, cr, interval.Calendar)
+		if err == nil && (url != sync.url || time.Since(sync.lastSync) >= getCalendarSyncInterval(interval.Calendar)) {
+			sync.url = url
+			sync.lastSync = time.Now()
+			err = r.syncCalendar(ctx, sync, interval.Calendar)
+		}
+		if err != nil {
+			r.logger.Error(err, "error syncing calendar", "interval", interval.Name)
+			sync.message = err.Error()
+		}
+
+		status := v1.AlertmanagerCalendarStatus{
+			Name:    interval.Name,
+			Events:  sync.events,
+			Message: sync.message,
+		}
+		if !sync.lastSynced.IsZero() {
+			status.LastSynced = sync.lastSynced.Unix()
+		}
+		statuses = append(statuses, status)
+	}
+	r.setCalendarSyncs(cr, syncs)
+	s.AlertmanagerCalendars = statuses
+}
+
+// The syncs of a CR are only changed by its own reconcile
+func (r *Reconciler) getCalendarSyncs(cr *v1.Observability) map[string]*calendarSync {
+	state := r.getSyncState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.alertmanagerCalendars[getSyncStateKey(cr)]
+}
+
+func (r *Reconciler) setCalendarSyncs(cr *v1.Observability, syncs map[string]*calendarSync) {
+	state := r.getSyncState()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if len(syncs) == 0 {
+		delete(state.alertmanagerCalendars, getSyncStateKey(cr))
+		return
+	}
+	state.alertmanagerCalendars[getSyncStateKey(cr)] = syncs
+}
+
+func (r *Reconciler) getCalendarUrl(ctx context.Context, cr *v1.Observability, calendar *v1.AlertmanagerCalendar) (string, error) {
+	if calendar.UrlSecretRef == nil {
+		if calendar.Url == "" {
+			return "", fmt.Errorf("calendar without url")
+		}
+		return calendar.Url, nil
+	}
+
+	secret := &core.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: calendar.UrlSecretRef.Name}, secret)
+	if err != nil {
+		return "", fmt.Errorf("error fetching calendar url secret: %v", err)
+	}
+	url := strings.TrimSpace(string(secret.Data[calendar.UrlSecretRef.Key]))
+	if url == "" {
+		return "", fmt.Errorf("calendar url secret %v has no key %v", calendar.UrlSecretRef.Name, calendar.UrlSecretRef.Key)
+	}
+	return url, nil
+}
+
+func (r *Reconciler) syncCalendar(ctx context.Context, sync *calendarSync, calendar *v1.AlertmanagerCalendar) error {
+	var summary *regexp.Regexp
+	if calendar.SummaryRegex != "" {
+		var err error
+		summary, err = regexp.Compile(calendar.SummaryRegex)
+		if err != nil {
+			return fmt.Errorf("invalid summary regex: %v", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sync.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code fetching calendar: %v", resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarSize+1))
+	if err != nil {
+		return err
+	}
+	if len(content) > maxCalendarSize {
+		return fmt.Errorf("calendar exceeds %v bytes", maxCalendarSize)
+	}
+
+	now := time.Now()
+	events, err := parseCalendar(content, summary, now, now.Add(calendarHorizon))
+	if err != nil {
+		return err
+	}
+	sync.periods, sync.message = getCalendarTimePeriods(events)
+	sync.events = len(events)
+	sync.lastSynced = now
+	return nil
+}
+
+func getCalendarSyncInterval(calendar *v1.AlertmanagerCalendar) time.Duration {
+	if calendar.SyncInterval == "" {
+		return defaultCalendarSyncInterval
+	}
+	interval, err := time.ParseDuration(calendar.SyncInterval)
+	if err != nil || interval <= 0 {
+		return defaultCalendarSyncInterval
+	}
+	return interval
+}
+
+// Time intervals of the CR with the periods of their calendars. Intervals without any period, e.g. a
+// calendar without upcoming events, are left out, Alertmanager would not mute anything with them.
+func (r *Reconciler) getMuteTimeIntervals(cr *v1.Observability) []v1.AlertmanagerConfigTimeInterval {
+	var result []v1.AlertmanagerConfigTimeInterval
+	seen := map[string]bool{}
+	syncs := r.getCalendarSyncs(cr)
+	for _, interval := range cr.Spec.AlertmanagerMuteTimeIntervals {
+		err := validateMuteTimeInterval(&interval, seen)
+		if err != nil {
+			r.logger.Error(err, "skipped mute time interval")
+			r.recordEvent(cr, core.EventTypeWarning, "InvalidMuteTimeInterval", err.Error())
+			continue
+		}
+
+		periods := interval.TimeIntervals
+		if sync := syncs[interval.Name]; interval.Calendar != nil && sync != nil {
+			periods = append(append([]v1.AlertmanagerTimePeriod{}, periods...), sync.periods...)
+		}
+		if len(periods) == 0 {
+			continue
+		}
+		converted, err := getTimePeriods(periods)
+		if err != nil {
+			r.logger.Error(err, "skipped mute time interval", "interval", interval.Name)
+			r.recordEvent(cr, core.EventTypeWarning, "InvalidMuteTimeInterval", "time interval %v: %v", interval.Name, err)
+			continue
+		}
+		result = append(result, v1.AlertmanagerConfigTimeInterval{
+			Name:          getMuteTimeIntervalName(interval.Name),
+			TimeIntervals: converted,
+		})
+	}
+	return result
+}
+
+func validateMuteTimeInterval(interval *v1.AlertmanagerMuteTimeInterval, seen map[string]bool) error {
+	if interval.Name == "" {
+		return fmt.Errorf("time interval without name")
+	}
+	if seen[interval.Name] {
+		return fmt.Errorf("duplicate time interval %v", interval.Name)
+	}
+	seen[interval.Name] = true
+	if len(interval.TimeIntervals) == 0 && interval.Calendar == nil {
+		return fmt.Errorf("time interval %v has neither time intervals nor a calendar", interval.Name)
+	}
+	if interval.Calendar != nil && interval.Calendar.SyncInterval != "" {
+		if _, err := time.ParseDuration(interval.Calendar.SyncInterval); err != nil {
+			return fmt.Errorf("time interval %v: invalid sync interval %v", interval.Name, interval.Calendar.SyncInterval)
+		}
+	}
+	return nil
+}
+
+func getMuteTimeIntervalName(name string) string {
+	return fmt.Sprintf("%v-%v", muteTimeIntervalPrefix, name)
+}
+
+// Names of the time intervals of the CR that mute the routes of an index
+func getIndexMuteTimeIntervals(cr *v1.Observability, intervals []v1.AlertmanagerConfigTimeInterval, indexId string) []string {
+	available := map[string]bool{}
+	for _, interval := range intervals {
+		available[interval.Name] = true
+	}
+
+	var result []string
+	for _, interval := range cr.Spec.AlertmanagerMuteTimeIntervals {
+		name := getMuteTimeIntervalName(interval.Name)
+		if !available[name] {
+			continue
+		}
+		// Duplicates are only applied once
+		available[name] = false
+		if len(interval.Indexes) > 0 && !containsString(interval.Indexes, indexId) {
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+// Child routes don't inherit the mute time intervals of their parent, every route of the tree is muted.
+// Routes of the excluded receiver, the dead man's switch, keep notifying.
+func muteRoutes(routes []v1.AlertmanagerConfigRoute, intervals []string, excludedReceiver string) {
+	if len(intervals) == 0 {
+		return
+	}
+	for i := range routes {
+		route := &routes[i]
+		if excludedReceiver != "" && route.Receiver == excludedReceiver {
+			continue
+		}
+		route.MuteTimeIntervals = append(route.MuteTimeIntervals, intervals...)
+		muteRoutes(route.Routes, intervals, excludedReceiver)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Time periods of the events in UTC, one per day that an event covers. Full days match the whole day,
+// the other days match the minutes of the event.
+func getCalendarTimePeriods(events []calendarEvent) ([]v1.AlertmanagerTimePeriod, string) {
+	var result []v1.AlertmanagerTimePeriod
+	seen := map[string]bool{}
+	for _, event := range events {
+		start := event.start.UTC().Truncate(time.Minute)
+		end := event.end.UTC()
+		if end.Truncate(time.Minute) != end {
+			end = end.Truncate(time.Minute).Add(time.Minute)
+		}
+
+		for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC); day.Before(end); day = day.AddDate(0, 0, 1) {
+			from := start
+			if from.Before(day) {
+				from = day
+			}
+			to := end
+			if next := day.AddDate(0, 0, 1); to.After(next) {
+				to = next
+			}
+			if !from.Before(to) {
+				continue
+			}
+
+			period := v1.AlertmanagerTimePeriod{
+				Years:       []string{strconv.Itoa(day.Year())},
+				Months:      []string{strconv.Itoa(int(day.Month()))},
+				DaysOfMonth: []string{strconv.Itoa(day.Day())},
+			}
+			if from != day || to != day.AddDate(0, 0, 1) {
+				period.Times = []v1.AlertmanagerTimeRange{{
+					StartTime: formatTimeOfDay(from, day),
+					EndTime:   formatTimeOfDay(to, day),
+				}}
+			}
+
+			key := fmt.Sprintf("%+v", period)
+			if seen[key] {
+				continue
+			}
+			if len(result) == maxCalendarPeriods {
+				return result, fmt.Sprintf("calendar exceeds %v time periods, later events are not synced", maxCalendarPeriods)
+			}
+			seen[key] = true
+			result = append(result, period)
+		}
+	}
+	return result, ""
+}
+
+// Minutes since the start of the day, the end of the day is 24:00
+func formatTimeOfDay(t time.Time, day time.Time) string {
+	minutes := int(t.Sub(day) / time.Minute)
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Occurrences of the events that overlap the window, clipped to it and sorted by their start. Cancelled
+// events and events with a summary that doesn't match are skipped.
+func parseCalendar(content []byte, summary *regexp.Regexp, from time.Time, to time.Time) ([]calendarEvent, error) {
+	lines := unfoldCalendarLines(content)
+	if len(lines) == 0 || lines[0] != "BEGIN:VCALENDAR" {
+		return nil, fmt.Errorf("not an icalendar feed")
+	}
+
+	var result []calendarEvent
+	var properties map[string]calendarProperty
+	var exdates []calendarProperty
+	for _, line := range lines {
+		switch line {
+		case "BEGIN:VEVENT":
+			properties = map[string]calendarProperty{}
+			exdates = nil
+			continue
+		case "END:VEVENT":
+			if properties == nil {
+				continue
+			}
+			if summary == nil || summary.MatchString(properties["SUMMARY"].value) {
+				events, err := expandCalendarEvent(properties, exdates, from, to)
+				if err != nil {
+					return nil, fmt.Errorf("event %v: %v", properties["SUMMARY"].value, err)
+				}
+				result = append(result, events...)
+			}
+			properties = nil
+			continue
+		}
+		if properties == nil {
+			continue
+		}
+		property, ok := parseCalendarProperty(line)
+		if !ok {
+			continue
+		}
+		if property.name == "EXDATE" {
+			exdates = append(exdates, property)
+		} else {
+			properties[property.name] = property
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].start.Before(result[j].start)
+	})
+	return result, nil
+}
+
+// Long lines are folded onto continuation lines that start with a space or tab
+func unfoldCalendarLines(content []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), maxCalendarSize)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+type calendarProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// NAME;PARAM=VALUE;PARAM="QUOTED:VALUE":VALUE
+func parseCalendarProperty(line string) (calendarProperty, bool) {
+	quoted := false
+	separator := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			separator = i
+			break
+		}
+	}
+	if separator < 0 {
+		return calendarProperty{}, false
+	}
+
+	parts := strings.Split(line[:separator], ";")
+	property := calendarProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: map[string]string{},
+		value:  line[separator+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return property, true
+}
+
+func expandCalendarEvent(properties map[string]calendarProperty, exdates []calendarProperty, from time.Time, to time.Time) ([]calendarEvent, error) {
+	if strings.EqualFold(properties["STATUS"].value, "CANCELLED") {
+		return nil, nil
+	}
+	dtstart, ok := properties["DTSTART"]
+	if !ok {
+		return nil, fmt.Errorf("no start")
+	}
+	start, allDay, err := parseCalendarTime(dtstart)
+	if err != nil {
+		return nil, err
+	}
+
+	var duration time.Duration
+	if dtend, ok := properties["DTEND"]; ok {
+		end, _, err := parseCalendarTime(dtend)
+		if err != nil {
+			return nil, err
+		}
+		duration = end.Sub(start)
+	} else if value, ok := properties["DURATION"]; ok {
+		duration, err = parseCalendarDuration(value.value)
+		if err != nil {
+			return nil, err
+		}
+	} else if allDay {
+		duration = 24 * time.Hour
+	}
+	if duration <= 0 {
+		return nil, nil
+	}
+
+	excluded := map[int64]bool{}
+	for _, exdate := range exdates {
+		for _, value := range strings.Split(exdate.value, ",") {
+			exdate.value = value
+			t, _, err := parseCalendarTime(exdate)
+			if err != nil {
+				return nil, err
+			}
+			excluded[t.Unix()] = true
+		}
+	}
+
+	var starts []time.Time
+	if rule, ok := properties["RRULE"]; ok {
+		starts, err = expandCalendarRule(rule.value, start, from.Add(-duration), to)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		starts = []time.Time{start}
+	}
+
+	var result []calendarEvent
+	for _, occurrence := range starts {
+		end := occurrence.Add(duration)
+		if excluded[occurrence.Unix()] || !end.After(from) || !occurrence.Before(to) {
+			continue
+		}
+		if occurrence.Before(from) {
+			occurrence = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		result = append(result, calendarEvent{start: occurrence.UTC(), end: end.UTC()})
+	}
+	return result, nil
+}
+
+// UTC times end with Z, local times are in the zone of the TZID parameter and floating times in UTC.
+// Zones that are unknown, e.g. Windows zone names, fall back to UTC.
+func parseCalendarTime(property calendarProperty) (time.Time, bool, error) {
+	value := property.value
+	if property.params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.UTC)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %v", value)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %v", value)
+		}
+		return t, false, nil
+	}
+
+	location := time.UTC
+	if tzid := property.params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %v", value)
+	}
+	return t, false, nil
+}
+
+// Durations in weeks, days, hours, minutes and seconds, e.g. PT1H30M
+func parseCalendarDuration(value string) (time.Duration, error) {
+	match := durationRegex.FindStringSubmatch(value)
+	if match == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid duration %v", value)
+	}
+
+	var duration time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if match[i+2] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(match[i+2])
+		duration += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+// Starts of the occurrences of daily, weekly and monthly rules up to the end of the window. Occurrences
+// start at the local time of the first one, also across daylight saving time changes.
+func expandCalendarRule(value string, start time.Time, from time.Time, to time.Time) ([]time.Time, error) {
+	rule := map[string]string{}
+	for _, part := range strings.Split(value, ";") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			rule[strings.ToUpper(key)] = strings.ToUpper(value)
+		}
+	}
+
+	interval := 1
+	if value, ok := rule["INTERVAL"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid interval %v", value)
+		}
+		interval = n
+	}
+	count := -1
+	if value, ok := rule["COUNT"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid count %v", value)
+		}
+		count = n
+	}
+	until := to
+	if value, ok := rule["UNTIL"]; ok {
+		t, _, err := parseCalendarTime(calendarProperty{value: value, params: map[string]string{}})
+		if err != nil {
+			return nil, err
+		}
+		if len(value) == 8 {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		if t.Before(until) {
+			until = t
+		}
+	}
+
+	var days []time.Weekday
+	if value, ok := rule["BYDAY"]; ok {
+		for _, day := range strings.Split(value, ",") {
+			weekday, ok := calendarWeekdays[day]
+			if !ok {
+				return nil, fmt.Errorf("unsupported day %v", day)
+			}
+			days = append(days, weekday)
+		}
+	}
+
+	// Candidates of the n-th period of the rule, in the order they occur
+	var candidates func(n int) []time.Time
+	switch rule["FREQ"] {
+	case "DAILY":
+		candidates = func(n int) []time.Time {
+			t := start.AddDate(0, 0, n*interval)
+			if len(days) > 0 && !containsWeekday(days, t.Weekday()) {
+				return nil
+			}
+			return []time.Time{t}
+		}
+	case "WEEKLY":
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		// Weeks start on monday
+		weekStart := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		candidates = func(n int) []time.Time {
+			week := weekStart.AddDate(0, 0, 7*n*interval)
+			var result []time.Time
+			for offset := 0; offset < 7; offset++ {
+				t := week.AddDate(0, 0, offset)
+				if containsWeekday(days, t.Weekday()) && !t.Before(start) {
+					result = append(result, t)
+				}
+			}
+			return result
+		}
+	case "MONTHLY":
+		if len(days) > 0 {
+			return nil, fmt.Errorf("unsupported monthly rule %v", value)
+		}
+		candidates = func(n int) []time.Time {
+			t := time.Date(start.Year(), start.Month()+time.Month(n*interval), start.Day(), start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+			// Months without the day of the first occurrence are skipped
+			if t.Day() != start.Day() {
+				return nil
+			}
+			return []time.Time{t}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported recurrence %v", value)
+	}
+
+	var result []time.Time
+	occurrences := 0
+	for n := 0; n < maxCalendarOccurrences; n++ {
+		for _, t := range candidates(n) {
+			if t.After(until) || count == 0 {
+				return result, nil
+			}
+			occurrences++
+			if occurrences > maxCalendarOccurrences {
+				return nil, fmt.Errorf("recurrence exceeds %v occurrences", maxCalendarOccurrences)
+			}
+			if count > 0 {
+				count--
+			}
+			if !t.Before(from) {
+				result = append(result, t)
+			}
+		}
+	}
+	return nil, fmt.Errorf("recurrence exceeds %v occurrences", maxCalendarOccurrences)
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
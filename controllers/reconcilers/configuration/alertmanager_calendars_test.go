@@ -0,0 +1,245 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Database maintenance\r\n" +
+	"DTSTART:20261015T220000Z\r\n" +
+	"DTEND:20261016T020000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Weekly patching\r\n" +
+	"DTSTART;TZID=Europe/Dublin:20260101T090000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20261022T235959Z\r\n" +
+	"EXDATE;TZID=Europe/Dublin:20261020T090000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Cancelled maintenance\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART;VALUE=DATE:20261017\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Company\r\n" +
+	"  offsite\r\n" +
+	"DTSTART;VALUE=DATE:20261019\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestAlertmanagerCalendars_ParseCalendar(t *testing.T) {
+	RegisterTestingT(t)
+
+	from := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	to := from.Add(calendarHorizon)
+	events, err := parseCalendar([]byte(testCalendar), nil, from, to)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Dublin is on summer time until the 25th of october, the excluded occurrence is skipped
+	Expect(events).To(Equal([]calendarEvent{
+		{start: time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC), end: time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)},
+		{start: time.Date(2026, 10, 15, 22, 0, 0, 0, time.UTC), end: time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)},
+		{start: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), end: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{start: time.Date(2026, 10, 22, 8, 0, 0, 0, time.UTC), end: time.Date(2026, 10, 22, 9, 30, 0, 0, time.UTC)},
+	}))
+
+	events, err = parseCalendar([]byte(testCalendar), regexp.MustCompile("(?i)maintenance"), from, to)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(events).To(HaveLen(1))
+
+	_, err = parseCalendar([]byte("<html></html>"), nil, from, to)
+	Expect(err).To(HaveOccurred())
+	_, err = parseCalendar([]byte("BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20261015T220000Z\nDURATION:PT1H\nRRULE:FREQ=YEARLY\nEND:VEVENT\nEND:VCALENDAR\n"), nil, from, to)
+	Expect(err).To(MatchError(ContainSubstring("unsupported recurrence")))
+}
+
+func TestAlertmanagerCalendars_ExpandCalendarRule(t *testing.T) {
+	RegisterTestingT(t)
+
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	starts, err := expandCalendarRule("FREQ=DAILY;INTERVAL=2;COUNT=3", start, start, to)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(starts).To(Equal([]time.Time{start, start.AddDate(0, 0, 2), start.AddDate(0, 0, 4)}))
+
+	// Months without a 31st are skipped
+	starts, err = expandCalendarRule("FREQ=MONTHLY;UNTIL=20260601", start, start, to)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(starts).To(HaveLen(3))
+	Expect(starts[1].Month()).To(Equal(time.March))
+
+	// Occurrences before the window count towards the count of the rule
+	starts, err = expandCalendarRule("FREQ=WEEKLY;COUNT=4", start, start.AddDate(0, 0, 15), to)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(starts).To(Equal([]time.Time{start.AddDate(0, 0, 21)}))
+
+	for _, rule := range []string{"FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;INTERVAL=0", "FREQ=MONTHLY;BYDAY=1MO", "FREQ=HOURLY"} {
+		_, err = expandCalendarRule(rule, start, start, to)
+		Expect(err).To(HaveOccurred(), rule)
+	}
+}
+
+func TestAlertmanagerCalendars_ParseCalendarDuration(t *testing.T) {
+	RegisterTestingT(t)
+
+	for value, expected := range map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"P1DT12H": 36 * time.Hour,
+		"-PT15M":  -15 * time.Minute,
+	} {
+		duration, err := parseCalendarDuration(value)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(duration).To(Equal(expected), value)
+	}
+
+	for _, value := range []string{"P", "PT", "1H", "PT1.5H"} {
+		_, err := parseCalendarDuration(value)
+		Expect(err).To(HaveOccurred(), value)
+	}
+}
+
+func TestAlertmanagerCalendars_GetCalendarTimePeriods(t *testing.T) {
+	RegisterTestingT(t)
+
+	periods, message := getCalendarTimePeriods([]calendarEvent{
+		{start: time.Date(2026, 10, 15, 22, 0, 0, 0, time.UTC), end: time.Date(2026, 10, 17, 2, 0, 30, 0, time.UTC)},
+	})
+	Expect(message).To(BeEmpty())
+	Expect(periods).To(Equal([]v1.AlertmanagerTimePeriod{
+		{Years: []string{"2026"}, Months: []string{"10"}, DaysOfMonth: []string{"15"}, Times: []v1.AlertmanagerTimeRange{{StartTime: "22:00", EndTime: "24:00"}}},
+		{Years: []string{"2026"}, Months: []string{"10"}, DaysOfMonth: []string{"16"}},
+		{Years: []string{"2026"}, Months: []string{"10"}, DaysOfMonth: []string{"17"}, Times: []v1.AlertmanagerTimeRange{{StartTime: "00:00", EndTime: "02:01"}}},
+	}))
+	_, err := getTimePeriods(periods)
+	Expect(err).ToNot(HaveOccurred())
+
+	var events []calendarEvent
+	for i := 0; i < maxCalendarPeriods+1; i++ {
+		start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+		events = append(events, calendarEvent{start: start, end: start.Add(time.Hour)})
+	}
+	periods, message = getCalendarTimePeriods(events)
+	Expect(periods).To(HaveLen(maxCalendarPeriods))
+	Expect(message).ToNot(BeEmpty())
+}
+
+func TestAlertmanagerCalendars_MuteTimeIntervals(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := &v1.Observability{
+		Spec: v1.ObservabilitySpec{
+			AlertmanagerMuteTimeIntervals: []v1.AlertmanagerMuteTimeInterval{
+				{
+					Name:          "weekend",
+					TimeIntervals: []v1.AlertmanagerTimePeriod{{Weekdays: []string{"saturday", "sunday"}}},
+					Indexes:       []string{"index-1"},
+				},
+				{Name: "maintenance", Calendar: &v1.AlertmanagerCalendar{Url: "https://calendar.example.com/maintenance.ics"}},
+				{Name: "upcoming", Calendar: &v1.AlertmanagerCalendar{Url: "https://calendar.example.com/upcoming.ics"}},
+				{Name: "invalid", TimeIntervals: []v1.AlertmanagerTimePeriod{{Weekdays: []string{"someday"}}}},
+				{Name: "weekend", TimeIntervals: []v1.AlertmanagerTimePeriod{{Weekdays: []string{"monday"}}}},
+			},
+		},
+	}
+	r := &Reconciler{logger: logr.Discard()}
+	r.setCalendarSyncs(cr, map[string]*calendarSync{
+		"maintenance": {periods: []v1.AlertmanagerTimePeriod{{Years: []string{"2026"}, Months: []string{"10"}, DaysOfMonth: []string{"16"}}}},
+		"upcoming":    {},
+	})
+
+	// Calendars without events and invalid or duplicate intervals are left out
+	intervals := r.getMuteTimeIntervals(cr)
+	Expect(intervals).To(HaveLen(2))
+	Expect(intervals[0].Name).To(Equal("observability-weekend"))
+	Expect(intervals[0].TimeIntervals[0].Weekdays).To(Equal([]string{"saturday", "sunday"}))
+	Expect(intervals[1].Name).To(Equal("observability-maintenance"))
+	Expect(intervals[1].TimeIntervals[0].DaysOfMonth).To(Equal([]string{"16"}))
+
+	Expect(getIndexMuteTimeIntervals(cr, intervals, "index-1")).To(Equal([]string{"observability-weekend", "observability-maintenance"}))
+	Expect(getIndexMuteTimeIntervals(cr, intervals, "index-2")).To(Equal([]string{"observability-maintenance"}))
+
+	// The dead man's switch keeps notifying, child routes are muted as well
+	routes := []v1.AlertmanagerConfigRoute{
+		{Receiver: "index-1-deadmanssnitch"},
+		{Receiver: "index-1-pagerduty", Routes: []v1.AlertmanagerConfigRoute{{Receiver: "index-1-smtp"}}},
+	}
+	muteRoutes(routes, []string{"observability-weekend"}, "index-1-deadmanssnitch")
+	Expect(routes[0].MuteTimeIntervals).To(BeEmpty())
+	Expect(routes[1].MuteTimeIntervals).To(Equal([]string{"observability-weekend"}))
+	Expect(routes[1].Routes[0].MuteTimeIntervals).To(Equal([]string{"observability-weekend"}))
+}
+
+// The reconcilers are created for every reconcile, the periods of a calendar that can't be fetched are
+// kept in the shared sync state
+func TestAlertmanagerCalendars_KeptWhileFetchFails(t *testing.T) {
+	RegisterTestingT(t)
+
+	start := time.Now().Add(24 * time.Hour).UTC()
+	calendar := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Maintenance\r\n" +
+		fmt.Sprintf("DTSTART:%v\r\n", start.Format("20060102T150405Z")) +
+		"DURATION:PT1H\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetches++
+		if fetches > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(calendar))
+	}))
+	defer server.Close()
+
+	cr := &v1.Observability{
+		Spec: v1.ObservabilitySpec{
+			AlertmanagerMuteTimeIntervals: []v1.AlertmanagerMuteTimeInterval{
+				{Name: "maintenance", Calendar: &v1.AlertmanagerCalendar{Url: server.URL, SyncInterval: "1ns"}},
+			},
+		},
+	}
+	state := NewSyncState()
+	reconcile := func() (*v1.ObservabilityStatus, []v1.AlertmanagerConfigTimeInterval) {
+		r := NewReconciler(nil, logr.Discard(), nil, state).(*Reconciler)
+		r.httpClient = server.Client()
+		s := &v1.ObservabilityStatus{}
+		r.reconcileAlertmanagerCalendars(context.Background(), cr, s)
+		return s, r.getMuteTimeIntervals(cr)
+	}
+
+	s, intervals := reconcile()
+	Expect(s.AlertmanagerCalendars[0].Message).To(BeEmpty())
+	Expect(intervals).To(HaveLen(1))
+
+	// The second fetch fails, the mute window of the first sync is kept
+	s, intervals = reconcile()
+	Expect(fetches).To(Equal(2))
+	Expect(s.AlertmanagerCalendars[0].Message).ToNot(BeEmpty())
+	Expect(s.AlertmanagerCalendars[0].Events).To(Equal(1))
+	Expect(intervals).To(HaveLen(1))
+	Expect(intervals[0].TimeIntervals[0].DaysOfMonth).To(Equal([]string{fmt.Sprint(start.Day())}))
+
+	// Calendars that aren't due are not fetched again
+	cr.Spec.AlertmanagerMuteTimeIntervals[0].Calendar.SyncInterval = "1h"
+	_, intervals = reconcile()
+	Expect(fetches).To(Equal(2))
+	Expect(intervals).To(HaveLen(1))
+}
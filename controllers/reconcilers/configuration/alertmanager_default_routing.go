@@ -0,0 +1,226 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	core "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultRoutingPagerDutyReceiver = "observability-pagerduty"
+	defaultRoutingSlackReceiver     = "observability-slack"
+	defaultSeverityLabel            = "severity"
+	defaultRoutingGroupWait         = "30s"
+	defaultRoutingGroupInterval     = "5m"
+	defaultPageRepeatInterval       = "4h"
+	defaultNotifyRepeatInterval     = "12h"
+
+	slackTitleTemplate = `[{{ .Status | toUpper }}{{ if eq .Status "firing" }}:{{ .Alerts.Firing | len }}{{ end }}] {{ .CommonLabels.alertname }}`
+	slackTextTemplate  = `{{ range .Alerts }}*{{ .Labels.severity }}* {{ .Labels.namespace }}: {{ if .Annotations.summary }}{{ .Annotations.summary }}{{ else }}{{ .Annotations.description }}{{ end }}
+{{ end }}`
+)
+
+var (
+	defaultPageSeverities   = []string{"critical"}
+	defaultNotifySeverities = []string{"critical", "warning"}
+	defaultRoutingGroupBy   = []string{"alertname", "namespace"}
+)
+
+// Receivers, routes and inhibit rules of the default routing, appended after the routes of the indexes
+type defaultRouting struct {
+	receivers    []v1.AlertmanagerConfigReceiver
+	routes       []v1.AlertmanagerConfigRoute
+	inhibitRules []v1.AlertmanagerConfigInhibitRule
+}
+
+// Nil without default routing. Receivers without a secret are left out.
+func (r *Reconciler) getDefaultRouting(ctx context.Context, cr *v1.Observability) (*defaultRouting, error) {
+	if cr.Spec.SelfContained == nil || cr.Spec.SelfContained.AlertManagerDefaultRouting == nil {
+		return nil, nil
+	}
+	config := cr.Spec.SelfContained.AlertManagerDefaultRouting
+
+	pagerDutyKey, err := r.getDefaultRoutingSecret(ctx, cr, config.PagerDutySecretRef)
+	if err != nil {
+		return nil, err
+	}
+	slackUrl, err := r.getDefaultRoutingSecret(ctx, cr, config.SlackSecretRef)
+	if err != nil {
+		return nil, err
+	}
+	return getDefaultRoutes(config, pagerDutyKey, slackUrl)
+}
+
+func (r *Reconciler) getDefaultRoutingSecret(ctx context.Context, cr *v1.Observability, selector *core.SecretKeySelector) (string, error) {
+	if selector == nil {
+		return "", nil
+	}
+	secret := &core.Secret{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: selector.Name}, secret)
+	if err != nil {
+		return "", fmt.Errorf("error fetching default routing secret %v: %v", selector.Name, err)
+	}
+	value := strings.TrimSpace(string(secret.Data[selector.Key]))
+	if value == "" {
+		return "", fmt.Errorf("default routing secret %v has no key %v", selector.Name, selector.Key)
+	}
+	return value, nil
+}
+
+// Pages continue to the notifications, so that critical alerts show up in Slack as well
+func getDefaultRoutes(config *v1.AlertmanagerDefaultRouting, pagerDutyKey string, slackUrl string) (*defaultRouting, error) {
+	severityLabel := config.SeverityLabel
+	if severityLabel == "" {
+		severityLabel = defaultSeverityLabel
+	}
+	if !commonmodel.LabelName(severityLabel).IsValid() {
+		return nil, fmt.Errorf("invalid severity label %v", severityLabel)
+	}
+	groupBy := config.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = defaultRoutingGroupBy
+	}
+	for _, label := range groupBy {
+		if label != "..." && !commonmodel.LabelName(label).IsValid() {
+			return nil, fmt.Errorf("invalid group by label %v", label)
+		}
+	}
+	groupWait, err := getDefaultRoutingDuration("group wait", config.GroupWait, defaultRoutingGroupWait)
+	if err != nil {
+		return nil, err
+	}
+	groupInterval, err := getDefaultRoutingDuration("group interval", config.GroupInterval, defaultRoutingGroupInterval)
+	if err != nil {
+		return nil, err
+	}
+	pageRepeatInterval, err := getDefaultRoutingDuration("repeat interval", config.RepeatInterval, defaultPageRepeatInterval)
+	if err != nil {
+		return nil, err
+	}
+	notifyRepeatInterval, _ := getDefaultRoutingDuration("repeat interval", config.RepeatInterval, defaultNotifyRepeatInterval)
+
+	pageSeverities := config.PageSeverities
+	if len(pageSeverities) == 0 {
+		pageSeverities = defaultPageSeverities
+	}
+	notifySeverities := config.NotifySeverities
+	if len(notifySeverities) == 0 {
+		notifySeverities = defaultNotifySeverities
+	}
+
+	result := &defaultRouting{
+		// The dead man's switch fires all the time, it is only meant for a snitch
+		routes: []v1.AlertmanagerConfigRoute{
+			{
+				Receiver: defaultReceiver,
+				Matchers: []string{`alertname="DeadMansSwitch"`},
+			},
+		},
+	}
+
+	if pagerDutyKey != "" {
+		matcher, err := getSeverityMatcher(severityLabel, pageSeverities)
+		if err != nil {
+			return nil, err
+		}
+		result.receivers = append(result.receivers, v1.AlertmanagerConfigReceiver{
+			Name: defaultRoutingPagerDutyReceiver,
+			PagerDutyConfigs: []v1.PagerDutyConfig{
+				{
+					RoutingKey: pagerDutyKey,
+					Severity:   fmt.Sprintf(`{{ if .CommonLabels.%[1]v }}{{ .CommonLabels.%[1]v }}{{ else }}critical{{ end }}`, severityLabel),
+				},
+			},
+		})
+		result.routes = append(result.routes, v1.AlertmanagerConfigRoute{
+			Receiver:       defaultRoutingPagerDutyReceiver,
+			Matchers:       []string{matcher},
+			GroupBy:        groupBy,
+			GroupWait:      groupWait,
+			GroupInterval:  groupInterval,
+			RepeatInterval: pageRepeatInterval,
+			Continue:       slackUrl != "",
+		})
+	}
+
+	if slackUrl != "" {
+		matcher, err := getSeverityMatcher(severityLabel, notifySeverities)
+		if err != nil {
+			return nil, err
+		}
+		result.receivers = append(result.receivers, v1.AlertmanagerConfigReceiver{
+			Name: defaultRoutingSlackReceiver,
+			SlackConfigs: []v1.SlackConfig{
+				{
+					SendResolved: true,
+					ApiUrl:       slackUrl,
+					Channel:      config.SlackChannel,
+					Title:        slackTitleTemplate,
+					Text:         strings.ReplaceAll(slackTextTemplate, ".Labels.severity", ".Labels."+severityLabel),
+				},
+			},
+		})
+		result.routes = append(result.routes, v1.AlertmanagerConfigRoute{
+			Receiver:       defaultRoutingSlackReceiver,
+			Matchers:       []string{matcher},
+			GroupBy:        groupBy,
+			GroupWait:      groupWait,
+			GroupInterval:  groupInterval,
+			RepeatInterval: notifyRepeatInterval,
+		})
+	}
+
+	// The most severe of the paged severities inhibits the notified ones that are not paged
+	var inhibited []string
+	for _, severity := range notifySeverities {
+		if !containsString(pageSeverities, severity) {
+			inhibited = append(inhibited, severity)
+		}
+	}
+	if len(inhibited) > 0 {
+		source, err := getSeverityMatcher(severityLabel, pageSeverities[:1])
+		if err != nil {
+			return nil, err
+		}
+		target, err := getSeverityMatcher(severityLabel, inhibited)
+		if err != nil {
+			return nil, err
+		}
+		result.inhibitRules = append(result.inhibitRules, v1.AlertmanagerConfigInhibitRule{
+			SourceMatchers: []string{source},
+			TargetMatchers: []string{target},
+			Equal:          []string{"alertname", "namespace"},
+		})
+	}
+	return result, nil
+}
+
+func getDefaultRoutingDuration(kind string, value string, defaultValue string) (string, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	if _, err := commonmodel.ParseDuration(value); err != nil {
+		return "", fmt.Errorf("invalid %v %v", kind, value)
+	}
+	return value, nil
+}
+
+// Severities are matched exactly, e.g. severity=~"critical|warning"
+func getSeverityMatcher(label string, severities []string) (string, error) {
+	var quoted []string
+	for _, severity := range severities {
+		if severity == "" {
+			return "", fmt.Errorf("empty severity")
+		}
+		quoted = append(quoted, regexp.QuoteMeta(severity))
+	}
+	matcher := fmt.Sprintf("%v=~%v", label, strconv.Quote(strings.Join(quoted, "|")))
+	return matcher, validateAlertmanagerMatcher(matcher)
+}
@@ -0,0 +1,83 @@
+package configuration
+
+import (
+	"testing"
+
+	goyaml "github.com/goccy/go-yaml"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestAlertmanagerDefaultRouting_GetDefaultRoutes(t *testing.T) {
+	RegisterTestingT(t)
+
+	routing, err := getDefaultRoutes(&v1.AlertmanagerDefaultRouting{SlackChannel: "#alerts"}, "integration-key", "https://hooks.slack.com/services/T0/B0/X")
+	Expect(err).ToNot(HaveOccurred())
+
+	Expect(routing.receivers).To(HaveLen(2))
+	Expect(routing.receivers[0].PagerDutyConfigs[0].RoutingKey).To(Equal("integration-key"))
+	Expect(routing.receivers[1].SlackConfigs[0].Channel).To(Equal("#alerts"))
+
+	// The dead man's switch is dropped, pages continue to Slack
+	Expect(routing.routes).To(HaveLen(3))
+	Expect(routing.routes[0].Receiver).To(Equal(defaultReceiver))
+	Expect(routing.routes[1]).To(Equal(v1.AlertmanagerConfigRoute{
+		Receiver:       defaultRoutingPagerDutyReceiver,
+		Matchers:       []string{`severity=~"critical"`},
+		GroupBy:        []string{"alertname", "namespace"},
+		GroupWait:      "30s",
+		GroupInterval:  "5m",
+		RepeatInterval: "4h",
+		Continue:       true,
+	}))
+	Expect(routing.routes[2].Matchers).To(Equal([]string{`severity=~"critical|warning"`}))
+	Expect(routing.routes[2].RepeatInterval).To(Equal("12h"))
+	Expect(routing.inhibitRules).To(Equal([]v1.AlertmanagerConfigInhibitRule{
+		{
+			SourceMatchers: []string{`severity=~"critical"`},
+			TargetMatchers: []string{`severity=~"warning"`},
+			Equal:          []string{"alertname", "namespace"},
+		},
+	}))
+
+	// Alertmanager reads the templates as they are
+	config, err := goyaml.Marshal(&v1.AlertmanagerConfigRoot{Receivers: routing.receivers})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(string(config)).To(ContainSubstring("routing_key: integration-key"))
+	Expect(string(config)).To(ContainSubstring("send_resolved: true"))
+	Expect(string(config)).ToNot(ContainSubstring("service_key"))
+
+	// Custom severities with only a Slack receiver
+	routing, err = getDefaultRoutes(&v1.AlertmanagerDefaultRouting{
+		SeverityLabel:    "priority",
+		PageSeverities:   []string{"P1"},
+		NotifySeverities: []string{"P1", "P2", "P3"},
+		RepeatInterval:   "1h",
+	}, "", "https://hooks.slack.com/services/T0/B0/X")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(routing.receivers).To(HaveLen(1))
+	Expect(routing.routes[1].Matchers).To(Equal([]string{`priority=~"P1|P2|P3"`}))
+	Expect(routing.routes[1].RepeatInterval).To(Equal("1h"))
+	Expect(routing.receivers[0].SlackConfigs[0].Text).To(ContainSubstring(".Labels.priority"))
+	Expect(routing.inhibitRules[0].TargetMatchers).To(Equal([]string{`priority=~"P2|P3"`}))
+
+	for _, config := range []v1.AlertmanagerDefaultRouting{
+		{SeverityLabel: "alert-severity"},
+		{GroupBy: []string{"team-name"}},
+		{GroupWait: "30 seconds"},
+		{RepeatInterval: "daily"},
+		{PageSeverities: []string{""}},
+	} {
+		config := config
+		_, err = getDefaultRoutes(&config, "integration-key", "")
+		Expect(err).To(HaveOccurred(), "%+v", config)
+	}
+}
+
+func TestAlertmanagerDefaultRouting_GetSeverityMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	matcher, err := getSeverityMatcher("severity", []string{"critical", "sev.1"})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(matcher).To(Equal(`severity=~"critical|sev\\.1"`))
+}
@@ -0,0 +1,452 @@
+package configuration
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	// The operator image has no time zone database to validate the locations of the time intervals
+	_ "time/tzdata"
+
+	"github.com/ghodss/yaml"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	// Receiver of the alerts that no route matches
+	defaultReceiver = "default-receiver"
+	// Receivers of an index are referenced without the id of the index
+	indexDefaultReceiver = "default"
+	// Marks the alerts the relabel configs of an index apply to, removed after the configs of the index
+	alertRelabelScopeLabel = "__observability_relabel_scope"
+)
+
+var (
+	alertmanagerMatcherRegex = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+	timeOfDayRegex           = regexp.MustCompile(`^(\d{2}):(\d{2})$`)
+
+	// The other actions can't be limited to the alerts of an index
+	alertRelabelActions = map[string]bool{
+		"replace": true, "keep": true, "drop": true,
+	}
+	weekdays = map[string]int{
+		"sunday": 0, "monday": 1, "tuesday": 2, "wednesday": 3, "thursday": 4, "friday": 5, "saturday": 6,
+	}
+	months = map[string]int{
+		"january": 1, "february": 2, "march": 3, "april": 4, "may": 5, "june": 6, "july": 7,
+		"august": 8, "september": 9, "october": 10, "november": 11, "december": 12,
+	}
+)
+
+// Routes, inhibit rules and time intervals that an index declares in addition to its receivers
+type indexRouting struct {
+	route         *v1.AlertmanagerConfigRoute
+	inhibitRules  []v1.AlertmanagerConfigInhibitRule
+	timeIntervals []v1.AlertmanagerConfigTimeInterval
+}
+
+// The routing tree of an index only matches its own alerts. Time intervals are prefixed with the id of
+// the index, so that indexes can't mute the alerts of others. Nil if the index declares nothing.
+func getIndexRouting(index *v1.RepositoryIndex, receivers map[string]string) (*indexRouting, error) {
+	config := index.Config.Alertmanager
+	if len(config.Routes) == 0 && len(config.InhibitRules) == 0 && len(config.MuteTimeIntervals) == 0 {
+		return nil, nil
+	}
+
+	indexMatcher := fmt.Sprintf(`%v="%v"`, PrometheusRuleIdentifierKey, index.Id)
+	result := &indexRouting{}
+
+	intervals := map[string]string{}
+	for _, interval := range config.MuteTimeIntervals {
+		if interval.Name == "" {
+			return nil, fmt.Errorf("time interval without name")
+		}
+		if _, ok := intervals[interval.Name]; ok {
+			return nil, fmt.Errorf("duplicate time interval %v", interval.Name)
+		}
+		periods, err := getTimePeriods(interval.TimeIntervals)
+		if err != nil {
+			return nil, fmt.Errorf("time interval %v: %v", interval.Name, err)
+		}
+		name := fmt.Sprintf("%v-%v", index.Id, interval.Name)
+		intervals[interval.Name] = name
+		result.timeIntervals = append(result.timeIntervals, v1.AlertmanagerConfigTimeInterval{
+			Name:          name,
+			TimeIntervals: periods,
+		})
+	}
+
+	if len(config.Routes) > 0 {
+		routes, err := getIndexRoutes(config.Routes, receivers, intervals)
+		if err != nil {
+			return nil, err
+		}
+		result.route = &v1.AlertmanagerConfigRoute{
+			Receiver: defaultReceiver,
+			Matchers: []string{indexMatcher},
+			Routes:   routes,
+		}
+	}
+
+	for i, rule := range config.InhibitRules {
+		if len(rule.SourceMatchers) == 0 || len(rule.TargetMatchers) == 0 {
+			return nil, fmt.Errorf("inhibit rule %v: source and target matchers are required", i)
+		}
+		for _, matcher := range append(append([]string{}, rule.SourceMatchers...), rule.TargetMatchers...) {
+			if err := validateAlertmanagerMatcher(matcher); err != nil {
+				return nil, fmt.Errorf("inhibit rule %v: %v", i, err)
+			}
+		}
+		for _, label := range rule.Equal {
+			if !commonmodel.LabelName(label).IsValid() {
+				return nil, fmt.Errorf("inhibit rule %v: invalid label name %v", i, label)
+			}
+		}
+		result.inhibitRules = append(result.inhibitRules, v1.AlertmanagerConfigInhibitRule{
+			SourceMatchers: append([]string{indexMatcher}, rule.SourceMatchers...),
+			TargetMatchers: append([]string{indexMatcher}, rule.TargetMatchers...),
+			Equal:          rule.Equal,
+		})
+	}
+	return result, nil
+}
+
+func getIndexRoutes(routes []v1.AlertmanagerIndexRoute, receivers map[string]string, intervals map[string]string) ([]v1.AlertmanagerConfigRoute, error) {
+	var result []v1.AlertmanagerConfigRoute
+	for _, route := range routes {
+		receiver := ""
+		if route.Receiver != "" {
+			var ok bool
+			receiver, ok = receivers[route.Receiver]
+			if !ok {
+				return nil, fmt.Errorf("unknown receiver %v", route.Receiver)
+			}
+		}
+		for _, matcher := range route.Matchers {
+			if err := validateAlertmanagerMatcher(matcher); err != nil {
+				return nil, err
+			}
+		}
+		for _, label := range route.GroupBy {
+			if label != "..." && !commonmodel.LabelName(label).IsValid() {
+				return nil, fmt.Errorf("invalid group by label %v", label)
+			}
+		}
+		for _, duration := range []string{route.GroupWait, route.GroupInterval, route.RepeatInterval} {
+			if duration == "" {
+				continue
+			}
+			if _, err := commonmodel.ParseDuration(duration); err != nil {
+				return nil, fmt.Errorf("invalid duration %v", duration)
+			}
+		}
+		muteTimeIntervals, err := getTimeIntervalNames(route.MuteTimeIntervals, intervals)
+		if err != nil {
+			return nil, err
+		}
+		activeTimeIntervals, err := getTimeIntervalNames(route.ActiveTimeIntervals, intervals)
+		if err != nil {
+			return nil, err
+		}
+		children, err := getIndexRoutes(route.Routes, receivers, intervals)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, v1.AlertmanagerConfigRoute{
+			Receiver:            receiver,
+			Matchers:            route.Matchers,
+			GroupBy:             route.GroupBy,
+			GroupWait:           route.GroupWait,
+			GroupInterval:       route.GroupInterval,
+			RepeatInterval:      route.RepeatInterval,
+			Continue:            route.Continue,
+			MuteTimeIntervals:   muteTimeIntervals,
+			ActiveTimeIntervals: activeTimeIntervals,
+			Routes:              children,
+		})
+	}
+	return result, nil
+}
+
+func getTimeIntervalNames(names []string, intervals map[string]string) ([]string, error) {
+	var result []string
+	for _, name := range names {
+		interval, ok := intervals[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown time interval %v", name)
+		}
+		result = append(result, interval)
+	}
+	return result, nil
+}
+
+// Matchers are checked for what Alertmanager parses, values may be quoted
+func validateAlertmanagerMatcher(matcher string) error {
+	match := alertmanagerMatcherRegex.FindStringSubmatch(matcher)
+	if match == nil {
+		return fmt.Errorf("invalid matcher %v", matcher)
+	}
+	value := match[3]
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return fmt.Errorf("invalid matcher %v: %v", matcher, err)
+		}
+		value = unquoted
+	}
+	if match[2] == "=~" || match[2] == "!~" {
+		// Alertmanager anchors the regexes of matchers
+		if _, err := regexp.Compile(fmt.Sprintf("^(?:%v)$", value)); err != nil {
+			return fmt.Errorf("invalid matcher %v: %v", matcher, err)
+		}
+	}
+	return nil
+}
+
+func getTimePeriods(periods []v1.AlertmanagerTimePeriod) ([]v1.AlertmanagerConfigTimePeriod, error) {
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("no time intervals")
+	}
+
+	var result []v1.AlertmanagerConfigTimePeriod
+	for _, period := range periods {
+		var times []v1.AlertmanagerConfigTimeRange
+		for _, timeRange := range period.Times {
+			start, err := parseTimeOfDay(timeRange.StartTime)
+			if err != nil {
+				return nil, err
+			}
+			end, err := parseTimeOfDay(timeRange.EndTime)
+			if err != nil {
+				return nil, err
+			}
+			if start >= end {
+				return nil, fmt.Errorf("start time %v is not before end time %v", timeRange.StartTime, timeRange.EndTime)
+			}
+			times = append(times, v1.AlertmanagerConfigTimeRange{
+				StartTime: timeRange.StartTime,
+				EndTime:   timeRange.EndTime,
+			})
+		}
+
+		for _, check := range []struct {
+			kind   string
+			values []string
+			parse  func(string) (int, error)
+		}{
+			{"weekday", period.Weekdays, parseNamed(weekdays)},
+			{"day of month", period.DaysOfMonth, parseInRange(-31, 31, true)},
+			{"month", period.Months, parseNamed(months)},
+			{"year", period.Years, parseInRange(1, 9999, false)},
+		} {
+			if err := validateTimeRanges(check.kind, check.values, check.parse); err != nil {
+				return nil, err
+			}
+		}
+		if period.Location != "" {
+			if _, err := time.LoadLocation(period.Location); err != nil {
+				return nil, fmt.Errorf("invalid location %v", period.Location)
+			}
+		}
+
+		result = append(result, v1.AlertmanagerConfigTimePeriod{
+			Times:       times,
+			Weekdays:    period.Weekdays,
+			DaysOfMonth: period.DaysOfMonth,
+			Months:      period.Months,
+			Years:       period.Years,
+			Location:    period.Location,
+		})
+	}
+	return result, nil
+}
+
+// Minutes of the day, 24:00 ends a range at midnight
+func parseTimeOfDay(value string) (int, error) {
+	match := timeOfDayRegex.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid time %v", value)
+	}
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	if minutes > 59 || hours*60+minutes > 24*60 {
+		return 0, fmt.Errorf("invalid time %v", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// Single values or inclusive ranges, ranges of negative and positive values are not ordered
+func validateTimeRanges(kind string, values []string, parse func(string) (int, error)) error {
+	for _, value := range values {
+		bounds := strings.Split(value, ":")
+		if len(bounds) > 2 {
+			return fmt.Errorf("invalid %v range %v", kind, value)
+		}
+		var parsed []int
+		for _, bound := range bounds {
+			number, err := parse(strings.TrimSpace(bound))
+			if err != nil {
+				return fmt.Errorf("invalid %v %v", kind, value)
+			}
+			parsed = append(parsed, number)
+		}
+		if len(parsed) == 2 && (parsed[0] < 0) == (parsed[1] < 0) && parsed[0] > parsed[1] {
+			return fmt.Errorf("invalid %v range %v", kind, value)
+		}
+	}
+	return nil
+}
+
+func parseNamed(names map[string]int) func(string) (int, error) {
+	return func(value string) (int, error) {
+		if number, ok := names[strings.ToLower(value)]; ok {
+			return number, nil
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, err
+		}
+		for _, known := range names {
+			if known == number {
+				return number, nil
+			}
+		}
+		return 0, fmt.Errorf("out of range")
+	}
+}
+
+func parseInRange(min int, max int, nonZero bool) func(string) (int, error) {
+	return func(value string) (int, error) {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, err
+		}
+		if number < min || number > max || (nonZero && number == 0) {
+			return 0, fmt.Errorf("out of range")
+		}
+		return number, nil
+	}
+}
+
+type alertRelabelConfig struct {
+	SourceLabels []string `json:"source_labels,omitempty"`
+	Separator    string   `json:"separator,omitempty"`
+	TargetLabel  string   `json:"target_label,omitempty"`
+	Regex        string   `json:"regex,omitempty"`
+	Replacement  string   `json:"replacement,omitempty"`
+	Action       string   `json:"action,omitempty"`
+}
+
+func hasAlertRelabelConfigs(indexes []v1.RepositoryIndex) bool {
+	for _, index := range indexes {
+		if index.Config != nil && index.Config.Alertmanager != nil && len(index.Config.Alertmanager.AlertRelabelConfigs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Alert relabel configs of an index in the format of Prometheus. An invalid config would prevent
+// Prometheus from reloading its configuration. The configs only apply to the alerts of the index
+// other than its dead man's switch: these are marked with the scope label and every config
+// requires the label. A keep drops the marked alerts it doesn't match.
+func getAlertRelabelConfigs(indexId string, configs []prometheusv1.RelabelConfig) ([]alertRelabelConfig, error) {
+	result := []alertRelabelConfig{
+		{SourceLabels: []string{PrometheusRuleIdentifierKey}, Regex: regexp.QuoteMeta(indexId), TargetLabel: alertRelabelScopeLabel, Replacement: "in", Action: "replace"},
+		{SourceLabels: []string{"alertname"}, Regex: "DeadMansSwitch", TargetLabel: alertRelabelScopeLabel, Replacement: "", Action: "replace"},
+	}
+	for i, config := range configs {
+		action := strings.ToLower(config.Action)
+		if action == "" {
+			action = "replace"
+		}
+		if !alertRelabelActions[action] {
+			return nil, fmt.Errorf("alert relabel config %v: invalid action %v, only replace, keep and drop are limited to the alerts of the index", i, config.Action)
+		}
+		if config.Regex != "" {
+			// Prometheus anchors relabel regexes
+			if _, err := regexp.Compile(fmt.Sprintf("^(?:%v)$", config.Regex)); err != nil {
+				return nil, fmt.Errorf("alert relabel config %v: invalid regex: %v", i, err)
+			}
+		}
+		if action == "replace" && config.TargetLabel == "" {
+			return nil, fmt.Errorf("alert relabel config %v: %v requires a target label", i, action)
+		}
+		if config.TargetLabel == PrometheusRuleIdentifierKey || config.TargetLabel == alertRelabelScopeLabel {
+			return nil, fmt.Errorf("alert relabel config %v: the %v label can't be relabeled", i, config.TargetLabel)
+		}
+
+		// The scope label is prepended to the source labels
+		separator := config.Separator
+		if separator == "" {
+			separator = ";"
+		}
+		regex := config.Regex
+		if regex == "" {
+			regex = "(.*)"
+		}
+		sourceLabels := []string{alertRelabelScopeLabel}
+		scopedRegex := fmt.Sprintf("in(?:%v)", regex)
+		if len(config.SourceLabels) > 0 {
+			scopedRegex = fmt.Sprintf("in%v(?:%v)", regexp.QuoteMeta(separator), regex)
+		}
+		for _, label := range config.SourceLabels {
+			sourceLabels = append(sourceLabels, string(label))
+		}
+
+		switch action {
+		case "replace":
+			replacement := config.Replacement
+			if replacement == "" {
+				replacement = "$1"
+			}
+			result = append(result, alertRelabelConfig{
+				SourceLabels: sourceLabels,
+				Separator:    config.Separator,
+				TargetLabel:  config.TargetLabel,
+				Regex:        scopedRegex,
+				Replacement:  replacement,
+				Action:       action,
+			})
+		case "drop":
+			result = append(result, alertRelabelConfig{
+				SourceLabels: sourceLabels,
+				Separator:    config.Separator,
+				Regex:        scopedRegex,
+				Action:       action,
+			})
+		case "keep":
+			result = append(result,
+				alertRelabelConfig{SourceLabels: sourceLabels, Separator: config.Separator, Regex: scopedRegex, TargetLabel: alertRelabelScopeLabel, Replacement: "kept", Action: "replace"},
+				alertRelabelConfig{SourceLabels: []string{alertRelabelScopeLabel}, Regex: "in", Action: "drop"},
+				alertRelabelConfig{SourceLabels: []string{alertRelabelScopeLabel}, Regex: "kept", TargetLabel: alertRelabelScopeLabel, Replacement: "in", Action: "replace"},
+			)
+		}
+	}
+	return append(result, alertRelabelConfig{Regex: alertRelabelScopeLabel, Action: "labeldrop"}), nil
+}
+
+// Relabel configs of all indexes in their order, invalid configs of an index are reported and skipped
+func (r *Reconciler) getAlertRelabelConfig(indexes []v1.RepositoryIndex) ([]byte, error) {
+	var configs []alertRelabelConfig
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Alertmanager == nil || len(index.Config.Alertmanager.AlertRelabelConfigs) == 0 {
+			continue
+		}
+		indexConfigs, err := getAlertRelabelConfigs(index.Id, index.Config.Alertmanager.AlertRelabelConfigs)
+		if err != nil {
+			r.logger.Error(err, "invalid alert relabel configs", "index", index.Id)
+			r.addConfigurationError(index.Id, v1.ErrorStageValidate, err)
+			continue
+		}
+		configs = append(configs, indexConfigs...)
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	return yaml.Marshal(configs)
+}
@@ -0,0 +1,331 @@
+package configuration
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func buildRoutingIndex(config *v1.AlertmanagerIndex) *v1.RepositoryIndex {
+	return &v1.RepositoryIndex{
+		Id: "index-1",
+		Config: &v1.RepositoryConfig{
+			Alertmanager: config,
+		},
+	}
+}
+
+func TestAlertmanagerRouting_GetIndexRouting(t *testing.T) {
+	RegisterTestingT(t)
+
+	receivers := map[string]string{
+		indexDefaultReceiver: defaultReceiver,
+		"pagerduty":          "index-1-pagerduty",
+	}
+
+	routing, err := getIndexRouting(buildRoutingIndex(&v1.AlertmanagerIndex{}), receivers)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(routing).To(BeNil())
+
+	routing, err = getIndexRouting(buildRoutingIndex(&v1.AlertmanagerIndex{
+		Routes: []v1.AlertmanagerIndexRoute{
+			{
+				Receiver:          "pagerduty",
+				Matchers:          []string{`severity=~"critical|high"`},
+				GroupBy:           []string{"alertname", "namespace"},
+				GroupWait:         "30s",
+				MuteTimeIntervals: []string{"maintenance"},
+				Routes: []v1.AlertmanagerIndexRoute{
+					{Matchers: []string{"team = payments"}, RepeatInterval: "1h"},
+				},
+			},
+		},
+		InhibitRules: []v1.AlertmanagerIndexInhibitRule{
+			{
+				SourceMatchers: []string{`severity="critical"`},
+				TargetMatchers: []string{`severity="warning"`},
+				Equal:          []string{"alertname"},
+			},
+		},
+		MuteTimeIntervals: []v1.AlertmanagerTimeInterval{
+			{
+				Name: "maintenance",
+				TimeIntervals: []v1.AlertmanagerTimePeriod{
+					{
+						Times:    []v1.AlertmanagerTimeRange{{StartTime: "22:00", EndTime: "24:00"}},
+						Weekdays: []string{"saturday", "sunday"},
+						Location: "Europe/Dublin",
+					},
+				},
+			},
+		},
+	}), receivers)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Only the alerts of the index are routed and inhibited
+	Expect(routing.route.Receiver).To(Equal(defaultReceiver))
+	Expect(routing.route.Matchers).To(Equal([]string{`observability="index-1"`}))
+	Expect(routing.route.Routes).To(HaveLen(1))
+	Expect(routing.route.Routes[0].Receiver).To(Equal("index-1-pagerduty"))
+	Expect(routing.route.Routes[0].MuteTimeIntervals).To(Equal([]string{"index-1-maintenance"}))
+	Expect(routing.route.Routes[0].Routes[0].Receiver).To(BeEmpty())
+	Expect(routing.route.Routes[0].Routes[0].RepeatInterval).To(Equal("1h"))
+	Expect(routing.inhibitRules).To(Equal([]v1.AlertmanagerConfigInhibitRule{
+		{
+			SourceMatchers: []string{`observability="index-1"`, `severity="critical"`},
+			TargetMatchers: []string{`observability="index-1"`, `severity="warning"`},
+			Equal:          []string{"alertname"},
+		},
+	}))
+	Expect(routing.timeIntervals).To(HaveLen(1))
+	Expect(routing.timeIntervals[0].Name).To(Equal("index-1-maintenance"))
+	Expect(routing.timeIntervals[0].TimeIntervals[0].Times[0].StartTime).To(Equal("22:00"))
+}
+
+func TestAlertmanagerRouting_GetIndexRoutingErrors(t *testing.T) {
+	RegisterTestingT(t)
+
+	receivers := map[string]string{indexDefaultReceiver: defaultReceiver}
+	for _, config := range []v1.AlertmanagerIndex{
+		{Routes: []v1.AlertmanagerIndexRoute{{Receiver: "pagerduty"}}},
+		{Routes: []v1.AlertmanagerIndexRoute{{Matchers: []string{"severity"}}}},
+		{Routes: []v1.AlertmanagerIndexRoute{{Matchers: []string{`severity=~"(critical"`}}}},
+		{Routes: []v1.AlertmanagerIndexRoute{{GroupWait: "30 seconds"}}},
+		{Routes: []v1.AlertmanagerIndexRoute{{GroupBy: []string{"team-name"}}}},
+		{Routes: []v1.AlertmanagerIndexRoute{{ActiveTimeIntervals: []string{"business-hours"}}}},
+		{Routes: []v1.AlertmanagerIndexRoute{{Routes: []v1.AlertmanagerIndexRoute{{Receiver: "slack"}}}}},
+		{InhibitRules: []v1.AlertmanagerIndexInhibitRule{{SourceMatchers: []string{`severity="critical"`}}}},
+		{MuteTimeIntervals: []v1.AlertmanagerTimeInterval{{Name: "empty"}}},
+		{MuteTimeIntervals: []v1.AlertmanagerTimeInterval{
+			{Name: "night", TimeIntervals: []v1.AlertmanagerTimePeriod{{Weekdays: []string{"monday"}}}},
+			{Name: "night", TimeIntervals: []v1.AlertmanagerTimePeriod{{Weekdays: []string{"tuesday"}}}},
+		}},
+	} {
+		config := config
+		_, err := getIndexRouting(buildRoutingIndex(&config), receivers)
+		Expect(err).To(HaveOccurred())
+	}
+}
+
+func TestAlertmanagerRouting_GetTimePeriods(t *testing.T) {
+	RegisterTestingT(t)
+
+	valid := []v1.AlertmanagerTimePeriod{
+		{Times: []v1.AlertmanagerTimeRange{{StartTime: "00:00", EndTime: "09:00"}}},
+		{Weekdays: []string{"monday:friday", "SUNDAY"}},
+		{DaysOfMonth: []string{"1:5", "-3:-1", "1:-1"}},
+		{Months: []string{"january:march", "12"}},
+		{Years: []string{"2026:2027"}},
+		{Location: "America/New_York"},
+	}
+	for _, period := range valid {
+		_, err := getTimePeriods([]v1.AlertmanagerTimePeriod{period})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	invalid := []v1.AlertmanagerTimePeriod{
+		{Times: []v1.AlertmanagerTimeRange{{StartTime: "17:00", EndTime: "09:00"}}},
+		{Times: []v1.AlertmanagerTimeRange{{StartTime: "9:00", EndTime: "17:00"}}},
+		{Times: []v1.AlertmanagerTimeRange{{StartTime: "09:00", EndTime: "24:30"}}},
+		{Weekdays: []string{"friday:monday"}},
+		{Weekdays: []string{"saturday:sunday"}},
+		{Weekdays: []string{"someday"}},
+		{DaysOfMonth: []string{"0"}},
+		{DaysOfMonth: []string{"1:32"}},
+		{Months: []string{"13"}},
+		{Years: []string{"2027:2026"}},
+		{Location: "Mars/Olympus_Mons"},
+	}
+	for _, period := range invalid {
+		_, err := getTimePeriods([]v1.AlertmanagerTimePeriod{period})
+		Expect(err).To(HaveOccurred(), "%+v", period)
+	}
+}
+
+// Applies relabel configs to the labels of an alert the way Prometheus does for the actions
+// the operator generates, returns nil when the alert is dropped
+func relabelAlert(configs []alertRelabelConfig, labels map[string]string) map[string]string {
+	result := map[string]string{}
+	for name, value := range labels {
+		result[name] = value
+	}
+	for _, config := range configs {
+		separator := config.Separator
+		if separator == "" {
+			separator = ";"
+		}
+		regex := config.Regex
+		if regex == "" {
+			regex = "(.*)"
+		}
+		pattern := regexp.MustCompile(fmt.Sprintf("^(?:%v)$", regex))
+		var values []string
+		for _, label := range config.SourceLabels {
+			values = append(values, result[label])
+		}
+		value := strings.Join(values, separator)
+		switch config.Action {
+		case "replace":
+			match := pattern.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+			replacement := config.Replacement
+			if replacement == "" {
+				replacement = "$1"
+			}
+			target := string(pattern.ExpandString(nil, replacement, value, match))
+			if target == "" {
+				delete(result, config.TargetLabel)
+			} else {
+				result[config.TargetLabel] = target
+			}
+		case "drop":
+			if pattern.MatchString(value) {
+				return nil
+			}
+		case "keep":
+			if !pattern.MatchString(value) {
+				return nil
+			}
+		case "labeldrop":
+			for name := range result {
+				if pattern.MatchString(name) {
+					delete(result, name)
+				}
+			}
+		}
+	}
+	return result
+}
+
+func TestAlertmanagerRouting_GetAlertRelabelConfigs(t *testing.T) {
+	RegisterTestingT(t)
+
+	configs, err := getAlertRelabelConfigs("index-1", []prometheusv1.RelabelConfig{
+		{SourceLabels: []prometheusv1.LabelName{"severity"}, Regex: "critical|warning", Action: "keep"},
+		{SourceLabels: []prometheusv1.LabelName{"namespace"}, Regex: "team-(.*)", TargetLabel: "team", Replacement: "$1"},
+		{SourceLabels: []prometheusv1.LabelName{"alertname"}, Regex: "Watchdog", Action: "drop"},
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	// The configs only apply to the alerts of the index
+	Expect(relabelAlert(configs, map[string]string{
+		"observability": "index-1", "alertname": "HighLatency", "severity": "critical", "namespace": "team-payments",
+	})).To(Equal(map[string]string{
+		"observability": "index-1", "alertname": "HighLatency", "severity": "critical", "namespace": "team-payments", "team": "payments",
+	}))
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-1", "alertname": "HighLatency", "severity": "info"})).To(BeNil())
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-1", "alertname": "Watchdog", "severity": "critical"})).To(BeNil())
+	Expect(relabelAlert(configs, map[string]string{
+		"observability": "index-2", "alertname": "Watchdog", "severity": "info", "namespace": "team-payments",
+	})).To(Equal(map[string]string{
+		"observability": "index-2", "alertname": "Watchdog", "severity": "info", "namespace": "team-payments",
+	}))
+	Expect(relabelAlert(configs, map[string]string{"alertname": "KubePodCrashLooping", "severity": "info"})).
+		To(Equal(map[string]string{"alertname": "KubePodCrashLooping", "severity": "info"}))
+	// The dead man's switch of the index is kept
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-1", "alertname": "DeadMansSwitch", "severity": "none"})).
+		To(Equal(map[string]string{"observability": "index-1", "alertname": "DeadMansSwitch", "severity": "none"}))
+
+	for _, config := range []prometheusv1.RelabelConfig{
+		{Action: "rename"},
+		{Action: "labeldrop", Regex: "prometheus_replica"},
+		{Action: "hashmod", TargetLabel: "shard", Modulus: 2},
+		{Action: "drop", Regex: "(unbalanced"},
+		{Action: "replace", Regex: "(.*)"},
+		{Action: "replace", TargetLabel: "observability", Replacement: "index-2"},
+		{Action: "replace", TargetLabel: alertRelabelScopeLabel, Replacement: "in"},
+	} {
+		_, err = getAlertRelabelConfigs("index-1", []prometheusv1.RelabelConfig{config})
+		Expect(err).To(HaveOccurred(), "%+v", config)
+	}
+
+	Expect(hasAlertRelabelConfigs([]v1.RepositoryIndex{*buildRoutingIndex(&v1.AlertmanagerIndex{})})).To(BeFalse())
+	Expect(hasAlertRelabelConfigs([]v1.RepositoryIndex{*buildRoutingIndex(&v1.AlertmanagerIndex{
+		AlertRelabelConfigs: []prometheusv1.RelabelConfig{{Action: "drop", Regex: "Watchdog"}},
+	})})).To(BeTrue())
+}
+
+func TestAlertmanagerRouting_GetAlertRelabelConfig(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Invalid configs of an index are reported and the other indexes are kept
+	r := &Reconciler{logger: logr.Discard()}
+	valid := *buildRoutingIndex(&v1.AlertmanagerIndex{
+		AlertRelabelConfigs: []prometheusv1.RelabelConfig{
+			{SourceLabels: []prometheusv1.LabelName{"severity"}, Regex: "critical", Action: "keep"},
+		},
+	})
+	other := *buildRoutingIndex(&v1.AlertmanagerIndex{
+		AlertRelabelConfigs: []prometheusv1.RelabelConfig{
+			{SourceLabels: []prometheusv1.LabelName{"alertname"}, Regex: "Watchdog", Action: "drop"},
+		},
+	})
+	other.Id = "index-2"
+	invalid := *buildRoutingIndex(&v1.AlertmanagerIndex{
+		AlertRelabelConfigs: []prometheusv1.RelabelConfig{{Action: "rename"}},
+	})
+	invalid.Id = "index-3"
+
+	config, err := r.getAlertRelabelConfig([]v1.RepositoryIndex{valid, other, invalid})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(r.configurationErrors).To(HaveLen(1))
+	Expect(r.configurationErrors[0].Index).To(Equal("index-3"))
+
+	var configs []alertRelabelConfig
+	Expect(yaml.Unmarshal(config, &configs)).To(Succeed())
+	// The keep of the first index doesn't drop the alerts of the second one
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-1", "severity": "warning"})).To(BeNil())
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-1", "severity": "critical"})).
+		To(Equal(map[string]string{"observability": "index-1", "severity": "critical"}))
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-2", "severity": "warning"})).
+		To(Equal(map[string]string{"observability": "index-2", "severity": "warning"}))
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-2", "alertname": "Watchdog"})).To(BeNil())
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-1", "alertname": "DeadMansSwitch"})).
+		To(Equal(map[string]string{"observability": "index-1", "alertname": "DeadMansSwitch"}))
+	Expect(relabelAlert(configs, map[string]string{"observability": "index-2", "alertname": "DeadMansSwitch"})).
+		To(Equal(map[string]string{"observability": "index-2", "alertname": "DeadMansSwitch"}))
+
+	// Indexes without relabel configs add none
+	r = &Reconciler{logger: logr.Discard()}
+	config, err = r.getAlertRelabelConfig([]v1.RepositoryIndex{*buildRoutingIndex(&v1.AlertmanagerIndex{})})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(config).To(BeNil())
+}
+
+func TestAlertmanagerRouting_GetAlertForwarderRouting(t *testing.T) {
+	RegisterTestingT(t)
+
+	tr := true
+	cr := &v1.Observability{}
+	cr.Namespace = "observability"
+	receiver, route, err := getAlertForwarderRouting(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(receiver).To(BeNil())
+	Expect(route).To(BeNil())
+
+	cr.Spec.AlertForwarder = &v1.AlertForwarderSpec{
+		Enabled:  &tr,
+		Url:      "https://events.example.com/alerts",
+		Matchers: []string{`severity=~"critical|warning"`},
+	}
+	receiver, route, err = getAlertForwarderRouting(cr)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(receiver.WebhookConfigs[0].Url).To(Equal("http://alert-forwarder.observability.svc:9096/webhook"))
+	// The alerts are routed to the indexes as well
+	Expect(route.Receiver).To(Equal(receiver.Name))
+	Expect(route.Continue).To(BeTrue())
+	Expect(route.Matchers).To(Equal([]string{`severity=~"critical|warning"`}))
+
+	cr.Spec.AlertForwarder.Matchers = []string{"severity"}
+	_, _, err = getAlertForwarderRouting(cr)
+	Expect(err).To(HaveOccurred())
+}
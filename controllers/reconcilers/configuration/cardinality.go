@@ -0,0 +1,199 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ghodss/yaml"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type CardinalityReport struct {
+	GeneratedAt string `json:"generatedAt"`
+	HeadSeries  uint64 `json:"headSeries"`
+	// Across all namespaces, as reported by the TSDB status API
+	TopMetrics []CardinalityEntry     `json:"topMetrics,omitempty"`
+	TopLabels  []CardinalityEntry     `json:"topLabels,omitempty"`
+	Namespaces []NamespaceCardinality `json:"namespaces,omitempty"`
+}
+
+type NamespaceCardinality struct {
+	Namespace  string             `json:"namespace"`
+	Series     uint64             `json:"series"`
+	TopMetrics []CardinalityEntry `json:"topMetrics,omitempty"`
+}
+
+// Number of series of a metric or number of values of a label
+type CardinalityEntry struct {
+	Name  string `json:"name"`
+	Count uint64 `json:"count"`
+}
+
+// Query the TSDB of the managed Prometheus for the metrics with the most series per namespace and
+// record them in the cardinality ConfigMap. The queries touch all series of the head block, so the
+// report is only refreshed once its generatedAt is older than the interval.
+func (r *Reconciler) reconcileCardinalityReport(ctx context.Context, cr *v1.Observability) error {
+	if !cr.CardinalityAnalysisEnabled() {
+		return r.deleteCardinalityReport(ctx, cr)
+	}
+
+	generatedAt, err := r.getCardinalityReportTime(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if time.Since(generatedAt) < model.GetCardinalityInterval(cr) {
+		return nil
+	}
+
+	prometheusUrl := model.GetPrometheusUpstreamUrl(cr)
+	status, err := utils.FetchTSDBStatus(r.httpClient, prometheusUrl)
+	if err != nil {
+		return err
+	}
+
+	topK := model.GetCardinalityTopK(cr)
+	namespaceSeries, err := utils.QueryVector(r.httpClient, prometheusUrl, `count by (namespace) ({namespace!=""})`)
+	if err != nil {
+		return err
+	}
+	metricSeries, err := utils.QueryVector(r.httpClient, prometheusUrl, fmt.Sprintf(`topk by (namespace) (%v, count by (namespace, __name__) ({namespace!=""}))`, topK))
+	if err != nil {
+		return err
+	}
+
+	report := getCardinalityReport(status, namespaceSeries, metricSeries, topK)
+	report.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	content, err := yaml.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	configMap := model.GetCardinalityReportConfigMap(cr)
+	_, err = utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Data = map[string]string{
+			model.CardinalityReportKey: string(content),
+		}
+		return nil
+	})
+	return err
+}
+
+// Zero without a report or if it can't be parsed, so that it is written again
+func (r *Reconciler) getCardinalityReportTime(ctx context.Context, cr *v1.Observability) (time.Time, error) {
+	configMap := model.GetCardinalityReportConfigMap(cr)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+	if errors.IsNotFound(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	report := &CardinalityReport{}
+	err = yaml.Unmarshal([]byte(configMap.Data[model.CardinalityReportKey]), report)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	generatedAt, err := time.Parse(time.RFC3339, report.GeneratedAt)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return generatedAt, nil
+}
+
+func (r *Reconciler) deleteCardinalityReport(ctx context.Context, cr *v1.Observability) error {
+	err := r.client.Delete(ctx, model.GetCardinalityReportConfigMap(cr))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Alert on sudden growth of the head series, removed when the analysis is disabled
+func (r *Reconciler) reconcileCardinalityAlert(ctx context.Context, cr *v1.Observability) error {
+	rule := model.GetCardinalityGrowthRule(cr)
+	if !cr.CardinalityAnalysisEnabled() {
+		err := r.client.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, getRuleSelectorLabels(cr))
+		rule.Spec.Groups = []prometheusv1.RuleGroup{
+			model.GetCardinalityGrowthRuleGroup(cr),
+		}
+		injectClusterLabels(cr, rule)
+		return nil
+	})
+	return err
+}
+
+// Combine the TSDB statistics and the per namespace query results. Namespaces are ordered by their
+// number of series, metrics within a namespace are limited to the top k.
+func getCardinalityReport(status *utils.TSDBStatus, namespaceSeries []utils.VectorSample, metricSeries []utils.VectorSample, topK int) *CardinalityReport {
+	report := &CardinalityReport{
+		HeadSeries: status.HeadStats.NumSeries,
+	}
+	for _, stat := range status.SeriesCountByMetricName {
+		report.TopMetrics = append(report.TopMetrics, CardinalityEntry{Name: stat.Name, Count: stat.Value})
+	}
+	for _, stat := range status.LabelValueCountByLabelName {
+		report.TopLabels = append(report.TopLabels, CardinalityEntry{Name: stat.Name, Count: stat.Value})
+	}
+
+	namespaces := map[string]*NamespaceCardinality{}
+	for _, sample := range namespaceSeries {
+		namespace := sample.Metric["namespace"]
+		namespaces[namespace] = &NamespaceCardinality{
+			Namespace: namespace,
+			Series:    uint64(sample.Value),
+		}
+	}
+	for _, sample := range metricSeries {
+		namespace, ok := namespaces[sample.Metric["namespace"]]
+		if !ok {
+			continue
+		}
+		namespace.TopMetrics = append(namespace.TopMetrics, CardinalityEntry{
+			Name:  sample.Metric["__name__"],
+			Count: uint64(sample.Value),
+		})
+	}
+
+	for _, namespace := range namespaces {
+		sortCardinalityEntries(namespace.TopMetrics)
+		if len(namespace.TopMetrics) > topK {
+			namespace.TopMetrics = namespace.TopMetrics[:topK]
+		}
+		report.Namespaces = append(report.Namespaces, *namespace)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		if report.Namespaces[i].Series != report.Namespaces[j].Series {
+			return report.Namespaces[i].Series > report.Namespaces[j].Series
+		}
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+
+	return report
+}
+
+func sortCardinalityEntries(entries []CardinalityEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
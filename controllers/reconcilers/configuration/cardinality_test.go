@@ -0,0 +1,157 @@
+package configuration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCardinality_GetCardinalityReport(t *testing.T) {
+	status := &utils.TSDBStatus{
+		SeriesCountByMetricName: []utils.TSDBStat{
+			{Name: "http_requests_total", Value: 300},
+		},
+		LabelValueCountByLabelName: []utils.TSDBStat{
+			{Name: "pod", Value: 40},
+		},
+	}
+	status.HeadStats.NumSeries = 1000
+
+	type args struct {
+		namespaceSeries []utils.VectorSample
+		metricSeries    []utils.VectorSample
+		topK            int
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *CardinalityReport
+	}{
+		{
+			name: "namespaces ordered by series with their top metrics",
+			args: args{
+				namespaceSeries: []utils.VectorSample{
+					{Metric: map[string]string{"namespace": "small"}, Value: 10},
+					{Metric: map[string]string{"namespace": "large"}, Value: 500},
+				},
+				metricSeries: []utils.VectorSample{
+					{Metric: map[string]string{"namespace": "large", "__name__": "up"}, Value: 20},
+					{Metric: map[string]string{"namespace": "large", "__name__": "http_requests_total"}, Value: 300},
+					{Metric: map[string]string{"namespace": "small", "__name__": "up"}, Value: 10},
+				},
+				topK: 10,
+			},
+			want: &CardinalityReport{
+				HeadSeries: 1000,
+				TopMetrics: []CardinalityEntry{{Name: "http_requests_total", Count: 300}},
+				TopLabels:  []CardinalityEntry{{Name: "pod", Count: 40}},
+				Namespaces: []NamespaceCardinality{
+					{
+						Namespace: "large",
+						Series:    500,
+						TopMetrics: []CardinalityEntry{
+							{Name: "http_requests_total", Count: 300},
+							{Name: "up", Count: 20},
+						},
+					},
+					{
+						Namespace: "small",
+						Series:    10,
+						TopMetrics: []CardinalityEntry{
+							{Name: "up", Count: 10},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "metrics of a namespace are limited to the top k",
+			args: args{
+				namespaceSeries: []utils.VectorSample{
+					{Metric: map[string]string{"namespace": "large"}, Value: 500},
+				},
+				metricSeries: []utils.VectorSample{
+					{Metric: map[string]string{"namespace": "large", "__name__": "b"}, Value: 20},
+					{Metric: map[string]string{"namespace": "large", "__name__": "a"}, Value: 20},
+					{Metric: map[string]string{"namespace": "large", "__name__": "c"}, Value: 100},
+				},
+				topK: 2,
+			},
+			want: &CardinalityReport{
+				HeadSeries: 1000,
+				TopMetrics: []CardinalityEntry{{Name: "http_requests_total", Count: 300}},
+				TopLabels:  []CardinalityEntry{{Name: "pod", Count: 40}},
+				Namespaces: []NamespaceCardinality{
+					{
+						Namespace: "large",
+						Series:    500,
+						TopMetrics: []CardinalityEntry{
+							{Name: "c", Count: 100},
+							{Name: "a", Count: 20},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getCardinalityReport(status, tt.args.namespaceSeries, tt.args.metricSeries, tt.args.topK)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestCardinality_ReconcileCardinalityReport(t *testing.T) {
+	RegisterTestingT(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path == "/api/v1/status/tsdb" {
+			_, _ = w.Write([]byte(`{"status":"success","data":{"headStats":{"numSeries":10}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	Expect(kv1.AddToScheme(scheme)).To(Succeed())
+	enabled := true
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.ExternalPrometheus = &v1.ExternalPrometheus{Name: "prometheus", Url: server.URL}
+		obsCR.Spec.CardinalityAnalysis = &v1.CardinalityAnalysis{Enabled: &enabled}
+	})
+	c := testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())
+
+	Expect((&Reconciler{client: c, httpClient: server.Client()}).reconcileCardinalityReport(context.TODO(), cr)).To(Succeed())
+	Expect(requests).To(Equal(3))
+	configMap := model.GetCardinalityReportConfigMap(cr)
+	Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+	Expect(configMap.Data[model.CardinalityReportKey]).To(ContainSubstring("headSeries: 10"))
+
+	// Reconcilers don't outlive a sync, the report is the time of the last run
+	Expect((&Reconciler{client: c, httpClient: server.Client()}).reconcileCardinalityReport(context.TODO(), cr)).To(Succeed())
+	Expect(requests).To(Equal(3))
+
+	configMap.Data[model.CardinalityReportKey] = "generatedAt: " + time.Now().Add(-48*time.Hour).UTC().Format(time.RFC3339)
+	Expect(c.Update(context.TODO(), configMap)).To(Succeed())
+	Expect((&Reconciler{client: c, httpClient: server.Client()}).reconcileCardinalityReport(context.TODO(), cr)).To(Succeed())
+	Expect(requests).To(Equal(6))
+}
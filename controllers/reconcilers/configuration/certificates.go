@@ -0,0 +1,181 @@
+package configuration
+
+import (
+	"context"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	errors2 "github.com/pkg/errors"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Certificates of the services issued by cert-manager. Certificates of components that are disabled,
+// or all of them without an issuer, are removed. Their secrets are kept until they are deleted so that
+// the service CA can issue them again.
+func (r *Reconciler) reconcileServingCertificates(ctx context.Context, cr *v1.Observability) error {
+	requested := map[string]bool{}
+	if cr.CertManagerEnabled() {
+		for _, certificate := range model.GetServingCertificates(cr) {
+			resource := model.GetCertManagerCertificate(certificate)
+			_, err := utils.Apply(ctx, r.client, resource, func() error {
+				resource.SetLabels(map[string]string{
+					"managed-by": "observability-operator",
+				})
+				resource.Object["spec"] = model.GetCertManagerCertificateSpec(cr, certificate)
+				return nil
+			})
+			if meta.IsNoMatchError(err) {
+				return errors2.New("cert-manager is not installed")
+			}
+			if err != nil {
+				return err
+			}
+			requested[certificate.Namespace+"/"+certificate.SecretName] = true
+		}
+	}
+
+	for _, namespace := range []string{cr.Namespace, cr.GetPrometheusOperatorNamespace()} {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(model.CertManagerCertificateGroupVersionKind.GroupVersion().WithKind("CertificateList"))
+		err := r.client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels{"managed-by": "observability-operator"})
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			certificate := &list.Items[i]
+			if requested[certificate.GetNamespace()+"/"+certificate.GetName()] {
+				continue
+			}
+			err = r.client.Delete(ctx, certificate)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Track the expiry of the certificates of the managed components, export them as metrics for the
+// bundled alert and report the ones that expire soon in the status. Missing secrets, e.g. serving
+// certificates that are not issued yet, are skipped.
+func (r *Reconciler) reconcileCertificateExpiry(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	expiries, err := r.getCertificateExpiries(ctx, cr)
+	if err != nil {
+		return err
+	}
+	metrics.SetCertificateExpiryMetrics(expiries)
+
+	s.ExpiringCertificates = getExpiringCertificates(expiries, time.Now())
+
+	rule := model.GetCertificateExpiryRule(cr)
+	_, err = utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, getRuleSelectorLabels(cr))
+		rule.Spec.Groups = []prometheusv1.RuleGroup{
+			model.GetCertificateExpiryRuleGroup(),
+		}
+		injectClusterLabels(cr, rule)
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) getCertificateExpiries(ctx context.Context, cr *v1.Observability) ([]v1.CertificateExpiry, error) {
+	var result []v1.CertificateExpiry
+	add := func(source model.CertificateSource, data []byte) {
+		expiry, err := model.GetCertificateExpiry(data)
+		if err != nil {
+			r.logger.Error(err, "invalid certificate", "namespace", source.Namespace, "name", source.Name)
+			return
+		}
+		result = append(result, v1.CertificateExpiry{
+			Kind:      source.Kind,
+			Namespace: source.Namespace,
+			Name:      source.Name,
+			Expires:   expiry.Unix(),
+		})
+	}
+
+	for _, source := range model.GetCertificateSecrets(cr) {
+		secret := &core.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: source.Namespace, Name: source.Name}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if data := secret.Data[source.Key]; len(data) > 0 {
+			add(source, data)
+		}
+	}
+
+	if source := model.GetProxyCABundle(r.clusterProxy); source != nil {
+		configMap := &core.ConfigMap{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: source.Namespace, Name: source.Name}, configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if data := configMap.Data[source.Key]; data != "" {
+			add(*source, []byte(data))
+		}
+	}
+
+	for _, route := range model.GetCertificateRoutes(cr) {
+		err := r.client.Get(ctx, client.ObjectKeyFromObject(route), route)
+		if err != nil {
+			if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, err
+		}
+		if certificate := getRouteCertificate(route); certificate != "" {
+			add(model.CertificateSource{
+				Kind:      model.CertificateKindRoute,
+				Namespace: route.Namespace,
+				Name:      route.Name,
+			}, []byte(certificate))
+		}
+	}
+	return result, nil
+}
+
+// Routes without a certificate use the default certificate of the router
+func getRouteCertificate(route *routev1.Route) string {
+	if route.Spec.TLS == nil {
+		return ""
+	}
+	return route.Spec.TLS.Certificate
+}
+
+func getExpiringCertificates(expiries []v1.CertificateExpiry, now time.Time) []v1.CertificateExpiry {
+	var result []v1.CertificateExpiry
+	for _, expiry := range expiries {
+		if time.Unix(expiry.Expires, 0).Sub(now) < model.CertificateExpiryWarningPeriod {
+			result = append(result, expiry)
+		}
+	}
+	return result
+}
+
+func (r *Reconciler) deleteCertificateExpiry(ctx context.Context, cr *v1.Observability) error {
+	metrics.SetCertificateExpiryMetrics(nil)
+	err := r.client.Delete(ctx, model.GetCertificateExpiryRule(cr))
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+)
+
+func TestCertificates_ValidRule(t *testing.T) {
+	RegisterTestingT(t)
+
+	rule := model.GetCertificateExpiryRule(&v1.Observability{})
+	rule.Spec.Groups = []prometheusv1.RuleGroup{model.GetCertificateExpiryRuleGroup()}
+	Expect(validateRule(rule)).To(Succeed())
+}
+
+func TestCertificates_GetExpiringCertificates(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Now()
+	soon := v1.CertificateExpiry{Kind: model.CertificateKindServingCert, Name: "soon", Expires: now.Add(7 * 24 * time.Hour).Unix()}
+	later := v1.CertificateExpiry{Kind: model.CertificateKindRoute, Name: "later", Expires: now.Add(90 * 24 * time.Hour).Unix()}
+	Expect(getExpiringCertificates([]v1.CertificateExpiry{soon, later}, now)).To(Equal([]v1.CertificateExpiry{soon}))
+	Expect(getExpiringCertificates([]v1.CertificateExpiry{later}, now)).To(BeEmpty())
+}
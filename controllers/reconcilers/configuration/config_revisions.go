@@ -0,0 +1,199 @@
+package configuration
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Documents fetched during a sync by tag and url: index files, rules, dashboards, pod monitors,
+// federation and remote write configs
+type configBundle map[string][]byte
+
+func getConfigBundleKey(path string, tag string) string {
+	return fmt.Sprintf("%v@%v", tag, path)
+}
+
+// Keep a fetched document for the revision of the sync
+func (r *Reconciler) recordFetchedResource(path string, tag string, content []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fetchedResources == nil {
+		r.fetchedResources = configBundle{}
+	}
+	r.fetchedResources[getConfigBundleKey(path, tag)] = content
+}
+
+// Documents of a pinned revision are served instead of fetching them. The tag of a repository
+// may have changed since the revision was recorded, the document at the same url is used then.
+func (r *Reconciler) getPinnedResource(path string, tag string) ([]byte, error) {
+	if content, ok := r.pinnedResources[getConfigBundleKey(path, tag)]; ok {
+		return content, nil
+	}
+	for key, content := range r.pinnedResources {
+		if strings.SplitN(key, "@", 2)[1] == path {
+			return content, nil
+		}
+	}
+	return nil, fmt.Errorf("%v is not part of the pinned configuration revision", path)
+}
+
+// Read the documents of the pinned revision, nothing is fetched from the repositories while pinned
+func (r *Reconciler) loadPinnedConfigRevision(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	if !cr.ConfigRevisionPinned() {
+		return nil
+	}
+
+	revision := *cr.Spec.PinnedConfigRevision
+	secret := model.GetConfigRevisionSecret(cr, revision)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("configuration revision %v not found", revision)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.pinnedResources, err = decodeConfigBundle(secret.Data[model.ConfigRevisionKey])
+	if err != nil {
+		return err
+	}
+
+	s.ConfigRevision = revision
+	s.ConfigRevisionPinned = true
+	return nil
+}
+
+// Record the documents of a successful sync as a new revision unless they match the latest
+// revision, and remove the revisions beyond the history limit
+func (r *Reconciler) saveConfigRevision(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	if cr.ConfigRevisionPinned() || len(r.fetchedResources) == 0 {
+		return nil
+	}
+
+	content, err := encodeConfigBundle(r.fetchedResources)
+	if err != nil {
+		return err
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(content))
+
+	list := &kv1.SecretList{}
+	err = r.client.List(ctx, list, client.InNamespace(cr.Namespace), client.HasLabels{model.ConfigRevisionLabel})
+	if err != nil {
+		return err
+	}
+
+	secrets := map[int]*kv1.Secret{}
+	var revisions []int
+	for i, secret := range list.Items {
+		revision, err := strconv.Atoi(secret.Labels[model.ConfigRevisionLabel])
+		if err != nil {
+			continue
+		}
+		secrets[revision] = &list.Items[i]
+		revisions = append(revisions, revision)
+	}
+	sort.Ints(revisions)
+
+	latest := 0
+	if len(revisions) > 0 {
+		latest = revisions[len(revisions)-1]
+	}
+
+	if latest == 0 || secrets[latest].Annotations[model.ConfigRevisionHashAnnotation] != hash {
+		latest++
+		secret := model.GetConfigRevisionSecret(cr, latest)
+		secret.Annotations = map[string]string{
+			model.ConfigRevisionHashAnnotation: hash,
+		}
+		secret.Data = map[string][]byte{
+			model.ConfigRevisionKey: content,
+		}
+		err = r.client.Create(ctx, secret)
+		if err != nil {
+			return err
+		}
+		r.logger.Info("recorded configuration revision", "revision", latest)
+		revisions = append(revisions, latest)
+	}
+
+	for _, revision := range getExpiredConfigRevisions(revisions, cr.GetConfigRevisionHistoryLimit()) {
+		err = r.client.Delete(ctx, secrets[revision])
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	s.ConfigRevision = latest
+	s.ConfigRevisionPinned = false
+	return nil
+}
+
+// Pinning, unpinning or changing the pinned revision applies it without waiting for the resync
+func isConfigRevisionPinChanged(cr *v1.Observability) bool {
+	if !cr.ConfigRevisionPinned() {
+		return cr.Status.ConfigRevisionPinned
+	}
+	return !cr.Status.ConfigRevisionPinned || cr.Status.ConfigRevision != *cr.Spec.PinnedConfigRevision
+}
+
+// Revisions are sorted, the newest ones are kept
+func getExpiredConfigRevisions(revisions []int, limit int) []int {
+	if len(revisions) <= limit {
+		return nil
+	}
+	return revisions[:len(revisions)-limit]
+}
+
+// Dashboards make up most of a revision, compressing keeps it within the size limit of a secret
+func encodeConfigBundle(bundle configBundle) ([]byte, error) {
+	content, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err = writer.Write(content)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeConfigBundle(content []byte) (configBundle, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := configBundle{}
+	err = json.Unmarshal(decompressed, &bundle)
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
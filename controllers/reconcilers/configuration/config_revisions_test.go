@@ -0,0 +1,131 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestConfigRevisions_EncodeConfigBundle(t *testing.T) {
+	bundle := configBundle{
+		getConfigBundleKey("https://example.com/repo/resources/index.json", "v1"): []byte(`{"id":"test"}`),
+		getConfigBundleKey("https://example.com/repo/resources/rules.yaml", "v1"): []byte("groups: []"),
+	}
+
+	RegisterTestingT(t)
+	content, err := encodeConfigBundle(bundle)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Unchanged configurations must not create new revisions
+	again, err := encodeConfigBundle(bundle)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(again).To(Equal(content))
+
+	result, err := decodeConfigBundle(content)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(Equal(bundle))
+}
+
+func TestConfigRevisions_GetPinnedResource(t *testing.T) {
+	r := &Reconciler{
+		pinnedResources: configBundle{
+			getConfigBundleKey("https://example.com/repo/resources/rules.yaml", "v1"): []byte("v1"),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		tag     string
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "recorded document",
+			path: "https://example.com/repo/resources/rules.yaml",
+			tag:  "v1",
+			want: []byte("v1"),
+		},
+		{
+			name: "tag changed since the revision was recorded",
+			path: "https://example.com/repo/resources/rules.yaml",
+			tag:  "v2",
+			want: []byte("v1"),
+		},
+		{
+			name:    "document not part of the revision",
+			path:    "https://example.com/repo/resources/dashboard.yaml",
+			tag:     "v1",
+			wantErr: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.getPinnedResource(tt.path, tt.tag)
+			if tt.wantErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestConfigRevisions_GetExpiredConfigRevisions(t *testing.T) {
+	RegisterTestingT(t)
+	Expect(getExpiredConfigRevisions([]int{1, 2, 3}, 5)).To(BeNil())
+	Expect(getExpiredConfigRevisions([]int{1, 2, 3, 4, 5, 6, 7}, 5)).To(Equal([]int{1, 2}))
+}
+
+func TestConfigRevisions_IsConfigRevisionPinChanged(t *testing.T) {
+	tests := []struct {
+		name   string
+		pinned *int
+		status v1.ObservabilityStatus
+		want   bool
+	}{
+		{
+			name:   "not pinned",
+			status: v1.ObservabilityStatus{ConfigRevision: 3},
+			want:   false,
+		},
+		{
+			name:   "pinned",
+			pinned: &([]int{2})[0],
+			status: v1.ObservabilityStatus{ConfigRevision: 3},
+			want:   true,
+		},
+		{
+			name:   "pinned revision applied",
+			pinned: &([]int{2})[0],
+			status: v1.ObservabilityStatus{ConfigRevision: 2, ConfigRevisionPinned: true},
+			want:   false,
+		},
+		{
+			name:   "pinned to another revision",
+			pinned: &([]int{1})[0],
+			status: v1.ObservabilityStatus{ConfigRevision: 2, ConfigRevisionPinned: true},
+			want:   true,
+		},
+		{
+			name:   "unpinned",
+			status: v1.ObservabilityStatus{ConfigRevision: 2, ConfigRevisionPinned: true},
+			want:   true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &v1.Observability{
+				Spec:   v1.ObservabilitySpec{PinnedConfigRevision: tt.pinned},
+				Status: tt.status,
+			}
+			Expect(isConfigRevisionPinChanged(cr)).To(Equal(tt.want))
+		})
+	}
+}
@@ -2,18 +2,18 @@ package configuration
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	v14 "k8s.io/api/networking/v1"
 
 	"github.com/go-logr/logr"
 	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	configv1 "github.com/openshift/api/config/v1"
 	errors2 "github.com/pkg/errors"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
@@ -21,10 +21,18 @@ import (
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
 	token2 "github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v4/controllers/tracing"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -37,22 +45,61 @@ const (
 	DefaultChannel              = "resources"
 )
 
+// Reasons of the events emitted on the Observability CR
+const (
+	EventReasonPrometheusUpgraded          = "PrometheusUpgraded"
+	EventReasonScrapeConfigRotated         = "ScrapeConfigRotated"
+	EventReasonIndexFetchFailed            = "IndexFetchFailed"
+	EventReasonRemoteWriteSkipped          = "RemoteWriteSkipped"
+	EventReasonStorageExpanded             = "StorageExpanded"
+	EventReasonStorageExpansionUnsupported = "StorageExpansionUnsupported"
+	EventReasonGatewayFailover             = "GatewayFailover"
+	EventReasonRuleRejected                = "RuleRejected"
+	EventReasonResourceAdopted             = "ResourceAdopted"
+	EventReasonPrometheusUpgradeStarted    = "PrometheusUpgradeStarted"
+	EventReasonPrometheusTrafficSwitched   = "PrometheusTrafficSwitched"
+)
+
 type Reconciler struct {
-	client     client.Client
-	logger     logr.Logger
-	httpClient *http.Client
+	client              client.Client
+	logger              logr.Logger
+	httpClient          *http.Client
+	recorder            record.EventRecorder
+	configurationErrors []v1.ConfigurationError
+	// Prometheus storage size derived from the ingestion rate, nil if unknown
+	storageRecommendation *resource.Quantity
+	// Cluster-wide proxy for outbound connections, nil if there is none
+	clusterProxy *configv1.Proxy
+	// Image of the operator that the alert forwarder and the query log exporter run, detected on first use
+	operatorImage string
+	// Gateways of the Observatorium instances with a secondary gateway
+	activeGateways []v1.ActiveGateway
+	// Resources requested by the current sync, pruned from the next sync once they are no longer requested
+	managedResources []v1.ManagedResource
+	// Blue/green upgrade of Prometheus in progress, nil if there is none
+	prometheusUpgrade *v1.PrometheusUpgradeStatus
+	// Documents fetched by the current sync, recorded as a configuration revision
+	fetchedResources configBundle
+	// Documents of the pinned configuration revision, nil if the configuration isn't pinned
+	pinnedResources configBundle
+	// Registry client for pinned images, created on first use
+	imageRegistry imageRegistry
+	// Caches of the previous syncs
+	state *SyncState
+	// Fetch policy of the current sync, the zero value doesn't limit the requests
+	fetchLimits model.FetchLimits
+	// Guards the state that the resource sync workers share
+	mu sync.Mutex
 }
 
-func NewReconciler(client client.Client, logger logr.Logger) reconcilers.ObservabilityReconciler {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	httpClient := &http.Client{Transport: tr}
-
+// The HTTP client is replaced by one for the CR on every reconcile
+func NewReconciler(client client.Client, logger logr.Logger, recorder record.EventRecorder, state *SyncState) reconcilers.ObservabilityReconciler {
 	return &Reconciler{
 		client:     client,
 		logger:     logger,
-		httpClient: httpClient,
+		httpClient: &http.Client{},
+		recorder:   recorder,
+		state:      state,
 	}
 }
 
@@ -107,6 +154,11 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 					return v1.ResultFailed, err
 				}
 			}
+
+			err = r.deleteQueryDatasource(ctx, cr)
+			if err != nil {
+				return v1.ResultFailed, err
+			}
 		}
 
 		prometheusRuleList := &prometheusv1.PrometheusRuleList{}
@@ -138,6 +190,52 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		}
 	}
 
+	// Delete the built-in rules and dashboards, the rules are in the Prometheus namespace
+	err = r.deleteLibrary(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Delete the rules and dashboard of the service level objectives
+	err = r.deleteServiceLevelObjectives(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Delete the certificate expiry alert
+	err = r.deleteCertificateExpiry(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Revert the scrape budgets applied to the monitors of the namespaces
+	err = r.deleteScrapeBudgets(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Delete the infrastructure exporters, their cluster role is not namespaced
+	err = r.deleteInfrastructureExporters(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	// Delete the event exporter and its dashboard
+	err = r.deleteEventExporter(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = r.deleteAlertForwarder(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	err = r.deleteThanosCompactor(ctx, cr)
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
 	// Delete Promtail daemonsets
 	daemonsetList := &v13.DaemonSetList{}
 	err = r.client.List(ctx, daemonsetList, opts)
@@ -195,6 +293,38 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 	return v1.ResultSuccess, nil
 }
 
+// Emit an event on the Observability CR, so that kubectl describe shows what the operator did
+func (r *Reconciler) recordEvent(cr *v1.Observability, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.Eventf(cr, eventType, reason, messageFmt, args...)
+}
+
+// Detect the image of the operator once, a component that runs it can do without when its image is overridden
+func (r *Reconciler) ensureOperatorImage(ctx context.Context, cr *v1.Observability, component v1.ImageComponent) error {
+	if r.operatorImage != "" {
+		return nil
+	}
+	image, err := utils.GetOperatorImage(ctx, r.client)
+	if err != nil && cr.Spec.ImageOverrides[component] == "" {
+		return err
+	}
+	r.operatorImage = image
+	return nil
+}
+
+// Keep track of an error that only affects a single index
+func (r *Reconciler) addConfigurationError(index string, stage v1.ConfigurationErrorStage, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configurationErrors = append(r.configurationErrors, v1.ConfigurationError{
+		Index:   index,
+		Stage:   stage,
+		Message: err.Error(),
+	})
+}
+
 func (r *Reconciler) stampConfigSource(ctx context.Context, index *v1.RepositoryIndex) error {
 	if index.Source != nil {
 		// Update source secret
@@ -211,11 +341,20 @@ func (r *Reconciler) stampConfigSource(ctx context.Context, index *v1.Repository
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
-	log := r.logger.WithValues("observability", cr.Name)
+	log := r.logger
 	if cr.Spec.ConfigurationSelector == nil && !cr.ExternalSyncDisabled() {
 		log.Info("warning: configuration label selector not present, dynamic configuration will be skipped")
 		return v1.ResultSuccess, nil
 	}
+	r.fetchLimits = model.GetFetchLimits(cr)
+
+	// Honours FIPS mode until the sync reads the trusted CA bundle, e.g. for the checks before the
+	// resync is due
+	r.httpClient = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: model.GetTLSConfig(cr, nil),
+		},
+	}
 
 	// Force a sync if one of the tokens has expired
 	overrideLastSync := false
@@ -242,6 +381,56 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		}
 	}
 
+	// Record all writes instead of applying them when running in dry run mode
+	// Once dry run mode is turned off again, force a sync to apply the reviewed changes
+	var dryRunClient *utils.DryRunClient
+	if cr.DryRunEnabled() {
+		dryRunClient = utils.NewDryRunClient(r.client)
+		r.client = dryRunClient
+	} else {
+		reportFound, err := r.deleteDryRunReport(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, errors2.Wrap(err, "error deleting dry run report")
+		}
+		if reportFound {
+			overrideLastSync = true
+		}
+	}
+
+	// Apply a pinned configuration revision right away, and the current configuration once unpinned
+	if isConfigRevisionPinChanged(cr) {
+		overrideLastSync = true
+	}
+
+	// Resyncs requested with the annotation, e.g. by the kubectl plugin
+	if cr.ResyncRequested() {
+		log.Info("resync requested", "request", cr.Annotations[v1.ResyncAnnotation])
+		s.ResyncRequest = cr.Annotations[v1.ResyncAnnotation]
+		overrideLastSync = true
+	}
+
+	// Syncs requested by the previous sync or by the drift watches, e.g. to resume Prometheus after a
+	// storage expansion or to repair a managed resource that someone else changed
+	if r.getSyncState().takeSyncRequest(cr) {
+		overrideLastSync = true
+	}
+
+	// Keep syncing until a blue/green upgrade of Prometheus is finished
+	if s.PrometheusUpgrade != nil {
+		overrideLastSync = true
+	}
+
+	// Remote write is checked on every reconcile, a switched gateway is applied right away
+	if observatoria := r.getSyncState().getFailoverObservatoria(cr); len(observatoria) > 0 && !cr.ObservatoriumDisabled() {
+		switched, err := r.reconcileGatewayFailover(cr, observatoria, s)
+		if err != nil {
+			log.Error(err, "error checking remote write of the active gateways")
+		}
+		if switched {
+			overrideLastSync = true
+		}
+	}
+
 	// Then check if the next sync is due
 	// Override if any of the tokens needs a refresh
 	if cr.Status.LastSynced != 0 && !overrideLastSync {
@@ -260,8 +449,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 	log.Info("operator resync window elapsed",
 		"configured resync period", cr.Spec.ResyncPeriod)
 
+	// Outbound connections of the managed components go through the cluster-wide proxy
+	r.clusterProxy, err = utils.GetClusterProxy(ctx, r.client)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error fetching cluster proxy")
+	}
+
+	err = r.reconcileTrustedCABundle(ctx, cr)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling trusted ca bundle")
+	}
+
+	// Errors of individual indexes are collected during the sync and reported in the status
+	defer func() {
+		s.ConfigurationErrors = r.configurationErrors
+	}()
+
+	err = r.loadPinnedConfigRevision(ctx, cr, s)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error loading pinned configuration revision")
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cr.Spec.ConfigurationSelector)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "invalid configuration selector")
+	}
 	opts := &client.ListOptions{
-		LabelSelector: labels.SelectorFromSet(cr.Spec.ConfigurationSelector.MatchLabels),
+		LabelSelector: selector,
 	}
 
 	// Get all configuration secret sets as well
@@ -312,11 +530,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 
 	// Collect index files
 	var indexes []v1.RepositoryIndex
-	for _, repoInfo := range repos {
-		indexBytes, err := r.readIndexFile(&repoInfo)
+	for name, repoInfo := range repos {
+		spanCtx, span := tracing.Tracer().Start(ctx, "FetchIndex", trace.WithAttributes(attribute.String("repository", repoInfo.Repository)))
+		indexBytes, err := r.readIndexFile(spanCtx, &repoInfo)
+		tracing.End(span, err)
 		if err != nil {
 			metrics.IncreaseFailedConfigurationSyncsMetric()
 			log.Error(err, "failed to fetch configuration repository index file")
+			r.addConfigurationError(name, v1.ErrorStageFetch, err)
+			r.recordEvent(cr, v12.EventTypeWarning, EventReasonIndexFetchFailed,
+				"Failed to fetch index file of repository %v: %v", repoInfo.Repository, err)
 			return v1.ResultFailed, err
 		}
 
@@ -325,8 +548,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		if err != nil {
 			metrics.IncreaseFailedConfigurationSyncsMetric()
 			log.Error(err, "failed to unmarshal configuration repository index")
+			r.addConfigurationError(name, v1.ErrorStageParse, err)
 			return v1.ResultFailed, err
 		}
+		// The index is still applied, the Prometheus Operator reports the failure on the Prometheus CR
+		err = model.ValidateIndexSelectors(&index)
+		if err != nil {
+			log.Error(err, "invalid selectors in configuration repository index")
+			r.addConfigurationError(index.Id, v1.ErrorStageValidate, err)
+		}
 		index.BaseUrl = fmt.Sprintf("%s/%s", repoInfo.Repository, repoInfo.Channel)
 		index.Tag = repoInfo.Tag
 		index.AccessToken = repoInfo.AccessToken
@@ -334,6 +564,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		indexes = append(indexes, index)
 	}
 
+	// Changes of indexes with a staged rollout are held back until this cluster is due
+	// A pinned configuration revision is applied as recorded
+	if !cr.ConfigRevisionPinned() {
+		indexes, err = r.reconcileIndexRollouts(ctx, cr, indexes, s)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling index rollouts")
+		}
+	}
+
 	// Delete unrequested token secrets
 	err = r.deleteUnrequestedCredentialSecrets(ctx, cr, indexes)
 	if err != nil {
@@ -342,9 +582,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 	}
 
 	for _, index := range indexes {
-		err = token2.ReconcileObservatoria(r.logger, ctx, r.client, cr, &index)
+		// The token secrets are kept when fetching a token fails
+		if index.Config != nil {
+			for _, observatorium := range index.Config.Observatoria {
+				r.trackResource(ManagedKindSecret, model.GetTokenSecret(cr, token2.GetObservatoriumTokenSecretName(&observatorium)), index.Id)
+			}
+		}
+
+		err = token2.ReconcileObservatoria(r.logger.WithValues("index", index.Id), ctx, r.client, r.httpClient, cr, &index)
 		if err != nil {
 			log.Error(err, "error configuring observatorium")
+			r.addConfigurationError(index.Id, v1.ErrorStageApply, err)
 			continue
 		}
 		r.stampConfigSource(ctx, &index)
@@ -362,6 +610,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested network policies")
 	}
 
+	// Gateway failover, the active gateways are kept if Prometheus can't be reached
+	if !cr.ObservatoriumDisabled() {
+		observatoria := getFailoverObservatoria(indexes)
+		r.getSyncState().setFailoverObservatoria(cr, observatoria)
+		_, err = r.reconcileGatewayFailover(cr, observatoria, s)
+		if err != nil {
+			log.Error(err, "error checking remote write of the active gateways")
+		}
+	} else {
+		r.getSyncState().setFailoverObservatoria(cr, nil)
+	}
+
+	// Digests of pinned images, before the first resource with an image is reconciled
+	err = r.reconcileImageDigests(ctx, cr, indexes, s)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling image digests")
+	}
+
 	if !cr.ObservatoriumDisabled() {
 		err = r.reconcileTokenRefresher(ctx, cr, indexes)
 		if err != nil {
@@ -370,13 +637,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		}
 	}
 
+	// Readiness of the token refreshers, the deployments are removed when observatorium is disabled
+	err = r.reconcileTokenRefresherStatus(ctx, cr, s)
+	if err != nil {
+		log.Error(err, "error reading token refresher status")
+	}
+
 	// Alertmanager configuration
 	// When external sync is disabled, allow to create secret
-	if !cr.ExternalSyncDisabled() {
+	if !cr.ExternalSyncDisabled() && cr.AlertmanagerEnabled() {
 		overrideConfigSecret, _ := cr.HasAlertmanagerConfigSecret()
 
 		// Only create the config secret if the user has not overridden it via CR
 		if !overrideConfigSecret {
+			r.reconcileAlertmanagerCalendars(ctx, cr, s)
 			err = r.reconcileAlertmanagerSecret(ctx, cr, indexes)
 			if err != nil {
 				metrics.IncreaseFailedConfigurationSyncsMetric()
@@ -385,39 +659,136 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		}
 	}
 
-	// Prometheus additional scrape configs
-	patterns, err := r.fetchFederationConfigs(cr, indexes)
+	err = r.reconcileAlertForwarder(ctx, cr)
 	if err != nil {
 		metrics.IncreaseFailedConfigurationSyncsMetric()
-		return v1.ResultFailed, errors2.Wrap(err, "error fetching federation config")
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling alert forwarder")
 	}
-	err = r.createAdditionalScrapeConfigSecret(cr, ctx, patterns)
+
+	// Downsampling of the blocks in object storage for a long retention
+	err = r.reconcileThanosCompactor(ctx, cr)
 	if err != nil {
 		metrics.IncreaseFailedConfigurationSyncsMetric()
-		return v1.ResultFailed, err
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling thanos compactor")
 	}
-	//blackbox exporter
-	hash, err := r.createBlackBoxConfig(cr, ctx)
+
+	// Infrastructure exporters, scraped by the additional scrape configs
+	err = r.reconcileInfrastructureExporters(ctx, cr)
 	if err != nil {
 		metrics.IncreaseFailedConfigurationSyncsMetric()
-		return v1.ResultFailed, err
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling infrastructure exporters")
 	}
-	// Alertmanager CR
-	err = r.reconcileAlertmanager(ctx, cr, indexes)
+
+	err = r.reconcileEventExporter(ctx, cr, indexes)
 	if err != nil {
 		metrics.IncreaseFailedConfigurationSyncsMetric()
-		return v1.ResultFailed, errors2.Wrap(err, "error reconciling alertmanager")
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling event exporter")
 	}
 
-	// Prometheus CR
-	err = r.reconcilePrometheus(ctx, cr, indexes, hash)
+	// Prometheus additional scrape configs, removed with Prometheus when it is disabled
+	var hash string
+	if cr.PrometheusEnabled() {
+		patterns, err := r.fetchFederationConfigs(ctx, cr, indexes)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error fetching federation config")
+		}
+		err = r.createStaticTargetsConfigMap(cr, ctx, indexes)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling static targets")
+		}
+		err = r.createAdditionalScrapeConfigSecret(cr, ctx, indexes, patterns)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, err
+		}
+		//blackbox exporter
+		hash, err = r.createBlackBoxConfig(cr, ctx)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, err
+		}
+	}
+	// Serving certificates of cert-manager, before the components that serve them
+	err = r.reconcileServingCertificates(ctx, cr)
 	if err != nil {
 		metrics.IncreaseFailedConfigurationSyncsMetric()
-		return v1.ResultFailed, errors2.Wrap(err, "error reconciling prometheus")
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling serving certificates")
+	}
+
+	// Alertmanager CR
+	if cr.AlertmanagerEnabled() {
+		err = r.reconcileAlertmanager(ctx, cr, indexes)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling alertmanager")
+		}
+	}
+
+	// Storage size recommendation, failing to calculate it does not fail the sync
+	if cr.PrometheusAutosizingEnabled() {
+		err = r.reconcileStorageRecommendation(cr, s)
+		if err != nil {
+			log.Error(err, "error calculating prometheus storage recommendation")
+		}
+	}
+
+	// Resource recommendations, the metrics API is optional
+	err = r.reconcileResourceRecommendations(ctx, cr, indexes, s)
+	if err != nil && !meta.IsNoMatchError(err) {
+		log.Error(err, "error calculating resource recommendations")
+	}
+
+	// Expiry of the managed certificates, failing to read them does not fail the sync
+	err = r.reconcileCertificateExpiry(ctx, cr, s)
+	if err != nil {
+		log.Error(err, "error checking certificate expiry")
+	}
+
+	// Scrape budgets of the namespaces, failing to apply or check them does not fail the sync
+	err = r.reconcileScrapeBudgets(ctx, cr, indexes, s)
+	if err != nil {
+		log.Error(err, "error reconciling scrape budgets")
+	}
+
+	// Health of the scrape targets, failing to query Prometheus does not fail the sync
+	err = r.reconcileScrapeTargetHealth(cr, s)
+	if err != nil {
+		log.Error(err, "error summarizing scrape target health")
+	}
+
+	// Prometheus CR
+	if cr.PrometheusEnabled() {
+		err = r.reconcilePrometheusUpgradeStart(ctx, cr, s)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error starting prometheus upgrade")
+		}
+
+		spanCtx, span := tracing.Tracer().Start(ctx, "ReconcilePrometheus")
+		err = r.reconcilePrometheus(spanCtx, cr, indexes, hash)
+		tracing.End(span, err)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling prometheus")
+		}
+
+		err = r.reconcilePrometheusUpgradeProgress(ctx, cr, s)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error switching prometheus upgrade")
+		}
+	} else if cr.ExternalPrometheusEnabled() {
+		err = r.checkExternalPrometheus(ctx, cr, indexes)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error checking external prometheus")
+		}
 	}
 
 	// Grafana CR
-	if !cr.DescopedModeEnabled() {
+	if cr.GrafanaEnabled() {
 		err = r.reconcileGrafanaCr(ctx, cr, indexes)
 		if err != nil {
 			metrics.IncreaseFailedConfigurationSyncsMetric()
@@ -425,9 +796,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		}
 	}
 
+	// Long-term query datasources, removed when Grafana or Observatorium are disabled
+	if !cr.DescopedModeEnabled() {
+		err = r.reconcileQueryDatasource(ctx, cr, indexes)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling query datasource")
+		}
+	}
+
 	// Manage monitoring resources
 	if !cr.ExternalSyncDisabled() {
-		if !cr.DescopedModeEnabled() {
+		if cr.GrafanaEnabled() {
 			dashboards := getUniqueDashboards(indexes)
 			err = r.deleteUnrequestedDashboards(cr, ctx, dashboards)
 			if err != nil {
@@ -435,11 +815,27 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 				return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested dashboards")
 			}
 
-			err = r.createRequestedDashboards(cr, ctx, dashboards)
+			// Permissions of the folders of the dashboards
+			err = r.reconcileGrafanaFolders(ctx, cr, indexes)
+			if err != nil {
+				metrics.IncreaseFailedConfigurationSyncsMetric()
+				return v1.ResultFailed, errors2.Wrap(err, "error reconciling grafana folders")
+			}
+
+			spanCtx, span := tracing.Tracer().Start(ctx, "SyncDashboards", trace.WithAttributes(attribute.Int("dashboards", len(dashboards))))
+			err = r.createRequestedDashboards(cr, spanCtx, dashboards)
+			tracing.End(span, err)
 			if err != nil {
 				metrics.IncreaseFailedConfigurationSyncsMetric()
 				return v1.ResultFailed, errors2.Wrap(err, "error creating requested dashboards")
 			}
+		} else if !cr.DescopedModeEnabled() {
+			// The dashboards of the indexes are removed together with Grafana
+			err = r.deleteUnrequestedDashboards(cr, ctx, nil)
+			if err != nil && !meta.IsNoMatchError(err) {
+				metrics.IncreaseFailedConfigurationSyncsMetric()
+				return v1.ResultFailed, errors2.Wrap(err, "error deleting dashboards")
+			}
 		}
 
 		// Manage prometheus rules
@@ -450,12 +846,40 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 			return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested prometheus rules")
 		}
 
-		err = r.createRequestedRules(cr, ctx, rules)
+		spanCtx, span := tracing.Tracer().Start(ctx, "SyncRules", trace.WithAttributes(attribute.Int("rules", len(rules))))
+		appliedRules, err := r.createRequestedRules(cr, spanCtx, rules)
+		tracing.End(span, err)
 		if err != nil {
 			metrics.IncreaseFailedConfigurationSyncsMetric()
 			return v1.ResultFailed, errors2.Wrap(err, "error creating requested prometheus rules")
 		}
 
+		// Recording rules of the aggregations, evaluated in the cluster before remote write
+		err = r.reconcileAggregationRules(ctx, cr, indexes)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling aggregation rules")
+		}
+
+		// Recording rules of the metric classes, the aggregates are remote written instead of the series
+		err = r.reconcileMetricClassRules(ctx, cr)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error reconciling metric class rules")
+		}
+
+		// Centrally evaluated rules, a failed push is retried on the next sync
+		err = r.reconcileObservatoriumRules(ctx, cr, indexes, appliedRules, s)
+		if err != nil {
+			log.Error(err, "error pushing rules to observatorium")
+		}
+
+		// Log rules of the Loki ruler, a failed sync is retried on the next sync
+		err = r.reconcileLogRules(ctx, cr, indexes, s)
+		if err != nil {
+			log.Error(err, "error syncing log rules to observatorium")
+		}
+
 		// Manage pod monitors
 		monitors := getUniquePodMonitors(indexes)
 		err = r.deleteUnrequestedPodMonitors(cr, ctx, monitors)
@@ -464,7 +888,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 			return v1.ResultFailed, errors2.Wrap(err, "error deleting unrequested pod monitors")
 		}
 
-		err = r.createRequestedPodMonitors(cr, ctx, monitors)
+		spanCtx, span = tracing.Tracer().Start(ctx, "SyncPodMonitors", trace.WithAttributes(attribute.Int("podMonitors", len(monitors))))
+		err = r.createRequestedPodMonitors(cr, spanCtx, monitors)
+		tracing.End(span, err)
 		if err != nil {
 			metrics.IncreaseFailedConfigurationSyncsMetric()
 			return v1.ResultFailed, errors2.Wrap(err, "error creating requested pod monitors")
@@ -477,6 +903,48 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		}
 	}
 
+	// Built-in rules and dashboards
+	err = r.reconcileLibrary(ctx, cr, indexes)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling built-in rules and dashboards")
+	}
+
+	// Dashboards of the tenants, copied to the namespace of Grafana
+	err = r.reconcileTenantDashboards(ctx, cr, indexes)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling tenant dashboards")
+	}
+
+	// Burn rate rules and dashboard of the service level objectives
+	err = r.reconcileServiceLevelObjectives(ctx, cr, indexes)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling service level objectives")
+	}
+
+	// Cardinality growth alert and report, failing to query Prometheus does not fail the sync
+	err = r.reconcileCardinalityAlert(ctx, cr)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling cardinality growth alert")
+	}
+
+	err = r.reconcileCardinalityReport(ctx, cr)
+	if err != nil {
+		log.Error(err, "error writing cardinality report")
+	}
+
+	// Remote write probe and its alert, failing to query Observatorium fails the probe and not the sync
+	err = r.reconcileRemoteWriteProbeAlert(ctx, cr)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error reconciling remote write probe alert")
+	}
+
+	r.reconcileRemoteWriteProbe(cr, indexes, s)
+
 	// Promtail instances
 	// First cleanup any no longer requested instances
 	err = r.deleteUnrequestedDaemonsets(ctx, cr, indexes)
@@ -494,6 +962,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		}
 	}
 
+	// Remove the resources that are no longer part of any index
+	err = r.pruneManagedResources(ctx, cr, s)
+	if err != nil {
+		metrics.IncreaseFailedConfigurationSyncsMetric()
+		return v1.ResultFailed, errors2.Wrap(err, "error pruning orphaned resources")
+	}
+
+	if dryRunClient != nil {
+		err = r.reconcileDryRunReport(ctx, cr, dryRunClient)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error writing dry run report")
+		}
+		log.Info("dry run complete, pending changes recorded", "changes", len(dryRunClient.GetChanges()))
+	} else {
+		// Only configurations that have been applied can be rolled back to
+		err = r.saveConfigRevision(ctx, cr, s)
+		if err != nil {
+			metrics.IncreaseFailedConfigurationSyncsMetric()
+			return v1.ResultFailed, errors2.Wrap(err, "error saving configuration revision")
+		}
+	}
+
 	// Next status: update timestamp
 	if cr.ExternalSyncDisabled() {
 		s.LastSynced = 0
@@ -550,17 +1041,29 @@ func (r *Reconciler) deleteUnrequestedCredentialSecrets(ctx context.Context, cr
 	return nil
 }
 
-func (r *Reconciler) readIndexFile(repo *v1.RepositoryInfo) ([]byte, error) {
+func (r *Reconciler) readIndexFile(ctx context.Context, repo *v1.RepositoryInfo) (_ []byte, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveFetchDurationMetric(metrics.FetchTypeIndex, time.Since(start))
+		if err != nil {
+			metrics.IncreaseFailedFetchesMetric(metrics.FetchTypeIndex)
+		}
+	}()
+
 	repoUrl, err := url.ParseRequestURI(fmt.Sprintf("%s/%s/index.json", repo.Repository, repo.Channel))
 	if err != nil {
 		return nil, err
 	}
 
+	if r.pinnedResources != nil {
+		return r.getPinnedResource(repoUrl.String(), repo.Tag)
+	}
+
 	if repo.AccessToken == "" {
 		return nil, fmt.Errorf("repository ConfigMap missing required AccessToken")
 	}
 
-	req, err := http.NewRequest(http.MethodGet, repoUrl.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, repoUrl.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -574,35 +1077,42 @@ func (r *Reconciler) readIndexFile(repo *v1.RepositoryInfo) ([]byte, error) {
 		req.URL.RawQuery = q.Encode()
 	}
 
-	resp, err := r.httpClient.Do(req)
+	bytes, statusCode, err := r.doCachedRequest(req, repoUrl.String(), repo.Tag, metrics.FetchTypeIndex)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code when reading index file from %v: %v", req.URL.String(), resp.StatusCode)
-	}
-
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code when reading index file from %v: %v", req.URL.String(), statusCode)
 	}
 
+	r.recordFetchedResource(repoUrl.String(), repo.Tag, bytes)
 	return bytes, nil
 }
 
-func (r *Reconciler) fetchResource(path string, tag string, token string) ([]byte, error) {
+func (r *Reconciler) fetchResource(ctx context.Context, path string, tag string, token string) (_ []byte, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveFetchDurationMetric(metrics.FetchTypeResource, time.Since(start))
+		if err != nil {
+			metrics.IncreaseFailedFetchesMetric(metrics.FetchTypeResource)
+		}
+	}()
+
 	resourceUrl, err := url.ParseRequestURI(path)
 	if err != nil {
 		return nil, errors2.Wrap(err, fmt.Sprintf("error parsing resource url: %s", path))
 	}
 
+	if r.pinnedResources != nil {
+		return r.getPinnedResource(path, tag)
+	}
+
 	if token == "" {
 		return nil, fmt.Errorf("repository ConfigMap missing required AccessToken")
 	}
 
-	req, err := http.NewRequest(http.MethodGet, resourceUrl.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceUrl.String(), nil)
 	if err != nil {
 		return nil, errors2.Wrap(err, "error creating http request")
 	}
@@ -615,21 +1125,16 @@ func (r *Reconciler) fetchResource(path string, tag string, token string) ([]byt
 		req.URL.RawQuery = q.Encode()
 	}
 
-	resp, err := r.httpClient.Do(req)
+	body, statusCode, err := r.doCachedRequest(req, path, tag, metrics.FetchTypeResource)
 	if err != nil {
 		return nil, errors2.Wrap(err, fmt.Sprintf("error fetching resource from %s", path))
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("unexpected status code when resource from %v: %v", req.URL.String(), resp.StatusCode)
-	}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors2.Wrap(err, "error reading response")
+	if statusCode != 200 {
+		return nil, fmt.Errorf("unexpected status code when resource from %v: %v", req.URL.String(), statusCode)
 	}
 
+	r.recordFetchedResource(path, tag, body)
 	return body, nil
 }
 
@@ -0,0 +1,91 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"sort"
+
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Set on the pods of Prometheus, Alertmanager, Grafana and Promtail. Changes of the mounted secrets
+// and config maps change the annotation and roll the pods, e.g. after credentials were rotated.
+const ContentHashAnnotation = "observability-operator/content-hash"
+
+// Observatorium tokens are refreshed all the time and read for every request, restarting for them
+// would only interrupt the shipping of data
+const tokenSecretPurpose = "observatorium-token-secret"
+
+// Hash of the data of the mounted secrets and config maps in the namespace. Mounts that don't
+// exist yet are left out, the hash changes once they are created.
+func (r *Reconciler) getMountedContentHash(ctx context.Context, namespace string, secrets []string, configMaps []string) (string, error) {
+	h := sha256.New()
+
+	for _, name := range sortedUnique(secrets) {
+		secret := &kv1.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if secret.Labels["purpose"] == tokenSecretPurpose {
+			continue
+		}
+		writeContent(h, "secret/"+name, secret.Data)
+	}
+
+	for _, name := range sortedUnique(configMaps) {
+		configMap := &kv1.ConfigMap{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		data := map[string][]byte{}
+		for key, value := range configMap.Data {
+			data[key] = []byte(value)
+		}
+		for key, value := range configMap.BinaryData {
+			data[key] = value
+		}
+		writeContent(h, "configmap/"+name, data)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:16], nil
+}
+
+// Keys are sorted, map order must not change the hash
+func writeContent(h hash.Hash, name string, data map[string][]byte) {
+	var keys []string
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h.Write([]byte(name))
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write(data[key])
+	}
+}
+
+func sortedUnique(names []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, name := range names {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
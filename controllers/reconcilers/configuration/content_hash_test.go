@@ -0,0 +1,47 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	kv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestContentHash_GetMountedContentHash(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+
+	getSecret := func(name string, value string, labels map[string]string) *kv1.Secret {
+		return &kv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "observability", Labels: labels},
+			Data:       map[string][]byte{"key": []byte(value)},
+		}
+	}
+	getHash := func(objects ...client.Object) string {
+		r := &Reconciler{client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+		hash, err := r.getMountedContentHash(context.TODO(), "observability", []string{"proxy", "token"}, []string{"ca"})
+		Expect(err).ToNot(HaveOccurred())
+		return hash
+	}
+
+	RegisterTestingT(t)
+	base := getHash(getSecret("proxy", "a", nil))
+
+	// Rotated secrets roll the pods
+	Expect(getHash(getSecret("proxy", "b", nil))).ToNot(Equal(base))
+
+	// Created mounts roll the pods
+	configMap := &kv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "observability"},
+		Data:       map[string]string{"ca-bundle.crt": "cert"},
+	}
+	Expect(getHash(getSecret("proxy", "a", nil), configMap)).ToNot(Equal(base))
+
+	// Refreshed observatorium tokens are read for every request
+	Expect(getHash(getSecret("proxy", "a", nil), getSecret("token", "t", map[string]string{"purpose": tokenSecretPurpose}))).To(Equal(base))
+}
@@ -0,0 +1,56 @@
+package configuration
+
+import (
+	"context"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Write the changes recorded during a dry run to the report config map
+// The report is written with the real client, everything else was only recorded
+func (r *Reconciler) reconcileDryRunReport(ctx context.Context, cr *v1.Observability, dryRunClient *utils.DryRunClient) error {
+	changes := dryRunClient.GetChanges()
+	if changes == nil {
+		changes = []utils.DryRunChange{}
+	}
+
+	report, err := yaml.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	configMap := model.GetDryRunReportConfigMap(cr)
+	_, err = utils.Apply(ctx, dryRunClient.Client, configMap, func() error {
+		configMap.Data = map[string]string{
+			model.DryRunReportKey: string(report),
+		}
+		return nil
+	})
+
+	return err
+}
+
+// Remove the dry run report once dry run mode is turned off
+// Returns true if a report existed, which means the pending changes still need to be applied
+func (r *Reconciler) deleteDryRunReport(ctx context.Context, cr *v1.Observability) (bool, error) {
+	configMap := model.GetDryRunReportConfigMap(cr)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = r.client.Delete(ctx, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	return true, nil
+}
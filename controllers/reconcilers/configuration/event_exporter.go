@@ -0,0 +1,132 @@
+package configuration
+
+import (
+	"context"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Exporter of the events of all namespaces as metrics and its dashboard
+func (r *Reconciler) reconcileEventExporter(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	if !cr.EventExporterEnabled() {
+		return r.deleteEventExporter(ctx, cr)
+	}
+
+	sa := model.GetEventExporterServiceAccount(cr)
+	_, err := utils.Apply(ctx, r.client, sa, func() error {
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	clusterRole := model.GetEventExporterClusterRole(cr)
+	_, err = utils.Apply(ctx, r.client, clusterRole, func() error {
+		clusterRole.Rules = model.GetEventExporterClusterRoleRules()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	binding := model.GetEventExporterClusterRoleBinding(cr)
+	_, err = utils.Apply(ctx, r.client, binding, func() error {
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole.Name,
+		}
+		binding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: cr.GetPrometheusOperatorNamespace(),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var replicas int32 = 1
+	deployment := model.GetEventExporterDeployment(cr)
+	labels := deployment.Labels
+	_, err = utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Labels = labels
+		deployment.Spec = appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: core.PodSpec{
+					ServiceAccountName: sa.Name,
+					PriorityClassName:  model.ObservabilityPriorityClassName,
+					SecurityContext:    model.GetPodSecurityContext(cr),
+					Affinity:           model.GetArchitectureAffinity(cr, nil, model.GetImageArchitectures(cr, v1.ImageEventExporter, model.EventExporterImage)),
+					Tolerations:        cr.Spec.Tolerations,
+					Containers:         []core.Container{model.GetEventExporterContainer(cr)},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileInfrastructureExporterService(ctx, model.GetEventExporterService(cr), model.EventExporterPort)
+	if err != nil {
+		return err
+	}
+
+	dashboard := model.GetEventExporterDashboard(cr)
+	if !cr.GrafanaEnabled() {
+		err = r.client.Delete(ctx, dashboard)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+		return nil
+	}
+
+	requestedSpec := dashboard.Spec
+	_, err = utils.Apply(ctx, r.client, dashboard, func() error {
+		dashboard.Spec = requestedSpec
+		dashboard.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, model.GetSelectorLabels(model.GetGrafanaDashboardLabelSelectors(cr, indexes)))
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) deleteEventExporter(ctx context.Context, cr *v1.Observability) error {
+	objects := []client.Object{
+		model.GetEventExporterDashboard(cr),
+		model.GetEventExporterDeployment(cr),
+		model.GetEventExporterService(cr),
+		model.GetEventExporterClusterRoleBinding(cr),
+		model.GetEventExporterClusterRole(cr),
+		model.GetEventExporterServiceAccount(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
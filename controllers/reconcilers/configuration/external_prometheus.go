@@ -0,0 +1,67 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const EventReasonExternalPrometheusSelectorMismatch = "ExternalPrometheusSelectorMismatch"
+
+// The external Prometheus must exist. It is never changed, selectors that miss the resources of the
+// indexes are reported instead.
+func (r *Reconciler) checkExternalPrometheus(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	prometheus := model.GetExternalPrometheus(cr)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(prometheus), prometheus)
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("external prometheus %v/%v not found", prometheus.Namespace, prometheus.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range getExternalPrometheusSelectorMismatches(cr, indexes, prometheus) {
+		r.recordEvent(cr, kv1.EventTypeWarning, EventReasonExternalPrometheusSelectorMismatch,
+			"The %v selector of external prometheus %v/%v does not select the %v of the indexes", kind, prometheus.Namespace, prometheus.Name, kind)
+	}
+	return nil
+}
+
+// The resources of the indexes have the labels that the managed Prometheus would select them by
+func getExternalPrometheusSelectorMismatches(cr *v1.Observability, indexes []v1.RepositoryIndex, prometheus *prometheusv1.Prometheus) []string {
+	selectors := []struct {
+		kind     string
+		external *metav1.LabelSelector
+		managed  *metav1.LabelSelector
+	}{
+		{"rules", prometheus.Spec.RuleSelector, model.GetPrometheusRuleLabelSelectors(cr, indexes)},
+		{"pod monitors", prometheus.Spec.PodMonitorSelector, model.GetPrometheusPodMonitorLabelSelectors(cr, indexes)},
+		{"service monitors", prometheus.Spec.ServiceMonitorSelector, model.GetPrometheusServiceMonitorLabelSelectors(cr, indexes)},
+	}
+
+	var result []string
+	for _, s := range selectors {
+		// Without a selector the Prometheus Operator selects nothing
+		if s.external == nil {
+			result = append(result, s.kind)
+			continue
+		}
+		// Only the labels of the resources are known, not the expressions they satisfy
+		if s.managed == nil || len(s.managed.MatchExpressions) > 0 {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(s.external)
+		if err != nil || !selector.Matches(labels.Set(s.managed.MatchLabels)) {
+			result = append(result, s.kind)
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExternalPrometheus_CheckExternalPrometheus(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = prometheusv1.AddToScheme(scheme)
+
+	prometheus := &prometheusv1.Prometheus{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s", Namespace: "monitoring"},
+	}
+	r := &Reconciler{client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(prometheus).Build()}
+
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.ExternalPrometheus = &v1.ExternalPrometheus{Name: "k8s", Namespace: "monitoring"}
+	})
+	Expect(r.checkExternalPrometheus(context.Background(), cr, nil)).To(Succeed())
+
+	cr.Spec.ExternalPrometheus.Namespace = ""
+	err := r.checkExternalPrometheus(context.Background(), cr, nil)
+	Expect(err).To(MatchError("external prometheus test-namespace/k8s not found"))
+}
+
+func TestExternalPrometheus_GetExternalPrometheusSelectorMismatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := buildObservabilityCR(nil)
+	prometheus := &prometheusv1.Prometheus{
+		Spec: prometheusv1.PrometheusSpec{
+			RuleSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "strimzi"}},
+			CommonPrometheusFields: prometheusv1.CommonPrometheusFields{
+				PodMonitorSelector: &metav1.LabelSelector{},
+				ServiceMonitorSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "payments"},
+				},
+			},
+		},
+	}
+
+	// An empty selector selects everything, no selector nothing
+	Expect(getExternalPrometheusSelectorMismatches(cr, nil, prometheus)).To(Equal([]string{"service monitors"}))
+
+	prometheus.Spec.PodMonitorSelector = nil
+	prometheus.Spec.ServiceMonitorSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpExists}},
+	}
+	Expect(getExternalPrometheusSelectorMismatches(cr, nil, prometheus)).To(Equal([]string{"pod monitors"}))
+}
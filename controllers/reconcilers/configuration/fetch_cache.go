@@ -0,0 +1,99 @@
+package configuration
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Validators and content of the last response for a document, sent back with conditional requests.
+// Kept in the sync state, so that the next syncs send them too.
+type cachedFetch struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// Send the request with the validators of the last response for the same path and tag. Not Modified
+// responses return the previous content with status OK, so that the callers handle both the same.
+// Unchanged content is then skipped by the apply of the index resources, it has the same hash.
+func (r *Reconciler) doCachedRequest(req *http.Request, path string, tag string, fetchType string) (_ []byte, statusCode int, err error) {
+	ctx, span := tracing.Tracer().Start(req.Context(), "Fetch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("fetch.type", fetchType),
+		attribute.String("fetch.url", path),
+		attribute.String("fetch.tag", tag),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("http.status_code", statusCode))
+		tracing.End(span, err)
+	}()
+
+	key := getConfigBundleKey(path, tag)
+	state := r.getSyncState()
+
+	state.mu.Lock()
+	cached, ok := state.fetchCache[key]
+	state.mu.Unlock()
+
+	if ok {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	if r.fetchLimits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.fetchLimits.Timeout)
+		defer cancel()
+	}
+	host := req.URL.Host
+	err = r.acquireFetch(ctx, host)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := r.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		r.recordFetchResult(host, nil)
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		r.recordFetchResult(host, resp)
+		metrics.IncreaseNotModifiedFetchesMetric(fetchType)
+		span.SetAttributes(attribute.Bool("fetch.not_modified", true))
+		return cached.body, http.StatusOK, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		r.recordFetchResult(host, resp)
+		return nil, resp.StatusCode, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		r.recordFetchResult(host, nil)
+		return nil, resp.StatusCode, err
+	}
+	r.recordFetchResult(host, resp)
+
+	// Servers without validators are asked for the full document every time
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if etag == "" && lastModified == "" {
+		delete(state.fetchCache, key)
+		return body, resp.StatusCode, nil
+	}
+	state.fetchCache[key] = cachedFetch{etag: etag, lastModified: lastModified, body: body}
+	return body, resp.StatusCode, nil
+}
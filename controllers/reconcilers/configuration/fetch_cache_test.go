@@ -0,0 +1,158 @@
+package configuration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFetchCache_FetchResource(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Rules with an etag, dashboards with a modification time, pod monitors without validators
+	content := map[string]string{"/rules.yaml": "rules-v1", "/dashboard.json": "dashboard-v1", "/monitor.yaml": "monitor-v1"}
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		Expect(req.Header.Get("Authorization")).To(Equal("token test-token"))
+		body := content[req.URL.Path]
+		switch req.URL.Path {
+		case "/rules.yaml":
+			etag := `"` + body + `"`
+			if req.Header.Get("If-None-Match") != "" {
+				conditional = append(conditional, req.URL.Path)
+			}
+			if req.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+		case "/dashboard.json":
+			if req.Header.Get("If-Modified-Since") != "" {
+				conditional = append(conditional, req.URL.Path)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Wed, 01 Jun 2022 10:00:00 GMT")
+		default:
+			if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+				conditional = append(conditional, req.URL.Path)
+			}
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	r := &Reconciler{httpClient: server.Client()}
+	fetch := func(path string) string {
+		body, err := r.fetchResource(context.Background(), server.URL+path, "v1", "test-token")
+		Expect(err).ToNot(HaveOccurred())
+		return string(body)
+	}
+
+	Expect(fetch("/rules.yaml")).To(Equal("rules-v1"))
+	Expect(fetch("/dashboard.json")).To(Equal("dashboard-v1"))
+	Expect(fetch("/monitor.yaml")).To(Equal("monitor-v1"))
+	Expect(conditional).To(BeEmpty())
+
+	// Not Modified responses return the previous content
+	Expect(fetch("/rules.yaml")).To(Equal("rules-v1"))
+	Expect(fetch("/dashboard.json")).To(Equal("dashboard-v1"))
+	Expect(fetch("/monitor.yaml")).To(Equal("monitor-v1"))
+	Expect(conditional).To(Equal([]string{"/rules.yaml", "/dashboard.json"}))
+
+	// Changed content is fetched again
+	content["/rules.yaml"] = "rules-v2"
+	content["/monitor.yaml"] = "monitor-v2"
+	Expect(fetch("/rules.yaml")).To(Equal("rules-v2"))
+	Expect(fetch("/monitor.yaml")).To(Equal("monitor-v2"))
+	Expect(r.state.fetchCache).To(HaveLen(2))
+
+	// Validators of another tag are not sent
+	_, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "v2", "test-token")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(conditional).To(HaveLen(3))
+}
+
+// The reconcilers are created for every reconcile, the validators are kept in the shared sync state
+func TestFetchCache_SharedState(t *testing.T) {
+	RegisterTestingT(t)
+
+	var conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("If-None-Match") == `"rules-v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"rules-v1"`)
+		_, _ = w.Write([]byte("rules-v1"))
+	}))
+	defer server.Close()
+
+	state := NewSyncState()
+	for i := 0; i < 2; i++ {
+		r := NewReconciler(nil, logr.Discard(), nil, state).(*Reconciler)
+		r.httpClient = server.Client()
+		body, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "v1", "test-token")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal("rules-v1"))
+	}
+	Expect(conditional).To(Equal(1))
+
+	// Without the shared state the document is fetched again
+	r := &Reconciler{httpClient: server.Client()}
+	_, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "v1", "test-token")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(conditional).To(Equal(1))
+}
+
+func TestFetchCache_FetchSpans(t *testing.T) {
+	RegisterTestingT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("rules"))
+	}))
+	defer server.Close()
+
+	// The fetches are children of the span of the sync
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "Sync")
+	r := &Reconciler{httpClient: server.Client()}
+	for _, path := range []string{"/rules.yaml", "/rules.yaml", "/missing.yaml"} {
+		_, _ = r.fetchResource(ctx, server.URL+path, "v1", "test-token")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	Expect(spans).To(HaveLen(4))
+	spans = spans[:3]
+	for _, span := range spans {
+		Expect(span.Name()).To(Equal("Fetch"))
+		Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(span.Attributes()).To(ContainElement(attribute.String("fetch.tag", "v1")))
+	}
+	Expect(spans[0].Attributes()).To(ContainElement(attribute.Int("http.status_code", http.StatusOK)))
+	Expect(spans[1].Attributes()).To(ContainElement(attribute.Bool("fetch.not_modified", true)))
+	Expect(spans[2].Attributes()).To(ContainElement(attribute.Int("http.status_code", http.StatusNotFound)))
+}
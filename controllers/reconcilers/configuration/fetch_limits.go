@@ -0,0 +1,134 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"golang.org/x/time/rate"
+)
+
+// Rate limit and circuit breaker of the requests to a host, kept in the sync state so that they
+// apply across reconciles
+type hostFetchState struct {
+	limiter *rate.Limiter
+	// Consecutive failed requests
+	failures int
+	// Requests fail right away until then
+	openUntil time.Time
+}
+
+// Wait for the rate limit of the host. While its circuit is open the request fails right away,
+// so that a slow or failing host doesn't hold up the sync with a timeout per document.
+func (r *Reconciler) acquireFetch(ctx context.Context, host string) error {
+	limits := r.fetchLimits
+
+	syncState := r.getSyncState()
+	syncState.mu.Lock()
+	state := r.getHostFetchState(syncState, host)
+	openUntil := state.openUntil
+	limiter := state.limiter
+	// Changes of the policy apply from the next request on
+	limiter.SetLimit(getFetchRate(limits))
+	limiter.SetBurst(limits.Burst)
+	syncState.mu.Unlock()
+
+	if time.Now().Before(openUntil) {
+		metrics.IncreaseRejectedFetchesMetric(host)
+		return fmt.Errorf("requests to %v are suspended until %v after repeated failures", host, openUntil.Format(time.RFC3339))
+	}
+	err := limiter.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("rate limit of requests to %v: %w", host, err)
+	}
+	return nil
+}
+
+// Count the failures of the host, the response is nil if the request failed. A Retry-After of
+// the host opens the circuit right away, for as long as the host asks.
+func (r *Reconciler) recordFetchResult(host string, resp *http.Response) {
+	limits := r.fetchLimits
+	if limits.FailureThreshold == 0 {
+		return
+	}
+
+	syncState := r.getSyncState()
+	syncState.mu.Lock()
+	defer syncState.mu.Unlock()
+	state := r.getHostFetchState(syncState, host)
+
+	if resp != nil && !isFailedFetch(resp) {
+		state.failures = 0
+		state.openUntil = time.Time{}
+		metrics.SetFetchCircuitOpenMetric(host, false)
+		return
+	}
+
+	state.failures++
+	retryAfter := getRetryAfter(resp)
+	if retryAfter == 0 && state.failures < limits.FailureThreshold {
+		return
+	}
+	openFor := limits.OpenDuration
+	if retryAfter > 0 {
+		openFor = retryAfter
+	}
+	state.openUntil = time.Now().Add(openFor)
+	metrics.SetFetchCircuitOpenMetric(host, true)
+}
+
+// Must be called with the lock of the sync state held
+func (r *Reconciler) getHostFetchState(syncState *SyncState, host string) *hostFetchState {
+	state, ok := syncState.hostFetchStates[host]
+	if !ok {
+		state = &hostFetchState{limiter: rate.NewLimiter(getFetchRate(r.fetchLimits), r.fetchLimits.Burst)}
+		syncState.hostFetchStates[host] = state
+	}
+	return state
+}
+
+func getFetchRate(limits model.FetchLimits) rate.Limit {
+	if limits.RequestsPerSecond == 0 {
+		return rate.Inf
+	}
+	return rate.Limit(limits.RequestsPerSecond)
+}
+
+// Server errors and rate limits count as failures of the host, missing documents don't. GitHub
+// answers its rate limits with 403 and no remaining requests.
+func isFailedFetch(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"
+	default:
+		return false
+	}
+}
+
+// Retry-After of a failed response in seconds or as a date, 0 if there is none
+func getRetryAfter(resp *http.Response) time.Duration {
+	if resp == nil || !isFailedFetch(resp) {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+package configuration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+)
+
+func TestFetchLimits_CircuitBreaker(t *testing.T) {
+	RegisterTestingT(t)
+
+	status := http.StatusServiceUnavailable
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("rules"))
+	}))
+	defer server.Close()
+	host := getTestHost(server.URL)
+
+	r := &Reconciler{
+		httpClient:  server.Client(),
+		fetchLimits: model.FetchLimits{Timeout: time.Second, FailureThreshold: 2, OpenDuration: time.Minute},
+	}
+	fetch := func() error {
+		_, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "", "test-token")
+		return err
+	}
+
+	// The circuit opens after two failures in a row, requests then fail without being sent
+	Expect(fetch()).ToNot(Succeed())
+	Expect(r.state.hostFetchStates[host].openUntil.IsZero()).To(BeTrue())
+	Expect(fetch()).ToNot(Succeed())
+	Expect(r.state.hostFetchStates[host].openUntil).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+	Expect(fetch()).To(MatchError(ContainSubstring("suspended")))
+	Expect(requests).To(Equal(2))
+
+	// Once the open duration passed the next request is sent, the circuit closes when it succeeds
+	status = http.StatusOK
+	r.state.hostFetchStates[host].openUntil = time.Now().Add(-time.Second)
+	Expect(fetch()).To(Succeed())
+	Expect(r.state.hostFetchStates[host].failures).To(BeZero())
+	Expect(requests).To(Equal(3))
+
+	// Missing documents are not failures of the host
+	status = http.StatusNotFound
+	Expect(fetch()).ToNot(Succeed())
+	Expect(fetch()).ToNot(Succeed())
+	Expect(fetch()).ToNot(Succeed())
+	Expect(r.state.hostFetchStates[host].openUntil.IsZero()).To(BeTrue())
+}
+
+// The circuit opened by a sync stays open for the syncs of the next reconciles
+func TestFetchLimits_SharedState(t *testing.T) {
+	RegisterTestingT(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	state := NewSyncState()
+	fetch := func() error {
+		r := &Reconciler{
+			httpClient:  server.Client(),
+			fetchLimits: model.FetchLimits{FailureThreshold: 2, OpenDuration: time.Minute},
+			state:       state,
+		}
+		_, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "", "test-token")
+		return err
+	}
+
+	Expect(fetch()).ToNot(Succeed())
+	Expect(fetch()).ToNot(Succeed())
+	Expect(fetch()).To(MatchError(ContainSubstring("suspended")))
+	Expect(requests).To(Equal(2))
+}
+
+func TestFetchLimits_RetryAfter(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	r := &Reconciler{
+		httpClient:  server.Client(),
+		fetchLimits: model.FetchLimits{FailureThreshold: 5, OpenDuration: time.Minute},
+	}
+	_, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "", "test-token")
+	Expect(err).To(HaveOccurred())
+
+	// The host asked for a longer wait than the open duration
+	Expect(r.state.hostFetchStates[getTestHost(server.URL)].openUntil).To(BeTemporally("~", time.Now().Add(2*time.Minute), time.Second))
+}
+
+func TestFetchLimits_RateLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("rules"))
+	}))
+	defer server.Close()
+
+	r := &Reconciler{
+		httpClient:  server.Client(),
+		fetchLimits: model.FetchLimits{RequestsPerSecond: 10, Burst: 2},
+	}
+
+	// Two requests at once, then one every 100ms
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "", "test-token")
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(time.Since(start)).To(BeNumerically(">=", 150*time.Millisecond))
+
+	// A wait longer than the timeout fails right away
+	r.fetchLimits = model.FetchLimits{RequestsPerSecond: 1, Burst: 1, Timeout: 100 * time.Millisecond}
+	r.state = nil
+	_, err := r.fetchResource(context.Background(), server.URL+"/rules.yaml", "", "test-token")
+	Expect(err).ToNot(HaveOccurred())
+	_, err = r.fetchResource(context.Background(), server.URL+"/rules.yaml", "", "test-token")
+	Expect(err).To(MatchError(ContainSubstring("rate limit")))
+}
+
+func getTestHost(rawUrl string) string {
+	parsed, _ := url.Parse(rawUrl)
+	return parsed.Host
+}
@@ -0,0 +1,143 @@
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	kv1 "k8s.io/api/core/v1"
+)
+
+// Remote write is considered failing if the newest sent sample is older than this. It is also
+// the minimum time between two switches, so that a new gateway gets the chance to catch up.
+const gatewayFailoverLag = 5 * time.Minute
+
+// Observatorium instances that have a secondary gateway, by the remote write queue of their index.
+// Remote write queues are named after the index.
+func getFailoverObservatoria(indexes []v1.RepositoryIndex) map[string]*v1.ObservatoriumIndex {
+	observatoria := map[string]*v1.ObservatoriumIndex{}
+	for i := range indexes {
+		index := &indexes[i]
+		if index.Config == nil || index.Config.Prometheus == nil || index.Config.Prometheus.Observatorium == "" {
+			continue
+		}
+		observatorium := token.GetObservatoriumConfig(index, index.Config.Prometheus.Observatorium)
+		if observatorium != nil && observatorium.SecondaryGateway != "" {
+			observatoria[index.Id] = observatorium
+		}
+	}
+	return observatoria
+}
+
+// Check the remote write queues of the Observatorium instances that have a secondary gateway and
+// switch to the other gateway if the queue of the active one is failing. Returns true if a gateway
+// was switched.
+func (r *Reconciler) reconcileGatewayFailover(cr *v1.Observability, observatoria map[string]*v1.ObservatoriumIndex, s *v1.ObservabilityStatus) (bool, error) {
+	// Forget the gateways of instances that no longer have a secondary gateway
+	isConfigured := func(id string) bool {
+		for _, observatorium := range observatoria {
+			if observatorium.Id == id {
+				return true
+			}
+		}
+		return false
+	}
+	var active []v1.ActiveGateway
+	for _, gateway := range s.ActiveGateways {
+		if isConfigured(gateway.Observatorium) {
+			active = append(active, gateway)
+		}
+	}
+	s.ActiveGateways = sortActiveGateways(active)
+	r.activeGateways = active
+
+	if len(observatoria) == 0 {
+		return false, nil
+	}
+
+	families, err := utils.FetchMetrics(r.httpClient, fmt.Sprintf("%v/metrics", model.GetPrometheusUpstreamUrl(cr)))
+	if err != nil {
+		return false, err
+	}
+
+	switched := false
+	now := time.Now()
+	for remoteName, observatorium := range observatoria {
+		current := model.GetObservatoriumGateway(active, observatorium)
+		gateway := getActiveGateway(active, observatorium.Id)
+		if gateway != nil && now.Sub(time.Unix(gateway.Since, 0)) < gatewayFailoverLag {
+			continue
+		}
+
+		if !isRemoteWriteFailing(families, remoteName, now) {
+			continue
+		}
+
+		next := observatorium.SecondaryGateway
+		if current == observatorium.SecondaryGateway {
+			next = observatorium.Gateway
+		}
+		active = setActiveGateway(active, v1.ActiveGateway{
+			Observatorium: observatorium.Id,
+			Gateway:       next,
+			Since:         now.Unix(),
+		})
+		r.recordEvent(cr, kv1.EventTypeWarning, EventReasonGatewayFailover,
+			"Remote write to %v is failing, switched observatorium %v to %v", current, observatorium.Id, next)
+		switched = true
+	}
+
+	s.ActiveGateways = sortActiveGateways(active)
+	r.activeGateways = active
+	return switched, nil
+}
+
+// The queue is failing if the newest sample sent lags behind the newest sample ingested. A queue
+// that has not sent anything yet is failing if it retried samples and Prometheus is running long enough.
+func isRemoteWriteFailing(families map[string]*dto.MetricFamily, remoteName string, now time.Time) bool {
+	labels := map[string]string{
+		"remote_name": remoteName,
+	}
+	highest := utils.SumMetricValues(families, "prometheus_remote_storage_highest_timestamp_in_seconds")
+	sent := utils.SumMatchingMetricValues(families, "prometheus_remote_storage_queue_highest_sent_timestamp_seconds", labels)
+	if highest == 0 {
+		return false
+	}
+
+	if sent > 0 {
+		return highest-sent > gatewayFailoverLag.Seconds()
+	}
+
+	uptime := float64(now.Unix()) - utils.SumMetricValues(families, "process_start_time_seconds")
+	retried := utils.SumMatchingMetricValues(families, "prometheus_remote_storage_samples_retried_total", labels)
+	return uptime > gatewayFailoverLag.Seconds() && retried > 0
+}
+
+func getActiveGateway(active []v1.ActiveGateway, observatorium string) *v1.ActiveGateway {
+	for i := range active {
+		if active[i].Observatorium == observatorium {
+			return &active[i]
+		}
+	}
+	return nil
+}
+
+func setActiveGateway(active []v1.ActiveGateway, gateway v1.ActiveGateway) []v1.ActiveGateway {
+	if existing := getActiveGateway(active, gateway.Observatorium); existing != nil {
+		*existing = gateway
+		return active
+	}
+	return append(active, gateway)
+}
+
+func sortActiveGateways(active []v1.ActiveGateway) []v1.ActiveGateway {
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Observatorium < active[j].Observatorium
+	})
+	return active
+}
@@ -0,0 +1,138 @@
+package configuration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/common/expfmt"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestGatewayFailover_IsRemoteWriteFailing(t *testing.T) {
+	now := time.Unix(100000, 0)
+
+	type args struct {
+		metrics string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "not failing if the queue keeps up",
+			args: args{
+				metrics: `
+prometheus_remote_storage_highest_timestamp_in_seconds 99990
+prometheus_remote_storage_queue_highest_sent_timestamp_seconds{remote_name="test",url="https://primary"} 99980
+`,
+			},
+			want: false,
+		},
+		{
+			name: "failing if the queue lags behind",
+			args: args{
+				metrics: `
+prometheus_remote_storage_highest_timestamp_in_seconds 99990
+prometheus_remote_storage_queue_highest_sent_timestamp_seconds{remote_name="test",url="https://primary"} 90000
+prometheus_remote_storage_queue_highest_sent_timestamp_seconds{remote_name="other",url="https://other"} 99980
+`,
+			},
+			want: true,
+		},
+		{
+			name: "failing if nothing was sent and samples are retried",
+			args: args{
+				metrics: `
+process_start_time_seconds 90000
+prometheus_remote_storage_highest_timestamp_in_seconds 99990
+prometheus_remote_storage_queue_highest_sent_timestamp_seconds{remote_name="test",url="https://primary"} 0
+prometheus_remote_storage_samples_retried_total{remote_name="test",url="https://primary"} 500
+`,
+			},
+			want: true,
+		},
+		{
+			name: "not failing right after Prometheus started",
+			args: args{
+				metrics: fmt.Sprintf(`
+process_start_time_seconds %v
+prometheus_remote_storage_highest_timestamp_in_seconds 99990
+prometheus_remote_storage_queue_highest_sent_timestamp_seconds{remote_name="test",url="https://primary"} 0
+prometheus_remote_storage_samples_retried_total{remote_name="test",url="https://primary"} 500
+`, now.Unix()-60),
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parser expfmt.TextParser
+			families, err := parser.TextToMetricFamilies(strings.NewReader(tt.args.metrics))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isRemoteWriteFailing(families, "test", now)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGatewayFailover_ReconcileGatewayFailover(t *testing.T) {
+	RegisterTestingT(t)
+
+	metrics := `
+prometheus_remote_storage_highest_timestamp_in_seconds %v
+prometheus_remote_storage_queue_highest_sent_timestamp_seconds{remote_name="index-1",url="https://primary"} %v
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().Unix()
+		fmt.Fprintf(w, metrics, now, now-600)
+	}))
+	defer server.Close()
+
+	cr := &v1.Observability{}
+	cr.Namespace = "observability"
+	cr.Name = "observability-stack"
+	cr.Spec.ExternalPrometheus = &v1.ExternalPrometheus{Name: "prometheus", Url: server.URL}
+	index := v1.RepositoryIndex{
+		Id: "index-1",
+		Config: &v1.RepositoryConfig{
+			Prometheus: &v1.PrometheusIndex{Observatorium: "observatorium"},
+			Observatoria: []v1.ObservatoriumIndex{
+				{Id: "observatorium", Gateway: "https://primary", SecondaryGateway: "https://secondary", Tenant: "test"},
+			},
+		},
+	}
+
+	// The instances of the last sync are checked by the next reconciles, before the resync is due
+	state := NewSyncState()
+	state.setFailoverObservatoria(cr, getFailoverObservatoria([]v1.RepositoryIndex{index}))
+	r := &Reconciler{logger: logr.Discard(), httpClient: server.Client(), state: state}
+	s := &v1.ObservabilityStatus{}
+	switched, err := r.reconcileGatewayFailover(cr, r.getSyncState().getFailoverObservatoria(cr), s)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(switched).To(BeTrue())
+	Expect(s.ActiveGateways).To(HaveLen(1))
+	Expect(s.ActiveGateways[0].Gateway).To(Equal("https://secondary"))
+
+	// The new gateway gets the chance to catch up
+	switched, err = r.reconcileGatewayFailover(cr, r.getSyncState().getFailoverObservatoria(cr), s)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(switched).To(BeFalse())
+	Expect(s.ActiveGateways[0].Gateway).To(Equal("https://secondary"))
+
+	// Instances without a secondary gateway are forgotten
+	state.setFailoverObservatoria(cr, nil)
+	Expect(state.getFailoverObservatoria(cr)).To(BeEmpty())
+	switched, err = r.reconcileGatewayFailover(cr, nil, s)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(switched).To(BeFalse())
+	Expect(s.ActiveGateways).To(BeEmpty())
+}
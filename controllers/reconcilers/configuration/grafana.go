@@ -2,20 +2,32 @@ package configuration
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const (
-	GrafanaBaseImage = "docker.io/grafana/grafana:"
-)
+// Empty without a version, the Grafana operator uses its default image then
+func getGrafanaImage(cr *v1.Observability, indexes []v1.RepositoryIndex) string {
+	specVer, verError := semver.ParseTolerant(model.GetGrafanaVersion(indexes, cr))
+	if specVer.String() != "0.0.0" && verError == nil {
+		return model.GetImage(cr, v1.ImageGrafana, model.GrafanaBaseImage, specVer.String())
+	}
+	// Without a version only an override with a tag applies
+	if _, tag := model.GetImageRepository(cr, v1.ImageGrafana, model.GrafanaBaseImage); tag != "" {
+		return model.GetImage(cr, v1.ImageGrafana, model.GrafanaBaseImage, "")
+	}
+	return ""
+}
 
 func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
 	grafana := model.GetGrafanaCr(cr)
@@ -24,13 +36,35 @@ func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observabilit
 	var t = true
 	var replicaCount int32 = 1
 
-	specVer, verError := semver.ParseTolerant(model.GetGrafanaVersion(indexes, cr))
-	GrafanaImage := ""
-	if specVer.String() != "0.0.0" && verError == nil {
-		GrafanaImage = GrafanaBaseImage + specVer.String()
+	GrafanaImage := getGrafanaImage(cr, indexes)
+
+	authentication, err := r.reconcileGrafanaAuthentication(ctx, cr)
+	if err != nil {
+		return err
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, grafana, func() error {
+	mountedSecrets := []string{model.GrafanaTLSSecretName, "grafana-k8s-proxy"}
+	if authentication != nil {
+		mountedSecrets = model.GetGrafanaAuthenticationSecrets(cr)
+		if model.IsCertManagerServedDirectly(cr) {
+			mountedSecrets = append(mountedSecrets, model.GrafanaTLSSecretName)
+		}
+	}
+	contentHash, err := r.getMountedContentHash(ctx, grafana.Namespace, mountedSecrets, nil)
+	if err != nil {
+		return err
+	}
+
+	existing, err := r.getExistingResource(ctx, grafana)
+	if err != nil {
+		return err
+	}
+	var opts []client.PatchOption
+	if r.adoptResource(cr, existing, grafana, "Grafana") {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	_, err = utils.Apply(ctx, r.client, grafana, func() error {
 		grafana.Spec = v1alpha1.GrafanaSpec{
 			Config: v1alpha1.GrafanaConfig{
 				Log: &v1alpha1.GrafanaConfigLog{
@@ -51,32 +85,31 @@ func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observabilit
 			Containers: []core.Container{
 				{
 					Name:  "grafana-proxy",
-					Image: "quay.io/openshift/origin-oauth-proxy:4.8",
-					Args: []string{
+					Image: model.GetOAuthProxyImage(cr),
+					Args: append([]string{
 						"-provider=openshift",
 						"-pass-basic-auth=false",
 						"-https-address=:9091",
 						"-http-address=",
 						"-email-domain=*",
 						"-upstream=http://localhost:3000",
-						"-openshift-sar={\"resource\": \"namespaces\", \"verb\": \"get\"}",
-						"-openshift-delegate-urls={\"/\": {\"resource\": \"namespaces\", \"verb\": \"get\"}}",
 						"-tls-cert=/etc/tls/private/tls.crt",
 						"-tls-key=/etc/tls/private/tls.key",
-						"-client-secret-file=/var/run/secrets/kubernetes.io/serviceaccount/token",
+						fmt.Sprintf("-client-secret-file=%v", model.GetServiceAccountTokenFile(model.OAuthProxyTokenVolume)),
 						"-cookie-secret-file=/etc/proxy/secrets/session_secret",
 						"-openshift-service-account=grafana-serviceaccount",
 						"-openshift-ca=/etc/pki/tls/cert.pem",
 						"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
 						"-skip-auth-regex=^/metrics",
-					},
+					}, append(model.GetOAuthProxyAuthorizationArgs(cr, v1.OAuthProxyGrafana), model.GetOAuthProxyTLSArgs(cr)...)...),
 					Ports: []core.ContainerPort{
 						{
 							Name:          "grafana-proxy",
 							ContainerPort: 9091,
 						},
 					},
-					Resources: core.ResourceRequirements{},
+					Resources:       model.GetRecommendedResourceRequirement(cr, model.ComponentGrafana, "grafana-proxy", core.ResourceRequirements{}),
+					SecurityContext: model.GetContainerSecurityContext(cr),
 					VolumeMounts: []core.VolumeMount{
 						{
 							Name:      "secret-grafana-k8s-tls",
@@ -88,6 +121,7 @@ func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observabilit
 							ReadOnly:  false,
 							MountPath: "/etc/proxy/secrets",
 						},
+						model.GetServiceAccountTokenVolumeMount(model.OAuthProxyTokenVolume),
 					},
 				},
 			},
@@ -101,13 +135,11 @@ func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observabilit
 				Termination: "reencrypt",
 			},
 			Secrets: []string{
-				"grafana-k8s-tls",
+				model.GrafanaTLSSecretName,
 				"grafana-k8s-proxy",
 			},
 			Service: &v1alpha1.GrafanaService{
-				Annotations: map[string]string{
-					"service.alpha.openshift.io/serving-cert-secret-name": "grafana-k8s-tls",
-				},
+				Annotations: model.GetServingCertAnnotations(cr, model.GrafanaTLSSecretName),
 				Ports: []core.ServicePort{
 					{
 						Name:       "grafana-proxy",
@@ -126,22 +158,108 @@ func (r *Reconciler) reconcileGrafanaCr(ctx context.Context, cr *v1.Observabilit
 				PreferService: &t,
 			},
 			Deployment: &v1alpha1.GrafanaDeployment{
-				Replicas:          &replicaCount,
-				PriorityClassName: model.ObservabilityPriorityClassName,
-				Annotations: map[string]string{
+				Replicas:                 &replicaCount,
+				PriorityClassName:        model.ObservabilityPriorityClassName,
+				SecurityContext:          model.GetPodSecurityContext(cr),
+				ContainerSecurityContext: model.GetContainerSecurityContext(cr),
+				Annotations: MergeLabels(map[string]string{
 					"cluster-autoscaler.kubernetes.io/safe-to-evict": "true",
+					ContentHashAnnotation:                            contentHash,
+				}, model.GetServiceMeshPodAnnotations(cr)),
+				ExtraVolumes: []core.Volume{
+					model.GetServiceAccountTokenVolume(model.OAuthProxyTokenVolume, "", model.ServiceAccountTokenExpirationSeconds),
 				},
 			},
-			Resources: model.GetGrafanaResourceRequirement(cr),
+			Resources: &([]core.ResourceRequirements{model.GetRecommendedResourceRequirement(cr, model.ComponentGrafana, "grafana", *model.GetGrafanaResourceRequirement(cr))})[0],
 		}
 		if cr.Spec.Tolerations != nil {
 			grafana.Spec.Deployment.Tolerations = cr.Spec.Tolerations
 		}
-		if cr.Spec.Affinity != nil {
-			grafana.Spec.Deployment.Affinity = cr.Spec.Affinity
+		var architectures [][]string
+		if cr.OAuthProxyEnabled() {
+			architectures = append(architectures, model.GetOAuthProxyImageArchitectures(cr))
+		}
+		if GrafanaImage != "" {
+			architectures = append(architectures, model.GetImageArchitectures(cr, v1.ImageGrafana, model.GrafanaBaseImage))
+		}
+		grafana.Spec.Deployment.Affinity = model.GetArchitectureAffinity(cr, cr.Spec.Affinity, architectures...)
+		// Without the OpenShift oauth proxy Grafana is exposed directly and anonymous access is disabled
+		if cr.IsKubernetesCluster() {
+			grafana.Spec.Config.AuthAnonymous.Enabled = &f
+			grafana.Spec.Containers = nil
+			grafana.Spec.Secrets = nil
+			grafana.Spec.Service = nil
+			grafana.Spec.ServiceAccount = nil
+			grafana.Spec.Ingress = &v1alpha1.GrafanaIngress{
+				Enabled:    true,
+				TargetPort: "grafana",
+			}
+		}
+		// The mesh authenticates, Grafana is exposed directly and keeps its anonymous access
+		if cr.ServiceMeshAuthenticationEnabled() && !cr.IsKubernetesCluster() {
+			grafana.Spec.Containers = nil
+			grafana.Spec.Secrets = nil
+			grafana.Spec.Service = nil
+			grafana.Spec.Deployment.ExtraVolumes = nil
+			grafana.Spec.Ingress = &v1alpha1.GrafanaIngress{
+				Enabled:     true,
+				TargetPort:  "grafana",
+				Termination: "edge",
+			}
+		}
+		// Grafana logs users in itself, the oauth proxy is not needed
+		if authentication != nil {
+			grafana.Spec.Config.AuthAnonymous.Enabled = &f
+			grafana.Spec.Config.Auth.DisableSignoutMenu = &f
+			grafana.Spec.Config.AuthGenericOauth = model.GetGrafanaAuthGenericOauth(cr, authentication.oauthHost, authentication.roleAttributePath)
+			if authentication.rootUrl != "" {
+				grafana.Spec.Config.Server = &v1alpha1.GrafanaConfigServer{
+					RootUrl: authentication.rootUrl,
+				}
+			}
+			grafana.Spec.Containers = nil
+			grafana.Spec.Secrets = mountedSecrets
+			grafana.Spec.Service = nil
+			grafana.Spec.Deployment.Env = model.GetGrafanaAuthenticationEnv(cr)
+			grafana.Spec.Deployment.ExtraVolumes = model.GetGrafanaAuthenticationVolumes(cr)
+			grafana.Spec.Deployment.ExtraVolumeMounts = model.GetGrafanaAuthenticationVolumeMounts(cr)
+			if rotation := model.GetGrafanaOAuthTokenRotation(cr, time.Now()); rotation != "" {
+				grafana.Spec.Deployment.Annotations[model.GrafanaOAuthTokenRotationAnnotation] = rotation
+			}
+			if !cr.IsKubernetesCluster() {
+				grafana.Spec.Ingress = &v1alpha1.GrafanaIngress{
+					Enabled:     true,
+					TargetPort:  "grafana",
+					Termination: "edge",
+				}
+			}
+		}
+		// Grafana serves the certificate of cert-manager itself, the secrets of the login already include it
+		if model.IsCertManagerServedDirectly(cr) {
+			if grafana.Spec.Config.Server == nil {
+				grafana.Spec.Config.Server = &v1alpha1.GrafanaConfigServer{}
+			}
+			grafana.Spec.Config.Server.Protocol = "https"
+			grafana.Spec.Config.Server.CertFile = model.GetGrafanaTLSFile("tls.crt")
+			grafana.Spec.Config.Server.CertKey = model.GetGrafanaTLSFile("tls.key")
+			if authentication == nil {
+				grafana.Spec.Secrets = []string{model.GrafanaTLSSecretName}
+			}
+		}
+		// The ingress is created by the grafana operator
+		if cr.IngressEnabled() {
+			endpoint := cr.Spec.Ingress.Grafana
+			grafana.Spec.Ingress.Enabled = endpoint != nil
+			grafana.Spec.Ingress.Hostname = model.GetIngressHost(endpoint)
+			grafana.Spec.Ingress.IngressClassName = cr.Spec.Ingress.IngressClassName
+			grafana.Spec.Ingress.Annotations = cr.Spec.Ingress.Annotations
+			if endpoint != nil && endpoint.TLSSecretName != "" {
+				grafana.Spec.Ingress.TLSEnabled = true
+				grafana.Spec.Ingress.TLSSecretName = endpoint.TLSSecretName
+			}
 		}
 		return nil
-	})
+	}, opts...)
 
 	return err
 }
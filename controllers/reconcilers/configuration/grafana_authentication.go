@@ -0,0 +1,99 @@
+package configuration
+
+import (
+	"context"
+	"sort"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Settings of the Grafana login that depend on the cluster
+type grafanaAuthentication struct {
+	rootUrl           string
+	oauthHost         string
+	roleAttributePath string
+}
+
+// Resolves the login settings. Nil with the default proxy authentication. The OpenShift
+// login uses a projected token, the token secret of earlier versions is deleted.
+func (r *Reconciler) reconcileGrafanaAuthentication(ctx context.Context, cr *v1.Observability) (*grafanaAuthentication, error) {
+	err := r.client.Delete(ctx, model.GetGrafanaOAuthClientSecret(cr))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	authType := cr.GetGrafanaAuthenticationType()
+	if authType == v1.GrafanaAuthenticationProxy {
+		return nil, nil
+	}
+
+	domain := ""
+	if !cr.IsKubernetesCluster() {
+		domain, err = utils.GetClusterIngressDomain(ctx, r.client)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := &grafanaAuthentication{
+		rootUrl:           model.GetGrafanaRootUrl(cr, domain),
+		oauthHost:         model.GetOpenShiftOAuthHost(domain),
+		roleAttributePath: model.GetGrafanaRoleAttributePath(cr, nil),
+	}
+	if authType != v1.GrafanaAuthenticationOpenShift {
+		return result, nil
+	}
+
+	bindings, err := r.getClusterRoleSubjects(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	result.roleAttributePath = model.GetGrafanaRoleAttributePath(cr, bindings)
+	return result, nil
+}
+
+// Users and groups bound to the mapped cluster roles. Changes of the bindings apply on the next
+// reconcile, the role of a user is updated on their next login.
+func (r *Reconciler) getClusterRoleSubjects(ctx context.Context, cr *v1.Observability) (map[string]model.GrafanaRoleSubjects, error) {
+	clusterRoles := map[string]bool{}
+	for _, mapping := range cr.Spec.GrafanaAuthentication.RoleMappings {
+		if mapping.ClusterRole != "" {
+			clusterRoles[mapping.ClusterRole] = true
+		}
+	}
+	if len(clusterRoles) == 0 {
+		return nil, nil
+	}
+
+	list := &rbacv1.ClusterRoleBindingList{}
+	err := r.client.List(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]model.GrafanaRoleSubjects{}
+	for _, binding := range list.Items {
+		if binding.RoleRef.Kind != "ClusterRole" || !clusterRoles[binding.RoleRef.Name] {
+			continue
+		}
+		subjects := result[binding.RoleRef.Name]
+		for _, subject := range binding.Subjects {
+			switch subject.Kind {
+			case rbacv1.UserKind:
+				subjects.Users = append(subjects.Users, subject.Name)
+			case rbacv1.GroupKind:
+				subjects.Groups = append(subjects.Groups, subject.Name)
+			}
+		}
+		result[binding.RoleRef.Name] = subjects
+	}
+	// Sorted for a stable configuration of Grafana
+	for name, subjects := range result {
+		sort.Strings(subjects.Users)
+		sort.Strings(subjects.Groups)
+		result[name] = subjects
+	}
+	return result, nil
+}
@@ -3,17 +3,19 @@ package configuration
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	url2 "net/url"
 	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	errors2 "github.com/pkg/errors"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type SourceType int
@@ -26,10 +28,13 @@ const (
 )
 
 type DashboardInfo struct {
+	Id          string
 	Name        string
 	Url         string
 	AccessToken string
 	Tag         string
+	// Title of the Grafana folder, empty for the default folder
+	Folder string
 }
 
 func getNameFromUrl(path string) string {
@@ -54,10 +59,12 @@ seek:
 				}
 			}
 			result = append(result, DashboardInfo{
+				Id:          index.Id,
 				Name:        name,
 				Url:         fmt.Sprintf("%s/%s", index.BaseUrl, dashboard),
 				AccessToken: index.AccessToken,
 				Tag:         index.Tag,
+				Folder:      getDashboardFolder(&index, dashboard),
 			})
 		}
 	}
@@ -76,6 +83,11 @@ func (r *Reconciler) deleteUnrequestedDashboards(cr *v1.Observability, ctx conte
 	}
 
 	isRequested := func(name string) bool {
+		// Built-in and generated dashboards are managed separately
+		if model.IsLibraryResource(name) || name == model.GetServiceLevelObjectiveDashboard(cr).Name ||
+			name == model.GetEventExporterDashboard(cr).Name || model.IsTenantDashboard(name) {
+			return true
+		}
 		for _, dashboard := range dashboards {
 			if name == dashboard.Name {
 				return true
@@ -99,11 +111,20 @@ func (r *Reconciler) deleteUnrequestedDashboards(cr *v1.Observability, ctx conte
 }
 
 func (r *Reconciler) createRequestedDashboards(cr *v1.Observability, ctx context.Context, dashboards []DashboardInfo) error {
+	workers := model.GetResourceSyncWorkers(cr)
+
 	// Create a list of requested dashboards from the external sources provided
-	// in the CR
-	var requestedDashboards []*v1alpha1.GrafanaDashboard
+	// in the CR, fetched in parallel
+	fetched := make([]*v1alpha1.GrafanaDashboard, len(dashboards))
+	indexIds := map[string]string{}
+	folders := map[string]string{}
 	for _, d := range dashboards {
-		sourceType, source, err := r.fetchDashboard(d.Url, d.Tag, d.AccessToken)
+		indexIds[d.Name] = d.Id
+		folders[d.Name] = d.Folder
+	}
+	err := runParallel(len(dashboards), workers, func(i int) error {
+		d := dashboards[i]
+		sourceType, source, err := r.fetchDashboard(ctx, d.Url, d.Tag, d.AccessToken)
 		if err != nil {
 			return err
 		}
@@ -116,37 +137,62 @@ func (r *Reconciler) createRequestedDashboards(cr *v1.Observability, ctx context
 			if err != nil {
 				return err
 			}
-			requestedDashboards = append(requestedDashboards, dashboard)
+			fetched[i] = dashboard
 		case SourceTypeJsonnet:
 		case SourceTypeJson:
 			dashboard, err := createDashboardFromSource(cr, d.Name, sourceType, source)
 			if err != nil {
 				return err
 			}
-			requestedDashboards = append(requestedDashboards, dashboard)
+			fetched[i] = dashboard
 		default:
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var requestedDashboards []*v1alpha1.GrafanaDashboard
+	for _, dashboard := range fetched {
+		if dashboard != nil {
+			requestedDashboards = append(requestedDashboards, dashboard)
+		}
 	}
 
 	// Sync requested dashboards
-	for _, dashboard := range requestedDashboards {
+	return runParallel(len(requestedDashboards), workers, func(i int) error {
+		dashboard := requestedDashboards[i]
+		// Dashboards filtered out for this cluster are removed if they have been applied before
+		selected, err := isResourceSelected(cr.Spec.DashboardFilters, dashboard.Name, dashboard.Labels)
+		if err != nil {
+			return errors2.Wrap(err, "error applying dashboard filters")
+		}
+		if !selected {
+			err = r.client.Delete(ctx, dashboard)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}
 
+		r.trackResource(ManagedKindGrafanaDashboard, dashboard, indexIds[dashboard.Name])
 		requestedSpec := dashboard.Spec
-		requestedLabels := dashboard.Labels
+		// The folder of the index replaces the one of a dashboard in YAML
+		if folders[dashboard.Name] != "" {
+			requestedSpec.CustomFolderName = folders[dashboard.Name]
+		}
+		requestedLabels := MergeLabels(map[string]string{
+			"managed-by":              "observability-operator",
+			ManagedResourceIndexLabel: indexIds[dashboard.Name],
+		}, dashboard.Labels)
 
-		_, err := controllerutil.CreateOrUpdate(ctx, r.client, dashboard, func() error {
+		return r.createOrUpdateIndexResource(ctx, cr, dashboard, []interface{}{requestedSpec, requestedLabels}, func() error {
 			dashboard.Spec = requestedSpec
-			dashboard.Labels = MergeLabels(map[string]string{
-				"managed-by": "observability-operator",
-			}, requestedLabels)
+			dashboard.Labels = requestedLabels
 			return nil
 		})
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	})
 }
 
 func parseDashboardFromYaml(cr *v1.Observability, name string, source []byte) (*v1alpha1.GrafanaDashboard, error) {
@@ -200,17 +246,25 @@ func getFileType(path string) SourceType {
 	}
 }
 
-func (r *Reconciler) fetchDashboard(path string, tag string, token string) (SourceType, []byte, error) {
+func (r *Reconciler) fetchDashboard(ctx context.Context, path string, tag string, token string) (SourceType, []byte, error) {
 	url, err := url2.ParseRequestURI(path)
 	if err != nil {
 		return SourceTypeUnknown, nil, err
 	}
 
+	if r.pinnedResources != nil {
+		body, err := r.getPinnedResource(path, tag)
+		if err != nil {
+			return SourceTypeUnknown, nil, err
+		}
+		return getFileType(url.Path), body, nil
+	}
+
 	if token == "" {
 		return SourceTypeUnknown, nil, fmt.Errorf("repository ConfigMap missing required AccessToken")
 	}
 
-	req, err := http.NewRequest(http.MethodGet, url.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
 	if err != nil {
 		return SourceTypeUnknown, nil, err
 	}
@@ -223,21 +277,16 @@ func (r *Reconciler) fetchDashboard(path string, tag string, token string) (Sour
 		req.URL.RawQuery = q.Encode()
 	}
 
-	resp, err := r.httpClient.Do(req)
+	body, statusCode, err := r.doCachedRequest(req, path, tag, metrics.FetchTypeResource)
 	if err != nil {
 		return SourceTypeUnknown, nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return SourceTypeUnknown, nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return SourceTypeUnknown, nil, err
+	if statusCode != 200 {
+		return SourceTypeUnknown, nil, fmt.Errorf("unexpected status code: %v", statusCode)
 	}
 
+	r.recordFetchedResource(path, tag, body)
 	sourceType := getFileType(url.Path)
 	return sourceType, body, nil
 }
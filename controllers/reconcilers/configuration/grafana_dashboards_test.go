@@ -161,6 +161,7 @@ func TestGrafanaDashboards_GetUniqueDashboards(t *testing.T) {
 			},
 			want: []DashboardInfo{
 				{
+					Id:          "test-id",
 					Name:        "test-dashboard-name",
 					Url:         "test-base-url/test-dashboard-name",
 					AccessToken: "test-access-token",
@@ -0,0 +1,103 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Folders of an index that are applied, along with the errors of the invalid ones
+func getValidGrafanaFolders(index *v1.RepositoryIndex) ([]v1.GrafanaFolderIndex, []error) {
+	if index.Config == nil || index.Config.Grafana == nil {
+		return nil, nil
+	}
+
+	var result []v1.GrafanaFolderIndex
+	var errs []error
+	folders := map[string]string{}
+	for _, folder := range index.Config.Grafana.Folders {
+		err := model.ValidateGrafanaFolder(&folder, index.Config.Grafana.Dashboards)
+		if err == nil {
+			for _, dashboard := range folder.Dashboards {
+				if existing, ok := folders[dashboard]; ok {
+					err = fmt.Errorf("dashboard %v is in grafana folders %v and %v", dashboard, existing, folder.Name)
+					break
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, dashboard := range folder.Dashboards {
+			folders[dashboard] = folder.Name
+		}
+		result = append(result, folder)
+	}
+	return result, errs
+}
+
+// Empty for the default folder
+func getDashboardFolder(index *v1.RepositoryIndex, dashboard string) string {
+	folders, _ := getValidGrafanaFolders(index)
+	for _, folder := range folders {
+		for _, existing := range folder.Dashboards {
+			if existing == dashboard {
+				return folder.Name
+			}
+		}
+	}
+	return ""
+}
+
+// The Grafana operator creates the folders of the dashboards, a GrafanaFolder is only needed for the
+// permissions. The first index that declares permissions for a folder wins, like for dashboards.
+// Folders that are no longer requested are pruned with the other resources of the indexes.
+func (r *Reconciler) reconcileGrafanaFolders(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	requested := map[string]bool{}
+	for i := range indexes {
+		index := &indexes[i]
+		folders, errs := getValidGrafanaFolders(index)
+		for _, err := range errs {
+			r.logger.Error(err, "skipped grafana folder", "index", index.Id)
+			r.addConfigurationError(index.Id, v1.ErrorStageValidate, err)
+		}
+
+		for j := range folders {
+			if len(folders[j].Permissions) == 0 {
+				continue
+			}
+			folder := model.GetGrafanaFolder(cr, folders[j].Name)
+			if requested[folder.Name] {
+				continue
+			}
+			requested[folder.Name] = true
+
+			requestedTitle := folders[j].Name
+			requestedPermissions := model.GetGrafanaFolderPermissions(&folders[j])
+			requestedLabels := map[string]string{
+				"managed-by":              "observability-operator",
+				ManagedResourceIndexLabel: index.Id,
+			}
+			err := r.createOrUpdateIndexResource(ctx, cr, folder, []interface{}{requestedTitle, requestedPermissions, requestedLabels}, func() error {
+				folder.Labels = requestedLabels
+				folder.Spec.FolderName = requestedTitle
+				folder.Spec.FolderPermissions = requestedPermissions
+				return nil
+			})
+			// Older Grafana operators have no GrafanaFolder, the dashboards still go to their folders
+			if meta.IsNoMatchError(err) {
+				r.addConfigurationError(index.Id, v1.ErrorStageValidate, fmt.Errorf("the grafana operator doesn't support folder permissions"))
+				return nil
+			}
+			r.trackResource(ManagedKindGrafanaFolder, folder, index.Id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildGrafanaFolderIndexes() []v1.RepositoryIndex {
+	return []v1.RepositoryIndex{
+		{
+			Id:      "kafka",
+			BaseUrl: "test-base-url",
+			Config: &v1.RepositoryConfig{
+				Grafana: &v1.GrafanaIndex{
+					Dashboards: []string{"dashboards/kafka-brokers.json", "dashboards/kafka-topics.yaml", "dashboards/overview.json"},
+					Folders: []v1.GrafanaFolderIndex{
+						{
+							Name:       "Kafka",
+							Dashboards: []string{"dashboards/kafka-brokers.json", "dashboards/kafka-topics.yaml"},
+							Permissions: []v1.GrafanaFolderPermission{
+								{Type: "role", Target: "Viewer", Permission: "view"},
+								{Type: "team", Target: "12", Permission: "edit"},
+							},
+						},
+						{
+							Name:       "Topics",
+							Dashboards: []string{"dashboards/kafka-topics.yaml"},
+						},
+						{
+							Name:       "Missing",
+							Dashboards: []string{"dashboards/missing.json"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGrafanaFolders_GetValidGrafanaFolders(t *testing.T) {
+	RegisterTestingT(t)
+
+	indexes := buildGrafanaFolderIndexes()
+	folders, errs := getValidGrafanaFolders(&indexes[0])
+	Expect(folders).To(HaveLen(1))
+	Expect(folders[0].Name).To(Equal("Kafka"))
+	Expect(errs).To(HaveLen(2))
+	Expect(errs[0].Error()).To(ContainSubstring("is in grafana folders Kafka and Topics"))
+	Expect(errs[1].Error()).To(ContainSubstring("is not a dashboard of the index"))
+
+	dashboards := getUniqueDashboards(indexes)
+	Expect(dashboards).To(HaveLen(3))
+	Expect(dashboards[0].Folder).To(Equal("Kafka"))
+	Expect(dashboards[1].Folder).To(Equal("Kafka"))
+	Expect(dashboards[2].Folder).To(BeEmpty())
+}
+
+func TestGrafanaFolders_ReconcileGrafanaFolders(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	r := &Reconciler{
+		client: testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build()),
+		logger: logr.Discard(),
+	}
+	cr := &v1.Observability{}
+	cr.Namespace = "observability"
+
+	Expect(r.reconcileGrafanaFolders(context.TODO(), cr, buildGrafanaFolderIndexes())).To(Succeed())
+	Expect(r.configurationErrors).To(HaveLen(2))
+	Expect(r.managedResources).To(HaveLen(1))
+	Expect(r.managedResources[0].Kind).To(Equal(ManagedKindGrafanaFolder))
+
+	folder := model.GetGrafanaFolder(cr, "Kafka")
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(folder), folder)).To(Succeed())
+	Expect(folder.Labels[ManagedResourceIndexLabel]).To(Equal("kafka"))
+	Expect(folder.Spec.FolderName).To(Equal("Kafka"))
+	Expect(folder.Spec.FolderPermissions).To(Equal([]v1alpha1.GrafanaPermissionItem{
+		{PermissionTargetType: "role", PermissionTarget: "Viewer", PermissionLevel: 1},
+		{PermissionTargetType: "teamId", PermissionTarget: "12", PermissionLevel: 2},
+	}))
+}
@@ -0,0 +1,257 @@
+package configuration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/images"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	v12 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	EventReasonImageResolveFailed      = "ImageResolveFailed"
+	EventReasonImageVerificationFailed = "ImageVerificationFailed"
+)
+
+// Looks up digests and verifies signatures, replaced in tests
+type imageRegistry interface {
+	ResolveDigest(ctx context.Context, image string) (string, error)
+	VerifySignature(ctx context.Context, image string, digest string, keys []*ecdsa.PublicKey) error
+}
+
+// Logins of the pull secrets, later secrets override the logins of the same registry
+func (r *Reconciler) getRegistryCredentials(ctx context.Context, cr *v1.Observability) (map[string]images.RegistryCredential, error) {
+	credentials := map[string]images.RegistryCredential{}
+	for _, name := range cr.Spec.ImagePinning.PullSecrets {
+		secret := &v12.Secret{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: name}, secret)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching pull secret %v: %w", name, err)
+		}
+		content, ok := secret.Data[v12.DockerConfigJsonKey]
+		if !ok {
+			return nil, fmt.Errorf("pull secret %v has no %v", name, v12.DockerConfigJsonKey)
+		}
+		err = images.ParseDockerConfig(content, credentials)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pull secret %v: %w", name, err)
+		}
+	}
+	return credentials, nil
+}
+
+// Resolves the digests of the images in use and records them in the status, from where the
+// resources pick them up. Failing lookups keep the previous digests. With signature verification
+// the stage fails if an image has no verified digest yet, so that nothing unverified is started.
+func (r *Reconciler) reconcileImageDigests(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) error {
+	if !cr.ImagePinningEnabled() {
+		s.ResolvedImages = nil
+		cr.Status.ResolvedImages = nil
+		return nil
+	}
+
+	var keys []*ecdsa.PublicKey
+	if cr.ImageVerificationEnabled() {
+		var err error
+		keys, err = images.ParsePublicKeys(cr.Spec.ImagePinning.CosignPublicKeys)
+		if err != nil {
+			return err
+		}
+	}
+
+	managed, err := r.getManagedImages(cr, indexes)
+	if err != nil {
+		return err
+	}
+
+	if r.imageRegistry == nil {
+		credentials, err := r.getRegistryCredentials(ctx, cr)
+		if err != nil {
+			return err
+		}
+		// Verified with the trusted CA bundle and through the proxy of the cluster
+		r.imageRegistry = images.NewRegistryClient(r.httpClient.Transport, credentials)
+	}
+
+	previous := map[string]v1.ResolvedImage{}
+	for _, resolved := range cr.Status.ResolvedImages {
+		previous[resolved.Image] = resolved
+	}
+	supplied := getSuppliedImageDigests(indexes)
+
+	var result []v1.ResolvedImage
+	for _, image := range managed {
+		entry, found := previous[image]
+		expired := time.Since(time.Unix(entry.ResolvedAt, 0)) >= model.GetImageResolveInterval(cr)
+		if found && !expired && (supplied[image] == "" || supplied[image] == entry.Digest) {
+			result = append(result, entry)
+			continue
+		}
+
+		digest := supplied[image]
+		var err error
+		if digest == "" {
+			digest, err = r.imageRegistry.ResolveDigest(ctx, image)
+		}
+		if err != nil {
+			r.logger.Error(err, "error resolving digest", "image", image)
+			r.recordEvent(cr, v12.EventTypeWarning, EventReasonImageResolveFailed, "Failed to resolve digest of %v: %v", image, err)
+			if found {
+				result = append(result, entry)
+			}
+			continue
+		}
+
+		resolved := v1.ResolvedImage{
+			Image:      image,
+			Digest:     digest,
+			ResolvedAt: time.Now().Unix(),
+		}
+		if cr.ImageVerificationEnabled() {
+			if found && entry.Digest == digest && entry.Verified {
+				resolved.Verified = true
+			} else if err := r.imageRegistry.VerifySignature(ctx, image, digest, keys); err != nil {
+				r.logger.Error(err, "error verifying signature", "image", image)
+				r.recordEvent(cr, v12.EventTypeWarning, EventReasonImageVerificationFailed, "Not rolling out %v@%v: %v", image, digest, err)
+				if found {
+					result = append(result, entry)
+				}
+				continue
+			} else {
+				resolved.Verified = true
+			}
+		}
+		result = append(result, resolved)
+	}
+
+	// Digests of images no longer in use are dropped, unless they hold back an unverified
+	// image of the same repository
+	if cr.ImageVerificationEnabled() {
+		for _, entry := range cr.Status.ResolvedImages {
+			if entry.Verified && !hasResolvedImage(result, entry.Image) && isHeldBack(result, managed, entry.Image) {
+				result = append(result, entry)
+			}
+		}
+	}
+
+	s.ResolvedImages = result
+	cr.Status.ResolvedImages = result
+
+	if cr.ImageVerificationEnabled() {
+		for _, image := range managed {
+			if pinned := model.GetPinnedImage(cr, image); !strings.Contains(pinned, "@") {
+				return fmt.Errorf("no verified digest for image %v", image)
+			}
+		}
+	}
+	return nil
+}
+
+// Images of all components in use, by tag
+func (r *Reconciler) getManagedImages(cr *v1.Observability, indexes []v1.RepositoryIndex) ([]string, error) {
+	unpinned := cr.DeepCopy()
+	unpinned.Spec.ImagePinning = nil
+
+	// The serving and the requested version differ during blue/green upgrades
+	result := []string{
+		model.GetImage(unpinned, v1.ImagePrometheus, model.PrometheusBaseImage, r.getPrometheusVersion(cr)),
+		model.GetImage(unpinned, v1.ImagePrometheus, model.PrometheusBaseImage, model.GetPrometheusVersion(cr)),
+	}
+	if !cr.BlackboxExporterDisabled() {
+		result = append(result, model.GetImage(unpinned, v1.ImageBlackboxExporter, model.BlackboxExporterImage, model.BlackboxExporterImageTag))
+	}
+	if !cr.IsKubernetesCluster() {
+		result = append(result, model.GetOAuthProxyImage(unpinned))
+	}
+	if cr.TenancyProxyEnabled() {
+		result = append(result,
+			model.GetImage(unpinned, v1.ImageKubeRBACProxy, model.KubeRBACProxyImage, model.KubeRBACProxyImageTag),
+			model.GetImage(unpinned, v1.ImagePromLabelProxy, model.PromLabelProxyImage, model.PromLabelProxyImageTag))
+	}
+	if cr.InfrastructureExportersEnabled() {
+		result = append(result, model.GetKubeStateMetricsImage(unpinned), model.GetNodeExporterImage(unpinned))
+	}
+	if thanosEnabled(cr) {
+		result = append(result, model.GetThanosImage(unpinned))
+	}
+	if cr.GrafanaEnabled() {
+		if image := getGrafanaImage(unpinned, indexes); image != "" {
+			result = append(result, image)
+		}
+	}
+
+	if !cr.ObservatoriumDisabled() {
+		configSets, err := r.getTokenRefresherConfigSets(unpinned, indexes)
+		if err != nil {
+			return nil, err
+		}
+		for _, configSet := range configSets {
+			result = append(result, fmt.Sprintf("%v:%v", configSet.Image, configSet.Tag))
+		}
+
+		if !cr.ExternalSyncDisabled() {
+			for _, index := range indexes {
+				if index.Config != nil && index.Config.Promtail != nil && index.Config.Promtail.Enabled {
+					for _, architecture := range append([]string{""}, model.GetOverriddenArchitectures(cr, v1.ImagePromtail)...) {
+						result = append(result, model.GetImageForArchitecture(unpinned, v1.ImagePromtail, architecture, model.PromtailImage, model.PromtailImageTag))
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return sortedUnique(result), nil
+}
+
+// Digests supplied by the indexes, the first index wins
+func getSuppliedImageDigests(indexes []v1.RepositoryIndex) map[string]string {
+	result := map[string]string{}
+	for _, index := range indexes {
+		if index.Config == nil {
+			continue
+		}
+		for image, digest := range index.Config.ImageDigests {
+			if _, ok := result[image]; !ok {
+				result[image] = digest
+			}
+		}
+	}
+	return result
+}
+
+func hasResolvedImage(resolved []v1.ResolvedImage, image string) bool {
+	for _, entry := range resolved {
+		if entry.Image == image {
+			return true
+		}
+	}
+	return false
+}
+
+// An image in use of the same repository has no verified digest
+func isHeldBack(resolved []v1.ResolvedImage, managed []string, image string) bool {
+	repository, _ := model.SplitImageTag(image)
+
+	for _, candidate := range managed {
+		if !strings.HasPrefix(candidate, repository+":") {
+			continue
+		}
+		verified := false
+		for _, entry := range resolved {
+			if entry.Image == candidate && entry.Verified {
+				verified = true
+			}
+		}
+		if !verified {
+			return true
+		}
+	}
+	return false
+}
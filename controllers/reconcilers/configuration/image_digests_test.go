@@ -0,0 +1,99 @@
+package configuration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+)
+
+type testImageRegistry struct {
+	digests  map[string]string
+	verified map[string]bool
+}
+
+func (r *testImageRegistry) ResolveDigest(ctx context.Context, image string) (string, error) {
+	if digest, ok := r.digests[image]; ok {
+		return digest, nil
+	}
+	return "", errors.New("not found")
+}
+
+func (r *testImageRegistry) VerifySignature(ctx context.Context, image string, digest string, keys []*ecdsa.PublicKey) error {
+	if r.verified[digest] {
+		return nil
+	}
+	return errors.New("invalid signature")
+}
+
+func getTestCosignPublicKey() string {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestImageDigests_ReconcileImageDigests(t *testing.T) {
+	g := NewWithT(t)
+	f := false
+	tr := true
+
+	cr := &v1.Observability{
+		Spec: v1.ObservabilitySpec{
+			ClusterType: v1.ClusterTypeKubernetes,
+			SelfContained: &v1.SelfContained{
+				DisableObservatorium:    &tr,
+				DisableBlackboxExporter: &tr,
+			},
+			ImagePinning: &v1.ImagePinningSpec{
+				Enabled: &tr,
+			},
+			DescopedMode: &v1.DescopedMode{Enabled: &tr},
+		},
+	}
+	image := model.GetImage(cr, v1.ImagePrometheus, model.PrometheusBaseImage, model.GetPrometheusVersion(cr))
+	registry := &testImageRegistry{
+		digests:  map[string]string{image: "sha256:first"},
+		verified: map[string]bool{},
+	}
+	r := &Reconciler{logger: logr.Discard(), imageRegistry: registry}
+
+	// Pinned by the resolved digest
+	s := &v1.ObservabilityStatus{}
+	g.Expect(r.reconcileImageDigests(context.TODO(), cr, nil, s)).To(Succeed())
+	g.Expect(s.ResolvedImages).To(HaveLen(1))
+	g.Expect(model.GetImage(cr, v1.ImagePrometheus, model.PrometheusBaseImage, model.GetPrometheusVersion(cr))).To(Equal(model.PrometheusBaseImage + "@sha256:first"))
+
+	// Digests supplied by the index are used instead
+	indexes := []v1.RepositoryIndex{{Config: &v1.RepositoryConfig{ImageDigests: map[string]string{image: "sha256:supplied"}}}}
+	g.Expect(r.reconcileImageDigests(context.TODO(), cr, indexes, s)).To(Succeed())
+	g.Expect(s.ResolvedImages[0].Digest).To(Equal("sha256:supplied"))
+
+	// Unverified digests are not rolled out, nothing runs without a verified digest
+	cr.Spec.ImagePinning.CosignPublicKeys = []string{getTestCosignPublicKey()}
+	cr.Status.ResolvedImages = nil
+	g.Expect(r.reconcileImageDigests(context.TODO(), cr, nil, s)).ToNot(Succeed())
+
+	registry.verified["sha256:first"] = true
+	g.Expect(r.reconcileImageDigests(context.TODO(), cr, nil, s)).To(Succeed())
+	g.Expect(s.ResolvedImages[0].Verified).To(BeTrue())
+
+	// A new version with an invalid signature keeps the verified digest of the old one
+	cr.Spec.SelfContained.PrometheusVersion = "v9.9.9"
+	registry.digests[model.PrometheusBaseImage+":v9.9.9"] = "sha256:unsigned"
+	g.Expect(r.reconcileImageDigests(context.TODO(), cr, nil, s)).To(Succeed())
+	g.Expect(model.GetImage(cr, v1.ImagePrometheus, model.PrometheusBaseImage, "v9.9.9")).To(Equal(model.PrometheusBaseImage + "@sha256:first"))
+
+	// Disabling pinning clears the digests
+	cr.Spec.ImagePinning.Enabled = &f
+	g.Expect(r.reconcileImageDigests(context.TODO(), cr, nil, s)).To(Succeed())
+	g.Expect(s.ResolvedImages).To(BeNil())
+}
@@ -0,0 +1,214 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"time"
+
+	errors2 "github.com/pkg/errors"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Commit hashes of git, the resources at a branch or at a moved tag change without a new revision
+var immutableTagRegex = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// Revision of an index as stored in the rollout secret. The access token and the
+// source secret are taken from the current index.
+type appliedIndex struct {
+	BaseUrl string             `json:"baseUrl"`
+	Tag     string             `json:"tag"`
+	Index   v1.RepositoryIndex `json:"index"`
+}
+
+// Hold back changes of indexes with rollout settings until the cluster is due. The previously
+// applied revision of such an index stays in place in the meantime.
+func (r *Reconciler) reconcileIndexRollouts(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) ([]v1.RepositoryIndex, error) {
+	applied, err := r.getAppliedIndexes(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var result []v1.RepositoryIndex
+	var rollouts []v1.IndexRollout
+	data := map[string]string{}
+	for _, index := range indexes {
+		revision, err := getIndexRevision(&index)
+		if err != nil {
+			return nil, err
+		}
+
+		// An invalid window delays nothing, the canary percentage still applies
+		if index.RolloutWindow != "" {
+			if _, err := time.ParseDuration(index.RolloutWindow); err != nil {
+				r.addConfigurationError(index.Id, v1.ErrorStageParse, errors2.Wrap(err, "invalid rollout window"))
+			}
+		}
+
+		// The resources of the previous revision are fetched at its tag, a tag that can move would fetch
+		// the changed resources. Such changes are applied right away.
+		held := hasRolloutSettings(&index)
+		if held && !immutableTagRegex.MatchString(index.Tag) {
+			err := fmt.Errorf("rollout settings require the tag of the index to be a commit hash, %v isn't one", index.Tag)
+			r.addConfigurationError(index.Id, v1.ErrorStageValidate, err)
+			held = false
+		}
+
+		rollout := getIndexRollout(s.IndexRollouts, index.Id)
+		if rollout == nil || rollout.AppliedRevision == revision || !held {
+			rollout = &v1.IndexRollout{Index: index.Id, AppliedRevision: revision}
+		} else {
+			if rollout.PendingRevision != revision {
+				rollout.PendingRevision = revision
+				rollout.PendingSince = now.Unix()
+			}
+			if isRolloutDue(s.ClusterID, &index, time.Unix(rollout.PendingSince, 0), now) {
+				r.logger.Info("rolling out index change", "index", index.Id, "revision", revision)
+				rollout = &v1.IndexRollout{Index: index.Id, AppliedRevision: revision}
+			}
+		}
+
+		// The previous revision can only be applied if it was recorded
+		if rollout.PendingRevision != "" {
+			previous, err := getAppliedIndex(applied[index.Id], &index)
+			if err != nil {
+				r.logger.Error(err, "previous revision of index unavailable, applying the change", "index", index.Id)
+				rollout = &v1.IndexRollout{Index: index.Id, AppliedRevision: revision}
+			} else {
+				data[index.Id] = applied[index.Id]
+				result = append(result, *previous)
+				rollouts = append(rollouts, *rollout)
+				continue
+			}
+		}
+
+		content, err := json.Marshal(appliedIndex{BaseUrl: index.BaseUrl, Tag: index.Tag, Index: index})
+		if err != nil {
+			return nil, err
+		}
+		data[index.Id] = string(content)
+		result = append(result, index)
+		rollouts = append(rollouts, *rollout)
+	}
+
+	s.IndexRollouts = rollouts
+
+	secret := model.GetIndexRolloutSecret(cr)
+	_, err = utils.Apply(ctx, r.client, secret, func() error {
+		secret.Type = kv1.SecretTypeOpaque
+		secret.Data = map[string][]byte{}
+		for id, content := range data {
+			secret.Data[id] = []byte(content)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The revisions of earlier versions are in the secret now
+	err = r.client.Delete(ctx, model.GetIndexRolloutConfigMap(cr))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Applied revisions by index id, from the config map of earlier versions until the secret exists
+func (r *Reconciler) getAppliedIndexes(ctx context.Context, cr *v1.Observability) (map[string]string, error) {
+	applied := map[string]string{}
+	secret := model.GetIndexRolloutSecret(cr)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+	if err == nil {
+		for id, content := range secret.Data {
+			applied[id] = string(content)
+		}
+		return applied, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	configMap := model.GetIndexRolloutConfigMap(cr)
+	err = r.client.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	for id, content := range configMap.Data {
+		applied[id] = content
+	}
+	return applied, nil
+}
+
+func hasRolloutSettings(index *v1.RepositoryIndex) bool {
+	return index.RolloutWindow != "" || index.CanaryPercent != nil
+}
+
+// The revision covers the index file and the location of its resources
+func getIndexRevision(index *v1.RepositoryIndex) (string, error) {
+	content, err := json.Marshal(appliedIndex{BaseUrl: index.BaseUrl, Tag: index.Tag, Index: *index})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content))[:16], nil
+}
+
+func getAppliedIndex(content string, current *v1.RepositoryIndex) (*v1.RepositoryIndex, error) {
+	if content == "" {
+		return nil, fmt.Errorf("no applied revision of index %v", current.Id)
+	}
+
+	applied := appliedIndex{}
+	err := json.Unmarshal([]byte(content), &applied)
+	if err != nil {
+		return nil, err
+	}
+
+	index := applied.Index
+	index.BaseUrl = applied.BaseUrl
+	index.Tag = applied.Tag
+	index.AccessToken = current.AccessToken
+	index.Source = current.Source
+	return &index, nil
+}
+
+func getIndexRollout(rollouts []v1.IndexRollout, id string) *v1.IndexRollout {
+	for i := range rollouts {
+		if rollouts[i].Index == id {
+			rollout := rollouts[i]
+			return &rollout
+		}
+	}
+	return nil
+}
+
+// Every cluster has a fixed position between 0 and 1 per index. Clusters below the canary percentage
+// apply a change, after a delay that is the same fraction of the rollout window.
+func isRolloutDue(clusterId string, index *v1.RepositoryIndex, pendingSince time.Time, now time.Time) bool {
+	position := getRolloutPosition(clusterId, index.Id)
+	if index.CanaryPercent != nil && position*100 >= float64(*index.CanaryPercent) {
+		return false
+	}
+
+	window, err := time.ParseDuration(index.RolloutWindow)
+	if err != nil {
+		window = 0
+	}
+	delay := time.Duration(position * float64(window))
+	return !now.Before(pendingSince.Add(delay))
+}
+
+func getRolloutPosition(clusterId string, indexId string) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("%v/%v", clusterId, indexId)))
+	return float64(h.Sum64()%10000) / 10000
+}
@@ -0,0 +1,172 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIndexRollout_IsRolloutDue(t *testing.T) {
+	pendingSince := time.Unix(1000, 0)
+	position := getRolloutPosition("cluster-1", "index-1")
+
+	tests := []struct {
+		name  string
+		index v1.RepositoryIndex
+		now   time.Time
+		want  bool
+	}{
+		{
+			name:  "without window the change applies immediately",
+			index: v1.RepositoryIndex{Id: "index-1"},
+			now:   pendingSince,
+			want:  true,
+		},
+		{
+			name:  "clusters wait for their share of the window",
+			index: v1.RepositoryIndex{Id: "index-1", RolloutWindow: "100h"},
+			now:   pendingSince.Add(time.Duration(position*float64(100*time.Hour)) - time.Minute),
+			want:  false,
+		},
+		{
+			name:  "clusters apply the change once their share of the window passed",
+			index: v1.RepositoryIndex{Id: "index-1", RolloutWindow: "100h"},
+			now:   pendingSince.Add(time.Duration(position*float64(100*time.Hour)) + time.Minute),
+			want:  true,
+		},
+		{
+			name:  "clusters outside of the canary wait",
+			index: v1.RepositoryIndex{Id: "index-1", CanaryPercent: &([]int{0})[0]},
+			now:   pendingSince.Add(1000 * time.Hour),
+			want:  false,
+		},
+		{
+			name:  "all clusters are in a full canary",
+			index: v1.RepositoryIndex{Id: "index-1", CanaryPercent: &([]int{100})[0]},
+			now:   pendingSince,
+			want:  true,
+		},
+		{
+			name:  "invalid windows delay nothing",
+			index: v1.RepositoryIndex{Id: "index-1", RolloutWindow: "soon"},
+			now:   pendingSince,
+			want:  true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isRolloutDue("cluster-1", &tt.index, pendingSince, tt.now)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIndexRollout_GetRolloutPosition(t *testing.T) {
+	RegisterTestingT(t)
+	position := getRolloutPosition("cluster-1", "index-1")
+	Expect(position).To(BeNumerically(">=", 0))
+	Expect(position).To(BeNumerically("<", 1))
+	Expect(getRolloutPosition("cluster-1", "index-1")).To(Equal(position))
+}
+
+func TestIndexRollout_GetAppliedIndex(t *testing.T) {
+	previous := v1.RepositoryIndex{
+		Id:      "index-1",
+		BaseUrl: "https://example.com/repo/stage",
+		Tag:     "v1",
+		Config: &v1.RepositoryConfig{
+			Prometheus: &v1.PrometheusIndex{Rules: []string{"rules-v1.yaml"}},
+		},
+	}
+	current := v1.RepositoryIndex{
+		Id:          "index-1",
+		BaseUrl:     "https://example.com/repo/stage",
+		Tag:         "v2",
+		AccessToken: "token",
+	}
+
+	RegisterTestingT(t)
+	previousRevision, err := getIndexRevision(&previous)
+	Expect(err).ToNot(HaveOccurred())
+	currentRevision, err := getIndexRevision(&current)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(previousRevision).ToNot(Equal(currentRevision))
+
+	content := `{"baseUrl":"https://example.com/repo/stage","tag":"v1","index":{"id":"index-1","config":{"prometheus":{"rules":["rules-v1.yaml"]}}}}`
+	result, err := getAppliedIndex(content, &current)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result.Tag).To(Equal("v1"))
+	Expect(result.AccessToken).To(Equal("token"))
+	Expect(result.Config.Prometheus.Rules).To(Equal([]string{"rules-v1.yaml"}))
+
+	_, err = getAppliedIndex("", &current)
+	Expect(err).To(HaveOccurred())
+}
+
+func TestIndexRollout_ReconcileIndexRollouts(t *testing.T) {
+	RegisterTestingT(t)
+
+	previous := v1.RepositoryIndex{Id: "index-1", BaseUrl: "https://example.com/repo/stage", Tag: "0123456789abcdef0123456789abcdef01234567"}
+	previousRevision, err := getIndexRevision(&previous)
+	Expect(err).ToNot(HaveOccurred())
+	content, err := json.Marshal(appliedIndex{BaseUrl: previous.BaseUrl, Tag: previous.Tag, Index: previous})
+	Expect(err).ToNot(HaveOccurred())
+
+	// Revisions recorded by an earlier version
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Name: "observability-stack", Namespace: "observability"}}
+	configMap := model.GetIndexRolloutConfigMap(cr)
+	configMap.Data = map[string]string{"index-1": string(content)}
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	r := &Reconciler{
+		client: testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()),
+		logger: logr.Discard(),
+	}
+
+	// The change is held back, no cluster is in the canary
+	canary := 0
+	current := previous
+	current.Tag = "89abcdef0123456789abcdef0123456789abcdef"
+	current.CanaryPercent = &canary
+	s := &v1.ObservabilityStatus{IndexRollouts: []v1.IndexRollout{{Index: "index-1", AppliedRevision: previousRevision}}}
+	result, err := r.reconcileIndexRollouts(context.TODO(), cr, []v1.RepositoryIndex{current}, s)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result).To(HaveLen(1))
+	Expect(result[0].Tag).To(Equal(previous.Tag))
+
+	// The revisions move to the secret
+	secret := &kv1.Secret{}
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(model.GetIndexRolloutSecret(cr)), secret)).To(Succeed())
+	Expect(string(secret.Data["index-1"])).To(Equal(string(content)))
+	err = r.client.Get(context.TODO(), client.ObjectKeyFromObject(configMap), &kv1.ConfigMap{})
+	Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	// And are read from there on
+	result, err = r.reconcileIndexRollouts(context.TODO(), cr, []v1.RepositoryIndex{current}, s)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result[0].Tag).To(Equal(previous.Tag))
+
+	// Tags that can move are reported and their changes are not held back
+	current.Tag = "main"
+	result, err = r.reconcileIndexRollouts(context.TODO(), cr, []v1.RepositoryIndex{current}, s)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(result[0].Tag).To(Equal("main"))
+	Expect(r.configurationErrors).To(HaveLen(1))
+	Expect(r.configurationErrors[0].Stage).To(Equal(v1.ErrorStageValidate))
+	Expect(s.IndexRollouts[0].PendingRevision).To(BeEmpty())
+}
@@ -0,0 +1,165 @@
+package configuration
+
+import (
+	"context"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kube-state-metrics and node-exporter for clusters without a monitoring stack to federate from
+func (r *Reconciler) reconcileInfrastructureExporters(ctx context.Context, cr *v1.Observability) error {
+	if !cr.InfrastructureExportersEnabled() {
+		return r.deleteInfrastructureExporters(ctx, cr)
+	}
+
+	for _, sa := range []*core.ServiceAccount{model.GetKubeStateMetricsServiceAccount(cr), model.GetNodeExporterServiceAccount(cr)} {
+		_, err := utils.Apply(ctx, r.client, sa, func() error {
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	clusterRole := model.GetKubeStateMetricsClusterRole(cr)
+	_, err := utils.Apply(ctx, r.client, clusterRole, func() error {
+		clusterRole.Rules = model.GetKubeStateMetricsClusterRoleRules()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	binding := model.GetKubeStateMetricsClusterRoleBinding(cr)
+	_, err = utils.Apply(ctx, r.client, binding, func() error {
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole.Name,
+		}
+		binding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      model.GetKubeStateMetricsServiceAccount(cr).Name,
+				Namespace: cr.GetPrometheusOperatorNamespace(),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileKubeStateMetrics(ctx, cr)
+	if err != nil {
+		return err
+	}
+	return r.reconcileNodeExporter(ctx, cr)
+}
+
+func (r *Reconciler) reconcileKubeStateMetrics(ctx context.Context, cr *v1.Observability) error {
+	var replicas int32 = 1
+	deployment := model.GetKubeStateMetricsDeployment(cr)
+	labels := deployment.Labels
+	_, err := utils.Apply(ctx, r.client, deployment, func() error {
+		deployment.Labels = labels
+		deployment.Spec = appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: core.PodSpec{
+					ServiceAccountName: model.GetKubeStateMetricsServiceAccount(cr).Name,
+					PriorityClassName:  model.ObservabilityPriorityClassName,
+					SecurityContext:    model.GetPodSecurityContext(cr),
+					Affinity:           model.GetArchitectureAffinity(cr, nil, model.GetImageArchitectures(cr, v1.ImageKubeStateMetrics, model.KubeStateMetricsImage)),
+					Tolerations:        cr.Spec.Tolerations,
+					Containers:         []core.Container{model.GetKubeStateMetricsContainer(cr)},
+				},
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.reconcileInfrastructureExporterService(ctx, model.GetKubeStateMetricsService(cr), model.KubeStateMetricsPort)
+}
+
+func (r *Reconciler) reconcileNodeExporter(ctx context.Context, cr *v1.Observability) error {
+	daemonset := model.GetNodeExporterDaemonSet(cr)
+	labels := daemonset.Labels
+	_, err := utils.Apply(ctx, r.client, daemonset, func() error {
+		daemonset.Labels = labels
+		daemonset.Spec = appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: model.GetNodeExporterPodSpec(cr),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.reconcileInfrastructureExporterService(ctx, model.GetNodeExporterService(cr), model.NodeExporterPort)
+}
+
+// Prometheus discovers the exporters by the endpoints of their services
+func (r *Reconciler) reconcileInfrastructureExporterService(ctx context.Context, service *core.Service, port int32) error {
+	selector := service.Labels
+	_, err := utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = selector
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "http-metrics",
+				Protocol:   core.ProtocolTCP,
+				Port:       port,
+				TargetPort: intstr.FromString("http-metrics"),
+			},
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) deleteInfrastructureExporters(ctx context.Context, cr *v1.Observability) error {
+	objects := []client.Object{
+		model.GetKubeStateMetricsDeployment(cr),
+		model.GetKubeStateMetricsService(cr),
+		model.GetKubeStateMetricsClusterRoleBinding(cr),
+		model.GetKubeStateMetricsClusterRole(cr),
+		model.GetKubeStateMetricsServiceAccount(cr),
+		model.GetNodeExporterDaemonSet(cr),
+		model.GetNodeExporterService(cr),
+		model.GetNodeExporterServiceAccount(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package configuration
+
+import (
+	"context"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	errors2 "github.com/pkg/errors"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Built-in rules and dashboards, independent of the indexes. They are selected by the rule and
+// dashboard selectors in use and the filters of the CR apply.
+func (r *Reconciler) reconcileLibrary(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	rules, err := model.GetLibraryRules(cr)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		selected, err := isResourceSelected(cr.Spec.RuleFilters, rule.Name, nil)
+		if err != nil {
+			return errors2.Wrap(err, "error applying rule filters")
+		}
+		if !cr.DefaultRulesEnabled() || !cr.ClusterRulesEnabled() || !selected {
+			err = r.client.Delete(ctx, rule)
+			if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return err
+			}
+			continue
+		}
+
+		err = r.applyLibraryRule(ctx, cr, indexes, rule)
+		if err != nil {
+			return err
+		}
+	}
+
+	dashboards, err := model.GetLibraryDashboards(cr)
+	if err != nil {
+		return err
+	}
+	for _, dashboard := range dashboards {
+		selected, err := isResourceSelected(cr.Spec.DashboardFilters, dashboard.Name, nil)
+		if err != nil {
+			return errors2.Wrap(err, "error applying dashboard filters")
+		}
+		if !cr.DefaultRulesEnabled() || !cr.GrafanaEnabled() || !selected {
+			err = r.client.Delete(ctx, dashboard)
+			if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return err
+			}
+			continue
+		}
+
+		err = r.applyLibraryDashboard(ctx, cr, indexes, dashboard)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) applyLibraryRule(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, rule *prometheusv1.PrometheusRule) error {
+	requestedSpec := rule.Spec
+	_, err := utils.Apply(ctx, r.client, rule, func() error {
+		rule.Spec = requestedSpec
+		rule.Labels = MergeLabels(map[string]string{
+			"managed-by":              "observability-operator",
+			model.LibraryVersionLabel: model.LibraryVersion,
+		}, model.GetSelectorLabels(model.GetPrometheusRuleLabelSelectors(cr, indexes)))
+		injectClusterLabels(cr, rule)
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) applyLibraryDashboard(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, dashboard *v1alpha1.GrafanaDashboard) error {
+	requestedSpec := dashboard.Spec
+	_, err := utils.Apply(ctx, r.client, dashboard, func() error {
+		dashboard.Spec = requestedSpec
+		dashboard.Labels = MergeLabels(map[string]string{
+			"managed-by":              "observability-operator",
+			model.LibraryVersionLabel: model.LibraryVersion,
+		}, model.GetSelectorLabels(model.GetGrafanaDashboardLabelSelectors(cr, indexes)))
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) deleteLibrary(ctx context.Context, cr *v1.Observability) error {
+	rules, err := model.GetLibraryRules(cr)
+	if err != nil {
+		return err
+	}
+	dashboards, err := model.GetLibraryDashboards(cr)
+	if err != nil {
+		return err
+	}
+
+	var objects []client.Object
+	for _, rule := range rules {
+		objects = append(objects, rule)
+	}
+	for _, dashboard := range dashboards {
+		objects = append(objects, dashboard)
+	}
+	for _, object := range objects {
+		err = r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
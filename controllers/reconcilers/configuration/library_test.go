@@ -0,0 +1,19 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+)
+
+func TestLibrary_ValidRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	rules, err := model.GetLibraryRules(&v1.Observability{})
+	Expect(err).ToNot(HaveOccurred())
+	for _, rule := range rules {
+		Expect(validateRule(rule)).To(Succeed())
+	}
+}
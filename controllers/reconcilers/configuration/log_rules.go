@@ -0,0 +1,206 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+)
+
+// Sync the LogQL rules of the indexes and the CR to the Loki ruler of the Observatorium instances that
+// receive logs. The ruler takes one group per request, so groups that are no longer declared are
+// deleted from the namespace of the operator. The synced rules are recorded in the status and only
+// synced again when they change. Nothing is synced in dry run mode.
+func (r *Reconciler) reconcileLogRules(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) error {
+	// Logs are only shipped to Observatorium
+	if cr.ObservatoriumDisabled() {
+		s.LogRules = nil
+		return nil
+	}
+	if cr.DryRunEnabled() {
+		return nil
+	}
+
+	synced := map[string]v1.PushedRules{}
+	for _, status := range s.LogRules {
+		synced[status.Observatorium] = status
+	}
+
+	observatoria, groups := getLogRuleGroups(cr, indexes)
+
+	var failed []string
+	for _, id := range sortedLogRuleIds(groups) {
+		content, err := yaml.Marshal(groups[id])
+		if err != nil {
+			return err
+		}
+
+		hash := fmt.Sprintf("%x", sha256.Sum256(content))
+		if status, ok := synced[id]; ok && status.Hash == hash && status.Tenant == observatoria[id].Tenant {
+			continue
+		}
+
+		err = r.syncLogRules(ctx, cr, observatoria[id], groups[id])
+		if err != nil {
+			r.logger.Error(err, "error syncing log rules to observatorium", "observatorium", id)
+			failed = append(failed, id)
+			continue
+		}
+		synced[id] = v1.PushedRules{Observatorium: id, Tenant: observatoria[id].Tenant, Hash: hash}
+	}
+
+	// Instances that no index ships logs to anymore are synced again when they are used again
+	s.LogRules = nil
+	for _, id := range sortedPushedRulesIds(synced) {
+		if observatoria[id] != nil {
+			s.LogRules = append(s.LogRules, synced[id])
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to sync log rules to observatoria %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (r *Reconciler) syncLogRules(ctx context.Context, cr *v1.Observability, observatorium *v1.ObservatoriumIndex, groups []v12.RuleGroup) error {
+	bearer, err := r.getObservatoriumToken(ctx, cr, getLogsObservatorium(observatorium))
+	if err != nil {
+		return err
+	}
+	rulesUrl := model.GetObservatoriumLogRulesUrl(model.GetObservatoriumGateway(r.activeGateways, observatorium), observatorium.Tenant)
+
+	status, body, err := r.doLogRulesRequest(ctx, http.MethodGet, rulesUrl, bearer, nil)
+	if err != nil {
+		return err
+	}
+	existing := map[string][]v12.RuleGroup{}
+	switch status {
+	case http.StatusOK:
+		err = yaml.Unmarshal(body, &existing)
+		if err != nil {
+			return err
+		}
+	case http.StatusNotFound:
+	default:
+		return fmt.Errorf("unexpected status code from log rules api of %v: %v", observatorium.Id, status)
+	}
+
+	declared := map[string]bool{}
+	for _, group := range groups {
+		declared[group.Name] = true
+		content, err := yaml.Marshal(group)
+		if err != nil {
+			return err
+		}
+		status, _, err = r.doLogRulesRequest(ctx, http.MethodPost, rulesUrl, bearer, content)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK && status != http.StatusAccepted {
+			return fmt.Errorf("unexpected status code from log rules api of %v: %v", observatorium.Id, status)
+		}
+	}
+
+	for _, group := range existing[model.LogRulesNamespace] {
+		if declared[group.Name] {
+			continue
+		}
+		status, _, err = r.doLogRulesRequest(ctx, http.MethodDelete, fmt.Sprintf("%v/%v", rulesUrl, url.PathEscape(group.Name)), bearer, nil)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK && status != http.StatusAccepted && status != http.StatusNotFound {
+			return fmt.Errorf("unexpected status code from log rules api of %v: %v", observatorium.Id, status)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) doLogRulesRequest(ctx context.Context, method string, rulesUrl string, bearer string, content []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rulesUrl, bytes.NewReader(content))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", bearer))
+	if content != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+// Red Hat SSO issues the tokens for logs to the logs client
+func getLogsObservatorium(observatorium *v1.ObservatoriumIndex) *v1.ObservatoriumIndex {
+	if observatorium.RedhatSsoConfig == nil {
+		return observatorium
+	}
+	result := *observatorium
+	sso := *observatorium.RedhatSsoConfig
+	sso.MetricsClient = sso.LogsClient
+	sso.MetricsSecret = sso.LogsSecret
+	result.RedhatSsoConfig = &sso
+	return &result
+}
+
+// Rule groups by Observatorium instance. Groups of the indexes are prefixed with the index id, the
+// groups of the CR go to every instance that receives logs.
+func getLogRuleGroups(cr *v1.Observability, indexes []v1.RepositoryIndex) (map[string]*v1.ObservatoriumIndex, map[string][]v12.RuleGroup) {
+	observatoria := map[string]*v1.ObservatoriumIndex{}
+	groups := map[string][]v12.RuleGroup{}
+	for i := range indexes {
+		index := &indexes[i]
+		if index.Config == nil || index.Config.Promtail == nil || index.Config.Promtail.Observatorium == "" {
+			continue
+		}
+
+		observatorium := token.GetObservatoriumConfig(index, index.Config.Promtail.Observatorium)
+		if observatorium == nil || !observatorium.IsValid() {
+			continue
+		}
+
+		observatoria[observatorium.Id] = observatorium
+		if groups[observatorium.Id] == nil {
+			groups[observatorium.Id] = []v12.RuleGroup{}
+		}
+		for _, group := range index.Config.Promtail.Rules {
+			group.Name = fmt.Sprintf("%v-%v", index.Id, group.Name)
+			groups[observatorium.Id] = append(groups[observatorium.Id], group)
+		}
+	}
+
+	for id := range groups {
+		groups[id] = append(groups[id], cr.Spec.LogRules...)
+	}
+	return observatoria, groups
+}
+
+func sortedLogRuleIds(groups map[string][]v12.RuleGroup) []string {
+	var result []string
+	for id := range groups {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
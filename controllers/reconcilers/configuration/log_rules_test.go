@@ -0,0 +1,110 @@
+package configuration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	kv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLogRules_ReconcileLogRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Loki ruler with a stale group of the operator
+	var posted []string
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		Expect(req.Header.Get("Authorization")).To(Equal("Bearer test-token"))
+		path := "/api/logs/v1/" + testTenant + "/loki/api/v1/rules/" + model.LogRulesNamespace
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == path:
+			content, _ := yaml.Marshal(map[string][]v12.RuleGroup{model.LogRulesNamespace: {{Name: "stale"}}})
+			_, _ = w.Write(content)
+		case req.Method == http.MethodPost && req.URL.Path == path:
+			content, _ := io.ReadAll(req.Body)
+			group := v12.RuleGroup{}
+			Expect(yaml.Unmarshal(content, &group)).To(Succeed())
+			posted = append(posted, group.Name)
+			w.WriteHeader(http.StatusAccepted)
+		case req.Method == http.MethodDelete:
+			deleted = append(deleted, req.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	observatorium := v1.ObservatoriumIndex{
+		Id:       "default",
+		Gateway:  server.URL,
+		Tenant:   testTenant,
+		AuthType: v1.AuthTypeDex,
+		DexConfig: &v1.DexConfig{
+			Url:      server.URL,
+			Username: "user",
+		},
+	}
+	indexes := []v1.RepositoryIndex{
+		{
+			Id: "kafka",
+			Config: &v1.RepositoryConfig{
+				Promtail: &v1.PromtailIndex{
+					Enabled:       true,
+					Observatorium: "default",
+					Rules:         []v12.RuleGroup{{Name: "errors"}},
+				},
+				Observatoria: []v1.ObservatoriumIndex{observatorium},
+			},
+		},
+	}
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability"}}
+	cr.Spec.LogRules = []v12.RuleGroup{{Name: "cluster"}}
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	secret := &kv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: token.GetObservatoriumTokenSecretName(&observatorium), Namespace: "observability"},
+		Data:       map[string][]byte{token.RemoteTokenValue: []byte("test-token")},
+	}
+	r := &Reconciler{
+		client:     fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		logger:     logr.Discard(),
+		httpClient: server.Client(),
+	}
+
+	s := &v1.ObservabilityStatus{}
+	Expect(r.reconcileLogRules(context.TODO(), cr, indexes, s)).To(Succeed())
+	Expect(posted).To(Equal([]string{"kafka-errors", "cluster"}))
+	Expect(s.LogRules).To(HaveLen(1))
+	Expect(s.LogRules[0].Tenant).To(Equal(testTenant))
+	Expect(deleted).To(Equal([]string{"/api/logs/v1/" + testTenant + "/loki/api/v1/rules/" + model.LogRulesNamespace + "/stale"}))
+
+	// Unchanged rules are not synced again, the status carries over to the next reconciles
+	posted = nil
+	Expect(r.reconcileLogRules(context.TODO(), cr, indexes, s.DeepCopy())).To(Succeed())
+	Expect(posted).To(BeEmpty())
+
+	// Dry runs don't sync
+	dryRun := cr.DeepCopy()
+	enabled := true
+	dryRun.Spec.DryRun = &enabled
+	dryRun.Spec.LogRules = nil
+	deleted = nil
+	Expect(r.reconcileLogRules(context.TODO(), dryRun, indexes, s)).To(Succeed())
+	Expect(posted).To(BeEmpty())
+	Expect(deleted).To(BeEmpty())
+}
@@ -0,0 +1,148 @@
+package configuration
+
+import (
+	"context"
+	"sort"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	errors2 "github.com/pkg/errors"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v13 "k8s.io/api/apps/v1"
+	v12 "k8s.io/api/core/v1"
+	v14 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Id of the index a resource was created from
+const ManagedResourceIndexLabel = "observability-operator/index"
+
+// Kinds of the resources in the inventory
+const (
+	ManagedKindPrometheusRule   = "PrometheusRule"
+	ManagedKindGrafanaDashboard = "GrafanaDashboard"
+	ManagedKindGrafanaFolder    = "GrafanaFolder"
+	ManagedKindPodMonitor       = "PodMonitor"
+	ManagedKindService          = "Service"
+	ManagedKindNetworkPolicy    = "NetworkPolicy"
+	ManagedKindDeployment       = "Deployment"
+	ManagedKindSecret           = "Secret"
+)
+
+// Remember a resource that is requested by the current sync, also if it could not be applied,
+// so that the version applied by a previous sync is not pruned
+func (r *Reconciler) trackResource(kind string, obj client.Object, index string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.managedResources {
+		if existing.Kind == kind && existing.Namespace == obj.GetNamespace() && existing.Name == obj.GetName() {
+			return
+		}
+	}
+	r.managedResources = append(r.managedResources, v1.ManagedResource{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Index:     index,
+	})
+}
+
+// Delete the resources of the previous sync that are no longer requested and record the
+// current inventory. Only called after a successful sync, a partial sync would prune too much.
+func (r *Reconciler) pruneManagedResources(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	for _, resource := range getOrphanedResources(cr.Status.ManagedResources, r.managedResources) {
+		obj := newManagedObject(resource)
+		if obj == nil {
+			continue
+		}
+
+		err := r.client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors2.Wrapf(err, "error fetching orphaned %v %v", resource.Kind, resource.Name)
+		}
+
+		// Resources that have been taken over by someone else are left alone
+		if !isOwnedResource(obj) {
+			continue
+		}
+
+		r.logger.Info("pruning orphaned resource", "kind", resource.Kind, "name", resource.Name, "index", resource.Index)
+		err = r.client.Delete(ctx, obj)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors2.Wrapf(err, "error deleting orphaned %v %v", resource.Kind, resource.Name)
+		}
+	}
+
+	s.ManagedResources = sortManagedResources(r.managedResources)
+	return nil
+}
+
+func getOrphanedResources(previous []v1.ManagedResource, current []v1.ManagedResource) []v1.ManagedResource {
+	isCurrent := func(resource v1.ManagedResource) bool {
+		for _, c := range current {
+			if c.Kind == resource.Kind && c.Namespace == resource.Namespace && c.Name == resource.Name {
+				return true
+			}
+		}
+		return false
+	}
+
+	var result []v1.ManagedResource
+	for _, resource := range previous {
+		if !isCurrent(resource) {
+			result = append(result, resource)
+		}
+	}
+	return result
+}
+
+func newManagedObject(resource v1.ManagedResource) client.Object {
+	var obj client.Object
+	switch resource.Kind {
+	case ManagedKindPrometheusRule:
+		obj = &prometheusv1.PrometheusRule{}
+	case ManagedKindGrafanaDashboard:
+		obj = &v1alpha1.GrafanaDashboard{}
+	case ManagedKindGrafanaFolder:
+		obj = &v1alpha1.GrafanaFolder{}
+	case ManagedKindPodMonitor:
+		obj = &prometheusv1.PodMonitor{}
+	case ManagedKindService:
+		obj = &v12.Service{}
+	case ManagedKindNetworkPolicy:
+		obj = &v14.NetworkPolicy{}
+	case ManagedKindDeployment:
+		obj = &v13.Deployment{}
+	case ManagedKindSecret:
+		obj = &v12.Secret{}
+	default:
+		return nil
+	}
+	obj.SetNamespace(resource.Namespace)
+	obj.SetName(resource.Name)
+	return obj
+}
+
+// Resources synced from the indexes carry the managed-by label, the token refresher resources
+// are labeled with their component
+func isOwnedResource(obj client.Object) bool {
+	labels := obj.GetLabels()
+	return labels["managed-by"] == "observability-operator" || labels["app.kubernetes.io/component"] == "authentication-proxy"
+}
+
+func sortManagedResources(resources []v1.ManagedResource) []v1.ManagedResource {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		if resources[i].Namespace != resources[j].Namespace {
+			return resources[i].Namespace < resources[j].Namespace
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	return resources
+}
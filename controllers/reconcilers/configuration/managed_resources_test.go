@@ -0,0 +1,96 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestManagedResources_GetOrphanedResources(t *testing.T) {
+	rule := v1.ManagedResource{Kind: ManagedKindPrometheusRule, Namespace: "prometheus", Name: "rule-1", Index: "index-1"}
+	dashboard := v1.ManagedResource{Kind: ManagedKindGrafanaDashboard, Namespace: "observability", Name: "rule-1", Index: "index-1"}
+	moved := v1.ManagedResource{Kind: ManagedKindPrometheusRule, Namespace: "prometheus", Name: "rule-1", Index: "index-2"}
+
+	type args struct {
+		previous []v1.ManagedResource
+		current  []v1.ManagedResource
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []v1.ManagedResource
+	}{
+		{
+			name: "resources missing from the current sync are orphaned",
+			args: args{
+				previous: []v1.ManagedResource{rule, dashboard},
+				current:  []v1.ManagedResource{rule},
+			},
+			want: []v1.ManagedResource{dashboard},
+		},
+		{
+			name: "resources that moved to another index are kept",
+			args: args{
+				previous: []v1.ManagedResource{rule},
+				current:  []v1.ManagedResource{moved},
+			},
+			want: nil,
+		},
+		{
+			name: "first sync without inventory prunes nothing",
+			args: args{
+				previous: nil,
+				current:  []v1.ManagedResource{rule, dashboard},
+			},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getOrphanedResources(tt.args.previous, tt.args.current)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestManagedResources_IsOwnedResource(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want bool
+	}{
+		{
+			name: "synced from an index",
+			obj: &v12.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				"managed-by": "observability-operator",
+			}}},
+			want: true,
+		},
+		{
+			name: "token refresher resource",
+			obj: &v12.Service{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				"app.kubernetes.io/component": "authentication-proxy",
+			}}},
+			want: true,
+		},
+		{
+			name: "labels removed by someone else",
+			obj:  &v12.Secret{},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Expect(isOwnedResource(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Metric classes of the CR that are applied, along with the errors of the invalid ones
+func getValidMetricClasses(cr *v1.Observability) ([]v1.MetricClass, []error) {
+	var result []v1.MetricClass
+	var errs []error
+	seen := map[string]bool{}
+	for _, class := range cr.Spec.MetricClasses {
+		err := model.ValidateMetricClass(&class)
+		if err == nil && seen[class.Name] {
+			err = fmt.Errorf("duplicate metric class %v", class.Name)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		seen[class.Name] = true
+		result = append(result, class)
+	}
+	return result, errs
+}
+
+// Record the aggregations of all metric classes in one rule, removed when no class declares aggregations
+func (r *Reconciler) reconcileMetricClassRules(ctx context.Context, cr *v1.Observability) error {
+	classes, errs := getValidMetricClasses(cr)
+	for _, err := range errs {
+		r.logger.Error(err, "skipped metric class")
+		r.addConfigurationError("", v1.ErrorStageValidate, err)
+	}
+
+	var groups []prometheusv1.RuleGroup
+	for i := range classes {
+		if len(classes[i].Aggregations) > 0 {
+			groups = append(groups, model.GetMetricClassRuleGroup(&classes[i]))
+		}
+	}
+
+	rule := model.GetMetricClassRule(cr)
+	if len(groups) == 0 {
+		err := r.client.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, getRuleSelectorLabels(cr))
+		rule.Spec.Groups = groups
+		return nil
+	})
+	return err
+}
+
+// The series of the metric classes are not remote written unless the class enables it
+func applyMetricClasses(cr *v1.Observability, remoteWrite *prometheusv1.RemoteWriteSpec) {
+	classes, _ := getValidMetricClasses(cr)
+	remoteWrite.WriteRelabelConfigs = append(remoteWrite.WriteRelabelConfigs, model.GetMetricClassRelabelConfigs(classes)...)
+}
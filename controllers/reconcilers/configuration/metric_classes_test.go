@@ -0,0 +1,40 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func buildMetricClassesCR() *v1.Observability {
+	return &v1.Observability{
+		Spec: v1.ObservabilitySpec{
+			Retention: "45d",
+			MetricClasses: []v1.MetricClass{
+				{Name: "histograms", Selectors: []v1.MetricClassSelector{{Regex: ".+_bucket"}}},
+				{Name: "empty", Selectors: []v1.MetricClassSelector{{Regex: ".*"}}},
+				{Name: "histograms", Selectors: []v1.MetricClassSelector{{Regex: "apiserver_.*"}}},
+			},
+		},
+	}
+}
+
+func TestMetricClasses_GetValidMetricClasses(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Invalid classes and duplicate names are skipped
+	classes, errs := getValidMetricClasses(buildMetricClassesCR())
+	Expect(classes).To(HaveLen(1))
+	Expect(classes[0].Name).To(Equal("histograms"))
+	Expect(classes[0].Selectors[0].Regex).To(Equal(".+_bucket"))
+	Expect(errs).To(HaveLen(2))
+
+	remoteWrite := &prometheusv1.RemoteWriteSpec{
+		WriteRelabelConfigs: []prometheusv1.RelabelConfig{{Action: "labeldrop", Regex: "prometheus_replica"}},
+	}
+	applyMetricClasses(buildMetricClassesCR(), remoteWrite)
+	Expect(remoteWrite.WriteRelabelConfigs).To(HaveLen(2))
+	Expect(remoteWrite.WriteRelabelConfigs[1].Regex).To(Equal(";(?:.+_bucket)"))
+}
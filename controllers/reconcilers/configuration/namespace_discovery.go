@@ -0,0 +1,56 @@
+package configuration
+
+import (
+	"context"
+	"sort"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Label selectors can't be combined with a logical or, so the namespaces of the selector are resolved
+// and selected by name together with the discovered namespaces. Without a selector Prometheus only
+// selects its own namespace, an empty selector selects all namespaces already.
+func (r *Reconciler) getDiscoveryNamespaceSelector(ctx context.Context, cr *v1.Observability, selector *metav1.LabelSelector) (*metav1.LabelSelector, error) {
+	if !cr.NamespaceDiscoveryEnabled() || len(cr.Status.DiscoveredNamespaces) == 0 {
+		return selector, nil
+	}
+
+	namespaces := []string{cr.GetPrometheusOperatorNamespace()}
+	if selector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return nil, err
+		}
+		if labelSelector.Empty() {
+			return selector, nil
+		}
+
+		list := &core.NamespaceList{}
+		err = r.client.List(ctx, list, &client.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return nil, err
+		}
+		namespaces = nil
+		for _, namespace := range list.Items {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+	return model.GetNamespaceNameSelector(mergeNamespaces(namespaces, cr.Status.DiscoveredNamespaces)), nil
+}
+
+func mergeNamespaces(namespaces []string, additional []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, namespace := range append(append([]string{}, namespaces...), additional...) {
+		if !seen[namespace] {
+			seen[namespace] = true
+			result = append(result, namespace)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,50 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	kv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNamespaceDiscovery_GetDiscoveryNamespaceSelector(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+
+	labelled := &kv1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kafka", Labels: map[string]string{"monitoring": "true"}}}
+	r := &Reconciler{client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(labelled).Build()}
+
+	enabled := true
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability"}}
+	cr.Spec.NamespaceDiscovery = &enabled
+	cr.Status.DiscoveredNamespaces = []string{"payments"}
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"monitoring": "true"}}
+	result, err := r.getDiscoveryNamespaceSelector(context.TODO(), cr, selector)
+	Expect(err).To(BeNil())
+	Expect(result).To(Equal(model.GetNamespaceNameSelector([]string{"kafka", "payments"})))
+
+	// Prometheus selects its own namespace without a selector
+	result, err = r.getDiscoveryNamespaceSelector(context.TODO(), cr, nil)
+	Expect(err).To(BeNil())
+	Expect(result).To(Equal(model.GetNamespaceNameSelector(mergeNamespaces([]string{cr.GetPrometheusOperatorNamespace()}, []string{"payments"}))))
+
+	// All namespaces are selected already
+	result, err = r.getDiscoveryNamespaceSelector(context.TODO(), cr, &metav1.LabelSelector{})
+	Expect(err).To(BeNil())
+	Expect(result).To(Equal(&metav1.LabelSelector{}))
+
+	// The selector is kept with target namespaces
+	cr.Spec.TargetNamespaces = []string{"kafka"}
+	result, err = r.getDiscoveryNamespaceSelector(context.TODO(), cr, selector)
+	Expect(err).To(BeNil())
+	Expect(result).To(Equal(selector))
+}
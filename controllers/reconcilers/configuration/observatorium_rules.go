@@ -0,0 +1,199 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	token2 "github.com/redhat-developer/observability-operator/v4/controllers/token"
+	kv1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Rule file in the format of the Rules API
+type ObservatoriumRuleFile struct {
+	Groups []v12.RuleGroup `json:"groups"`
+}
+
+// Push the rules of every index to the Rules API of the Observatorium instance that the index writes
+// its metrics to. The API replaces all rules of the tenant, so the rules of all indexes writing to the
+// same instance are pushed together. The pushed rules are recorded in the status and only pushed again
+// when they change. Nothing is pushed in dry run mode.
+func (r *Reconciler) reconcileObservatoriumRules(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, rules map[string][]*v12.PrometheusRule, s *v1.ObservabilityStatus) error {
+	if cr.DryRunEnabled() {
+		return nil
+	}
+
+	pushed := map[string]v1.PushedRules{}
+	for _, status := range s.ObservatoriumRules {
+		pushed[status.Observatorium] = status
+	}
+
+	observatoria, files := getObservatoriumRuleFiles(indexes, rules)
+
+	// When switching back to in-cluster rules, the pushed rules are removed from the tenants they were
+	// pushed to
+	if !cr.ObservatoriumRulesEnabled() {
+		for id := range files {
+			status, ok := pushed[id]
+			if !ok || status.Tenant != observatoria[id].Tenant {
+				delete(files, id)
+				continue
+			}
+			files[id] = &ObservatoriumRuleFile{Groups: []v12.RuleGroup{}}
+		}
+	}
+
+	var failed []string
+	for _, id := range sortedRuleFileIds(files) {
+		observatorium := observatoria[id]
+		content, err := yaml.Marshal(files[id])
+		if err != nil {
+			return err
+		}
+
+		hash := fmt.Sprintf("%x", sha256.Sum256(content))
+		if status, ok := pushed[id]; ok && status.Hash == hash && status.Tenant == observatorium.Tenant {
+			continue
+		}
+
+		err = r.pushObservatoriumRules(ctx, cr, observatorium, content)
+		if err != nil {
+			r.logger.Error(err, "error pushing rules to observatorium", "observatorium", id)
+			failed = append(failed, id)
+			continue
+		}
+		if cr.ObservatoriumRulesEnabled() {
+			pushed[id] = v1.PushedRules{Observatorium: id, Tenant: observatorium.Tenant, Hash: hash}
+		} else {
+			delete(pushed, id)
+		}
+	}
+
+	// Instances that no index refers to anymore can't be reached
+	s.ObservatoriumRules = nil
+	for _, id := range sortedPushedRulesIds(pushed) {
+		if observatoria[id] != nil {
+			s.ObservatoriumRules = append(s.ObservatoriumRules, pushed[id])
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to push rules to observatoria %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (r *Reconciler) pushObservatoriumRules(ctx context.Context, cr *v1.Observability, observatorium *v1.ObservatoriumIndex, content []byte) error {
+	bearer, err := r.getObservatoriumToken(ctx, cr, observatorium)
+	if err != nil {
+		return err
+	}
+
+	gateway := model.GetObservatoriumGateway(r.activeGateways, observatorium)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, model.GetObservatoriumRulesUrl(gateway, observatorium.Tenant), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", bearer))
+	req.Header.Set("Content-Type", "application/yaml")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected status code from rules api of %v: %v", observatorium.Id, resp.StatusCode)
+	}
+	return nil
+}
+
+// Dex tokens are kept up to date by the token stage, Red Hat SSO tokens are fetched for every push
+func (r *Reconciler) getObservatoriumToken(ctx context.Context, cr *v1.Observability, observatorium *v1.ObservatoriumIndex) (string, error) {
+	switch observatorium.AuthType {
+	case v1.AuthTypeDex:
+		secret := &kv1.Secret{}
+		selector := client.ObjectKey{
+			Namespace: cr.Namespace,
+			Name:      token.GetObservatoriumTokenSecretName(observatorium),
+		}
+		err := r.client.Get(ctx, selector, secret)
+		if err != nil {
+			return "", err
+		}
+		return string(secret.Data[token.RemoteTokenValue]), nil
+	case v1.AuthTypeRedhat:
+		fetcher := &token2.RedhatSsoTokenFetcher{HttpClient: r.httpClient}
+		bearer, _, err := fetcher.Fetch(cr, observatorium, "")
+		if err == nil && bearer == "" {
+			err = fmt.Errorf("no metrics client configured for %v", observatorium.Id)
+		}
+		return bearer, err
+	default:
+		return "", fmt.Errorf("rules api not supported for auth type %v", observatorium.AuthType)
+	}
+}
+
+// Rule files by Observatorium instance. Group names are prefixed with the name of the rule, because
+// the groups of multiple rules end up in the same file.
+func getObservatoriumRuleFiles(indexes []v1.RepositoryIndex, rules map[string][]*v12.PrometheusRule) (map[string]*v1.ObservatoriumIndex, map[string]*ObservatoriumRuleFile) {
+	observatoria := map[string]*v1.ObservatoriumIndex{}
+	files := map[string]*ObservatoriumRuleFile{}
+	for i := range indexes {
+		index := &indexes[i]
+		if index.Config == nil || index.Config.Prometheus == nil || index.Config.Prometheus.Observatorium == "" {
+			continue
+		}
+
+		observatorium := token.GetObservatoriumConfig(index, index.Config.Prometheus.Observatorium)
+		if observatorium == nil || !observatorium.IsValid() {
+			continue
+		}
+
+		observatoria[observatorium.Id] = observatorium
+		if files[observatorium.Id] == nil {
+			files[observatorium.Id] = &ObservatoriumRuleFile{Groups: []v12.RuleGroup{}}
+		}
+
+		indexRules := rules[index.Id]
+		sort.Slice(indexRules, func(i, j int) bool {
+			return indexRules[i].Name < indexRules[j].Name
+		})
+		for _, rule := range indexRules {
+			for _, group := range rule.Spec.Groups {
+				group.Name = fmt.Sprintf("%v/%v", rule.Name, group.Name)
+				files[observatorium.Id].Groups = append(files[observatorium.Id].Groups, group)
+			}
+		}
+	}
+	return observatoria, files
+}
+
+func sortedRuleFileIds(files map[string]*ObservatoriumRuleFile) []string {
+	var result []string
+	for id := range files {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedPushedRulesIds(pushed map[string]v1.PushedRules) []string {
+	var result []string
+	for id := range pushed {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,161 @@
+package configuration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	kv1 "k8s.io/api/core/v1"
+	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestObservatoriumRules_GetObservatoriumRuleFiles(t *testing.T) {
+	observatorium := v1.ObservatoriumIndex{
+		Id:       "default",
+		Gateway:  testGateway,
+		Tenant:   testTenant,
+		AuthType: v1.AuthTypeDex,
+	}
+	getIndex := func(id string) v1.RepositoryIndex {
+		return v1.RepositoryIndex{
+			Id: id,
+			Config: &v1.RepositoryConfig{
+				Prometheus:   &v1.PrometheusIndex{Observatorium: "default"},
+				Observatoria: []v1.ObservatoriumIndex{observatorium},
+			},
+		}
+	}
+	getRule := func(name string, groups ...string) *v12.PrometheusRule {
+		rule := &v12.PrometheusRule{ObjectMeta: v14.ObjectMeta{Name: name}}
+		for _, group := range groups {
+			rule.Spec.Groups = append(rule.Spec.Groups, v12.RuleGroup{Name: group})
+		}
+		return rule
+	}
+
+	type args struct {
+		indexes []v1.RepositoryIndex
+		rules   map[string][]*v12.PrometheusRule
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want map[string]*ObservatoriumRuleFile
+	}{
+		{
+			name: "rules of all indexes writing to the same observatorium are combined",
+			args: args{
+				indexes: []v1.RepositoryIndex{getIndex("kafka"), getIndex("connectors")},
+				rules: map[string][]*v12.PrometheusRule{
+					"kafka":      {getRule("kafka-rules-b", "general"), getRule("kafka-rules-a", "general", "slo")},
+					"connectors": {getRule("connectors-rules", "general")},
+				},
+			},
+			want: map[string]*ObservatoriumRuleFile{
+				"default": {
+					Groups: []v12.RuleGroup{
+						{Name: "kafka-rules-a/general"},
+						{Name: "kafka-rules-a/slo"},
+						{Name: "kafka-rules-b/general"},
+						{Name: "connectors-rules/general"},
+					},
+				},
+			},
+		},
+		{
+			name: "empty rule file to remove the rules of an index without rules",
+			args: args{
+				indexes: []v1.RepositoryIndex{getIndex("kafka")},
+				rules:   map[string][]*v12.PrometheusRule{},
+			},
+			want: map[string]*ObservatoriumRuleFile{
+				"default": {Groups: []v12.RuleGroup{}},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, files := getObservatoriumRuleFiles(tt.args.indexes, tt.args.rules)
+			Expect(files).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestObservatoriumRules_ReconcileObservatoriumRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	var pushed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		Expect(req.Method).To(Equal(http.MethodPut))
+		Expect(req.URL.Path).To(Equal("/api/metrics/v1/" + testTenant + "/api/v1/rules/raw"))
+		body, _ := io.ReadAll(req.Body)
+		pushed = append(pushed, string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	observatorium := v1.ObservatoriumIndex{Id: "default", Gateway: server.URL, Tenant: testTenant, AuthType: v1.AuthTypeDex}
+	indexes := []v1.RepositoryIndex{{
+		Id: "kafka",
+		Config: &v1.RepositoryConfig{
+			Prometheus:   &v1.PrometheusIndex{Observatorium: "default"},
+			Observatoria: []v1.ObservatoriumIndex{observatorium},
+		},
+	}}
+	rules := map[string][]*v12.PrometheusRule{
+		"kafka": {{ObjectMeta: v14.ObjectMeta{Name: "kafka-rules"}, Spec: v12.PrometheusRuleSpec{Groups: []v12.RuleGroup{{Name: "general"}}}}},
+	}
+	cr := &v1.Observability{ObjectMeta: v14.ObjectMeta{Namespace: "observability"}}
+	cr.Spec.RuleDestination = v1.RuleDestinationObservatorium
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(&kv1.Secret{
+		ObjectMeta: v14.ObjectMeta{Name: token.GetObservatoriumTokenSecretName(&observatorium), Namespace: "observability"},
+		Data:       map[string][]byte{token.RemoteTokenValue: []byte("test-token")},
+	}).Build()
+	newReconciler := func(c client.Client) *Reconciler {
+		return &Reconciler{client: c, logger: logr.Discard(), httpClient: server.Client()}
+	}
+
+	status := &v1.ObservabilityStatus{}
+	Expect(newReconciler(c).reconcileObservatoriumRules(context.TODO(), cr, indexes, rules, status)).To(Succeed())
+	Expect(pushed).To(HaveLen(1))
+	Expect(pushed[0]).To(ContainSubstring("kafka-rules/general"))
+	Expect(status.ObservatoriumRules).To(HaveLen(1))
+	Expect(status.ObservatoriumRules[0].Tenant).To(Equal(testTenant))
+
+	// The reconciler of the next sync finds the pushed rules in the status
+	Expect(newReconciler(c).reconcileObservatoriumRules(context.TODO(), cr, indexes, rules, status)).To(Succeed())
+	Expect(pushed).To(HaveLen(1))
+
+	// Nothing is pushed in dry run mode
+	enabled := true
+	cr.Spec.DryRun = &enabled
+	cr.Spec.RuleDestination = v1.RuleDestinationCluster
+	Expect(newReconciler(c).reconcileObservatoriumRules(context.TODO(), cr, indexes, rules, status)).To(Succeed())
+	Expect(pushed).To(HaveLen(1))
+
+	// Switching back to in-cluster rules removes the pushed rules
+	cr.Spec.DryRun = nil
+	Expect(newReconciler(c).reconcileObservatoriumRules(context.TODO(), cr, indexes, rules, status)).To(Succeed())
+	Expect(pushed).To(HaveLen(2))
+	Expect(pushed[1]).To(Equal("groups: []\n"))
+	Expect(status.ObservatoriumRules).To(BeEmpty())
+
+	Expect(newReconciler(c).reconcileObservatoriumRules(context.TODO(), cr, indexes, rules, status)).To(Succeed())
+	Expect(pushed).To(HaveLen(2))
+}
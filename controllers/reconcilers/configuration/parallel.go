@@ -0,0 +1,97 @@
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Content and resource version of a resource applied by a previous sync, kept in the sync state
+type appliedResource struct {
+	hash            string
+	resourceVersion string
+}
+
+// Call fn for 0..n-1 with at most workers calls at a time. All calls run to completion, the error
+// of the lowest index is returned, the same one a sequential sweep would have stopped at.
+func runParallel(n int, workers int, fn func(i int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, n)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create or update a resource of an index unless the same content was applied to the current
+// version of the resource before. The resource is read from the cache, so unchanged resources are
+// not applied again. Resources changed by someone else have a new resource version and
+// are applied again. Dry runs always compare the resource, so that every pending change is reported.
+func (r *Reconciler) createOrUpdateIndexResource(ctx context.Context, cr *v1.Observability, obj client.Object, requested interface{}, mutate controllerutil.MutateFn) error {
+	content, err := json.Marshal(requested)
+	if err != nil {
+		return err
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256(content))
+	key := fmt.Sprintf("%T/%v/%v", obj, obj.GetNamespace(), obj.GetName())
+
+	state := r.getSyncState()
+	state.mu.Lock()
+	applied, ok := state.appliedResources[key]
+	state.mu.Unlock()
+
+	if ok && applied.hash == hash && !cr.DryRunEnabled() {
+		existing := obj.DeepCopyObject().(client.Object)
+		err = r.client.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && existing.GetResourceVersion() == applied.resourceVersion {
+			r.logger.V(2).Info("index resource unchanged", "kind", fmt.Sprintf("%T", obj), "namespace", obj.GetNamespace(), "name", obj.GetName())
+			return nil
+		}
+	}
+
+	result, err := utils.Apply(ctx, r.client, obj, mutate)
+	if err != nil {
+		return err
+	}
+	r.logger.V(1).Info("index resource applied", "kind", fmt.Sprintf("%T", obj), "namespace", obj.GetNamespace(), "name", obj.GetName(), "result", result)
+
+	if !cr.DryRunEnabled() {
+		state.mu.Lock()
+		state.appliedResources[key] = appliedResource{hash: hash, resourceVersion: obj.GetResourceVersion()}
+		state.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	kv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParallel_RunParallel(t *testing.T) {
+	RegisterTestingT(t)
+
+	var calls int32
+	err := runParallel(10, 3, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 4 || i == 7 {
+			return fmt.Errorf("error %v", i)
+		}
+		return nil
+	})
+
+	// Every call completes, the error of the lowest index is returned
+	Expect(calls).To(Equal(int32(10)))
+	Expect(err).To(MatchError("error 4"))
+
+	Expect(runParallel(0, 4, func(i int) error { return fmt.Errorf("not called") })).To(Succeed())
+}
+
+func TestParallel_CreateOrUpdateIndexResource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	r := &Reconciler{logger: logr.Discard(), client: testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())}
+	cr := &v1.Observability{}
+
+	mutations := 0
+	apply := func(cr *v1.Observability, value string) {
+		configMap := &kv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: "observability"}}
+		err := r.createOrUpdateIndexResource(context.TODO(), cr, configMap, value, func() error {
+			mutations++
+			configMap.Data = map[string]string{"key": value}
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	RegisterTestingT(t)
+	apply(cr, "a")
+	Expect(mutations).To(Equal(1))
+
+	// Unchanged resources are skipped
+	apply(cr, "a")
+	Expect(mutations).To(Equal(1))
+
+	// Changed content is applied
+	apply(cr, "b")
+	Expect(mutations).To(Equal(2))
+
+	// Resources changed by someone else are applied again
+	configMap := &kv1.ConfigMap{}
+	Expect(r.client.Get(context.TODO(), client.ObjectKey{Namespace: "observability", Name: "rules"}, configMap)).To(Succeed())
+	configMap.Data = map[string]string{"key": "edited"}
+	Expect(r.client.Update(context.TODO(), configMap)).To(Succeed())
+	apply(cr, "b")
+	Expect(mutations).To(Equal(3))
+
+	// Dry runs compare every resource
+	dryRun := cr.DeepCopy()
+	enabled := true
+	dryRun.Spec.DryRun = &enabled
+	apply(dryRun, "b")
+	Expect(mutations).To(Equal(4))
+}
+
+// The reconcilers are created for every reconcile, the applied resources are kept in the shared sync state
+func TestParallel_CreateOrUpdateIndexResourceSharedState(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	c := testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())
+	state := NewSyncState()
+
+	mutations := 0
+	for i := 0; i < 2; i++ {
+		r := NewReconciler(c, logr.Discard(), nil, state).(*Reconciler)
+		configMap := &kv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: "observability"}}
+		err := r.createOrUpdateIndexResource(context.TODO(), &v1.Observability{}, configMap, "a", func() error {
+			mutations++
+			configMap.Data = map[string]string{"key": "a"}
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(mutations).To(Equal(1))
+}
+
+// The workers share the dry run client, no change is lost. Run with -race.
+func TestParallel_DryRunClient(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	dryRunClient := utils.NewDryRunClient(testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build()))
+
+	err := runParallel(50, 8, func(i int) error {
+		configMap := &kv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rules-%v", i), Namespace: "observability"}}
+		_, err := utils.Apply(context.TODO(), dryRunClient, configMap, func() error {
+			configMap.Data = map[string]string{"key": "value"}
+			return nil
+		})
+		return err
+	})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(dryRunClient.GetChanges()).To(HaveLen(50))
+}
@@ -0,0 +1,105 @@
+package configuration
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors;alertmanagers;prometheuses;prometheuses/finalizers;alertmanagers/finalizers;servicemonitors;prometheusrules;thanosrulers;thanosrulers/finalizers,verbs=get;list;create;update;patch;delete;watch
+// +kubebuilder:rbac:groups=integreatly.org,resources=grafanas;grafanadashboards;grafanadatasources;grafanafolders,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps;services;persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.openshift.io,resources=ingresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces;nodes;persistentvolumeclaims;persistentvolumes;pods;services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;replicasets;statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs;jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	namespace := cr.GetPrometheusOperatorNamespace()
+	result := reconcilers.NewPermissions("", []string{"secrets", "configmaps"}, reconcilers.ManageVerbs, cr.Namespace)
+	result = append(result, reconcilers.NewPermissions("", []string{"secrets", "configmaps", "services"}, reconcilers.ManageVerbs, namespace)...)
+	result = append(result, reconcilers.NewPermissions("monitoring.coreos.com", []string{"prometheuses", "alertmanagers", "prometheusrules", "podmonitors"}, reconcilers.ManageVerbs, namespace)...)
+	result = append(result, reconcilers.NewPermissions("apps", []string{"deployments", "statefulsets"}, reconcilers.ManageVerbs, namespace)...)
+	result = append(result, reconcilers.NewPermissions("networking.k8s.io", []string{"networkpolicies"}, reconcilers.ManageVerbs, namespace)...)
+
+	if !cr.DescopedModeEnabled() {
+		result = append(result, reconcilers.NewPermissions("integreatly.org", []string{"grafanas", "grafanadashboards", "grafanadatasources", "grafanafolders"}, reconcilers.ManageVerbs, cr.Namespace)...)
+	}
+	if !cr.ObservatoriumDisabled() && !cr.ExternalSyncDisabled() {
+		result = append(result, reconcilers.NewPermissions("apps", []string{"daemonsets"}, reconcilers.ManageVerbs, cr.Namespace)...)
+	}
+	if cr.ExternalPrometheusEnabled() {
+		result = append(result, reconcilers.NewPermissions("monitoring.coreos.com", []string{"prometheuses"}, reconcilers.ReadVerbs, model.GetExternalPrometheus(cr).Namespace)...)
+	}
+	if cr.PrometheusAutoResizeEnabled() {
+		result = append(result, reconcilers.NewPermissions("", []string{"persistentvolumeclaims"}, reconcilers.ManageVerbs, namespace)...)
+		result = append(result, reconcilers.NewPermissions("storage.k8s.io", []string{"storageclasses"}, reconcilers.ReadVerbs, "")...)
+	}
+	if cr.GetCredentialProviderType() == v1.CredentialProviderExternalSecrets {
+		result = append(result, reconcilers.NewPermissions("external-secrets.io", []string{"externalsecrets"}, reconcilers.ManageVerbs, cr.Namespace)...)
+	}
+	if cr.CertManagerEnabled() {
+		result = append(result, reconcilers.NewPermissions("cert-manager.io", []string{"certificates"}, reconcilers.ManageVerbs, namespace)...)
+		result = append(result, reconcilers.NewPermissions("cert-manager.io", []string{"certificates"}, reconcilers.ManageVerbs, cr.Namespace)...)
+	}
+	if cr.GetGrafanaAuthenticationType() == v1.GrafanaAuthenticationOpenShift {
+		result = append(result, reconcilers.NewPermissions("config.openshift.io", []string{"ingresses"}, reconcilers.ReadVerbs, "")...)
+		result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"clusterrolebindings"}, reconcilers.ReadVerbs, "")...)
+	}
+	// The cluster role of the event exporter can only grant what the operator has itself
+	if cr.EventExporterEnabled() {
+		result = append(result, reconcilers.NewPermissions("", []string{"serviceaccounts", "services"}, reconcilers.ManageVerbs, namespace)...)
+		result = append(result, reconcilers.NewPermissions("apps", []string{"deployments"}, reconcilers.ManageVerbs, namespace)...)
+		result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"clusterroles", "clusterrolebindings"}, reconcilers.ManageVerbs, "")...)
+		result = append(result, reconcilers.NewPermissions("", []string{"events"}, reconcilers.ReadVerbs, "")...)
+	}
+	// Monitors of all namespaces are listed to revert removed budgets
+	if len(cr.Spec.NamespaceScrapeBudgets) > 0 {
+		monitorNamespaces := []string{""}
+		if cr.NamespaceScoped() {
+			monitorNamespaces = model.GetTargetNamespaces(cr)
+		}
+		for _, monitorNamespace := range monitorNamespaces {
+			result = append(result, reconcilers.NewPermissions("monitoring.coreos.com", []string{"servicemonitors", "podmonitors"}, reconcilers.ReadVerbs, monitorNamespace)...)
+		}
+		for _, budget := range cr.Spec.NamespaceScrapeBudgets {
+			result = append(result, reconcilers.NewPermissions("monitoring.coreos.com", []string{"servicemonitors", "podmonitors"}, []string{"update"}, budget.Namespace)...)
+		}
+	}
+	// Dashboards of the tenants
+	for _, tenant := range cr.Status.Tenants {
+		if len(tenant.Dashboards) > 0 {
+			result = append(result, reconcilers.NewPermissions("", []string{"configmaps"}, reconcilers.ReadVerbs, tenant.Namespace)...)
+		}
+	}
+	// Certificates of the routes and the additional CA bundle of the cluster proxy
+	if !cr.IsKubernetesCluster() {
+		result = append(result, reconcilers.NewPermissions("route.openshift.io", []string{"routes"}, reconcilers.ReadVerbs, namespace)...)
+		result = append(result, reconcilers.NewPermissions("route.openshift.io", []string{"routes"}, reconcilers.ReadVerbs, cr.Namespace)...)
+	}
+	if source := model.GetProxyCABundle(r.clusterProxy); source != nil {
+		result = append(result, reconcilers.NewPermissions("", []string{"configmaps"}, reconcilers.ReadVerbs, source.Namespace)...)
+	}
+	// The cluster role of kube-state-metrics can only grant what the operator has itself
+	if cr.InfrastructureExportersEnabled() {
+		result = append(result, reconcilers.NewPermissions("", []string{"serviceaccounts", "services"}, reconcilers.ManageVerbs, namespace)...)
+		result = append(result, reconcilers.NewPermissions("apps", []string{"deployments", "daemonsets"}, reconcilers.ManageVerbs, namespace)...)
+		result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"clusterroles", "clusterrolebindings"}, reconcilers.ManageVerbs, "")...)
+		for _, rule := range model.GetKubeStateMetricsClusterRoleRules() {
+			result = append(result, reconcilers.NewPermissions(rule.APIGroups[0], rule.Resources, reconcilers.ReadVerbs, "")...)
+		}
+	}
+	return result
+}
@@ -7,8 +7,8 @@ import (
 	"github.com/ghodss/yaml"
 	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func MergeLabels(requested map[string]string, existing map[string]string) map[string]string {
@@ -42,6 +42,12 @@ func getUniquePodMonitors(indexes []v1.RepositoryIndex) []ResourceInfo {
 				Url:         fmt.Sprintf("%s/%s", index.BaseUrl, monitor),
 				AccessToken: index.AccessToken,
 				Tag:         index.Tag,
+				Limits: v1.ScrapeLimits{
+					SampleLimit:          index.Config.Prometheus.SampleLimit,
+					LabelLimit:           index.Config.Prometheus.LabelLimit,
+					LabelNameLengthLimit: index.Config.Prometheus.LabelNameLengthLimit,
+					TargetLimit:          index.Config.Prometheus.TargetLimit,
+				},
 			})
 		}
 	}
@@ -83,8 +89,9 @@ func (r *Reconciler) deleteUnrequestedPodMonitors(cr *v1.Observability, ctx cont
 
 func (r *Reconciler) createRequestedPodMonitors(cr *v1.Observability, ctx context.Context, monitors []ResourceInfo) error {
 	// Sync requested pod monitors
-	for _, resource := range monitors {
-		bytes, err := r.fetchResource(resource.Url, resource.Tag, resource.AccessToken)
+	return runParallel(len(monitors), model.GetResourceSyncWorkers(cr), func(i int) error {
+		resource := monitors[i]
+		bytes, err := r.fetchResource(ctx, resource.Url, resource.Tag, resource.AccessToken)
 		if err != nil {
 			return err
 		}
@@ -94,21 +101,36 @@ func (r *Reconciler) createRequestedPodMonitors(cr *v1.Observability, ctx contex
 			return err
 		}
 
-		requestedLabels := monitor.Labels
-		requestedSpec := monitor.Spec
+		r.trackResource(ManagedKindPodMonitor, monitor, resource.Id)
+		requestedLabels := MergeLabels(map[string]string{
+			"managed-by":              "observability-operator",
+			ManagedResourceIndexLabel: resource.Id,
+		}, monitor.Labels)
+		requestedSpec := applyPodMonitorLimits(monitor.Spec, resource.Limits)
 
-		_, err = controllerutil.CreateOrUpdate(ctx, r.client, monitor, func() error {
+		return r.createOrUpdateIndexResource(ctx, cr, monitor, []interface{}{requestedSpec, requestedLabels}, func() error {
 			monitor.Spec = requestedSpec
-			monitor.Labels = MergeLabels(map[string]string{
-				"managed-by": "observability-operator",
-			}, requestedLabels)
+			monitor.Labels = requestedLabels
 			return nil
 		})
-		if err != nil {
-			return err
-		}
+	})
+}
+
+// Limits of the index override the limits of the pod monitor
+func applyPodMonitorLimits(spec v12.PodMonitorSpec, limits v1.ScrapeLimits) v12.PodMonitorSpec {
+	if limits.SampleLimit > 0 {
+		spec.SampleLimit = limits.SampleLimit
 	}
-	return nil
+	if limits.LabelLimit > 0 {
+		spec.LabelLimit = limits.LabelLimit
+	}
+	if limits.LabelNameLengthLimit > 0 {
+		spec.LabelNameLengthLimit = limits.LabelNameLengthLimit
+	}
+	if limits.TargetLimit > 0 {
+		spec.TargetLimit = limits.TargetLimit
+	}
+	return spec
 }
 
 func parsePodMonitorFromYaml(cr *v1.Observability, name string, source []byte) (*v12.PodMonitor, error) {
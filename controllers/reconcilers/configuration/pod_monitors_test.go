@@ -146,3 +146,57 @@ func TestPodMonitors_ParsePodMonitorFromYaml(t *testing.T) {
 		})
 	}
 }
+
+func TestPodMonitors_ApplyPodMonitorLimits(t *testing.T) {
+	type args struct {
+		spec   v12.PodMonitorSpec
+		limits v1.ScrapeLimits
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want v12.PodMonitorSpec
+	}{
+		{
+			name: "limits of the index override the pod monitor",
+			args: args{
+				spec: v12.PodMonitorSpec{
+					SampleLimit: 1000,
+					LabelLimit:  30,
+				},
+				limits: v1.ScrapeLimits{
+					SampleLimit:          500,
+					LabelNameLengthLimit: 100,
+					TargetLimit:          10,
+				},
+			},
+			want: v12.PodMonitorSpec{
+				SampleLimit:          500,
+				LabelLimit:           30,
+				LabelNameLengthLimit: 100,
+				TargetLimit:          10,
+			},
+		},
+		{
+			name: "pod monitor unchanged without limits",
+			args: args{
+				spec: v12.PodMonitorSpec{
+					SampleLimit: 1000,
+				},
+				limits: v1.ScrapeLimits{},
+			},
+			want: v12.PodMonitorSpec{
+				SampleLimit: 1000,
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := applyPodMonitorLimits(tt.args.spec, tt.args.limits)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
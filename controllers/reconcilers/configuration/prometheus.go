@@ -9,12 +9,13 @@ import (
 	errors2 "github.com/pkg/errors"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
 	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
-	"github.com/sirupsen/logrus"
 	kv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -23,11 +24,10 @@ import (
 )
 
 const (
-	PrometheusBaseImage = "quay.io/prometheus/prometheus"
 	PrometheusRetention = "45d"
 )
 
-func (r *Reconciler) fetchFederationConfigs(cr *v1.Observability, indexes []v1.RepositoryIndex) ([]string, error) {
+func (r *Reconciler) fetchFederationConfigs(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) ([]string, error) {
 	var result []string
 
 	type federationPatterns struct {
@@ -54,7 +54,7 @@ func (r *Reconciler) fetchFederationConfigs(cr *v1.Observability, indexes []v1.R
 		}
 
 		federationConfigUrl := fmt.Sprintf("%s/%s", index.BaseUrl, index.Config.Prometheus.Federation)
-		bytes, err := r.fetchResource(federationConfigUrl, index.Tag, index.AccessToken)
+		bytes, err := r.fetchResource(ctx, federationConfigUrl, index.Tag, index.AccessToken)
 		if err != nil {
 			return nil, err
 		}
@@ -75,6 +75,88 @@ func (r *Reconciler) fetchFederationConfigs(cr *v1.Observability, indexes []v1.R
 	return result, nil
 }
 
+// Upstreams from the CR and all indexes, the job names of index upstreams are prefixed with the index id
+func getFederationUpstreams(cr *v1.Observability, indexes []v1.RepositoryIndex) []v1.FederationUpstream {
+	var result []v1.FederationUpstream
+	result = append(result, cr.Spec.FederationUpstreams...)
+
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Prometheus == nil {
+			continue
+		}
+
+		for _, upstream := range index.Config.Prometheus.FederationUpstreams {
+			upstream.Name = fmt.Sprintf("%s-%s", index.Id, upstream.Name)
+			result = append(result, upstream)
+		}
+	}
+
+	return result
+}
+
+// Remote read targets from the CR and all indexes, the names of index targets are prefixed with the index id
+func getRemoteReadTargets(cr *v1.Observability, indexes []v1.RepositoryIndex) []v1.RemoteReadTarget {
+	var result []v1.RemoteReadTarget
+	if cr.Spec.SelfContained != nil {
+		result = append(result, cr.Spec.SelfContained.RemoteRead...)
+	}
+
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Prometheus == nil {
+			continue
+		}
+
+		for _, target := range index.Config.Prometheus.RemoteRead {
+			target.Name = fmt.Sprintf("%s-%s", index.Id, target.Name)
+			result = append(result, target)
+		}
+	}
+
+	return result
+}
+
+// Static targets from the CR and all indexes, the job names of index targets are prefixed with the index id
+// and those of hosted control plane endpoints with hosted
+func getStaticTargets(cr *v1.Observability, indexes []v1.RepositoryIndex) []v1.StaticTargetGroup {
+	var result []v1.StaticTargetGroup
+	result = append(result, cr.Spec.StaticTargets...)
+
+	for _, index := range indexes {
+		if index.Config == nil || index.Config.Prometheus == nil {
+			continue
+		}
+
+		for _, group := range index.Config.Prometheus.StaticTargets {
+			group.Name = fmt.Sprintf("%s-%s", index.Id, group.Name)
+			result = append(result, group)
+		}
+	}
+
+	if cr.IsHostedControlPlane() && cr.Spec.HostedControlPlane != nil {
+		for _, group := range cr.Spec.HostedControlPlane.Endpoints {
+			group.Name = fmt.Sprintf("hosted-%s", group.Name)
+			result = append(result, group)
+		}
+	}
+
+	return result
+}
+
+// The ConfigMap is always created because it is mounted into Prometheus
+func (r *Reconciler) createStaticTargetsConfigMap(cr *v1.Observability, ctx context.Context, indexes []v1.RepositoryIndex) error {
+	configMap := model.GetPrometheusStaticTargetsConfigMap(cr)
+	files, err := model.GetStaticTargetsFileSD(getStaticTargets(cr, indexes))
+	if err != nil {
+		return err
+	}
+
+	_, err = utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Data = files
+		return nil
+	})
+	return err
+}
+
 func (r *Reconciler) createBlackBoxConfig(cr *v1.Observability, ctx context.Context) (string, error) {
 	configMap := model.GetPrometheusBlackBoxConfig(cr)
 	cfg, hash, err := model.GetDefaultBlackBoxConfig(cr, ctx, r.client)
@@ -82,7 +164,7 @@ func (r *Reconciler) createBlackBoxConfig(cr *v1.Observability, ctx context.Cont
 		return hash, err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, configMap, func() error {
+	_, err = utils.Apply(ctx, r.client, configMap, func() error {
 		configMap.Data = map[string]string{
 			"black-box-config.yaml": string(cfg),
 		}
@@ -91,19 +173,89 @@ func (r *Reconciler) createBlackBoxConfig(cr *v1.Observability, ctx context.Cont
 	return hash, err
 }
 
-// Write the additional scrape config secret, used to federate from openshift-monitoring
+// Write the additional scrape config secret, used to federate from openshift-monitoring, kube-prometheus
+// or the monitoring stack of a hosted control plane, or to scrape the managed infrastructure exporters
 // This expects the aggregation of all federation configs across all indexes
-func (r *Reconciler) createAdditionalScrapeConfigSecret(cr *v1.Observability, ctx context.Context, patterns []string) error {
+func (r *Reconciler) createAdditionalScrapeConfigSecret(cr *v1.Observability, ctx context.Context, indexes []v1.RepositoryIndex, patterns []string) error {
 	secret := model.GetPrometheusAdditionalScrapeConfig(cr)
-	federationConfig, err := model.GetFederationConfigBearerToken(patterns)
+	getFederationConfig := model.GetFederationConfigBearerToken
+	if cr.IsKubernetesCluster() {
+		getFederationConfig = model.GetFederationConfigKubePrometheus
+	}
+	if cr.IsHostedControlPlane() {
+		getFederationConfig = func(patterns []string) ([]byte, error) {
+			return model.GetFederationConfigUpstreams(model.GetHostedControlPlaneFederationUpstreams(cr, patterns))
+		}
+	}
+	if cr.FederationDisabled() {
+		getFederationConfig = func(patterns []string) ([]byte, error) {
+			return nil, nil
+		}
+	}
+	// The managed exporters replace kube-prometheus
+	if cr.InfrastructureExportersEnabled() {
+		getFederationConfig = func(patterns []string) ([]byte, error) {
+			return model.GetInfrastructureExportersScrapeConfig(cr), nil
+		}
+	}
+
+	federationConfig, err := getFederationConfig(patterns)
+	if err != nil {
+		return err
+	}
+
+	upstreamsConfig, err := model.GetFederationConfigUpstreams(getFederationUpstreams(cr, indexes))
+	if err != nil {
+		return err
+	}
+	federationConfig = append(federationConfig, upstreamsConfig...)
+
+	staticTargetsConfig, err := model.GetStaticTargetsScrapeConfig(cr, getStaticTargets(cr, indexes))
+	if err != nil {
+		return err
+	}
+	federationConfig = append(federationConfig, staticTargetsConfig...)
+
+	if cr.KubeletScrapingEnabled() {
+		federationConfig = append(federationConfig, model.GetKubeletScrapeConfig()...)
+	}
+
+	if cr.EventExporterEnabled() {
+		federationConfig = append(federationConfig, model.GetEventExporterScrapeConfig(cr)...)
+	}
+
+	if cr.CardinalityAnalysisEnabled() {
+		federationConfig = append(federationConfig, model.GetCardinalityScrapeConfig(cr)...)
+	}
+
+	if cr.QueryLogEnabled() {
+		federationConfig = append(federationConfig, model.GetQueryLogExporterScrapeConfig()...)
+	}
+
+	federationConfig = append(federationConfig, r.getOperatorScrapeConfig(cr)...)
+
+	additionalConfig, err := r.getAdditionalScrapeConfigs(ctx, cr, federationConfig)
 	if err != nil {
 		return err
 	}
+	federationConfig = append(federationConfig, additionalConfig...)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
+	alertmanagerConfig, err := model.GetExternalAlertmanagersConfig(cr.Spec.ExternalAlertmanagers)
+	if err != nil {
+		return err
+	}
+
+	alertRelabelConfig, err := r.getAlertRelabelConfig(indexes)
+	if err != nil {
+		return err
+	}
+
+	result, err := utils.Apply(ctx, r.client, secret, func() error {
 		secret.Type = kv1.SecretTypeOpaque
 		secret.StringData = map[string]string{
-			"additional-scrape-config.yaml": string(federationConfig),
+			"additional-scrape-config.yaml":       string(federationConfig),
+			model.AdditionalAlertmanagerConfigKey: string(alertmanagerConfig),
+			model.AdditionalAlertRelabelConfigKey: string(alertRelabelConfig),
 		}
 		return nil
 	})
@@ -112,12 +264,101 @@ func (r *Reconciler) createAdditionalScrapeConfigSecret(cr *v1.Observability, ct
 		return err
 	}
 
+	if result == controllerutil.OperationResultUpdated && !cr.DryRunEnabled() {
+		r.recordEvent(cr, kv1.EventTypeNormal, EventReasonScrapeConfigRotated,
+			"Rotated additional scrape config secret %v", secret.Name)
+	}
+
 	return nil
 }
 
-func (r *Reconciler) getRemoteWriteIndex(index v1.RepositoryIndex) (*v1.RemoteWriteIndex, error) {
+// User provided scrape configs from the CR and the referenced secret. Both have to be YAML lists of entries
+// with a job name that is not used by the generated scrape configs or another entry, Prometheus rejects
+// a configuration with duplicate jobs. The other fields are passed on as they are. The secret isn't watched,
+// its changes are applied by the next resync.
+func (r *Reconciler) getAdditionalScrapeConfigs(ctx context.Context, cr *v1.Observability, generated []byte) ([]byte, error) {
+	if cr.Spec.SelfContained == nil || cr.Spec.SelfContained.AdditionalScrapeConfigs == nil {
+		return nil, nil
+	}
+	additional := cr.Spec.SelfContained.AdditionalScrapeConfigs
+
+	var sources [][]byte
+	if additional.Raw != "" {
+		sources = append(sources, []byte(additional.Raw))
+	}
+
+	if additional.SecretRef != nil {
+		secret := &kv1.Secret{}
+		selector := client.ObjectKey{
+			Namespace: cr.Namespace,
+			Name:      additional.SecretRef.Name,
+		}
+		err := r.client.Get(ctx, selector, secret)
+		if err != nil {
+			return nil, errors2.Wrap(err, "error fetching additional scrape configs secret")
+		}
+
+		data, ok := secret.Data[additional.SecretRef.Key]
+		if !ok {
+			return nil, fmt.Errorf("additional scrape configs secret %v has no key %v", additional.SecretRef.Name, additional.SecretRef.Key)
+		}
+		sources = append(sources, data)
+	}
+
+	var generatedConfigs []map[string]interface{}
+	err := yaml.Unmarshal(generated, &generatedConfigs)
+	if err != nil {
+		return nil, errors2.Wrap(err, "error parsing generated scrape configs")
+	}
+	jobNames := map[string]bool{}
+	for _, config := range generatedConfigs {
+		if name, ok := config["job_name"].(string); ok {
+			jobNames[name] = true
+		}
+	}
+
+	var result []byte
+	for _, source := range sources {
+		config, err := normalizeScrapeConfigs(source, jobNames)
+		if err != nil {
+			return nil, errors2.Wrap(err, "error parsing additional scrape configs")
+		}
+		result = append(result, config...)
+	}
+
+	return result, nil
+}
+
+// Re-encode a YAML list of scrape configs, so that it can be appended to the generated list. The job
+// names of the entries are added to the ones in use.
+func normalizeScrapeConfigs(source []byte, jobNames map[string]bool) ([]byte, error) {
+	var scrapeConfigs []map[string]interface{}
+	err := yaml.Unmarshal(source, &scrapeConfigs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scrapeConfigs) == 0 {
+		return nil, nil
+	}
+
+	for i, config := range scrapeConfigs {
+		name, ok := config["job_name"].(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("scrape config %v has no job_name", i)
+		}
+		if jobNames[name] {
+			return nil, fmt.Errorf("scrape config %v: job %v is already in use", i, name)
+		}
+		jobNames[name] = true
+	}
+
+	return yaml.Marshal(scrapeConfigs)
+}
+
+func (r *Reconciler) getRemoteWriteIndex(ctx context.Context, index v1.RepositoryIndex) (*v1.RemoteWriteIndex, error) {
 	patternUrl := fmt.Sprintf("%s/%s", index.BaseUrl, index.Config.Prometheus.RemoteWrite)
-	bytes, err := r.fetchResource(patternUrl, index.Tag, index.AccessToken)
+	bytes, err := r.fetchResource(ctx, patternUrl, index.Tag, index.AccessToken)
 	if err != nil {
 		return nil, err
 	}
@@ -131,28 +372,84 @@ func (r *Reconciler) getRemoteWriteIndex(index v1.RepositoryIndex) (*v1.RemoteWr
 }
 
 // Send requests directly to observatorium
-func (r *Reconciler) getRemoteWriteSpecForDex(index v1.RepositoryIndex, observatoriumConfig *v1.ObservatoriumIndex, remoteWrite *v1.RemoteWriteIndex) (*prometheusv1.RemoteWriteSpec, string, error) {
+func (r *Reconciler) getRemoteWriteSpecForDex(cr *v1.Observability, index v1.RepositoryIndex, observatoriumConfig *v1.ObservatoriumIndex, remoteWrite *v1.RemoteWriteIndex) (*prometheusv1.RemoteWriteSpec, string, error) {
 	tokenSecret := token.GetObservatoriumPrometheusSecretName(&index)
+	gateway := model.GetObservatoriumGateway(r.activeGateways, observatoriumConfig)
+	url := fmt.Sprintf("%s/api/metrics/v1/%s/api/v1/receive", gateway, observatoriumConfig.Tenant)
+
+	proxyUrl := remoteWrite.ProxyUrl
+	if proxyUrl == "" {
+		proxyUrl = model.GetProxyUrlFor(r.clusterProxy, url)
+	}
+
 	return &prometheusv1.RemoteWriteSpec{
-		URL:                 fmt.Sprintf("%s/api/metrics/v1/%s/api/v1/receive", observatoriumConfig.Gateway, observatoriumConfig.Tenant),
+		URL:                 url,
 		Name:                index.Id,
 		RemoteTimeout:       prometheusv1.Duration(remoteWrite.RemoteTimeout),
 		WriteRelabelConfigs: remoteWrite.WriteRelabelConfigs,
 		BearerTokenFile:     fmt.Sprintf("/etc/prometheus/secrets/%s/token", tokenSecret),
 		TLSConfig: &prometheusv1.TLSConfig{
 			SafeTLSConfig: prometheusv1.SafeTLSConfig{
-				InsecureSkipVerify: true,
+				InsecureSkipVerify: !cr.FIPSModeEnabled(),
 			},
 		},
-		ProxyURL:    remoteWrite.ProxyUrl,
+		ProxyURL:    proxyUrl,
 		QueueConfig: remoteWrite.QueueConfig,
 	}, tokenSecret, nil
 }
 
+// Authenticate with a projected token of the Prometheus service account
+func (r *Reconciler) getRemoteWriteSpecForServiceAccount(cr *v1.Observability, index v1.RepositoryIndex, observatoriumConfig *v1.ObservatoriumIndex, remoteWrite *v1.RemoteWriteIndex) (*prometheusv1.RemoteWriteSpec, string, error) {
+	gateway := model.GetObservatoriumGateway(r.activeGateways, observatoriumConfig)
+	url := fmt.Sprintf("%s/api/metrics/v1/%s/api/v1/receive", gateway, observatoriumConfig.Tenant)
+
+	proxyUrl := remoteWrite.ProxyUrl
+	if proxyUrl == "" {
+		proxyUrl = model.GetProxyUrlFor(r.clusterProxy, url)
+	}
+
+	return &prometheusv1.RemoteWriteSpec{
+		URL:                 url,
+		Name:                index.Id,
+		RemoteTimeout:       prometheusv1.Duration(remoteWrite.RemoteTimeout),
+		WriteRelabelConfigs: remoteWrite.WriteRelabelConfigs,
+		BearerTokenFile:     model.GetServiceAccountTokenFile(model.GetObservatoriumServiceAccountTokenVolumeName(observatoriumConfig)),
+		TLSConfig: &prometheusv1.TLSConfig{
+			SafeTLSConfig: prometheusv1.SafeTLSConfig{
+				InsecureSkipVerify: !cr.FIPSModeEnabled(),
+			},
+		},
+		ProxyURL:    proxyUrl,
+		QueueConfig: remoteWrite.QueueConfig,
+	}, "", nil
+}
+
+// Observatorium instances that Prometheus writes to with the serviceaccount auth type, each
+// needs a token with its own audience
+func getServiceAccountTokenObservatoria(indexes []v1.RepositoryIndex) []*v1.ObservatoriumIndex {
+	var result []*v1.ObservatoriumIndex
+	seen := map[string]bool{}
+	for i := range indexes {
+		index := &indexes[i]
+		if index.Config == nil || index.Config.Prometheus == nil || index.Config.Prometheus.Observatorium == "" {
+			continue
+		}
+		observatorium := token.GetObservatoriumConfig(index, index.Config.Prometheus.Observatorium)
+		if observatorium == nil || observatorium.AuthType != v1.AuthTypeServiceAccount {
+			continue
+		}
+		name := model.GetObservatoriumServiceAccountTokenVolumeName(observatorium)
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, observatorium)
+		}
+	}
+	return result
+}
+
 // Proxy requests through the token refresher
 func (r *Reconciler) getRemoteWriteSpecForRedHat(cr *v1.Observability, index v1.RepositoryIndex, observatoriumConfig *v1.ObservatoriumIndex, remoteWrite *v1.RemoteWriteIndex) (*prometheusv1.RemoteWriteSpec, string, error) {
-	tokenRefresherName := model.GetTokenRefresherName(observatoriumConfig.Id, model.MetricsTokenRefresher)
-	tokenRefresherUrl := fmt.Sprintf("http://%v.%v.svc.cluster.local", tokenRefresherName, cr.GetPrometheusOperatorNamespace())
+	tokenRefresherUrl := model.GetTokenRefresherUrl(cr, observatoriumConfig.Id, model.MetricsTokenRefresher)
 
 	return &prometheusv1.RemoteWriteSpec{
 		URL:                 tokenRefresherUrl,
@@ -161,7 +458,7 @@ func (r *Reconciler) getRemoteWriteSpecForRedHat(cr *v1.Observability, index v1.
 		WriteRelabelConfigs: remoteWrite.WriteRelabelConfigs,
 		TLSConfig: &prometheusv1.TLSConfig{
 			SafeTLSConfig: prometheusv1.SafeTLSConfig{
-				InsecureSkipVerify: true,
+				InsecureSkipVerify: !cr.FIPSModeEnabled(),
 			},
 		},
 		ProxyURL:    remoteWrite.ProxyUrl,
@@ -169,6 +466,31 @@ func (r *Reconciler) getRemoteWriteSpecForRedHat(cr *v1.Observability, index v1.
 	}, "", nil
 }
 
+// Sign requests with AWS credentials, the tenant is the id of the workspace
+func (r *Reconciler) getRemoteWriteSpecForSigv4(cr *v1.Observability, index v1.RepositoryIndex, observatoriumConfig *v1.ObservatoriumIndex, remoteWrite *v1.RemoteWriteIndex) (*prometheusv1.RemoteWriteSpec, string, error) {
+	if observatoriumConfig.Sigv4Config == nil {
+		return nil, "", fmt.Errorf("no sigv4 config found for %v", observatoriumConfig.Id)
+	}
+
+	gateway := model.GetObservatoriumGateway(r.activeGateways, observatoriumConfig)
+	url := fmt.Sprintf("%s/workspaces/%s/api/v1/remote_write", gateway, observatoriumConfig.Tenant)
+
+	proxyUrl := remoteWrite.ProxyUrl
+	if proxyUrl == "" {
+		proxyUrl = model.GetProxyUrlFor(r.clusterProxy, url)
+	}
+
+	return &prometheusv1.RemoteWriteSpec{
+		URL:                 url,
+		Name:                index.Id,
+		RemoteTimeout:       prometheusv1.Duration(remoteWrite.RemoteTimeout),
+		WriteRelabelConfigs: remoteWrite.WriteRelabelConfigs,
+		Sigv4:               model.GetSigv4(observatoriumConfig.Sigv4Config),
+		ProxyURL:            proxyUrl,
+		QueueConfig:         remoteWrite.QueueConfig,
+	}, "", nil
+}
+
 func (r *Reconciler) getRemoteWriteSpec(cr *v1.Observability, index v1.RepositoryIndex, remoteWrite *v1.RemoteWriteIndex) (*prometheusv1.RemoteWriteSpec, string, error) {
 	if index.Config == nil || index.Config.Prometheus == nil || index.Config.Prometheus.Observatorium == "" {
 		return nil, "", fmt.Errorf("no observatorium config found for %v / prometheus", index.Id)
@@ -181,32 +503,79 @@ func (r *Reconciler) getRemoteWriteSpec(cr *v1.Observability, index v1.Repositor
 
 	switch observatoriumConfig.AuthType {
 	case v1.AuthTypeDex:
-		return r.getRemoteWriteSpecForDex(index, observatoriumConfig, remoteWrite)
+		return r.getRemoteWriteSpecForDex(cr, index, observatoriumConfig, remoteWrite)
 	case v1.AuthTypeRedhat:
 		return r.getRemoteWriteSpecForRedHat(cr, index, observatoriumConfig, remoteWrite)
+	case v1.AuthTypeSigv4:
+		return r.getRemoteWriteSpecForSigv4(cr, index, observatoriumConfig, remoteWrite)
+	case v1.AuthTypeServiceAccount:
+		return r.getRemoteWriteSpecForServiceAccount(cr, index, observatoriumConfig, remoteWrite)
 	default:
 		return nil, "", errors2.New(fmt.Sprintf("unknown auth type %v", observatoriumConfig.AuthType))
 	}
 }
 
+// The metric filters of the CR and the index are applied after the relabel configs of the remote write index
+func applyMetricFilters(cr *v1.Observability, index v1.RepositoryIndex, remoteWrite *prometheusv1.RemoteWriteSpec) error {
+	var indexFilter *v1.MetricFilter
+	if index.Config != nil && index.Config.Prometheus != nil {
+		indexFilter = index.Config.Prometheus.MetricFilter
+	}
+
+	relabelConfigs, err := model.GetMetricFilterRelabelConfigs(model.GetMetricFilter(cr), indexFilter)
+	if err != nil {
+		return err
+	}
+
+	remoteWrite.WriteRelabelConfigs = append(remoteWrite.WriteRelabelConfigs, relabelConfigs...)
+	return nil
+}
+
+// Without Alertmanager the alerts are only evaluated
 func (r *Reconciler) getAlerting(cr *v1.Observability) *prometheusv1.AlertingSpec {
+	if !cr.AlertmanagerEnabled() {
+		return nil
+	}
+
 	alertmanager := model.GetAlertmanagerCr(cr)
 	alertmanagerService := model.GetAlertmanagerService(cr)
 
+	// Without the oauth proxy alertmanager serves plain HTTP, unless it serves the certificate of cert-manager
+	// itself. The mesh secures and authenticates the alerts if it is enabled.
+	if !cr.OAuthProxyEnabled() && !model.IsCertManagerServedDirectly(cr) {
+		return &prometheusv1.AlertingSpec{
+			Alertmanagers: []prometheusv1.AlertmanagerEndpoints{
+				{
+					Namespace: cr.GetPrometheusOperatorNamespace(),
+					Name:      alertmanager.Name,
+					Port:      intstr.FromString("web"),
+					Scheme:    "http",
+				},
+			},
+		}
+	}
+
+	tlsConfig := &prometheusv1.TLSConfig{
+		CAFile: "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
+		SafeTLSConfig: prometheusv1.SafeTLSConfig{
+			ServerName: fmt.Sprintf("%v.%v.svc", alertmanagerService.Name, cr.GetPrometheusOperatorNamespace()),
+		},
+	}
+	// The certificate of cert-manager is verified with the CA in its secret
+	if cr.CertManagerEnabled() {
+		tlsConfig.CAFile = ""
+		tlsConfig.CA = model.GetServingCertificateCA(model.GetAlertmanagerTLSSecret(cr).Name)
+	}
+
 	return &prometheusv1.AlertingSpec{
 		Alertmanagers: []prometheusv1.AlertmanagerEndpoints{
 			{
-				Namespace: cr.GetPrometheusOperatorNamespace(),
-				Name:      alertmanager.Name,
-				Port:      intstr.FromString("web"),
-				Scheme:    "https",
-				TLSConfig: &prometheusv1.TLSConfig{
-					CAFile: "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
-					SafeTLSConfig: prometheusv1.SafeTLSConfig{
-						ServerName: fmt.Sprintf("%v.%v.svc", alertmanagerService.Name, cr.GetPrometheusOperatorNamespace()),
-					},
-				},
-				BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+				Namespace:       cr.GetPrometheusOperatorNamespace(),
+				Name:            alertmanager.Name,
+				Port:            intstr.FromString("web"),
+				Scheme:          "https",
+				TLSConfig:       tlsConfig,
+				BearerTokenFile: model.GetServiceAccountTokenFile(model.PrometheusTokenVolume),
 			},
 		},
 	}
@@ -222,19 +591,24 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 		Name:      route.Name,
 	}
 
+	// There are no routes on Kubernetes clusters or if ingresses are used
 	err := r.client.Get(ctx, selector, route)
-	if err != nil && !errors.IsNotFound(err) {
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
 		return err
 	}
 
 	host := ""
-	if utils.IsRouteReady(route) {
+	if cr.IngressEnabled() {
+		host = model.GetIngressHost(cr.Spec.Ingress.Prometheus)
+	} else if err == nil && utils.IsRouteReady(route) {
 		host = route.Spec.Host
 	}
 
 	var secrets []string
-	secrets = append(secrets, proxySecret.Name)
-	secrets = append(secrets, "prometheus-k8s-tls")
+	if !cr.IsKubernetesCluster() {
+		secrets = append(secrets, proxySecret.Name)
+		secrets = append(secrets, "prometheus-k8s-tls")
+	}
 
 	var remoteWrites []prometheusv1.RemoteWriteSpec
 	var sidecars []kv1.Container
@@ -242,14 +616,23 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 	// If Observatorium is disabled, we won't create any remote write targets
 	if !cr.ObservatoriumDisabled() {
 		for _, index := range indexes {
-			rw, err := r.getRemoteWriteIndex(index)
+			rw, err := r.getRemoteWriteIndex(ctx, index)
 			if err != nil {
+				r.addConfigurationError(index.Id, v1.ErrorStageFetch, err)
 				return err
 			}
 
 			remoteWrite, tokenSecret, err := r.getRemoteWriteSpec(cr, index, rw)
+			if err == nil {
+				applyAggregations(index, remoteWrite)
+				applyMetricClasses(cr, remoteWrite)
+				err = applyMetricFilters(cr, index, remoteWrite)
+			}
 			if err != nil {
-				logrus.Error(err)
+				r.logger.Error(err, "skipped remote write target", "index", index.Id)
+				r.addConfigurationError(index.Id, v1.ErrorStageParse, err)
+				r.recordEvent(cr, kv1.EventTypeWarning, EventReasonRemoteWriteSkipped,
+					"Skipped remote write target for %v: %v", index.Id, err)
 				continue
 			}
 
@@ -260,61 +643,189 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 		}
 	}
 
-	var image = fmt.Sprintf("%s:%s", PrometheusBaseImage, model.GetPrometheusVersion(cr))
-
-	sidecars = append(sidecars, kv1.Container{
-		Name:  "oauth-proxy",
-		Image: "quay.io/openshift/origin-oauth-proxy:4.8",
-		Args: []string{
-			"-provider=openshift",
-			"-https-address=:9091",
-			"-http-address=",
-			"-email-domain=*",
-			"-upstream=http://localhost:9090",
-			fmt.Sprintf("-openshift-service-account=%v", sa.Name),
-			"-openshift-sar={\"resource\": \"namespaces\", \"verb\": \"get\"}",
-			"-openshift-delegate-urls={\"/\": {\"resource\": \"namespaces\", \"verb\": \"get\"}}",
-			"-tls-cert=/etc/tls/private/tls.crt",
-			"-tls-key=/etc/tls/private/tls.key",
-			"-client-secret-file=/var/run/secrets/kubernetes.io/serviceaccount/token",
-			"-cookie-secret-file=/etc/proxy/secrets/session_secret",
-			"-openshift-ca=/etc/pki/tls/cert.pem",
-			"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
-			"-skip-auth-regex=^/metrics",
-		},
-		Env: []kv1.EnvVar{
-			{
-				Name: "HTTP_PROXY",
-			},
-			{
-				Name: "HTTPS_PROXY",
-			},
-			{
-				Name: "NO_PROXY",
-			},
-		},
-		Ports: []kv1.ContainerPort{
-			{
-				Name:          "proxy",
-				ContainerPort: 9091,
+	hasSecret := func(name string) bool {
+		for _, secret := range secrets {
+			if secret == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Remote write targets of self-contained installs, not tied to Observatorium
+	if cr.Spec.SelfContained != nil {
+		for i := range cr.Spec.SelfContained.RemoteWrite {
+			target := &cr.Spec.SelfContained.RemoteWrite[i]
+			remoteWrite, err := model.GetRemoteWriteTargetSpec(cr, target, model.GetProxyUrlFor(r.clusterProxy, target.Url))
+			if err == nil {
+				applyMetricClasses(cr, remoteWrite)
+				err = applyMetricFilters(cr, v1.RepositoryIndex{}, remoteWrite)
+			}
+			if err != nil {
+				r.logger.Error(err, "skipped remote write target", "target", target.Name)
+				r.addConfigurationError("", v1.ErrorStageParse, err)
+				r.recordEvent(cr, kv1.EventTypeWarning, EventReasonRemoteWriteSkipped,
+					"Skipped remote write target %v: %v", target.Name, err)
+				continue
+			}
+
+			remoteWrites = append(remoteWrites, *remoteWrite)
+			if target.AuthSecret != "" && !hasSecret(target.AuthSecret) {
+				secrets = append(secrets, target.AuthSecret)
+			}
+		}
+	}
+
+	var remoteReads []prometheusv1.RemoteReadSpec
+	for _, target := range getRemoteReadTargets(cr, indexes) {
+		remoteRead, err := model.GetRemoteReadSpec(cr, &target, model.GetProxyUrlFor(r.clusterProxy, target.Url))
+		if err != nil {
+			r.logger.Error(err, "skipped remote read target", "target", target.Name)
+			r.addConfigurationError("", v1.ErrorStageParse, err)
+			continue
+		}
+
+		remoteReads = append(remoteReads, *remoteRead)
+		if target.AuthSecret != "" && !hasSecret(target.AuthSecret) {
+			secrets = append(secrets, target.AuthSecret)
+		}
+	}
+
+	// Auth secrets of the federation upstreams are mounted to /etc/prometheus/secrets
+	for _, upstream := range append(getFederationUpstreams(cr, indexes), model.GetHostedControlPlaneFederationUpstreams(cr, nil)...) {
+		if upstream.AuthSecret != "" && !hasSecret(upstream.AuthSecret) {
+			secrets = append(secrets, upstream.AuthSecret)
+		}
+	}
+	for _, alertmanager := range cr.Spec.ExternalAlertmanagers {
+		if alertmanager.AuthSecret != "" && !hasSecret(alertmanager.AuthSecret) {
+			secrets = append(secrets, alertmanager.AuthSecret)
+		}
+	}
+
+	metrics.SetRemoteWriteTargetsMetric(len(remoteWrites))
+
+	version := r.getPrometheusVersion(cr)
+	var image = model.GetImage(cr, v1.ImagePrometheus, model.PrometheusBaseImage, version)
+
+	// Invalid feature flags and those that the version doesn't support are left out
+	enableFeatures, errs := model.GetPrometheusEnableFeatures(cr, version)
+	for _, err := range errs {
+		r.logger.Error(err, "skipped prometheus feature")
+		r.addConfigurationError("", v1.ErrorStageValidate, err)
+	}
+	model.ApplyRemoteWriteExemplars(enableFeatures, remoteWrites)
+
+	// Limits are only enforced if set in the CR
+	limits := v1.ScrapeLimits{}
+	if cr.Spec.ScrapeLimits != nil {
+		limits = *cr.Spec.ScrapeLimits
+	}
+
+	// Projected tokens replace the token secret of the service account
+	volumes := []kv1.Volume{
+		{
+			Name: "black-box-config",
+			VolumeSource: kv1.VolumeSource{
+				ConfigMap: &kv1.ConfigMapVolumeSource{
+					LocalObjectReference: kv1.LocalObjectReference{
+						Name: "black-box-config",
+					},
+				},
 			},
 		},
-		VolumeMounts: []kv1.VolumeMount{
-			{
-				Name:      "secret-prometheus-k8s-tls",
-				MountPath: "/etc/tls/private",
+		model.GetTrustedCABundleVolume(cr),
+		model.GetServiceAccountTokenVolume(model.PrometheusTokenVolume, "", model.ServiceAccountTokenExpirationSeconds),
+	}
+	volumeMounts := []kv1.VolumeMount{
+		model.GetTrustedCABundleVolumeMount(),
+		model.GetServiceAccountTokenVolumeMount(model.PrometheusTokenVolume),
+	}
+	for _, observatorium := range getServiceAccountTokenObservatoria(indexes) {
+		volumes = append(volumes, model.GetObservatoriumServiceAccountTokenVolume(observatorium))
+		volumeMounts = append(volumeMounts, model.GetServiceAccountTokenVolumeMount(model.GetObservatoriumServiceAccountTokenVolumeName(observatorium)))
+	}
+
+	webSpec, err := model.GetPrometheusWebSpec(cr)
+	if err != nil {
+		return err
+	}
+
+	// The oauth proxy authenticates against the OpenShift oauth server, unless Prometheus serves TLS itself
+	oauthProxy := cr.OAuthProxyEnabled() && !cr.PrometheusWebTLSEnabled()
+	if oauthProxy {
+		sidecars = append(sidecars, kv1.Container{
+			Name:  "oauth-proxy",
+			Image: model.GetOAuthProxyImage(cr),
+			Args: append([]string{
+				"-provider=openshift",
+				"-https-address=:9091",
+				"-http-address=",
+				"-email-domain=*",
+				"-upstream=http://localhost:9090",
+				fmt.Sprintf("-openshift-service-account=%v", sa.Name),
+				"-tls-cert=/etc/tls/private/tls.crt",
+				"-tls-key=/etc/tls/private/tls.key",
+				fmt.Sprintf("-client-secret-file=%v", model.GetServiceAccountTokenFile(model.OAuthProxyTokenVolume)),
+				"-cookie-secret-file=/etc/proxy/secrets/session_secret",
+				"-openshift-ca=/etc/pki/tls/cert.pem",
+				"-openshift-ca=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+				"-skip-auth-regex=^/metrics",
+			}, append(model.GetOAuthProxyAuthorizationArgs(cr, v1.OAuthProxyPrometheus), model.GetOAuthProxyTLSArgs(cr)...)...),
+			Env:             model.GetProxyEnvVars(r.clusterProxy),
+			SecurityContext: model.GetContainerSecurityContext(cr),
+			Ports: []kv1.ContainerPort{
+				{
+					Name:          "proxy",
+					ContainerPort: 9091,
+				},
 			},
-			{
-				Name:      fmt.Sprintf("secret-%v", proxySecret.Name),
-				MountPath: "/etc/proxy/secrets",
+			Resources: model.GetRecommendedResourceRequirement(cr, model.ComponentPrometheus, "oauth-proxy", kv1.ResourceRequirements{}),
+			VolumeMounts: []kv1.VolumeMount{
+				{
+					Name:      "secret-prometheus-k8s-tls",
+					MountPath: "/etc/tls/private",
+				},
+				{
+					Name:      fmt.Sprintf("secret-%v", proxySecret.Name),
+					MountPath: "/etc/proxy/secrets",
+				},
+				model.GetServiceAccountTokenVolumeMount(model.OAuthProxyTokenVolume),
 			},
-		},
-	})
+		})
+		volumes = append(volumes, model.GetServiceAccountTokenVolume(model.OAuthProxyTokenVolume, "", model.ServiceAccountTokenExpirationSeconds))
+	}
+
+	// The sidecar of the mesh writes its certificates for the mTLS scrapes
+	if cr.ServiceMeshMTLSScrapesEnabled() {
+		volumes = append(volumes, model.GetServiceMeshCertsVolume())
+		volumeMounts = append(volumeMounts, model.GetServiceMeshCertsVolumeMount())
+	}
+
+	// The exporter of the slow queries reads the query log from the volume that Prometheus writes it to
+	if cr.QueryLogEnabled() {
+		exporter, err := r.getQueryLogExporterContainer(ctx, cr)
+		if err != nil {
+			r.logger.Error(err, "skipped query log exporter")
+			r.addConfigurationError("", v1.ErrorStageValidate, err)
+		} else {
+			sidecars = append(sidecars, exporter)
+		}
+	}
+
+	configMaps := []string{model.GetPrometheusStaticTargetsConfigMap(cr).Name}
+	// prom-label-proxy can only reach Prometheus over plain HTTP
+	if cr.TenancyProxyEnabled() && cr.PrometheusWebTLSEnabled() {
+		r.addConfigurationError("", v1.ErrorStageValidate, errors2.New("the tenancy proxy is not supported with prometheus web tls"))
+	} else if cr.TenancyProxyEnabled() {
+		sidecars = append(sidecars, model.GetTenancyProxyContainers(cr)...)
+		configMaps = append(configMaps, model.GetTenancyProxyConfigMap(cr).Name)
+	}
 
 	if !cr.BlackboxExporterDisabled() {
 		sidecars = append(sidecars, kv1.Container{
 			Name:  "blackbox-exporter",
-			Image: "quay.io/prometheus/blackbox-exporter:v0.19.0",
+			Image: model.GetImage(cr, v1.ImageBlackboxExporter, model.BlackboxExporterImage, model.BlackboxExporterImageTag),
 			Args: []string{
 				"--config.file=/opt/config/black-box-config.yaml",
 			},
@@ -324,6 +835,7 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 					Value: configHash,
 				},
 			},
+			SecurityContext: model.GetContainerSecurityContext(cr),
 			Ports: []kv1.ContainerPort{
 				{
 					Name:          "http",
@@ -342,8 +854,42 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 			},
 		})
 	}
+	// Static targets are picked up by the file discovery without a restart
+	contentHash, err := r.getMountedContentHash(ctx, cr.GetPrometheusOperatorNamespace(), secrets, []string{model.GetTrustedCABundleConfigMap(cr).Name})
+	if err != nil {
+		return err
+	}
+
+	// Namespaces that opted in are added to the namespace selectors
+	podMonitorNamespaceSelector, err := r.getDiscoveryNamespaceSelector(ctx, cr, model.GetPrometheusPodMonitorNamespaceSelectors(cr, indexes))
+	if err != nil {
+		return err
+	}
+	serviceMonitorNamespaceSelector, err := r.getDiscoveryNamespaceSelector(ctx, cr, model.GetPrometheusServiceMonitorNamespaceSelectors(cr, indexes))
+	if err != nil {
+		return err
+	}
+	probeNamespaceSelector, err := r.getDiscoveryNamespaceSelector(ctx, cr, model.GetProbeNamespaceSelectors(cr, indexes))
+	if err != nil {
+		return err
+	}
+	ruleNamespaceSelector, err := r.getDiscoveryNamespaceSelector(ctx, cr, model.GetPrometheusRuleNamespaceSelectors(cr, indexes))
+	if err != nil {
+		return err
+	}
+
 	prometheus := model.GetPrometheus(cr)
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, prometheus, func() error {
+	existing, err := r.getExistingResource(ctx, prometheus)
+	if err != nil {
+		return err
+	}
+	var opts []client.PatchOption
+	if r.adoptResource(cr, existing, prometheus, "Prometheus") {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	var expansion *storageExpansion
+	_, err = utils.Apply(ctx, r.client, prometheus, func() error {
 		cr.Labels = map[string]string{
 			"app": "prometheus",
 		}
@@ -351,13 +897,15 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 		prometheus.Spec = prometheusv1.PrometheusSpec{
 			CommonPrometheusFields: prometheusv1.CommonPrometheusFields{
 				PodMetadata: &prometheusv1.EmbeddedObjectMetadata{
-					Annotations: map[string]string{
+					Annotations: MergeLabels(map[string]string{
 						"cluster-autoscaler.kubernetes.io/safe-to-evict": "true",
-					},
+						ContentHashAnnotation:                            contentHash,
+					}, model.GetPrometheusServiceMeshPodAnnotations(cr)),
 				},
 				// Custom Prometheus version
-				Image:   &image,
-				Version: model.GetPrometheusVersion(cr),
+				Image:          &image,
+				Version:        version,
+				EnableFeatures: enableFeatures,
 
 				PriorityClassName: model.ObservabilityPriorityClassName,
 
@@ -370,39 +918,63 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 					},
 					Key: "additional-scrape-config.yaml",
 				},
-				ExternalLabels: map[string]string{
-					"cluster_id": cr.Status.ClusterID,
-				},
-				Volumes: []kv1.Volume{
-					{
-						Name: "black-box-config",
-						VolumeSource: kv1.VolumeSource{
-							ConfigMap: &kv1.ConfigMapVolumeSource{
-								LocalObjectReference: kv1.LocalObjectReference{
-									Name: "black-box-config",
-								},
-							},
-						},
-					},
-				},
+				ExternalLabels:                  model.GetPrometheusExternalLabels(cr),
+				Volumes:                         volumes,
 				PodMonitorSelector:              model.GetPrometheusPodMonitorLabelSelectors(cr, indexes),
-				PodMonitorNamespaceSelector:     model.GetPrometheusPodMonitorNamespaceSelectors(cr, indexes),
+				PodMonitorNamespaceSelector:     podMonitorNamespaceSelector,
 				ServiceMonitorSelector:          model.GetPrometheusServiceMonitorLabelSelectors(cr, indexes),
-				ServiceMonitorNamespaceSelector: model.GetPrometheusServiceMonitorNamespaceSelectors(cr, indexes),
+				ServiceMonitorNamespaceSelector: serviceMonitorNamespaceSelector,
 
 				ProbeSelector:          model.GetProbeLabelSelectors(cr, indexes),
-				ProbeNamespaceSelector: model.GetProbeNamespaceSelectors(cr, indexes),
+				ProbeNamespaceSelector: probeNamespaceSelector,
 				RemoteWrite:            remoteWrites,
 
 				Secrets:    secrets,
-				Containers: sidecars,
-				Resources:  *model.GetPrometheusResourceRequirement(cr),
+				ConfigMaps: configMaps,
+				Containers: append(sidecars, model.GetSecurityContextContainerPatches(cr, "prometheus", "config-reloader")...),
+				// The init container renders the configuration before the first start
+				InitContainers:  model.GetSecurityContextContainerPatches(cr, "init-config-reloader"),
+				SecurityContext: model.GetPodSecurityContext(cr),
+				VolumeMounts:    volumeMounts,
+				Web:             webSpec,
+				Resources:       model.GetRecommendedResourceRequirement(cr, model.ComponentPrometheus, "prometheus", *model.GetPrometheusResourceRequirement(cr)),
+
+				ScrapeInterval:     model.GetPrometheusScrapeInterval(cr, indexes),
+				EvaluationInterval: model.GetPrometheusEvaluationInterval(cr, indexes),
+
+				EnforcedSampleLimit:          getEnforcedLimit(limits.SampleLimit),
+				EnforcedLabelLimit:           getEnforcedLimit(limits.LabelLimit),
+				EnforcedLabelNameLengthLimit: getEnforcedLimit(limits.LabelNameLengthLimit),
+				EnforcedTargetLimit:          getEnforcedLimit(limits.TargetLimit),
 			},
+			RemoteRead:            remoteReads,
 			Retention:             getRetentionHelper(cr),
+			RetentionSize:         getRetentionSizeHelper(cr),
+			WALCompression:        &([]bool{!cr.WALCompressionDisabled()})[0],
 			RuleSelector:          model.GetPrometheusRuleLabelSelectors(cr, indexes),
-			RuleNamespaceSelector: model.GetPrometheusRuleNamespaceSelectors(cr, indexes),
+			RuleNamespaceSelector: ruleNamespaceSelector,
 			Alerting:              r.getAlerting(cr),
+			Exemplars:             model.GetPrometheusExemplars(cr),
+			QueryLogFile:          model.GetPrometheusQueryLogFile(cr),
 		}
+		if len(cr.Spec.ExternalAlertmanagers) > 0 {
+			prometheus.Spec.AdditionalAlertManagerConfigs = &kv1.SecretKeySelector{
+				LocalObjectReference: kv1.LocalObjectReference{
+					Name: model.GetPrometheusAdditionalScrapeConfig(cr).Name,
+				},
+				Key: model.AdditionalAlertmanagerConfigKey,
+			}
+		}
+		if hasAlertRelabelConfigs(indexes) {
+			prometheus.Spec.AdditionalAlertRelabelConfigs = &kv1.SecretKeySelector{
+				LocalObjectReference: kv1.LocalObjectReference{
+					Name: model.GetPrometheusAdditionalScrapeConfig(cr).Name,
+				},
+				Key: model.AdditionalAlertRelabelConfigKey,
+			}
+		}
+		// The sidecar uploads the blocks to the object storage of Thanos
+		prometheus.Spec.Thanos = getPrometheusThanosSpec(cr)
 		if cr.Spec.Storage != nil && cr.Spec.Storage.PrometheusStorageSpec != nil {
 			var prometheusStorageSpec *prometheusv1.StorageSpec
 			existingPV, pvName, err := r.existingPVC(cr, ctx)
@@ -420,21 +992,66 @@ func (r *Reconciler) reconcilePrometheus(ctx context.Context, cr *v1.Observabili
 					return err
 				}
 			}
+			if cr.PrometheusAutoResizeEnabled() && r.storageRecommendation != nil {
+				prometheusStorageSpec = getAutosizedStorageSpec(prometheusStorageSpec, *r.storageRecommendation)
+			}
 			prometheus.Spec.Storage = prometheusStorageSpec
+			expansion, err = r.getPrometheusStorageExpansion(ctx, prometheus)
+			if err != nil {
+				return err
+			}
+			// The data of volumes that can't be expanded isn't migrated, Prometheus keeps the current size
+			if expansion != nil && len(expansion.unsupported) > 0 && !expansion.current.IsZero() {
+				prometheus.Spec.Storage = getStorageSpecWithSize(prometheus.Spec.Storage, expansion.current)
+			}
+			prometheus.Spec.Paused = expansion.recreate()
 		}
+		model.ApplyQueryLogExporterVolumeMount(cr, prometheus)
 		if cr.Spec.Tolerations != nil {
 			prometheus.Spec.Tolerations = cr.Spec.Tolerations
 		}
-		if cr.Spec.Affinity != nil {
-			prometheus.Spec.Affinity = cr.Spec.Affinity
+		architectures := [][]string{model.GetImageArchitectures(cr, v1.ImagePrometheus, model.PrometheusBaseImage)}
+		if oauthProxy {
+			architectures = append(architectures, model.GetOAuthProxyImageArchitectures(cr))
+		}
+		if !cr.BlackboxExporterDisabled() {
+			architectures = append(architectures, model.GetImageArchitectures(cr, v1.ImageBlackboxExporter, model.BlackboxExporterImage))
 		}
+		if cr.TenancyProxyEnabled() {
+			architectures = append(architectures,
+				model.GetImageArchitectures(cr, v1.ImageKubeRBACProxy, model.KubeRBACProxyImage),
+				model.GetImageArchitectures(cr, v1.ImagePromLabelProxy, model.PromLabelProxyImage))
+		}
+		if prometheus.Spec.Thanos != nil {
+			architectures = append(architectures, model.GetImageArchitectures(cr, v1.ImageThanos, model.ThanosImage))
+		}
+		prometheus.Spec.Affinity = model.GetArchitectureAffinity(cr, cr.Spec.Affinity, architectures...)
 		return nil
-	})
+	}, opts...)
 
 	if err != nil {
 		return err
 	}
 
+	previousVersion := existing.(*prometheusv1.Prometheus).Spec.Version
+	if previousVersion != "" && previousVersion != prometheus.Spec.Version && !cr.DryRunEnabled() {
+		r.recordEvent(cr, kv1.EventTypeNormal, EventReasonPrometheusUpgraded,
+			"Changed Prometheus version from %v to %v", previousVersion, prometheus.Spec.Version)
+	}
+
+	// The candidate of a blue/green upgrade follows all other changes of the spec
+	if r.prometheusUpgrade != nil {
+		err = r.reconcilePrometheusCandidate(ctx, cr, prometheus)
+		if err != nil {
+			return errors2.Wrap(err, "error reconciling prometheus upgrade candidate")
+		}
+	}
+
+	err = r.reconcilePrometheusStorageExpansion(ctx, cr, prometheus, expansion)
+	if err != nil {
+		return errors2.Wrap(err, "error expanding prometheus storage")
+	}
+
 	// need to remove the unbound PVC once new PVC is bound to existing PV
 	err = r.removePVCPostMigration(ctx, cr)
 	if err != nil {
@@ -552,3 +1169,40 @@ func getRetentionHelper(cr *v1.Observability) prometheusv1.Duration {
 
 	return prometheusv1.Duration(cr.Spec.Retention)
 }
+
+func getEnforcedLimit(limit uint64) *uint64 {
+	if limit == 0 {
+		return nil
+	}
+	return &limit
+}
+
+// Size based retention is only applied if a valid size is configured
+func getRetentionSizeHelper(cr *v1.Observability) prometheusv1.ByteSize {
+	match, err := regexp.MatchString("^(0|([0-9]*[.])?[0-9]+((K|M|G|T|E|P)i?)?B)$", cr.Spec.RetentionSize)
+	if err != nil || !match {
+		return ""
+	}
+
+	return prometheusv1.ByteSize(cr.Spec.RetentionSize)
+}
+
+// The operator exports the results of the synthetic checks, the certificate expiries and the remote
+// write probe sample. With namespace scoped permissions Prometheus can't discover the operator pods
+// unless the operator namespace is a target namespace.
+func (r *Reconciler) getOperatorScrapeConfig(cr *v1.Observability) []byte {
+	namespace, err := utils.GetOperatorNamespace()
+	if err != nil {
+		r.logger.Error(err, "operator metrics are not scraped")
+		return nil
+	}
+	if cr.NamespaceScoped() {
+		for _, target := range model.GetTargetNamespaces(cr) {
+			if target == namespace {
+				return model.GetOperatorScrapeConfig(namespace)
+			}
+		}
+		return nil
+	}
+	return model.GetOperatorScrapeConfig(namespace)
+}
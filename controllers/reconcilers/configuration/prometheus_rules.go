@@ -5,12 +5,15 @@ import (
 	"fmt"
 
 	"github.com/ghodss/yaml"
+	errors2 "github.com/pkg/errors"
 	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type ResourceInfo struct {
@@ -19,6 +22,8 @@ type ResourceInfo struct {
 	Url         string
 	AccessToken string
 	Tag         string
+	// Only set for pod monitors
+	Limits v1.ScrapeLimits
 }
 
 func getUniqueRules(indexes []v1.RepositoryIndex) []ResourceInfo {
@@ -59,6 +64,12 @@ func (r *Reconciler) deleteUnrequestedRules(cr *v1.Observability, ctx context.Co
 	}
 
 	isRequested := func(name string) bool {
+		// Generated by the operator, not part of the indexes
+		if name == model.GetCardinalityGrowthRule(cr).Name || name == model.GetAggregationRule(cr).Name || model.IsLibraryResource(name) ||
+			name == model.GetServiceLevelObjectiveRule(cr).Name || name == model.GetCertificateExpiryRule(cr).Name ||
+			name == model.GetMetricClassRule(cr).Name {
+			return true
+		}
 		for _, rule := range rules {
 			if name == rule.Name {
 				return true
@@ -81,10 +92,17 @@ func (r *Reconciler) deleteUnrequestedRules(cr *v1.Observability, ctx context.Co
 	return nil
 }
 
-func (r *Reconciler) createRequestedRules(cr *v1.Observability, ctx context.Context, rules []ResourceInfo) error {
+// Returns the applied rules with the injected labels by index id, also if they are only evaluated by Observatorium
+func (r *Reconciler) createRequestedRules(cr *v1.Observability, ctx context.Context, rules []ResourceInfo) (map[string][]*v12.PrometheusRule, error) {
+	// The injected and rejected rules are collected by position, so that the result and the errors keep
+	// the order of the indexes
+	injectedRules := make([]*v12.PrometheusRule, len(rules))
+	rejected := make([]error, len(rules))
+
 	// Sync requested prometheus rules
-	for _, rule := range rules {
-		bytes, err := r.fetchResource(rule.Url, rule.Tag, rule.AccessToken)
+	err := runParallel(len(rules), model.GetResourceSyncWorkers(cr), func(i int) error {
+		rule := rules[i]
+		bytes, err := r.fetchResource(ctx, rule.Url, rule.Tag, rule.AccessToken)
 		if err != nil {
 			return err
 		}
@@ -94,24 +112,77 @@ func (r *Reconciler) createRequestedRules(cr *v1.Observability, ctx context.Cont
 			return err
 		}
 
+		// Rules filtered out for this cluster are removed if they have been applied before
+		selected, err := isResourceSelected(cr.Spec.RuleFilters, rule.Name, parsedRule.Labels)
+		if err != nil {
+			return errors2.Wrap(err, "error applying rule filters")
+		}
+		if !selected {
+			err = r.client.Delete(ctx, parsedRule)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}
+
+		// Broken rules are not applied, the previous version of the rule stays in place
+		if cr.ClusterRulesEnabled() {
+			r.trackResource(ManagedKindPrometheusRule, parsedRule, rule.Id)
+		}
+		err = validateRule(parsedRule)
+		if err != nil {
+			rejected[i] = err
+			return nil
+		}
+
+		injected := parsedRule.DeepCopy()
+		injectClusterLabels(cr, injected)
+		injectIdLabel(injected, rule.Id)
+		injectedRules[i] = injected
+
+		// Rules that are only evaluated by Observatorium are removed from the cluster
+		if !cr.ClusterRulesEnabled() {
+			err = r.client.Delete(ctx, parsedRule)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}
+
 		requestedSpec := parsedRule.Spec
 		requestedLabels := parsedRule.Labels
 
-		_, err = controllerutil.CreateOrUpdate(ctx, r.client, parsedRule, func() error {
+		return r.createOrUpdateIndexResource(ctx, cr, parsedRule, injected, func() error {
 			// Add managed label to Rule CR
 			parsedRule.Spec = requestedSpec
 			parsedRule.Labels = MergeLabels(map[string]string{
-				"managed-by": "observability-operator",
+				"managed-by":              "observability-operator",
+				ManagedResourceIndexLabel: rule.Id,
 			}, requestedLabels)
 			// Inject managed labels for each rule
+			injectClusterLabels(cr, parsedRule)
 			injectIdLabel(parsedRule, rule.Id)
 			return nil
 		})
+	})
+	for i, err := range rejected {
 		if err != nil {
-			return err
+			r.logger.Error(err, "rejected prometheus rule")
+			r.addConfigurationError(rules[i].Id, v1.ErrorStageValidate, err)
+			r.recordEvent(cr, kv1.EventTypeWarning, EventReasonRuleRejected, "Rejected rule %v: %v", rules[i].Name, err)
 		}
 	}
-	return nil
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]*v12.PrometheusRule{}
+	for i, injected := range injectedRules {
+		if injected != nil {
+			result[rules[i].Id] = append(result[rules[i].Id], injected)
+		}
+	}
+	return result, nil
 }
 
 func (r *Reconciler) createDMSAlert(cr *v1.Observability, ctx context.Context) error {
@@ -121,15 +192,9 @@ func (r *Reconciler) createDMSAlert(cr *v1.Observability, ctx context.Context) e
 	}
 
 	dms := model.GetDeadmansSwitch(cr)
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, dms, func() error {
-		if cr.Spec.SelfContained != nil && cr.Spec.SelfContained.RuleLabelSelector != nil {
-			if dms.Labels == nil {
-				dms.Labels = make(map[string]string)
-			}
-
-			for k, v := range cr.Spec.SelfContained.RuleLabelSelector.MatchLabels {
-				dms.Labels[k] = v
-			}
+	_, err := utils.Apply(ctx, r.client, dms, func() error {
+		if labels := getRuleSelectorLabels(cr); labels != nil {
+			dms.Labels = MergeLabels(labels, dms.Labels)
 		}
 		dms.Spec.Groups = []v12.RuleGroup{
 			{
@@ -145,11 +210,42 @@ func (r *Reconciler) createDMSAlert(cr *v1.Observability, ctx context.Context) e
 				},
 			},
 		}
+		injectClusterLabels(cr, dms)
 		return nil
 	})
 	return err
 }
 
+// Labels of rules generated by the operator, so that Prometheus selects them
+func getRuleSelectorLabels(cr *v1.Observability) map[string]string {
+	if cr.Spec.SelfContained == nil {
+		return nil
+	}
+	return model.GetSelectorLabels(cr.Spec.SelfContained.RuleLabelSelector)
+}
+
+// Cluster labels take precedence over the labels of the alerts, recording rules are not changed
+func injectClusterLabels(cr *v1.Observability, rule *v12.PrometheusRule) {
+	labels := model.GetClusterLabels(cr)
+	if len(labels) == 0 {
+		return
+	}
+
+	for i := 0; i < len(rule.Spec.Groups); i++ {
+		for j := 0; j < len(rule.Spec.Groups[i].Rules); j++ {
+			if rule.Spec.Groups[i].Rules[j].Alert == "" {
+				continue
+			}
+			if rule.Spec.Groups[i].Rules[j].Labels == nil {
+				rule.Spec.Groups[i].Rules[j].Labels = make(map[string]string)
+			}
+			for name, value := range labels {
+				rule.Spec.Groups[i].Rules[j].Labels[name] = value
+			}
+		}
+	}
+}
+
 func injectIdLabel(rule *v12.PrometheusRule, id string) {
 	for i := 0; i < len(rule.Spec.Groups); i++ {
 		for j := 0; j < len(rule.Spec.Groups[i].Rules); j++ {
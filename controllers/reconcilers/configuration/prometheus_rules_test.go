@@ -149,6 +149,57 @@ func TestPrometheusRules_InjectIdLabel(t *testing.T) {
 	}
 }
 
+func TestPrometheusRules_InjectClusterLabels(t *testing.T) {
+	cr := &apiv1.Observability{
+		Spec: apiv1.ObservabilitySpec{
+			ClusterLabels: map[string]string{
+				"environment":  "production",
+				"invalid-name": "ignored",
+			},
+		},
+	}
+
+	rule := &v12.PrometheusRule{
+		Spec: v12.PrometheusRuleSpec{
+			Groups: []v12.RuleGroup{
+				{
+					Rules: []v12.Rule{
+						{
+							Record: "job:up:sum",
+						},
+						{
+							Alert:  "TargetDown",
+							Labels: map[string]string{"environment": "staging", "severity": "warning"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := &v12.PrometheusRule{
+		Spec: v12.PrometheusRuleSpec{
+			Groups: []v12.RuleGroup{
+				{
+					Rules: []v12.Rule{
+						{
+							Record: "job:up:sum",
+						},
+						{
+							Alert:  "TargetDown",
+							Labels: map[string]string{"environment": "production", "severity": "warning"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	injectClusterLabels(cr, rule)
+	Expect(rule).To(Equal(want))
+}
+
 func TestPrometheusRules_ParseRuleFromYaml(t *testing.T) {
 	type args struct {
 		cr     *apiv1.Observability
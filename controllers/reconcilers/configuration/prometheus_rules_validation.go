@@ -0,0 +1,146 @@
+package configuration
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+)
+
+// Prometheus defines these variables before expanding the labels and annotations of an alert
+const ruleTemplateDefs = "{{$labels := .Labels}}{{$externalLabels := .ExternalLabels}}{{$externalURL := .ExternalURL}}{{$value := .Value}}"
+
+// Functions available in the templates of alerts, only the names matter for parsing
+var ruleTemplateFuncs = func() template.FuncMap {
+	funcs := template.FuncMap{}
+	for _, name := range []string{
+		"query", "first", "label", "value", "strvalue", "args", "reReplaceAll", "safeHtml", "match", "title",
+		"toUpper", "toLower", "graphLink", "tableLink", "sortByLabel", "stripPort", "stripDomain", "humanize",
+		"humanize1024", "humanizeDuration", "humanizePercentage", "humanizeTimestamp", "pathPrefix", "externalURL",
+		"parseDuration", "toTime",
+	} {
+		funcs[name] = func(...interface{}) interface{} { return nil }
+	}
+	return funcs
+}()
+
+// Checks what promtool checks before Prometheus loads a rule file, except for the PromQL grammar.
+// Expressions are only checked for balanced brackets and quotes.
+func validateRule(rule *v12.PrometheusRule) error {
+	var problems []string
+	groups := map[string]bool{}
+	for _, group := range rule.Spec.Groups {
+		if group.Name == "" {
+			problems = append(problems, "group without name")
+		} else if groups[group.Name] {
+			problems = append(problems, fmt.Sprintf("group %v: duplicate group name", group.Name))
+		}
+		groups[group.Name] = true
+
+		if group.Interval != "" {
+			if _, err := commonmodel.ParseDuration(group.Interval); err != nil {
+				problems = append(problems, fmt.Sprintf("group %v: invalid interval %v", group.Name, group.Interval))
+			}
+		}
+
+		for i, r := range group.Rules {
+			for _, problem := range validateRuleEntry(r) {
+				problems = append(problems, fmt.Sprintf("group %v, rule %v: %v", group.Name, i, problem))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid rule %v: %v", rule.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func validateRuleEntry(rule v12.Rule) []string {
+	var problems []string
+	switch {
+	case rule.Record != "" && rule.Alert != "":
+		problems = append(problems, "only one of record and alert may be set")
+	case rule.Record == "" && rule.Alert == "":
+		problems = append(problems, "one of record and alert must be set")
+	case rule.Record != "":
+		if !commonmodel.IsValidMetricName(commonmodel.LabelValue(rule.Record)) {
+			problems = append(problems, fmt.Sprintf("invalid recording rule name %v", rule.Record))
+		}
+		if rule.For != "" {
+			problems = append(problems, "for is only allowed for alerts")
+		}
+		if len(rule.Annotations) > 0 {
+			problems = append(problems, "annotations are only allowed for alerts")
+		}
+	}
+
+	if rule.For != "" {
+		if _, err := commonmodel.ParseDuration(rule.For); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid for duration %v", rule.For))
+		}
+	}
+
+	expr := strings.TrimSpace(rule.Expr.String())
+	if expr == "" {
+		problems = append(problems, "empty expression")
+	} else if err := checkExpressionBalanced(expr); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid expression: %v", err))
+	}
+
+	for _, values := range []map[string]string{rule.Labels, rule.Annotations} {
+		for name, value := range values {
+			if !commonmodel.LabelName(name).IsValid() {
+				problems = append(problems, fmt.Sprintf("invalid label name %v", name))
+			}
+			if rule.Alert == "" {
+				continue
+			}
+			if _, err := template.New(name).Funcs(ruleTemplateFuncs).Parse(ruleTemplateDefs + value); err != nil {
+				problems = append(problems, fmt.Sprintf("invalid template in %v: %v", name, err))
+			}
+		}
+	}
+
+	return problems
+}
+
+// Brackets must be closed in order, strings must be terminated. Comments run to the end of the line.
+func checkExpressionBalanced(expr string) error {
+	closing := map[rune]rune{')': '(', ']': '[', '}': '{'}
+	var stack []rune
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch c {
+		case '"', '\'', '`':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != c; end++ {
+				if runes[end] == '\\' && c != '`' {
+					end++
+				}
+			}
+			if end >= len(runes) {
+				return fmt.Errorf("unterminated string at position %v", i)
+			}
+			i = end
+		case '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case '(', '[', '{':
+			stack = append(stack, c)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != closing[c] {
+				return fmt.Errorf("unexpected %c at position %v", c, i)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("unclosed %c", stack[len(stack)-1])
+	}
+	return nil
+}
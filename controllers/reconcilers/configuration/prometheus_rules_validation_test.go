@@ -0,0 +1,104 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v12 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPrometheusRulesValidation_ValidateRule(t *testing.T) {
+	getRule := func(groups ...v12.RuleGroup) *v12.PrometheusRule {
+		return &v12.PrometheusRule{
+			ObjectMeta: v14.ObjectMeta{Name: "test-rule"},
+			Spec:       v12.PrometheusRuleSpec{Groups: groups},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		rule    *v12.PrometheusRule
+		wantErr string
+	}{
+		{
+			name: "valid alerting and recording rules",
+			rule: getRule(v12.RuleGroup{
+				Name:     "test",
+				Interval: "1m",
+				Rules: []v12.Rule{
+					{
+						Record: "job:up:sum",
+						Expr:   intstr.FromString(`sum by (job) (up{job=~"kafka.*"})`),
+					},
+					{
+						Alert: "TargetDown",
+						Expr:  intstr.FromString(`up == 0 # ignore ( in comments`),
+						For:   "5m",
+						Labels: map[string]string{
+							"severity": "warning",
+						},
+						Annotations: map[string]string{
+							"description": "{{ $labels.job }} is down for {{ $value | humanizeDuration }}",
+						},
+					},
+				},
+			}),
+		},
+		{
+			name: "unbalanced expression and invalid duration",
+			rule: getRule(v12.RuleGroup{
+				Name: "test",
+				Rules: []v12.Rule{
+					{
+						Alert: "TargetDown",
+						Expr:  intstr.FromString(`sum(rate(up[5m])`),
+						For:   "5 minutes",
+					},
+				},
+			}),
+			wantErr: "invalid rule test-rule: group test, rule 0: invalid for duration 5 minutes; group test, rule 0: invalid expression: unclosed (",
+		},
+		{
+			name: "recording rule with an invalid name and an alert with a broken template",
+			rule: getRule(v12.RuleGroup{
+				Name: "test",
+				Rules: []v12.Rule{
+					{
+						Record: "job-up",
+						Expr:   intstr.FromString(`up`),
+					},
+					{
+						Alert: "TargetDown",
+						Expr:  intstr.FromString(`up == 0`),
+						Annotations: map[string]string{
+							"summary": "{{ $labels.job ",
+						},
+					},
+				},
+			}),
+			wantErr: "invalid rule test-rule: group test, rule 0: invalid recording rule name job-up; group test, rule 1: invalid template in summary: template: summary:1: unclosed action",
+		},
+		{
+			name: "duplicate groups and rules without alert or record",
+			rule: getRule(
+				v12.RuleGroup{Name: "test", Rules: []v12.Rule{{Expr: intstr.FromString(`up`)}}},
+				v12.RuleGroup{Name: "test"},
+			),
+			wantErr: "invalid rule test-rule: group test, rule 0: one of record and alert must be set; group test: duplicate group name",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRule(tt.rule)
+			if tt.wantErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(tt.wantErr))
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	kv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Increase of the requested storage size. Volume claim templates of a StatefulSet are immutable, the
+// Prometheus operator would recreate the StatefulSet with its pods for a larger template. Instead
+// Prometheus is paused until the StatefulSet has been deleted without its pods.
+type storageExpansion struct {
+	desired resource.Quantity
+	// Size of the claim template of the current StatefulSet, zero without one
+	current resource.Quantity
+	// Claims that are smaller than the requested size
+	claims []kv1.PersistentVolumeClaim
+	// Claims of storage classes that don't allow expansion. The data isn't migrated to new volumes,
+	// the StatefulSet keeps the size of its claims then.
+	unsupported []string
+}
+
+func (e *storageExpansion) recreate() bool {
+	return e != nil && len(e.unsupported) == 0 && !e.current.IsZero() && e.current.Cmp(e.desired) < 0
+}
+
+// Nil without requested storage
+func (r *Reconciler) getPrometheusStorageExpansion(ctx context.Context, prometheus *prometheusv1.Prometheus) (*storageExpansion, error) {
+	if prometheus.Spec.Storage == nil {
+		return nil, nil
+	}
+	desired, ok := prometheus.Spec.Storage.VolumeClaimTemplate.Spec.Resources.Requests[kv1.ResourceStorage]
+	if !ok {
+		return nil, nil
+	}
+	result := &storageExpansion{desired: desired}
+
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: prometheus.Namespace, Name: getPrometheusStatefulSetName(prometheus)}, statefulSet)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	template := getPrometheusVolumeClaimTemplateName(prometheus)
+	for _, claim := range statefulSet.Spec.VolumeClaimTemplates {
+		if claim.Name == template {
+			result.current = claim.Spec.Resources.Requests[kv1.ResourceStorage]
+		}
+	}
+
+	list := &kv1.PersistentVolumeClaimList{}
+	err = r.client.List(ctx, list, &client.ListOptions{Namespace: prometheus.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range list.Items {
+		if !isPrometheusVolumeClaim(&pvc, prometheus) || !volumeClaimNeedsExpansion(&pvc, desired) {
+			continue
+		}
+		expandable, err := r.storageClassAllowsExpansion(ctx, pvc.Spec.StorageClassName)
+		if err != nil {
+			return nil, err
+		}
+		if expandable {
+			result.claims = append(result.claims, pvc)
+		} else {
+			result.unsupported = append(result.unsupported, pvc.Name)
+		}
+	}
+	return result, nil
+}
+
+// Grow the Prometheus volume claims when the requested storage size was increased, then delete the
+// paused StatefulSet and orphan its pods. Prometheus is resumed by the next sync, which is requested
+// right away. The Prometheus operator then creates the StatefulSet with the new template, which adopts
+// the running pods and the expanded claims.
+func (r *Reconciler) reconcilePrometheusStorageExpansion(ctx context.Context, cr *v1.Observability, prometheus *prometheusv1.Prometheus, expansion *storageExpansion) error {
+	if expansion == nil {
+		return nil
+	}
+
+	if len(expansion.unsupported) > 0 {
+		err := fmt.Errorf("storage class of volume claims %v does not allow expansion, requested size %v is not applied and the data has to be migrated to a new volume manually", strings.Join(expansion.unsupported, ", "), expansion.desired.String())
+		r.addConfigurationError("", v1.ErrorStageApply, err)
+		r.recordEvent(cr, kv1.EventTypeWarning, EventReasonStorageExpansionUnsupported, err.Error())
+		return nil
+	}
+
+	for _, pvc := range expansion.claims {
+		pvc.Spec.Resources.Requests[kv1.ResourceStorage] = expansion.desired
+		err := r.client.Update(ctx, &pvc)
+		if err != nil {
+			return err
+		}
+
+		if !cr.DryRunEnabled() {
+			r.recordEvent(cr, kv1.EventTypeNormal, EventReasonStorageExpanded,
+				"Expanded volume claim %v to %v", pvc.Name, expansion.desired.String())
+		}
+	}
+
+	if !prometheus.Spec.Paused {
+		return nil
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getPrometheusStatefulSetName(prometheus),
+			Namespace: prometheus.Namespace,
+		},
+	}
+	err := r.client.Delete(ctx, statefulSet, client.PropagationPolicy(metav1.DeletePropagationOrphan))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	r.getSyncState().requestSync(cr)
+	if !cr.DryRunEnabled() {
+		r.recordEvent(cr, kv1.EventTypeNormal, EventReasonStorageExpanded,
+			"Recreating StatefulSet %v with a storage size of %v, its pods keep running", statefulSet.Name, expansion.desired.String())
+	}
+	return nil
+}
+
+func (r *Reconciler) storageClassAllowsExpansion(ctx context.Context, name *string) (bool, error) {
+	if name == nil || *name == "" {
+		return false, nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	err := r.client.Get(ctx, client.ObjectKey{Name: *name}, storageClass)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+// Created by the Prometheus operator
+func getPrometheusStatefulSetName(prometheus *prometheusv1.Prometheus) string {
+	return fmt.Sprintf("prometheus-%v", prometheus.Name)
+}
+
+func getPrometheusVolumeClaimTemplateName(prometheus *prometheusv1.Prometheus) string {
+	template := prometheus.Spec.Storage.VolumeClaimTemplate.Name
+	if template == "" {
+		template = fmt.Sprintf("prometheus-%v-db", prometheus.Name)
+	}
+	return template
+}
+
+// Claims created from the volume claim template are named <template>-prometheus-<name>-<ordinal>
+func isPrometheusVolumeClaim(pvc *kv1.PersistentVolumeClaim, prometheus *prometheusv1.Prometheus) bool {
+	template := getPrometheusVolumeClaimTemplateName(prometheus)
+	return strings.HasPrefix(pvc.Name, fmt.Sprintf("%v-%v-", template, getPrometheusStatefulSetName(prometheus)))
+}
+
+func volumeClaimNeedsExpansion(pvc *kv1.PersistentVolumeClaim, desired resource.Quantity) bool {
+	current, ok := pvc.Spec.Resources.Requests[kv1.ResourceStorage]
+	return ok && current.Cmp(desired) < 0
+}
+
+const (
+	// Used until Prometheus has compacted its first block
+	defaultBytesPerSample = 2
+	// Headroom for the WAL, head block and compactions
+	storageHeadroomFactor = 1.2
+)
+
+// Derive the storage needed to hold the configured retention from the current ingestion rate
+// of Prometheus. The rate is averaged over the lifetime of the Prometheus process.
+func (r *Reconciler) reconcileStorageRecommendation(cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	retention, err := commonmodel.ParseDuration(string(getRetentionHelper(cr)))
+	if err != nil {
+		return err
+	}
+
+	families, err := utils.FetchMetrics(r.httpClient, fmt.Sprintf("%v/metrics", model.GetPrometheusUpstreamUrl(cr)))
+	if err != nil {
+		return err
+	}
+
+	startTime := utils.SumMetricValues(families, "process_start_time_seconds")
+	uptime := float64(time.Now().Unix()) - startTime
+	if startTime == 0 || uptime <= 0 {
+		return fmt.Errorf("unable to determine prometheus uptime")
+	}
+	samplesPerSecond := utils.SumMetricValues(families, "prometheus_tsdb_head_samples_appended_total") / uptime
+
+	bytesPerSample := float64(defaultBytesPerSample)
+	chunkSamples := utils.SumMetricObservations(families, "prometheus_tsdb_compaction_chunk_samples")
+	if chunkSamples > 0 {
+		bytesPerSample = utils.SumMetricObservations(families, "prometheus_tsdb_compaction_chunk_size_bytes") / chunkSamples
+	}
+
+	recommendation := getRecommendedStorageSize(samplesPerSecond, bytesPerSample, time.Duration(retention))
+	r.storageRecommendation = &recommendation
+	s.PrometheusStorageRecommendation = recommendation.String()
+	return nil
+}
+
+// Storage needed for the given ingestion rate and retention, rounded up to the next Gi
+func getRecommendedStorageSize(samplesPerSecond float64, bytesPerSample float64, retention time.Duration) resource.Quantity {
+	bytes := samplesPerSecond * bytesPerSample * retention.Seconds() * storageHeadroomFactor
+	gibibytes := int64(math.Ceil(bytes / (1 << 30)))
+	if gibibytes < 1 {
+		gibibytes = 1
+	}
+	return *resource.NewQuantity(gibibytes<<30, resource.BinarySI)
+}
+
+// Raise the requested storage to the recommendation, the requested size is never reduced
+func getAutosizedStorageSpec(spec *prometheusv1.StorageSpec, recommendation resource.Quantity) *prometheusv1.StorageSpec {
+	if spec == nil {
+		return nil
+	}
+
+	current, ok := spec.VolumeClaimTemplate.Spec.Resources.Requests[kv1.ResourceStorage]
+	if ok && current.Cmp(recommendation) >= 0 {
+		return spec
+	}
+
+	return getStorageSpecWithSize(spec, recommendation)
+}
+
+func getStorageSpecWithSize(spec *prometheusv1.StorageSpec, size resource.Quantity) *prometheusv1.StorageSpec {
+	result := spec.DeepCopy()
+	if result.VolumeClaimTemplate.Spec.Resources.Requests == nil {
+		result.VolumeClaimTemplate.Spec.Resources.Requests = kv1.ResourceList{}
+	}
+	result.VolumeClaimTemplate.Spec.Resources.Requests[kv1.ResourceStorage] = size
+	return result
+}
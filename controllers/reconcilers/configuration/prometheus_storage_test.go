@@ -0,0 +1,325 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	kv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildVolumeClaim(name string, size string) *kv1.PersistentVolumeClaim {
+	return &kv1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kv1.PersistentVolumeClaimSpec{
+			Resources: kv1.ResourceRequirements{
+				Requests: kv1.ResourceList{
+					kv1.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+	}
+}
+
+func TestPrometheusStorage_IsPrometheusVolumeClaim(t *testing.T) {
+	type args struct {
+		pvc      *kv1.PersistentVolumeClaim
+		template string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "true if claim was created from named template",
+			args: args{
+				pvc:      buildVolumeClaim("managed-services-prometheus-obs-prometheus-0", "250Gi"),
+				template: "managed-services",
+			},
+			want: true,
+		},
+		{
+			name: "true if claim was created from default template",
+			args: args{
+				pvc:      buildVolumeClaim("prometheus-obs-prometheus-db-prometheus-obs-prometheus-0", "250Gi"),
+				template: "",
+			},
+			want: true,
+		},
+		{
+			name: "false if claim belongs to a different prometheus",
+			args: args{
+				pvc:      buildVolumeClaim("managed-services-prometheus-kafka-prometheus-0", "250Gi"),
+				template: "managed-services",
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prometheus := &prometheusv1.Prometheus{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "obs-prometheus",
+				},
+				Spec: prometheusv1.PrometheusSpec{
+					CommonPrometheusFields: prometheusv1.CommonPrometheusFields{
+						Storage: &prometheusv1.StorageSpec{
+							VolumeClaimTemplate: prometheusv1.EmbeddedPersistentVolumeClaim{
+								EmbeddedObjectMetadata: prometheusv1.EmbeddedObjectMetadata{
+									Name: tt.args.template,
+								},
+							},
+						},
+					},
+				},
+			}
+			result := isPrometheusVolumeClaim(tt.args.pvc, prometheus)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusStorage_VolumeClaimNeedsExpansion(t *testing.T) {
+	type args struct {
+		pvc     *kv1.PersistentVolumeClaim
+		desired resource.Quantity
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "true if desired size is larger",
+			args: args{
+				pvc:     buildVolumeClaim("test-claim", "250Gi"),
+				desired: resource.MustParse("300Gi"),
+			},
+			want: true,
+		},
+		{
+			name: "false if desired size is equal",
+			args: args{
+				pvc:     buildVolumeClaim("test-claim", "250Gi"),
+				desired: resource.MustParse("250Gi"),
+			},
+			want: false,
+		},
+		{
+			name: "false if desired size is smaller",
+			args: args{
+				pvc:     buildVolumeClaim("test-claim", "250Gi"),
+				desired: resource.MustParse("100Gi"),
+			},
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := volumeClaimNeedsExpansion(tt.args.pvc, tt.args.desired)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusStorage_GetRecommendedStorageSize(t *testing.T) {
+	type args struct {
+		samplesPerSecond float64
+		bytesPerSample   float64
+		retention        time.Duration
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want resource.Quantity
+	}{
+		{
+			name: "rounds up to the next Gi",
+			args: args{
+				samplesPerSecond: 10000,
+				bytesPerSample:   2,
+				retention:        45 * 24 * time.Hour,
+			},
+			want: resource.MustParse("87Gi"),
+		},
+		{
+			name: "at least 1Gi if there is no ingestion",
+			args: args{
+				samplesPerSecond: 0,
+				bytesPerSample:   2,
+				retention:        45 * 24 * time.Hour,
+			},
+			want: resource.MustParse("1Gi"),
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getRecommendedStorageSize(tt.args.samplesPerSecond, tt.args.bytesPerSample, tt.args.retention)
+			Expect(result.Cmp(tt.want)).To(Equal(0))
+		})
+	}
+}
+
+func TestPrometheusStorage_GetAutosizedStorageSpec(t *testing.T) {
+	buildStorageSpec := func(size string) *prometheusv1.StorageSpec {
+		return &prometheusv1.StorageSpec{
+			VolumeClaimTemplate: prometheusv1.EmbeddedPersistentVolumeClaim{
+				Spec: buildVolumeClaim("", size).Spec,
+			},
+		}
+	}
+
+	type args struct {
+		spec           *prometheusv1.StorageSpec
+		recommendation resource.Quantity
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want *prometheusv1.StorageSpec
+	}{
+		{
+			name: "raises requested size to recommendation",
+			args: args{
+				spec:           buildStorageSpec("250Gi"),
+				recommendation: resource.MustParse("300Gi"),
+			},
+			want: buildStorageSpec("300Gi"),
+		},
+		{
+			name: "keeps requested size if larger than recommendation",
+			args: args{
+				spec:           buildStorageSpec("250Gi"),
+				recommendation: resource.MustParse("100Gi"),
+			},
+			want: buildStorageSpec("250Gi"),
+		},
+		{
+			name: "nil if no storage is configured",
+			args: args{
+				spec:           nil,
+				recommendation: resource.MustParse("100Gi"),
+			},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getAutosizedStorageSpec(tt.args.spec, tt.args.recommendation)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusStorage_ReconcilePrometheusStorageExpansion(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = storagev1.AddToScheme(scheme)
+
+	expandable := true
+	storageClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "gp3"}, AllowVolumeExpansion: &expandable}
+	buildObjects := func(storageClassName string) (*kv1.PersistentVolumeClaim, *appsv1.StatefulSet) {
+		claim := buildVolumeClaim("managed-services-prometheus-obs-prometheus-0", "250Gi")
+		claim.Namespace = "observability"
+		claim.Spec.StorageClassName = &storageClassName
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "prometheus-obs-prometheus", Namespace: "observability"},
+			Spec: appsv1.StatefulSetSpec{
+				VolumeClaimTemplates: []kv1.PersistentVolumeClaim{*buildVolumeClaim("managed-services", "250Gi")},
+			},
+		}
+		return claim, statefulSet
+	}
+	buildPrometheus := func(size string) *prometheusv1.Prometheus {
+		return &prometheusv1.Prometheus{
+			ObjectMeta: metav1.ObjectMeta{Name: "obs-prometheus", Namespace: "observability"},
+			Spec: prometheusv1.PrometheusSpec{
+				CommonPrometheusFields: prometheusv1.CommonPrometheusFields{
+					Storage: &prometheusv1.StorageSpec{
+						VolumeClaimTemplate: prometheusv1.EmbeddedPersistentVolumeClaim{
+							EmbeddedObjectMetadata: prometheusv1.EmbeddedObjectMetadata{Name: "managed-services"},
+							Spec:                   buildVolumeClaim("", size).Spec,
+						},
+					},
+				},
+			},
+		}
+	}
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Name: "observability", Namespace: "observability"}}
+
+	claim, statefulSet := buildObjects(storageClass.Name)
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(storageClass, claim, statefulSet).Build()
+	r := &Reconciler{logger: logr.Discard(), client: c}
+
+	// An unchanged size keeps the StatefulSet
+	expansion, err := r.getPrometheusStorageExpansion(context.TODO(), buildPrometheus("250Gi"))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(expansion.recreate()).To(BeFalse())
+
+	// A larger size pauses Prometheus until the StatefulSet is deleted without its pods
+	prometheus := buildPrometheus("300Gi")
+	expansion, err = r.getPrometheusStorageExpansion(context.TODO(), prometheus)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(expansion.recreate()).To(BeTrue())
+
+	prometheus.Spec.Paused = expansion.recreate()
+	Expect(r.reconcilePrometheusStorageExpansion(context.TODO(), cr, prometheus, expansion)).To(Succeed())
+
+	Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(claim), claim)).To(Succeed())
+	Expect(claim.Spec.Resources.Requests[kv1.ResourceStorage]).To(Equal(resource.MustParse("300Gi")))
+	err = c.Get(context.TODO(), client.ObjectKeyFromObject(statefulSet), &appsv1.StatefulSet{})
+	Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	// The next reconcile syncs right away and resumes Prometheus, the StatefulSet is gone
+	Expect(r.getSyncState().takeSyncRequest(cr)).To(BeTrue())
+	Expect(r.getSyncState().takeSyncRequest(cr)).To(BeFalse())
+	expansion, err = r.getPrometheusStorageExpansion(context.TODO(), prometheus)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(expansion.recreate()).To(BeFalse())
+
+	// Claims of storage classes without expansion are not migrated and Prometheus isn't paused
+	claim, statefulSet = buildObjects("standard")
+	c = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(claim, statefulSet).Build()
+	r = &Reconciler{logger: logr.Discard(), client: c}
+	prometheus = buildPrometheus("300Gi")
+	expansion, err = r.getPrometheusStorageExpansion(context.TODO(), prometheus)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(expansion.recreate()).To(BeFalse())
+	Expect(expansion.unsupported).To(Equal([]string{claim.Name}))
+	Expect(expansion.current).To(Equal(resource.MustParse("250Gi")))
+
+	Expect(r.reconcilePrometheusStorageExpansion(context.TODO(), cr, prometheus, expansion)).To(Succeed())
+	Expect(r.configurationErrors).To(HaveLen(1))
+	Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(statefulSet), &appsv1.StatefulSet{})).To(Succeed())
+	Expect(r.getSyncState().takeSyncRequest(cr)).To(BeFalse())
+}
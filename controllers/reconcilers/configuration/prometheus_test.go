@@ -0,0 +1,469 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+)
+
+func TestPrometheus_GetRetentionSizeHelper(t *testing.T) {
+	type args struct {
+		cr *v1.Observability
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want prometheusv1.ByteSize
+	}{
+		{
+			name: "no retention size if not set",
+			args: args{
+				cr: buildObservabilityCR(nil),
+			},
+			want: "",
+		},
+		{
+			name: "retention size from cr if valid",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.RetentionSize = "200GiB"
+				}),
+			},
+			want: "200GiB",
+		},
+		{
+			name: "no retention size if invalid",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.RetentionSize = "200Gi"
+				}),
+			},
+			want: "",
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getRetentionSizeHelper(tt.args.cr)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheus_GetFederationUpstreams(t *testing.T) {
+	type args struct {
+		cr      *v1.Observability
+		indexes []v1.RepositoryIndex
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []v1.FederationUpstream
+	}{
+		{
+			name: "upstreams from cr and indexes",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.FederationUpstreams = []v1.FederationUpstream{
+						{
+							Name: "central",
+							Url:  "https://central.example.com",
+						},
+					}
+				}),
+				indexes: []v1.RepositoryIndex{
+					{
+						Id: "test-index",
+						Config: &v1.RepositoryConfig{
+							Prometheus: &v1.PrometheusIndex{
+								FederationUpstreams: []v1.FederationUpstream{
+									{
+										Name: "regional",
+										Url:  "https://regional.example.com",
+									},
+								},
+							},
+						},
+					},
+					{
+						Id: "no-prometheus-config",
+					},
+				},
+			},
+			want: []v1.FederationUpstream{
+				{
+					Name: "central",
+					Url:  "https://central.example.com",
+				},
+				{
+					Name: "test-index-regional",
+					Url:  "https://regional.example.com",
+				},
+			},
+		},
+		{
+			name: "no upstreams if none are configured",
+			args: args{
+				cr:      buildObservabilityCR(nil),
+				indexes: []v1.RepositoryIndex{},
+			},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getFederationUpstreams(tt.args.cr, tt.args.indexes)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheus_NormalizeScrapeConfigs(t *testing.T) {
+	type args struct {
+		source string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "re-encodes a list of scrape configs",
+			args: args{
+				source: `
+- job_name: appliance
+  static_configs:
+    - targets: ['10.0.0.1:9100']
+`,
+			},
+			want: `- job_name: appliance
+  static_configs:
+  - targets:
+    - 10.0.0.1:9100
+`,
+			wantErr: false,
+		},
+		{
+			name: "nothing if the list is empty",
+			args: args{
+				source: "",
+			},
+			want:    "",
+			wantErr: false,
+		},
+		{
+			name: "error if not a list",
+			args: args{
+				source: "job_name: appliance",
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "error if an entry has no job name",
+			args: args{
+				source: `
+- static_configs:
+    - targets: ['10.0.0.1:9100']
+`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "error if a job name is used twice",
+			args: args{
+				source: `
+- job_name: appliance
+- job_name: appliance
+`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "error if a job name is used by a generated scrape config",
+			args: args{
+				source: `
+- job_name: kube-state-metrics
+`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := normalizeScrapeConfigs([]byte(tt.args.source), map[string]bool{"kube-state-metrics": true})
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(string(result)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheus_GetStaticTargets(t *testing.T) {
+	type args struct {
+		cr      *v1.Observability
+		indexes []v1.RepositoryIndex
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []v1.StaticTargetGroup
+	}{
+		{
+			name: "static targets from cr and indexes",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.StaticTargets = []v1.StaticTargetGroup{
+						{
+							Name:    "appliances",
+							Targets: []string{"10.0.0.1:9100"},
+						},
+					}
+				}),
+				indexes: []v1.RepositoryIndex{
+					{
+						Id: "test-index",
+						Config: &v1.RepositoryConfig{
+							Prometheus: &v1.PrometheusIndex{
+								StaticTargets: []v1.StaticTargetGroup{
+									{
+										Name:    "vms",
+										Targets: []string{"vm.example.com:9100"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []v1.StaticTargetGroup{
+				{
+					Name:    "appliances",
+					Targets: []string{"10.0.0.1:9100"},
+				},
+				{
+					Name:    "test-index-vms",
+					Targets: []string{"vm.example.com:9100"},
+				},
+			},
+		},
+		{
+			name: "endpoints of a hosted control plane",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Status.ClusterTopology = v1.ClusterTopologyHosted
+					obsCR.Spec.HostedControlPlane = &v1.HostedControlPlaneSpec{
+						Endpoints: []v1.StaticTargetGroup{
+							{
+								Name:    "kube-apiserver",
+								Targets: []string{"api.hcp.example.com:6443"},
+								Scheme:  "https",
+							},
+						},
+					}
+				}),
+			},
+			want: []v1.StaticTargetGroup{
+				{
+					Name:    "hosted-kube-apiserver",
+					Targets: []string{"api.hcp.example.com:6443"},
+					Scheme:  "https",
+				},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getStaticTargets(tt.args.cr, tt.args.indexes)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheus_GetRemoteReadTargets(t *testing.T) {
+	type args struct {
+		cr      *v1.Observability
+		indexes []v1.RepositoryIndex
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []v1.RemoteReadTarget
+	}{
+		{
+			name: "targets from cr and indexes",
+			args: args{
+				cr: buildObservabilityCR(func(obsCR *v1.Observability) {
+					obsCR.Spec.SelfContained = &v1.SelfContained{
+						RemoteRead: []v1.RemoteReadTarget{
+							{
+								Name: "thanos",
+								Url:  "https://thanos.example.com/api/v1/read",
+							},
+						},
+					}
+				}),
+				indexes: []v1.RepositoryIndex{
+					{
+						Id: "test-index",
+						Config: &v1.RepositoryConfig{
+							Prometheus: &v1.PrometheusIndex{
+								RemoteRead: []v1.RemoteReadTarget{
+									{
+										Name: "observatorium",
+										Url:  "https://observatorium.example.com/api/metrics/v1/test/api/v1/read",
+									},
+								},
+							},
+						},
+					},
+					{
+						Id: "no-prometheus-config",
+					},
+				},
+			},
+			want: []v1.RemoteReadTarget{
+				{
+					Name: "thanos",
+					Url:  "https://thanos.example.com/api/v1/read",
+				},
+				{
+					Name: "test-index-observatorium",
+					Url:  "https://observatorium.example.com/api/metrics/v1/test/api/v1/read",
+				},
+			},
+		},
+		{
+			name: "no targets if none are configured",
+			args: args{
+				cr:      buildObservabilityCR(nil),
+				indexes: []v1.RepositoryIndex{},
+			},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getRemoteReadTargets(tt.args.cr, tt.args.indexes)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheus_GetServiceAccountTokenObservatoria(t *testing.T) {
+	getIndex := func(id string, authType v1.ObservabilityAuthType) v1.RepositoryIndex {
+		return v1.RepositoryIndex{
+			Id: id,
+			Config: &v1.RepositoryConfig{
+				Prometheus: &v1.PrometheusIndex{Observatorium: "default"},
+				Observatoria: []v1.ObservatoriumIndex{
+					{
+						Id:       "default",
+						Gateway:  testGateway,
+						Tenant:   testTenant,
+						AuthType: authType,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		indexes []v1.RepositoryIndex
+		want    []string
+	}{
+		{
+			name:    "only observatoria with the serviceaccount auth type",
+			indexes: []v1.RepositoryIndex{getIndex("a", v1.AuthTypeDex), getIndex("b", v1.AuthTypeServiceAccount)},
+			want:    []string{"default"},
+		},
+		{
+			name:    "observatoria shared by indexes get one token",
+			indexes: []v1.RepositoryIndex{getIndex("a", v1.AuthTypeServiceAccount), getIndex("b", v1.AuthTypeServiceAccount)},
+			want:    []string{"default"},
+		},
+		{
+			name:    "indexes without prometheus config are skipped",
+			indexes: []v1.RepositoryIndex{{Id: "a"}},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, observatorium := range getServiceAccountTokenObservatoria(tt.indexes) {
+				ids = append(ids, observatorium.Id)
+			}
+			Expect(ids).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheus_GetAlerting(t *testing.T) {
+	RegisterTestingT(t)
+
+	r := &Reconciler{}
+	cr := buildObservabilityCR(nil)
+	alerting := r.getAlerting(cr)
+	Expect(alerting.Alertmanagers).To(HaveLen(1))
+	Expect(alerting.Alertmanagers[0].Name).To(Equal(model.GetAlertmanagerCr(cr).Name))
+	Expect(alerting.Alertmanagers[0].Scheme).To(Equal("https"))
+	Expect(alerting.Alertmanagers[0].TLSConfig.CAFile).To(ContainSubstring("service-ca.crt"))
+
+	// Certificates of cert-manager are verified with the CA in their secret
+	cr.Spec.CertManager = &v1.CertManagerSpec{IssuerRef: v1.CertManagerIssuerRef{Name: "ca-issuer"}}
+	alerting = r.getAlerting(cr)
+	Expect(alerting.Alertmanagers[0].TLSConfig.CAFile).To(BeEmpty())
+	Expect(alerting.Alertmanagers[0].TLSConfig.CA.Secret.Name).To(Equal("alertmanager-k8s-tls"))
+	Expect(alerting.Alertmanagers[0].TLSConfig.CA.Secret.Key).To(Equal("ca.crt"))
+	cr.Spec.CertManager = nil
+
+	// The mesh secures the alerts, alertmanager serves plain HTTP without the oauth proxy
+	enabled := true
+	cr.Spec.ServiceMesh = &v1.ServiceMeshSpec{Enabled: &enabled, Authentication: &enabled}
+	alerting = r.getAlerting(cr)
+	Expect(alerting.Alertmanagers[0].Scheme).To(Equal("http"))
+	Expect(alerting.Alertmanagers[0].TLSConfig).To(BeNil())
+	Expect(alerting.Alertmanagers[0].BearerTokenFile).To(BeEmpty())
+	cr.Spec.ServiceMesh = nil
+
+	// Alertmanager serves plain HTTP on Kubernetes, or the certificate of cert-manager itself
+	cr.Spec.ClusterType = v1.ClusterTypeKubernetes
+	alerting = r.getAlerting(cr)
+	Expect(alerting.Alertmanagers[0].Scheme).To(Equal("http"))
+	Expect(alerting.Alertmanagers[0].TLSConfig).To(BeNil())
+	Expect(alerting.Alertmanagers[0].BearerTokenFile).To(BeEmpty())
+
+	cr.Spec.CertManager = &v1.CertManagerSpec{IssuerRef: v1.CertManagerIssuerRef{Name: "ca-issuer"}}
+	alerting = r.getAlerting(cr)
+	Expect(alerting.Alertmanagers[0].Scheme).To(Equal("https"))
+	Expect(alerting.Alertmanagers[0].TLSConfig.CA.Secret.Name).To(Equal("alertmanager-k8s-tls"))
+	cr.Spec.CertManager = nil
+	cr.Spec.ClusterType = ""
+
+	// Without Alertmanager there is nowhere to send the alerts to
+	cr.Spec.Components = &v1.Components{Alertmanager: &v1.ComponentToggle{Enabled: &([]bool{false})[0]}}
+	Expect(r.getAlerting(cr)).To(BeNil())
+}
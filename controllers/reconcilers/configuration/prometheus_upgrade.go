@@ -0,0 +1,200 @@
+package configuration
+
+import (
+	"context"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A blue/green upgrade starts when the version of the existing Prometheus differs from the
+// requested version. The serving Prometheus keeps its version until the candidate is ready.
+func (r *Reconciler) reconcilePrometheusUpgradeStart(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	desired := model.GetPrometheusVersion(cr)
+	upgrade := s.PrometheusUpgrade
+
+	// Disabled or rolled back before the switch, the Prometheus of the CR serves again
+	if upgrade != nil && (!cr.BlueGreenUpgradesEnabled() || (upgrade.Phase == v1.PrometheusUpgradeCandidate && upgrade.FromVersion == desired)) {
+		s.PrometheusUpgrade = nil
+		r.prometheusUpgrade = nil
+		return r.finishPrometheusUpgrade(ctx, cr)
+	}
+
+	if upgrade != nil {
+		upgrade.ToVersion = desired
+		r.prometheusUpgrade = upgrade
+		return nil
+	}
+
+	// Nothing is applied in dry run mode, an upgrade would never progress
+	if !cr.BlueGreenUpgradesEnabled() || cr.DryRunEnabled() {
+		return nil
+	}
+
+	prometheus := model.GetPrometheus(cr)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(prometheus), prometheus)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if prometheus.Spec.Version == "" || prometheus.Spec.Version == desired {
+		return nil
+	}
+
+	s.PrometheusUpgrade = &v1.PrometheusUpgradeStatus{
+		FromVersion: prometheus.Spec.Version,
+		ToVersion:   desired,
+		Phase:       v1.PrometheusUpgradeCandidate,
+	}
+	r.prometheusUpgrade = s.PrometheusUpgrade
+	r.recordEvent(cr, kv1.EventTypeNormal, EventReasonPrometheusUpgradeStarted,
+		"Started blue/green upgrade of Prometheus from %v to %v", prometheus.Spec.Version, desired)
+	return nil
+}
+
+// Version of the Prometheus of the CR, the old one until the candidate serves
+func (r *Reconciler) getPrometheusVersion(cr *v1.Observability) string {
+	if r.prometheusUpgrade != nil && r.prometheusUpgrade.Phase == v1.PrometheusUpgradeCandidate {
+		return r.prometheusUpgrade.FromVersion
+	}
+	return model.GetPrometheusVersion(cr)
+}
+
+// The candidate has the spec of the Prometheus of the CR with the new version. It is short-lived
+// and starts without history instead of claiming volumes of its own. It only serves queries, the
+// Prometheus of the CR keeps remote writing and sending alerts, so that they are not duplicated.
+func (r *Reconciler) reconcilePrometheusCandidate(ctx context.Context, cr *v1.Observability, prometheus *prometheusv1.Prometheus) error {
+	candidate := model.GetPrometheusCandidate(cr)
+	version := r.prometheusUpgrade.ToVersion
+	image := model.GetImage(cr, v1.ImagePrometheus, model.PrometheusBaseImage, version)
+
+	_, err := utils.Apply(ctx, r.client, candidate, func() error {
+		candidate.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, candidate.Labels)
+		candidate.Spec = *prometheus.Spec.DeepCopy()
+		candidate.Spec.Image = &image
+		candidate.Spec.Version = version
+		candidate.Spec.Storage = nil
+		// Only the StatefulSet of the primary is recreated for a storage expansion
+		candidate.Spec.Paused = false
+		candidate.Spec.RemoteWrite = nil
+		candidate.Spec.Alerting = nil
+		// The data volume of the candidate has its own name
+		model.ApplyQueryLogExporterVolumeMount(cr, candidate)
+		return nil
+	})
+	return err
+}
+
+// Switch the service to the candidate once it is ready, and back once the Prometheus of the CR runs
+// the new version. Readiness of the pods includes the replay of the WAL.
+func (r *Reconciler) reconcilePrometheusUpgradeProgress(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	upgrade := s.PrometheusUpgrade
+	if upgrade == nil {
+		return nil
+	}
+
+	switch upgrade.Phase {
+	case v1.PrometheusUpgradeCandidate:
+		candidate := model.GetPrometheusCandidate(cr)
+		ready, err := r.isPrometheusRolledOut(ctx, cr, candidate.Name, upgrade.ToVersion)
+		if err != nil || !ready {
+			return err
+		}
+
+		upgrade.Phase = v1.PrometheusUpgradeSwitched
+		err = r.switchPrometheusService(ctx, cr, model.GetPrometheusServingName(cr, upgrade))
+		if err != nil {
+			return err
+		}
+		r.recordEvent(cr, kv1.EventTypeNormal, EventReasonPrometheusTrafficSwitched,
+			"Switched Prometheus service to %v running %v", candidate.Name, upgrade.ToVersion)
+	case v1.PrometheusUpgradeSwitched:
+		prometheus := model.GetPrometheus(cr)
+		ready, err := r.isPrometheusRolledOut(ctx, cr, prometheus.Name, upgrade.ToVersion)
+		if err != nil || !ready {
+			return err
+		}
+
+		s.PrometheusUpgrade = nil
+		r.prometheusUpgrade = nil
+		err = r.finishPrometheusUpgrade(ctx, cr)
+		if err != nil {
+			return err
+		}
+		r.recordEvent(cr, kv1.EventTypeNormal, EventReasonPrometheusTrafficSwitched,
+			"Switched Prometheus service back to %v running %v", prometheus.Name, upgrade.ToVersion)
+	}
+	return nil
+}
+
+// Point the service at the Prometheus of the CR again and remove the candidate
+func (r *Reconciler) finishPrometheusUpgrade(ctx context.Context, cr *v1.Observability) error {
+	err := r.switchPrometheusService(ctx, cr, model.GetDefaultNamePrometheus(cr))
+	if err != nil {
+		return err
+	}
+
+	err = r.client.Delete(ctx, model.GetPrometheusCandidate(cr))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *Reconciler) switchPrometheusService(ctx context.Context, cr *v1.Observability, name string) error {
+	service := model.GetPrometheusService(cr)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(service), service)
+	if err != nil {
+		return err
+	}
+
+	service.Spec.Selector = map[string]string{
+		"prometheus": name,
+	}
+	return r.client.Update(ctx, service)
+}
+
+func (r *Reconciler) isPrometheusRolledOut(ctx context.Context, cr *v1.Observability, name string, version string) (bool, error) {
+	statefulSet := model.GetPrometheusStatefulSet(cr, name)
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(statefulSet), statefulSet)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return isStatefulSetRolledOut(statefulSet, model.GetImage(cr, v1.ImagePrometheus, model.PrometheusBaseImage, version)), nil
+}
+
+// All replicas are ready and run the given image of the prometheus container
+func isStatefulSetRolledOut(statefulSet *appsv1.StatefulSet, image string) bool {
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	hasImage := false
+	for _, container := range statefulSet.Spec.Template.Spec.Containers {
+		if container.Name == "prometheus" && container.Image == image {
+			hasImage = true
+		}
+	}
+
+	status := statefulSet.Status
+	return hasImage &&
+		status.ObservedGeneration >= statefulSet.Generation &&
+		status.UpdateRevision == status.CurrentRevision &&
+		status.UpdatedReplicas == replicas &&
+		status.ReadyReplicas == replicas
+}
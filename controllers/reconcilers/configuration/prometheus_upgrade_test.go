@@ -0,0 +1,120 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	appsv1 "k8s.io/api/apps/v1"
+	kv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPrometheusUpgrade_IsStatefulSetRolledOut(t *testing.T) {
+	getStatefulSet := func(image string, status appsv1.StatefulSetStatus) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: appsv1.StatefulSetSpec{
+				Template: kv1.PodTemplateSpec{
+					Spec: kv1.PodSpec{
+						Containers: []kv1.Container{
+							{Name: "prometheus", Image: image},
+							{Name: "oauth-proxy", Image: "quay.io/openshift/origin-oauth-proxy:4.8"},
+						},
+					},
+				},
+			},
+			Status: status,
+		}
+	}
+	rolledOut := appsv1.StatefulSetStatus{
+		ObservedGeneration: 2,
+		CurrentRevision:    "b",
+		UpdateRevision:     "b",
+		UpdatedReplicas:    1,
+		ReadyReplicas:      1,
+	}
+
+	tests := []struct {
+		name        string
+		statefulSet *appsv1.StatefulSet
+		want        bool
+	}{
+		{
+			name:        "all replicas ready with the new image",
+			statefulSet: getStatefulSet("quay.io/prometheus/prometheus:v2.36.2", rolledOut),
+			want:        true,
+		},
+		{
+			name:        "not yet updated by the prometheus operator",
+			statefulSet: getStatefulSet("quay.io/prometheus/prometheus:v2.35.0", rolledOut),
+			want:        false,
+		},
+		{
+			name: "replica still replaying the WAL",
+			statefulSet: getStatefulSet("quay.io/prometheus/prometheus:v2.36.2", appsv1.StatefulSetStatus{
+				ObservedGeneration: 2,
+				CurrentRevision:    "b",
+				UpdateRevision:     "b",
+				UpdatedReplicas:    1,
+				ReadyReplicas:      0,
+			}),
+			want: false,
+		},
+		{
+			name: "rollout not observed yet",
+			statefulSet: getStatefulSet("quay.io/prometheus/prometheus:v2.36.2", appsv1.StatefulSetStatus{
+				ObservedGeneration: 1,
+				CurrentRevision:    "a",
+				UpdateRevision:     "a",
+				UpdatedReplicas:    1,
+				ReadyReplicas:      1,
+			}),
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isStatefulSetRolledOut(tt.statefulSet, "quay.io/prometheus/prometheus:v2.36.2")
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPrometheusUpgrade_ReconcilePrometheusCandidate(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = prometheusv1.AddToScheme(scheme)
+	r := &Reconciler{
+		client:            testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build()),
+		prometheusUpgrade: &v1.PrometheusUpgradeStatus{FromVersion: "v2.35.0", ToVersion: "v2.36.2", Phase: v1.PrometheusUpgradeCandidate},
+	}
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Name: "observability-stack", Namespace: "observability"}}
+
+	prometheus := model.GetPrometheus(cr)
+	prometheus.Spec.Version = "v2.35.0"
+	prometheus.Spec.RemoteWrite = []prometheusv1.RemoteWriteSpec{{URL: "https://observatorium.example.com/api/metrics/v1/test/api/v1/receive"}}
+	prometheus.Spec.Alerting = &prometheusv1.AlertingSpec{Alertmanagers: []prometheusv1.AlertmanagerEndpoints{{Name: "alertmanager-operated"}}}
+	prometheus.Spec.Storage = &prometheusv1.StorageSpec{}
+	prometheus.Spec.RuleSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "rhoc"}}
+	Expect(r.reconcilePrometheusCandidate(context.TODO(), cr, prometheus)).To(Succeed())
+
+	// The candidate only serves queries
+	candidate := &prometheusv1.Prometheus{}
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(model.GetPrometheusCandidate(cr)), candidate)).To(Succeed())
+	Expect(candidate.Spec.Version).To(Equal("v2.36.2"))
+	Expect(candidate.Spec.RuleSelector).To(Equal(prometheus.Spec.RuleSelector))
+	Expect(candidate.Spec.RemoteWrite).To(BeEmpty())
+	Expect(candidate.Spec.Alerting).To(BeNil())
+	Expect(candidate.Spec.Storage).To(BeNil())
+}
@@ -9,12 +9,12 @@ import (
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
 	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
 	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // Get the namespaces in which this Promtail instance should scrape the logs from all pods
@@ -24,6 +24,11 @@ func (r *Reconciler) getScrapeNamespacesFor(ctx context.Context, cr *v1.Observab
 		return nil, nil
 	}
 
+	// Namespaces can't be listed without cluster wide permissions
+	if cr.NamespaceScoped() {
+		return cr.Spec.TargetNamespaces, nil
+	}
+
 	var result []string
 	list := &v12.NamespaceList{}
 	selector := labels.SelectorFromSet(index.Config.Promtail.NamespaceLabelSelector)
@@ -40,6 +45,11 @@ func (r *Reconciler) getScrapeNamespacesFor(ctx context.Context, cr *v1.Observab
 		result = append(result, ns.Name)
 	}
 
+	// Namespaces that opted in are scraped as well
+	if cr.NamespaceDiscoveryEnabled() {
+		result = mergeNamespaces(result, cr.Status.DiscoveredNamespaces)
+	}
+
 	return result, nil
 }
 
@@ -53,7 +63,7 @@ func (r *Reconciler) createPromtailConfigFor(ctx context.Context, cr *v1.Observa
 	configMap := model.GetPromtailConfigmap(cr, index.Id)
 	config, err := model.GetPromtailConfig(cr, observatorium, index.Id, namespaces)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, configMap, func() error {
+	_, err = utils.Apply(ctx, r.client, configMap, func() error {
 		configMap.Labels = map[string]string{
 			"managed-by": "observability-operator",
 		}
@@ -88,12 +98,17 @@ func (r *Reconciler) deleteUnrequestedDaemonsets(ctx context.Context, cr *v1.Obs
 	shouldExist := func(name string) bool {
 		// Always remove promtail if observatorium or external sync is disabled
 		// Without observatorium we have no place to send the logs
-		if cr.ExternalSyncDisabled() || cr.ObservatoriumDisabled() {
+		if cr.ExternalSyncDisabled() || cr.ObservatoriumDisabled() || !cr.PromtailEnabled() {
 			return false
 		}
 
 		for _, index := range indexes {
 			expectedName := fmt.Sprintf("promtail-%s", index.Id)
+			for _, architecture := range model.GetOverriddenArchitectures(cr, v1.ImagePromtail) {
+				if name == fmt.Sprintf("%s-%s", expectedName, architecture) {
+					expectedName = name
+				}
+			}
 			if name == expectedName {
 				// No promtail configuration
 				if index.Config == nil || index.Config.Promtail == nil {
@@ -134,31 +149,48 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 
 	// Without Observatorium there is no need to install Promtail, because we're not
 	// running on cluster Loki
-	if cr.ObservatoriumDisabled() || cr.ExternalSyncDisabled() {
+	if cr.ObservatoriumDisabled() || cr.ExternalSyncDisabled() || !cr.PromtailEnabled() {
 		return nil
 	}
 
 	if index.Config.Promtail.Observatorium == "" {
-		r.logger.Info(fmt.Sprintf("skip creating promtail daemonset for %v because observatorium config is missing", index.Id))
+		r.logger.Info("skip creating promtail daemonset because observatorium config is missing", "index", index.Id)
 		return nil
 	}
 
 	observatoriumConfig := token.GetObservatoriumConfig(index, index.Config.Promtail.Observatorium)
 	if observatoriumConfig == nil {
-		r.logger.Info(fmt.Sprintf("skip creating promtail daemonset for %v because observatorium config is missing", index.Id))
+		r.logger.Info("skip creating promtail daemonset because observatorium config is missing", "index", index.Id)
 		return nil
 	}
 
-	daemonset := model.GetPromtailDaemonSet(cr, index.Id)
-	sa := model.GetPromtailServiceAccount(cr)
-
 	config, hash, err := r.createPromtailConfigFor(ctx, cr, index, observatoriumConfig)
 	if err != nil {
 		return err
 	}
 
-	var t = true
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, daemonset, func() error {
+	// The promtail config is covered by its own hash
+	contentHash, err := r.getMountedContentHash(ctx, cr.Namespace, nil, []string{model.GetTrustedCABundleConfigMap(cr).Name})
+	if err != nil {
+		return err
+	}
+
+	// Architectures with an image override get their own daemonset
+	architectures := append([]string{""}, model.GetOverriddenArchitectures(cr, v1.ImagePromtail)...)
+	for _, architecture := range architectures {
+		err = r.createPromtailDaemonset(ctx, cr, index, observatoriumConfig, config, hash, contentHash, architecture)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) createPromtailDaemonset(ctx context.Context, cr *v1.Observability, index *v1.RepositoryIndex, observatoriumConfig *v1.ObservatoriumIndex, config *v12.ConfigMap, hash []byte, contentHash string, architecture string) error {
+	daemonset := model.GetPromtailDaemonSetForArchitecture(cr, index.Id, architecture)
+	sa := model.GetPromtailServiceAccount(cr)
+
+	_, err := utils.Apply(ctx, r.client, daemonset, func() error {
 		daemonset.Labels = map[string]string{
 			"managed-by": "observability-operator",
 		}
@@ -167,24 +199,14 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 			Template: v12.PodTemplateSpec{
 				ObjectMeta: v14.ObjectMeta{
 					Labels: model.GetPromtailDaemonSetLabels(index).MatchLabels,
+					Annotations: map[string]string{
+						ContentHashAnnotation: contentHash,
+					},
 				},
 				Spec: v12.PodSpec{
-					Affinity: &v12.Affinity{
-						NodeAffinity: &v12.NodeAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: &v12.NodeSelector{
-								NodeSelectorTerms: []v12.NodeSelectorTerm{
-									{
-										MatchExpressions: []v12.NodeSelectorRequirement{
-											{
-												Key:      "node-role.kubernetes.io/infra",
-												Operator: "DoesNotExist",
-											},
-										},
-									},
-								},
-							},
-						},
-					},
+					Affinity:           model.GetPromtailArchitectureAffinity(cr, architecture),
+					NodeSelector:       model.GetPromtailNodeSelector(cr),
+					Tolerations:        model.GetPromtailTolerations(cr),
 					ServiceAccountName: sa.Name,
 					Volumes: []v12.Volume{
 						{
@@ -205,17 +227,15 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 								},
 							},
 						},
+						model.GetTrustedCABundleVolume(cr),
 					},
 					PriorityClassName: model.ObservabilityPriorityClassName,
 					Containers: []v12.Container{
 						{
-							Name:  "promtail",
-							Image: "quay.io/integreatly/promtail:latest",
-
-							SecurityContext: &v12.SecurityContext{
-								Privileged: &t,
-							},
-							Env: []v12.EnvVar{
+							Name:            "promtail",
+							Image:           model.GetImageForArchitecture(cr, v1.ImagePromtail, architecture, model.PromtailImage, model.PromtailImageTag),
+							SecurityContext: model.GetPromtailSecurityContext(cr),
+							Env: append([]v12.EnvVar{
 								{
 									Name: "HOSTNAME",
 									ValueFrom: &v12.EnvVarSource{
@@ -228,7 +248,7 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 									Name:  "CONFIG_HASH",
 									Value: fmt.Sprintf("%x", hash),
 								},
-							},
+							}, model.GetProxyEnvVars(r.clusterProxy)...),
 							Args: []string{
 								"-config.file=/opt/config/promtail.yaml",
 							},
@@ -241,6 +261,7 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 									Name:      "logs",
 									MountPath: "/var/log/pods",
 								},
+								model.GetTrustedCABundleVolumeMount(),
 							},
 							Ports: []v12.ContainerPort{
 								{
@@ -248,6 +269,7 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 									Protocol:      "TCP",
 								},
 							},
+							Resources:                model.GetRecommendedResourceRequirement(cr, model.ComponentPromtail, "promtail", *model.GetPromtailResourceRequirement(cr)),
 							TerminationMessagePath:   "/dev/termination-log",
 							TerminationMessagePolicy: "File",
 							ImagePullPolicy:          "Always",
@@ -273,7 +295,19 @@ func (r *Reconciler) createPromtailDaemonsetFor(ctx context.Context, cr *v1.Obse
 					Name:      "token",
 					MountPath: "/opt/secrets",
 				})
+			}
+
+			// Promtail reads the token file from the same place as the Dex token
+			if observatoriumConfig.AuthType == v1.AuthTypeServiceAccount {
+				volume := model.GetObservatoriumServiceAccountTokenVolume(observatoriumConfig)
+				volume.Name = "token"
+				daemonset.Spec.Template.Spec.Volumes = append(daemonset.Spec.Template.Spec.Volumes, volume)
 
+				daemonset.Spec.Template.Spec.Containers[0].VolumeMounts = append(daemonset.Spec.Template.Spec.Containers[0].VolumeMounts, v12.VolumeMount{
+					Name:      "token",
+					MountPath: "/opt/secrets",
+					ReadOnly:  true,
+				})
 			}
 		}
 		return nil
@@ -0,0 +1,88 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Datasources of the long-term storage, so that dashboards cover the whole retention of Observatorium
+// and not only the one of the local Prometheus. The queries go through the query token refreshers.
+func (r *Reconciler) reconcileQueryDatasource(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	datasources := getQueryDatasources(cr, indexes)
+	if len(datasources) == 0 {
+		return r.deleteQueryDatasource(ctx, cr)
+	}
+
+	datasource := model.GetGrafanaQueryDatasource(cr)
+	_, err := utils.Apply(ctx, r.client, datasource, func() error {
+		datasource.Labels = map[string]string{
+			"managed-by": "observability-operator",
+		}
+		datasource.Spec.Name = "obs-observatorium.yaml"
+		datasource.Spec.Datasources = datasources
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) deleteQueryDatasource(ctx context.Context, cr *v1.Observability) error {
+	err := r.client.Delete(ctx, model.GetGrafanaQueryDatasource(cr))
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+// Indexes with a Grafana section request the datasource unless they opt out
+func isQueryDatasourceRequested(cr *v1.Observability, index *v1.RepositoryIndex) bool {
+	if !cr.GrafanaEnabled() || index.Config == nil || index.Config.Grafana == nil {
+		return false
+	}
+	return index.Config.Grafana.ObservatoriumDatasourceEnabled()
+}
+
+// One datasource per Observatorium instance with a query token refresher, instances referenced by
+// multiple indexes are only added once
+func getQueryDatasources(cr *v1.Observability, indexes []v1.RepositoryIndex) []v1alpha1.GrafanaDataSourceFields {
+	if cr.ObservatoriumDisabled() || cr.ExternalSyncDisabled() {
+		return nil
+	}
+
+	var result []v1alpha1.GrafanaDataSourceFields
+	seen := map[string]bool{}
+	for _, index := range indexes {
+		if !isQueryDatasourceRequested(cr, &index) {
+			continue
+		}
+
+		for _, observatorium := range index.Config.Observatoria {
+			if observatorium.AuthType != v1.AuthTypeRedhat || !observatorium.IsValid() || observatorium.RedhatSsoConfig == nil || !observatorium.RedhatSsoConfig.HasQueries() {
+				continue
+			}
+			if seen[observatorium.Id] {
+				continue
+			}
+			seen[observatorium.Id] = true
+
+			result = append(result, v1alpha1.GrafanaDataSourceFields{
+				Name:     fmt.Sprintf("Observatorium %v", observatorium.Id),
+				Type:     "prometheus",
+				Access:   "proxy",
+				Url:      model.GetTokenRefresherUrl(cr, observatorium.Id, model.QueryTokenRefresher),
+				Version:  1,
+				Editable: true,
+				JsonData: v1alpha1.GrafanaDataSourceJsonData{
+					TimeInterval: "30s",
+				},
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+)
+
+func TestQueryDatasource_GetQueryDatasources(t *testing.T) {
+	RegisterTestingT(t)
+
+	redhat := *testObservatoriumSsoConfigHasMetrics
+	redhat.AuthType = v1.AuthTypeRedhat
+	dex := redhat
+	dex.Id = "dex"
+	dex.AuthType = v1.AuthTypeDex
+	optOut := redhat
+	optOut.Id = "opt-out"
+	disabled := false
+	indexes := []v1.RepositoryIndex{
+		{Id: "a", Config: &v1.RepositoryConfig{Grafana: &v1.GrafanaIndex{}, Observatoria: []v1.ObservatoriumIndex{redhat, dex}}},
+		{Id: "b", Config: &v1.RepositoryConfig{Grafana: &v1.GrafanaIndex{}, Observatoria: []v1.ObservatoriumIndex{redhat}}},
+		{Id: "c"},
+		{Id: "d", Config: &v1.RepositoryConfig{Grafana: &v1.GrafanaIndex{ObservatoriumDatasource: &disabled}, Observatoria: []v1.ObservatoriumIndex{optOut}}},
+		{Id: "e", Config: &v1.RepositoryConfig{Observatoria: []v1.ObservatoriumIndex{optOut}}},
+	}
+
+	datasources := getQueryDatasources(buildObservabilityCR(nil), indexes)
+	Expect(datasources).To(HaveLen(1))
+	Expect(datasources[0].Name).To(Equal("Observatorium test-id"))
+	Expect(datasources[0].Type).To(Equal("prometheus"))
+	Expect(datasources[0].IsDefault).To(BeFalse())
+	Expect(datasources[0].Url).To(Equal("http://token-refresher-query-test-id.test-namespace.svc.cluster.local"))
+
+	withoutGrafana := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.Components = &v1.Components{Grafana: &v1.ComponentToggle{Enabled: &disabled}}
+	})
+	Expect(getQueryDatasources(withoutGrafana, indexes)).To(BeEmpty())
+	Expect(getTokenRefresherTypes(withoutGrafana, &indexes[0])).NotTo(ContainElement(model.QueryTokenRefresher))
+	Expect(getTokenRefresherTypes(buildObservabilityCR(nil), &indexes[0])).To(ContainElement(model.QueryTokenRefresher))
+	Expect(getTokenRefresherTypes(buildObservabilityCR(nil), &indexes[3])).NotTo(ContainElement(model.QueryTokenRefresher))
+	Expect(getTokenRefresherTypes(buildObservabilityCR(nil), &indexes[4])).NotTo(ContainElement(model.QueryTokenRefresher))
+}
@@ -0,0 +1,23 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	core "k8s.io/api/core/v1"
+)
+
+// Sidecar of Prometheus that runs the operator image, the query log is kept without it
+func (r *Reconciler) getQueryLogExporterContainer(ctx context.Context, cr *v1.Observability) (core.Container, error) {
+	threshold, err := model.GetQueryLogSlowQueryThreshold(cr)
+	if err != nil {
+		return core.Container{}, err
+	}
+	err = r.ensureOperatorImage(ctx, cr, v1.ImageQueryLogExporter)
+	if err != nil {
+		return core.Container{}, fmt.Errorf("error detecting the image of the query log exporter: %v", err)
+	}
+	return model.GetQueryLogExporterContainer(cr, r.operatorImage, threshold), nil
+}
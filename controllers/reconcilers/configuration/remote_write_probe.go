@@ -0,0 +1,141 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers/token"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Read the latest probe sample back from the Observatorium instances that the indexes remote write
+// to. Only instances with sso.redhat.com authentication can be read through a query token refresher,
+// the others are not probed. Instances are verified once per interval, as recorded in the status.
+func (r *Reconciler) reconcileRemoteWriteProbe(cr *v1.Observability, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) {
+	metrics.SetRemoteWriteProbeEnabled(cr.RemoteWriteProbeEnabled())
+	if !cr.RemoteWriteProbeEnabled() || cr.ObservatoriumDisabled() {
+		s.RemoteWriteProbes = nil
+		metrics.SetRemoteWriteProbeMetrics(nil)
+		return
+	}
+
+	ids := getRemoteWriteProbeObservatoria(indexes)
+	if time.Since(getRemoteWriteProbesChecked(s.RemoteWriteProbes, ids)) < model.GetRemoteWriteProbeInterval(cr) {
+		metrics.SetRemoteWriteProbeMetrics(s.RemoteWriteProbes)
+		return
+	}
+
+	query := model.GetRemoteWriteProbeQuery(cr)
+	sla := model.GetRemoteWriteProbeSla(cr)
+	var probes []v1.RemoteWriteProbeStatus
+	for _, id := range ids {
+		samples, err := utils.QueryVector(r.httpClient, model.GetTokenRefresherUrl(cr, id, model.QueryTokenRefresher), query)
+		probes = append(probes, getRemoteWriteProbeStatus(id, samples, err, time.Now(), sla))
+	}
+
+	s.RemoteWriteProbes = probes
+	metrics.SetRemoteWriteProbeMetrics(probes)
+}
+
+// Earliest verification of the instances, zero if one of them wasn't verified yet or the status lists
+// other instances
+func getRemoteWriteProbesChecked(probes []v1.RemoteWriteProbeStatus, ids []string) time.Time {
+	if len(probes) != len(ids) {
+		return time.Time{}
+	}
+	checked := map[string]int64{}
+	for _, probe := range probes {
+		checked[probe.Observatorium] = probe.LastChecked
+	}
+	var result time.Time
+	for _, id := range ids {
+		lastChecked, ok := checked[id]
+		if !ok {
+			return time.Time{}
+		}
+		if result.IsZero() || time.Unix(lastChecked, 0).Before(result) {
+			result = time.Unix(lastChecked, 0)
+		}
+	}
+	return result
+}
+
+// Alert on failed probes, removed when the alert or the probe is disabled
+func (r *Reconciler) reconcileRemoteWriteProbeAlert(ctx context.Context, cr *v1.Observability) error {
+	rule := model.GetRemoteWriteProbeRule(cr)
+	if !cr.RemoteWriteProbeAlertEnabled() {
+		err := r.client.Delete(ctx, rule)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, getRuleSelectorLabels(cr))
+		rule.Spec.Groups = []prometheusv1.RuleGroup{
+			model.GetRemoteWriteProbeRuleGroup(),
+		}
+		injectClusterLabels(cr, rule)
+		return nil
+	})
+	return err
+}
+
+// The probe reads through the query token refresher of the index
+func isRemoteWriteProbeRequested(cr *v1.Observability, index *v1.RepositoryIndex) bool {
+	return cr.RemoteWriteProbeEnabled() && index.Config != nil && index.Config.Prometheus != nil && index.Config.Prometheus.Observatorium != ""
+}
+
+// Observatorium instances that the indexes remote write to and that can be read back
+func getRemoteWriteProbeObservatoria(indexes []v1.RepositoryIndex) []string {
+	var result []string
+	seen := map[string]bool{}
+	for i := range indexes {
+		index := &indexes[i]
+		if index.Config == nil || index.Config.Prometheus == nil || index.Config.Prometheus.Observatorium == "" {
+			continue
+		}
+		observatorium := token.GetObservatoriumConfig(index, index.Config.Prometheus.Observatorium)
+		if observatorium == nil || observatorium.AuthType != v1.AuthTypeRedhat || observatorium.RedhatSsoConfig == nil || !observatorium.RedhatSsoConfig.HasQueries() {
+			continue
+		}
+		if !seen[observatorium.Id] {
+			seen[observatorium.Id] = true
+			result = append(result, observatorium.Id)
+		}
+	}
+	return result
+}
+
+// The value of the sample is the time it was scraped, the probe succeeds if it isn't older than the SLA
+func getRemoteWriteProbeStatus(id string, samples []utils.VectorSample, err error, now time.Time, sla time.Duration) v1.RemoteWriteProbeStatus {
+	status := v1.RemoteWriteProbeStatus{
+		Observatorium: id,
+		LastChecked:   now.Unix(),
+	}
+	if err != nil {
+		status.Message = fmt.Sprintf("error querying observatorium: %v", err)
+		return status
+	}
+	if len(samples) == 0 {
+		status.Message = "no probe sample found"
+		return status
+	}
+
+	status.LastSample = int64(samples[0].Value)
+	lag := now.Sub(time.Unix(status.LastSample, 0))
+	status.Success = lag <= sla
+	if !status.Success {
+		status.Message = fmt.Sprintf("latest probe sample is %v old, exceeds the sla of %v", lag.Round(time.Second), sla)
+	}
+	return status
+}
@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+)
+
+func TestRemoteWriteProbe_GetRemoteWriteProbeStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	now := time.Unix(1000, 0)
+	sla := 5 * time.Minute
+
+	status := getRemoteWriteProbeStatus("obs", []utils.VectorSample{{Value: 900}}, nil, now, sla)
+	Expect(status).To(Equal(v1.RemoteWriteProbeStatus{Observatorium: "obs", Success: true, LastSample: 900, LastChecked: 1000}))
+
+	status = getRemoteWriteProbeStatus("obs", []utils.VectorSample{{Value: 600}}, nil, now, sla)
+	Expect(status.Success).To(BeFalse())
+	Expect(status.LastSample).To(Equal(int64(600)))
+	Expect(status.Message).To(Equal("latest probe sample is 6m40s old, exceeds the sla of 5m0s"))
+
+	status = getRemoteWriteProbeStatus("obs", nil, nil, now, sla)
+	Expect(status.Success).To(BeFalse())
+	Expect(status.Message).To(Equal("no probe sample found"))
+
+	status = getRemoteWriteProbeStatus("obs", nil, errors.New("unavailable"), now, sla)
+	Expect(status.Success).To(BeFalse())
+	Expect(status.Message).To(Equal("error querying observatorium: unavailable"))
+}
+
+func TestRemoteWriteProbe_GetRemoteWriteProbeObservatoria(t *testing.T) {
+	RegisterTestingT(t)
+
+	redhat := *testObservatoriumSsoConfigHasMetrics
+	redhat.AuthType = v1.AuthTypeRedhat
+	dex := v1.ObservatoriumIndex{Id: "dex", AuthType: v1.AuthTypeDex}
+	indexes := []v1.RepositoryIndex{
+		{Id: "a", Config: &v1.RepositoryConfig{Prometheus: &v1.PrometheusIndex{Observatorium: redhat.Id}, Observatoria: []v1.ObservatoriumIndex{redhat}}},
+		{Id: "b", Config: &v1.RepositoryConfig{Prometheus: &v1.PrometheusIndex{Observatorium: redhat.Id}, Observatoria: []v1.ObservatoriumIndex{redhat}}},
+		{Id: "c", Config: &v1.RepositoryConfig{Prometheus: &v1.PrometheusIndex{Observatorium: dex.Id}, Observatoria: []v1.ObservatoriumIndex{dex}}},
+		{Id: "d", Config: &v1.RepositoryConfig{Observatoria: []v1.ObservatoriumIndex{redhat}}},
+	}
+	Expect(getRemoteWriteProbeObservatoria(indexes)).To(Equal([]string{"test-id"}))
+
+	cr := buildObservabilityCR(nil)
+	Expect(isRemoteWriteProbeRequested(cr, &indexes[0])).To(BeFalse())
+	enabled := true
+	cr.Spec.RemoteWriteProbe = &v1.RemoteWriteProbe{Enabled: &enabled}
+	Expect(isRemoteWriteProbeRequested(cr, &indexes[0])).To(BeTrue())
+	Expect(isRemoteWriteProbeRequested(cr, &indexes[3])).To(BeFalse())
+}
+
+func TestRemoteWriteProbe_GetRemoteWriteProbesChecked(t *testing.T) {
+	RegisterTestingT(t)
+
+	probes := []v1.RemoteWriteProbeStatus{{Observatorium: "a", LastChecked: 2000}, {Observatorium: "b", LastChecked: 1000}}
+	Expect(getRemoteWriteProbesChecked(probes, []string{"a", "b"})).To(Equal(time.Unix(1000, 0)))
+
+	// Instances that weren't verified yet are probed right away
+	Expect(getRemoteWriteProbesChecked(probes, []string{"a", "c"})).To(BeZero())
+	Expect(getRemoteWriteProbesChecked(probes, []string{"a"})).To(BeZero())
+	Expect(getRemoteWriteProbesChecked(nil, []string{"a"})).To(BeZero())
+}
+
+func TestRemoteWriteProbe_ReconcileRemoteWriteProbe(t *testing.T) {
+	RegisterTestingT(t)
+
+	redhat := *testObservatoriumSsoConfigHasMetrics
+	redhat.AuthType = v1.AuthTypeRedhat
+	indexes := []v1.RepositoryIndex{
+		{Id: "a", Config: &v1.RepositoryConfig{Prometheus: &v1.PrometheusIndex{Observatorium: redhat.Id}, Observatoria: []v1.ObservatoriumIndex{redhat}}},
+	}
+	enabled := true
+	cr := buildObservabilityCR(func(obsCR *v1.Observability) {
+		obsCR.Spec.RemoteWriteProbe = &v1.RemoteWriteProbe{Enabled: &enabled}
+	})
+
+	// A new reconciler of the next sync doesn't probe again within the interval, it has no HTTP client
+	checked := v1.RemoteWriteProbeStatus{Observatorium: redhat.Id, Success: true, LastChecked: time.Now().Unix()}
+	status := &v1.ObservabilityStatus{RemoteWriteProbes: []v1.RemoteWriteProbeStatus{checked}}
+	(&Reconciler{}).reconcileRemoteWriteProbe(cr, indexes, status)
+	Expect(status.RemoteWriteProbes).To(Equal([]v1.RemoteWriteProbeStatus{checked}))
+
+	cr.Spec.RemoteWriteProbe.Enabled = nil
+	(&Reconciler{}).reconcileRemoteWriteProbe(cr, indexes, status)
+	Expect(status.RemoteWriteProbes).To(BeNil())
+}
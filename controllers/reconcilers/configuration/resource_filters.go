@@ -0,0 +1,57 @@
+package configuration
+
+import (
+	"fmt"
+	"regexp"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Whether a rule or dashboard of the indexes passes the filter of the CR
+func isResourceSelected(filter *v1.ResourceFilter, name string, resourceLabels map[string]string) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+
+	if filter.Include != nil {
+		included, err := matchesResourceSelector(filter.Include, name, resourceLabels)
+		if err != nil || !included {
+			return false, err
+		}
+	}
+
+	if filter.Exclude != nil {
+		excluded, err := matchesResourceSelector(filter.Exclude, name, resourceLabels)
+		if err != nil || excluded {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchesResourceSelector(selector *v1.ResourceSelector, name string, resourceLabels map[string]string) (bool, error) {
+	for _, pattern := range selector.Names {
+		matcher, err := regexp.Compile(fmt.Sprintf("^(?:%v)$", pattern))
+		if err != nil {
+			return false, fmt.Errorf("invalid name pattern %v: %v", pattern, err)
+		}
+		if matcher.MatchString(name) {
+			return true, nil
+		}
+	}
+
+	if selector.Labels != nil {
+		labelSelector, err := v14.LabelSelectorAsSelector(selector.Labels)
+		if err != nil {
+			return false, fmt.Errorf("invalid label selector: %v", err)
+		}
+		if labelSelector.Matches(labels.Set(resourceLabels)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,101 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceFilters_IsResourceSelected(t *testing.T) {
+	type args struct {
+		filter *v1.ResourceFilter
+		name   string
+		labels map[string]string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "all resources without filter",
+			args: args{
+				filter: nil,
+				name:   "kafka-rules",
+			},
+			want: true,
+		},
+		{
+			name: "resource not matching the include names",
+			args: args{
+				filter: &v1.ResourceFilter{
+					Include: &v1.ResourceSelector{Names: []string{"kafka-.*"}},
+				},
+				name: "connectors-rules",
+			},
+			want: false,
+		},
+		{
+			name: "names must match completely",
+			args: args{
+				filter: &v1.ResourceFilter{
+					Include: &v1.ResourceSelector{Names: []string{"kafka"}},
+				},
+				name: "kafka-rules",
+			},
+			want: false,
+		},
+		{
+			name: "included by labels and excluded by name",
+			args: args{
+				filter: &v1.ResourceFilter{
+					Include: &v1.ResourceSelector{
+						Labels: &v14.LabelSelector{MatchLabels: map[string]string{"team": "kafka"}},
+					},
+					Exclude: &v1.ResourceSelector{Names: []string{".*-canary"}},
+				},
+				name:   "kafka-canary",
+				labels: map[string]string{"team": "kafka"},
+			},
+			want: false,
+		},
+		{
+			name: "included by labels",
+			args: args{
+				filter: &v1.ResourceFilter{
+					Include: &v1.ResourceSelector{
+						Labels: &v14.LabelSelector{MatchLabels: map[string]string{"team": "kafka"}},
+					},
+					Exclude: &v1.ResourceSelector{Names: []string{".*-canary"}},
+				},
+				name:   "kafka-rules",
+				labels: map[string]string{"team": "kafka"},
+			},
+			want: true,
+		},
+		{
+			name: "invalid name pattern",
+			args: args{
+				filter: &v1.ResourceFilter{
+					Exclude: &v1.ResourceSelector{Names: []string{"kafka-("}},
+				},
+				name: "kafka-rules",
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := isResourceSelected(tt.args.filter, tt.args.name, tt.args.labels)
+			Expect(err != nil).To(Equal(tt.wantErr))
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package configuration
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Requests are set above the observed usage to absorb spikes between samples
+	resourceHeadroomFactor = 1.3
+	// Recommendations shrink by at most this factor per sync, so that short idle
+	// periods do not undo the recommendation for the usual load
+	resourceDecayFactor = 0.85
+	// Recommendations only change if they differ from the previous one by more than this ratio,
+	// every change restarts the pods. Has to be below the decay.
+	resourceChangeRatio = 0.1
+)
+
+// Subset of the metrics.k8s.io PodMetrics type
+type podMetrics struct {
+	Containers []struct {
+		Name  string           `json:"name"`
+		Usage kv1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+// Prometheus runs in the namespace of the Prometheus operator, the other components in the namespace of the CR
+func getResourceRecommendationNamespace(cr *v1.Observability, component string) string {
+	if component == model.ComponentPrometheus {
+		return cr.GetPrometheusOperatorNamespace()
+	}
+	return cr.Namespace
+}
+
+// Sample the current CPU and memory usage of the managed components from the metrics API
+// and update the resource recommendations in the status
+func (r *Reconciler) reconcileResourceRecommendations(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) error {
+	selectors := map[string][]*metav1.LabelSelector{
+		model.ComponentPrometheus: {
+			{
+				MatchLabels: map[string]string{
+					"prometheus": model.GetDefaultNamePrometheus(cr),
+				},
+			},
+		},
+		model.ComponentGrafana: {
+			{
+				MatchLabels: map[string]string{
+					"app": "grafana",
+				},
+			},
+		},
+	}
+	for i := range indexes {
+		selectors[model.ComponentPromtail] = append(selectors[model.ComponentPromtail], model.GetPromtailDaemonSetLabels(&indexes[i]))
+	}
+
+	var recommendations []v1.ResourceRecommendation
+	for _, component := range []string{model.ComponentPrometheus, model.ComponentGrafana, model.ComponentPromtail} {
+		usage := map[string]kv1.ResourceList{}
+		for _, selector := range selectors[component] {
+			err := r.collectContainerUsage(ctx, getResourceRecommendationNamespace(cr, component), selector, usage)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, container := range sortedKeys(usage) {
+			previous := getPreviousRecommendation(cr.Status.ResourceRecommendations, component, container)
+			recommendations = append(recommendations, v1.ResourceRecommendation{
+				Component: component,
+				Container: container,
+				Requests:  recommendResourceRequests(usage[container], previous),
+			})
+		}
+	}
+
+	s.ResourceRecommendations = recommendations
+	return nil
+}
+
+// Records the highest usage of every container across all pods matching the selector
+func (r *Reconciler) collectContainerUsage(ctx context.Context, namespace string, selector *metav1.LabelSelector, usage map[string]kv1.ResourceList) error {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "metrics.k8s.io",
+		Version: "v1beta1",
+		Kind:    "PodMetricsList",
+	})
+	opts := &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+	}
+	err = r.client.List(ctx, list, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range list.Items {
+		metrics := &podMetrics{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, metrics)
+		if err != nil {
+			return err
+		}
+
+		for _, container := range metrics.Containers {
+			if usage[container.Name] == nil {
+				usage[container.Name] = kv1.ResourceList{}
+			}
+			for _, name := range []kv1.ResourceName{kv1.ResourceCPU, kv1.ResourceMemory} {
+				quantity, ok := container.Usage[name]
+				current := usage[container.Name][name]
+				if ok && quantity.Cmp(current) > 0 {
+					usage[container.Name][name] = quantity
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func getPreviousRecommendation(recommendations []v1.ResourceRecommendation, component string, container string) kv1.ResourceList {
+	for _, recommendation := range recommendations {
+		if recommendation.Component == component && recommendation.Container == container {
+			return recommendation.Requests
+		}
+	}
+	return nil
+}
+
+// Requests are the usage plus headroom. A lower usage than before only slowly decreases the
+// previous recommendation, and the previous recommendation is kept unless the new one differs
+// by more than the change ratio. CPU is rounded up to millicores and memory to Mi.
+func recommendResourceRequests(usage kv1.ResourceList, previous kv1.ResourceList) kv1.ResourceList {
+	result := kv1.ResourceList{}
+
+	if cpu, ok := usage[kv1.ResourceCPU]; ok {
+		millis := float64(cpu.MilliValue()) * resourceHeadroomFactor
+		if last, ok := previous[kv1.ResourceCPU]; ok {
+			millis = math.Max(millis, float64(last.MilliValue())*resourceDecayFactor)
+			if !isResourceChange(millis, float64(last.MilliValue())) {
+				millis = float64(last.MilliValue())
+			}
+		}
+		result[kv1.ResourceCPU] = *resource.NewMilliQuantity(int64(math.Ceil(millis)), resource.DecimalSI)
+	}
+
+	if memory, ok := usage[kv1.ResourceMemory]; ok {
+		bytes := float64(memory.Value()) * resourceHeadroomFactor
+		if last, ok := previous[kv1.ResourceMemory]; ok {
+			bytes = math.Max(bytes, float64(last.Value())*resourceDecayFactor)
+			if !isResourceChange(bytes, float64(last.Value())) {
+				bytes = float64(last.Value())
+			}
+		}
+		mebibytes := int64(math.Ceil(bytes / (1 << 20)))
+		result[kv1.ResourceMemory] = *resource.NewQuantity(mebibytes<<20, resource.BinarySI)
+	}
+
+	return result
+}
+
+func isResourceChange(recommended float64, previous float64) bool {
+	return previous == 0 || math.Abs(recommended-previous) > previous*resourceChangeRatio
+}
+
+func sortedKeys(usage map[string]kv1.ResourceList) []string {
+	var keys []string
+	for key := range usage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	kv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceRecommendations_RecommendResourceRequests(t *testing.T) {
+	type args struct {
+		usage    kv1.ResourceList
+		previous kv1.ResourceList
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want kv1.ResourceList
+	}{
+		{
+			name: "usage plus headroom without previous recommendation",
+			args: args{
+				usage: kv1.ResourceList{
+					kv1.ResourceCPU:    resource.MustParse("100m"),
+					kv1.ResourceMemory: resource.MustParse("1000Mi"),
+				},
+				previous: nil,
+			},
+			want: kv1.ResourceList{
+				kv1.ResourceCPU:    resource.MustParse("130m"),
+				kv1.ResourceMemory: resource.MustParse("1300Mi"),
+			},
+		},
+		{
+			name: "previous recommendation decays slowly when usage drops",
+			args: args{
+				usage: kv1.ResourceList{
+					kv1.ResourceCPU:    resource.MustParse("10m"),
+					kv1.ResourceMemory: resource.MustParse("100Mi"),
+				},
+				previous: kv1.ResourceList{
+					kv1.ResourceCPU:    resource.MustParse("200m"),
+					kv1.ResourceMemory: resource.MustParse("2000Mi"),
+				},
+			},
+			want: kv1.ResourceList{
+				kv1.ResourceCPU:    resource.MustParse("170m"),
+				kv1.ResourceMemory: resource.MustParse("1700Mi"),
+			},
+		},
+		{
+			name: "previous recommendation kept when the usage changes a little",
+			args: args{
+				usage: kv1.ResourceList{
+					kv1.ResourceCPU:    resource.MustParse("160m"),
+					kv1.ResourceMemory: resource.MustParse("1450Mi"),
+				},
+				previous: kv1.ResourceList{
+					kv1.ResourceCPU:    resource.MustParse("200m"),
+					kv1.ResourceMemory: resource.MustParse("2000Mi"),
+				},
+			},
+			want: kv1.ResourceList{
+				kv1.ResourceCPU:    resource.MustParse("200m"),
+				kv1.ResourceMemory: resource.MustParse("2000Mi"),
+			},
+		},
+		{
+			name: "new recommendation when the usage changes more than the ratio",
+			args: args{
+				usage: kv1.ResourceList{
+					kv1.ResourceCPU:    resource.MustParse("180m"),
+					kv1.ResourceMemory: resource.MustParse("1000Mi"),
+				},
+				previous: kv1.ResourceList{
+					kv1.ResourceCPU:    resource.MustParse("200m"),
+					kv1.ResourceMemory: resource.MustParse("1000Mi"),
+				},
+			},
+			want: kv1.ResourceList{
+				kv1.ResourceCPU:    resource.MustParse("234m"),
+				kv1.ResourceMemory: resource.MustParse("1300Mi"),
+			},
+		},
+		{
+			name: "only resources with observed usage are recommended",
+			args: args{
+				usage: kv1.ResourceList{
+					kv1.ResourceMemory: resource.MustParse("100Mi"),
+				},
+				previous: nil,
+			},
+			want: kv1.ResourceList{
+				kv1.ResourceMemory: resource.MustParse("130Mi"),
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := recommendResourceRequests(tt.args.usage, tt.args.previous)
+			Expect(len(result)).To(Equal(len(tt.want)))
+			for name, quantity := range tt.want {
+				recommended := result[name]
+				Expect(recommended.Cmp(quantity)).To(Equal(0), string(name))
+			}
+		})
+	}
+}
+
+func TestResourceRecommendations_GetResourceRecommendationNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	enabled := true
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability"}}
+	cr.Spec.DescopedMode = &v1.DescopedMode{Enabled: &enabled, PrometheusOperatorNamespace: "prometheus-operator"}
+
+	Expect(getResourceRecommendationNamespace(cr, model.ComponentPrometheus)).To(Equal("prometheus-operator"))
+	Expect(getResourceRecommendationNamespace(cr, model.ComponentGrafana)).To(Equal("observability"))
+	Expect(getResourceRecommendationNamespace(cr, model.ComponentPromtail)).To(Equal("observability"))
+}
@@ -0,0 +1,277 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Parts of a service or pod monitor that a scrape budget changes
+type scrapeBudgetTarget struct {
+	annotations map[string]string
+	sampleLimit *uint64
+	targetLimit *uint64
+	intervals   []prometheusv1.Duration
+	relabelings []*[]*prometheusv1.RelabelConfig
+}
+
+// Apply the budgets of the namespaces to their service and pod monitors and report the namespaces
+// that exceed their budget. The monitors of the operator are skipped, their limits come from the
+// indexes.
+func (r *Reconciler) reconcileScrapeBudgets(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex, s *v1.ObservabilityStatus) error {
+	err := r.applyScrapeBudgets(ctx, cr, model.GetPrometheusScrapeInterval(cr, indexes))
+	if err != nil {
+		return err
+	}
+
+	if len(cr.Spec.NamespaceScrapeBudgets) == 0 {
+		metrics.SetScrapeBudgetMetrics(nil)
+		s.ScrapeBudgetViolations = nil
+		return nil
+	}
+
+	prometheusUrl := model.GetPrometheusUpstreamUrl(cr)
+	targets, err := utils.QueryVector(r.httpClient, prometheusUrl, model.ScrapeBudgetTargetsQuery)
+	if err != nil {
+		return err
+	}
+	samples, err := utils.QueryVector(r.httpClient, prometheusUrl, model.ScrapeBudgetSamplesQuery)
+	if err != nil {
+		return err
+	}
+
+	usage := getScrapeBudgetUsage(cr.Spec.NamespaceScrapeBudgets, targets, samples)
+	metrics.SetScrapeBudgetMetrics(usage)
+	s.ScrapeBudgetViolations = getScrapeBudgetViolations(usage)
+	return nil
+}
+
+// Revert the budgets applied to the monitors when the CR is deleted
+func (r *Reconciler) deleteScrapeBudgets(ctx context.Context, cr *v1.Observability) error {
+	metrics.SetScrapeBudgetMetrics(nil)
+	withoutBudgets := cr.DeepCopy()
+	withoutBudgets.Spec.NamespaceScrapeBudgets = nil
+	return r.applyScrapeBudgets(ctx, withoutBudgets, "")
+}
+
+// Monitors are listed in all namespaces, so that budgets that were removed are reverted as well
+func (r *Reconciler) applyScrapeBudgets(ctx context.Context, cr *v1.Observability, defaultInterval prometheusv1.Duration) error {
+	namespaces := []string{""}
+	if cr.NamespaceScoped() {
+		namespaces = model.GetTargetNamespaces(cr)
+	}
+
+	for _, namespace := range namespaces {
+		opts := &client.ListOptions{Namespace: namespace}
+
+		serviceMonitors := &prometheusv1.ServiceMonitorList{}
+		err := r.client.List(ctx, serviceMonitors, opts)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				return nil
+			}
+			return err
+		}
+		for _, monitor := range serviceMonitors.Items {
+			if isOwnedResource(monitor) {
+				continue
+			}
+			target := scrapeBudgetTarget{
+				sampleLimit: &monitor.Spec.SampleLimit,
+				targetLimit: &monitor.Spec.TargetLimit,
+			}
+			for i := range monitor.Spec.Endpoints {
+				target.intervals = append(target.intervals, monitor.Spec.Endpoints[i].Interval)
+				target.relabelings = append(target.relabelings, &monitor.Spec.Endpoints[i].MetricRelabelConfigs)
+			}
+			err = r.applyScrapeBudget(ctx, monitor, target, model.GetScrapeBudget(cr, monitor.Namespace), defaultInterval)
+			if err != nil {
+				return err
+			}
+		}
+
+		podMonitors := &prometheusv1.PodMonitorList{}
+		err = r.client.List(ctx, podMonitors, opts)
+		if err != nil {
+			return err
+		}
+		for _, monitor := range podMonitors.Items {
+			if isOwnedResource(monitor) {
+				continue
+			}
+			target := scrapeBudgetTarget{
+				sampleLimit: &monitor.Spec.SampleLimit,
+				targetLimit: &monitor.Spec.TargetLimit,
+			}
+			for i := range monitor.Spec.PodMetricsEndpoints {
+				target.intervals = append(target.intervals, monitor.Spec.PodMetricsEndpoints[i].Interval)
+				target.relabelings = append(target.relabelings, &monitor.Spec.PodMetricsEndpoints[i].MetricRelabelConfigs)
+			}
+			err = r.applyScrapeBudget(ctx, monitor, target, model.GetScrapeBudget(cr, monitor.Namespace), defaultInterval)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// The target points into the monitor, which is only updated if the budget changes it
+func (r *Reconciler) applyScrapeBudget(ctx context.Context, monitor client.Object, target scrapeBudgetTarget, budget *v1.NamespaceScrapeBudget, defaultInterval prometheusv1.Duration) error {
+	before := monitor.DeepCopyObject()
+	target.annotations = monitor.GetAnnotations()
+
+	annotations, err := setScrapeBudget(target, budget, defaultInterval)
+	if err != nil {
+		r.logger.Error(err, "invalid scrape budget", "namespace", monitor.GetNamespace())
+		return nil
+	}
+	monitor.SetAnnotations(annotations)
+
+	if reflect.DeepEqual(before, monitor) {
+		return nil
+	}
+	return r.client.Update(ctx, monitor)
+}
+
+// Revert the budget applied before and apply the current one. Limits that the owner of the monitor
+// changed since are kept, lower limits of the monitor take precedence over the budget.
+func setScrapeBudget(target scrapeBudgetTarget, budget *v1.NamespaceScrapeBudget, defaultInterval prometheusv1.Duration) (map[string]string, error) {
+	var previous model.AppliedScrapeBudget
+	if value, ok := target.annotations[model.ScrapeBudgetAnnotation]; ok {
+		// An invalid annotation is replaced
+		_ = json.Unmarshal([]byte(value), &previous)
+	}
+
+	if previous.TargetLimit > 0 && *target.targetLimit == previous.TargetLimit {
+		*target.targetLimit = previous.OriginalTargetLimit
+	}
+	if previous.SampleLimit > 0 && *target.sampleLimit == previous.SampleLimit {
+		*target.sampleLimit = previous.OriginalSampleLimit
+	}
+	if previous.Drop != "" {
+		for _, relabelings := range target.relabelings {
+			*relabelings = removeScrapeBudgetDrop(*relabelings, previous.Drop)
+		}
+	}
+
+	annotations := map[string]string{}
+	for key, value := range target.annotations {
+		annotations[key] = value
+	}
+	delete(annotations, model.ScrapeBudgetAnnotation)
+	if budget == nil {
+		if len(annotations) == 0 {
+			return nil, nil
+		}
+		return annotations, nil
+	}
+
+	applied := model.AppliedScrapeBudget{
+		OriginalTargetLimit: *target.targetLimit,
+		OriginalSampleLimit: *target.sampleLimit,
+	}
+	if limit := budget.TargetLimit; limit > 0 && (*target.targetLimit == 0 || *target.targetLimit > limit) {
+		*target.targetLimit = limit
+		applied.TargetLimit = limit
+	}
+	if limit := model.GetScrapeBudgetSampleLimit(budget, target.intervals, defaultInterval); limit > 0 && (*target.sampleLimit == 0 || *target.sampleLimit > limit) {
+		*target.sampleLimit = limit
+		applied.SampleLimit = limit
+	}
+
+	drops, err := model.GetMetricFilterRelabelConfigs(&v1.MetricFilter{Drop: budget.Drop})
+	if err != nil {
+		return nil, err
+	}
+	for _, drop := range drops {
+		applied.Drop = drop.Regex
+		for _, relabelings := range target.relabelings {
+			relabeling := drop
+			*relabelings = append(*relabelings, &relabeling)
+		}
+	}
+
+	content, err := json.Marshal(applied)
+	if err != nil {
+		return nil, err
+	}
+	annotations[model.ScrapeBudgetAnnotation] = string(content)
+	return annotations, nil
+}
+
+func removeScrapeBudgetDrop(relabelings []*prometheusv1.RelabelConfig, regex string) []*prometheusv1.RelabelConfig {
+	var result []*prometheusv1.RelabelConfig
+	for _, relabeling := range relabelings {
+		if relabeling.Action == "drop" && relabeling.Regex == regex && reflect.DeepEqual(relabeling.SourceLabels, []prometheusv1.LabelName{"__name__"}) {
+			continue
+		}
+		result = append(result, relabeling)
+	}
+	return result
+}
+
+// Usage of every budgeted namespace, namespaces without targets use nothing
+func getScrapeBudgetUsage(budgets []v1.NamespaceScrapeBudget, targets []utils.VectorSample, samples []utils.VectorSample) []metrics.ScrapeBudgetUsage {
+	byNamespace := func(vector []utils.VectorSample) map[string]float64 {
+		result := map[string]float64{}
+		for _, sample := range vector {
+			result[sample.Metric["namespace"]] = sample.Value
+		}
+		return result
+	}
+	targetsByNamespace := byNamespace(targets)
+	samplesByNamespace := byNamespace(samples)
+
+	var result []metrics.ScrapeBudgetUsage
+	for _, budget := range budgets {
+		if budget.TargetLimit > 0 {
+			result = append(result, metrics.ScrapeBudgetUsage{
+				Namespace: budget.Namespace,
+				Limit:     model.ScrapeBudgetLimitTargets,
+				Budget:    budget.TargetLimit,
+				Observed:  targetsByNamespace[budget.Namespace],
+			})
+		}
+		if budget.SamplesPerSecond > 0 {
+			result = append(result, metrics.ScrapeBudgetUsage{
+				Namespace: budget.Namespace,
+				Limit:     model.ScrapeBudgetLimitSamplesPerSecond,
+				Budget:    budget.SamplesPerSecond,
+				Observed:  samplesByNamespace[budget.Namespace],
+			})
+		}
+	}
+	return result
+}
+
+func getScrapeBudgetViolations(usage []metrics.ScrapeBudgetUsage) []v1.ScrapeBudgetViolation {
+	var result []v1.ScrapeBudgetViolation
+	for _, u := range usage {
+		if u.Observed > float64(u.Budget) {
+			result = append(result, v1.ScrapeBudgetViolation{
+				Namespace: u.Namespace,
+				Limit:     u.Limit,
+				Budget:    u.Budget,
+				Observed:  uint64(math.Ceil(u.Observed)),
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Limit < result[j].Limit
+	})
+	return result
+}
@@ -0,0 +1,122 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScrapeBudgets_ApplyScrapeBudgets(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = prometheusv1.AddToScheme(scheme)
+
+	monitor := &prometheusv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "payments"},
+		Spec: prometheusv1.ServiceMonitorSpec{
+			SampleLimit: 100000,
+			Endpoints: []prometheusv1.Endpoint{
+				{Port: "metrics", Interval: "10s"},
+			},
+		},
+	}
+	managed := &prometheusv1.PodMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "strimzi", Namespace: "payments", Labels: map[string]string{"managed-by": "observability-operator"}},
+	}
+	r := &Reconciler{client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(monitor, managed).Build()}
+
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability"}}
+	cr.Spec.NamespaceScrapeBudgets = []v1.NamespaceScrapeBudget{{
+		Namespace:        "payments",
+		TargetLimit:      50,
+		SamplesPerSecond: 1000,
+		Drop:             []string{"go_.*"},
+	}}
+	Expect(r.applyScrapeBudgets(context.TODO(), cr, "")).To(Succeed())
+
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(monitor), monitor)).To(Succeed())
+	Expect(monitor.Spec.TargetLimit).To(Equal(uint64(50)))
+	Expect(monitor.Spec.SampleLimit).To(Equal(uint64(10000)))
+	Expect(monitor.Spec.Endpoints[0].MetricRelabelConfigs).To(HaveLen(1))
+	Expect(monitor.Spec.Endpoints[0].MetricRelabelConfigs[0].Regex).To(Equal("go_.*"))
+	Expect(monitor.Annotations).To(HaveKey(model.ScrapeBudgetAnnotation))
+
+	// Applying the same budget again doesn't add another drop
+	Expect(r.applyScrapeBudgets(context.TODO(), cr, "")).To(Succeed())
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(monitor), monitor)).To(Succeed())
+	Expect(monitor.Spec.Endpoints[0].MetricRelabelConfigs).To(HaveLen(1))
+
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(managed), managed)).To(Succeed())
+	Expect(managed.Spec.TargetLimit).To(BeZero())
+
+	// Removing the budget restores the limits of the monitor
+	Expect(r.deleteScrapeBudgets(context.TODO(), cr)).To(Succeed())
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(monitor), monitor)).To(Succeed())
+	Expect(monitor.Spec.TargetLimit).To(BeZero())
+	Expect(monitor.Spec.SampleLimit).To(Equal(uint64(100000)))
+	Expect(monitor.Spec.Endpoints[0].MetricRelabelConfigs).To(BeEmpty())
+	Expect(monitor.Annotations).NotTo(HaveKey(model.ScrapeBudgetAnnotation))
+}
+
+func TestScrapeBudgets_SetScrapeBudget(t *testing.T) {
+	RegisterTestingT(t)
+
+	// Lower limits of the monitor take precedence
+	sampleLimit := uint64(500)
+	targetLimit := uint64(10)
+	target := scrapeBudgetTarget{sampleLimit: &sampleLimit, targetLimit: &targetLimit}
+	annotations, err := setScrapeBudget(target, &v1.NamespaceScrapeBudget{TargetLimit: 50, SamplesPerSecond: 1000}, "30s")
+	Expect(err).To(BeNil())
+	Expect(sampleLimit).To(Equal(uint64(500)))
+	Expect(targetLimit).To(Equal(uint64(10)))
+	Expect(annotations).To(HaveKey(model.ScrapeBudgetAnnotation))
+
+	// A limit that the owner changed since is kept when the budget is removed
+	target.annotations = map[string]string{model.ScrapeBudgetAnnotation: `{"targetLimit":50,"originalTargetLimit":0}`}
+	targetLimit = 20
+	annotations, err = setScrapeBudget(target, nil, "")
+	Expect(err).To(BeNil())
+	Expect(targetLimit).To(Equal(uint64(20)))
+	Expect(annotations).To(BeNil())
+
+	_, err = setScrapeBudget(target, &v1.NamespaceScrapeBudget{Drop: []string{"("}}, "")
+	Expect(err).NotTo(BeNil())
+}
+
+func TestScrapeBudgets_GetScrapeBudgetViolations(t *testing.T) {
+	RegisterTestingT(t)
+
+	budgets := []v1.NamespaceScrapeBudget{
+		{Namespace: "payments", TargetLimit: 10, SamplesPerSecond: 1000},
+		{Namespace: "search", TargetLimit: 10},
+	}
+	targets := []utils.VectorSample{
+		{Metric: map[string]string{"namespace": "payments"}, Value: 4},
+		{Metric: map[string]string{"namespace": "search"}, Value: 12},
+	}
+	samples := []utils.VectorSample{
+		{Metric: map[string]string{"namespace": "payments"}, Value: 1500.4},
+	}
+
+	usage := getScrapeBudgetUsage(budgets, targets, samples)
+	Expect(usage).To(Equal([]metrics.ScrapeBudgetUsage{
+		{Namespace: "payments", Limit: model.ScrapeBudgetLimitTargets, Budget: 10, Observed: 4},
+		{Namespace: "payments", Limit: model.ScrapeBudgetLimitSamplesPerSecond, Budget: 1000, Observed: 1500.4},
+		{Namespace: "search", Limit: model.ScrapeBudgetLimitTargets, Budget: 10, Observed: 12},
+	}))
+	Expect(getScrapeBudgetViolations(usage)).To(Equal([]v1.ScrapeBudgetViolation{
+		{Namespace: "payments", Limit: model.ScrapeBudgetLimitSamplesPerSecond, Budget: 1000, Observed: 1501},
+		{Namespace: "search", Limit: model.ScrapeBudgetLimitTargets, Budget: 10, Observed: 12},
+	}))
+}
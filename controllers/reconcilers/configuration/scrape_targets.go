@@ -0,0 +1,113 @@
+package configuration
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+)
+
+// Summarize the active targets of the managed Prometheus by monitor once per interval, so that the
+// scrape coverage of a cluster is visible without access to its Prometheus. The time of the last
+// summary is taken from the status.
+func (r *Reconciler) reconcileScrapeTargetHealth(cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	if !cr.ScrapeTargetHealthEnabled() {
+		s.ScrapeTargets = nil
+		metrics.SetScrapeTargetMetrics(nil)
+		return nil
+	}
+
+	if s.ScrapeTargets != nil && time.Since(time.Unix(s.ScrapeTargets.LastChecked, 0)) < model.GetScrapeTargetHealthInterval(cr) {
+		return nil
+	}
+
+	targets, err := utils.FetchScrapeTargets(r.httpClient, model.GetPrometheusUpstreamUrl(cr))
+	if err != nil {
+		return err
+	}
+
+	counts, status := getScrapeTargetHealth(targets)
+	status.LastChecked = time.Now().Unix()
+	metrics.SetScrapeTargetMetrics(counts)
+	s.ScrapeTargets = status
+	return nil
+}
+
+// Scrape pools of monitors are named <kind>/<namespace>/<name>/<endpoint>, the targets of all
+// endpoints of a monitor are counted together. Other pools are named after their job.
+func getScrapeTargetMonitor(target utils.ScrapeTarget) (string, string) {
+	parts := strings.Split(target.ScrapePool, "/")
+	if len(parts) == 4 && (parts[0] == "serviceMonitor" || parts[0] == "podMonitor") {
+		return parts[0] + "/" + parts[2], parts[1]
+	}
+	return target.ScrapePool, target.Labels["namespace"]
+}
+
+// Counts of all monitors for the metrics, the monitors with the most down targets for the status
+func getScrapeTargetHealth(targets []utils.ScrapeTarget) ([]metrics.ScrapeTargetCount, *v1.ScrapeTargetsStatus) {
+	type key struct {
+		monitor   string
+		namespace string
+	}
+	counts := map[key]*metrics.ScrapeTargetCount{}
+	lastErrors := map[key]string{}
+	status := &v1.ScrapeTargetsStatus{}
+	for _, target := range targets {
+		monitor, namespace := getScrapeTargetMonitor(target)
+		k := key{monitor: monitor, namespace: namespace}
+		count, ok := counts[k]
+		if !ok {
+			count = &metrics.ScrapeTargetCount{Monitor: monitor, Namespace: namespace}
+			counts[k] = count
+		}
+		// Targets that weren't scraped yet are counted as up
+		if target.Health == "down" {
+			count.Down++
+			status.Down++
+			if target.LastError != "" {
+				lastErrors[k] = target.LastError
+			}
+		} else {
+			count.Up++
+			status.Up++
+		}
+	}
+
+	var result []metrics.ScrapeTargetCount
+	for k, count := range counts {
+		result = append(result, *count)
+		if count.Down > 0 {
+			status.Unhealthy = append(status.Unhealthy, v1.UnhealthyScrapeTargets{
+				Monitor:   count.Monitor,
+				Namespace: count.Namespace,
+				Down:      count.Down,
+				Total:     count.Up + count.Down,
+				LastError: lastErrors[k],
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Monitor < result[j].Monitor
+	})
+	sort.Slice(status.Unhealthy, func(i, j int) bool {
+		a, b := status.Unhealthy[i], status.Unhealthy[j]
+		if a.Down != b.Down {
+			return a.Down > b.Down
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Monitor < b.Monitor
+	})
+	if len(status.Unhealthy) > model.MaxUnhealthyScrapeTargets {
+		status.Unhealthy = status.Unhealthy[:model.MaxUnhealthyScrapeTargets]
+	}
+	return result, status
+}
@@ -0,0 +1,68 @@
+package configuration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+)
+
+func TestScrapeTargets_GetScrapeTargetHealth(t *testing.T) {
+	RegisterTestingT(t)
+
+	targets := []utils.ScrapeTarget{
+		{ScrapePool: "serviceMonitor/kafka/broker/0", Health: "up"},
+		{ScrapePool: "serviceMonitor/kafka/broker/1", Health: "down", LastError: "connection refused"},
+		{ScrapePool: "podMonitor/kafka/operator/0", Health: "down"},
+		{ScrapePool: "podMonitor/kafka/operator/0", Health: "down"},
+		{ScrapePool: "static", Labels: map[string]string{"namespace": "edge"}, Health: "unknown"},
+	}
+
+	counts, status := getScrapeTargetHealth(targets)
+	Expect(counts).To(Equal([]metrics.ScrapeTargetCount{
+		{Monitor: "static", Namespace: "edge", Up: 1},
+		{Monitor: "podMonitor/operator", Namespace: "kafka", Down: 2},
+		{Monitor: "serviceMonitor/broker", Namespace: "kafka", Up: 1, Down: 1},
+	}))
+	Expect(status.Up).To(Equal(2))
+	Expect(status.Down).To(Equal(3))
+	Expect(status.Unhealthy).To(Equal([]v1.UnhealthyScrapeTargets{
+		{Monitor: "podMonitor/operator", Namespace: "kafka", Down: 2, Total: 2},
+		{Monitor: "serviceMonitor/broker", Namespace: "kafka", Down: 1, Total: 2, LastError: "connection refused"},
+	}))
+}
+
+func TestScrapeTargets_GetScrapeTargetHealthLimit(t *testing.T) {
+	RegisterTestingT(t)
+
+	var targets []utils.ScrapeTarget
+	for i := 0; i < model.MaxUnhealthyScrapeTargets+5; i++ {
+		targets = append(targets, utils.ScrapeTarget{ScrapePool: fmt.Sprintf("serviceMonitor/ns/monitor-%v/0", i), Health: "down"})
+	}
+
+	counts, status := getScrapeTargetHealth(targets)
+	Expect(counts).To(HaveLen(model.MaxUnhealthyScrapeTargets + 5))
+	Expect(status.Down).To(Equal(model.MaxUnhealthyScrapeTargets + 5))
+	Expect(status.Unhealthy).To(HaveLen(model.MaxUnhealthyScrapeTargets))
+}
+
+func TestScrapeTargets_ReconcileScrapeTargetHealth(t *testing.T) {
+	RegisterTestingT(t)
+
+	// The reconciler of the next sync finds the summary in the status, it has no HTTP client to query with
+	cr := buildObservabilityCR(nil)
+	checked := &v1.ScrapeTargetsStatus{Up: 3, LastChecked: time.Now().Unix()}
+	status := &v1.ObservabilityStatus{ScrapeTargets: checked}
+	Expect((&Reconciler{}).reconcileScrapeTargetHealth(cr, status)).To(Succeed())
+	Expect(status.ScrapeTargets).To(Equal(checked))
+
+	disabled := false
+	cr.Spec.ScrapeTargetHealth = &v1.ScrapeTargetHealth{Enabled: &disabled}
+	Expect((&Reconciler{}).reconcileScrapeTargetHealth(cr, status)).To(Succeed())
+	Expect(status.ScrapeTargets).To(BeNil())
+}
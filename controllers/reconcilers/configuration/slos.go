@@ -0,0 +1,81 @@
+package configuration
+
+import (
+	"context"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// Burn rate rules of the valid objectives in one rule, the invalid ones are reported as events
+func (r *Reconciler) getServiceLevelObjectiveRuleGroups(cr *v1.Observability) []prometheusv1.RuleGroup {
+	var groups []prometheusv1.RuleGroup
+	for i := range cr.Spec.ServiceLevelObjectives {
+		slo := &cr.Spec.ServiceLevelObjectives[i]
+		err := model.ValidateServiceLevelObjective(slo)
+		if err != nil {
+			r.logger.Error(err, "skipped slo")
+			r.recordEvent(cr, core.EventTypeWarning, "InvalidSLO", err.Error())
+			continue
+		}
+		groups = append(groups, model.GetServiceLevelObjectiveRuleGroup(slo))
+	}
+	return groups
+}
+
+// Rules and dashboard of the objectives, removed when none are declared
+func (r *Reconciler) reconcileServiceLevelObjectives(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	groups := r.getServiceLevelObjectiveRuleGroups(cr)
+	if len(groups) == 0 {
+		return r.deleteServiceLevelObjectives(ctx, cr)
+	}
+
+	rule := model.GetServiceLevelObjectiveRule(cr)
+	_, err := utils.Apply(ctx, r.client, rule, func() error {
+		rule.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, model.GetSelectorLabels(model.GetPrometheusRuleLabelSelectors(cr, indexes)))
+		rule.Spec.Groups = groups
+		injectClusterLabels(cr, rule)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	dashboard := model.GetServiceLevelObjectiveDashboard(cr)
+	if !cr.GrafanaEnabled() {
+		err = r.client.Delete(ctx, dashboard)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+		return nil
+	}
+
+	requestedSpec := dashboard.Spec
+	_, err = utils.Apply(ctx, r.client, dashboard, func() error {
+		dashboard.Spec = requestedSpec
+		dashboard.Labels = MergeLabels(map[string]string{
+			"managed-by": "observability-operator",
+		}, model.GetSelectorLabels(model.GetGrafanaDashboardLabelSelectors(cr, indexes)))
+		return nil
+	})
+	return err
+}
+
+func (r *Reconciler) deleteServiceLevelObjectives(ctx context.Context, cr *v1.Observability) error {
+	err := r.client.Delete(ctx, model.GetServiceLevelObjectiveRule(cr))
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	err = r.client.Delete(ctx, model.GetServiceLevelObjectiveDashboard(cr))
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+)
+
+func TestServiceLevelObjectives_ValidRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	cr := &v1.Observability{}
+	cr.Spec.ServiceLevelObjectives = []v1.ServiceLevelObjective{
+		{
+			Name:       "api-availability",
+			ErrorQuery: `sum(rate(http_requests_total{code=~"5.."}[$window]))`,
+			TotalQuery: `sum(rate(http_requests_total[$window]))`,
+			Target:     "99.5",
+			Window:     "7d",
+		},
+		{
+			Name:       "invalid",
+			ErrorQuery: "up",
+			TotalQuery: "up",
+			Target:     "99",
+		},
+	}
+
+	reconciler := &Reconciler{logger: logr.Discard()}
+	rule := model.GetServiceLevelObjectiveRule(cr)
+	rule.Spec.Groups = reconciler.getServiceLevelObjectiveRuleGroups(cr)
+	Expect(rule.Spec.Groups).To(HaveLen(1))
+	Expect(validateRule(rule)).To(Succeed())
+}
@@ -0,0 +1,93 @@
+package configuration
+
+import (
+	"fmt"
+	"sync"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+// State of the configuration syncs that outlives the reconcilers, which are created for every
+// reconcile. Owned by the controller and shared by the syncs of all CRs, the keys of the caches
+// don't depend on the CR unless noted.
+type SyncState struct {
+	mu sync.Mutex
+	// Last responses of the fetched documents, by tag and url
+	fetchCache map[string]cachedFetch
+	// Rate limits and circuit breakers, by host
+	hostFetchStates map[string]*hostFetchState
+	// Resources of the indexes last applied, by kind, namespace and name
+	appliedResources map[string]appliedResource
+	// Last syncs of the calendars of the mute time intervals, by CR and interval name
+	alertmanagerCalendars map[string]map[string]*calendarSync
+	// CRs that are synced on their next reconcile regardless of the resync period, by CR
+	syncRequests map[string]bool
+	// Observatorium instances with a secondary gateway of the last sync, by CR and remote write queue
+	failoverObservatoria map[string]map[string]*v1.ObservatoriumIndex
+}
+
+func NewSyncState() *SyncState {
+	return &SyncState{
+		fetchCache:            map[string]cachedFetch{},
+		hostFetchStates:       map[string]*hostFetchState{},
+		appliedResources:      map[string]appliedResource{},
+		alertmanagerCalendars: map[string]map[string]*calendarSync{},
+		syncRequests:          map[string]bool{},
+		failoverObservatoria:  map[string]map[string]*v1.ObservatoriumIndex{},
+	}
+}
+
+// Sync the CR on its next reconcile, e.g. to finish a change that takes a second sync
+func (s *SyncState) requestSync(cr *v1.Observability) {
+	s.RequestSync(cr.Namespace, cr.Name)
+}
+
+// Sync the CR with the namespace and name on its next reconcile, e.g. to repair a resource that
+// was changed by someone else
+func (s *SyncState) RequestSync(namespace string, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncRequests[fmt.Sprintf("%v/%v", namespace, name)] = true
+}
+
+// True once after a sync of the CR was requested
+func (s *SyncState) takeSyncRequest(cr *v1.Observability) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := getSyncStateKey(cr)
+	requested := s.syncRequests[key]
+	delete(s.syncRequests, key)
+	return requested
+}
+
+// The failover of the gateways is checked on every reconcile with the instances of the last sync
+func (s *SyncState) getFailoverObservatoria(cr *v1.Observability) map[string]*v1.ObservatoriumIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failoverObservatoria[getSyncStateKey(cr)]
+}
+
+func (s *SyncState) setFailoverObservatoria(cr *v1.Observability, observatoria map[string]*v1.ObservatoriumIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(observatoria) == 0 {
+		delete(s.failoverObservatoria, getSyncStateKey(cr))
+		return
+	}
+	s.failoverObservatoria[getSyncStateKey(cr)] = observatoria
+}
+
+// Key of the state of a CR
+func getSyncStateKey(cr *v1.Observability) string {
+	return fmt.Sprintf("%v/%v", cr.Namespace, cr.Name)
+}
+
+// Reconcilers that are not created by the controller, e.g. in tests, keep the state for themselves
+func (r *Reconciler) getSyncState() *SyncState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == nil {
+		r.state = NewSyncState()
+	}
+	return r.state
+}
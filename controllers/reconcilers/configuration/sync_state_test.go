@@ -0,0 +1,23 @@
+package configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncState_RequestSync(t *testing.T) {
+	RegisterTestingT(t)
+
+	state := NewSyncState()
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability", Name: "observability-stack"}}
+	other := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability", Name: "other"}}
+
+	// Requests of the drift watches are taken once by the next reconcile of the CR
+	state.RequestSync("observability", "observability-stack")
+	Expect(state.takeSyncRequest(other)).To(BeFalse())
+	Expect(state.takeSyncRequest(cr)).To(BeTrue())
+	Expect(state.takeSyncRequest(cr)).To(BeFalse())
+}
@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Copy the dashboards of the tenants to the namespace of Grafana and delete the copies of removed
+// dashboards. Missing config maps are skipped, the team may create them after the tenant.
+func (r *Reconciler) reconcileTenantDashboards(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	requested := map[string]bool{}
+	if cr.GrafanaEnabled() {
+		for i := range cr.Status.Tenants {
+			tenant := &cr.Status.Tenants[i]
+			for _, name := range tenant.Dashboards {
+				configMap := &core.ConfigMap{}
+				err := r.client.Get(ctx, client.ObjectKey{Namespace: tenant.Namespace, Name: name}, configMap)
+				if err != nil {
+					if errors.IsNotFound(err) {
+						continue
+					}
+					return err
+				}
+
+				for _, key := range getDashboardKeys(configMap) {
+					dashboard := model.GetTenantDashboard(cr, tenant, name, key)
+					requested[dashboard.Name] = true
+					content := configMap.Data[key]
+					_, err = utils.Apply(ctx, r.client, dashboard, func() error {
+						dashboard.Spec = v1alpha1.GrafanaDashboardSpec{Json: content}
+						dashboard.Labels = getTenantDashboardLabels(cr, indexes, tenant)
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return r.deleteTenantDashboards(ctx, cr, requested)
+}
+
+// The selector labels are a copy, the tenant label does not end up in the selector of Grafana
+func getTenantDashboardLabels(cr *v1.Observability, indexes []v1.RepositoryIndex, tenant *v1.TenantStatus) map[string]string {
+	result := model.GetSelectorLabels(model.GetGrafanaDashboardLabelSelectors(cr, indexes))
+	result["managed-by"] = "observability-operator"
+	result[model.TenantLabel] = tenant.Namespace
+	return result
+}
+
+func getDashboardKeys(configMap *core.ConfigMap) []string {
+	var result []string
+	for key := range configMap.Data {
+		if strings.HasSuffix(key, ".json") {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (r *Reconciler) deleteTenantDashboards(ctx context.Context, cr *v1.Observability, requested map[string]bool) error {
+	list := &v1alpha1.GrafanaDashboardList{}
+	err := r.client.List(ctx, list, &client.ListOptions{
+		Namespace:     cr.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{"managed-by": "observability-operator"}),
+	})
+	if err != nil {
+		return err
+	}
+	for _, dashboard := range list.Items {
+		if _, ok := dashboard.Labels[model.TenantLabel]; !ok || requested[dashboard.Name] {
+			continue
+		}
+		err = r.client.Delete(ctx, &dashboard)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	kv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTenants_ReconcileTenantDashboards(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	configMap := &kv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboards", Namespace: "payments"},
+		Data: map[string]string{
+			"checkout.json": `{"title": "Checkout"}`,
+			"README.md":     "not a dashboard",
+		},
+	}
+	stale := &v1alpha1.GrafanaDashboard{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tenant-payments-dashboards-removed",
+			Namespace: "observability",
+			Labels:    map[string]string{"managed-by": "observability-operator", model.TenantLabel: "payments"},
+		},
+	}
+	r := &Reconciler{client: testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(configMap, stale).Build())}
+
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Namespace: "observability"}}
+	cr.Status.Tenants = []v1.TenantStatus{{Name: "payments", Namespace: "payments", Dashboards: []string{"dashboards", "missing"}}}
+	Expect(r.reconcileTenantDashboards(context.TODO(), cr, nil)).To(Succeed())
+
+	list := &v1alpha1.GrafanaDashboardList{}
+	Expect(r.client.List(context.TODO(), list)).To(Succeed())
+	Expect(list.Items).To(HaveLen(1))
+	Expect(list.Items[0].Name).To(Equal("tenant-payments-dashboards-checkout"))
+	Expect(list.Items[0].Spec.Json).To(Equal(`{"title": "Checkout"}`))
+	Expect(list.Items[0].Labels).To(HaveKeyWithValue(model.TenantLabel, "payments"))
+	Expect(list.Items[0].Labels).To(HaveKeyWithValue("app", "strimzi"))
+}
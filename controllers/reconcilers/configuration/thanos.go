@@ -0,0 +1,114 @@
+package configuration
+
+import (
+	"context"
+
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/features"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The Thanos spec is ignored without the feature gate
+func thanosEnabled(cr *v1.Observability) bool {
+	return features.Enabled(features.Thanos) && cr.Spec.Thanos != nil && cr.Spec.Thanos.ObjectStorageConfig != nil
+}
+
+// Sidecar of Prometheus, nil without Thanos
+func getPrometheusThanosSpec(cr *v1.Observability) *prometheusv1.ThanosSpec {
+	if !thanosEnabled(cr) {
+		return nil
+	}
+	return model.GetPrometheusThanosSpec(cr)
+}
+
+// Compactor of the blocks that the sidecar uploads, removed when it is disabled or the retention of
+// Prometheus drops below the threshold. The volume claim template can't be changed once the
+// statefulset exists.
+func (r *Reconciler) reconcileThanosCompactor(ctx context.Context, cr *v1.Observability) error {
+	if !thanosEnabled(cr) {
+		return r.deleteThanosCompactor(ctx, cr)
+	}
+	retention := string(getRetentionHelper(cr))
+	required, err := model.IsThanosCompactorRequired(cr, retention)
+	if err != nil {
+		return err
+	}
+	if !required {
+		return r.deleteThanosCompactor(ctx, cr)
+	}
+
+	service := model.GetThanosCompactorService(cr)
+	selector := service.Labels
+	_, err = utils.Apply(ctx, r.client, service, func() error {
+		service.Spec.Selector = selector
+		service.Spec.Ports = []core.ServicePort{
+			{
+				Name:       "http",
+				Protocol:   core.ProtocolTCP,
+				Port:       model.ThanosCompactorPort,
+				TargetPort: intstr.FromString("http"),
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only one compactor may run against a bucket
+	var replicas int32 = 1
+	automountToken := false
+	statefulSet := model.GetThanosCompactorStatefulSet(cr)
+	labels := statefulSet.Labels
+	_, err = utils.Apply(ctx, r.client, statefulSet, func() error {
+		statefulSet.Labels = labels
+		statefulSet.Spec = appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: service.Name,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: core.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: core.PodSpec{
+					PriorityClassName:            model.ObservabilityPriorityClassName,
+					SecurityContext:              model.GetPodSecurityContext(cr),
+					Affinity:                     model.GetArchitectureAffinity(cr, nil, model.GetImageArchitectures(cr, v1.ImageThanos, model.ThanosImage)),
+					Tolerations:                  cr.Spec.Tolerations,
+					AutomountServiceAccountToken: &automountToken,
+					Containers:                   []core.Container{model.GetThanosCompactorContainer(cr, retention, model.GetProxyEnvVars(r.clusterProxy))},
+					Volumes:                      model.GetThanosCompactorVolumes(cr),
+				},
+			},
+			VolumeClaimTemplates: []core.PersistentVolumeClaim{model.GetThanosCompactorVolumeClaimTemplate(cr)},
+		}
+		return nil
+	})
+	return err
+}
+
+// The volume claim of the working directory is kept, it is reused when the compactor comes back
+func (r *Reconciler) deleteThanosCompactor(ctx context.Context, cr *v1.Observability) error {
+	objects := []client.Object{
+		model.GetThanosCompactorStatefulSet(cr),
+		model.GetThanosCompactorService(cr),
+	}
+	for _, object := range objects {
+		err := r.client.Delete(ctx, object)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
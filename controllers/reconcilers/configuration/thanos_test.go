@@ -0,0 +1,60 @@
+package configuration
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/features"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	appsv1 "k8s.io/api/apps/v1"
+	kv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestThanos_ReconcileThanosCompactor(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(features.DefaultGates().Set("thanos=true")).To(Succeed())
+	defer func() { _ = features.DefaultGates().Set("") }()
+
+	scheme := runtime.NewScheme()
+	_ = kv1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	r := &Reconciler{client: testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())}
+
+	tr := true
+	cr := &v1.Observability{}
+	cr.Namespace = "observability"
+	cr.Spec.Retention = "1y"
+	cr.Spec.Thanos = &v1.ThanosSpec{
+		ObjectStorageConfig: &kv1.SecretKeySelector{
+			LocalObjectReference: kv1.LocalObjectReference{Name: "thanos-objstore"},
+			Key:                  "objstore.yml",
+		},
+		Compactor: &v1.ThanosCompactor{Enabled: &tr},
+	}
+	Expect(getPrometheusThanosSpec(cr)).ToNot(BeNil())
+
+	Expect(r.reconcileThanosCompactor(context.TODO(), cr)).To(Succeed())
+	statefulSet := model.GetThanosCompactorStatefulSet(cr)
+	Expect(r.client.Get(context.TODO(), client.ObjectKeyFromObject(statefulSet), statefulSet)).To(Succeed())
+	Expect(*statefulSet.Spec.Replicas).To(Equal(int32(1)))
+	Expect(statefulSet.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--retention.resolution-raw=1y"))
+	Expect(statefulSet.Spec.VolumeClaimTemplates).To(HaveLen(1))
+
+	// Below the threshold the compactor is removed
+	cr.Spec.Retention = "15d"
+	Expect(r.reconcileThanosCompactor(context.TODO(), cr)).To(Succeed())
+	err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(statefulSet), statefulSet)
+	Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// The spec is ignored without the feature gate
+	Expect(features.DefaultGates().Set("thanos=false")).To(Succeed())
+	Expect(getPrometheusThanosSpec(cr)).To(BeNil())
+}
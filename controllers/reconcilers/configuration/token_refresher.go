@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"sort"
 
 	errors2 "github.com/pkg/errors"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
 	v13 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
 	v15 "k8s.io/api/networking/v1"
@@ -16,14 +18,11 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-const (
-	TokenRefresherImageTag = "0b54d2e"
-)
+const tokenRefresherPort = 8080
 
-// Return a set of credentials and configuration for either logs or metrics
+// Return a set of credentials and configuration for logs, metrics or queries
 func getTokenRefresherConfigSetFor(t model.TokenRefresherType, observatorium *v1.ObservatoriumIndex) (*model.TokenRefresherConfigSet, error) {
 	if observatorium.RedhatSsoConfig == nil {
 		return nil, nil
@@ -60,16 +59,35 @@ func getTokenRefresherConfigSetFor(t model.TokenRefresherType, observatorium *v1
 		result.ObservatoriumUrl = fmt.Sprintf("%v/api/logs/v1/%v/loki/api/v1/push", observatorium.Gateway, observatorium.Tenant)
 		result.Secret = observatorium.RedhatSsoConfig.LogsSecret
 		result.Client = observatorium.RedhatSsoConfig.LogsClient
+	case model.QueryTokenRefresher:
+		if !observatorium.RedhatSsoConfig.HasQueries() {
+			return nil, nil
+		}
+
+		// The paths of the queries are appended to the url
+		result.ObservatoriumUrl = fmt.Sprintf("%v/api/metrics/v1/%v", observatorium.Gateway, observatorium.Tenant)
+		result.Secret = observatorium.RedhatSsoConfig.MetricsSecret
+		result.Client = observatorium.RedhatSsoConfig.MetricsClient
+		if observatorium.RedhatSsoConfig.QueryClient != "" && observatorium.RedhatSsoConfig.QuerySecret != "" {
+			result.Secret = observatorium.RedhatSsoConfig.QuerySecret
+			result.Client = observatorium.RedhatSsoConfig.QueryClient
+		}
 	default:
 		return nil, nil
 	}
 	return result, nil
 }
 
-func (r *Reconciler) createServiceFor(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet) error {
+// The service keeps the name of the config set in both modes, so that the remote write and promtail
+// urls don't change when switching to a shared token refresher
+func (r *Reconciler) createServiceFor(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet, deploymentName string, port int32) error {
 	service := model.GetTokenRefresherService(cr, config.Name)
+	r.trackResource(ManagedKindService, service, "")
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+	_, err := utils.Apply(ctx, r.client, service, func() error {
+		service.Labels = map[string]string{
+			"app.kubernetes.io/component": "authentication-proxy",
+		}
 		service.Spec.Ports = []v12.ServicePort{
 			{
 				Name:        "http",
@@ -77,14 +95,14 @@ func (r *Reconciler) createServiceFor(ctx context.Context, cr *v1.Observability,
 				AppProtocol: nil,
 				Port:        80,
 				TargetPort: intstr.IntOrString{
-					IntVal: 8080,
+					IntVal: port,
 				},
 				NodePort: 0,
 			},
 		}
 		service.Spec.Selector = map[string]string{
 			"app.kubernetes.io/component": "authentication-proxy",
-			"app.kubernetes.io/name":      config.Name,
+			"app.kubernetes.io/name":      deploymentName,
 		}
 		return nil
 	})
@@ -93,17 +111,34 @@ func (r *Reconciler) createServiceFor(ctx context.Context, cr *v1.Observability,
 }
 
 func (r *Reconciler) createNetworkPolicyFor(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet) error {
-	policy := model.GetTokenRefresherNetworkPolicy(cr, config.Name)
+	return r.createNetworkPolicy(ctx, cr, config.Name, []model.TokenRefresherType{config.Type})
+}
 
-	selector := make(map[string]string)
-	switch config.Type {
-	case model.LogsTokenRefresher:
-		selector["app"] = "promtail"
-	case model.MetricsTokenRefresher:
-		selector["app.kubernetes.io/name"] = "prometheus"
+// Only the clients of the token refresher types may connect to the deployment
+func (r *Reconciler) createNetworkPolicy(ctx context.Context, cr *v1.Observability, deploymentName string, types []model.TokenRefresherType) error {
+	policy := model.GetTokenRefresherNetworkPolicy(cr, deploymentName)
+	r.trackResource(ManagedKindNetworkPolicy, policy, "")
+
+	var peers []v15.NetworkPolicyPeer
+	for _, t := range types {
+		selector := make(map[string]string)
+		switch t {
+		case model.LogsTokenRefresher:
+			selector["app"] = "promtail"
+		case model.MetricsTokenRefresher:
+			selector["app.kubernetes.io/name"] = "prometheus"
+		case model.QueryTokenRefresher:
+			selector["app"] = "grafana"
+			peers = append(peers, r.getOperatorNetworkPolicyPeers()...)
+		}
+		peers = append(peers, v15.NetworkPolicyPeer{
+			PodSelector: &v14.LabelSelector{
+				MatchLabels: selector,
+			},
+		})
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, policy, func() error {
+	_, err := utils.Apply(ctx, r.client, policy, func() error {
 		policy.Labels = map[string]string{
 			"app.kubernetes.io/component": "authentication-proxy",
 		}
@@ -111,18 +146,12 @@ func (r *Reconciler) createNetworkPolicyFor(ctx context.Context, cr *v1.Observab
 			PodSelector: v14.LabelSelector{
 				MatchLabels: map[string]string{
 					"app.kubernetes.io/component": "authentication-proxy",
-					"app.kubernetes.io/name":      config.Name,
+					"app.kubernetes.io/name":      deploymentName,
 				},
 			},
 			Ingress: []v15.NetworkPolicyIngressRule{
 				{
-					From: []v15.NetworkPolicyPeer{
-						{
-							PodSelector: &v14.LabelSelector{
-								MatchLabels: selector,
-							},
-						},
-					},
+					From: peers,
 				},
 			},
 			PolicyTypes: []v15.PolicyType{v15.PolicyTypeIngress},
@@ -133,56 +162,82 @@ func (r *Reconciler) createNetworkPolicyFor(ctx context.Context, cr *v1.Observab
 	return err
 }
 
-func (r *Reconciler) createDeploymentFor(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet) error {
-	deployment := model.GetTokenRefresherDeployment(cr, config.Name)
+// The operator reads back from Observatorium through the query token refreshers
+func (r *Reconciler) getOperatorNetworkPolicyPeers() []v15.NetworkPolicyPeer {
+	namespace, err := utils.GetOperatorNamespace()
+	if err != nil {
+		r.logger.Error(err, "operator can't connect to the query token refreshers")
+		return nil
+	}
+	return []v15.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &v14.LabelSelector{
+				MatchLabels: map[string]string{
+					"kubernetes.io/metadata.name": namespace,
+				},
+			},
+			PodSelector: &v14.LabelSelector{
+				MatchLabels: map[string]string{
+					"control-plane": "controller-manager",
+				},
+			},
+		},
+	}
+}
 
+func (r *Reconciler) createDeploymentFor(ctx context.Context, cr *v1.Observability, config *model.TokenRefresherConfigSet) error {
 	err := r.createNetworkPolicyFor(ctx, cr, config)
 	if err != nil {
 		return err
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, deployment, func() error {
+	return r.createDeployment(ctx, cr, config.Name, config.Replicas, config.Tag, []v12.Container{
+		r.getTokenRefresherContainer(config, tokenRefresherPort),
+	})
+}
+
+// The version label is the image tag of the first container
+func (r *Reconciler) createDeployment(ctx context.Context, cr *v1.Observability, name string, replicas int32, version string, containers []v12.Container) error {
+	deployment := model.GetTokenRefresherDeployment(cr, name)
+	r.trackResource(ManagedKindDeployment, deployment, "")
+
+	var architectures [][]string
+	for i := range containers {
+		architectures = append(architectures, model.GetTokenRefresherImageArchitectures(cr, containers[i].Image))
+		containers[i].Image = model.GetPinnedImage(cr, containers[i].Image)
+		containers[i].SecurityContext = model.GetContainerSecurityContext(cr)
+	}
+
+	_, err := utils.Apply(ctx, r.client, deployment, func() error {
 		deployment.Labels = map[string]string{
 			"app.kubernetes.io/component": "authentication-proxy",
-			"app.kubernetes.io/name":      config.Name,
+			"app.kubernetes.io/name":      name,
 		}
 		deployment.Spec = v13.DeploymentSpec{
+			Replicas: &replicas,
 			Selector: &v14.LabelSelector{
 				MatchLabels: map[string]string{
 					"app.kubernetes.io/component": "authentication-proxy",
-					"app.kubernetes.io/name":      config.Name,
+					"app.kubernetes.io/name":      name,
 				},
 			},
 			Template: v12.PodTemplateSpec{
 				ObjectMeta: v14.ObjectMeta{
 					Labels: map[string]string{
 						"app.kubernetes.io/component": "authentication-proxy",
-						"app.kubernetes.io/name":      config.Name,
-						"app.kubernetes.io/version":   TokenRefresherImageTag,
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/version":   version,
 					},
+					Annotations: model.GetServiceMeshPodAnnotations(cr),
 				},
 				Spec: v12.PodSpec{
 					PriorityClassName: model.ObservabilityPriorityClassName,
-					Containers: []v12.Container{
-						{
-							Name:            config.Name,
-							Image:           fmt.Sprintf("quay.io/rhoas/mk-token-refresher:%v", TokenRefresherImageTag),
-							ImagePullPolicy: v12.PullAlways,
-							Args: []string{
-								"--oidc.audience=observatorium-telemeter",
-								fmt.Sprintf("--oidc.client-id=%v", config.Client),
-								fmt.Sprintf("--oidc.client-secret=%v", config.Secret),
-								fmt.Sprintf("--oidc.issuer-url=%v", config.AuthUrl),
-								fmt.Sprintf("--url=%v", config.ObservatoriumUrl),
-							},
-							Ports: []v12.ContainerPort{
-								{
-									Name:          "http",
-									ContainerPort: 8080,
-								},
-							},
-						},
+					SecurityContext:   model.GetPodSecurityContext(cr),
+					Affinity:          model.GetArchitectureAffinity(cr, nil, architectures...),
+					Volumes: []v12.Volume{
+						model.GetTrustedCABundleVolume(cr),
 					},
+					Containers: containers,
 				},
 			},
 		}
@@ -192,29 +247,144 @@ func (r *Reconciler) createDeploymentFor(ctx context.Context, cr *v1.Observabili
 	return err
 }
 
-func (r *Reconciler) reconcileTokenRefresherFor(ctx context.Context, cr *v1.Observability, observatorium *v1.ObservatoriumIndex, logsDisabled bool) error {
-	if !observatorium.IsValid() {
-		return errors2.New(fmt.Sprintf("incomplete observatorium config, tenant or gateway missing for %v", observatorium.Id))
+// Token refresher for a single config set. Containers of a shared deployment listen on
+// different ports, the internal port for health checks and metrics is the next one.
+func (r *Reconciler) getTokenRefresherContainer(config *model.TokenRefresherConfigSet, port int32) v12.Container {
+	container := v12.Container{
+		Name:            config.Name,
+		Image:           fmt.Sprintf("%v:%v", config.Image, config.Tag),
+		ImagePullPolicy: v12.PullAlways,
+		Args: []string{
+			"--oidc.audience=observatorium-telemeter",
+			fmt.Sprintf("--oidc.client-id=%v", config.Client),
+			fmt.Sprintf("--oidc.client-secret=%v", config.Secret),
+			fmt.Sprintf("--oidc.issuer-url=%v", config.AuthUrl),
+			fmt.Sprintf("--url=%v", config.ObservatoriumUrl),
+		},
+		Env:       model.GetProxyEnvVars(r.clusterProxy),
+		Resources: config.Resources,
+		VolumeMounts: []v12.VolumeMount{
+			model.GetTrustedCABundleVolumeMount(),
+		},
+		Ports: []v12.ContainerPort{
+			{
+				Name:          "http",
+				ContainerPort: port,
+			},
+		},
 	}
 
-	for _, t := range []model.TokenRefresherType{model.MetricsTokenRefresher, model.LogsTokenRefresher} {
-		// Don't deploy a token refresher for promtail when logs are disabled
-		if t == model.LogsTokenRefresher && logsDisabled {
-			continue
-		}
+	// The defaults are 8080 and 8081
+	if port != tokenRefresherPort {
+		container.Args = append(container.Args,
+			fmt.Sprintf("--web.listen=:%v", port),
+			fmt.Sprintf("--web.internal.listen=:%v", port+1))
+	}
+
+	return container
+}
+
+// Token refreshers requested by an index. Promtail only needs one when logs are enabled, the query
+// token refresher is deployed on demand for the readers of the index.
+func getTokenRefresherTypes(cr *v1.Observability, index *v1.RepositoryIndex) []model.TokenRefresherType {
+	result := []model.TokenRefresherType{model.MetricsTokenRefresher}
+	if index.Config != nil && index.Config.Promtail != nil && index.Config.Promtail.Enabled {
+		result = append(result, model.LogsTokenRefresher)
+	}
+	if isQueryTokenRefresherRequested(cr, index) {
+		result = append(result, model.QueryTokenRefresher)
+	}
+	return result
+}
 
-		configSet, err := getTokenRefresherConfigSetFor(t, observatorium)
+// True if something reads from the Observatorium instances of the index
+func isQueryTokenRefresherRequested(cr *v1.Observability, index *v1.RepositoryIndex) bool {
+	return isQueryDatasourceRequested(cr, index) || isRemoteWriteProbeRequested(cr, index)
+}
+
+func (r *Reconciler) getTokenRefresherConfigSetsFor(cr *v1.Observability, observatorium *v1.ObservatoriumIndex, types []model.TokenRefresherType) ([]*model.TokenRefresherConfigSet, error) {
+	if !observatorium.IsValid() {
+		return nil, errors2.New(fmt.Sprintf("incomplete observatorium config, tenant or gateway missing for %v", observatorium.Id))
+	}
+
+	var result []*model.TokenRefresherConfigSet
+	for _, t := range types {
+		// The token refresher proxies to the active gateway
+		active := *observatorium
+		active.Gateway = model.GetObservatoriumGateway(r.activeGateways, observatorium)
+		configSet, err := getTokenRefresherConfigSetFor(t, &active)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if configSet == nil {
 			// Do not abort in case of error, setups that skip logs are expected
-			r.logger.Info(fmt.Sprintf("skip creating %v token refresher for %v because of missing config", t, observatorium.Id))
+			r.logger.Info("skip creating token refresher because of missing config", "type", t, "observatorium", observatorium.Id)
 			continue
 		}
 
-		err = r.createServiceFor(ctx, cr, configSet)
+		configSet.Image, configSet.Tag = model.GetTokenRefresherImage(cr, observatorium)
+		configSet.Replicas = model.GetTokenRefresherReplicas(cr, observatorium)
+		configSet.Resources = model.GetTokenRefresherResources(cr, observatorium)
+		result = append(result, configSet)
+	}
+
+	return result, nil
+}
+
+// Config sets of all token refreshers requested by the indexes, an Observatorium instance
+// referenced by multiple indexes only needs one token refresher per type
+func (r *Reconciler) getTokenRefresherConfigSets(cr *v1.Observability, indexes []v1.RepositoryIndex) ([]*model.TokenRefresherConfigSet, error) {
+	var result []*model.TokenRefresherConfigSet
+	exists := func(name string) bool {
+		for _, configSet := range result {
+			if configSet.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, index := range indexes {
+		if index.Config == nil {
+			continue
+		}
+
+		types := getTokenRefresherTypes(cr, &index)
+		for _, observatorium := range index.Config.Observatoria {
+			// token-refresher is only used for sso.redhat.com authentication
+			if observatorium.AuthType != v1.AuthTypeRedhat {
+				continue
+			}
+
+			configSets, err := r.getTokenRefresherConfigSetsFor(cr, &observatorium, types)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, configSet := range configSets {
+				if !exists(configSet.Name) {
+					result = append(result, configSet)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Reconciler) reconcileTokenRefresher(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
+	configSets, err := r.getTokenRefresherConfigSets(cr, indexes)
+	if err != nil {
+		return err
+	}
+
+	if cr.SharedTokenRefresherEnabled() {
+		return r.reconcileSharedTokenRefreshers(ctx, cr, configSets)
+	}
+
+	for _, configSet := range configSets {
+		err = r.createServiceFor(ctx, cr, configSet, configSet.Name, tokenRefresherPort)
 		if err != nil {
 			return err
 		}
@@ -228,31 +398,137 @@ func (r *Reconciler) reconcileTokenRefresherFor(ctx context.Context, cr *v1.Obse
 	return nil
 }
 
-func (r *Reconciler) reconcileTokenRefresher(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
-	for _, index := range indexes {
-		if index.Config == nil {
-			continue
+// One deployment per auth realm with a container per config set, scaled to the highest
+// number of replicas requested for any of the config sets
+func (r *Reconciler) reconcileSharedTokenRefreshers(ctx context.Context, cr *v1.Observability, configSets []*model.TokenRefresherConfigSet) error {
+	realms := getTokenRefresherRealms(configSets)
+	for _, realm := range sortedRealms(realms) {
+		name := model.GetSharedTokenRefresherName(realm)
+
+		var containers []v12.Container
+		var types []model.TokenRefresherType
+		var replicas int32
+		for i, configSet := range realms[realm] {
+			port := tokenRefresherPort + int32(2*i)
+			err := r.createServiceFor(ctx, cr, configSet, name, port)
+			if err != nil {
+				return err
+			}
+
+			containers = append(containers, r.getTokenRefresherContainer(configSet, port))
+			if !hasTokenRefresherType(types, configSet.Type) {
+				types = append(types, configSet.Type)
+			}
+			if configSet.Replicas > replicas {
+				replicas = configSet.Replicas
+			}
 		}
 
-		promtailDisabled := false
-		if index.Config.Promtail == nil || index.Config.Promtail.Enabled == false {
-			promtailDisabled = true
+		err := r.createNetworkPolicy(ctx, cr, name, types)
+		if err != nil {
+			return err
 		}
 
-		for _, observatorium := range index.Config.Observatoria {
-			// token-refresher is only used for sso.redhat.com authentication
-			if observatorium.AuthType == v1.AuthTypeRedhat {
-				err := r.reconcileTokenRefresherFor(ctx, cr, &observatorium, promtailDisabled)
-				if err != nil {
-					return err
-				}
-			}
+		err = r.createDeployment(ctx, cr, name, replicas, realms[realm][0].Tag, containers)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Config sets grouped by realm and ordered by name, so that the ports of the containers are stable
+func getTokenRefresherRealms(configSets []*model.TokenRefresherConfigSet) map[string][]*model.TokenRefresherConfigSet {
+	realms := map[string][]*model.TokenRefresherConfigSet{}
+	for _, configSet := range configSets {
+		realms[configSet.Realm] = append(realms[configSet.Realm], configSet)
+	}
+	for _, realm := range realms {
+		sort.Slice(realm, func(i, j int) bool {
+			return realm[i].Name < realm[j].Name
+		})
+	}
+	return realms
+}
+
+func sortedRealms(realms map[string][]*model.TokenRefresherConfigSet) []string {
+	var result []string
+	for realm := range realms {
+		result = append(result, realm)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func hasTokenRefresherType(types []model.TokenRefresherType, t model.TokenRefresherType) bool {
+	for _, existing := range types {
+		if existing == t {
+			return true
 		}
 	}
+	return false
+}
+
+// Names of the shared deployments, empty if the shared mode is disabled or the config sets are invalid
+func (r *Reconciler) getSharedTokenRefresherNames(cr *v1.Observability, indexes []v1.RepositoryIndex) []string {
+	if !cr.SharedTokenRefresherEnabled() {
+		return nil
+	}
+
+	configSets, err := r.getTokenRefresherConfigSets(cr, indexes)
+	if err != nil {
+		return nil
+	}
 
+	var result []string
+	for _, realm := range sortedRealms(getTokenRefresherRealms(configSets)) {
+		result = append(result, model.GetSharedTokenRefresherName(realm))
+	}
+	return result
+}
+
+// Record the readiness of all token refresher deployments in the status
+func (r *Reconciler) reconcileTokenRefresherStatus(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) error {
+	list := &v13.DeploymentList{}
+	selector := labels.SelectorFromSet(map[string]string{
+		"app.kubernetes.io/component": "authentication-proxy",
+	})
+	opts := &client.ListOptions{
+		Namespace:     cr.GetPrometheusOperatorNamespace(),
+		LabelSelector: selector,
+	}
+
+	err := r.client.List(ctx, list, opts)
+	if err != nil {
+		return err
+	}
+
+	s.TokenRefreshers = getTokenRefresherStatus(list.Items)
 	return nil
 }
 
+// A deployment is ready once all requested replicas are ready
+func getTokenRefresherStatus(deployments []v13.Deployment) []v1.TokenRefresherStatus {
+	var result []v1.TokenRefresherStatus
+	for _, deployment := range deployments {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		result = append(result, v1.TokenRefresherStatus{
+			Name:          deployment.Name,
+			Ready:         deployment.Status.ReadyReplicas >= replicas,
+			Replicas:      replicas,
+			ReadyReplicas: deployment.Status.ReadyReplicas,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
 func (r *Reconciler) deleteUnrequestedNetworkPolicies(ctx context.Context, cr *v1.Observability, indexes []v1.RepositoryIndex) error {
 	list := &v15.NetworkPolicyList{}
 	selector := labels.SelectorFromSet(map[string]string{
@@ -268,11 +544,21 @@ func (r *Reconciler) deleteUnrequestedNetworkPolicies(ctx context.Context, cr *v
 		return err
 	}
 
+	shared := r.getSharedTokenRefresherNames(cr, indexes)
 	shouldExist := func(name string) bool {
 		if cr.ExternalSyncDisabled() || cr.ObservatoriumDisabled() {
 			return false
 		}
 
+		if cr.SharedTokenRefresherEnabled() {
+			for _, deployment := range shared {
+				if name == model.GetTokenRefresherNetworkPolicy(cr, deployment).Name {
+					return true
+				}
+			}
+			return false
+		}
+
 		for _, index := range indexes {
 			if index.Config == nil {
 				return false
@@ -283,14 +569,10 @@ func (r *Reconciler) deleteUnrequestedNetworkPolicies(ctx context.Context, cr *v
 					return false
 				}
 
-				for _, t := range []model.TokenRefresherType{model.MetricsTokenRefresher, model.LogsTokenRefresher} {
-					if t == model.LogsTokenRefresher && (index.Config.Promtail == nil || !index.Config.Promtail.Enabled) {
-						return false
-					}
-
+				for _, t := range getTokenRefresherTypes(cr, &index) {
 					configSet, err := getTokenRefresherConfigSetFor(t, &observatorium)
-					if err != nil {
-						return false
+					if err != nil || configSet == nil {
+						continue
 					}
 
 					if name == fmt.Sprintf("%v-network-policy", configSet.Name) {
@@ -329,11 +611,21 @@ func (r *Reconciler) deleteUnrequestedTokenRefreshers(ctx context.Context, cr *v
 		return err
 	}
 
+	shared := r.getSharedTokenRefresherNames(cr, indexes)
 	shouldExist := func(name string) bool {
 		if cr.ExternalSyncDisabled() || cr.ObservatoriumDisabled() {
 			return false
 		}
 
+		if cr.SharedTokenRefresherEnabled() {
+			for _, deployment := range shared {
+				if name == deployment {
+					return true
+				}
+			}
+			return false
+		}
+
 		for _, index := range indexes {
 			if index.Config == nil {
 				return false
@@ -344,14 +636,10 @@ func (r *Reconciler) deleteUnrequestedTokenRefreshers(ctx context.Context, cr *v
 					return false
 				}
 
-				for _, t := range []model.TokenRefresherType{model.MetricsTokenRefresher, model.LogsTokenRefresher} {
-					if t == model.LogsTokenRefresher && (index.Config.Promtail == nil || !index.Config.Promtail.Enabled) {
-						return false
-					}
-
+				for _, t := range getTokenRefresherTypes(cr, &index) {
 					configSet, err := getTokenRefresherConfigSetFor(t, &observatorium)
-					if err != nil {
-						return false
+					if err != nil || configSet == nil {
+						continue
 					}
 
 					if name == configSet.Name {
@@ -7,6 +7,8 @@ import (
 	. "github.com/onsi/gomega"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	v13 "k8s.io/api/apps/v1"
+	v14 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -40,6 +42,19 @@ var (
 			MetricsSecret: testSecret,
 		},
 	}
+	testObservatoriumSsoConfigHasQueries = &v1.ObservatoriumIndex{
+		Id:      "test-id",
+		Gateway: testGateway,
+		Tenant:  testTenant,
+		RedhatSsoConfig: &v1.RedhatSsoConfig{
+			Url:           testUrl,
+			Realm:         testRealm,
+			MetricsClient: "write-client",
+			MetricsSecret: "write-secret",
+			QueryClient:   testClient,
+			QuerySecret:   testSecret,
+		},
+	}
 	testObservatoriumSsoConfigHasLogs = &v1.ObservatoriumIndex{
 		Id:      "test-id",
 		Gateway: testGateway,
@@ -125,6 +140,40 @@ func TestTokenRefresher_GetTokenRefresherConfigSetFor(t *testing.T) {
 				Type:             model.LogsTokenRefresher,
 			},
 		},
+		{
+			name: "returns TokenRefresherConfigSet for queries with metrics client and secret",
+			args: args{
+				tokenRefresherType: model.QueryTokenRefresher,
+				observatorium:      testObservatoriumSsoConfigHasMetrics,
+			},
+			want: &model.TokenRefresherConfigSet{
+				ObservatoriumUrl: fmt.Sprintf("%v/api/metrics/v1/%v", testGateway, testTenant),
+				AuthUrl:          "test-url/realms/test-realm",
+				Name:             "token-refresher-query-test-id",
+				Realm:            testRealm,
+				Tenant:           testTenant,
+				Secret:           testSecret,
+				Client:           testClient,
+				Type:             model.QueryTokenRefresher,
+			},
+		},
+		{
+			name: "returns TokenRefresherConfigSet for queries with query client and secret",
+			args: args{
+				tokenRefresherType: model.QueryTokenRefresher,
+				observatorium:      testObservatoriumSsoConfigHasQueries,
+			},
+			want: &model.TokenRefresherConfigSet{
+				ObservatoriumUrl: fmt.Sprintf("%v/api/metrics/v1/%v", testGateway, testTenant),
+				AuthUrl:          "test-url/realms/test-realm",
+				Name:             "token-refresher-query-test-id",
+				Realm:            testRealm,
+				Tenant:           testTenant,
+				Secret:           testSecret,
+				Client:           testClient,
+				Type:             model.QueryTokenRefresher,
+			},
+		},
 	}
 
 	RegisterTestingT(t)
@@ -136,3 +185,93 @@ func TestTokenRefresher_GetTokenRefresherConfigSetFor(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenRefresher_GetTokenRefresherRealms(t *testing.T) {
+	metricsB := &model.TokenRefresherConfigSet{Name: "token-refresher-metrics-b", Realm: "realm-a"}
+	metricsA := &model.TokenRefresherConfigSet{Name: "token-refresher-metrics-a", Realm: "realm-a"}
+	logsA := &model.TokenRefresherConfigSet{Name: "token-refresher-logs-a", Realm: "realm-b"}
+
+	type args struct {
+		configSets []*model.TokenRefresherConfigSet
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want map[string][]*model.TokenRefresherConfigSet
+	}{
+		{
+			name: "config sets grouped by realm and ordered by name",
+			args: args{
+				configSets: []*model.TokenRefresherConfigSet{metricsB, logsA, metricsA},
+			},
+			want: map[string][]*model.TokenRefresherConfigSet{
+				"realm-a": {metricsA, metricsB},
+				"realm-b": {logsA},
+			},
+		},
+		{
+			name: "no realms without config sets",
+			args: args{
+				configSets: nil,
+			},
+			want: map[string][]*model.TokenRefresherConfigSet{},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getTokenRefresherRealms(tt.args.configSets)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestTokenRefresher_GetTokenRefresherStatus(t *testing.T) {
+	type args struct {
+		deployments []v13.Deployment
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []v1.TokenRefresherStatus
+	}{
+		{
+			name: "deployments are ready once all replicas are ready",
+			args: args{
+				deployments: []v13.Deployment{
+					{
+						ObjectMeta: v14.ObjectMeta{Name: "token-refresher-metrics-b"},
+						Spec:       v13.DeploymentSpec{Replicas: &([]int32{2})[0]},
+						Status:     v13.DeploymentStatus{ReadyReplicas: 1},
+					},
+					{
+						ObjectMeta: v14.ObjectMeta{Name: "token-refresher-metrics-a"},
+						Status:     v13.DeploymentStatus{ReadyReplicas: 1},
+					},
+				},
+			},
+			want: []v1.TokenRefresherStatus{
+				{Name: "token-refresher-metrics-a", Ready: true, Replicas: 1, ReadyReplicas: 1},
+				{Name: "token-refresher-metrics-b", Ready: false, Replicas: 2, ReadyReplicas: 1},
+			},
+		},
+		{
+			name: "no status without deployments",
+			args: args{
+				deployments: nil,
+			},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getTokenRefresherStatus(tt.args.deployments)
+			Expect(result).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package configuration
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
+	kv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Request the trusted CA bundle of the cluster and use it to verify the index and resource
+// requests of the operator. Without an injected bundle, e.g. outside of OpenShift, the
+// operator keeps skipping certificate verification unless FIPS mode is enabled. The requests
+// go through the cluster-wide proxy.
+func (r *Reconciler) reconcileTrustedCABundle(ctx context.Context, cr *v1.Observability) error {
+	configMap := model.GetTrustedCABundleConfigMap(cr)
+	labels := configMap.Labels
+
+	_, err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Labels = labels
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var pool *x509.CertPool
+	if bundle := configMap.Data[model.TrustedCABundleKey]; bundle != "" {
+		pool = getSystemCertPool()
+		if !pool.AppendCertsFromPEM([]byte(bundle)) {
+			return errors.New("no certificates found in trusted ca bundle")
+		}
+	}
+
+	// Prometheus serving TLS itself is queried in-cluster with a certificate of the service CA or of
+	// the web TLS secret, which the trusted bundle doesn't contain. Without verification they aren't needed.
+	if cr.PrometheusWebTLSEnabled() && !cr.ExternalPrometheusEnabled() && (pool != nil || cr.FIPSModeEnabled()) {
+		if pool == nil {
+			pool = getSystemCertPool()
+		}
+		err = r.appendPrometheusWebCAs(ctx, cr, pool)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.httpClient = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: model.GetTLSConfig(cr, pool),
+			Proxy:           model.GetProxyFunc(r.clusterProxy),
+		},
+	}
+	return nil
+}
+
+// Read from the service account of the operator pod on OpenShift
+var serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+// The service CA and the CA of the web TLS secret, e.g. issued by cert-manager, if present
+func (r *Reconciler) appendPrometheusWebCAs(ctx context.Context, cr *v1.Observability, pool *x509.CertPool) error {
+	serviceCA, err := ioutil.ReadFile(serviceCAFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	pool.AppendCertsFromPEM(serviceCA)
+
+	secret := &kv1.Secret{}
+	err = r.client.Get(ctx, client.ObjectKey{Namespace: cr.GetPrometheusOperatorNamespace(), Name: model.GetPrometheusWebTLSSecretName(cr)}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	pool.AppendCertsFromPEM(secret.Data["ca.crt"])
+	return nil
+}
+
+func getSystemCertPool() *x509.CertPool {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return x509.NewCertPool()
+	}
+	return pool
+}
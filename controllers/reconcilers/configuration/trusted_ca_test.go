@@ -0,0 +1,96 @@
+package configuration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	kv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildTestCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestTrustedCA_ReconcileTrustedCABundle(t *testing.T) {
+	RegisterTestingT(t)
+
+	prometheus := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer prometheus.Close()
+	prometheusCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: prometheus.Certificate().Raw})
+	otherCA := buildTestCA(t)
+
+	serviceCAFile = filepath.Join(t.TempDir(), "service-ca.crt")
+	defer func() { serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt" }()
+
+	enabled := true
+	cr := &v1.Observability{ObjectMeta: metav1.ObjectMeta{Name: "observability-stack", Namespace: "observability"}}
+	cr.Spec.PrometheusWebTLS = &v1.PrometheusWebTLS{Enabled: &enabled}
+
+	reconcile := func(objects ...runtime.Object) error {
+		scheme := runtime.NewScheme()
+		_ = kv1.AddToScheme(scheme)
+		bundle := model.GetTrustedCABundleConfigMap(cr)
+		bundle.Data = map[string]string{model.TrustedCABundleKey: string(otherCA)}
+		objects = append(objects, bundle)
+		r := &Reconciler{
+			client: testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()),
+			logger: logr.Discard(),
+		}
+		Expect(r.reconcileTrustedCABundle(context.TODO(), cr)).To(Succeed())
+		resp, err := r.httpClient.Get(prometheus.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// The trusted bundle doesn't contain the CA of the web certificate
+	Expect(reconcile()).To(MatchError(ContainSubstring("certificate")))
+
+	// The CA of the web TLS secret
+	secret := &kv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: model.GetPrometheusWebTLSSecretName(cr), Namespace: cr.GetPrometheusOperatorNamespace()},
+		Data:       map[string][]byte{"ca.crt": prometheusCA},
+	}
+	Expect(reconcile(secret)).To(Succeed())
+
+	// The service CA of OpenShift
+	Expect(os.WriteFile(serviceCAFile, prometheusCA, 0600)).To(Succeed())
+	Expect(reconcile()).To(Succeed())
+}
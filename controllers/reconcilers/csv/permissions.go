@@ -0,0 +1,12 @@
+package csv
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=get;list;watch;update;delete
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	return reconcilers.NewPermissions("operators.coreos.com", []string{"clusterserviceversions"}, []string{"get", "list", "watch", "update", "delete"}, cr.Namespace)
+}
@@ -20,7 +20,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type SourceType int
@@ -54,14 +53,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
-	status, err = r.reconcileClusterRole(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
-	}
+	// The auth delegation of the proxy is granted by a cluster admin when running namespace scoped
+	if !cr.NamespaceScoped() {
+		status, err = r.reconcileClusterRole(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 
-	status, err = r.reconcileClusterRoleBinding(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+		status, err = r.reconcileClusterRoleBinding(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 	}
 
 	status, err = r.reconcileGrafanaDatasource(ctx, cr)
@@ -103,16 +105,18 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 	}
 
 	// Role
-	clusterRoleBinding := model.GetGrafanaClusterRoleBinding(cr)
-	err = r.client.Delete(ctx, clusterRoleBinding)
-	if err != nil && !errors.IsNotFound(err) {
-		return v1.ResultFailed, err
-	}
+	if !cr.NamespaceScoped() {
+		clusterRoleBinding := model.GetGrafanaClusterRoleBinding(cr)
+		err = r.client.Delete(ctx, clusterRoleBinding)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
 
-	clusterRole := model.GetGrafanaClusterRole(cr)
-	err = r.client.Delete(ctx, clusterRole)
-	if err != nil && !errors.IsNotFound(err) {
-		return v1.ResultFailed, err
+		clusterRole := model.GetGrafanaClusterRole(cr)
+		err = r.client.Delete(ctx, clusterRole)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
 	}
 
 	return v1.ResultSuccess, nil
@@ -121,11 +125,9 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 func (r *Reconciler) reconileProxySecret(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	secret := model.GetGrafanaProxySecret(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
-		if secret.Data == nil {
-			secret.StringData = map[string]string{
-				"session_secret": utils.GenerateRandomString(32),
-			}
+	_, err := utils.ApplyIfMissing(ctx, r.client, secret, func() error {
+		secret.StringData = map[string]string{
+			"session_secret": utils.GenerateRandomString(32),
 		}
 		return nil
 	})
@@ -140,7 +142,7 @@ func (r *Reconciler) reconileProxySecret(ctx context.Context, cr *v1.Observabili
 func (r *Reconciler) reconcileClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	clusterRole := model.GetGrafanaClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRole, func() error {
+	_, err := utils.Apply(ctx, r.client, clusterRole, func() error {
 		clusterRole.Rules = []v12.PolicyRule{
 			{
 				Verbs:     []string{"create"},
@@ -167,7 +169,7 @@ func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *v1.Obs
 	clusterRoleBinding := model.GetGrafanaClusterRoleBinding(cr)
 	clusterRole := model.GetGrafanaClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRoleBinding, func() error {
+	_, err := utils.Apply(ctx, r.client, clusterRoleBinding, func() error {
 		clusterRoleBinding.RoleRef = v12.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
@@ -192,9 +194,12 @@ func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *v1.Obs
 
 func (r *Reconciler) reconcileGrafanaDatasource(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	datasource := model.GetGrafanaDatasource(cr)
-	url := fmt.Sprintf("http://prometheus-operated.%s:9090", cr.Namespace)
+	url := fmt.Sprintf("%v://prometheus-operated.%s:9090", model.GetPrometheusScheme(cr), cr.Namespace)
+	if cr.ExternalPrometheusEnabled() {
+		url = model.GetPrometheusUpstreamUrl(cr)
+	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, datasource, func() error {
+	_, err := utils.Apply(ctx, r.client, datasource, func() error {
 		datasource.Spec.Name = "obs-prometheus.yaml"
 		datasource.Spec.Datasources = []v1alpha1.GrafanaDataSourceFields{
 			{
@@ -0,0 +1,27 @@
+package grafana_configuration
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups=integreatly.org,resources=grafanas;grafanadatasources,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	if cr.DescopedModeEnabled() {
+		return nil
+	}
+	result := reconcilers.NewPermissions("integreatly.org", []string{"grafanas", "grafanadatasources"}, reconcilers.ManageVerbs, cr.Namespace)
+	result = append(result, reconcilers.NewPermissions("", []string{"secrets"}, reconcilers.ManageVerbs, cr.Namespace)...)
+	if !cr.NamespaceScoped() {
+		result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"clusterroles", "clusterrolebindings"}, reconcilers.ManageVerbs, "")...)
+		// Granted to the proxy of Grafana by its cluster role
+		result = append(result, reconcilers.NewPermissions("authorization.k8s.io", []string{"subjectaccessreviews"}, []string{"create"}, "")...)
+		result = append(result, reconcilers.NewPermissions("authentication.k8s.io", []string{"tokenreviews"}, []string{"create"}, "")...)
+	}
+	return result
+}
@@ -14,7 +14,6 @@ import (
 	v12 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const GrafanaOperatorDefaultVersion = "v3.10.7"
@@ -166,10 +165,10 @@ func (r *Reconciler) deleteUnrequestedSubscriptions(ctx context.Context, cr *v1.
 func (r *Reconciler) reconcileCatalogSource(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	source := model.GetGrafanaCatalogSource(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, source, func() error {
+	_, err := utils.Apply(ctx, r.client, source, func() error {
 		source.Spec = v1alpha1.CatalogSourceSpec{
 			SourceType: v1alpha1.SourceTypeGrpc,
-			Image:      "quay.io/rhoas/grafana-operator-index:" + GrafanaOperatorDefaultVersion,
+			Image:      model.GetMirroredImage(cr, "quay.io/rhoas/grafana-operator-index:"+GrafanaOperatorDefaultVersion),
 		}
 		return nil
 	})
@@ -185,7 +184,7 @@ func (r *Reconciler) reconcileSubscription(ctx context.Context, cr *v1.Observabi
 	subscription := model.GetGrafanaSubscription(cr)
 	source := model.GetGrafanaCatalogSource(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, subscription, func() error {
+	_, err := utils.Apply(ctx, r.client, subscription, func() error {
 		subscription.Spec = &v1alpha1.SubscriptionSpec{
 			CatalogSource:          source.Name,
 			CatalogSourceNamespace: source.Namespace,
@@ -216,7 +215,7 @@ func (r *Reconciler) reconcileOperatorgroup(ctx context.Context, cr *v1.Observab
 
 	operatorgroup := model.GetGrafanaOperatorGroup(cr)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, operatorgroup, func() error {
+	_, err = utils.Apply(ctx, r.client, operatorgroup, func() error {
 		operatorgroup.Spec = coreosv1.OperatorGroupSpec{
 			TargetNamespaces: []string{cr.Namespace},
 		}
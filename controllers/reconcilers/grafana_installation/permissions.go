@@ -0,0 +1,19 @@
+package grafana_installation
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=catalogsources;subscriptions;operatorgroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	if cr.DescopedModeEnabled() {
+		return nil
+	}
+	result := reconcilers.NewPermissions("operators.coreos.com", []string{"catalogsources", "subscriptions", "operatorgroups"}, reconcilers.ManageVerbs, cr.Namespace)
+	result = append(result, reconcilers.NewPermissions("operators.coreos.com", []string{"clusterserviceversions"}, reconcilers.ReadVerbs, cr.Namespace)...)
+	return append(result, reconcilers.NewPermissions("apps", []string{"deployments"}, reconcilers.ReadVerbs, cr.Namespace)...)
+}
@@ -11,6 +11,7 @@ import (
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
 	v12 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -34,7 +35,8 @@ func NewReconciler(client client.Client, logger logr.Logger) reconcilers.Observa
 	}
 }
 func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
-	if cr.DescopedModeEnabled() {
+	// The logging operator is only available on OpenShift
+	if cr.DescopedModeEnabled() || cr.IsKubernetesCluster() {
 		return v1.ResultSuccess, nil
 	}
 
@@ -87,7 +89,8 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
-	if cr.DescopedModeEnabled() {
+	// The logging operator is only available on OpenShift
+	if cr.DescopedModeEnabled() || cr.IsKubernetesCluster() {
 		return v1.ResultSuccess, nil
 	}
 
@@ -189,7 +192,7 @@ func (r *Reconciler) reconcileSubscription(ctx context.Context, cr *v1.Observabi
 
 	subscription := model.GetLoggingSubscription(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, subscription, func() error {
+	_, err := utils.Apply(ctx, r.client, subscription, func() error {
 		subscription.Spec = &v1alpha1.SubscriptionSpec{
 			CatalogSource:          "redhat-operators",
 			CatalogSourceNamespace: "openshift-marketplace",
@@ -292,7 +295,7 @@ func (r *Reconciler) createClusterLoggingCr(ctx context.Context, cr *v1.Observab
 	if len(list.Items) == 0 || len(labelList.Items) > 0 {
 		// There's no ClusterLogging or one that we manage
 		clCr := model.GetClusterLoggingCR()
-		_, err = controllerutil.CreateOrUpdate(ctx, r.client, clCr, func() error {
+		_, err = utils.Apply(ctx, r.client, clCr, func() error {
 			return nil
 		})
 
@@ -362,6 +365,8 @@ func (r *Reconciler) createClusterLogForwarderCr(ctx context.Context, cr *v1.Obs
 
 		newPipeline.InputRefs = append(newPipeline.InputRefs, "kafka-log-resources")
 
+		// The forwarder is shared with the pipelines of others, the input and pipeline are edited in place
+		// instead of applied
 		_, err = controllerutil.CreateOrUpdate(ctx, r.client, clusterLogForwarder, func() error {
 			clusterLogForwarder.Spec.Pipelines = []v14.PipelineSpec{*newPipeline}
 			var namespaces []string
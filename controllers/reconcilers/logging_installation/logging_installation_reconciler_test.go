@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,14 +47,14 @@ func TestLoggingInstallationReconciler(t *testing.T) {
 				},
 			},
 			fields: fields{
-				client: fakeclient.NewFakeClientWithScheme(scheme,
+				client: testutils.NewFakeApplyClient(fakeclient.NewFakeClientWithScheme(scheme,
 					&v1alpha1.Subscription{
 						ObjectMeta: metav1.ObjectMeta{
 							Name:      "cluster-logging",
 							Namespace: "openshift-logging",
 						},
 					},
-				),
+				)),
 			},
 			wantErr: false,
 			want:    v1.ResultSuccess,
@@ -0,0 +1,22 @@
+package logging_installation
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups=logging.openshift.io,resources=clusterloggings;clusterlogforwarders,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions;operatorgroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	if cr.DescopedModeEnabled() || cr.IsKubernetesCluster() {
+		return nil
+	}
+	result := reconcilers.NewPermissions("logging.openshift.io", []string{"clusterloggings", "clusterlogforwarders"}, reconcilers.ManageVerbs, loggingNamespace)
+	result = append(result, reconcilers.NewPermissions("operators.coreos.com", []string{"subscriptions"}, reconcilers.ManageVerbs, loggingNamespace)...)
+	return append(result, reconcilers.NewPermissions("", []string{"namespaces"}, reconcilers.ReadVerbs, "")...)
+}
+
+// Namespace of the cluster logging operator and its resources
+const loggingNamespace = "openshift-logging"
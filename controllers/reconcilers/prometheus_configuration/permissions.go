@@ -0,0 +1,57 @@
+package prometheus_configuration
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts;services;secrets;configmaps;persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods;endpoints;namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes;nodes/proxy,verbs=get;list;watch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings;roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	namespace := cr.GetPrometheusOperatorNamespace()
+	result := reconcilers.NewPermissions("", []string{"serviceaccounts", "services", "secrets", "configmaps", "persistentvolumeclaims"}, reconcilers.ManageVerbs, namespace)
+	result = append(result, getExposurePermissions(cr, namespace)...)
+
+	// Roles can only grant the permissions that the operator holds itself
+	if cr.NamespaceScoped() {
+		for _, target := range model.GetTargetNamespaces(cr) {
+			result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"roles", "rolebindings"}, reconcilers.ManageVerbs, target)...)
+			result = append(result, reconcilers.NewPermissions("", []string{"services", "endpoints", "pods"}, reconcilers.ReadVerbs, target)...)
+			result = append(result, reconcilers.NewPermissions("", []string{"configmaps"}, []string{"get"}, target)...)
+		}
+	} else {
+		result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"clusterroles", "clusterrolebindings"}, reconcilers.ManageVerbs, "")...)
+		result = append(result, reconcilers.NewPermissions("", []string{"services", "endpoints", "pods"}, reconcilers.ReadVerbs, "")...)
+		result = append(result, reconcilers.NewPermissions("", []string{"configmaps", "namespaces"}, []string{"get"}, "")...)
+		result = append(result, reconcilers.NewPermissions("authorization.k8s.io", []string{"subjectaccessreviews"}, []string{"create"}, "")...)
+		result = append(result, reconcilers.NewPermissions("authentication.k8s.io", []string{"tokenreviews"}, []string{"create"}, "")...)
+		if cr.KubeletScrapingEnabled() {
+			result = append(result, reconcilers.NewPermissions("", []string{"nodes", "nodes/proxy"}, reconcilers.ReadVerbs, "")...)
+		}
+	}
+	return result
+}
+
+// Prometheus is exposed with an ingress or, on OpenShift, with a route. The tenancy proxy always
+// has a route.
+func getExposurePermissions(cr *v1.Observability, namespace string) []v1.Permission {
+	if cr.IngressEnabled() {
+		result := reconcilers.NewPermissions("networking.k8s.io", []string{"ingresses"}, reconcilers.ManageVerbs, namespace)
+		if cr.TenancyProxyEnabled() {
+			result = append(result, reconcilers.NewPermissions("route.openshift.io", []string{"routes"}, reconcilers.ManageVerbs, namespace)...)
+		}
+		return result
+	}
+	if !cr.IsKubernetesCluster() {
+		return reconcilers.NewPermissions("route.openshift.io", []string{"routes"}, reconcilers.ManageVerbs, namespace)
+	}
+	return nil
+}
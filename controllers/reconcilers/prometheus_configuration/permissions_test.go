@@ -0,0 +1,77 @@
+package prometheus_configuration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPermissions_GetRequiredPermissions(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      v1.ObservabilitySpec
+		want      v1.Permission
+		wantFound bool
+	}{
+		{
+			name:      "cluster roles are managed by default",
+			want:      v1.Permission{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Verb: "create"},
+			wantFound: true,
+		},
+		{
+			name:      "routes expose Prometheus on OpenShift",
+			want:      v1.Permission{Group: "route.openshift.io", Resource: "routes", Verb: "create", Namespace: "observability"},
+			wantFound: true,
+		},
+		{
+			name: "routes for the tenancy proxy with ingresses",
+			spec: v1.ObservabilitySpec{
+				Ingress:      &v1.IngressSpec{},
+				TenancyProxy: &v1.TenancyProxySpec{Enabled: &([]bool{true})[0]},
+			},
+			want:      v1.Permission{Group: "route.openshift.io", Resource: "routes", Verb: "create", Namespace: "observability"},
+			wantFound: true,
+		},
+		{
+			name: "node proxy for the kubelet scraping",
+			spec: v1.ObservabilitySpec{
+				SelfContained: &v1.SelfContained{
+					DisableFederation: &([]bool{true})[0],
+					ScrapeKubelet:     &([]bool{true})[0],
+				},
+			},
+			want:      v1.Permission{Group: "", Resource: "nodes/proxy", Verb: "get"},
+			wantFound: true,
+		},
+		{
+			name:      "no cluster roles when namespace scoped",
+			spec:      v1.ObservabilitySpec{TargetNamespaces: []string{"team-a"}},
+			want:      v1.Permission{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Verb: "create"},
+			wantFound: false,
+		},
+		{
+			name:      "roles in the target namespaces when namespace scoped",
+			spec:      v1.ObservabilitySpec{TargetNamespaces: []string{"team-a"}},
+			want:      v1.Permission{Group: "rbac.authorization.k8s.io", Resource: "roles", Verb: "create", Namespace: "team-a"},
+			wantFound: true,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &v1.Observability{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "observability"},
+				Spec:       tt.spec,
+			}
+			r := &Reconciler{}
+			if tt.wantFound {
+				Expect(r.GetRequiredPermissions(cr)).To(ContainElement(tt.want))
+			} else {
+				Expect(r.GetRequiredPermissions(cr)).ToNot(ContainElement(tt.want))
+			}
+		})
+	}
+}
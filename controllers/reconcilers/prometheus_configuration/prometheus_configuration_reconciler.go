@@ -15,7 +15,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
@@ -37,13 +36,30 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
+	// Delete static targets
+	configMap := model.GetPrometheusStaticTargetsConfigMap(cr)
+	err = r.client.Delete(ctx, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
 	// Delete route
 	route := model.GetPrometheusRoute(cr)
 	err = r.client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	ingress := model.GetPrometheusIngress(cr)
+	err = r.client.Delete(ctx, ingress)
 	if err != nil && !errors.IsNotFound(err) {
 		return v1.ResultFailed, err
 	}
 
+	status, err := r.deleteTenancyProxy(ctx, cr)
+	if status != v1.ResultSuccess {
+		return status, err
+	}
+
 	// Delete Prometheus CR
 	prom := model.GetPrometheus(cr)
 	err = r.client.Delete(ctx, prom)
@@ -51,23 +67,52 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		return v1.ResultFailed, err
 	}
 
+	// Delete the candidate of an unfinished blue/green upgrade
+	candidate := model.GetPrometheusCandidate(cr)
+	err = r.client.Delete(ctx, candidate)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
 	// Wait for the operator to be removed
-	status, err := utils.WaitForPrometheusToBeRemoved(ctx, cr, r.client)
+	status, err = utils.WaitForPrometheusToBeRemoved(ctx, cr, r.client)
 	if status != v1.ResultSuccess {
 		return status, err
 	}
 
-	// Delete role and rolebinding
-	rb := model.GetPrometheusClusterRoleBinding(cr)
-	err = r.client.Delete(ctx, rb)
-	if err != nil && !errors.IsNotFound(err) {
-		return v1.ResultFailed, err
+	// Volume claims outlive the statefulset, they are only deleted if the metric history shall not be kept
+	if !cr.RetainStorageOnDeleteEnabled() {
+		err = r.deletePrometheusVolumeClaims(ctx, cr)
+		if err != nil {
+			return v1.ResultFailed, err
+		}
 	}
 
-	role := model.GetPrometheusClusterRole(cr)
-	err = r.client.Delete(ctx, role)
-	if err != nil && !errors.IsNotFound(err) {
-		return v1.ResultFailed, err
+	// Delete role and rolebinding
+	if cr.NamespaceScoped() {
+		for _, namespace := range model.GetTargetNamespaces(cr) {
+			err = r.client.Delete(ctx, model.GetPrometheusRoleBinding(cr, namespace))
+			if err != nil && !errors.IsNotFound(err) {
+				return v1.ResultFailed, err
+			}
+
+			err = r.client.Delete(ctx, model.GetPrometheusRole(cr, namespace))
+			if err != nil && !errors.IsNotFound(err) {
+				return v1.ResultFailed, err
+			}
+		}
+	} else {
+		rb := model.GetPrometheusClusterRoleBinding(cr)
+		err = r.client.Delete(ctx, rb)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+
+		role := model.GetPrometheusClusterRole(cr)
+		err = r.client.Delete(ctx, role)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
 	}
 
 	// Service account
@@ -100,6 +145,29 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 	return v1.ResultSuccess, nil
 }
 
+func (r *Reconciler) deletePrometheusVolumeClaims(ctx context.Context, cr *v1.Observability) error {
+	list := &core.PersistentVolumeClaimList{}
+	opts := &client.ListOptions{
+		Namespace: cr.GetPrometheusOperatorNamespace(),
+	}
+	err := r.client.List(ctx, list, opts)
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		pvc := &list.Items[i]
+		if !model.IsPrometheusVolumeClaimName(cr, pvc.Name) {
+			continue
+		}
+		err = r.client.Delete(ctx, pvc)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error) {
 	// prometheus proxy secret
 	// prometheus service account
@@ -114,16 +182,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
-	// prometheus cluster role
-	status, err = r.reconcileClusterRole(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
-	}
+	if cr.NamespaceScoped() {
+		// prometheus roles in the target namespaces
+		status, err = r.reconcileRoles(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+	} else {
+		// prometheus cluster role
+		status, err = r.reconcileClusterRole(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 
-	// prometheus cluster role binding
-	status, err = r.reconcileClusterRoleBinding(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+		// prometheus cluster role binding
+		status, err = r.reconcileClusterRoleBinding(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 	}
 
 	// prometheus service
@@ -132,15 +208,35 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 		return status, err
 	}
 
-	// prometheus route
-	status, err = r.reconcileRoute(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+	// prometheus route or ingress
+	if cr.IngressEnabled() {
+		status, err = r.reconcileIngress(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+	} else if !cr.IsKubernetesCluster() {
+		status, err = r.reconcileRoute(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+
+		status, err = r.waitForRoute(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 	}
 
-	status, err = r.waitForRoute(ctx, cr)
-	if status != v1.ResultSuccess {
-		return status, err
+	// namespace scoped query api, without routes for Prometheus there is nothing to remove
+	if cr.TenancyProxyEnabled() {
+		status, err = r.reconcileTenancyProxy(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
+	} else if !cr.IngressEnabled() {
+		status, err = r.deleteTenancyProxy(ctx, cr)
+		if status != v1.ResultSuccess {
+			return status, err
+		}
 	}
 
 	// try to obtain the cluster id
@@ -160,7 +256,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 
 func (r *Reconciler) reconcileTokenLifetimeStorage(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	configmap := model.GetPrometheusAuthTokenLifetimes(cr)
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, configmap, func() error {
+	_, err := utils.Apply(ctx, r.client, configmap, func() error {
 		configmap.Labels = map[string]string{
 			"managed-by": "observability-operator",
 		}
@@ -177,7 +273,11 @@ func (r *Reconciler) reconcileTokenLifetimeStorage(ctx context.Context, cr *v1.O
 func (r *Reconciler) reconcileServiceAccount(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	serviceAccount := model.GetPrometheusServiceAccount(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, serviceAccount, func() error { return nil })
+	_, err := utils.Apply(ctx, r.client, serviceAccount, func() error {
+		// Identities removed from the CR are removed from the service account, annotations of others are kept
+		serviceAccount.Annotations = model.GetWorkloadIdentityAnnotations(cr)
+		return nil
+	})
 
 	if err != nil {
 		return v1.ResultFailed, err
@@ -189,11 +289,9 @@ func (r *Reconciler) reconcileServiceAccount(ctx context.Context, cr *v1.Observa
 func (r *Reconciler) reconcilePrometheusProxySecret(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	secret := model.GetPrometheusProxySecret(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, secret, func() error {
-		if secret.Data == nil {
-			secret.StringData = map[string]string{
-				"session_secret": utils.GenerateRandomString(64),
-			}
+	_, err := utils.ApplyIfMissing(ctx, r.client, secret, func() error {
+		secret.StringData = map[string]string{
+			"session_secret": utils.GenerateRandomString(64),
 		}
 		return nil
 	})
@@ -206,20 +304,23 @@ func (r *Reconciler) reconcilePrometheusProxySecret(ctx context.Context, cr *v1.
 
 func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	service := model.GetPrometheusService(cr)
-	prom := model.GetPrometheus(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
-		service.Annotations = map[string]string{
-			"service.alpha.openshift.io/serving-cert-secret-name": "prometheus-k8s-tls",
-		}
+	_, err := utils.Apply(ctx, r.client, service, func() error {
+		service.Annotations = model.GetServingCertAnnotations(cr, model.GetPrometheusTLSSecret(cr).Name)
+		// The configuration stage switches to the candidate during a blue/green upgrade
 		service.Spec.Selector = map[string]string{
-			"prometheus": prom.Name,
+			"prometheus": model.GetPrometheusServingName(cr, cr.Status.PrometheusUpgrade),
+		}
+		// Without the oauth proxy the web port is served by prometheus directly
+		webTargetPort := intstr.FromString("proxy")
+		if !cr.OAuthProxyEnabled() || cr.PrometheusWebTLSEnabled() {
+			webTargetPort = intstr.FromString("web")
 		}
 		service.Spec.Ports = []core.ServicePort{
 			{
 				Name:       "web",
 				Port:       9091,
-				TargetPort: intstr.FromString("proxy"),
+				TargetPort: webTargetPort,
 			},
 			{
 				Name:       "upstream",
@@ -227,6 +328,13 @@ func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability)
 				TargetPort: intstr.FromString("web"),
 			},
 		}
+		if cr.TenancyProxyEnabled() {
+			service.Spec.Ports = append(service.Spec.Ports, core.ServicePort{
+				Name:       "tenancy",
+				Port:       model.TenancyProxyPort,
+				TargetPort: intstr.FromString("tenancy"),
+			})
+		}
 		return nil
 	})
 
@@ -240,7 +348,7 @@ func (r *Reconciler) reconcileService(ctx context.Context, cr *v1.Observability)
 func (r *Reconciler) reconcileClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	clusterRole := model.GetPrometheusClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRole, func() error {
+	_, err := utils.Apply(ctx, r.client, clusterRole, func() error {
 		clusterRole.Rules = []rbacv1.PolicyRule{
 			{
 				Verbs:     []string{"get", "list", "watch"},
@@ -267,6 +375,14 @@ func (r *Reconciler) reconcileClusterRole(ctx context.Context, cr *v1.Observabil
 				NonResourceURLs: []string{"/metrics"},
 			},
 		}
+		// The kubelets are scraped through the node proxy
+		if cr.KubeletScrapingEnabled() {
+			clusterRole.Rules = append(clusterRole.Rules, rbacv1.PolicyRule{
+				Verbs:     []string{"get", "list", "watch"},
+				APIGroups: []string{""},
+				Resources: []string{"nodes", "nodes/proxy"},
+			})
+		}
 		return nil
 	})
 
@@ -277,11 +393,58 @@ func (r *Reconciler) reconcileClusterRole(ctx context.Context, cr *v1.Observabil
 	return v1.ResultSuccess, nil
 }
 
+// Without cluster wide permissions Prometheus can only discover targets in the target namespaces
+func (r *Reconciler) reconcileRoles(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	for _, namespace := range model.GetTargetNamespaces(cr) {
+		role := model.GetPrometheusRole(cr, namespace)
+		_, err := utils.Apply(ctx, r.client, role, func() error {
+			role.Rules = []rbacv1.PolicyRule{
+				{
+					Verbs:     []string{"get", "list", "watch"},
+					APIGroups: []string{""},
+					Resources: []string{"services", "endpoints", "pods"},
+				},
+				{
+					Verbs:     []string{"get"},
+					APIGroups: []string{""},
+					Resources: []string{"configmaps"},
+				},
+			}
+			return nil
+		})
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+
+		binding := model.GetPrometheusRoleBinding(cr, namespace)
+		_, err = utils.Apply(ctx, r.client, binding, func() error {
+			binding.Subjects = []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      model.GetPrometheusServiceAccount(cr).Name,
+					Namespace: cr.GetPrometheusOperatorNamespace(),
+				},
+			}
+			binding.RoleRef = rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "Role",
+				Name:     role.Name,
+			}
+			return nil
+		})
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+	}
+
+	return v1.ResultSuccess, nil
+}
+
 func (r *Reconciler) reconcileClusterRoleBinding(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	clusterRoleBinding := model.GetPrometheusClusterRoleBinding(cr)
 	role := model.GetPrometheusClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, clusterRoleBinding, func() error {
+	_, err := utils.Apply(ctx, r.client, clusterRoleBinding, func() error {
 		clusterRoleBinding.Subjects = []rbacv1.Subject{
 			{
 				Kind:      rbacv1.ServiceAccountKind,
@@ -308,7 +471,18 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, cr *v1.Observability) (
 	route := model.GetPrometheusRoute(cr)
 	service := model.GetPrometheusService(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, route, func() error {
+	// The router trusts the service CA, certificates of cert-manager are verified with its CA
+	termination := getRouteTermination(cr)
+	destinationCA := ""
+	if cr.CertManagerEnabled() && termination == routev1.TLSTerminationReencrypt {
+		var err error
+		destinationCA, err = utils.GetServingCertificateCA(ctx, r.client, route.Namespace, model.GetPrometheusWebTLSSecretName(cr))
+		if err != nil {
+			return v1.ResultFailed, err
+		}
+	}
+
+	_, err := utils.Apply(ctx, r.client, route, func() error {
 		route.Spec = routev1.RouteSpec{
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
@@ -319,7 +493,8 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, cr *v1.Observability) (
 			},
 			WildcardPolicy: routev1.WildcardPolicyNone,
 			TLS: &routev1.TLSConfig{
-				Termination: "reencrypt",
+				Termination:              termination,
+				DestinationCACertificate: destinationCA,
 			},
 		}
 		return nil
@@ -332,6 +507,19 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, cr *v1.Observability) (
 	return v1.ResultSuccess, nil
 }
 
+// The router only verifies certificates of the service CA when reencrypting, other web TLS
+// certificates are passed through to Prometheus. Without the oauth proxy or web TLS, e.g. when
+// the mesh authenticates, Prometheus serves plain HTTP.
+func getRouteTermination(cr *v1.Observability) routev1.TLSTerminationType {
+	if cr.PrometheusWebTLSEnabled() && cr.Spec.PrometheusWebTLS.SecretName != "" {
+		return routev1.TLSTerminationPassthrough
+	}
+	if !cr.PrometheusWebTLSEnabled() && !cr.OAuthProxyEnabled() {
+		return routev1.TLSTerminationEdge
+	}
+	return routev1.TLSTerminationReencrypt
+}
+
 func (r *Reconciler) waitForRoute(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	route := model.GetPrometheusRoute(cr)
 	selector := client.ObjectKey{
@@ -367,7 +555,12 @@ func (r *Reconciler) fetchClusterId(ctx context.Context, cr *v1.Observability, n
 		return v1.ResultSuccess, nil
 	}
 
-	clusterId, err := utils.GetClusterId(ctx, r.client)
+	getClusterId := utils.GetClusterId
+	if cr.IsKubernetesCluster() {
+		getClusterId = utils.GetKubernetesClusterId
+	}
+
+	clusterId, err := getClusterId(ctx, r.client)
 	if err != nil {
 		return v1.ResultFailed, err
 	}
@@ -376,3 +569,84 @@ func (r *Reconciler) fetchClusterId(ctx context.Context, cr *v1.Observability, n
 
 	return v1.ResultSuccess, nil
 }
+
+func (r *Reconciler) reconcileIngress(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	ingress := model.GetPrometheusIngress(cr)
+	endpoint := cr.Spec.Ingress.Prometheus
+
+	// Not exposed, remove the ingress in case the endpoint was removed from the spec
+	if endpoint == nil {
+		err := r.client.Delete(ctx, ingress)
+		if err != nil && !errors.IsNotFound(err) {
+			return v1.ResultFailed, err
+		}
+		return v1.ResultSuccess, nil
+	}
+
+	service := model.GetPrometheusService(cr)
+
+	_, err := utils.Apply(ctx, r.client, ingress, func() error {
+		ingress.Annotations = cr.Spec.Ingress.Annotations
+		ingress.Spec = model.GetIngressSpec(cr, endpoint, service.Name, "web")
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+func (r *Reconciler) reconcileTenancyProxy(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	configMap := model.GetTenancyProxyConfigMap(cr)
+	_, err := utils.Apply(ctx, r.client, configMap, func() error {
+		configMap.Data = map[string]string{
+			model.TenancyProxyConfigKey: model.GetTenancyProxyConfig(),
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	route := model.GetPrometheusTenancyRoute(cr)
+	service := model.GetPrometheusService(cr)
+	_, err = utils.Apply(ctx, r.client, route, func() error {
+		route.Spec = routev1.RouteSpec{
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: service.Name,
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString("tenancy"),
+			},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+			TLS: &routev1.TLSConfig{
+				Termination: "reencrypt",
+			},
+		}
+		return nil
+	})
+	if err != nil {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
+
+// The tenancy proxy was disabled or the CR is deleted
+func (r *Reconciler) deleteTenancyProxy(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
+	route := model.GetPrometheusTenancyRoute(cr)
+	err := r.client.Delete(ctx, route)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return v1.ResultFailed, err
+	}
+
+	configMap := model.GetTenancyProxyConfigMap(cr)
+	err = r.client.Delete(ctx, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return v1.ResultFailed, err
+	}
+
+	return v1.ResultSuccess, nil
+}
@@ -0,0 +1,24 @@
+package prometheus_installation
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=catalogsources;subscriptions;operatorgroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;patch;delete
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	namespace := cr.GetPrometheusOperatorNamespace()
+	result := reconcilers.NewPermissions("operators.coreos.com", []string{"catalogsources", "subscriptions", "operatorgroups"}, reconcilers.ManageVerbs, namespace)
+	result = append(result, reconcilers.NewPermissions("operators.coreos.com", []string{"clusterserviceversions"}, reconcilers.ReadVerbs, namespace)...)
+	result = append(result, reconcilers.NewPermissions("apps", []string{"deployments"}, reconcilers.ReadVerbs, namespace)...)
+
+	// The namespace of Prometheus is only created in descoped mode
+	if cr.DescopedModeEnabled() {
+		result = append(result, reconcilers.NewPermissions("", []string{"namespaces"}, []string{"get", "list", "watch", "create", "patch"}, "")...)
+	}
+	return result
+}
@@ -15,7 +15,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
@@ -54,7 +53,8 @@ func (r *Reconciler) Cleanup(ctx context.Context, cr *v1.Observability) (v1.Obse
 		return v1.ResultFailed, err
 	}
 
-	if cr.DescopedModeEnabled() {
+	// Deleting the namespace would also delete the retained volume claims
+	if cr.DescopedModeEnabled() && !cr.RetainStorageOnDeleteEnabled() {
 		namespace := model.GetPrometheusNamespace(cr)
 		err = r.client.Delete(ctx, namespace)
 		if err != nil && !errors.IsNotFound(err) {
@@ -122,7 +122,7 @@ func (r *Reconciler) waitForPrometheusOperator(ctx context.Context, cr *v1.Obser
 func (r *Reconciler) reconcileNamespace(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	namespace := model.GetPrometheusNamespace(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, namespace, func() error {
+	_, err := utils.Apply(ctx, r.client, namespace, func() error {
 		return nil
 	})
 
@@ -158,10 +158,10 @@ func (r *Reconciler) reconcileCatalogSource(ctx context.Context, cr *v1.Observab
 	}
 
 	// install Promethues Operator by catalogSource
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, source, func() error {
+	_, err = utils.Apply(ctx, r.client, source, func() error {
 		source.Spec = v1alpha1.CatalogSourceSpec{
 			SourceType: v1alpha1.SourceTypeGrpc,
-			Image:      "quay.io/integreatly/custom-prometheus-index:1.0.0",
+			Image:      model.GetMirroredImage(cr, "quay.io/integreatly/custom-prometheus-index:1.0.0"),
 		}
 		return nil
 	})
@@ -177,7 +177,7 @@ func (r *Reconciler) reconcileSubscription(ctx context.Context, cr *v1.Observabi
 	subscription := model.GetPrometheusSubscription(cr)
 	source := model.GetPrometheusCatalogSource(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, subscription, func() error {
+	_, err := utils.Apply(ctx, r.client, subscription, func() error {
 		subscription.Spec = &v1alpha1.SubscriptionSpec{
 			CatalogSource:          source.Name,
 			CatalogSourceNamespace: cr.GetPrometheusOperatorNamespace(),
@@ -208,9 +208,9 @@ func (r *Reconciler) reconcileOperatorgroup(ctx context.Context, cr *v1.Observab
 
 	operatorgroup := model.GetPrometheusOperatorgroup(cr)
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.client, operatorgroup, func() error {
+	_, err = utils.Apply(ctx, r.client, operatorgroup, func() error {
 		operatorgroup.Spec = coreosv1.OperatorGroupSpec{
-			TargetNamespaces: []string{cr.GetPrometheusOperatorNamespace()},
+			TargetNamespaces: getOperatorGroupTargetNamespaces(cr),
 		}
 
 		return nil
@@ -223,6 +223,14 @@ func (r *Reconciler) reconcileOperatorgroup(ctx context.Context, cr *v1.Observab
 	return v1.ResultSuccess, nil
 }
 
+// A namespace scoped Prometheus operator watches the target namespaces in addition to its own
+func getOperatorGroupTargetNamespaces(cr *v1.Observability) []string {
+	if cr.NamespaceScoped() {
+		return model.GetTargetNamespaces(cr)
+	}
+	return []string{cr.GetPrometheusOperatorNamespace()}
+}
+
 func (r *Reconciler) removePrometheusOperatorResources(ctx context.Context, cr *v1.Observability) error {
 	// Delete subscription
 	subscription := &v1alpha1.Subscription{
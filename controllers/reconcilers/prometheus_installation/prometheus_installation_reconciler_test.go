@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -47,7 +48,7 @@ func TestPrometheusInstallationReconciler_ReconcileCatalogSource(t *testing.T) {
 				},
 			},
 			fields: fields{
-				client: fakeclient.NewFakeClientWithScheme(scheme,
+				client: testutils.NewFakeApplyClient(fakeclient.NewFakeClientWithScheme(scheme,
 					&v1alpha1.CatalogSource{
 						ObjectMeta: metav1.ObjectMeta{
 							Name:      "prometheus-catalogsource",
@@ -57,7 +58,7 @@ func TestPrometheusInstallationReconciler_ReconcileCatalogSource(t *testing.T) {
 							Image: "quay.io/integreatly/custom-prometheus-index:1.0.0",
 						},
 					},
-				),
+				)),
 			},
 			wantErr: false,
 			want:    v1.ResultSuccess,
@@ -73,7 +74,7 @@ func TestPrometheusInstallationReconciler_ReconcileCatalogSource(t *testing.T) {
 				},
 			},
 			fields: fields{
-				client: fakeclient.NewFakeClientWithScheme(scheme),
+				client: testutils.NewFakeApplyClient(fakeclient.NewFakeClientWithScheme(scheme)),
 			},
 			wantErr: false,
 			want:    v1.ResultSuccess,
@@ -0,0 +1,21 @@
+package promtail_installation
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=nodes;nodes/proxy;services;endpoints;pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=privileged,verbs=use
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	if cr.ObservatoriumDisabled() || cr.ExternalSyncDisabled() {
+		return nil
+	}
+	result := reconcilers.NewPermissions("", []string{"serviceaccounts"}, reconcilers.ManageVerbs, cr.Namespace)
+	result = append(result, reconcilers.NewPermissions("rbac.authorization.k8s.io", []string{"clusterroles", "clusterrolebindings"}, reconcilers.ManageVerbs, "")...)
+	// Granted to Promtail by its cluster role
+	return append(result, reconcilers.NewPermissions("", []string{"nodes", "nodes/proxy", "services", "endpoints", "pods"}, reconcilers.ReadVerbs, "")...)
+}
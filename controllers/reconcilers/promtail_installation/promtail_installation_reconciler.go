@@ -6,10 +6,10 @@ import (
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
 	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 type Reconciler struct {
@@ -80,7 +80,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, cr *v1.Observability, s *v1.
 func (r *Reconciler) reconcilePromtailServiceAccount(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	sa := model.GetPromtailServiceAccount(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, sa, func() error {
+	_, err := utils.Apply(ctx, r.client, sa, func() error {
 		return nil
 	})
 
@@ -94,7 +94,7 @@ func (r *Reconciler) reconcilePromtailServiceAccount(ctx context.Context, cr *v1
 func (r *Reconciler) reconcilePromtailClusterRole(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error) {
 	role := model.GetPromtailClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, role, func() error {
+	_, err := utils.Apply(ctx, r.client, role, func() error {
 		role.Rules = []rbacv1.PolicyRule{
 			{
 				Verbs:     []string{"get", "list", "watch"},
@@ -129,7 +129,7 @@ func (r *Reconciler) reconcilePromtailClusterRoleBinding(ctx context.Context, cr
 	sa := model.GetPromtailServiceAccount(cr)
 	role := model.GetPromtailClusterRole(cr)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, rolebinding, func() error {
+	_, err := utils.Apply(ctx, r.client, rolebinding, func() error {
 		rolebinding.RoleRef = rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
@@ -9,3 +9,30 @@ type ObservabilityReconciler interface {
 	Reconcile(ctx context.Context, cr *v1.Observability, status *v1.ObservabilityStatus) (v1.ObservabilityStageStatus, error)
 	Cleanup(ctx context.Context, cr *v1.Observability) (v1.ObservabilityStageStatus, error)
 }
+
+// Implemented by the reconcilers of stages that access the API. The permissions depend on the
+// features enabled in the CR and are checked before the stage is reconciled.
+type PermissionsProvider interface {
+	GetRequiredPermissions(cr *v1.Observability) []v1.Permission
+}
+
+var (
+	ReadVerbs   = []string{"get", "list", "watch"}
+	ManageVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+)
+
+// All combinations of the resources and verbs, cluster wide if the namespace is empty
+func NewPermissions(group string, resources []string, verbs []string, namespace string) []v1.Permission {
+	var result []v1.Permission
+	for _, resource := range resources {
+		for _, verb := range verbs {
+			result = append(result, v1.Permission{
+				Group:     group,
+				Resource:  resource,
+				Verb:      verb,
+				Namespace: namespace,
+			})
+		}
+	}
+	return result
+}
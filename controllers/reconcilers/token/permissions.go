@@ -0,0 +1,17 @@
+package token
+
+import (
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/reconcilers"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=external-secrets.io,resources=externalsecrets,verbs=get;list;watch;create;update;patch;delete
+
+func (r *Reconciler) GetRequiredPermissions(cr *v1.Observability) []v1.Permission {
+	result := reconcilers.NewPermissions("", []string{"secrets"}, reconcilers.ManageVerbs, cr.Namespace)
+	if cr.GetCredentialProviderType() == v1.CredentialProviderExternalSecrets {
+		result = append(result, reconcilers.NewPermissions("external-secrets.io", []string{"externalsecrets"}, reconcilers.ManageVerbs, cr.Namespace)...)
+	}
+	return result
+}
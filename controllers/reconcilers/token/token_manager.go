@@ -6,13 +6,15 @@ import (
 	"github.com/go-logr/logr"
 	errors2 "github.com/pkg/errors"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/credentials"
 	"github.com/redhat-developer/observability-operator/v4/controllers/token"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils"
 	v12 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"net/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"strconv"
 )
 
@@ -33,6 +35,11 @@ const (
 	ObservatoriumSecretKeyMetricsSecret  = "metricsSecret"
 	ObservatoriumSecretKeyLogsClient     = "logsClientId"
 	ObservatoriumSecretKeyLogsSecret     = "logsSecret"
+
+	ObservatoriumSecretKeySigv4Region  = "sigv4Region"
+	ObservatoriumSecretKeySigv4RoleArn = "sigv4RoleArn"
+
+	ObservatoriumSecretKeyServiceAccountAudience = "serviceAccountAudience"
 )
 
 func GetObservatoriumTokenSecretName(config *v1.ObservatoriumIndex) string {
@@ -96,8 +103,8 @@ func findToken(ctx context.Context, c client.Client, cr *v1.Observability, confi
 	return string(token), lifetimeLeft, err
 }
 
-func refreshToken(ctx context.Context, c client.Client, config *v1.ObservatoriumIndex, cr *v1.Observability, oldToken string) (string, int64, error) {
-	fetcher := token.GetTokenFetcher(config, ctx, c)
+func refreshToken(ctx context.Context, c client.Client, httpClient *http.Client, config *v1.ObservatoriumIndex, cr *v1.Observability, oldToken string) (string, int64, error) {
+	fetcher := token.GetTokenFetcher(config, ctx, c, httpClient)
 	newToken, expires, err := fetcher.Fetch(cr, config, oldToken)
 	if err != nil {
 		return "", 0, errors2.Wrap(err, fmt.Sprintf("error fetching token for %v", config.Id))
@@ -115,7 +122,7 @@ func saveToken(ctx context.Context, c client.Client, config *v1.ObservatoriumInd
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+	_, err := utils.Apply(ctx, c, secret, func() error {
 		secret.Labels = map[string]string{
 			"managed-by": "observability-operator",
 			"purpose":    "observatorium-token-secret",
@@ -169,27 +176,21 @@ func TokensExpired(ctx context.Context, c client.Client, cr *v1.Observability) (
 }
 
 func assignFromSecret(ctx context.Context, c client.Client, cr *v1.Observability, index *v1.ObservatoriumIndex) error {
-	targetSecret := &v12.Secret{}
-	selector := client.ObjectKey{
-		Namespace: cr.Namespace,
-		Name:      index.SecretName,
-	}
-
-	err := c.Get(ctx, selector, targetSecret)
+	targetSecret, err := credentials.GetCredentialProvider(cr, c).GetCredentials(ctx, cr.Namespace, index.SecretName)
 	if err != nil {
 		return err
 	}
 
 	if index.AuthType == "" {
-		index.AuthType = v1.ObservabilityAuthType(targetSecret.Data[ObservatoriumSecretKeyAuthType])
+		index.AuthType = v1.ObservabilityAuthType(targetSecret[ObservatoriumSecretKeyAuthType])
 	}
 
 	if index.Gateway == "" {
-		index.Gateway = string(targetSecret.Data[ObservatoriumSecretKeyGateway])
+		index.Gateway = string(targetSecret[ObservatoriumSecretKeyGateway])
 	}
 
 	if index.Tenant == "" {
-		index.Tenant = string(targetSecret.Data[ObservatoriumSecretKeyTenant])
+		index.Tenant = string(targetSecret[ObservatoriumSecretKeyTenant])
 	}
 
 	switch index.AuthType {
@@ -202,20 +203,37 @@ func assignFromSecret(ctx context.Context, c client.Client, cr *v1.Observability
 
 		// Dex configuration is part of the config secret created by the KAS Fleet Manager
 		index.DexConfig = new(v1.DexConfig)
-		index.DexConfig.Url = string(targetSecret.Data[ObservatoriumSecretKeyDexUrl])
-		index.DexConfig.Secret = string(targetSecret.Data[ObservatoriumSecretKeyDexSecret])
-		index.DexConfig.Password = string(targetSecret.Data[ObservatoriumSecretKeyDexPassword])
-		index.DexConfig.Username = string(targetSecret.Data[ObservatoriumSecretKeyDexUsername])
+		index.DexConfig.Url = string(targetSecret[ObservatoriumSecretKeyDexUrl])
+		index.DexConfig.Secret = string(targetSecret[ObservatoriumSecretKeyDexSecret])
+		index.DexConfig.Password = string(targetSecret[ObservatoriumSecretKeyDexPassword])
+		index.DexConfig.Username = string(targetSecret[ObservatoriumSecretKeyDexUsername])
 	case v1.AuthTypeRedhat:
 		// RedHat SSO credentials are required to be part of the config secret created by the
 		// KAS Fleet Manager. This auth type is not supported in the config repository.
 		index.RedhatSsoConfig = new(v1.RedhatSsoConfig)
-		index.RedhatSsoConfig.Url = string(targetSecret.Data[ObservatoriumSecretKeyRedhatSsoUrl])
-		index.RedhatSsoConfig.Realm = string(targetSecret.Data[ObservatoriumSecretKeyRedhatSsoRealm])
-		index.RedhatSsoConfig.MetricsSecret = string(targetSecret.Data[ObservatoriumSecretKeyMetricsSecret])
-		index.RedhatSsoConfig.MetricsClient = string(targetSecret.Data[ObservatoriumSecretKeyMetricsClient])
-		index.RedhatSsoConfig.LogsSecret = string(targetSecret.Data[ObservatoriumSecretKeyLogsSecret])
-		index.RedhatSsoConfig.LogsClient = string(targetSecret.Data[ObservatoriumSecretKeyLogsClient])
+		index.RedhatSsoConfig.Url = string(targetSecret[ObservatoriumSecretKeyRedhatSsoUrl])
+		index.RedhatSsoConfig.Realm = string(targetSecret[ObservatoriumSecretKeyRedhatSsoRealm])
+		index.RedhatSsoConfig.MetricsSecret = string(targetSecret[ObservatoriumSecretKeyMetricsSecret])
+		index.RedhatSsoConfig.MetricsClient = string(targetSecret[ObservatoriumSecretKeyMetricsClient])
+		index.RedhatSsoConfig.LogsSecret = string(targetSecret[ObservatoriumSecretKeyLogsSecret])
+		index.RedhatSsoConfig.LogsClient = string(targetSecret[ObservatoriumSecretKeyLogsClient])
+	case v1.AuthTypeSigv4:
+		// Signing settings from the repository take precedence, credentials are never part of the secret
+		if index.Sigv4Config != nil {
+			return nil
+		}
+
+		index.Sigv4Config = new(v1.Sigv4Config)
+		index.Sigv4Config.Region = string(targetSecret[ObservatoriumSecretKeySigv4Region])
+		index.Sigv4Config.RoleArn = string(targetSecret[ObservatoriumSecretKeySigv4RoleArn])
+	case v1.AuthTypeServiceAccount:
+		// Token settings from the repository take precedence, the token itself is projected by the kubelet
+		if index.ServiceAccountTokenConfig != nil {
+			return nil
+		}
+
+		index.ServiceAccountTokenConfig = new(v1.ServiceAccountTokenConfig)
+		index.ServiceAccountTokenConfig.Audience = string(targetSecret[ObservatoriumSecretKeyServiceAccountAudience])
 	default:
 		return errors2.New(fmt.Sprintf("unknown auth type %v", index.AuthType))
 	}
@@ -223,7 +241,7 @@ func assignFromSecret(ctx context.Context, c client.Client, cr *v1.Observability
 	return nil
 }
 
-func ReconcileObservatoria(log logr.Logger, ctx context.Context, c client.Client, cr *v1.Observability, index *v1.RepositoryIndex) error {
+func ReconcileObservatoria(log logr.Logger, ctx context.Context, c client.Client, httpClient *http.Client, cr *v1.Observability, index *v1.RepositoryIndex) error {
 	if index == nil || index.Config == nil || index.Config.Observatoria == nil {
 		return nil
 	}
@@ -238,7 +256,7 @@ func ReconcileObservatoria(log logr.Logger, ctx context.Context, c client.Client
 		if observatorium.SecretName != "" {
 			err := assignFromSecret(ctx, c, cr, &observatorium)
 			if err != nil {
-				log.Error(err, fmt.Sprintf("error finding observatorium secret %v", observatorium.SecretName))
+				log.Error(err, "error finding observatorium secret", "observatorium", observatorium.Id, "secret", observatorium.SecretName)
 				return err
 			}
 		}
@@ -251,33 +269,27 @@ func ReconcileObservatoria(log logr.Logger, ctx context.Context, c client.Client
 			}
 
 			// Get credential secret
-			secret := &v12.Secret{}
-			selector := client.ObjectKey{
-				Namespace: namespace,
-				Name:      observatorium.DexConfig.CredentialSecretName,
-			}
-
-			err := c.Get(ctx, selector, secret)
+			secret, err := credentials.GetCredentialProvider(cr, c).GetCredentials(ctx, namespace, observatorium.DexConfig.CredentialSecretName)
 			if err != nil {
 				return err
 			}
 
-			if secret.Data["username"] != nil {
-				observatorium.DexConfig.Username = string(secret.Data["username"])
+			if secret["username"] != nil {
+				observatorium.DexConfig.Username = string(secret["username"])
 			} else {
-				observatorium.DexConfig.Username = string(secret.Data["dexUsername"])
+				observatorium.DexConfig.Username = string(secret["dexUsername"])
 			}
 
-			if secret.Data["password"] != nil {
-				observatorium.DexConfig.Password = string(secret.Data["password"])
+			if secret["password"] != nil {
+				observatorium.DexConfig.Password = string(secret["password"])
 			} else {
-				observatorium.DexConfig.Password = string(secret.Data["dexPassword"])
+				observatorium.DexConfig.Password = string(secret["dexPassword"])
 			}
 
-			if secret.Data["secret"] != nil {
-				observatorium.DexConfig.Secret = string(secret.Data["secret"])
+			if secret["secret"] != nil {
+				observatorium.DexConfig.Secret = string(secret["secret"])
 			} else {
-				observatorium.DexConfig.Secret = string(secret.Data["dexSecret"])
+				observatorium.DexConfig.Secret = string(secret["dexSecret"])
 			}
 		}
 
@@ -285,9 +297,9 @@ func ReconcileObservatoria(log logr.Logger, ctx context.Context, c client.Client
 		observatorium.DeepCopyInto(&copy)
 		transformed = append(transformed, copy)
 
-		// No token fetching required if we are using RedHat SSO. The token-refresher proxy
-		// is taking care of that for us
-		if observatorium.AuthType == v1.AuthTypeRedhat {
+		// No token fetching required if we are using RedHat SSO, sigv4 or service account tokens. The
+		// token-refresher proxy, Prometheus itself or the kubelet is taking care of that for us
+		if observatorium.AuthType != v1.AuthTypeDex {
 			continue
 		}
 
@@ -298,15 +310,15 @@ func ReconcileObservatoria(log logr.Logger, ctx context.Context, c client.Client
 
 		// No token yet?
 		if t == "" || token.AuthTokenExpires(lifetime) {
-			t, lifetime, err := refreshToken(ctx, c, &observatorium, cr, t)
+			t, lifetime, err := refreshToken(ctx, c, httpClient, &observatorium, cr, t)
 			if err != nil {
-				log.Error(err, fmt.Sprintf("error fetching token for observatorium %v", observatorium.Id))
+				log.Error(err, "error fetching token for observatorium", "observatorium", observatorium.Id)
 				continue
 			}
 
 			err = saveToken(ctx, c, &observatorium, cr, t, lifetime)
 			if err != nil {
-				log.Error(err, fmt.Sprintf("error storing token for observatorium %v", observatorium.Id))
+				log.Error(err, "error storing token for observatorium", "observatorium", observatorium.Id)
 				continue
 			}
 		}
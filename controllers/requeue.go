@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"math/rand"
+	"time"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Consecutive reconciles in which a stage failed, kept in memory so that the backoff doesn't add
+// status updates. A restarted operator starts again at the initial delay.
+type stageFailures struct {
+	stage    apiv1.ObservabilityStageName
+	failures int
+}
+
+// Delay before the next reconcile, from the requeue policy of the CR. The failures of a stage are
+// reset once it succeeds or another stage is reached.
+func (r *ObservabilityReconciler) getRequeueDelay(cr *apiv1.Observability, finished bool, status *apiv1.ObservabilityStatus) time.Duration {
+	if r.stageFailures == nil {
+		r.stageFailures = map[types.NamespacedName]stageFailures{}
+		r.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	key := client.ObjectKeyFromObject(cr)
+
+	var delay time.Duration
+	switch {
+	case finished:
+		delete(r.stageFailures, key)
+		delay = model.GetRequeueInterval(cr)
+	case status.StageStatus == apiv1.ResultFailed:
+		previous := r.stageFailures[key]
+		if previous.stage != status.Stage {
+			previous = stageFailures{stage: status.Stage}
+		}
+		previous.failures++
+		r.stageFailures[key] = previous
+		delay = model.GetStageRequeueDelay(cr, status.Stage, previous.failures)
+	default:
+		delete(r.stageFailures, key)
+		delay = model.GetStageRequeueDelay(cr, status.Stage, 0)
+	}
+	return model.GetJitteredRequeueDelay(cr, delay, r.random.Float64())
+}
@@ -0,0 +1,361 @@
+package synthetic
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"strings"
+	"time"
+
+	commonmodel "github.com/prometheus/common/model"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+const (
+	DefaultInterval             = time.Minute
+	defaultTimeout              = 10 * time.Second
+	defaultCertificateThreshold = 14 * 24 * time.Hour
+	// Limit of the response bodies that are matched, larger bodies are truncated
+	maxBodySize = 1 << 20
+)
+
+var captureReference = regexp.MustCompile(`\$\{([a-zA-Z0-9_]+)\}`)
+
+type StepResult struct {
+	Name     string
+	Success  bool
+	Duration time.Duration
+}
+
+type Result struct {
+	Success  bool
+	Message  string
+	Duration time.Duration
+	// Steps of HTTP checks
+	Steps []StepResult
+	// Expiry of the leaf certificate of certificate checks
+	CertificateExpiry time.Time
+}
+
+func parseDuration(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := commonmodel.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(duration), nil
+}
+
+func GetInterval(spec *v1.SyntheticCheckSpec) (time.Duration, error) {
+	return parseDuration(spec.Interval, DefaultInterval)
+}
+
+// Exactly one check has to be set and the durations have to parse
+func Validate(spec *v1.SyntheticCheckSpec) error {
+	set := 0
+	for _, check := range []bool{spec.HTTP != nil, spec.DNS != nil, spec.TCP != nil, spec.Certificate != nil} {
+		if check {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one of http, dns, tcp or certificate has to be set")
+	}
+	if _, err := GetInterval(spec); err != nil {
+		return fmt.Errorf("invalid interval %v", spec.Interval)
+	}
+	if _, err := parseDuration(spec.Timeout, defaultTimeout); err != nil {
+		return fmt.Errorf("invalid timeout %v", spec.Timeout)
+	}
+	if spec.HTTP != nil {
+		if len(spec.HTTP.Steps) == 0 {
+			return errors.New("http check without steps")
+		}
+		for _, step := range spec.HTTP.Steps {
+			if step.BodyRegex != "" {
+				if _, err := regexp.Compile(step.BodyRegex); err != nil {
+					return fmt.Errorf("invalid body regex of step %v", step.Name)
+				}
+			}
+			for name, expr := range step.Capture {
+				if re, err := regexp.Compile(expr); err != nil || re.NumSubexp() < 1 {
+					return fmt.Errorf("capture %v of step %v needs a regex with a group", name, step.Name)
+				}
+			}
+		}
+	}
+	if spec.Certificate != nil {
+		if _, err := parseDuration(spec.Certificate.ExpiryThreshold, 0); err != nil {
+			return fmt.Errorf("invalid expiry threshold %v", spec.Certificate.ExpiryThreshold)
+		}
+	}
+	return nil
+}
+
+// Runs the check once within its timeout, the spec is expected to be valid
+func Run(ctx context.Context, spec *v1.SyntheticCheckSpec) Result {
+	timeout, _ := parseDuration(spec.Timeout, defaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := Result{}
+	var err error
+	switch {
+	case spec.HTTP != nil:
+		result.Steps, err = runHTTP(ctx, spec.HTTP)
+	case spec.DNS != nil:
+		err = runDNS(ctx, spec.DNS)
+	case spec.TCP != nil:
+		err = runTCP(ctx, spec.TCP)
+	case spec.Certificate != nil:
+		result.CertificateExpiry, err = runCertificate(ctx, spec.Certificate)
+	}
+	result.Duration = time.Since(start)
+	result.Success = err == nil
+	if err != nil {
+		result.Message = err.Error()
+	}
+	return result
+}
+
+func expandCaptures(value string, captures map[string]string) string {
+	return captureReference.ReplaceAllStringFunc(value, func(reference string) string {
+		name := captureReference.FindStringSubmatch(reference)[1]
+		if captured, ok := captures[name]; ok {
+			return captured
+		}
+		return reference
+	})
+}
+
+func isExpectedStatus(step *v1.HTTPCheckStep, status int) bool {
+	if len(step.ExpectedStatusCodes) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, expected := range step.ExpectedStatusCodes {
+		if status == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func runHTTP(ctx context.Context, check *v1.HTTPCheck) ([]StepResult, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	transport := newTransport()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: check.InsecureSkipVerify,
+	}
+	client := &http.Client{
+		Jar:       jar,
+		Transport: transport,
+	}
+	defer transport.CloseIdleConnections()
+
+	var results []StepResult
+	captures := map[string]string{}
+	for i := range check.Steps {
+		step := &check.Steps[i]
+		start := time.Now()
+		err := runHTTPStep(ctx, client, step, captures)
+		results = append(results, StepResult{
+			Name:     step.Name,
+			Success:  err == nil,
+			Duration: time.Since(start),
+		})
+		if err != nil {
+			return results, fmt.Errorf("step %v: %w", step.Name, err)
+		}
+	}
+	return results, nil
+}
+
+func runHTTPStep(ctx context.Context, client *http.Client, step *v1.HTTPCheckStep, captures map[string]string) error {
+	method := step.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if step.Body != "" {
+		body = strings.NewReader(expandCaptures(step.Body, captures))
+	}
+	request, err := http.NewRequestWithContext(ctx, method, expandCaptures(step.URL, captures), body)
+	if err != nil {
+		return err
+	}
+	for name, value := range step.Headers {
+		request.Header.Set(name, expandCaptures(value, captures))
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if !isExpectedStatus(step, response.StatusCode) {
+		return fmt.Errorf("unexpected status %v", response.StatusCode)
+	}
+	if step.BodyRegex != "" && !regexp.MustCompile(step.BodyRegex).Match(content) {
+		return fmt.Errorf("body does not match %v", step.BodyRegex)
+	}
+	for name, expr := range step.Capture {
+		match := regexp.MustCompile(expr).FindSubmatch(content)
+		if match == nil {
+			return fmt.Errorf("nothing to capture for %v", name)
+		}
+		captures[name] = string(match[1])
+	}
+	return nil
+}
+
+func getResolver(check *v1.DNSCheck) *net.Resolver {
+	if check.Server == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return newDialer().DialContext(ctx, network, check.Server)
+		},
+	}
+}
+
+func lookup(ctx context.Context, check *v1.DNSCheck) ([]string, error) {
+	resolver := getResolver(check)
+	var answers []string
+	switch check.RecordType {
+	case "", "A", "AAAA":
+		network := "ip4"
+		if check.RecordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, check.Host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, check.Host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case "MX":
+		records, err := resolver.LookupMX(ctx, check.Host)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			answers = append(answers, record.Host)
+		}
+	case "NS":
+		records, err := resolver.LookupNS(ctx, check.Host)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			answers = append(answers, record.Host)
+		}
+	case "TXT":
+		return resolver.LookupTXT(ctx, check.Host)
+	default:
+		return nil, fmt.Errorf("unsupported record type %v", check.RecordType)
+	}
+	return answers, nil
+}
+
+func runDNS(ctx context.Context, check *v1.DNSCheck) error {
+	answers, err := lookup(ctx, check)
+	if err != nil {
+		return err
+	}
+	if len(answers) == 0 {
+		return fmt.Errorf("no records for %v", check.Host)
+	}
+	if len(check.ExpectedValues) == 0 {
+		return nil
+	}
+	for _, answer := range answers {
+		for _, expected := range check.ExpectedValues {
+			if strings.TrimSuffix(answer, ".") == strings.TrimSuffix(expected, ".") {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("none of the answers %v is expected", strings.Join(answers, ", "))
+}
+
+func runTCP(ctx context.Context, check *v1.TCPCheck) error {
+	dialer := newDialer()
+	conn, err := dialer.DialContext(ctx, "tcp", check.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if !check.TLS {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(check.Address)
+	if err != nil {
+		return err
+	}
+	return tls.Client(conn, &tls.Config{ServerName: host}).HandshakeContext(ctx)
+}
+
+// The chain is not verified, only the expiry of the leaf certificate matters
+func runCertificate(ctx context.Context, check *v1.CertificateCheck) (time.Time, error) {
+	serverName := check.ServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(check.Address)
+		if err != nil {
+			return time.Time{}, err
+		}
+		serverName = host
+	}
+
+	dialer := tls.Dialer{
+		NetDialer: newDialer(),
+		Config: &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", check.Address)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return time.Time{}, errors.New("no certificate presented")
+	}
+	expiry := certificates[0].NotAfter
+
+	threshold, _ := parseDuration(check.ExpiryThreshold, defaultCertificateThreshold)
+	if time.Until(expiry) < threshold {
+		return expiry, fmt.Errorf("certificate expires at %v", expiry.Format(time.RFC3339))
+	}
+	return expiry, nil
+}
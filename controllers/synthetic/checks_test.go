@@ -0,0 +1,154 @@
+package synthetic
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+// The test servers listen on the loopback interface
+func init() {
+	permittedNetworks = []*net.IPNet{{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}}
+}
+
+func TestChecks_HTTPSequence(t *testing.T) {
+	RegisterTestingT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+			_, _ = w.Write([]byte(`{"token": "secret"}`))
+		case "/profile":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != "abc" || r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"user": "synthetic"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	spec := &v1.SyntheticCheckSpec{
+		HTTP: &v1.HTTPCheck{
+			Steps: []v1.HTTPCheckStep{
+				{
+					Name:    "login",
+					URL:     server.URL + "/login",
+					Method:  http.MethodPost,
+					Capture: map[string]string{"token": `"token":\s*"([^"]+)"`},
+				},
+				{
+					Name:      "profile",
+					URL:       server.URL + "/profile",
+					Headers:   map[string]string{"Authorization": "Bearer ${token}"},
+					BodyRegex: `"user":\s*"synthetic"`,
+				},
+			},
+		},
+	}
+	Expect(Validate(spec)).To(Succeed())
+
+	result := Run(context.Background(), spec)
+	Expect(result.Message).To(BeEmpty())
+	Expect(result.Success).To(BeTrue())
+	Expect(result.Steps).To(HaveLen(2))
+
+	// The sequence ends with the first failed step
+	spec.HTTP.Steps[0].URL = server.URL + "/missing"
+	spec.HTTP.Steps[0].ExpectedStatusCodes = []int{http.StatusOK}
+	result = Run(context.Background(), spec)
+	Expect(result.Success).To(BeFalse())
+	Expect(result.Message).To(ContainSubstring("step login"))
+	Expect(result.Steps).To(HaveLen(1))
+}
+
+func TestChecks_TCPAndCertificate(t *testing.T) {
+	RegisterTestingT(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	result := Run(context.Background(), &v1.SyntheticCheckSpec{TCP: &v1.TCPCheck{Address: address}})
+	Expect(result.Success).To(BeTrue())
+
+	// The test certificate is valid until 2084
+	spec := &v1.SyntheticCheckSpec{Certificate: &v1.CertificateCheck{Address: address, ServerName: "example.com"}}
+	result = Run(context.Background(), spec)
+	Expect(result.Success).To(BeTrue())
+	Expect(result.CertificateExpiry.Year()).To(BeNumerically(">", 2080))
+
+	spec.Certificate.ExpiryThreshold = "100y"
+	result = Run(context.Background(), spec)
+	Expect(result.Success).To(BeFalse())
+	Expect(result.CertificateExpiry.IsZero()).To(BeFalse())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	closed := listener.Addr().String()
+	Expect(listener.Close()).To(Succeed())
+	result = Run(context.Background(), &v1.SyntheticCheckSpec{TCP: &v1.TCPCheck{Address: closed}})
+	Expect(result.Success).To(BeFalse())
+}
+
+func TestChecks_Validate(t *testing.T) {
+	RegisterTestingT(t)
+	Expect(Validate(&v1.SyntheticCheckSpec{})).ToNot(Succeed())
+	Expect(Validate(&v1.SyntheticCheckSpec{
+		TCP: &v1.TCPCheck{Address: "localhost:80"},
+		DNS: &v1.DNSCheck{Host: "localhost"},
+	})).ToNot(Succeed())
+	Expect(Validate(&v1.SyntheticCheckSpec{TCP: &v1.TCPCheck{Address: "localhost:80"}, Interval: "often"})).ToNot(Succeed())
+	Expect(Validate(&v1.SyntheticCheckSpec{HTTP: &v1.HTTPCheck{Steps: []v1.HTTPCheckStep{
+		{Name: "no-group", URL: "http://localhost", Capture: map[string]string{"token": "token"}},
+	}}})).ToNot(Succeed())
+}
+
+func TestChecks_InternalTargets(t *testing.T) {
+	RegisterTestingT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	permitted := permittedNetworks
+	permittedNetworks = nil
+	defer func() { permittedNetworks = permitted }()
+
+	result := Run(context.Background(), &v1.SyntheticCheckSpec{HTTP: &v1.HTTPCheck{Steps: []v1.HTTPCheckStep{{Name: "local", URL: server.URL}}}})
+	Expect(result.Success).To(BeFalse())
+	Expect(result.Message).To(ContainSubstring("not a public address"))
+
+	result = Run(context.Background(), &v1.SyntheticCheckSpec{TCP: &v1.TCPCheck{Address: address}})
+	Expect(result.Success).To(BeFalse())
+	Expect(result.Message).To(ContainSubstring("not a public address"))
+
+	result = Run(context.Background(), &v1.SyntheticCheckSpec{Certificate: &v1.CertificateCheck{Address: address}})
+	Expect(result.Success).To(BeFalse())
+	Expect(result.Message).To(ContainSubstring("not a public address"))
+
+	result = Run(context.Background(), &v1.SyntheticCheckSpec{DNS: &v1.DNSCheck{Host: "example.com", Server: "10.0.0.53:53"}})
+	Expect(result.Success).To(BeFalse())
+	Expect(result.Message).To(ContainSubstring("not a public address"))
+
+	for _, ip := range []string{"169.254.169.254", "10.0.0.1", "172.30.0.1", "192.168.1.1", "100.64.0.1", "127.0.0.1", "::1", "fe80::1", "fd00::1"} {
+		Expect(isBlockedIP(net.ParseIP(ip))).To(BeTrue(), ip)
+	}
+	Expect(isBlockedIP(net.ParseIP("8.8.8.8"))).To(BeFalse())
+	Expect(isBlockedIP(net.ParseIP("2001:4860:4860::8888"))).To(BeFalse())
+}
+
+func TestChecks_GetProxyAddress(t *testing.T) {
+	RegisterTestingT(t)
+	Expect(getProxyAddress(&url.URL{Scheme: "http", Host: "proxy.example.com:3128"})).To(Equal("proxy.example.com:3128"))
+	Expect(getProxyAddress(&url.URL{Scheme: "http", Host: "proxy.example.com"})).To(Equal("proxy.example.com:80"))
+	Expect(getProxyAddress(&url.URL{Scheme: "https", Host: "proxy.example.com"})).To(Equal("proxy.example.com:443"))
+}
@@ -0,0 +1,102 @@
+package synthetic
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+)
+
+// Checks are created by the users of a namespace and run with the network access of the operator, so
+// they only reach public addresses. Otherwise a check could read the cloud metadata service, the API
+// server or the services of other namespaces.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Networks that checks may reach in addition, the local test servers
+var permittedNetworks []*net.IPNet
+
+// Loopback, link-local, private and shared addresses are internal to the cluster or its nodes
+func isBlockedIP(ip net.IP) bool {
+	for _, network := range permittedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() || sharedAddressSpace.Contains(ip)
+}
+
+// Checked after the name is resolved, so that names that resolve to internal addresses are rejected too
+func dialControl(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isBlockedIP(ip) {
+		return fmt.Errorf("target %v is not a public address", host)
+	}
+	return nil
+}
+
+func newDialer() *net.Dialer {
+	return &net.Dialer{Control: dialControl}
+}
+
+// Requests through a proxy are resolved by the proxy, their host is checked before
+func checkHost(ctx context.Context, host string) error {
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if isBlockedIP(address.IP) {
+			return fmt.Errorf("target %v resolves to %v, which is not a public address", host, address.IP)
+		}
+	}
+	return nil
+}
+
+// Transport of the HTTP checks. The proxy of the environment is dialed as is, it is usually internal itself.
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := newDialer()
+	var proxies sync.Map
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxy, err := http.ProxyFromEnvironment(req)
+		if err != nil || proxy == nil {
+			return proxy, err
+		}
+		err = checkHost(req.Context(), req.URL.Hostname())
+		if err != nil {
+			return nil, err
+		}
+		proxies.Store(getProxyAddress(proxy), true)
+		return proxy, nil
+	}
+	transport.DialContext = func(ctx context.Context, network string, address string) (net.Conn, error) {
+		if _, ok := proxies.Load(address); ok {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+	return transport
+}
+
+// Address that the transport dials for the proxy
+func getProxyAddress(proxy *url.URL) string {
+	if proxy.Port() != "" {
+		return proxy.Host
+	}
+	port := "80"
+	switch proxy.Scheme {
+	case "https":
+		port = "443"
+	case "socks5":
+		port = "1080"
+	}
+	return net.JoinHostPort(proxy.Hostname(), port)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/synthetic"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const syntheticCheckConcurrency = 4
+
+// SyntheticCheckReconciler runs the synthetic checks at their interval
+type SyntheticCheckReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=observability.redhat.com,resources=syntheticchecks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=observability.redhat.com,resources=syntheticchecks/status,verbs=get;update;patch
+
+func (r *SyntheticCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("syntheticcheck", req.NamespacedName)
+
+	check := &apiv1.SyntheticCheck{}
+	err := r.Get(ctx, req.NamespacedName, check)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteSyntheticCheckMetrics(req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Invalid checks are not retried until their spec changes
+	err = synthetic.Validate(&check.Spec)
+	if err != nil {
+		metrics.DeleteSyntheticCheckMetrics(check.Namespace, check.Name)
+		check.Status.Success = false
+		check.Status.Message = err.Error()
+		check.Status.ObservedGeneration = check.Generation
+		return ctrl.Result{}, r.Status().Update(ctx, check)
+	}
+	interval, _ := synthetic.GetInterval(&check.Spec)
+
+	// Requeued before the interval passed, e.g. after a restart of the operator
+	if check.Status.LastRun != nil && check.Status.ObservedGeneration == check.Generation {
+		if next := time.Until(check.Status.LastRun.Add(interval)); next > 0 {
+			return ctrl.Result{RequeueAfter: next}, nil
+		}
+	}
+
+	result := synthetic.Run(ctx, &check.Spec)
+	metrics.SetSyntheticCheckMetrics(check, result)
+	if !result.Success {
+		log.Info("synthetic check failed", "reason", result.Message)
+	}
+
+	check.Status.LastRun = &metav1.Time{Time: time.Now()}
+	check.Status.Success = result.Success
+	check.Status.Message = result.Message
+	check.Status.ObservedGeneration = check.Generation
+	err = r.Status().Update(ctx, check)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// Status updates don't trigger a run, only spec changes and the requeue at the interval. Slow
+// checks don't delay the others up to the number of concurrent runs.
+func (r *SyntheticCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.SyntheticCheck{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: syntheticCheckConcurrency}).
+		Complete(r)
+}
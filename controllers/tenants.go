@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// +kubebuilder:rbac:groups=observability.redhat.com,resources=observabilitytenants,verbs=get;list;watch
+
+// Tenants of all namespaces sorted by namespace and name. Other namespaces than the one of a tenant
+// have to opt in with the tenant label set to the namespace of the tenant, anyone who can create a
+// tenant could claim any namespace otherwise. Namespaces that the selector of a tenant can't be
+// resolved for, e.g. without the permission to list them, are skipped, as are invalid allowlist patterns.
+func (r *ObservabilityReconciler) getTenants(ctx context.Context) ([]apiv1.TenantStatus, error) {
+	list := &apiv1.ObservabilityTenantList{}
+	err := r.List(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []apiv1.TenantStatus
+	for _, tenant := range list.Items {
+		log := r.Log.WithValues("namespace", tenant.Namespace, "name", tenant.Name)
+		namespaces := map[string]bool{tenant.Namespace: true}
+		optedIn := map[string]bool{}
+		if len(tenant.Spec.Namespaces) > 0 || tenant.Spec.NamespaceSelector != nil {
+			selected, err := r.getSelectedNamespaces(ctx, &metav1.LabelSelector{
+				MatchLabels: map[string]string{model.TenantLabel: tenant.Namespace},
+			})
+			if err != nil {
+				log.Error(err, "error resolving the namespaces that opted in to tenant")
+			}
+			for _, namespace := range selected {
+				optedIn[namespace] = true
+			}
+		}
+
+		requested := append([]string{}, tenant.Spec.Namespaces...)
+		if tenant.Spec.NamespaceSelector != nil {
+			selected, err := r.getSelectedNamespaces(ctx, tenant.Spec.NamespaceSelector)
+			if err != nil {
+				log.Error(err, "error resolving the namespaces of tenant")
+			}
+			requested = append(requested, selected...)
+		}
+		for _, namespace := range requested {
+			if namespace == tenant.Namespace {
+				continue
+			}
+			if !optedIn[namespace] {
+				log.Info("skipped namespace of tenant without the tenant label", "targetNamespace", namespace)
+				continue
+			}
+			namespaces[namespace] = true
+		}
+
+		status := apiv1.TenantStatus{
+			Name:       tenant.Name,
+			Namespace:  tenant.Namespace,
+			Dashboards: tenant.Spec.Dashboards,
+		}
+		for _, pattern := range tenant.Spec.RemoteWriteAllowlist {
+			err := model.ValidateTenantAllowlistPattern(pattern)
+			if err != nil {
+				log.Error(err, "skipped remote write allowlist pattern of tenant")
+				continue
+			}
+			status.RemoteWriteAllowlist = append(status.RemoteWriteAllowlist, pattern)
+		}
+		for namespace := range namespaces {
+			status.Namespaces = append(status.Namespaces, namespace)
+		}
+		sort.Strings(status.Namespaces)
+		result = append(result, status)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (r *ObservabilityReconciler) getSelectedNamespaces(ctx context.Context, selector *metav1.LabelSelector) ([]string, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	list := &v1.NamespaceList{}
+	err = r.List(ctx, list, &client.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, namespace := range list.Items {
+		result = append(result, namespace.Name)
+	}
+	return result, nil
+}
+
+// Changed tenants are aggregated by all Observability CRs
+func (r *ObservabilityReconciler) getTenantRequests(client.Object) []reconcile.Request {
+	return r.getObservabilityRequests("tenant change")
+}
+
+func (r *ObservabilityReconciler) getObservabilityRequests(reason string) []reconcile.Request {
+	list := &apiv1.ObservabilityList{}
+	err := r.List(context.Background(), list)
+	if err != nil {
+		r.Log.Error(err, fmt.Sprintf("error listing observability CRs for %v", reason))
+		return nil
+	}
+	var result []reconcile.Request
+	for _, obs := range list.Items {
+		result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&obs)})
+	}
+	return result
+}
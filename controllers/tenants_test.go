@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTenants_GetTenants(t *testing.T) {
+	RegisterTestingT(t)
+
+	scheme := runtime.NewScheme()
+	Expect(v1.AddToScheme(scheme)).To(Succeed())
+	Expect(apiv1.AddToScheme(scheme)).To(Succeed())
+	namespace := func(name string, labels map[string]string) *v1.Namespace {
+		return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	tenant := &apiv1.ObservabilityTenant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "payments"},
+		Spec: apiv1.ObservabilityTenantSpec{
+			Namespaces:           []string{"payments-staging", "kube-system"},
+			NamespaceSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+			RemoteWriteAllowlist: []string{"payments_.*", ".*"},
+		},
+	}
+	r := &ObservabilityReconciler{
+		Client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+			tenant,
+			namespace("payments", nil),
+			namespace("payments-staging", map[string]string{model.TenantLabel: "payments"}),
+			namespace("payments-batch", map[string]string{model.TenantLabel: "payments", "team": "payments"}),
+			namespace("kube-system", nil),
+			// Selected, but didn't opt in
+			namespace("billing", map[string]string{"team": "payments"}),
+		).Build(),
+		Log: logr.Discard(),
+	}
+
+	tenants, err := r.getTenants(context.TODO())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(tenants).To(HaveLen(1))
+	Expect(tenants[0].Namespaces).To(Equal([]string{"payments", "payments-batch", "payments-staging"}))
+	Expect(tenants[0].RemoteWriteAllowlist).To(Equal([]string{"payments_.*"}))
+}
@@ -2,11 +2,11 @@ package token
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	_ "github.com/redhat-developer/observability-operator/v4/api/v1"
 	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	"github.com/redhat-developer/observability-operator/v4/controllers/model"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -23,13 +23,22 @@ type AuthTokenFetcher interface {
 // Default empty token fetcher
 type NilTokenFetcher struct{}
 
-// Fetches auth tokens from dex
+// Fetches auth tokens from dex. The HTTP client of the configuration sync trusts the CA bundle of
+// the cluster and goes through the cluster-wide proxy. Without an HTTP client a default client is
+// used, that only honours FIPS mode.
 type DexTokenFetcher struct {
 	Client     client.Client
 	Context    context.Context
 	HttpClient *http.Client
 }
 
+// Fetches auth tokens for the metrics client from sso.redhat.com. Remote write goes through
+// the token refresher, these tokens are only used for the other Observatorium APIs. Without an
+// HTTP client a default client is used, that only honours FIPS mode.
+type RedhatSsoTokenFetcher struct {
+	HttpClient *http.Client
+}
+
 func AuthTokenExpires(expires int64) bool {
 	if expires > 0 {
 		// Refresh the token a little bit in advance
@@ -44,14 +53,14 @@ func AuthTokenExpires(expires int64) bool {
 }
 
 // Returns a token fetcher for the given auth type
-func GetTokenFetcher(config *v1.ObservatoriumIndex, ctx context.Context, client client.Client) AuthTokenFetcher {
+func GetTokenFetcher(config *v1.ObservatoriumIndex, ctx context.Context, client client.Client, httpClient *http.Client) AuthTokenFetcher {
 	if config == nil {
 		return NewNilTokenFetcher()
 	}
 
 	switch config.AuthType {
 	case v1.AuthTypeDex:
-		return NewDexTokenFetcher(ctx, client)
+		return NewDexTokenFetcher(ctx, client, httpClient)
 	default:
 		return NewNilTokenFetcher()
 	}
@@ -65,12 +74,7 @@ func (r *NilTokenFetcher) Fetch(*v1.Observability, *v1.ObservatoriumIndex, strin
 	return "", 0, nil
 }
 
-func NewDexTokenFetcher(ctx context.Context, client client.Client) AuthTokenFetcher {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	httpClient := &http.Client{Transport: tr}
-
+func NewDexTokenFetcher(ctx context.Context, client client.Client, httpClient *http.Client) AuthTokenFetcher {
 	return &DexTokenFetcher{
 		Client:     client,
 		Context:    ctx,
@@ -94,7 +98,16 @@ func (r *DexTokenFetcher) Fetch(cr *v1.Observability, config *v1.ObservatoriumIn
 		"scope":         {"openid email"},
 	}
 
-	resp, err := r.HttpClient.PostForm(tokenEndpoint, formData)
+	httpClient := r.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: model.GetTLSConfig(cr, nil),
+			},
+		}
+	}
+
+	resp, err := httpClient.PostForm(tokenEndpoint, formData)
 	if err != nil {
 		return oldToken, cr.Status.TokenExpires, err
 	}
@@ -121,3 +134,59 @@ func (r *DexTokenFetcher) Fetch(cr *v1.Observability, config *v1.ObservatoriumIn
 	expires := time.Now().Add(time.Second * time.Duration(dexResponse.ExpiresIn)).Unix()
 	return dexResponse.AccessToken, expires, nil
 }
+
+func NewRedhatSsoTokenFetcher() AuthTokenFetcher {
+	return &RedhatSsoTokenFetcher{}
+}
+
+func (r *RedhatSsoTokenFetcher) Fetch(cr *v1.Observability, config *v1.ObservatoriumIndex, oldToken string) (string, int64, error) {
+	// No config, no token
+	if config.RedhatSsoConfig == nil || !config.RedhatSsoConfig.HasMetrics() {
+		return oldToken, 0, nil
+	}
+
+	tokenEndpoint := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", config.RedhatSsoConfig.Url, config.RedhatSsoConfig.Realm)
+	formData := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {config.RedhatSsoConfig.MetricsClient},
+		"client_secret": {config.RedhatSsoConfig.MetricsSecret},
+	}
+
+	httpClient := r.HttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+		if cr.FIPSModeEnabled() {
+			httpClient = &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: model.GetTLSConfig(cr, nil),
+				},
+			}
+		}
+	}
+
+	resp, err := httpClient.PostForm(tokenEndpoint, formData)
+	if err != nil {
+		return oldToken, 0, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return oldToken, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return oldToken, 0, fmt.Errorf("unexpected response from token endpoint: %v", resp.Status)
+	}
+
+	ssoResponse := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+
+	err = json.Unmarshal(body, &ssoResponse)
+	if err != nil {
+		return oldToken, 0, err
+	}
+
+	expires := time.Now().Add(time.Second * time.Duration(ssoResponse.ExpiresIn)).Unix()
+	return ssoResponse.AccessToken, expires, nil
+}
@@ -0,0 +1,42 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+)
+
+func TestTokenFetcher_DexTokenFetcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.URL.Path).To(Equal("/dex/token"))
+		_, _ = w.Write([]byte(`{"id_token":"token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	fips := true
+	cr := &v1.Observability{}
+	cr.Spec.FIPSMode = &fips
+	config := &v1.ObservatoriumIndex{
+		Id:        "observatorium",
+		AuthType:  v1.AuthTypeDex,
+		Tenant:    "test",
+		DexConfig: &v1.DexConfig{Url: server.URL},
+	}
+
+	// The client of the sync trusts the CA of the server, in FIPS mode as well
+	fetcher := GetTokenFetcher(config, context.Background(), nil, server.Client())
+	token, _, err := fetcher.Fetch(cr, config, "")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(token).To(Equal("token"))
+
+	// The default client verifies the server in FIPS mode
+	fetcher = GetTokenFetcher(config, context.Background(), nil, nil)
+	_, _, err = fetcher.Fetch(cr, config, "old")
+	Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_End(t *testing.T) {
+	RegisterTestingT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(ServiceName)
+
+	_, span := tracer.Start(context.Background(), "success")
+	End(span, nil)
+	_, span = tracer.Start(context.Background(), "failure")
+	End(span, errors.New("fetch failed"))
+
+	spans := recorder.Ended()
+	Expect(spans).To(HaveLen(2))
+	Expect(spans[0].Status().Code).To(Equal(codes.Unset))
+	Expect(spans[1].Status().Code).To(Equal(codes.Error))
+	Expect(spans[1].Status().Description).To(Equal("fetch failed"))
+	Expect(spans[1].Events()).To(HaveLen(1))
+}
+
+func TestTracing_Setup(t *testing.T) {
+	RegisterTestingT(t)
+
+	// The exporter connects lazily, nothing is sent without spans
+	shutdown, err := Setup(context.Background(), Options{Endpoint: "localhost:4318", Insecure: true, SampleRatio: 1})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(shutdown(context.Background())).To(Succeed())
+}
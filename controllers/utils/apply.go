@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// Field manager of the server-side applies of the operator
+	FieldManager = "observability-operator"
+
+	// Field manager of the updates of earlier operator versions, named after the binary
+	legacyFieldManager = "manager"
+)
+
+// Apply the state set by mutate with server-side apply. Unlike with CreateOrUpdate the object is not
+// read first, mutate starts from the name, namespace and whatever else the caller set. Only the
+// fields set there are owned by the operator, changes of others to the remaining fields are kept.
+// Fields that another manager changed are not taken over, the conflict is returned instead. Fields
+// written by the updates of earlier operator versions are taken over, ForceOwnership takes over all.
+func Apply(ctx context.Context, client k8sclient.Client, obj k8sclient.Object, mutate controllerutil.MutateFn, opts ...k8sclient.PatchOption) (controllerutil.OperationResult, error) {
+	key := k8sclient.ObjectKeyFromObject(obj)
+	gvk, err := apiutil.GVKForObject(obj, client.Scheme())
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	existing := obj.DeepCopyObject().(k8sclient.Object)
+	err = client.Get(ctx, key, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return controllerutil.OperationResultNone, err
+	}
+	created := errors.IsNotFound(err)
+
+	if err := mutate(); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	if k8sclient.ObjectKeyFromObject(obj) != key {
+		return controllerutil.OperationResultNone, fmt.Errorf("mutate must not change the name or namespace of %v %v", gvk.Kind, key)
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	opts = append([]k8sclient.PatchOption{k8sclient.FieldOwner(FieldManager)}, opts...)
+	err = client.Patch(ctx, obj, k8sclient.Apply, opts...)
+	if isLegacyConflict(err) {
+		err = client.Patch(ctx, obj, k8sclient.Apply, append(opts, k8sclient.ForceOwnership)...)
+	}
+	if errors.IsConflict(err) {
+		return controllerutil.OperationResultNone, fmt.Errorf("conflicting changes to %v %v: %w", gvk.Kind, key, err)
+	}
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	switch {
+	case created:
+		return controllerutil.OperationResultCreated, nil
+	case obj.GetResourceVersion() != existing.GetResourceVersion():
+		return controllerutil.OperationResultUpdated, nil
+	default:
+		return controllerutil.OperationResultNone, nil
+	}
+}
+
+// True for the field manager of the applies and the one of the updates of the operator
+func IsOperatorFieldManager(manager string) bool {
+	return manager == FieldManager || manager == legacyFieldManager
+}
+
+// Conflicts that are only with the updates of earlier operator versions
+func isLegacyConflict(err error) bool {
+	status, ok := err.(errors.APIStatus)
+	if !ok || !errors.IsConflict(err) {
+		return false
+	}
+	details := status.Status().Details
+	if details == nil || len(details.Causes) == 0 {
+		return false
+	}
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			return false
+		}
+		if !strings.Contains(cause.Message, fmt.Sprintf("conflict with %q", legacyFieldManager)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply the resource only if it doesn't exist yet, for generated content such as session secrets that
+// must not change once created
+func ApplyIfMissing(ctx context.Context, client k8sclient.Client, obj k8sclient.Object, mutate controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+	err := client.Get(ctx, k8sclient.ObjectKeyFromObject(obj), obj.DeepCopyObject().(k8sclient.Object))
+	if err == nil {
+		return controllerutil.OperationResultNone, nil
+	}
+	if !errors.IsNotFound(err) {
+		return controllerutil.OperationResultNone, err
+	}
+	return Apply(ctx, client, obj, mutate)
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestApply_Apply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.SchemeBuilder.AddToScheme(scheme)
+	client := testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())
+
+	apply := func(data map[string]string) controllerutil.OperationResult {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: testNamespace}}
+		result, err := Apply(context.TODO(), client, configMap, func() error {
+			configMap.Data = data
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	RegisterTestingT(t)
+	Expect(apply(map[string]string{"key": "a"})).To(Equal(controllerutil.OperationResultCreated))
+	Expect(apply(map[string]string{"key": "b"})).To(Equal(controllerutil.OperationResultUpdated))
+
+	current := &corev1.ConfigMap{}
+	err := client.Get(context.TODO(), k8sclient.ObjectKey{Namespace: testNamespace, Name: "config"}, current)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(current.Data).To(Equal(map[string]string{"key": "b"}))
+
+	// The name can't be changed by mutate
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: testNamespace}}
+	_, err = Apply(context.TODO(), client, configMap, func() error {
+		configMap.Name = "other"
+		return nil
+	})
+	Expect(err).To(HaveOccurred())
+}
+
+func TestApply_ApplyIfMissing(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.SchemeBuilder.AddToScheme(scheme)
+	client := testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).Build())
+
+	apply := func(value string) {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "session", Namespace: testNamespace}}
+		_, err := ApplyIfMissing(context.TODO(), client, secret, func() error {
+			secret.Data = map[string][]byte{"session_secret": []byte(value)}
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	RegisterTestingT(t)
+	apply("a")
+	apply("b")
+
+	// Generated once, never replaced
+	current := &corev1.Secret{}
+	err := client.Get(context.TODO(), k8sclient.ObjectKey{Namespace: testNamespace, Name: "session"}, current)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(current.Data["session_secret"]).To(Equal([]byte("a")))
+}
+
+func TestApply_IsLegacyConflict(t *testing.T) {
+	getConflict := func(messages ...string) error {
+		err := errors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "config", nil)
+		for _, message := range messages {
+			err.ErrStatus.Details.Causes = append(err.ErrStatus.Details.Causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: message,
+				Field:   ".data.key",
+			})
+		}
+		return err
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "conflicts with earlier operator versions are taken over",
+			err:  getConflict(`conflict with "manager" using v1`),
+			want: true,
+		},
+		{
+			name: "conflicts with other managers are returned",
+			err:  getConflict(`conflict with "manager" using v1`, `conflict with "kubectl-edit" using v1`),
+			want: false,
+		},
+		{
+			name: "conflicts without causes are returned",
+			err:  getConflict(),
+			want: false,
+		},
+		{
+			name: "other errors are returned",
+			err:  errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "config"),
+			want: false,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Expect(isLegacyConflict(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	DryRunOperationCreate = "create"
+	DryRunOperationUpdate = "update"
+	DryRunOperationPatch  = "patch"
+	DryRunOperationDelete = "delete"
+)
+
+// A single write that would have been performed against the cluster
+type DryRunChange struct {
+	Operation string `json:"operation"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// JSON merge patch between the current and the desired object. Never set for secrets.
+	Diff string `json:"diff,omitempty"`
+}
+
+// DryRunClient passes reads through to the wrapped client but only records writes.
+// Used together with controllerutil.CreateOrUpdate or Apply this yields exactly the set of
+// changes that a regular reconcile would apply. Safe for concurrent use, e.g. by the resource
+// sync workers.
+type DryRunClient struct {
+	k8sclient.Client
+	mu      sync.Mutex
+	changes []DryRunChange
+}
+
+func NewDryRunClient(client k8sclient.Client) *DryRunClient {
+	return &DryRunClient{
+		Client: client,
+	}
+}
+
+func (c *DryRunClient) Create(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.CreateOption) error {
+	c.record(DryRunOperationCreate, obj, "")
+	return nil
+}
+
+func (c *DryRunClient) Update(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.UpdateOption) error {
+	diff := ""
+	if !isSecret(obj) {
+		current := obj.DeepCopyObject().(k8sclient.Object)
+		err := c.Client.Get(ctx, k8sclient.ObjectKeyFromObject(obj), current)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		data, err := k8sclient.MergeFrom(current).Data(obj)
+		if err != nil {
+			return err
+		}
+		diff = string(data)
+	}
+	c.record(DryRunOperationUpdate, obj, diff)
+	return nil
+}
+
+func (c *DryRunClient) Patch(ctx context.Context, obj k8sclient.Object, patch k8sclient.Patch, opts ...k8sclient.PatchOption) error {
+	if patch.Type() == types.ApplyPatchType {
+		return c.apply(ctx, obj, patch, opts...)
+	}
+
+	diff := ""
+	if !isSecret(obj) {
+		data, err := patch.Data(obj)
+		if err != nil {
+			return err
+		}
+		diff = string(data)
+	}
+	c.record(DryRunOperationPatch, obj, diff)
+	return nil
+}
+
+// Applies are sent as server-side dry runs, only the fields the apply changes are recorded. Applies
+// that change nothing are left out like unchanged objects of CreateOrUpdate.
+func (c *DryRunClient) apply(ctx context.Context, obj k8sclient.Object, patch k8sclient.Patch, opts ...k8sclient.PatchOption) error {
+	current := obj.DeepCopyObject().(k8sclient.Object)
+	err := c.Client.Get(ctx, k8sclient.ObjectKeyFromObject(obj), current)
+	if errors.IsNotFound(err) {
+		c.record(DryRunOperationCreate, obj, "")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	applied := obj.DeepCopyObject().(k8sclient.Object)
+	err = c.Client.Patch(ctx, applied, patch, append(opts, k8sclient.DryRunAll)...)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range []k8sclient.Object{current, applied} {
+		o.SetManagedFields(nil)
+		o.SetResourceVersion("")
+		o.SetGeneration(0)
+	}
+	data, err := k8sclient.MergeFrom(current).Data(applied)
+	if err != nil {
+		return err
+	}
+	if string(data) == "{}" {
+		return nil
+	}
+
+	diff := ""
+	if !isSecret(obj) {
+		diff = string(data)
+	}
+	c.record(DryRunOperationPatch, obj, diff)
+	return nil
+}
+
+func (c *DryRunClient) Delete(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.DeleteOption) error {
+	c.record(DryRunOperationDelete, obj, "")
+	return nil
+}
+
+func (c *DryRunClient) DeleteAllOf(ctx context.Context, obj k8sclient.Object, opts ...k8sclient.DeleteAllOfOption) error {
+	c.record(DryRunOperationDelete, obj, "")
+	return nil
+}
+
+func (c *DryRunClient) record(operation string, obj k8sclient.Object, diff string) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, DryRunChange{
+		Operation: operation,
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Diff:      diff,
+	})
+}
+
+// Changes recorded so far, in the order they were recorded
+func (c *DryRunClient) GetChanges() []DryRunChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]DryRunChange(nil), c.changes...)
+}
+
+func isSecret(obj k8sclient.Object) bool {
+	_, ok := obj.(*corev1.Secret)
+	return ok
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/redhat-developer/observability-operator/v4/controllers/utils/testutils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestDryRunClient_CreateOrUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.SchemeBuilder.AddToScheme(scheme)
+
+	existingConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "existing",
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{"key": "old"},
+	}
+
+	type args struct {
+		obj  k8sclient.Object
+		data map[string]string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want []DryRunChange
+	}{
+		{
+			name: "records create for missing object",
+			args: args{
+				obj: &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "missing",
+						Namespace: testNamespace,
+					},
+				},
+				data: map[string]string{"key": "new"},
+			},
+			want: []DryRunChange{
+				{
+					Operation: DryRunOperationCreate,
+					Kind:      "ConfigMap",
+					Namespace: testNamespace,
+					Name:      "missing",
+				},
+			},
+		},
+		{
+			name: "records update with diff for changed object",
+			args: args{
+				obj: &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "existing",
+						Namespace: testNamespace,
+					},
+				},
+				data: map[string]string{"key": "new"},
+			},
+			want: []DryRunChange{
+				{
+					Operation: DryRunOperationUpdate,
+					Kind:      "ConfigMap",
+					Namespace: testNamespace,
+					Name:      "existing",
+					Diff:      `{"data":{"key":"new"}}`,
+				},
+			},
+		},
+		{
+			name: "records nothing for unchanged object",
+			args: args{
+				obj: &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "existing",
+						Namespace: testNamespace,
+					},
+				},
+				data: map[string]string{"key": "old"},
+			},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(existingConfigMap.DeepCopy()).Build()
+			dryRunClient := NewDryRunClient(fakeClient)
+
+			configMap := tt.args.obj.(*corev1.ConfigMap)
+			_, err := controllerutil.CreateOrUpdate(context.TODO(), dryRunClient, configMap, func() error {
+				configMap.Data = tt.args.data
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dryRunClient.GetChanges()).To(Equal(tt.want))
+
+			// the wrapped client must never be written to
+			current := &corev1.ConfigMap{}
+			err = fakeClient.Get(context.TODO(), k8sclient.ObjectKeyFromObject(existingConfigMap), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(current.Data).To(Equal(existingConfigMap.Data))
+		})
+	}
+}
+
+func TestDryRunClient_Apply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.SchemeBuilder.AddToScheme(scheme)
+
+	existingConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "existing",
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{"key": "old", "other": "kept"},
+	}
+
+	tests := []struct {
+		name string
+		obj  *corev1.ConfigMap
+		data map[string]string
+		want []DryRunChange
+	}{
+		{
+			name: "records create for missing object",
+			obj:  &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: testNamespace}},
+			data: map[string]string{"key": "new"},
+			want: []DryRunChange{
+				{
+					Operation: DryRunOperationCreate,
+					Kind:      "ConfigMap",
+					Namespace: testNamespace,
+					Name:      "missing",
+				},
+			},
+		},
+		{
+			name: "records only the applied fields of a changed object",
+			obj:  &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: testNamespace}},
+			data: map[string]string{"key": "new"},
+			want: []DryRunChange{
+				{
+					Operation: DryRunOperationPatch,
+					Kind:      "ConfigMap",
+					Namespace: testNamespace,
+					Name:      "existing",
+					Diff:      `{"data":{"key":"new"}}`,
+				},
+			},
+		},
+		{
+			name: "records nothing for unchanged object",
+			obj:  &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: testNamespace}},
+			data: map[string]string{"key": "old"},
+			want: nil,
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := testutils.NewFakeApplyClient(fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(existingConfigMap.DeepCopy()).Build())
+			dryRunClient := NewDryRunClient(fakeClient)
+
+			_, err := Apply(context.TODO(), dryRunClient, tt.obj, func() error {
+				tt.obj.Data = tt.data
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dryRunClient.GetChanges()).To(Equal(tt.want))
+
+			// the wrapped client must never be written to
+			current := &corev1.ConfigMap{}
+			err = fakeClient.Get(context.TODO(), k8sclient.ObjectKeyFromObject(existingConfigMap), current)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(current.Data).To(Equal(existingConfigMap.Data))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Granted permissions only change when the roles of the operator are edited, the results of the
+// access reviews are reused for a while to avoid a review per permission and reconcile
+const PermissionCheckTTL = 5 * time.Minute
+
+type permissionCheckResult struct {
+	allowed   bool
+	checkedAt time.Time
+}
+
+// Checks the permissions of the operator with self subject access reviews
+type PermissionChecker struct {
+	client  k8sclient.Client
+	mu      sync.Mutex
+	results map[v1.Permission]permissionCheckResult
+}
+
+func NewPermissionChecker(client k8sclient.Client) *PermissionChecker {
+	return &PermissionChecker{
+		client:  client,
+		results: map[v1.Permission]permissionCheckResult{},
+	}
+}
+
+// Returns the permissions that are not granted to the operator
+func (c *PermissionChecker) GetMissingPermissions(ctx context.Context, permissions []v1.Permission) ([]v1.Permission, error) {
+	var missing []v1.Permission
+	for _, permission := range permissions {
+		allowed, err := c.isAllowed(ctx, permission)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			missing = append(missing, permission)
+		}
+	}
+	return missing, nil
+}
+
+func (c *PermissionChecker) isAllowed(ctx context.Context, permission v1.Permission) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if result, ok := c.results[permission]; ok && time.Since(result.checkedAt) < PermissionCheckTTL {
+		return result.allowed, nil
+	}
+
+	// Subresources are part of the resource name, e.g. nodes/proxy
+	resource := strings.SplitN(permission.Resource, "/", 2)
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: permission.Namespace,
+				Verb:      permission.Verb,
+				Group:     permission.Group,
+				Resource:  resource[0],
+			},
+		},
+	}
+	if len(resource) > 1 {
+		review.Spec.ResourceAttributes.Subresource = resource[1]
+	}
+
+	err := c.client.Create(ctx, review)
+	if err != nil {
+		return false, err
+	}
+
+	c.results[permission] = permissionCheckResult{
+		allowed:   review.Status.Allowed,
+		checkedAt: time.Now(),
+	}
+	return review.Status.Allowed, nil
+}
+
+// Groups the permissions into rules with the same group and verbs
+func GetPolicyRules(permissions []v1.Permission) []rbacv1.PolicyRule {
+	verbs := map[string]map[string]bool{}
+	for _, permission := range permissions {
+		key := fmt.Sprintf("%v %v", permission.Group, permission.Resource)
+		if verbs[key] == nil {
+			verbs[key] = map[string]bool{}
+		}
+		verbs[key][permission.Verb] = true
+	}
+
+	resources := map[string][]string{}
+	for key, set := range verbs {
+		var list []string
+		for verb := range set {
+			list = append(list, verb)
+		}
+		sort.Strings(list)
+		rule := fmt.Sprintf("%v %v", strings.SplitN(key, " ", 2)[0], strings.Join(list, ","))
+		resources[rule] = append(resources[rule], strings.SplitN(key, " ", 2)[1])
+	}
+
+	// Keys start with the group, the core group sorts first
+	var keys []string
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var rules []rbacv1.PolicyRule
+	for _, key := range keys {
+		parts := strings.SplitN(key, " ", 2)
+		sort.Strings(resources[key])
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{parts[0]},
+			Resources: resources[key],
+			Verbs:     strings.Split(parts[1], ","),
+		})
+	}
+	return rules
+}
+
+// Renders a cluster role for the cluster wide permissions and a role per namespace, as a
+// starting point for administrators that grant the operator only what it needs
+func GetRoleReport(name string, permissions []v1.Permission) (string, error) {
+	byNamespace := map[string][]v1.Permission{}
+	for _, permission := range permissions {
+		byNamespace[permission.Namespace] = append(byNamespace[permission.Namespace], permission)
+	}
+
+	var namespaces []string
+	for namespace := range byNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	var documents []string
+	for _, namespace := range namespaces {
+		var role interface{}
+		if namespace == "" {
+			role = &rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Rules:      GetPolicyRules(byNamespace[namespace]),
+			}
+		} else {
+			role = &rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Rules:      GetPolicyRules(byNamespace[namespace]),
+			}
+		}
+
+		content, err := yaml.Marshal(role)
+		if err != nil {
+			return "", err
+		}
+		documents = append(documents, string(content))
+	}
+	return strings.Join(documents, "---\n"), nil
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	v1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestPermissionChecker_GetPolicyRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []v1.Permission
+		want        []rbacv1.PolicyRule
+	}{
+		{
+			name: "no permissions, no rules",
+		},
+		{
+			name: "resources with the same group and verbs share a rule",
+			permissions: []v1.Permission{
+				{Resource: "secrets", Verb: "get"},
+				{Resource: "configmaps", Verb: "get"},
+				{Resource: "secrets", Verb: "list"},
+				{Resource: "configmaps", Verb: "list"},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps", "secrets"}, Verbs: []string{"get", "list"}},
+			},
+		},
+		{
+			name: "different groups and verbs get their own rules",
+			permissions: []v1.Permission{
+				{Resource: "secrets", Verb: "get"},
+				{Resource: "configmaps", Verb: "create"},
+				{Group: "apps", Resource: "deployments", Verb: "get"},
+				{Resource: "secrets", Verb: "get"},
+			},
+			want: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"create"}},
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+			},
+		},
+	}
+
+	RegisterTestingT(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Expect(GetPolicyRules(tt.permissions)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPermissionChecker_GetRoleReport(t *testing.T) {
+	permissions := []v1.Permission{
+		{Resource: "secrets", Verb: "get", Namespace: testNamespace},
+		{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Verb: "create"},
+	}
+
+	RegisterTestingT(t)
+	report, err := GetRoleReport("observability-operator", permissions)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(report).To(Equal(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  creationTimestamp: null
+  name: observability-operator
+rules:
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - clusterroles
+  verbs:
+  - create
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  creationTimestamp: null
+  name: observability-operator
+  namespace: test-namespace
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+`))
+}
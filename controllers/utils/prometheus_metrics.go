@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Scrape a metrics endpoint and return the parsed metric families
+func FetchMetrics(httpClient *http.Client, metricsUrl string) (map[string]*dto.MetricFamily, error) {
+	resp, err := httpClient.Get(metricsUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code when fetching metrics from %v: %v", metricsUrl, resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// Sum of all counter, gauge or untyped values of a metric family, 0 if the family is missing
+func SumMetricValues(families map[string]*dto.MetricFamily, name string) float64 {
+	return SumMatchingMetricValues(families, name, nil)
+}
+
+// Sum of the values of the metrics of a family that have all the given labels
+func SumMatchingMetricValues(families map[string]*dto.MetricFamily, name string, labels map[string]string) float64 {
+	family, ok := families[name]
+	if !ok {
+		return 0
+	}
+
+	var sum float64
+	for _, metric := range family.Metric {
+		if !hasLabels(metric, labels) {
+			continue
+		}
+		switch {
+		case metric.Counter != nil:
+			sum += metric.Counter.GetValue()
+		case metric.Gauge != nil:
+			sum += metric.Gauge.GetValue()
+		case metric.Untyped != nil:
+			sum += metric.Untyped.GetValue()
+		}
+	}
+	return sum
+}
+
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	for name, value := range labels {
+		found := false
+		for _, pair := range metric.Label {
+			if pair.GetName() == name && pair.GetValue() == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Sum of all observations of a histogram or summary metric family, 0 if the family is missing
+func SumMetricObservations(families map[string]*dto.MetricFamily, name string) float64 {
+	family, ok := families[name]
+	if !ok {
+		return 0
+	}
+
+	var sum float64
+	for _, metric := range family.Metric {
+		switch {
+		case metric.Histogram != nil:
+			sum += metric.Histogram.GetSampleSum()
+		case metric.Summary != nil:
+			sum += metric.Summary.GetSampleSum()
+		}
+	}
+	return sum
+}
+
+// Subset of the response of the Prometheus TSDB status API
+type TSDBStatus struct {
+	HeadStats struct {
+		NumSeries uint64 `json:"numSeries"`
+	} `json:"headStats"`
+	SeriesCountByMetricName     []TSDBStat `json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []TSDBStat `json:"labelValueCountByLabelName"`
+	SeriesCountByLabelValuePair []TSDBStat `json:"seriesCountByLabelValuePair"`
+}
+
+type TSDBStat struct {
+	Name  string `json:"name"`
+	Value uint64 `json:"value"`
+}
+
+// Sample of an instant vector query result
+type VectorSample struct {
+	Metric map[string]string
+	Value  float64
+}
+
+// Fetch the cardinality statistics of the head block of a Prometheus server
+func FetchTSDBStatus(httpClient *http.Client, prometheusUrl string) (*TSDBStatus, error) {
+	status := &TSDBStatus{}
+	err := fetchPrometheusApi(httpClient, fmt.Sprintf("%v/api/v1/status/tsdb", prometheusUrl), status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Subset of an active target of the Prometheus targets API
+type ScrapeTarget struct {
+	ScrapePool string            `json:"scrapePool"`
+	Labels     map[string]string `json:"labels"`
+	Health     string            `json:"health"`
+	LastError  string            `json:"lastError"`
+}
+
+// Fetch the active targets of a Prometheus server
+func FetchScrapeTargets(httpClient *http.Client, prometheusUrl string) ([]ScrapeTarget, error) {
+	var result struct {
+		ActiveTargets []ScrapeTarget `json:"activeTargets"`
+	}
+	err := fetchPrometheusApi(httpClient, fmt.Sprintf("%v/api/v1/targets?state=active", prometheusUrl), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.ActiveTargets, nil
+}
+
+// Evaluate an instant query that returns a vector
+func QueryVector(httpClient *http.Client, prometheusUrl string, query string) ([]VectorSample, error) {
+	var result struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	}
+	queryUrl := fmt.Sprintf("%v/api/v1/query?query=%v", prometheusUrl, url.QueryEscape(query))
+	err := fetchPrometheusApi(httpClient, queryUrl, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected result type of query %v: %v", query, result.ResultType)
+	}
+
+	var samples []VectorSample
+	for _, r := range result.Result {
+		// Values are encoded as [<timestamp>, "<value>"]
+		if len(r.Value) != 2 {
+			return nil, fmt.Errorf("unexpected sample value in result of query %v", query)
+		}
+		value, ok := r.Value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected sample value in result of query %v", query)
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, VectorSample{
+			Metric: r.Metric,
+			Value:  parsed,
+		})
+	}
+	return samples, nil
+}
+
+// Decode the data of a successful response of the Prometheus HTTP API
+func fetchPrometheusApi(httpClient *http.Client, apiUrl string, data interface{}) error {
+	resp, err := httpClient.Get(apiUrl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+		Error  string          `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return fmt.Errorf("unexpected response from %v: %v", apiUrl, err)
+	}
+
+	if resp.StatusCode != http.StatusOK || body.Status != "success" {
+		return fmt.Errorf("request to %v failed with status code %v: %v", apiUrl, resp.StatusCode, body.Error)
+	}
+
+	return json.Unmarshal(body.Data, data)
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"os"
 
 	v13 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
@@ -14,6 +16,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -32,6 +37,115 @@ func GetClusterId(ctx context.Context, client k8sclient.Client) (string, error)
 	return string(v.Spec.ClusterID), nil
 }
 
+// Kubernetes clusters have no cluster id, the uid of the kube-system namespace is unique and stable instead
+func GetKubernetesClusterId(ctx context.Context, client k8sclient.Client) (string, error) {
+	ns := &corev1.Namespace{}
+	selector := k8sclient.ObjectKey{
+		Name: "kube-system",
+	}
+
+	err := client.Get(ctx, selector, ns)
+	if err != nil {
+		return "", err
+	}
+
+	return string(ns.UID), nil
+}
+
+// OpenShift clusters are detected by the availability of the Route API
+func GetClusterType(client k8sclient.Client) (v1.ClusterType, error) {
+	_, err := client.RESTMapper().RESTMapping(schema.GroupKind{
+		Group: routev1.GroupName,
+		Kind:  "Route",
+	})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return v1.ClusterTypeKubernetes, nil
+		}
+		return "", err
+	}
+
+	return v1.ClusterTypeOpenShift, nil
+}
+
+// Hosted control planes are detected by the external control plane topology of the infrastructure.
+// Clusters without the infrastructure resource are standalone.
+func GetClusterTopology(ctx context.Context, client k8sclient.Client) (v1.ClusterTopology, error) {
+	infrastructure := &unstructured.Unstructured{}
+	infrastructure.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   v13.GroupName,
+		Version: "v1",
+		Kind:    "Infrastructure",
+	})
+
+	err := client.Get(ctx, k8sclient.ObjectKey{Name: "cluster"}, infrastructure)
+	if err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return v1.ClusterTopologyStandalone, nil
+		}
+		return "", err
+	}
+
+	topology, _, _ := unstructured.NestedString(infrastructure.Object, "status", "controlPlaneTopology")
+	if topology == "External" {
+		return v1.ClusterTopologyHosted, nil
+	}
+	return v1.ClusterTopologyStandalone, nil
+}
+
+// Default domain of the routes of the cluster
+func GetClusterIngressDomain(ctx context.Context, client k8sclient.Client) (string, error) {
+	ingress := &v13.Ingress{}
+	selector := k8sclient.ObjectKey{
+		Name: "cluster",
+	}
+
+	err := client.Get(ctx, selector, ingress)
+	if err != nil {
+		return "", err
+	}
+	return ingress.Spec.Domain, nil
+}
+
+// Namespace of the operator pod, or the watch namespace when running locally
+func GetOperatorNamespace() (string, error) {
+	ns, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err == nil {
+		return string(ns), nil
+	}
+	if namespace := os.Getenv("WATCH_NAMESPACE"); namespace != "" {
+		return namespace, nil
+	}
+	return "", fmt.Errorf("cannot detect operator namespace")
+}
+
+// Image of the operator container, OPERATOR_IMAGE takes precedence over the image of the own pod
+func GetOperatorImage(ctx context.Context, client k8sclient.Client) (string, error) {
+	if image := os.Getenv("OPERATOR_IMAGE"); image != "" {
+		return image, nil
+	}
+
+	namespace, err := GetOperatorNamespace()
+	if err != nil {
+		return "", err
+	}
+	name, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	pod := &corev1.Pod{}
+	err = client.Get(ctx, k8sclient.ObjectKey{Namespace: namespace, Name: name}, pod)
+	if err != nil {
+		return "", fmt.Errorf("cannot detect operator image: %v", err)
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "manager" {
+			return container.Image, nil
+		}
+	}
+	return "", fmt.Errorf("cannot detect operator image, pod %v has no manager container", name)
+}
+
 // returns cluster Openshift version
 func GetClusterOSVersion(ctx context.Context, client k8sclient.Client) (string, error) {
 	v := &v13.ClusterVersion{}
@@ -46,6 +160,36 @@ func GetClusterOSVersion(ctx context.Context, client k8sclient.Client) (string,
 	return v.Status.Desired.Version, nil
 }
 
+// Returns the cluster-wide proxy configuration, nil if there is none
+func GetClusterProxy(ctx context.Context, client k8sclient.Client) (*v13.Proxy, error) {
+	proxy := &v13.Proxy{}
+	selector := k8sclient.ObjectKey{
+		Name: "cluster",
+	}
+
+	err := client.Get(ctx, selector, proxy)
+	if err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return proxy, nil
+}
+
+// CA of a serving certificate issued by cert-manager, empty until the certificate is issued
+func GetServingCertificateCA(ctx context.Context, client k8sclient.Client, namespace string, name string) (string, error) {
+	secret := &corev1.Secret{}
+	err := client.Get(ctx, k8sclient.ObjectKey{Namespace: namespace, Name: name}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(secret.Data[model.ServingCertificateCAKey]), nil
+}
+
 // We need to figure out if a sync set needs to be created
 // When installing via subscription this is not required because OLM will create one
 // When installing by deployment we need to create one ourselves
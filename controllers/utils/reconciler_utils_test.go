@@ -10,6 +10,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -303,3 +304,45 @@ func TestReconcilerUtils_WaitForPrometheusToBeRemoved(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilerUtils_GetClusterTopology(t *testing.T) {
+	getInfrastructure := func(topology string) *unstructured.Unstructured {
+		infrastructure := &unstructured.Unstructured{}
+		infrastructure.SetAPIVersion("config.openshift.io/v1")
+		infrastructure.SetKind("Infrastructure")
+		infrastructure.SetName("cluster")
+		_ = unstructured.SetNestedField(infrastructure.Object, topology, "status", "controlPlaneTopology")
+		return infrastructure
+	}
+
+	tests := []struct {
+		name       string
+		fakeClient k8sclient.Client
+		want       v1.ClusterTopology
+	}{
+		{
+			name:       "hosted with an external control plane",
+			fakeClient: fakeclient.NewClientBuilder().WithObjects(getInfrastructure("External")).Build(),
+			want:       v1.ClusterTopologyHosted,
+		},
+		{
+			name:       "standalone with a highly available control plane",
+			fakeClient: fakeclient.NewClientBuilder().WithObjects(getInfrastructure("HighlyAvailable")).Build(),
+			want:       v1.ClusterTopologyStandalone,
+		},
+		{
+			name:       "standalone without infrastructure",
+			fakeClient: fakeclient.NewClientBuilder().Build(),
+			want:       v1.ClusterTopologyStandalone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			topology, err := GetClusterTopology(context.TODO(), tt.fakeClient)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(topology).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package testutils
+
+import (
+	"context"
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FakeApplyClient adds server-side apply to the fake client of controller-runtime for tests. Applies
+// are merged into the existing object as JSON merge patches, field ownership is not tracked.
+type FakeApplyClient struct {
+	k8sclient.Client
+}
+
+func NewFakeApplyClient(client k8sclient.Client) *FakeApplyClient {
+	return &FakeApplyClient{
+		Client: client,
+	}
+}
+
+func (c *FakeApplyClient) Patch(ctx context.Context, obj k8sclient.Object, patch k8sclient.Patch, opts ...k8sclient.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	patchOptions := &k8sclient.PatchOptions{}
+	patchOptions.ApplyOptions(opts)
+	dryRun := len(patchOptions.DryRun) > 0 && patchOptions.DryRun[0] == metav1.DryRunAll
+
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+
+	existing := obj.DeepCopyObject().(k8sclient.Object)
+	err = c.Client.Get(ctx, k8sclient.ObjectKeyFromObject(obj), existing)
+	if errors.IsNotFound(err) {
+		if dryRun {
+			return nil
+		}
+		return c.Client.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+
+	current, err := json.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	merged, err := jsonpatch.MergePatch(current, data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(merged, obj)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+	return c.Client.Update(ctx, obj)
+}
@@ -19,7 +19,10 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	grafana "github.com/grafana-operator/grafana-operator/v4/api/integreatly/v1alpha1"
@@ -30,15 +33,26 @@ import (
 	coreosv1 "github.com/operator-framework/api/pkg/operators/v1"
 	coreosv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	apiv1 "github.com/redhat-developer/observability-operator/v4/api/v1"
+	apiv1beta2 "github.com/redhat-developer/observability-operator/v4/api/v1beta2"
 	"github.com/redhat-developer/observability-operator/v4/controllers"
+	"github.com/redhat-developer/observability-operator/v4/controllers/diagnostics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/features"
+	"github.com/redhat-developer/observability-operator/v4/controllers/forwarder"
+	"github.com/redhat-developer/observability-operator/v4/controllers/logging"
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"github.com/redhat-developer/observability-operator/v4/controllers/querylog"
+	"github.com/redhat-developer/observability-operator/v4/controllers/tracing"
 	"github.com/redhat-developer/observability-operator/v4/runners"
 	// +kubebuilder:scaffold:imports
 )
@@ -53,6 +67,8 @@ func init() {
 
 	utilruntime.Must(apiv1.AddToScheme(scheme))
 
+	utilruntime.Must(apiv1beta2.AddToScheme(scheme))
+
 	utilruntime.Must(projectv1.AddToScheme(scheme))
 
 	utilruntime.Must(routev1.AddToScheme(scheme))
@@ -73,34 +89,133 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == diagnostics.MustGatherCommand {
+		if err := diagnostics.RunMustGather(scheme, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == forwarder.Command {
+		if err := forwarder.Run(zap.New(zap.UseDevMode(true)).WithName("alert-forwarder"), os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == querylog.Command {
+		if err := querylog.Run(zap.New(zap.UseDevMode(true)).WithName("query-log-exporter"), os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
+	var probeAddr string
 	var enableLeaderElection bool
+	var leaderElectionNamespace string
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
+	var watchNamespaces string
 	var disableWebhooks bool
+	var operatorConfig string
+	var tracingOptions tracing.Options
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-addr", ":8081", "The address the liveness and readiness probes bind to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "",
+		"Namespace of the leader election lease, defaults to the namespace of the operator.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"Time standby replicas wait before taking over the lease of a leader that stopped renewing it.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"Time the leader retries renewing the lease before giving up leadership.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"Time between attempts to acquire or renew the lease.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", os.Getenv("WATCH_NAMESPACES"),
+		"Comma separated namespaces to watch instead of the whole cluster, for running with namespace scoped permissions. "+
+			"Must include the namespaces of the operator, the Observability CR and Prometheus.")
 	flag.BoolVar(&disableWebhooks, "disable-webhooks", false, "disable webhooks for running on local environment")
+	flag.StringVar(&operatorConfig, "operator-config", controllers.DefaultOperatorConfigMapName,
+		"Name of the config map in the operator namespace with the defaults of all Observability CRs.")
+	flag.StringVar(&tracingOptions.Endpoint, "tracing-endpoint", "",
+		"Host and port of the OTLP/HTTP receiver the traces are exported to with the tracing feature. "+
+			"Defaults to OTEL_EXPORTER_OTLP_ENDPOINT.")
+	flag.BoolVar(&tracingOptions.Insecure, "tracing-insecure", false, "Export the traces without TLS.")
+	flag.Float64Var(&tracingOptions.SampleRatio, "tracing-sample-ratio", 1,
+		"Fraction of the reconciles that are traced.")
+	if value := os.Getenv("FEATURE_GATES"); value != "" {
+		if err := features.DefaultGates().Set(value); err != nil {
+			setupLog.Error(err, "invalid FEATURE_GATES")
+			os.Exit(1)
+		}
+	}
+	if value := os.Getenv("LOG_LEVELS"); value != "" {
+		if err := logging.DefaultLevels().Set(value); err != nil {
+			fmt.Fprintln(os.Stderr, "invalid LOG_LEVELS:", err)
+			os.Exit(1)
+		}
+	}
+	flag.Var(logging.DefaultLevels(), "log-levels",
+		"Comma separated verbosity of the subsystems from 0 to 5, e.g. default=0,configuration=2. Defaults to LOG_LEVELS.")
+	flag.Var(features.DefaultGates(), "feature-gates",
+		"Comma separated features to enable or disable, e.g. thanos=true,agentMode=false. Defaults to FEATURE_GATES.")
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	// Levels are filtered by subsystem, the base logger lets all of them through
+	ctrl.SetLogger(logging.WithLevels(zap.New(zap.UseDevMode(true), zap.Level(zapcore.Level(-logging.MaxLevel))), logging.DefaultSubsystem))
+	setupLog.Info("feature gates", "enabled", features.DefaultGates().All())
+	metrics.SetFeatureMetrics(features.DefaultGates().All())
+
+	if features.Enabled(features.Tracing) {
+		shutdown, err := tracing.Setup(context.Background(), tracingOptions)
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				setupLog.Error(err, "error flushing traces")
+			}
+		}()
+	}
+
+	options := ctrl.Options{
+		Scheme:                  scheme,
+		MetricsBindAddress:      metricsAddr,
+		HealthProbeBindAddress:  probeAddr,
+		Port:                    9443,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        "04220e3f.redhat.com",
+		LeaderElectionNamespace: leaderElectionNamespace,
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
+		// Hand over the lease on shutdown instead of letting it expire, so that upgrades
+		// and node drains don't pause reconciliation for the lease duration
+		LeaderElectionReleaseOnCancel: true,
+	}
+
+	// Namespace scoped permissions don't allow to list and watch across the cluster
+	if watchNamespaces != "" {
+		options.NewCache = cache.MultiNamespacedCacheBuilder(strings.Split(watchNamespaces, ","))
+	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		Port:               9443,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   "04220e3f.redhat.com",
-	})
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
 	observabilityReconciler := &controllers.ObservabilityReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Observability"),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("Observability"),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("observability-operator"),
+		ConfigMapName: operatorConfig,
 	}
 
 	if err = observabilityReconciler.SetupWithManager(mgr); err != nil {
@@ -108,6 +223,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.SyntheticCheckReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("SyntheticCheck"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SyntheticCheck")
+		os.Exit(1)
+	}
+
 	if !disableWebhooks {
 		if err = (&apiv1.Observability{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Observability")
@@ -115,6 +238,27 @@ func main() {
 		}
 	}
 	// +kubebuilder:scaffold:builder
+
+	// Standby replicas are ready as well, they only wait for the lease
+	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err = mgr.AddReadyzCheck("cache", runners.NewCacheSyncChecker(mgr.GetCache(), time.Second)); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	if !disableWebhooks {
+		if err = mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up ready check")
+			os.Exit(1)
+		}
+	}
+	if err = mgr.Add(runners.NewLeaderElectionObserver(mgr.Elected())); err != nil {
+		setupLog.Error(err, "unable to set up leader election metric")
+		os.Exit(1)
+	}
+
 	mgr.Add(runners.NewOperandInitializer(func() error {
 		if err = observabilityReconciler.InitializeOperand(mgr); err != nil {
 			setupLog.Error(err, "unable to create or update operand", "controller", "Observability")
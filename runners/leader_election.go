@@ -0,0 +1,50 @@
+package runners
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/redhat-developer/observability-operator/v4/controllers/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Reports whether this replica holds the leader lease. Standby replicas run it as well.
+type LeaderElectionObserver struct {
+	elected <-chan struct{}
+}
+
+func NewLeaderElectionObserver(elected <-chan struct{}) manager.Runnable {
+	return &LeaderElectionObserver{
+		elected: elected,
+	}
+}
+
+func (r *LeaderElectionObserver) Start(ctx context.Context) error {
+	metrics.SetLeaderMetric(false)
+	select {
+	case <-r.elected:
+		metrics.SetLeaderMetric(true)
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func (r *LeaderElectionObserver) NeedLeaderElection() bool {
+	return false
+}
+
+// A replica is ready once its caches are synced, so that a standby can take over without delay
+func NewCacheSyncChecker(c cache.Cache, timeout time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("informer caches not synced")
+		}
+		return nil
+	}
+}